@@ -0,0 +1,140 @@
+package adstxt
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+)
+
+// MetadataAdsCert Response metadata key of ads.cert call sign enrichment (see AdsCertEnricher)
+const MetadataAdsCert = "adscert"
+
+// ads.cert DNS record names and versions
+const (
+	adsCertPrefix         = "_adscert."           // policy records of domain
+	adsCertDeliveryPrefix = "_delivery._adscert." // public key records of call sign domain
+	adsCertPolicyVersion  = "adpf"                // version of policy record, delegating to call sign domain
+)
+
+// AdsCertRecord ads.cert DNS TXT record: space separated "key=value" parameters, for example policy record
+// "v=adpf a=adscorp.com" or public key record "v=adcrtd k=x25519 h=sha256 p=<base64 public key>"
+type AdsCertRecord struct {
+	Name   string            `json:"name"`   // Name DNS name of the record
+	Raw    string            `json:"raw"`    // Raw TXT record value
+	Params map[string]string `json:"params"` // Params record parameters by key
+}
+
+// Version return the record version ("v" parameter)
+func (r *AdsCertRecord) Version() string {
+	return r.Params["v"]
+}
+
+// PublicKey return the base64 encoded public key of public key record ("p" parameter)
+func (r *AdsCertRecord) PublicKey() string {
+	return r.Params["p"]
+}
+
+// AdsCert ads.cert call sign of Ads.txt domain, published via DNS: policy records of the domain, and the public key
+// records of its call sign domain, for consumers that verify cryptographic attestations of the supply chain
+type AdsCert struct {
+	Domain      string           `json:"domain"`                // Domain root domain of the Ads.txt request
+	Policy      []*AdsCertRecord `json:"policy"`                // Policy records of the domain (_adscert.<domain>)
+	CallSign    string           `json:"callSign"`              // CallSign domain of the public keys: the domain delegated by policy record, or the domain itself
+	Keys        []*AdsCertRecord `json:"keys"`                  // Keys public key records of the call sign domain (_delivery._adscert.<call sign>)
+	Verified    bool             `json:"verified"`              // Verified the call sign was verified by the enricher verifier (see AdsCertEnricher)
+	VerifyError string           `json:"verifyError,omitempty"` // VerifyError error of the enricher verifier
+}
+
+// AdsCertVerifier verify cryptographic attestations of Ads.txt response with the ads.cert call sign of its domain (for
+// example signature of the Ads.txt file or of the supply chain records). Verification error is recorded on the call
+// sign (see AdsCert.VerifyError)
+type AdsCertVerifier func(ctx context.Context, res *Response, cert *AdsCert) error
+
+// AdsCertEnricher Enricher that adds the ads.cert call sign of the Ads.txt request domain to the response metadata, as
+// AdsCert under the MetadataAdsCert key (see Response.AdsCert). TXT records are looked up with LookupTXT (Resolver
+// LookupTXT if nil, system resolver if Resolver is nil as well), and the call sign is verified with Verify (not
+// verified if nil). Domain without ads.cert records has call sign without records
+type AdsCertEnricher struct {
+	Resolver  *net.Resolver                                            // Resolver DNS resolver of the TXT records
+	LookupTXT func(ctx context.Context, name string) ([]string, error) // LookupTXT look up TXT records of DNS name
+	Verify    AdsCertVerifier                                          // Verify verify attestations with the call sign
+}
+
+// Enrich is the Enricher interface implementation for AdsCertEnricher
+func (e *AdsCertEnricher) Enrich(ctx context.Context, res *Response) error {
+	domain := asciiHost(strings.ToLower(res.Domain))
+	cert := &AdsCert{Domain: res.Domain, Policy: []*AdsCertRecord{}, CallSign: domain, Keys: []*AdsCertRecord{}}
+
+	var err error
+	if cert.Policy, err = e.lookup(ctx, adsCertPrefix+domain); err != nil {
+		return err
+	}
+	// policy record delegates the domain call sign to another domain
+	for _, p := range cert.Policy {
+		if p.Version() == adsCertPolicyVersion && len(p.Params["a"]) > 0 {
+			cert.CallSign = strings.ToLower(p.Params["a"])
+			break
+		}
+	}
+	if cert.Keys, err = e.lookup(ctx, adsCertDeliveryPrefix+cert.CallSign); err != nil {
+		return err
+	}
+
+	res.SetMetadata(MetadataAdsCert, cert)
+	if e.Verify == nil {
+		return nil
+	}
+	if err := e.Verify(ctx, res, cert); err != nil {
+		cert.VerifyError = err.Error()
+		return err
+	}
+	cert.Verified = true
+	return nil
+}
+
+// lookup return the ads.cert records of DNS name: name that does not exist has no records
+func (e *AdsCertEnricher) lookup(ctx context.Context, name string) ([]*AdsCertRecord, error) {
+	lookup := e.LookupTXT
+	if lookup == nil {
+		r := e.Resolver
+		if r == nil {
+			r = net.DefaultResolver
+		}
+		lookup = r.LookupTXT
+	}
+
+	txt, err := lookup(ctx, name)
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return []*AdsCertRecord{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*AdsCertRecord, 0, len(txt))
+	for _, t := range txt {
+		records = append(records, parseAdsCertRecord(name, t))
+	}
+	return records, nil
+}
+
+// parseAdsCertRecord parse space separated "key=value" parameters of ads.cert TXT record. Keys are lower case, and
+// parameters without "=" are ignored
+func parseAdsCertRecord(name, txt string) *AdsCertRecord {
+	r := &AdsCertRecord{Name: name, Raw: txt, Params: map[string]string{}}
+	for _, f := range strings.Fields(txt) {
+		if k, v, ok := strings.Cut(f, "="); ok && len(k) > 0 {
+			r.Params[strings.ToLower(k)] = v
+		}
+	}
+	return r
+}
+
+// AdsCert return the ads.cert call sign of the response domain added by AdsCertEnricher, or nil if the response was
+// not enriched with it
+func (r *Response) AdsCert() *AdsCert {
+	cert, _ := r.Metadata[MetadataAdsCert].(*AdsCert)
+	return cert
+}
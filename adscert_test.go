@@ -0,0 +1,73 @@
+package adstxt
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+)
+
+// TestAdsCertEnricher test ads.cert call sign records of Ads.txt domain are looked up and verified
+func TestAdsCertEnricher(t *testing.T) {
+	txt := map[string][]string{
+		"_adscert.example.com":             {"v=adpf a=AdsCorp.com"},
+		"_delivery._adscert.adscorp.com":   {"v=adcrtd k=x25519 h=sha256 p=bBvfZUTPDGIFiOq-WivBoOEYWM5mA1kaEfpDaoYtfHg"},
+		"_delivery._adscert.publisher.com": {"v=adcrtd k=x25519 h=sha256 p=key1", "v=adcrtd k=x25519 h=sha256 p=key2"},
+		"_adscert.failing.com":             nil,
+	}
+	lookup := func(ctx context.Context, name string) ([]string, error) {
+		records, ok := txt[name]
+		if !ok {
+			return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+		}
+		if records == nil {
+			return nil, &net.DNSError{Err: "server misbehaving", Name: name, IsTemporary: true}
+		}
+		return records, nil
+	}
+
+	verified := []string{}
+	e := &AdsCertEnricher{LookupTXT: lookup, Verify: func(ctx context.Context, res *Response, cert *AdsCert) error {
+		if len(cert.Keys) == 0 {
+			return errors.New("no public key")
+		}
+		verified = append(verified, cert.CallSign)
+		return nil
+	}}
+
+	// policy record delegates the call sign to another domain
+	res := &Response{Request: &Request{URL: "https://example.com/ads.txt", Domain: "example.com"}}
+	if err := e.Enrich(context.Background(), res); err != nil {
+		t.Fatal(err)
+	}
+	cert := res.AdsCert()
+	if cert == nil || cert.CallSign != "adscorp.com" || len(cert.Policy) != 1 || len(cert.Keys) != 1 || !cert.Verified {
+		t.Fatalf("Expected verified call sign of [adscorp.com] and not %+v", cert)
+	}
+	if k := cert.Keys[0]; k.Version() != "adcrtd" || k.PublicKey() != "bBvfZUTPDGIFiOq-WivBoOEYWM5mA1kaEfpDaoYtfHg" ||
+		k.Params["k"] != "x25519" || k.Name != "_delivery._adscert.adscorp.com" {
+		t.Errorf("Expected parsed public key record and not %+v", k)
+	}
+
+	// domain without policy record is its own call sign
+	res = &Response{Request: &Request{URL: "https://publisher.com/ads.txt", Domain: "Publisher.com"}}
+	if err := e.Enrich(context.Background(), res); err != nil || res.AdsCert().CallSign != "publisher.com" || len(res.AdsCert().Keys) != 2 {
+		t.Errorf("Expected call sign of the domain itself and not %+v [%v]", res.AdsCert(), err)
+	}
+
+	// verification error is recorded on the call sign
+	res = &Response{Request: &Request{URL: "https://unsigned.com/ads.txt", Domain: "unsigned.com"}}
+	if err := e.Enrich(context.Background(), res); err == nil || res.AdsCert().Verified || res.AdsCert().VerifyError != "no public key" {
+		t.Errorf("Expected call sign without public key to fail verification and not %+v", res.AdsCert())
+	}
+
+	// DNS failure fails the enrichment
+	res = &Response{Request: &Request{URL: "https://failing.com/ads.txt", Domain: "failing.com"}}
+	if err := e.Enrich(context.Background(), res); err == nil || res.AdsCert() != nil {
+		t.Errorf("Expected DNS failure to fail the enrichment")
+	}
+
+	if len(verified) != 2 {
+		t.Errorf("Expected [2] verified call signs and not %v", verified)
+	}
+}
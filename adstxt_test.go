@@ -1,11 +1,15 @@
 package adstxt
 
 import (
+	"context"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 // TestGetMultiple testing fetch and parse multile Ads.txt files from remote hosts
@@ -74,6 +78,55 @@ func TestGet(t *testing.T) {
 	}
 }
 
+// TestGetWithContext test canceling Ads.txt request to remote host that does not respond
+func TestGetWithContext(t *testing.T) {
+	done := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer ts.Close()
+	defer close(done)
+
+	// request mock
+	req := &Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := GetWithContext(ctx, req)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected request to fail with context deadline exceeded and not [%v]", err)
+	}
+}
+
+// TestGetMultipleWithContext test requests are not sent once context is canceled
+func TestGetMultipleWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	requests := []*Request{
+		&Request{URL: "http://127.0.0.1:1/ads.txt", Domain: "127.0.0.1"},
+		&Request{URL: "http://127.0.0.1:1/ads.txt", Domain: "127.0.0.1"},
+	}
+
+	var mu sync.Mutex
+	count := 0
+	h := func(req *Request, res *Response, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		count++
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected request to fail with context canceled and not [%v]", err)
+		}
+	}
+
+	GetMultipleWithContext(ctx, requests, HandlerFunc(h))
+
+	if count != len(requests) {
+		t.Errorf("Expected handler to be called [%d] times and not [%d]", len(requests), count)
+	}
+}
+
 // TestParseBody test paring []byte array into []Line array
 func TestParseBody(t *testing.T) {
 	body := []string{
@@ -139,6 +192,34 @@ func TestParseRecordsSuccess(t *testing.T) {
 	}
 }
 
+// TestParseOwnerAndManagerDomains test parsing Ads.txt 1.1 OWNERDOMAIN and MANAGERDOMAIN variables into records
+func TestParseOwnerAndManagerDomains(t *testing.T) {
+	b := []byte("OWNERDOMAIN=example.com\nOWNERDOMAIN=other.com\nMANAGERDOMAIN=manager.com\nMANAGERDOMAIN=manager.com,US\nMANAGERDOMAIN=other.com,us\nMANAGERDOMAIN=other.com")
+	res, err := ParseBody(b)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if res.OwnerDomain != "example.com" {
+		t.Errorf("Expected OWNERDOMAIN to be [example.com] and not [%s]", res.OwnerDomain)
+	}
+
+	if len(res.ManagerDomains) != 2 {
+		t.Errorf("Expected [2] MANAGERDOMAIN variables and not [%d]", len(res.ManagerDomains))
+	}
+
+	// duplicate OWNERDOMAIN, duplicate country MANAGERDOMAIN and duplicate global MANAGERDOMAIN
+	if len(res.Warnings) != 3 {
+		t.Errorf("Expected [3] warnings and not [%d]", len(res.Warnings))
+	}
+
+	for _, w := range res.Warnings {
+		if w.Index != 2 && w.Index != 5 && w.Index != 6 {
+			t.Errorf("Unexpected warning for line [%d] [%s]", w.Index, w.Message)
+		}
+	}
+}
+
 // TestParseRecordsFailure test parsing to invalid Ads.txt file
 func TestParseRecordsFailure(t *testing.T) {
 	b1 := []byte("greenadexchange.com,XF7342,\ngreenadexchange.com, XF7342, DIRECT, 5jyxf8k54\n#greenadexchange.com,XF7342,DIRECT\nsubdomain=dev.example.com")
@@ -172,3 +253,75 @@ func TestParseRecordsFailure(t *testing.T) {
 	}
 
 }
+
+// benchmarkBody return Ads.txt file body of n lines: data records of known ad systems, variables and comments
+func benchmarkBody(n int) []byte {
+	lines := []string{
+		"google.com, pub-1234567890123456, DIRECT, f08c47fec0942fa0 # Google",
+		"openx.com, 540191398, RESELLER, 6a698e2ec38604c6",
+		"greenadexchange.com, XF7342, DIRECT",
+		"# comment line",
+		"contact=adops@example.com",
+		"subdomain=dev.example.com",
+		"",
+	}
+
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		b.WriteString(lines[i%len(lines)])
+		b.WriteString("\n")
+	}
+	return []byte(b.String())
+}
+
+// BenchmarkParseBody benchmark parsing 50k lines Ads.txt file
+func BenchmarkParseBody(b *testing.B) {
+	body := benchmarkBody(50000)
+	b.SetBytes(int64(len(body)))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseBody(body); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkParseDataRecord benchmark parsing single data record
+func BenchmarkParseDataRecord(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		parseDataRecord("google.com, pub-1234567890123456, DIRECT, f08c47fec0942fa0")
+	}
+}
+
+// FuzzParseBody test parsing arbitrary Ads.txt body never panics, and each parsed record points to its source line
+func FuzzParseBody(f *testing.F) {
+	f.Add([]byte("greenadexchange.com,XF7342,DIRECT,a1b2c3;ext # comment\nsubdomain=test.com\r\nCONTACT=ads@example.com"))
+	f.Add([]byte("\xef\xbb\xbfgoogle.com, pub-1234, RESELLER\n\x00\x00\x00"))
+	f.Add([]byte("\xff\xfeg\x00o\x00o\x00g\x00l\x00e\x00"))
+	f.Add([]byte("MANAGERDOMAIN=example.com,US\nMANAGERDOMAIN=example.com,US\n=\n,,\n"))
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		res, err := ParseBody(b)
+		if err != nil {
+			var lineErr *ErrLineTooLong
+			if !errors.As(err, &lineErr) {
+				t.Fatalf("Expected ErrLineTooLong parse error and not [%v]", err)
+			}
+			return
+		}
+
+		for _, dr := range res.DataRecords {
+			if dr.Line < 1 || dr.Line > len(res.Body) || res.Body[dr.Line-1] != dr.Text {
+				t.Fatalf("Expected DataRecord to point to its source line and not [%d]", dr.Line)
+			}
+		}
+		for _, w := range res.Warnings {
+			if w.Index > len(res.Body) {
+				t.Fatalf("Expected warning line index in Ads.txt body and not [%d]", w.Index)
+			}
+		}
+	})
+}
@@ -0,0 +1,139 @@
+// Package adstxttest provide mock HTTP transport, mock Fetcher, manual clock and canned Ads.txt responses for testing
+// code that uses the Ads.txt crawler (for example crawl result handlers) without network access:
+//
+//	tr := adstxttest.NewTransport().
+//		Handle("http://example.com/ads.txt", adstxttest.ValidFile()).
+//		Handle("http://missing.com/ads.txt", adstxttest.NotFound())
+//	c := adstxttest.NewCrawler(tr)
+//	c.GetMultiple(requests, myHandler)
+package adstxttest
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/tzafrirben/go-adstxt-crawler/adstxt"
+)
+
+// ValidBody content of valid Ads.txt file (see ValidFile): 2 data records and contact variable
+const ValidBody = "# Ads.txt file for example.com\n" +
+	"google.com, pub-1234567890, DIRECT, f08c47fec0942fa0\n" +
+	"openx.com, 537120563, RESELLER\n" +
+	"contact=adops@example.com\n"
+
+// hugeFileLine data record line repeated in huge Ads.txt file (see HugeFile)
+const hugeFileLine = "google.com, pub-1234567890, RESELLER, f08c47fec0942fa0\n"
+
+// Fixture canned HTTP response served by Transport
+type Fixture struct {
+	StatusCode int         // StatusCode HTTP status code of the response
+	Header     http.Header // Header HTTP headers of the response
+	Body       string      // Body of the response
+}
+
+// ValidFile return fixture of valid Ads.txt file (see ValidBody)
+func ValidFile() Fixture {
+	return File(ValidBody)
+}
+
+// File return fixture of Ads.txt file with body, served as text/plain
+func File(body string) Fixture {
+	return Fixture{StatusCode: http.StatusOK, Header: http.Header{"Content-Type": []string{"text/plain; charset=utf-8"}}, Body: body}
+}
+
+// Redirect return fixture of HTTP 301 redirect to location
+func Redirect(location string) Fixture {
+	return Fixture{StatusCode: http.StatusMovedPermanently, Header: http.Header{"Location": []string{location}}}
+}
+
+// NotFound return fixture of HTTP 404 response: Ads.txt file does not exist
+func NotFound() Fixture {
+	return Fixture{StatusCode: http.StatusNotFound, Header: http.Header{"Content-Type": []string{"text/html"}}, Body: "<html><body>Not Found</body></html>"}
+}
+
+// SoftNotFound return fixture of HTML error page served with HTTP 200 status (soft 404)
+func SoftNotFound() Fixture {
+	return Fixture{StatusCode: http.StatusOK, Header: http.Header{"Content-Type": []string{"text/plain"}},
+		Body: "<!DOCTYPE html>\n<html><head><title>Page not found</title></head><body>Not Found</body></html>"}
+}
+
+// HugeFile return fixture of valid Ads.txt file of at least size bytes, for testing body size limits
+func HugeFile(size int) Fixture {
+	return File(strings.Repeat(hugeFileLine, size/len(hugeFileLine)+1))
+}
+
+// Transport mock HTTP transport that serves fixtures by request URL, and records the requested URLs. URLs without
+// fixture are served with NotFound fixture. Transport is safe to use from multiple goroutines
+type Transport struct {
+	fixtures map[string]Fixture
+	requests []string
+	mu       sync.Mutex
+}
+
+// NewTransport create new Transport with no fixtures
+func NewTransport() *Transport {
+	return &Transport{fixtures: map[string]Fixture{}, requests: []string{}}
+}
+
+// Handle serve fixture f for requests of url, and return the transport so calls can be chained
+func (t *Transport) Handle(url string, f Fixture) *Transport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.fixtures[url] = f
+	return t
+}
+
+// RedirectChain serve chain of redirects: each url is redirected to the next url, and the last url is served with
+// final fixture
+func (t *Transport) RedirectChain(final Fixture, urls ...string) *Transport {
+	for i, url := range urls {
+		if i == len(urls)-1 {
+			t.Handle(url, final)
+			break
+		}
+		t.Handle(url, Redirect(urls[i+1]))
+	}
+	return t
+}
+
+// RoundTrip is the http.RoundTripper interface implementation for Transport
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	url := req.URL.String()
+	t.requests = append(t.requests, url)
+	f, ok := t.fixtures[url]
+	t.mu.Unlock()
+
+	if !ok {
+		f = NotFound()
+	}
+	return &http.Response{
+		StatusCode: f.StatusCode,
+		Status:     fmt.Sprintf("%d %s", f.StatusCode, http.StatusText(f.StatusCode)),
+		Header:     f.Header.Clone(),
+		Body:       io.NopCloser(strings.NewReader(f.Body)),
+		Request:    req,
+	}, nil
+}
+
+// Requests return the URLs requested so far, in order of the requests
+func (t *Transport) Requests() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]string{}, t.requests...)
+}
+
+// Client return HTTP client that uses the transport
+func (t *Transport) Client() *http.Client {
+	return &http.Client{Transport: t}
+}
+
+// NewCrawler create new crawler that sends all HTTP requests to transport t, with additional crawler options
+func NewCrawler(t *Transport, opts ...adstxt.Option) *adstxt.Crawler {
+	return adstxt.NewCrawler(append([]adstxt.Option{adstxt.WithHTTPClient(t.Client())}, opts...)...)
+}
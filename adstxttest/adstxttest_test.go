@@ -0,0 +1,161 @@
+package adstxttest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tzafrirben/go-adstxt-crawler/adstxt"
+)
+
+// TestTransportValidFile test crawling valid Ads.txt file fixture
+func TestTransportValidFile(t *testing.T) {
+	tr := NewTransport().Handle("http://example.com/ads.txt", ValidFile())
+	c := NewCrawler(tr)
+
+	req, _ := adstxt.NewRequest("example.com")
+	res, err := c.Get(req)
+	if err != nil {
+		t.Fatalf("Expected valid Ads.txt file and not [%s]", err)
+	}
+	AssertRecordsEqual(t, Parse(t, ValidBody), res.Records)
+	if len(res.DataRecords) != 2 || len(res.Variables) != 1 {
+		t.Errorf("Expected [2] data records and [1] variable and not [%d] [%d]", len(res.DataRecords), len(res.Variables))
+	}
+	if r := tr.Requests(); len(r) != 1 || r[0] != "http://example.com/ads.txt" {
+		t.Errorf("Expected single request of [http://example.com/ads.txt] and not %v", r)
+	}
+}
+
+// TestTransportRedirectChain test crawling Ads.txt file through redirect chain fixture
+func TestTransportRedirectChain(t *testing.T) {
+	tr := NewTransport().RedirectChain(ValidFile(),
+		"http://example.com/ads.txt",
+		"https://example.com/ads.txt",
+		"https://www.example.com/ads.txt")
+	c := NewCrawler(tr)
+
+	req, _ := adstxt.NewRequest("example.com")
+	res, err := c.Get(req)
+	if err != nil {
+		t.Fatalf("Expected valid Ads.txt file and not [%s]", err)
+	}
+	if len(res.Redirects) != 2 {
+		t.Errorf("Expected [2] redirects and not [%d]", len(res.Redirects))
+	}
+	if len(tr.Requests()) != 3 {
+		t.Errorf("Expected [3] requests and not %v", tr.Requests())
+	}
+}
+
+// TestTransportNotFound test crawling missing Ads.txt file: unknown URLs and NotFound fixture
+func TestTransportNotFound(t *testing.T) {
+	tr := NewTransport().Handle("http://missing.com/ads.txt", NotFound())
+	c := NewCrawler(tr)
+
+	for _, domain := range []string{"missing.com", "unknown.com"} {
+		req, _ := adstxt.NewRequest(domain)
+		if _, err := c.Get(req); !errors.Is(err, adstxt.ErrNotFound) {
+			t.Errorf("Expected [%s] to fail with ErrNotFound and not [%v]", domain, err)
+		}
+	}
+}
+
+// TestTransportSoftNotFound test crawling HTML error page served with success status
+func TestTransportSoftNotFound(t *testing.T) {
+	c := NewCrawler(NewTransport().Handle("http://example.com/ads.txt", SoftNotFound()))
+
+	req, _ := adstxt.NewRequest("example.com")
+	var htmlErr *adstxt.ErrHTMLPage
+	if _, err := c.Get(req); !errors.As(err, &htmlErr) {
+		t.Errorf("Expected ErrHTMLPage error and not [%v]", err)
+	}
+}
+
+// TestTransportHugeFile test crawling huge Ads.txt file fixture with body size limit
+func TestTransportHugeFile(t *testing.T) {
+	f := HugeFile(1 << 20)
+	if len(f.Body) < 1<<20 {
+		t.Fatalf("Expected huge file of at least [%d] bytes and not [%d]", 1<<20, len(f.Body))
+	}
+
+	tr := NewTransport().Handle("http://example.com/ads.txt", f)
+	req, _ := adstxt.NewRequest("example.com")
+	if _, err := NewCrawler(tr, adstxt.WithMaxBodySize(1<<10)).Get(req); err == nil {
+		t.Error("Expected huge Ads.txt file to exceed maximum body size")
+	}
+
+	req, _ = adstxt.NewRequest("example.com")
+	res, err := NewCrawler(tr).Get(req)
+	if err != nil {
+		t.Fatalf("Expected huge Ads.txt file and not [%s]", err)
+	}
+	if len(res.DataRecords) == 0 {
+		t.Error("Expected huge Ads.txt file data records")
+	}
+}
+
+// TestFetcher test mock Fetcher used as crawler fetcher
+func TestFetcher(t *testing.T) {
+	errFetch := errors.New("fetch failed")
+	f := NewFetcher().
+		Add("http://example.com/ads.txt", ValidBody).
+		AddError("http://failed.com/ads.txt", errFetch)
+	c := adstxt.NewCrawler(adstxt.WithFetcher(f))
+
+	req, _ := adstxt.NewRequest("example.com")
+	res, err := c.Get(req)
+	if err != nil {
+		t.Fatalf("Expected valid Ads.txt file and not [%s]", err)
+	}
+	AssertRecordsEqual(t, Parse(t, ValidBody), res.Records)
+
+	req, _ = adstxt.NewRequest("failed.com")
+	if _, err := c.Get(req); !errors.Is(err, errFetch) {
+		t.Errorf("Expected fetch error and not [%v]", err)
+	}
+
+	req, _ = adstxt.NewRequest("missing.com")
+	if _, err := c.Get(req); !errors.Is(err, adstxt.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound error and not [%v]", err)
+	}
+
+	if n := f.Calls("http://example.com/ads.txt"); n != 1 {
+		t.Errorf("Expected [1] fetch of [http://example.com/ads.txt] and not [%d]", n)
+	}
+}
+
+// TestFetcherContext test mock Fetcher with canceled context
+func TestFetcherContext(t *testing.T) {
+	f := NewFetcher().Add("http://example.com/ads.txt", ValidBody)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	req, _ := adstxt.NewRequest("example.com")
+	if _, err := f.Fetch(ctx, req); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context canceled error and not [%v]", err)
+	}
+}
+
+// TestRecordsEqual test Ads.txt records equality regardless of order, case and duplicates
+func TestRecordsEqual(t *testing.T) {
+	a := Parse(t, "google.com, pub-1, DIRECT\nopenx.com, 2, RESELLER\n")
+	b := Parse(t, "OPENX.COM, 2, reseller\ngoogle.com, pub-1, DIRECT\ngoogle.com, pub-1, DIRECT\n")
+	c := Parse(t, "google.com, pub-1, RESELLER\n")
+
+	if !RecordsEqual(a, b) {
+		t.Error("Expected Ads.txt records to be equal")
+	}
+	if RecordsEqual(a, c) {
+		t.Error("Expected Ads.txt records not to be equal")
+	}
+	if RecordsEqual(a, nil) || !RecordsEqual(nil, nil) {
+		t.Error("Expected nil Ads.txt records to equal only nil records")
+	}
+
+	mock := &testing.T{}
+	AssertRecordsEqual(mock, a, c)
+	if !mock.Failed() {
+		t.Error("Expected AssertRecordsEqual to fail the test")
+	}
+}
@@ -0,0 +1,154 @@
+package adstxttest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tzafrirben/go-adstxt-crawler/adstxt"
+)
+
+// Clock manual adstxt.Clock for deterministic tests of Ads.txt files expiration, caching and Scheduler re-crawls (see
+// adstxt.WithClock): the clock time changes only by Advance and Set, and timers fire once the clock reaches their
+// deadline. Clock is safe to use from multiple goroutines
+//
+//	clock := adstxttest.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+//	c := adstxttest.NewCrawler(tr, adstxt.WithClock(clock))
+//	go scheduler.Run(ctx)
+//	clock.WaitTimers(1)
+//	clock.Advance(adstxt.DefaultExpiration) // Ads.txt file expired, and is re-crawled
+type Clock struct {
+	mu      sync.Mutex
+	changed *sync.Cond
+	now     time.Time
+	pending map[*timer]bool
+}
+
+// NewClock create new manual clock set to now
+func NewClock(now time.Time) *Clock {
+	c := &Clock{now: now, pending: map[*timer]bool{}}
+	c.changed = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now is the adstxt.Clock interface implementation for Clock: return the clock time
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// Since is the adstxt.Clock interface implementation for Clock: return the clock time elapsed since t
+func (c *Clock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+// NewTimer is the adstxt.Clock interface implementation for Clock: return timer that fires once the clock is
+// advanced by d
+func (c *Clock) NewTimer(d time.Duration) adstxt.Timer {
+	t := &timer{clock: c, c: make(chan time.Time, 1)}
+	t.Reset(d)
+	return t
+}
+
+// Advance move the clock forward by d, and fire the timers whose deadline is reached, in order of their deadline
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.set(c.now.Add(d))
+}
+
+// Set set the clock time to t, and fire the timers whose deadline is reached, in order of their deadline
+func (c *Clock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.set(t)
+}
+
+// Timers return the number of timers that did not fire yet and were not stopped
+func (c *Clock) Timers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.pending)
+}
+
+// WaitTimers block until at least n timers are pending (see Timers), for example until the goroutines of the code
+// under test are waiting for the clock to be advanced
+func (c *Clock) WaitTimers(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for len(c.pending) < n {
+		c.changed.Wait()
+	}
+}
+
+// set set the clock time and fire due timers: must be called with the clock lock held
+func (c *Clock) set(now time.Time) {
+	c.now = now
+	for {
+		var next *timer
+		for t := range c.pending {
+			if !t.deadline.After(now) && (next == nil || t.deadline.Before(next.deadline)) {
+				next = t
+			}
+		}
+		if next == nil {
+			break
+		}
+		next.fire()
+	}
+	c.changed.Broadcast()
+}
+
+// timer adstxt.Timer of manual clock
+type timer struct {
+	clock    *Clock
+	c        chan time.Time
+	deadline time.Time
+}
+
+// C is the adstxt.Timer interface implementation for timer
+func (t *timer) C() <-chan time.Time {
+	return t.c
+}
+
+// Stop is the adstxt.Timer interface implementation for timer
+func (t *timer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	active := t.clock.pending[t]
+	delete(t.clock.pending, t)
+	t.clock.changed.Broadcast()
+	return active
+}
+
+// Reset is the adstxt.Timer interface implementation for timer: timer that is reset to non positive duration fires
+// immediately
+func (t *timer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	active := t.clock.pending[t]
+	t.deadline = t.clock.now.Add(d)
+	t.clock.pending[t] = true
+	if d <= 0 {
+		t.fire()
+	}
+	t.clock.changed.Broadcast()
+	return active
+}
+
+// fire send the clock time on the timer channel, unless the previous value was not received: must be called with the
+// clock lock held
+func (t *timer) fire() {
+	delete(t.clock.pending, t)
+	select {
+	case t.c <- t.clock.now:
+	default:
+	}
+}
@@ -0,0 +1,130 @@
+package adstxttest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/tzafrirben/go-adstxt-crawler/adstxt"
+)
+
+// start time of manual clock tests
+var clockStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// hourFile fixture of valid Ads.txt file that expires in one hour
+func hourFile() Fixture {
+	f := ValidFile()
+	f.Header.Set("Cache-Control", "max-age=3600")
+	return f
+}
+
+// TestClockExpiration test Ads.txt response dates and cache freshness by manual clock
+func TestClockExpiration(t *testing.T) {
+	tr := NewTransport().Handle("http://example.com/ads.txt", hourFile())
+	clock := NewClock(clockStart)
+	c := adstxt.NewCachingCrawler(NewCrawler(tr, adstxt.WithClock(clock)), adstxt.NewLRUCache(10))
+
+	req, _ := adstxt.NewRequest("example.com")
+	res, err := c.Get(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Fetched.Equal(clockStart) || !res.Expires.Equal(clockStart.Add(time.Hour)) {
+		t.Errorf("Expected response fetched at [%s] that expires in one hour and not [%s] [%s]", clockStart, res.Fetched, res.Expires)
+	}
+
+	clock.Advance(59 * time.Minute)
+	req, _ = adstxt.NewRequest("example.com")
+	if res, _ := c.Get(req); !res.Cached || res.Age != 59*time.Minute || len(tr.Requests()) != 1 {
+		t.Errorf("Expected cached response of age [59m] and not [%t] [%s]", res.Cached, res.Age)
+	}
+
+	clock.Advance(time.Minute)
+	req, _ = adstxt.NewRequest("example.com")
+	if res, _ := c.Get(req); res.Cached || len(tr.Requests()) != 2 {
+		t.Errorf("Expected expired response to be fetched again and not %v", tr.Requests())
+	}
+}
+
+// TestClockScheduler test Scheduler re-crawl of expired Ads.txt file by manual clock
+func TestClockScheduler(t *testing.T) {
+	tr := NewTransport().Handle("http://example.com/ads.txt", hourFile())
+	clock := NewClock(clockStart)
+
+	crawled := make(chan *adstxt.Response, 1)
+	s, _ := adstxt.NewScheduler(NewCrawler(tr, adstxt.WithClock(clock)), []string{"example.com"},
+		adstxt.HandlerFunc(func(req *adstxt.Request, res *adstxt.Response, err error) {
+			if err != nil {
+				t.Error(err)
+			}
+			crawled <- res
+		}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	// first crawl is delayed by jitter up to 5 minutes
+	clock.WaitTimers(1)
+	clock.Advance(s.Jitter)
+	if res := <-crawled; !res.Fetched.Equal(clock.Now()) {
+		t.Errorf("Expected Ads.txt file to be crawled at [%s] and not [%s]", clock.Now(), res.Fetched)
+	}
+
+	// re-crawl is due once the Ads.txt file expires (plus jitter)
+	clock.WaitTimers(1)
+	clock.Advance(59 * time.Minute)
+	if n := clock.Timers(); n != 1 {
+		t.Errorf("Expected re-crawl to wait for Ads.txt file expiration and not [%d] pending timers", n)
+	}
+	clock.Advance(time.Minute + s.Jitter)
+	if res := <-crawled; res.StatusCode != http.StatusOK {
+		t.Errorf("Expected Ads.txt file to be re-crawled and not [%d]", res.StatusCode)
+	}
+	if r := tr.Requests(); len(r) != 2 {
+		t.Errorf("Expected [2] requests and not %v", r)
+	}
+}
+
+// TestClockTimers test manual clock timers
+func TestClockTimers(t *testing.T) {
+	clock := NewClock(clockStart)
+	first, second := clock.NewTimer(2*time.Second), clock.NewTimer(time.Second)
+	stopped := clock.NewTimer(time.Second)
+	if !stopped.Stop() || clock.Timers() != 2 {
+		t.Errorf("Expected [2] pending timers after stop and not [%d]", clock.Timers())
+	}
+
+	clock.Advance(time.Second)
+	select {
+	case now := <-second.C():
+		if !now.Equal(clockStart.Add(time.Second)) {
+			t.Errorf("Expected timer to fire at [%s] and not [%s]", clockStart.Add(time.Second), now)
+		}
+	default:
+		t.Error("Expected timer to fire once its deadline is reached")
+	}
+	select {
+	case <-first.C():
+		t.Error("Expected timer not to fire before its deadline")
+	case <-stopped.C():
+		t.Error("Expected stopped timer not to fire")
+	default:
+	}
+
+	if first.Reset(time.Second) != true || clock.Timers() != 1 {
+		t.Errorf("Expected reset of pending timer and not [%d] pending timers", clock.Timers())
+	}
+	clock.Set(clockStart.Add(time.Hour))
+	if len(first.C()) != 1 || clock.Timers() != 0 {
+		t.Error("Expected reset timer to fire once the clock is set past its deadline")
+	}
+}
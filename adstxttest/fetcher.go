@@ -0,0 +1,72 @@
+package adstxttest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/tzafrirben/go-adstxt-crawler/adstxt"
+)
+
+// Fetcher mock adstxt.Fetcher that serves Ads.txt files and errors by request URL, and counts the fetches of each
+// URL. URLs without Ads.txt file fail with error that matches adstxt.ErrNotFound. Fetcher is safe to use from multiple
+// goroutines
+type Fetcher struct {
+	bodies map[string]string
+	errs   map[string]error
+	calls  map[string]int
+	mu     sync.Mutex
+}
+
+// NewFetcher create new Fetcher with no Ads.txt files
+func NewFetcher() *Fetcher {
+	return &Fetcher{bodies: map[string]string{}, errs: map[string]error{}, calls: map[string]int{}}
+}
+
+// Add serve Ads.txt file body for requests of url, and return the fetcher so calls can be chained
+func (f *Fetcher) Add(url, body string) *Fetcher {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.bodies[url] = body
+	delete(f.errs, url)
+	return f
+}
+
+// AddError fail requests of url with err, and return the fetcher so calls can be chained
+func (f *Fetcher) AddError(url string, err error) *Fetcher {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.errs[url] = err
+	delete(f.bodies, url)
+	return f
+}
+
+// Fetch is the adstxt.Fetcher interface implementation for Fetcher
+func (f *Fetcher) Fetch(ctx context.Context, req *adstxt.Request) (*adstxt.Response, error) {
+	f.mu.Lock()
+	f.calls[req.URL]++
+	body, ok := f.bodies[req.URL]
+	err := f.errs[req.URL]
+	f.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("[%s] %w", req.URL, adstxt.ErrNotFound)
+	}
+	return adstxt.NewResponse(req, []byte(body))
+}
+
+// Calls return the number of fetches of url
+func (f *Fetcher) Calls(url string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.calls[url]
+}
@@ -0,0 +1,40 @@
+package adstxttest
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/tzafrirben/go-adstxt-crawler/adstxt"
+)
+
+// Parse return Ads.txt records parsed from body, and fail the test if body can not be parsed
+func Parse(t testing.TB, body string) *adstxt.Records {
+	t.Helper()
+
+	records, err := adstxt.ParseBody([]byte(body))
+	if err != nil {
+		t.Fatalf("Failed to parse Ads.txt body: %s", err)
+	}
+	return records
+}
+
+// RecordsEqual return true if both Ads.txt records hold the same data records and variables, after normalization
+// and regardless of order and duplicates (see adstxt.Records.Hash)
+func RecordsEqual(expected, actual *adstxt.Records) bool {
+	if expected == nil || actual == nil {
+		return expected == actual
+	}
+	return expected.Hash() == actual.Hash()
+}
+
+// AssertRecordsEqual fail the test if Ads.txt records are not equal (see RecordsEqual), and report the difference
+// between them (see adstxt.Diff)
+func AssertRecordsEqual(t testing.TB, expected, actual *adstxt.Records) {
+	t.Helper()
+
+	if RecordsEqual(expected, actual) {
+		return
+	}
+	diff, _ := json.Marshal(adstxt.Diff(expected, actual))
+	t.Errorf("Expected Ads.txt records to be equal, difference: %s", diff)
+}
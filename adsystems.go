@@ -3,6 +3,7 @@ package adstxt
 import (
 	"fmt"
 	"log"
+	"net"
 	"net/url"
 	"strings"
 
@@ -671,6 +672,11 @@ func validateDomainName(domain string) bool {
 		return false
 	}
 
+	// domain of DNS name characters only is always parsed as URL host: skip URL parsing
+	if isHostName(domain) {
+		return true
+	}
+
 	// parse domain
 	u, err := url.Parse("http://" + domain)
 	if err != nil {
@@ -682,44 +688,61 @@ func validateDomainName(domain string) bool {
 	return u.Host == domain
 }
 
-// RootDomain Extract “root domain” from specified URL. Root domain is defined as the “public suffix” plus one sting in the name.
+// RootDomain Extract “root domain” from specified URL or hostname. Root domain is defined as the “public suffix” plus one
+// sting in the name, based on the Public Suffix List (so multi-part suffixes such as co.uk are handled). IP address
+// has no public suffix, and it is its own root domain. Internationalized root domain is returned in its Unicode form
 func rootDomain(rawurl string) (string, error) {
-	// Strip domain from specified URL: remove HTTP schema (http/s) and path from input URL string
-	stripDomain := func(rawurl string) string {
-		var index int
-		// remove schema
-		index = strings.Index(rawurl, "://")
-		if index != -1 {
-			rawurl = rawurl[index+3:]
-		}
+	host, err := urlHostname(rawurl)
+	if err != nil {
+		return "", err
+	}
 
-		// remove path
-		index = strings.Index(rawurl, "/")
-		if index != -1 {
-			rawurl = rawurl[0:index]
-		}
+	if net.ParseIP(host) != nil {
+		return host, nil
+	}
 
-		// remove port
-		index = strings.Index(rawurl, ":")
-		if index != -1 {
-			rawurl = rawurl[0:index]
-		}
+	// extract top level domain (Public Suffix List rules are matched against the punycode form of the hostname)
+	d, err := publicsuffix.EffectiveTLDPlusOne(asciiHost(host))
+	if err != nil {
+		return "", err
+	}
+	return unicodeHost(d), nil
+}
 
-		return rawurl
+// urlHostname return lower case hostname of the specified URL or hostname, without scheme, user info, port and path.
+// Internationalized hostname is returned in its Unicode form
+func urlHostname(rawurl string) (string, error) {
+	if !strings.Contains(rawurl, "://") {
+		rawurl = "http://" + rawurl
+	}
+
+	u, err := url.Parse(strings.TrimSpace(rawurl))
+	if err != nil {
+		return "", err
 	}
-	// extract top level domain
-	return publicsuffix.EffectiveTLDPlusOne(stripDomain(rawurl))
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if len(host) == 0 {
+		return "", fmt.Errorf("missing hostname in URL [%s]", rawurl)
+	}
+	return unicodeHost(host), nil
 }
 
 // VaidateAdSystemCName validate that the specifiied ad system domain is a known Ad System.
 // It does not imply that any of the ad systems have been vetted or certified.
 func vaidateAdSystemCName(domain string) error {
-	// check case insensative for domain in
-	adSystemDomain, ok := adSystemDomains[strings.ToLower(domain)]
+	// check case insensative for domain in (internationalized domain is matched by its punycode form)
+	lcDomain := asciiHost(strings.ToLower(domain))
+	adSystemDomain, ok := adSystemDomains[lcDomain]
 	if !ok {
+		// known alias domain of advertising system that is not listed in the IAB normalization mappings
+		if cName, ok := knownAdSystemAliases[lcDomain]; ok {
+			return fmt.Errorf("%s is not the preferred form of the exchange domain. Please consider using %s as the canonical domain name",
+				domain, cName)
+		}
 		// if domain name not found in ad system domains collection, search for it directly in the AdSystem list
 		for _, adSystem := range adSystems {
-			if adSystem.compareCName(domain) {
+			if adSystem.compareCName(lcDomain) {
 				return nil
 			}
 		}
@@ -734,7 +757,7 @@ func vaidateAdSystemCName(domain string) error {
 
 	// domain does not match Ad System Canonical name: it is still valid but publisher should probably use canonical name
 	if len(adSystem.CanonicalDomain) > 0 {
-		match := adSystem.compareCName(domain)
+		match := adSystem.compareCName(lcDomain)
 
 		if !match {
 			return fmt.Errorf("%s is not the preferred form of the exchange domain. Please consider using %s as the canonical domain name",
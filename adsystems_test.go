@@ -13,6 +13,10 @@ func TestMain(m *testing.M) {
 	adSystems[10001] = newAdSystem(10001, "testexchange", "testexchange.net")
 	adSystemDomains["testexchange.com"] = newAdSystemDomain("testexchange.com", 10001)
 
+	// internationalized ad system domain (bücherexchange.com)
+	adSystems[10002] = newAdSystem(10002, "bücherexchange", "xn--bcherexchange-wob.com")
+	adSystemDomains["xn--bcherexchange-wob.com"] = newAdSystemDomain("xn--bcherexchange-wob.com", 10002)
+
 	os.Exit(m.Run())
 }
 
@@ -0,0 +1,114 @@
+package adstxt
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// knownAdSystemAliases alias domains of advertising systems that are not listed in the IAB normalization mappings (see
+// normalizeMappingURL), by lower case alias domain
+var knownAdSystemAliases = map[string]string{
+	"doubleclick.net":       "google.com",
+	"googlesyndication.com": "google.com",
+	"casalemedia.com":       "indexexchange.com",
+	"xandr.com":             "appnexus.com",
+}
+
+// AdSystemAliases canonical advertising system domain of alias domains, by lower case alias domain (internationalized
+// alias domain by its punycode form)
+type AdSystemAliases map[string]string
+
+// Canonical return the canonical domain of advertising system domain, or the trimmed lower case domain if it is not an
+// alias domain
+func (a AdSystemAliases) Canonical(domain string) string {
+	d := strings.ToLower(strings.TrimSpace(domain))
+	if c, ok := a[asciiHost(d)]; ok {
+		return c
+	}
+	return d
+}
+
+// DefaultAdSystemAliases return copy of the maintained advertising system aliases: the IAB normalization mappings of
+// advertising systems with declared canonical domain, and known alias domains of advertising systems. Add or replace
+// aliases of the returned copy and use SetAdSystemAliases to override the aliases used by the package
+func DefaultAdSystemAliases() AdSystemAliases {
+	aliases := AdSystemAliases{}
+	for domain, d := range adSystemDomains {
+		s, ok := adSystems[d.ID]
+		if !ok || len(s.CanonicalDomain) == 0 || !isHostName(domain) {
+			continue
+		}
+		// domain of advertising system with multiple canonical domains is its own canonical domain
+		if s.compareCName(domain) {
+			aliases[domain] = domain
+			continue
+		}
+		aliases[domain] = strings.TrimSpace(strings.Split(s.CanonicalDomain, ",")[0])
+	}
+	for alias, canonical := range knownAdSystemAliases {
+		aliases[alias] = canonical
+	}
+	return aliases
+}
+
+// package advertising system aliases (see SetAdSystemAliases)
+var (
+	customAdSystemAliases  atomic.Pointer[AdSystemAliases]
+	defaultAdSystemAliases = sync.OnceValue(DefaultAdSystemAliases)
+)
+
+// SetAdSystemAliases set the advertising system aliases used by Records.CanonicalAdSystems and Records.IsAuthorized.
+// Alias domains are matched case insensitive. Use nil to restore the default aliases (see DefaultAdSystemAliases)
+func SetAdSystemAliases(aliases AdSystemAliases) {
+	if aliases == nil {
+		customAdSystemAliases.Store(nil)
+		return
+	}
+
+	normalized := make(AdSystemAliases, len(aliases))
+	for alias, canonical := range aliases {
+		normalized[asciiHost(strings.ToLower(strings.TrimSpace(alias)))] = strings.ToLower(strings.TrimSpace(canonical))
+	}
+	customAdSystemAliases.Store(&normalized)
+}
+
+// adSystemAliases return the advertising system aliases used by the package
+func adSystemAliases() AdSystemAliases {
+	if a := customAdSystemAliases.Load(); a != nil {
+		return *a
+	}
+	return defaultAdSystemAliases()
+}
+
+// CanonicalAdSystem return the canonical domain of advertising system domain (see SetAdSystemAliases), or the trimmed
+// lower case domain if it is not an alias domain
+func CanonicalAdSystem(domain string) string {
+	return adSystemAliases().Canonical(domain)
+}
+
+// CanonicalAdSystems return the distinct canonical domains of the advertising systems of all data records, sorted.
+// Alias domains of the same advertising system (for example doubleclick.net and google.com) are returned once
+func (r *Records) CanonicalAdSystems() []string {
+	aliases := adSystemAliases()
+
+	seen := map[string]bool{}
+	domains := []string{}
+	for _, d := range r.DataRecords {
+		c := aliases.Canonical(d.AdverterDomain)
+		if len(c) == 0 || seen[c] {
+			continue
+		}
+		seen[c] = true
+		domains = append(domains, c)
+	}
+	sort.Strings(domains)
+	return domains
+}
+
+// sameAdSystem compare advertising system domains by their canonical domains (see sameDomain)
+func sameAdSystem(a, b string) bool {
+	aliases := adSystemAliases()
+	return sameDomain(aliases.Canonical(a), aliases.Canonical(b))
+}
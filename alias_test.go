@@ -0,0 +1,86 @@
+package adstxt
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestDefaultAdSystemAliases test default aliases of IAB normalization mappings and known alias domains
+func TestDefaultAdSystemAliases(t *testing.T) {
+	aliases := DefaultAdSystemAliases()
+
+	tests := map[string]string{
+		"doubleclick.net":        "google.com",
+		"googletagservices.com":  "google.com",
+		"Google.com":             "google.com",
+		" ADNXS.com ":            "appnexus.com",
+		"aolcloud.net":           "aolcloud.net",
+		"unknownexchange.com":    "unknownexchange.com",
+		"www.indexexchange.com ": "indexexchange.com",
+	}
+	for domain, canonical := range tests {
+		if c := aliases.Canonical(domain); c != canonical {
+			t.Errorf("Expected canonical domain of [%s] to be [%s] and not [%s]", domain, canonical, c)
+		}
+	}
+
+	// returned aliases are a copy
+	aliases["doubleclick.net"] = "doubleclick.net"
+	if c := CanonicalAdSystem("doubleclick.net"); c != "google.com" {
+		t.Errorf("Expected default aliases to be unchanged and not [%s]", c)
+	}
+
+	// known alias domain is reported as not the preferred form of the advertising system domain
+	if err := vaidateAdSystemCName("doubleclick.net"); err == nil || !strings.Contains(err.Error(), "google.com") {
+		t.Errorf("Expected [doubleclick.net] to be reported as alias of [google.com] and not [%v]", err)
+	}
+}
+
+// TestRecordsCanonicalAdSystems test distinct canonical advertising systems of data records
+func TestRecordsCanonicalAdSystems(t *testing.T) {
+	records := &Records{DataRecords: []*DataRecord{
+		{AdverterDomain: "google.com", PublisherAccountID: "pub-1", AccountType: RelationshipDirect},
+		{AdverterDomain: "doubleclick.net", PublisherAccountID: "pub-1", AccountType: RelationshipReseller},
+		{AdverterDomain: "greenadexchange.com", PublisherAccountID: "XF7342", AccountType: RelationshipDirect},
+		{AdverterDomain: "appnexus.com", PublisherAccountID: "1", AccountType: RelationshipReseller},
+		{AdverterDomain: "ADNXS.com", PublisherAccountID: "2", AccountType: RelationshipReseller},
+	}}
+
+	expected := []string{"appnexus.com", "google.com", "greenadexchange.com"}
+	if domains := records.CanonicalAdSystems(); !reflect.DeepEqual(domains, expected) {
+		t.Errorf("Expected canonical advertising systems %v and not %v", expected, domains)
+	}
+}
+
+// TestSetAdSystemAliases test authorization check match alias domains, and custom aliases override
+func TestSetAdSystemAliases(t *testing.T) {
+	records := &Records{DataRecords: []*DataRecord{
+		{AdverterDomain: "doubleclick.net", PublisherAccountID: "pub-1", AccountType: RelationshipDirect},
+		{AdverterDomain: "greenadexchange.com", PublisherAccountID: "XF7342", AccountType: RelationshipDirect},
+	}}
+
+	if ok, _ := records.IsAuthorized("google.com", "pub-1", RelationshipDirect); !ok {
+		t.Errorf("Expected alias domain record to authorize [google.com] account [pub-1]")
+	}
+	if ok, _ := records.IsAuthorized("green.example.com", "XF7342", RelationshipAny); ok {
+		t.Errorf("Expected [green.example.com] to not match [greenadexchange.com] by default")
+	}
+
+	aliases := DefaultAdSystemAliases()
+	aliases["Green.Example.com"] = "greenadexchange.com"
+	SetAdSystemAliases(aliases)
+	defer SetAdSystemAliases(nil)
+
+	if ok, _ := records.IsAuthorized("green.example.com", "XF7342", RelationshipAny); !ok {
+		t.Errorf("Expected custom alias to authorize [green.example.com] account [XF7342]")
+	}
+	if ok, _ := records.IsAuthorized("google.com", "pub-1", RelationshipDirect); !ok {
+		t.Errorf("Expected default aliases to be kept in custom aliases")
+	}
+
+	SetAdSystemAliases(nil)
+	if ok, _ := records.IsAuthorized("green.example.com", "XF7342", RelationshipAny); ok {
+		t.Errorf("Expected default aliases to be restored")
+	}
+}
@@ -0,0 +1,65 @@
+package adstxt
+
+import (
+	"context"
+	"errors"
+)
+
+// well-known alternate locations of Ads.txt files (see Request AlternatePaths)
+const (
+	// WellKnownAdsTxtPath Ads.txt file location in the well-known URIs directory (RFC 8615), used by some CDNs
+	WellKnownAdsTxtPath = "/.well-known/ads.txt"
+	// WellKnownAppAdsTxtPath app-ads.txt file location in the well-known URIs directory (RFC 8615)
+	WellKnownAppAdsTxtPath = "/.well-known/app-ads.txt"
+)
+
+// fetchAlternates crawl Ads.txt file from the request path, and try the request alternate paths in order if the file
+// is not found there (see isNotFoundAt). Return the response of the first path where the file was found, with its
+// alternate path set in Response AlternatePath, or the error of the request path if the file was not found at any
+// path
+func (c *Crawler) fetchAlternates(ctx context.Context, req *Request) (*Response, error) {
+	if len(req.AlternatePaths) == 0 {
+		return c.fetch(ctx, req)
+	}
+
+	orig := *req
+	variants := []*Request{&orig}
+	for _, p := range req.AlternatePaths {
+		r := orig
+		if r.SetPath(p) == nil {
+			variants = append(variants, &r)
+		}
+	}
+
+	// file is looked up at alternate paths while it is not found, and error of the request path is returned if it is
+	// not found at any path, or if remote host failed at alternate path
+	var notFound error
+	return c.fetchVariants(ctx, req, variants, func(ctx context.Context, i int, r *Request) (*Response, bool, error) {
+		res, err := c.fetch(ctx, r)
+		switch {
+		case err == nil && i > 0:
+			res.AlternatePath = r.Path
+			return res, true, nil
+		case i == 0 && (err == nil || !isNotFoundAt(err)):
+			return res, true, err
+		case i == 0:
+			notFound = err
+		case !isNotFoundAt(err):
+			return nil, true, notFound
+		}
+		return nil, false, err
+	})
+}
+
+// isNotFoundAt check if Ads.txt request error means the file does not exist at the requested path: HTTP 4xx status
+// code, or HTML page (for example "page not found" page) served instead of the file. Errors of the remote host itself
+// (DNS, connection, timeout, throttling) are not retried at alternate paths
+func isNotFoundAt(err error) bool {
+	var clientErr *ErrClientError
+	var contentTypeErr *ErrInvalidContentType
+	var htmlErr *ErrHTMLPage
+	if errors.Is(err, ErrRateLimited) {
+		return false
+	}
+	return errors.As(err, &clientErr) || errors.As(err, &contentTypeErr) || errors.As(err, &htmlErr)
+}
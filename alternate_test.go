@@ -0,0 +1,70 @@
+package adstxt
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// TestAlternatePaths test crawler try request alternate paths in order when Ads.txt file is not found at the request
+// path
+func TestAlternatePaths(t *testing.T) {
+	sent := []string{}
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			sent = append(sent, req.URL.Path)
+
+			switch req.URL.Path {
+			case WellKnownAdsTxtPath:
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": []string{"text/plain"}},
+					Body:       io.NopCloser(strings.NewReader("greenadexchange.com,XF7342,DIRECT")),
+					Request:    req,
+				}, nil
+			case "/html/ads.txt":
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": []string{"text/html"}},
+					Body:       io.NopCloser(strings.NewReader("<html>Page not found</html>")),
+					Request:    req,
+				}, nil
+			}
+			return &http.Response{StatusCode: http.StatusNotFound, Status: "404 Not Found", Body: http.NoBody, Request: req}, nil
+		}),
+	}
+	c := NewCrawler(WithHTTPClient(client))
+
+	req := &Request{URL: "https://example.com/ads.txt", Domain: "example.com",
+		AlternatePaths: []string{"/html/ads.txt", WellKnownAdsTxtPath, "/other/ads.txt"}}
+	res, err := c.Get(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(sent, " ") != "/ads.txt /html/ads.txt /.well-known/ads.txt" {
+		t.Errorf("Expected alternate paths to be crawled in order until the file is found and not [%v]", sent)
+	}
+	if res.AlternatePath != WellKnownAdsTxtPath || res.Request != req || req.URL != "https://example.com/.well-known/ads.txt" {
+		t.Errorf("Expected response to hold the alternate path that succeeded and not [%s] [%s]", res.AlternatePath, req.URL)
+	}
+	if res.Attempts != 3 || len(res.DataRecords) != 1 {
+		t.Errorf("Expected [3] attempts and not [%d]", res.Attempts)
+	}
+
+	// file found at the request path
+	sent = []string{}
+	res, err = c.Get(&Request{URL: "https://example.com/.well-known/ads.txt", Domain: "example.com", Path: WellKnownAdsTxtPath,
+		AlternatePaths: []string{"/ads.txt"}})
+	if err != nil || res.AlternatePath != "" || len(sent) != 1 {
+		t.Errorf("Expected no alternate path of file found at the request path [%v] [%v]", err, sent)
+	}
+
+	// error of the request path is returned when the file is not found at any path
+	sent = []string{}
+	_, err = c.Get(&Request{URL: "https://example.com/ads.txt", Domain: "example.com", AlternatePaths: []string{"/other/ads.txt"}})
+	if !errors.Is(err, ErrNotFound) || len(sent) != 2 {
+		t.Errorf("Expected [%s] of all paths and not [%v] [%v]", ErrNotFound, err, sent)
+	}
+}
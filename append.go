@@ -0,0 +1,67 @@
+package adstxt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// incremental parse errors
+const (
+	errAppendStartLine = "append start line [%d] must follow the last parsed line [%d]"
+)
+
+// AppendParse parse lines appended to append-only Ads.txt file, and add their Data\Variable records and parse warnings
+// to the records, so very large Ads.txt files can be monitored by fetching only their appended bytes (see
+// Request.RangeStart). b holds the appended lines, and startLine is the line index of its first line in the Ads.txt
+// file: the line that follows the last parsed line (len(Body)+1 when Body is kept). Line indexes of appended records
+// are set accordingly, and records are not changed if b fails to be parsed
+func (r *Records) AppendParse(b []byte, startLine int) error {
+	last := r.lastLine()
+	if startLine <= last || (len(r.Body) > 0 && startLine != len(r.Body)+1) {
+		return fmt.Errorf(errAppendStartLine, startLine, last)
+	}
+
+	// scan all lines before adding any record, so records are not changed on scan error (e.g. line too long)
+	b, _ = toUTF8(b, "")
+	lines := make([]string, 0, bytes.Count(b, []byte("\n"))+1)
+	if err := scanLines(bytes.NewReader(b), func(index int, txt string) error {
+		lines = append(lines, txt)
+		return nil
+	}); err != nil {
+		var e *ErrLineTooLong
+		if errors.As(err, &e) {
+			e.Index += startLine - 1
+		}
+		return err
+	}
+
+	// trailing comments are attached to the first appended record
+	r.comments = r.TrailingComments
+	keepBody := len(r.Body) > 0 || startLine == 1
+	for i, txt := range lines {
+		if keepBody {
+			r.Body = append(r.Body, txt)
+		}
+		r.parseRecord(startLine+i, txt, FieldPolicy{})
+	}
+	r.TrailingComments = r.comments
+	r.comments = nil
+
+	return nil
+}
+
+// lastLine return the last line index of Ads.txt file that was parsed into the records
+func (r *Records) lastLine() int {
+	last := len(r.Body)
+	for _, d := range r.DataRecords {
+		last = max(last, r.Line(d))
+	}
+	for _, v := range r.Variables {
+		last = max(last, r.Line(v))
+	}
+	for _, w := range r.Warnings {
+		last = max(last, w.Index)
+	}
+	return last
+}
@@ -0,0 +1,63 @@
+package adstxt
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestAppendParse test parsing lines appended to Ads.txt file
+func TestAppendParse(t *testing.T) {
+	records, _ := ParseBody([]byte("greenadexchange.com, XF7342, DIRECT\n# appended records\n"))
+
+	if err := records.AppendParse([]byte("google.com, pub-1, RESELLER\ncontact=adops@example.com\nnot a record, , DIRECT\n"), 3); err != nil {
+		t.Fatal(err)
+	}
+	if len(records.DataRecords) != 2 || len(records.Variables) != 1 || len(records.Warnings) != 1 || len(records.Body) != 5 {
+		t.Fatalf("Expected appended records, variable, warning and body lines and not [%d] [%d] [%d] [%d]", len(records.DataRecords),
+			len(records.Variables), len(records.Warnings), len(records.Body))
+	}
+	appended := records.DataRecords[1]
+	if records.Line(appended) != 3 || records.Line(records.Variables[0]) != 4 || records.Warnings[0].Index != 5 {
+		t.Errorf("Expected appended lines index to start at line [3]")
+	}
+	if len(appended.Comments) != 1 || len(records.TrailingComments) != 0 {
+		t.Errorf("Expected trailing comment to be attached to appended record and not [%v]", appended.Comments)
+	}
+	if len(records.Contacts) != 1 {
+		t.Errorf("Expected appended CONTACT variable to be set and not [%v]", records.Contacts)
+	}
+
+	// start line must follow the last parsed line
+	for _, line := range []int{0, 5, 7} {
+		if err := records.AppendParse([]byte("google.com, pub-2, DIRECT"), line); err == nil {
+			t.Errorf("Expected append start line [%d] to fail", line)
+		}
+	}
+
+	// records are not changed when appended lines fail to be parsed
+	if err := records.AppendParse([]byte(strings.Repeat("x", maxLineSize+1)), 6); err == nil {
+		t.Errorf("Expected appended line longer than [%d] bytes to fail", maxLineSize)
+	}
+	if len(records.Body) != 5 {
+		t.Errorf("Expected records to be unchanged by failed append")
+	}
+
+	// records without body lines (see WithBoundedMemory) are appended by their last parsed line
+	records.Body = nil
+	if err := records.AppendParse([]byte("google.com, pub-2, DIRECT"), 6); err != nil || records.Line(records.DataRecords[2]) != 6 || records.Body != nil {
+		t.Errorf("Expected record to be appended without body lines [%v]", err)
+	}
+}
+
+// TestPartialResponseJSON test partial response is encoded and decoded as partial
+func TestPartialResponseJSON(t *testing.T) {
+	b, err := json.Marshal(&Response{Request: &Request{URL: "https://example.com/ads.txt"}, Records: &Records{}, Partial: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := &Response{}
+	if err := json.Unmarshal(b, res); err != nil || !res.Partial {
+		t.Errorf("Expected partial response JSON [%s] to be decoded as partial [%v]", b, err)
+	}
+}
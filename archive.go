@@ -0,0 +1,180 @@
+package adstxt
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrRecordNotArchived data record was not found in any archived version of the Ads.txt file
+var ErrRecordNotArchived = errors.New("record not found in archive")
+
+// archiveVersionKey Store key of single archived Ads.txt file version
+const archiveVersionKey = "%s#%d"
+
+// Archive keep every crawled version of Ads.txt files in a Store, with the time each version was fetched, for
+// auditing Ads.txt files history: the records as of some date (see AsOf), or when a seller was first added (see
+// FirstSeen). Versions are saved in the order they were crawled, and are never replaced. Archive must be the only
+// writer of its Store, and the Store should not be shared with the crawler responses (ListExpired lists archived
+// versions). Archive is safe to use from multiple goroutines
+type Archive struct {
+	Clock Clock // Clock source of versions fetch time and of archived responses age (system clock if nil)
+
+	store    Store
+	versions map[string]int // number of archived versions by key, loaded from the store on first access
+	mu       sync.Mutex
+}
+
+// NewArchive create new Archive that saves Ads.txt file versions in store
+func NewArchive(store Store) *Archive {
+	return &Archive{store: store, versions: map[string]int{}}
+}
+
+// Save archive new version of Ads.txt file by key (the request Ads.txt URL, as for Store). Version time is the
+// response Fetched time, or now if the response has no fetch time. NotModified responses are not archived, since the
+// Ads.txt file did not change since the previous version
+func (a *Archive) Save(key string, res *Response) error {
+	if res.NotModified {
+		return nil
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	n, err := a.count(key)
+	if err != nil {
+		return err
+	}
+
+	version := *res
+	if version.Fetched.IsZero() {
+		version.Fetched = clockNow(a.Clock).UTC()
+	}
+	if err := a.store.SaveResponse(fmt.Sprintf(archiveVersionKey, key, n+1), &version); err != nil {
+		// version not allowed by the store policy is not archived (see PolicyStore)
+		if errors.Is(err, ErrStoragePolicy) {
+			return nil
+		}
+		return err
+	}
+
+	a.versions[key] = n + 1
+	return nil
+}
+
+// Versions return the number of archived versions of Ads.txt file
+func (a *Archive) Versions(key string) (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.count(key)
+}
+
+// Version return archived version of Ads.txt file, starting from 1 for the first archived version, marked as cached
+// response with its age and staleness (see Response.Cached). Return ErrResponseNotFound if there is no such version
+func (a *Archive) Version(key string, version int) (*Response, error) {
+	if version < 1 {
+		return nil, ErrResponseNotFound
+	}
+	res, err := a.store.LoadResponse(fmt.Sprintf(archiveVersionKey, key, version))
+	if err != nil {
+		return nil, err
+	}
+	res.setCached(clockNow(a.Clock))
+	return res, nil
+}
+
+// AsOf return the version of Ads.txt file that was current at t: the last version fetched at or before t. Return
+// ErrResponseNotFound if Ads.txt file was not archived before t
+func (a *Archive) AsOf(key string, t time.Time) (*Response, error) {
+	n, err := a.Versions(key)
+	if err != nil {
+		return nil, err
+	}
+
+	for v := n; v > 0; v-- {
+		res, err := a.Version(key, v)
+		if err != nil {
+			return nil, err
+		}
+		if !res.Fetched.After(t) {
+			return res, nil
+		}
+	}
+
+	return nil, ErrResponseNotFound
+}
+
+// FirstSeen return the first archived version of Ads.txt file that includes data record of advertising system and
+// publisher account ID (compared after normalization, as in Diff). Return ErrRecordNotArchived if no archived
+// version includes the data record
+func (a *Archive) FirstSeen(key string, adSystemDomain string, publisherAccountID string) (*Response, error) {
+	n, err := a.Versions(key)
+	if err != nil {
+		return nil, err
+	}
+
+	target := (&DataRecord{AdverterDomain: adSystemDomain, PublisherAccountID: publisherAccountID}).diffKey()
+	for v := 1; v <= n; v++ {
+		res, err := a.Version(key, v)
+		if err != nil {
+			return nil, err
+		}
+		if res.Records == nil {
+			continue
+		}
+		for _, r := range res.DataRecords {
+			if r.diffKey() == target {
+				return res, nil
+			}
+		}
+	}
+
+	return nil, ErrRecordNotArchived
+}
+
+// count return the number of archived versions of Ads.txt file. Versions are numbered sequentially, so the number of
+// versions in the store is found by exponential and then binary search for the last stored version. Must be called
+// with archive lock held
+func (a *Archive) count(key string) (int, error) {
+	if n, ok := a.versions[key]; ok {
+		return n, nil
+	}
+
+	exists := func(v int) (bool, error) {
+		_, err := a.store.LoadResponse(fmt.Sprintf(archiveVersionKey, key, v))
+		if err == ErrResponseNotFound {
+			return false, nil
+		}
+		return err == nil, err
+	}
+
+	// find upper bound of versions: version lo exists and version hi does not
+	lo, hi := 0, 1
+	for {
+		ok, err := exists(hi)
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			break
+		}
+		lo, hi = hi, hi*2
+	}
+	for hi-lo > 1 {
+		mid := (lo + hi) / 2
+		ok, err := exists(mid)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+
+	a.versions[key] = lo
+	return lo, nil
+}
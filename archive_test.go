@@ -0,0 +1,102 @@
+package adstxt
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestArchive test archiving Ads.txt file versions and querying Ads.txt file history
+func TestArchive(t *testing.T) {
+	s, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := NewArchive(s)
+
+	key := "http://example.com/ads.txt"
+	day := func(d int) time.Time {
+		return time.Date(2024, time.March, d, 0, 0, 0, 0, time.UTC)
+	}
+	versions := []struct {
+		body    string
+		fetched time.Time
+	}{
+		{"greenadexchange.com,XF7342,DIRECT", day(1)},
+		{"greenadexchange.com,XF7342,DIRECT\ngoogle.com,pub-1,RESELLER", day(5)},
+		{"google.com,PUB-1,RESELLER", day(10)},
+	}
+	for _, v := range versions {
+		records, _ := ParseBody([]byte(v.body))
+		if err := a.Save(key, &Response{Records: records, Fetched: v.fetched}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := a.Save(key, &Response{Records: &Records{}, NotModified: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	// versions are loaded from the store by new archive
+	a = NewArchive(s)
+	if n, err := a.Versions(key); err != nil || n != 3 {
+		t.Errorf("Expected [3] archived versions and not [%d] (%v)", n, err)
+	}
+
+	res, err := a.AsOf(key, day(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Fetched.Equal(day(5)) || len(res.DataRecords) != 2 {
+		t.Errorf("Expected version fetched at [%s] and not [%s]", day(5), res.Fetched)
+	}
+	if !res.Cached || res.Age < time.Since(day(5))-time.Minute {
+		t.Errorf("Expected archived version to be marked as cached response of age [%s]", time.Since(day(5)))
+	}
+	if res, _ := a.AsOf(key, day(10)); res == nil || !res.Fetched.Equal(day(10)) {
+		t.Errorf("Expected version fetched at query time to be returned")
+	}
+	if _, err := a.AsOf(key, day(1).Add(-time.Second)); err != ErrResponseNotFound {
+		t.Errorf("Expected ErrResponseNotFound before first version and not [%v]", err)
+	}
+
+	res, err = a.FirstSeen(key, "Google.com", "pub-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Fetched.Equal(day(5)) {
+		t.Errorf("Expected seller to be first seen at [%s] and not [%s]", day(5), res.Fetched)
+	}
+	if _, err := a.FirstSeen(key, "openx.com", "540"); !errors.Is(err, ErrRecordNotArchived) {
+		t.Errorf("Expected ErrRecordNotArchived and not [%v]", err)
+	}
+
+	if n, _ := a.Versions("http://missing.com/ads.txt"); n != 0 {
+		t.Errorf("Expected no archived versions and not [%d]", n)
+	}
+}
+
+// TestArchiveClock test archived versions fetch time and age are set by the archive clock
+func TestArchiveClock(t *testing.T) {
+	s, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := &testClock{now: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)}
+	a := NewArchive(s)
+	a.Clock = clock
+
+	key := "http://example.com/ads.txt"
+	records, _ := ParseBody([]byte("greenadexchange.com,XF7342,DIRECT"))
+	if err := a.Save(key, &Response{Records: records}); err != nil {
+		t.Fatal(err)
+	}
+	clock.advance(time.Hour)
+
+	res, err := a.Version(key, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Fetched.Equal(time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)) || res.Age != time.Hour {
+		t.Errorf("Expected version fetched at clock time of age [1h] and not [%s] of age [%s]", res.Fetched, res.Age)
+	}
+}
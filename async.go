@@ -0,0 +1,130 @@
+package adstxt
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// OverflowPolicy what AsyncHandler does with new result when its queue is full
+type OverflowPolicy int
+
+const (
+	// OverflowBlock block until there is room in the queue, which throttles the crawl (see Handler)
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropNewest drop the new result
+	OverflowDropNewest
+	// OverflowDropOldest drop the oldest queued result, and queue the new result
+	OverflowDropOldest
+)
+
+// asyncResult single result queued by AsyncHandler
+type asyncResult struct {
+	req *Request
+	res *Response
+	err error
+}
+
+// AsyncHandler Handler that queues results in bounded queue, and passes them to the wrapped handler from its own
+// workers, so the crawl goes on while the wrapped handler catches up with burst of results. Once the queue is full,
+// new results are handled by the overflow policy: block the crawl (the default), or drop results. Memory use is
+// bounded by the queue size, since each queued result holds its response. Close must be called once the crawl is
+// done, to wait for all queued results to be handled
+type AsyncHandler struct {
+	OnDrop HandlerFunc // OnDrop called with each dropped result, for example to log or count it (optional)
+
+	h       Handler
+	policy  OverflowPolicy
+	queue   chan *asyncResult
+	dropped int64
+	wg      sync.WaitGroup
+	mu      sync.Mutex // mu serializes drop oldest overflow handling
+	close   sync.Once
+}
+
+// NewAsyncHandler create new AsyncHandler that queues up to size results, and passes them to h from workers
+// goroutines (single worker if less than 1). h must be safe to use from multiple goroutines when workers is more than
+// 1, and results are passed to h in order of queue only with single worker
+func NewAsyncHandler(h Handler, size, workers int, policy OverflowPolicy) *AsyncHandler {
+	if size < 1 {
+		size = 1
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	a := &AsyncHandler{h: h, policy: policy, queue: make(chan *asyncResult, size)}
+	a.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer a.wg.Done()
+			for r := range a.queue {
+				a.h.Handle(r.req, r.res, r.err)
+			}
+		}()
+	}
+	return a
+}
+
+// Handle is the Handler interface implementation for AsyncHandler: queue the result, or apply the overflow policy if
+// the queue is full. Handle must not be called after Close
+func (a *AsyncHandler) Handle(req *Request, res *Response, err error) {
+	// queued response is copy of the response, so it is not released once Handle returns (see WithBoundedMemory)
+	if res != nil {
+		c := *res
+		res = &c
+	}
+	r := &asyncResult{req: req, res: res, err: err}
+
+	switch a.policy {
+	case OverflowDropNewest:
+		select {
+		case a.queue <- r:
+		default:
+			a.drop(r)
+		}
+	case OverflowDropOldest:
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		for {
+			select {
+			case a.queue <- r:
+				return
+			default:
+			}
+			// oldest result may be taken by a worker at the same time, so queue is retried until there is room
+			select {
+			case oldest := <-a.queue:
+				a.drop(oldest)
+			default:
+			}
+		}
+	default:
+		a.queue <- r
+	}
+}
+
+// drop count dropped result, and pass it to OnDrop
+func (a *AsyncHandler) drop(r *asyncResult) {
+	atomic.AddInt64(&a.dropped, 1)
+	if a.OnDrop != nil {
+		a.OnDrop(r.req, r.res, r.err)
+	}
+}
+
+// Dropped return the number of results dropped since the queue was full
+func (a *AsyncHandler) Dropped() int64 {
+	return atomic.LoadInt64(&a.dropped)
+}
+
+// Len return the number of queued results that were not passed to the wrapped handler yet
+func (a *AsyncHandler) Len() int {
+	return len(a.queue)
+}
+
+// Close stop accepting results, and wait until all queued results were passed to the wrapped handler
+func (a *AsyncHandler) Close() {
+	a.close.Do(func() {
+		close(a.queue)
+	})
+	a.wg.Wait()
+}
@@ -0,0 +1,135 @@
+package adstxt
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// backpressureClient HTTP client that serves Ads.txt file to any request, and counts the sent requests
+func backpressureClient(sent *int64) *http.Client {
+	return &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt64(sent, 1)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       io.NopCloser(strings.NewReader("greenadexchange.com,XF7342,DIRECT")),
+				Request:    req,
+			}, nil
+		}),
+	}
+}
+
+// backpressureRequests return n Ads.txt requests of different domains
+func backpressureRequests(n int) []*Request {
+	req := []*Request{}
+	for i := 0; i < n; i++ {
+		req = append(req, &Request{URL: fmt.Sprintf("https://example%d.com/ads.txt", i), Domain: fmt.Sprintf("example%d.com", i)})
+	}
+	return req
+}
+
+// TestHandlerBackpressure test slow handler throttles batch crawl: no new requests are sent beyond the crawler
+// concurrency while the handler is blocked
+func TestHandlerBackpressure(t *testing.T) {
+	var sent int64
+	c := NewCrawler(WithHTTPClient(backpressureClient(&sent)), WithConcurrency(2))
+
+	unblock := make(chan struct{})
+	done := make(chan struct{})
+	handled := 0
+	var mu sync.Mutex
+	go func() {
+		c.GetMultiple(backpressureRequests(10), HandlerFunc(func(req *Request, res *Response, err error) {
+			<-unblock
+			mu.Lock()
+			handled++
+			mu.Unlock()
+		}))
+		close(done)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	if n := atomic.LoadInt64(&sent); n != 2 {
+		t.Errorf("Expected [2] requests to be sent while handler is blocked and not [%d]", n)
+	}
+
+	close(unblock)
+	<-done
+	if sent != 10 || handled != 10 {
+		t.Errorf("Expected all [10] requests to be sent and handled and not [%d] [%d]", sent, handled)
+	}
+}
+
+// TestAsyncHandler test async handler queue results, and apply overflow policy once the queue is full
+func TestAsyncHandler(t *testing.T) {
+	policies := map[OverflowPolicy]int64{OverflowBlock: 0, OverflowDropNewest: 7, OverflowDropOldest: 7}
+	for policy, dropped := range policies {
+		handled := []string{}
+		started := make(chan struct{}, 10)
+		unblock := make(chan struct{})
+
+		a := NewAsyncHandler(HandlerFunc(func(req *Request, res *Response, err error) {
+			started <- struct{}{}
+			<-unblock
+			handled = append(handled, req.Domain)
+		}), 2, 1, policy)
+		var onDrop int64
+		a.OnDrop = func(req *Request, res *Response, err error) { atomic.AddInt64(&onDrop, 1) }
+
+		// first result blocks the single worker, so 2 results are queued and the others overflow
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			close(unblock)
+		}()
+		for i, req := range backpressureRequests(10) {
+			a.Handle(req, &Response{Request: req}, nil)
+			if i == 0 {
+				<-started
+			}
+		}
+		a.Close()
+
+		if a.Dropped() != dropped || onDrop != dropped || int64(len(handled)) != 10-dropped {
+			t.Errorf("Expected [%d] dropped results of policy [%d] and not [%d] [%v] handled", dropped, policy, a.Dropped(), handled)
+		}
+		switch policy {
+		case OverflowDropNewest:
+			if strings.Join(handled, " ") != "example0.com example1.com example2.com" {
+				t.Errorf("Expected oldest results to be kept by [%d] policy and not %v", policy, handled)
+			}
+		case OverflowDropOldest:
+			if strings.Join(handled, " ") != "example0.com example8.com example9.com" {
+				t.Errorf("Expected newest results to be kept by [%d] policy and not %v", policy, handled)
+			}
+		}
+	}
+}
+
+// TestAsyncHandlerBoundedMemory test queued responses of bounded memory crawl keep their records after the crawler
+// released them
+func TestAsyncHandlerBoundedMemory(t *testing.T) {
+	var sent int64
+	handled := 0
+	a := NewAsyncHandler(HandlerFunc(func(req *Request, res *Response, err error) {
+		time.Sleep(time.Millisecond)
+		if err != nil || len(res.DataRecords) != 1 {
+			t.Errorf("Expected queued response of [%s] to keep its records [%v]", req.Domain, err)
+		}
+		handled++
+	}), 5, 1, OverflowBlock)
+
+	c := NewCrawler(WithHTTPClient(backpressureClient(&sent)), WithConcurrency(2), WithBoundedMemory(true))
+	c.GetMultiple(backpressureRequests(10), a)
+	a.Close()
+
+	if handled != 10 || a.Len() != 0 {
+		t.Errorf("Expected all [10] results to be handled and not [%d]", handled)
+	}
+}
@@ -0,0 +1,30 @@
+package adstxt
+
+// boundedHandler return handler that passes each response to h, and releases the response body and records once h
+// returns, when the crawler is set to bounded memory batch mode (see WithBoundedMemory). h receives a copy of the
+// response, so cached responses shared with other requests are not released
+func (c *Crawler) boundedHandler(h Handler) Handler {
+	if !c.boundedMemory || h == nil {
+		return h
+	}
+
+	return HandlerFunc(func(req *Request, res *Response, err error) {
+		if res == nil {
+			h.Handle(req, res, err)
+			return
+		}
+
+		r := *res
+		h.Handle(req, &r, err)
+		r.release()
+	})
+}
+
+// release drop the response raw body and parsed records, so response retained after it was handled does not hold
+// Ads.txt file content in memory. Records are replaced with empty records, and other response metadata is kept
+func (r *Response) release() {
+	r.RawBody = nil
+	if r.Records != nil {
+		r.Records = &Records{}
+	}
+}
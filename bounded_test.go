@@ -0,0 +1,101 @@
+package adstxt
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// newBoundedTestClient return HTTP client that serves single Ads.txt record for any URL
+func newBoundedTestClient() *http.Client {
+	return &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       io.NopCloser(strings.NewReader("greenadexchange.com,XF7342,DIRECT")),
+				Request:    req,
+			}, nil
+		}),
+	}
+}
+
+// TestWithBoundedMemory test batch crawl release response body and records once handled
+func TestWithBoundedMemory(t *testing.T) {
+	req1, _ := NewRequest("example.com")
+	req2, _ := NewRequest("google.com")
+
+	var mu sync.Mutex
+	retained := []*Response{}
+	handled := 0
+	c := NewCrawler(WithHTTPClient(newBoundedTestClient()), WithBoundedMemory(true))
+	c.GetMultiple([]*Request{req1, req2}, HandlerFunc(func(req *Request, res *Response, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if err != nil || len(res.DataRecords) != 1 || len(res.RawBody) == 0 {
+			t.Errorf("Expected handler to receive response records [%v]", err)
+		}
+		handled++
+		retained = append(retained, res)
+	}))
+
+	if handled != 2 {
+		t.Fatalf("Expected both requests to be handled and not [%d]", handled)
+	}
+	for _, res := range retained {
+		if res.Records == nil || len(res.DataRecords) != 0 || len(res.Body) != 0 || res.RawBody != nil {
+			t.Errorf("Expected retained response records to be released [%+v]", res.Records)
+		}
+		if res.StatusCode != http.StatusOK || len(res.SHA256) == 0 {
+			t.Errorf("Expected retained response metadata to be kept")
+		}
+	}
+
+	// results of GetMultipleChan are received after they were handled, and are not released
+	for r := range c.GetMultipleChan(context.Background(), []*Request{req1}) {
+		if r.Err != nil || len(r.Response.DataRecords) != 1 {
+			t.Errorf("Expected GetMultipleChan result records not to be released [%v]", r.Err)
+		}
+	}
+
+	// cached response shared by batch crawl results is not released
+	cache := NewLRUCache(10)
+	cc := NewCachingCrawler(c, cache)
+	cc.GetMultiple([]*Request{req1}, HandlerFunc(func(*Request, *Response, error) {}))
+	if cached, ok := cache.Get(req1.URL); !ok || len(cached.DataRecords) != 1 {
+		t.Errorf("Expected cached response records not to be released")
+	}
+}
+
+// TestSinkHandlerDropRawLines test SinkHandler pass response without raw body lines to the next handler once published
+func TestSinkHandlerDropRawLines(t *testing.T) {
+	req, _ := NewRequest("example.com")
+
+	var published *SinkMessage
+	var next *Response
+	h := NewSinkHandler(SinkFunc(func(ctx context.Context, msg *SinkMessage) error {
+		published = msg
+		return nil
+	}), HandlerFunc(func(req *Request, res *Response, err error) { next = res }))
+	h.DropRawLines = true
+
+	res, err := NewCrawler(WithHTTPClient(newBoundedTestClient())).Get(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.Handle(req, res, nil)
+
+	if published == nil || len(published.Response.Body) != 1 {
+		t.Fatalf("Expected published response to hold raw body lines")
+	}
+	if next == nil || next.Body != nil || next.RawBody != nil || len(next.DataRecords) != 1 {
+		t.Errorf("Expected next handler to receive response records without raw body lines")
+	}
+	if len(res.Body) != 1 || len(res.RawBody) == 0 {
+		t.Errorf("Expected original response not to be changed")
+	}
+}
@@ -0,0 +1,219 @@
+package adstxt
+
+import (
+	"encoding/csv"
+	"io"
+	"net"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// UnknownCountry country code of Ads.txt requests whose host country is unknown, for example requests that failed
+// before the host address was resolved (ISO 3166-1 user-assigned code)
+const UnknownCountry = "ZZ"
+
+// breakdownCSVHeader CSV columns of breakdown table (see WriteBreakdownCSV)
+var breakdownCSVHeader = []string{"key", "requests", "succeeded", "failed", "successRate", "dataRecords", "direct", "reseller"}
+
+// CountryLookup return the ISO 3166-1 alpha-2 country code of IP address, for example by lookup in GeoIP database
+type CountryLookup func(ip netip.Addr) (string, error)
+
+// BreakdownRow crawl outcomes and record counts of the Ads.txt requests of single TLD or country
+type BreakdownRow struct {
+	Key         string         `json:"key"`         // Key TLD or country code of the row
+	Requests    int            `json:"requests"`    // Requests number of handled Ads.txt requests
+	Succeeded   int            `json:"succeeded"`   // Succeeded Ads.txt requests
+	Failed      int            `json:"failed"`      // Failed Ads.txt requests
+	Errors      map[string]int `json:"errors"`      // Errors number of failed requests by error class (see StatusClass)
+	DataRecords int            `json:"dataRecords"` // DataRecords number of parsed data records
+	Direct      int            `json:"direct"`      // Direct number of DIRECT data records
+	Reseller    int            `json:"reseller"`    // Reseller number of RESELLER data records
+}
+
+// SuccessRate return the ratio of succeeded Ads.txt requests (zero if the row has no requests)
+func (r *BreakdownRow) SuccessRate() float64 {
+	if r.Requests == 0 {
+		return 0
+	}
+	return float64(r.Succeeded) / float64(r.Requests)
+}
+
+// add single Ads.txt request outcome to the row
+func (r *BreakdownRow) add(res *Response, err error) {
+	r.Requests++
+	if err != nil {
+		r.Failed++
+		r.Errors[StatusClass(res, err)]++
+		return
+	}
+
+	r.Succeeded++
+	if res == nil || res.Records == nil {
+		return
+	}
+	r.DataRecords += len(res.DataRecords)
+	for _, dr := range res.DataRecords {
+		switch dr.normalized().AccountType {
+		case accountTypeDirect:
+			r.Direct++
+		case accountTypeReseller:
+			r.Reseller++
+		}
+	}
+}
+
+// CrawlBreakdown aggregate crawl outcomes and record counts of Ads.txt requests by TLD (public suffix of the request
+// domain, e.g. "com" or "co.uk") and, if it has country lookup, by country of the Ads.txt host address, for summary
+// tables of transparency reports. CrawlBreakdown implements the Handler interface, and it is safe to use from multiple
+// goroutines
+type CrawlBreakdown struct {
+	TLDs      map[string]*BreakdownRow `json:"tlds"`                // TLDs breakdown by TLD of the request domain
+	Countries map[string]*BreakdownRow `json:"countries,omitempty"` // Countries breakdown by country of the Ads.txt host (if country lookup is set)
+
+	lookup CountryLookup
+	mu     sync.Mutex
+}
+
+// NewCrawlBreakdown create new CrawlBreakdown. Requests are bucketed by country of the Ads.txt host only if lookup is
+// not nil: the host address is the remote address of the final HTTP response, or the first address resolved by
+// DNSEnricher (see HostInfo)
+func NewCrawlBreakdown(lookup CountryLookup) *CrawlBreakdown {
+	b := &CrawlBreakdown{TLDs: map[string]*BreakdownRow{}, lookup: lookup}
+	if lookup != nil {
+		b.Countries = map[string]*BreakdownRow{}
+	}
+	return b
+}
+
+// Handle is the Handler interface implementation for CrawlBreakdown: add single Ads.txt request outcome to the TLD
+// and country rows of the request
+func (b *CrawlBreakdown) Handle(req *Request, res *Response, err error) {
+	tld := requestTLD(req)
+	country := ""
+	if b.lookup != nil {
+		country = b.country(res)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	breakdownRow(b.TLDs, tld).add(res, err)
+	if b.Countries != nil {
+		breakdownRow(b.Countries, country).add(res, err)
+	}
+}
+
+// TLDTable return the TLD rows, sorted by number of requests (most requests first) and by TLD
+func (b *CrawlBreakdown) TLDTable() []*BreakdownRow {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return breakdownTable(b.TLDs)
+}
+
+// CountryTable return the country rows, sorted by number of requests (most requests first) and by country code. The
+// table is empty if the breakdown has no country lookup
+func (b *CrawlBreakdown) CountryTable() []*BreakdownRow {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return breakdownTable(b.Countries)
+}
+
+// country return the country code of the Ads.txt host address of response, or UnknownCountry if the address or its
+// country is unknown
+func (b *CrawlBreakdown) country(res *Response) string {
+	ip, ok := hostAddr(res)
+	if !ok {
+		return UnknownCountry
+	}
+	code, err := b.lookup(ip)
+	if err != nil || len(code) == 0 {
+		return UnknownCountry
+	}
+	return strings.ToUpper(code)
+}
+
+// WriteBreakdownCSV write breakdown table rows (see CrawlBreakdown.TLDTable) to w as CSV, with a header line
+func WriteBreakdownCSV(w io.Writer, rows []*BreakdownRow) error {
+	cw := csv.NewWriter(w)
+	cw.Write(breakdownCSVHeader)
+	for _, r := range rows {
+		cw.Write([]string{
+			r.Key,
+			strconv.Itoa(r.Requests),
+			strconv.Itoa(r.Succeeded),
+			strconv.Itoa(r.Failed),
+			strconv.FormatFloat(r.SuccessRate(), 'f', 4, 64),
+			strconv.Itoa(r.DataRecords),
+			strconv.Itoa(r.Direct),
+			strconv.Itoa(r.Reseller),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// breakdownRow return the row of key, created if it does not exist
+func breakdownRow(rows map[string]*BreakdownRow, key string) *BreakdownRow {
+	r := rows[key]
+	if r == nil {
+		r = &BreakdownRow{Key: key, Errors: map[string]int{}}
+		rows[key] = r
+	}
+	return r
+}
+
+// breakdownTable return copy of the rows, sorted by number of requests and by key
+func breakdownTable(rows map[string]*BreakdownRow) []*BreakdownRow {
+	table := make([]*BreakdownRow, 0, len(rows))
+	for _, r := range rows {
+		row := *r
+		row.Errors = map[string]int{}
+		for class, n := range r.Errors {
+			row.Errors[class] = n
+		}
+		table = append(table, &row)
+	}
+	sort.Slice(table, func(i, j int) bool {
+		if table[i].Requests != table[j].Requests {
+			return table[i].Requests > table[j].Requests
+		}
+		return table[i].Key < table[j].Key
+	})
+	return table
+}
+
+// requestTLD return the public suffix of the request domain in its Unicode form (e.g. "co.uk")
+func requestTLD(req *Request) string {
+	domain := asciiHost(strings.ToLower(strings.TrimSuffix(req.Domain, ".")))
+	if len(domain) == 0 {
+		return ""
+	}
+	suffix, _ := publicsuffix.PublicSuffix(domain)
+	return unicodeHost(suffix)
+}
+
+// hostAddr return the Ads.txt host address of response: the remote address of the final HTTP response, or the first
+// address resolved by DNSEnricher
+func hostAddr(res *Response) (netip.Addr, bool) {
+	if res == nil {
+		return netip.Addr{}, false
+	}
+	if host, _, err := net.SplitHostPort(res.RemoteAddr); err == nil {
+		if ip, err := netip.ParseAddr(host); err == nil {
+			return ip.Unmap(), true
+		}
+	}
+	if info, ok := res.Metadata[MetadataDNS].(*HostInfo); ok {
+		for _, a := range append(append([]string{}, info.A...), info.AAAA...) {
+			if ip, err := netip.ParseAddr(a); err == nil {
+				return ip, true
+			}
+		}
+	}
+	return netip.Addr{}, false
+}
@@ -0,0 +1,73 @@
+package adstxt
+
+import (
+	"bytes"
+	"errors"
+	"net/netip"
+	"strings"
+	"testing"
+)
+
+// TestCrawlBreakdown test bucketing Ads.txt requests outcomes and records by TLD and country
+func TestCrawlBreakdown(t *testing.T) {
+	countries := map[string]string{"192.0.2.1": "us", "2001:db8::1": "DE"}
+	lookup := func(ip netip.Addr) (string, error) {
+		if c, ok := countries[ip.String()]; ok {
+			return c, nil
+		}
+		return "", errors.New("address not found")
+	}
+	b := NewCrawlBreakdown(lookup)
+
+	response := func(domain, remoteAddr string, body string) (*Request, *Response) {
+		req, _ := NewRequest(domain)
+		rec, err := ParseBody([]byte(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return req, &Response{Request: req, Records: rec, RemoteAddr: remoteAddr}
+	}
+
+	req, res := response("example.com", "192.0.2.1:443", "greenadexchange.com,XF7342,DIRECT\ngreenadexchange.com,XF7343,RESELLER")
+	b.Handle(req, res, nil)
+	req, res = response("example.co.uk", "[2001:db8::1]:443", "greenadexchange.com,XF7342,DIRECT")
+	b.Handle(req, res, nil)
+	req, res = response("other.com", "", "")
+	res.SetMetadata(MetadataDNS, &HostInfo{A: []string{"198.51.100.1"}, AAAA: []string{}})
+	b.Handle(req, res, nil)
+	req, _ = NewRequest("missing.com")
+	b.Handle(req, nil, ErrNotFound)
+
+	tlds := b.TLDTable()
+	if len(tlds) != 2 || tlds[0].Key != "com" || tlds[1].Key != "co.uk" {
+		t.Fatalf("Expected [com co.uk] TLD rows and not %+v", tlds)
+	}
+	if com := tlds[0]; com.Requests != 3 || com.Succeeded != 2 || com.Failed != 1 || com.DataRecords != 2 || com.Direct != 1 || com.Reseller != 1 {
+		t.Errorf("Expected [com] TLD row outcomes and records and not %+v", com)
+	}
+	if n := tlds[0].Errors["other"]; n != 1 {
+		t.Errorf("Expected [1] failed request by error class and not %v", tlds[0].Errors)
+	}
+
+	byCountry := map[string]*BreakdownRow{}
+	for _, r := range b.CountryTable() {
+		byCountry[r.Key] = r
+	}
+	if len(byCountry) != 3 || byCountry["US"] == nil || byCountry["DE"] == nil || byCountry[UnknownCountry].Requests != 2 {
+		t.Errorf("Expected [US DE %s] country rows and not %+v", UnknownCountry, byCountry)
+	}
+
+	// breakdown without country lookup
+	if rows := NewCrawlBreakdown(nil).CountryTable(); len(rows) != 0 {
+		t.Errorf("Expected no country rows without country lookup and not %+v", rows)
+	}
+
+	var csv bytes.Buffer
+	if err := WriteBreakdownCSV(&csv, tlds); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(csv.String()), "\n")
+	if len(lines) != 3 || lines[1] != "com,3,2,1,0.6667,2,1,1" {
+		t.Errorf("Expected CSV breakdown table and not [%s]", csv.String())
+	}
+}
@@ -0,0 +1,76 @@
+package adstxt
+
+import (
+	"context"
+	"net/http"
+	"net/http/cookiejar"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// Ads.txt fetch profiles (see WithFetchProfile)
+const (
+	// ProfileCrawler send crawler headers: crawler User-Agent and plain text Accept header (default)
+	ProfileCrawler FetchProfile = iota
+	// ProfileBrowser send realistic browser headers, and keep cookies set by remote host across the redirects of
+	// each Ads.txt request, so Ads.txt files of CDNs that block bare HTTP clients can be fetched
+	ProfileBrowser
+)
+
+// FetchProfile set the HTTP headers and cookie handling of Ads.txt requests
+type FetchProfile int
+
+// browser fetch profile headers
+const (
+	browserUserAgent      = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36"
+	browserAccept         = "text/html,application/xhtml+xml,application/xml;q=0.9,text/plain;q=0.8,*/*;q=0.7"
+	browserAcceptLanguage = "en-US,en;q=0.9"
+)
+
+// cookieJarContextKey context key of the cookie jar of Ads.txt request
+type cookieJarContextKey struct{}
+
+// withCookieJar return copy of ctx that holds new cookie jar for the Ads.txt request, when the crawler uses the
+// browser fetch profile. Each Ads.txt request has its own jar, so cookies are kept across its redirects and retries
+// but are not shared with other requests
+func (c *Crawler) withCookieJar(ctx context.Context) context.Context {
+	if c.profile != ProfileBrowser {
+		return ctx
+	}
+	jar, err := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
+	if err != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, cookieJarContextKey{}, jar)
+}
+
+// cookieJarFromContext return the cookie jar held by ctx, or nil if ctx does not hold cookie jar
+func cookieJarFromContext(ctx context.Context) http.CookieJar {
+	jar, _ := ctx.Value(cookieJarContextKey{}).(http.CookieJar)
+	return jar
+}
+
+// setProfileHeaders set the default headers of HTTP request according to the crawler fetch profile. Custom
+// User-Agent (see WithUserAgent) is used by both profiles
+func (c *Crawler) setProfileHeaders(r *http.Request) {
+	if c.profile != ProfileBrowser {
+		r.Header.Add("User-Agent", c.userAgent)
+		r.Header.Add("Accept", "text/plain")
+		r.Header.Add("Accept-Charset", "utf-8")
+		r.Header.Add("Content-Type", "text/plain; charset=utf-8")
+		return
+	}
+
+	ua := c.userAgent
+	if ua == userAgent {
+		ua = browserUserAgent
+	}
+	r.Header.Add("User-Agent", ua)
+	r.Header.Add("Accept", browserAccept)
+	r.Header.Add("Accept-Language", browserAcceptLanguage)
+	r.Header.Add("Upgrade-Insecure-Requests", "1")
+	r.Header.Add("Sec-Fetch-Dest", "document")
+	r.Header.Add("Sec-Fetch-Mode", "navigate")
+	r.Header.Add("Sec-Fetch-Site", "none")
+	r.Header.Add("Sec-Fetch-User", "?1")
+}
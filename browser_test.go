@@ -0,0 +1,75 @@
+package adstxt
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// newBrowserTestServer return test server that blocks non browser clients, and sets cookie and redirects to the same
+// URL before serving the Ads.txt file
+func newBrowserTestServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.UserAgent(), "Mozilla/") || len(r.Header.Get("Accept-Language")) == 0 {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if _, err := r.Cookie("session"); err != nil {
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "verified", Path: "/"})
+			http.Redirect(w, r, r.URL.Path, http.StatusFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, "greenadexchange.com,XF7342,DIRECT")
+	}))
+}
+
+// TestWithFetchProfile test browser fetch profile send browser headers and cookies set before redirect
+func TestWithFetchProfile(t *testing.T) {
+	ts := newBrowserTestServer()
+	defer ts.Close()
+
+	if _, err := NewCrawler().Get(&Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"}); err == nil {
+		t.Errorf("Expected request with crawler fetch profile to be blocked")
+	}
+
+	c := NewCrawler(WithFetchProfile(ProfileBrowser))
+	res, err := c.Get(&Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.DataRecords) != 1 || res.DataRecords[0].PublisherAccountID != "XF7342" {
+		t.Errorf("Expected single data record of Ads.txt file and not [%+v]", res.DataRecords)
+	}
+
+	// cookies are not shared between Ads.txt requests
+	u, _ := url.Parse(ts.URL + "/ads.txt")
+	jar := cookieJarFromContext(c.withCookieJar(t.Context()))
+	if jar == nil || len(jar.Cookies(u)) != 0 {
+		t.Errorf("Expected empty cookie jar for new Ads.txt request")
+	}
+}
+
+// TestBrowserUserAgent test browser fetch profile keep custom User-Agent
+func TestBrowserUserAgent(t *testing.T) {
+	var ua, accept string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ua, accept = r.UserAgent(), r.Header.Get("Accept")
+		io.WriteString(w, "greenadexchange.com,XF7342,DIRECT")
+	}))
+	defer ts.Close()
+
+	c := NewCrawler(WithFetchProfile(ProfileBrowser), WithUserAgent("Mozilla/5.0 (custom)"))
+	if _, err := c.Get(&Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"}); err != nil {
+		t.Fatal(err)
+	}
+	if ua != "Mozilla/5.0 (custom)" {
+		t.Errorf("Expected custom User-Agent and not [%s]", ua)
+	}
+	if accept != browserAccept {
+		t.Errorf("Expected browser Accept header and not [%s]", accept)
+	}
+}
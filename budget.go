@@ -0,0 +1,79 @@
+package adstxt
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// budget errors
+const (
+	errBudgetRequests = "%w: [%d] requests sent"
+	errBudgetBytes    = "%w: [%d] bytes downloaded"
+	errBudgetDuration = "%w: crawl duration [%s] elapsed"
+)
+
+// Budget resource limits of single batch crawl (see WithBudget). Zero limit is not enforced. Limits are soft: they
+// are checked before each request is sent, so requests that are in-flight when a limit is reached are completed, and
+// the crawl may exceed the bytes limit by the size of their Ads.txt files
+type Budget struct {
+	MaxRequests int           // MaxRequests maximum number of Ads.txt requests sent by the batch crawl
+	MaxBytes    int64         // MaxBytes maximum total size of Ads.txt files downloaded by the batch crawl
+	MaxDuration time.Duration // MaxDuration maximum wall-clock duration of the batch crawl
+}
+
+// budgetTracker track Budget spent by single batch crawl. nil budgetTracker does not enforce any limit
+type budgetTracker struct {
+	budget   Budget
+	start    time.Time
+	requests int   // number of sent requests
+	bytes    int64 // total size of downloaded Ads.txt files
+	mu       sync.Mutex
+}
+
+// newBudgetTracker return tracker of the crawler budget for new batch crawl, or nil if the crawler has no budget
+func (c *Crawler) newBudgetTracker() *budgetTracker {
+	if c.budget == (Budget{}) {
+		return nil
+	}
+	return &budgetTracker{budget: c.budget, start: time.Now()}
+}
+
+// spend budget of single request that is about to be sent, or return ErrBudgetExceeded error if any limit was reached
+func (b *budgetTracker) spend() error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case b.budget.MaxRequests > 0 && b.requests >= b.budget.MaxRequests:
+		return fmt.Errorf(errBudgetRequests, ErrBudgetExceeded, b.requests)
+	case b.budget.MaxBytes > 0 && b.bytes >= b.budget.MaxBytes:
+		return fmt.Errorf(errBudgetBytes, ErrBudgetExceeded, b.bytes)
+	case b.budget.MaxDuration > 0 && time.Since(b.start) >= b.budget.MaxDuration:
+		return fmt.Errorf(errBudgetDuration, ErrBudgetExceeded, b.budget.MaxDuration)
+	}
+	b.requests++
+	return nil
+}
+
+// track return get function that adds the size of each downloaded Ads.txt file to the spent budget
+func (b *budgetTracker) track(get func(context.Context, *Request) (*Response, error)) func(context.Context, *Request) (*Response, error) {
+	if b == nil {
+		return get
+	}
+
+	return func(ctx context.Context, req *Request) (*Response, error) {
+		res, err := get(ctx, req)
+		if res != nil {
+			b.mu.Lock()
+			b.bytes += res.ContentLength
+			b.mu.Unlock()
+		}
+		return res, err
+	}
+}
@@ -0,0 +1,86 @@
+package adstxt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestWithBudget test batch crawl stop once requests or bytes budget is exhausted, and report the remaining requests
+func TestWithBudget(t *testing.T) {
+	var sent atomic.Int32
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			sent.Add(1)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       io.NopCloser(strings.NewReader("greenadexchange.com,XF7342,DIRECT")),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	requests := func() []*Request {
+		req := []*Request{}
+		for i := 0; i < 5; i++ {
+			r, _ := NewRequest(fmt.Sprintf("example%d.com", i))
+			req = append(req, r)
+		}
+		return req
+	}
+
+	tests := map[string]Budget{
+		"requests": {MaxRequests: 2},
+		"bytes":    {MaxBytes: 40},
+	}
+	for name, budget := range tests {
+		sent.Store(0)
+		c := NewCrawler(WithHTTPClient(client), WithConcurrency(1), WithBudget(budget))
+		report := c.Crawl(context.Background(), requests(), HandlerFunc(func(req *Request, res *Response, err error) {
+			if err != nil && !errors.Is(err, ErrBudgetExceeded) {
+				t.Errorf("Expected budget exceeded error and not [%s]", err)
+			}
+		}))
+
+		if sent.Load() != 2 || report.Succeeded != 2 || report.Total != 2 {
+			t.Errorf("Expected 2 requests to be sent within %s budget and not [%d]", name, sent.Load())
+		}
+		if len(report.Remaining) != 3 || report.Remaining[0] != "http://example2.com/ads.txt" {
+			t.Errorf("Expected 3 remaining requests of %s budget and not %v", name, report.Remaining)
+		}
+	}
+
+	// each batch crawl has its own budget
+	c := NewCrawler(WithHTTPClient(client), WithConcurrency(1), WithBudget(Budget{MaxRequests: 5}))
+	for i := 0; i < 2; i++ {
+		if report := c.Crawl(context.Background(), requests(), nil); report.Succeeded != 5 {
+			t.Errorf("Expected all requests of batch crawl [%d] within budget and not [%d]", i, report.Succeeded)
+		}
+	}
+}
+
+// TestBudgetDuration test batch crawl stop once the duration budget is exhausted
+func TestBudgetDuration(t *testing.T) {
+	c := NewCrawler(WithBudget(Budget{MaxDuration: time.Millisecond}))
+	b := c.newBudgetTracker()
+	if err := b.spend(); err != nil {
+		t.Fatalf("Expected request within duration budget [%s]", err)
+	}
+
+	time.Sleep(2 * time.Millisecond)
+	if err := b.spend(); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("Expected budget exceeded error once duration elapsed and not [%v]", err)
+	}
+
+	// crawler without budget does not enforce any limit
+	if err := NewCrawler().newBudgetTracker().spend(); err != nil {
+		t.Errorf("Expected no budget limits [%s]", err)
+	}
+}
@@ -0,0 +1,224 @@
+package adstxt
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// Cache store Ads.txt responses by key. The key identifies a single Ads.txt file (the request Ads.txt URL).
+// Implementations must be safe to use from multiple goroutines
+type Cache interface {
+	Get(key string) (*Response, bool)
+	Set(key string, res *Response)
+}
+
+// CachingCrawler crawler that returns cached Ads.txt response as long as the response did not expire (based on
+// response Expires), and fetch Ads.txt file from remote host only when it is missing from cache or expired. Cached
+// responses are marked as cached, with their age and staleness (see Response.Cached)
+type CachingCrawler struct {
+	*Crawler
+	cache Cache
+
+	// StaleWhileRevalidate period after cached response expired in which the stale cached response is returned
+	// immediately, and the Ads.txt file is refreshed in the background (zero: expired response is refreshed before it
+	// is returned)
+	StaleWhileRevalidate time.Duration
+
+	refreshing map[string]bool // keys of cached responses refreshed in the background
+	refreshed  sync.WaitGroup  // background refreshes in progress
+	mu         sync.Mutex
+}
+
+// NewCachingCrawler create new caching crawler using the specified crawler to fetch Ads.txt files and cache to store
+// Ads.txt responses
+func NewCachingCrawler(c *Crawler, cache Cache) *CachingCrawler {
+	return &CachingCrawler{Crawler: c, cache: cache}
+}
+
+// Get return cached Ads.txt response, or crawl and parse Ads.txt file from remote host
+func (c *CachingCrawler) Get(req *Request) (*Response, error) {
+	return c.GetWithContext(context.Background(), req)
+}
+
+// GetWithContext return cached Ads.txt response, or crawl and parse Ads.txt file from remote host using the
+// provided context. Expired cached response is re-validated with conditional request: if the Ads.txt file did not
+// change, the cached records are returned as NotModified response with the new expiration date
+func (c *CachingCrawler) GetWithContext(ctx context.Context, req *Request) (*Response, error) {
+	// partial response of range request does not hold the whole Ads.txt file, and is not cached
+	if req.RangeStart > 0 {
+		return c.Crawler.GetWithContext(ctx, req)
+	}
+
+	// request URL is changed when following redirects: use the original URL as cache key
+	key := req.URL
+
+	cached, ok := c.cache.Get(key)
+	now := c.Crawler.now()
+	if ok && now.Before(cached.Expires) {
+		return cachedResponse(cached, req, now), nil
+	}
+	// stale response within the stale-while-revalidate period is returned, and refreshed in the background
+	if ok && c.StaleWhileRevalidate > 0 && now.Before(cached.Expires.Add(c.StaleWhileRevalidate)) {
+		c.revalidate(ctx, key, req, cached)
+		return cachedResponse(cached, req, now), nil
+	}
+
+	return c.fetch(ctx, key, req, cached, ok)
+}
+
+// cachedResponse return copy of cached response with the request, marked as cached response of age at now
+func cachedResponse(cached *Response, req *Request, now time.Time) *Response {
+	// cached response is returned with this request, so it holds the request metadata
+	hit := *cached
+	hit.Request = req
+	hit.setCached(now)
+	return &hit
+}
+
+// revalidate refresh stale cached response in the background, unless it is already refreshed. The refresh is not
+// canceled with ctx, since it outlives the request
+func (c *CachingCrawler) revalidate(ctx context.Context, key string, req *Request, cached *Response) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.refreshing == nil {
+		c.refreshing = map[string]bool{}
+	}
+	if c.refreshing[key] {
+		return
+	}
+	c.refreshing[key] = true
+
+	// request URL is changed when following redirects: refresh copy of the request
+	r := *req
+	c.refreshed.Add(1)
+	go func() {
+		defer c.refreshed.Done()
+		// failed request is logged by the crawler, and the stale response is kept in cache
+		c.fetch(context.WithoutCancel(ctx), key, &r, cached, true)
+
+		c.mu.Lock()
+		delete(c.refreshing, key)
+		c.mu.Unlock()
+	}()
+}
+
+// fetch crawl Ads.txt file of request that is missing from cache (ok is false) or expired, and cache the response
+func (c *CachingCrawler) fetch(ctx context.Context, key string, req *Request, cached *Response, ok bool) (*Response, error) {
+	// expired response: send conditional request, unless the caller already set the request validators
+	if ok && len(req.IfNoneMatch) == 0 && len(req.IfModifiedSince) == 0 {
+		req = cached.Conditional(req)
+	}
+
+	// partial response returned with error (see WithPartialBody) is not cached
+	res, err := c.Crawler.GetWithContext(ctx, req)
+	if err != nil {
+		return res, err
+	}
+
+	// Ads.txt file did not change: keep cached records with the new expiration date
+	if res.NotModified && ok {
+		refreshed := *cached
+		refreshed.Request = res.Request
+		refreshed.Expires = res.Expires
+		refreshed.ExpiresSource = res.ExpiresSource
+		refreshed.ExpiresHeader = res.ExpiresHeader
+		refreshed.Attempts = res.Attempts
+		refreshed.ETag = res.ETag
+		refreshed.LastModified = res.LastModified
+		refreshed.FinalURL = res.FinalURL
+		refreshed.StatusCode = res.StatusCode
+		refreshed.Header = res.Header
+		refreshed.Fetched = res.Fetched
+		refreshed.Duration = res.Duration
+		refreshed.Redirects = res.Redirects
+		refreshed.FetchedInsecurely = res.FetchedInsecurely
+		c.cache.Set(key, &refreshed)
+
+		notModified := refreshed
+		notModified.NotModified = true
+		return &notModified, nil
+	}
+
+	c.cache.Set(key, res)
+	return res, nil
+}
+
+// GetMultiple return cached Ads.txt responses, or crawl and parse multiple Ads.txt files from remote hosts
+func (c *CachingCrawler) GetMultiple(req []*Request, h Handler) {
+	c.GetMultipleWithContext(context.Background(), req, h)
+}
+
+// GetMultipleWithContext return cached Ads.txt responses, or crawl and parse multiple Ads.txt files from remote
+// hosts using the provided context
+func (c *CachingCrawler) GetMultipleWithContext(ctx context.Context, req []*Request, h Handler) {
+	ctx = withFlushers(ctx, h)
+	c.Crawler.getMultiple(ctx, req, c.Crawler.progressHandler(c.Crawler.boundedHandler(h), len(req)), c.GetWithContext)
+}
+
+// LRUCache in-memory Cache that holds up to a fixed number of Ads.txt responses, and evicts the least recently used
+// response when full
+type LRUCache struct {
+	size  int
+	items map[string]*list.Element
+	order *list.List // most recently used items are at the front of the list
+	mu    sync.Mutex
+}
+
+// lruItem single LRUCache entry
+type lruItem struct {
+	key string
+	res *Response
+}
+
+// NewLRUCache create new in-memory LRU cache that holds up to size Ads.txt responses
+func NewLRUCache(size int) *LRUCache {
+	return &LRUCache{
+		size:  size,
+		items: map[string]*list.Element{},
+		order: list.New(),
+	}
+}
+
+// Get return cached Ads.txt response
+func (c *LRUCache) Get(key string) (*Response, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	c.order.MoveToFront(e)
+	return e.Value.(*lruItem).res, true
+}
+
+// Set add Ads.txt response to cache, evicting least recently used response if cache is full
+func (c *LRUCache) Set(key string, res *Response) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.items[key]; ok {
+		e.Value.(*lruItem).res = res
+		c.order.MoveToFront(e)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&lruItem{key: key, res: res})
+
+	for c.size > 0 && c.order.Len() > c.size {
+		e := c.order.Back()
+		c.order.Remove(e)
+		delete(c.items, e.Value.(*lruItem).key)
+	}
+}
+
+// Len return number of cached Ads.txt responses
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
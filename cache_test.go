@@ -0,0 +1,183 @@
+package adstxt
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestLRUCache test LRU cache evicts least recently used Ads.txt response
+func TestLRUCache(t *testing.T) {
+	c := NewLRUCache(2)
+
+	c.Set("a", &Response{})
+	c.Set("b", &Response{})
+
+	// use "a" so "b" is the least recently used response
+	if _, ok := c.Get("a"); !ok {
+		t.Errorf("Expected [a] to be cached")
+	}
+
+	c.Set("c", &Response{})
+
+	if _, ok := c.Get("b"); ok {
+		t.Errorf("Expected [b] to be evicted from cache")
+	}
+	if _, ok := c.Get("a"); !ok {
+		t.Errorf("Expected [a] to be cached")
+	}
+	if _, ok := c.Get("c"); !ok {
+		t.Errorf("Expected [c] to be cached")
+	}
+	if c.Len() != 2 {
+		t.Errorf("Expected cache size to be [2] and not [%d]", c.Len())
+	}
+}
+
+// TestCachingCrawler test caching crawler returns cached response until it expires
+func TestCachingCrawler(t *testing.T) {
+	requests := 0
+	expires := time.Now().Add(time.Hour)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("Expires", expires.Format(http.TimeFormat))
+		io.WriteString(w, "greenadexchange.com,XF7342,DIRECT")
+	}))
+	defer ts.Close()
+
+	cache := NewLRUCache(10)
+	c := NewCachingCrawler(NewCrawler(), cache)
+
+	for i := 0; i < 3; i++ {
+		res, err := c.Get(&Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res.DataRecords) != 1 {
+			t.Errorf("Expected single DataRecord but found [%d]", len(res.DataRecords))
+		}
+	}
+
+	if requests != 1 {
+		t.Errorf("Expected single request to remote host and not [%d]", requests)
+	}
+
+	// expire cached response
+	res, _ := cache.Get(ts.URL + "/ads.txt")
+	res.Expires = time.Now().Add(-time.Minute)
+
+	c.GetMultiple([]*Request{&Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"}}, HandlerFunc(func(req *Request, res *Response, err error) {
+		if err != nil {
+			t.Error(err)
+		}
+	}))
+
+	if requests != 2 {
+		t.Errorf("Expected expired response to be fetched again from remote host")
+	}
+}
+
+// TestCachingCrawlerNotModified test expired cached response is re-validated with conditional request
+func TestCachingCrawlerNotModified(t *testing.T) {
+	const etag = `"v1"`
+
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("ETag", etag)
+		io.WriteString(w, "greenadexchange.com,XF7342,DIRECT")
+	}))
+	defer ts.Close()
+
+	cache := NewLRUCache(10)
+	c := NewCachingCrawler(NewCrawler(), cache)
+
+	if _, err := c.Get(&Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// expire cached response
+	cached, _ := cache.Get(ts.URL + "/ads.txt")
+	cached.Expires = time.Now().Add(-time.Minute)
+
+	res, err := c.Get(&Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests != 2 || !res.NotModified {
+		t.Errorf("Expected expired response to be re-validated with conditional request")
+	}
+	if len(res.DataRecords) != 1 || !time.Now().Before(res.Expires) {
+		t.Errorf("Expected not modified response to hold cached records with new expiration date")
+	}
+
+	cached, _ = cache.Get(ts.URL + "/ads.txt")
+	if cached.NotModified || !time.Now().Before(cached.Expires) {
+		t.Errorf("Expected cached response expiration date to be refreshed")
+	}
+}
+
+// TestCachingCrawlerStale test cached responses are marked with their age and staleness, and stale responses are
+// returned immediately and refreshed in the background within the stale-while-revalidate period
+func TestCachingCrawlerStale(t *testing.T) {
+	var mu sync.Mutex
+	requests := 0
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+		<-release
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("Cache-Control", "max-age=3600")
+		io.WriteString(w, "greenadexchange.com,XF7342,DIRECT\ngreenadexchange.com,185,RESELLER")
+	}))
+	defer ts.Close()
+
+	url := ts.URL + "/ads.txt"
+	fetched := time.Now().Add(-2 * time.Hour)
+	records, _ := ParseBody([]byte("greenadexchange.com,XF7342,DIRECT"))
+	cache := NewLRUCache(10)
+	cache.Set(url, &Response{Request: &Request{URL: url, Domain: "127.0.0.1"}, Records: records, Fetched: fetched,
+		Expires: time.Now().Add(-time.Hour)})
+
+	c := NewCachingCrawler(NewCrawler(), cache)
+	c.StaleWhileRevalidate = 2 * time.Hour
+	for i := 0; i < 2; i++ {
+		res, err := c.Get(&Request{URL: url, Domain: "127.0.0.1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res.Cached || !res.Stale || res.Age < 2*time.Hour || !res.Fetched.Equal(fetched) || len(res.DataRecords) != 1 {
+			t.Errorf("Expected stale cached response fetched [%s] and not [%v] %+v", fetched, res.Fetched, res)
+		}
+	}
+
+	// stale response is refreshed once in the background
+	close(release)
+	c.refreshed.Wait()
+	if requests != 1 {
+		t.Errorf("Expected stale response to be refreshed once and not [%d] times", requests)
+	}
+	res, err := c.Get(&Request{URL: url, Domain: "127.0.0.1"})
+	if err != nil || !res.Cached || res.Stale || res.Age > time.Minute || len(res.DataRecords) != 2 {
+		t.Errorf("Expected refreshed cached response and not %+v [%v]", res, err)
+	}
+
+	// expired response past the stale-while-revalidate period is refreshed before it is returned
+	cache.Set(url, &Response{Request: &Request{URL: url, Domain: "127.0.0.1"}, Records: records, Fetched: fetched,
+		Expires: time.Now().Add(-3 * time.Hour)})
+	if res, err = c.Get(&Request{URL: url, Domain: "127.0.0.1"}); err != nil || res.Cached || res.Stale || requests != 2 {
+		t.Errorf("Expected expired response to be refreshed and not %+v [%v]", res, err)
+	}
+}
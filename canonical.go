@@ -0,0 +1,243 @@
+package adstxt
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+)
+
+// CanonicalBytes return the canonical serialized form of Ads.txt records, used for hashing, storing and diffing
+// Ads.txt files. Data records and variables are normalized (trimmed, lower case domains and variable names, upper
+// case account type), deduped and sorted, and written one record per line: data records first, then variables.
+// Comments, warnings and the original ordering of the Ads.txt file are intentionally excluded, so two files that
+// declare the same records produce the same canonical bytes
+func (r *Records) CanonicalBytes() []byte {
+	var b bytes.Buffer
+	for _, l := range canonicalLines(r.DataRecords, r.Variables) {
+		b.WriteString(l)
+		b.WriteByte('\n')
+	}
+	return b.Bytes()
+}
+
+// Hash return hex encoded SHA-256 digest of the Ads.txt records canonical form (see CanonicalBytes)
+func (r *Records) Hash() string {
+	sum := sha256.Sum256(r.CanonicalBytes())
+	return hex.EncodeToString(sum[:])
+}
+
+// canonicalLines return sorted and deduped canonical lines of data records followed by variables
+func canonicalLines(records []*DataRecord, variables []*Variable) []string {
+	sortedSet := func(lines []string) []string {
+		sort.Strings(lines)
+		set := lines[:0]
+		for index, l := range lines {
+			if index > 0 && l == lines[index-1] {
+				continue
+			}
+			set = append(set, l)
+		}
+		return set
+	}
+
+	dr := make([]string, 0, len(records))
+	for _, r := range records {
+		dr = append(dr, r.canonical())
+	}
+
+	vr := make([]string, 0, len(variables))
+	for _, v := range variables {
+		vr = append(vr, v.canonical())
+	}
+
+	return append(sortedSet(dr), sortedSet(vr)...)
+}
+
+// Normalize normalize Ads.txt data records and variables in place: trim whitespaces, lower case advertising system
+// and variable domains, upper case account type. Return the number of data records and variables that were changed
+func (r *Records) Normalize() int {
+	changed := 0
+	for _, dr := range r.DataRecords {
+		if n := dr.normalized(); n.exact() != dr.exact() {
+			*dr = n
+			changed++
+		}
+	}
+
+	for _, v := range r.Variables {
+		if n := v.normalized(); n.exact() != v.exact() {
+			*v = n
+			changed++
+		}
+	}
+
+	return changed
+}
+
+// Sort sort Ads.txt data records and variables in place, in a documented stable order, so JSON and CSV exports of
+// the same records do not change when the Ads.txt file is reordered. Data records are sorted by advertising system
+// domain, publisher account ID and relationship (compared in normalized form, see Normalize), then by certification
+// authority ID and extension fields. Variables are sorted by type and value. Records that are equal in normalized
+// form keep their relative order. Records line index in the Ads.txt file is not changed, and WriteTo still writes
+// records in Ads.txt file order
+func (r *Records) Sort() {
+	type dataKey struct {
+		adSystem, account, relationship, canonical string
+		record                                     *DataRecord
+	}
+	dk := make([]dataKey, 0, len(r.DataRecords))
+	for _, dr := range r.DataRecords {
+		n := dr.normalized()
+		dk = append(dk, dataKey{n.AdverterDomain, n.PublisherAccountID, string(n.AccountType), n.canonical(), dr})
+	}
+	sort.SliceStable(dk, func(i, j int) bool {
+		a, b := dk[i], dk[j]
+		switch {
+		case a.adSystem != b.adSystem:
+			return a.adSystem < b.adSystem
+		case a.account != b.account:
+			return a.account < b.account
+		case a.relationship != b.relationship:
+			return a.relationship < b.relationship
+		}
+		return a.canonical < b.canonical
+	})
+	for i, k := range dk {
+		r.DataRecords[i] = k.record
+	}
+
+	type variableKey struct {
+		varType, value string
+		variable       *Variable
+	}
+	vk := make([]variableKey, 0, len(r.Variables))
+	for _, v := range r.Variables {
+		n := v.normalized()
+		vk = append(vk, variableKey{n.Type, n.Value, v})
+	}
+	sort.SliceStable(vk, func(i, j int) bool {
+		if vk[i].varType != vk[j].varType {
+			return vk[i].varType < vk[j].varType
+		}
+		return vk[i].value < vk[j].value
+	})
+	for i, k := range vk {
+		r.Variables[i] = k.variable
+	}
+}
+
+// Dedupe remove exact duplicates of Ads.txt data records and variables, keeping the first occurrence of each record.
+// Records that differ only by whitespaces or casing are not exact duplicates: use Normalize before Dedupe to remove
+// them as well. Return the number of data records and variables that were removed
+func (r *Records) Dedupe() int {
+	removed := 0
+
+	records := map[string]bool{}
+	dr := r.DataRecords[:0]
+	for _, d := range r.DataRecords {
+		if records[d.exact()] {
+			removed++
+			continue
+		}
+		records[d.exact()] = true
+		dr = append(dr, d)
+	}
+	r.DataRecords = dr
+
+	variables := map[string]bool{}
+	vr := r.Variables[:0]
+	for _, v := range r.Variables {
+		if variables[v.exact()] {
+			removed++
+			continue
+		}
+		variables[v.exact()] = true
+		vr = append(vr, v)
+	}
+	r.Variables = vr
+
+	return removed
+}
+
+// Equal check if data record declares the same record as other: advertising system domain is compared case
+// insensitive (internationalized domain by its punycode form), account type and certification authority ID are
+// compared case insensitive, and publisher account ID and extension fields are compared exactly. Whitespaces around
+// fields and comments are ignored
+func (r *DataRecord) Equal(other *DataRecord) bool {
+	if r == nil || other == nil {
+		return r == other
+	}
+	return r.identity() == other.identity()
+}
+
+// Key return stable hex encoded SHA-256 key of data record, equal for records that are Equal, so records can be used
+// as map keys for set operations and joins across Ads.txt files
+func (r *DataRecord) Key() string {
+	sum := sha256.Sum256([]byte(r.identity()))
+	return hex.EncodeToString(sum[:])
+}
+
+// identity return DataRecord canonical form with advertising system domain in its punycode form, to compare records
+func (r *DataRecord) identity() string {
+	n := r.normalized()
+	n.AdverterDomain = asciiHost(n.AdverterDomain)
+	return n.canonical()
+}
+
+// normalized return normalized copy of DataRecord: trimmed fields, lower case advertising system domain and
+// certification authority ID, upper case account type
+func (r DataRecord) normalized() DataRecord {
+	r.AdverterDomain = strings.ToLower(strings.TrimSpace(r.AdverterDomain))
+	r.PublisherAccountID = strings.TrimSpace(r.PublisherAccountID)
+	r.AccountType = r.AccountType.canonical()
+	r.CertAuthorityID = strings.ToLower(strings.TrimSpace(r.CertAuthorityID))
+	return r
+}
+
+// normalized return normalized copy of Variable: trimmed and lower case variable type, and lower case value for
+// variables that hold domain name
+func (v Variable) normalized() Variable {
+	v.Type = strings.ToLower(strings.TrimSpace(v.Type))
+	v.Value = strings.TrimSpace(v.Value)
+
+	switch v.Type {
+	case varTypeSubdomain, varTypeOwnerDomain, varTypeManagerDomain, varTypeInventoryPartnerDomain:
+		v.Value = strings.ToLower(v.Value)
+	}
+	return v
+}
+
+// canonical return DataRecord normalized single line form: <FIELD #1>,<FIELD #2>,<FIELD #3>[,<FIELD #4>][;<EXTENSION>]
+func (r *DataRecord) canonical() string {
+	n := r.normalized()
+	fields := []string{n.AdverterDomain, n.PublisherAccountID, string(n.AccountType)}
+
+	// certification authority ID is optional, unless the record has extension fields
+	if len(n.CertAuthorityID) > 0 || len(n.Extensions) > 0 {
+		fields = append(fields, n.CertAuthorityID)
+	}
+
+	line := strings.Join(fields, ",")
+	for _, ext := range n.Extensions {
+		line += extensionDenote + ext
+	}
+	return line
+}
+
+// exact return DataRecord fields as is (without comment), to compare records and identify exact duplicates
+func (r *DataRecord) exact() string {
+	return strings.Join([]string{r.AdverterDomain, r.PublisherAccountID, string(r.AccountType), r.CertAuthorityID, strings.Join(r.Extensions, extensionDenote)}, "\x00")
+}
+
+// canonical return Variable normalized single line form: <VARIABLE>=<VALUE>
+func (v *Variable) canonical() string {
+	n := v.normalized()
+	return n.Type + "=" + n.Value
+}
+
+// exact return Variable fields as is (without comments), to compare variables and identify exact duplicates
+func (v *Variable) exact() string {
+	return v.Type + "\x00" + v.Value
+}
@@ -0,0 +1,209 @@
+package adstxt
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestCanonicalBytes test Ads.txt records canonical form is normalized, sorted and deduped
+func TestCanonicalBytes(t *testing.T) {
+	b := []byte("# comment\nsubdomain=dev.example.com\nGreenAdExchange.com, XF7342, direct, 5JYXF8K54\ngreenadexchange.com,185,RESELLER\ngreenadexchange.com,XF7342,DIRECT,5jyxf8k54 # duplicate\nCONTACT=test@example.com")
+	res, err := ParseBody(b)
+	if err != nil {
+		t.Error(err)
+	}
+
+	const expected = "greenadexchange.com,185,RESELLER\ngreenadexchange.com,XF7342,DIRECT,5jyxf8k54\ncontact=test@example.com\nsubdomain=dev.example.com\n"
+	if string(res.CanonicalBytes()) != expected {
+		t.Errorf("Expected canonical form to be [%s] and not [%s]", expected, string(res.CanonicalBytes()))
+	}
+
+	// same records in different order and casing should produce the same canonical form
+	b = []byte("contact=test@example.com\ngreenadexchange.com,XF7342,DIRECT,5jyxf8k54\nsubdomain=dev.example.com\ngreenadexchange.com,185,reseller")
+	other, err := ParseBody(b)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if string(other.CanonicalBytes()) != expected {
+		t.Errorf("Expected canonical form to be [%s] and not [%s]", expected, string(other.CanonicalBytes()))
+	}
+}
+
+// TestHash test Ads.txt records hash is SHA-256 digest of the records canonical form
+func TestHash(t *testing.T) {
+	res, err := ParseBody([]byte("greenadexchange.com,XF7342,DIRECT\nsubdomain=dev.example.com"))
+	if err != nil {
+		t.Error(err)
+	}
+
+	sum := sha256.Sum256(res.CanonicalBytes())
+	if res.Hash() != hex.EncodeToString(sum[:]) {
+		t.Errorf("Expected records hash [%s] to be SHA-256 of canonical bytes [%s]", res.Hash(), hex.EncodeToString(sum[:]))
+	}
+
+	empty := &Records{}
+	if empty.Hash() == res.Hash() {
+		t.Errorf("Expected different records to have different hash [%s]", res.Hash())
+	}
+}
+
+// TestNormalize test normalizing Ads.txt records in place
+func TestNormalize(t *testing.T) {
+	r := &Records{
+		DataRecords: []*DataRecord{
+			&DataRecord{AdverterDomain: " GreenAdExchange.com", PublisherAccountID: "XF7342 ", AccountType: "direct"},
+			&DataRecord{AdverterDomain: "greenadexchange.com", PublisherAccountID: "185", AccountType: "RESELLER"},
+		},
+		Variables: []*Variable{
+			&Variable{Type: "subdomain", Value: "Dev.Example.com"},
+			&Variable{Type: "contact", Value: "Test@Example.com"},
+		},
+	}
+
+	if changed := r.Normalize(); changed != 2 {
+		t.Errorf("Expected [2] normalized records and not [%d]", changed)
+	}
+
+	expected := DataRecord{AdverterDomain: "greenadexchange.com", PublisherAccountID: "XF7342", AccountType: "DIRECT"}
+	if !reflect.DeepEqual(*r.DataRecords[0], expected) {
+		t.Errorf("Expected normalized DataRecord to be [%v] and not [%v]", expected, *r.DataRecords[0])
+	}
+	if r.Variables[0].Value != "dev.example.com" {
+		t.Errorf("Expected subdomain variable to be lower case and not [%s]", r.Variables[0].Value)
+	}
+	if r.Variables[1].Value != "Test@Example.com" {
+		t.Errorf("Expected contact variable to keep its casing and not [%s]", r.Variables[1].Value)
+	}
+}
+
+// TestDedupe test removing exact duplicates of Ads.txt records
+func TestDedupe(t *testing.T) {
+	res, err := ParseBody([]byte("greenadexchange.com,XF7342,DIRECT\ngreenadexchange.com,185,RESELLER\ngreenadexchange.com,XF7342,DIRECT\nGreenAdExchange.com,XF7342,DIRECT\nsubdomain=test.com\nsubdomain=test.com"))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if removed := res.Dedupe(); removed != 2 {
+		t.Errorf("Expected [2] removed duplicates and not [%d]", removed)
+	}
+	if len(res.DataRecords) != 3 || len(res.Variables) != 1 {
+		t.Errorf("Unexpected number of records after dedupe [%d] [%d]", len(res.DataRecords), len(res.Variables))
+	}
+
+	// after normalization, records that differ only by casing are duplicates
+	res.Normalize()
+	if removed := res.Dedupe(); removed != 1 {
+		t.Errorf("Expected [1] removed duplicate after normalization and not [%d]", removed)
+	}
+	if res.DataRecords[0].PublisherAccountID != "XF7342" || res.DataRecords[1].PublisherAccountID != "185" {
+		t.Errorf("Expected dedupe to keep first occurrence of each record")
+	}
+}
+
+// TestSort test sorting Ads.txt records in stable order, and deterministic JSON and CSV exports of reordered files
+func TestSort(t *testing.T) {
+	a, _ := ParseBody([]byte("contact=b@example.com\n" +
+		"google.com, pub-2, RESELLER\n" +
+		"GreenAdExchange.com, XF7342, reseller\n" +
+		"greenadexchange.com, XF7342, DIRECT, d75815a79\n" +
+		"contact=a@example.com\n" +
+		"google.com, pub-1, DIRECT\n"))
+	b, _ := ParseBody([]byte("google.com, pub-1, DIRECT\n" +
+		"contact=a@example.com\n" +
+		"greenadexchange.com, XF7342, DIRECT, d75815a79\n" +
+		"google.com, pub-2, RESELLER\n" +
+		"contact=b@example.com\n" +
+		"GreenAdExchange.com, XF7342, reseller\n"))
+	a.Sort()
+	b.Sort()
+
+	expected := []string{"google.com,pub-1,DIRECT", "google.com,pub-2,RESELLER", "greenadexchange.com,XF7342,DIRECT,d75815a79", "greenadexchange.com,XF7342,RESELLER"}
+	for i, dr := range a.DataRecords {
+		if c := dr.canonical(); c != expected[i] {
+			t.Errorf("Expected sorted record [%d] to be [%s] and not [%s]", i, expected[i], c)
+		}
+	}
+	if a.Variables[0].Value != "a@example.com" || a.Variables[1].Value != "b@example.com" {
+		t.Errorf("Expected variables to be sorted by type and value")
+	}
+	// line index of sorted records is kept
+	if a.Line(a.DataRecords[0]) != 6 {
+		t.Errorf("Expected sorted record to keep its line index and not [%d]", a.Line(a.DataRecords[0]))
+	}
+
+	var csvA, csvB bytes.Buffer
+	a.ToCSV(&csvA)
+	b.ToCSV(&csvB)
+	// CSV rows hold records line index: compare rows without it
+	stripLines := func(s string) []string {
+		rows := []string{}
+		for _, row := range strings.Split(strings.TrimSpace(s), "\n") {
+			rows = append(rows, row[strings.Index(row, ","):])
+		}
+		return rows
+	}
+	if !reflect.DeepEqual(stripLines(csvA.String()), stripLines(csvB.String())) {
+		t.Errorf("Expected CSV exports of reordered Ads.txt files to be the same [%s] [%s]", csvA.String(), csvB.String())
+	}
+
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       io.NopCloser(strings.NewReader("google.com, pub-2, RESELLER\ngoogle.com, pub-1, DIRECT")),
+				Request:    req,
+			}, nil
+		}),
+	}
+	req, _ := NewRequest("example.com")
+	res, err := NewCrawler(WithHTTPClient(client), WithSortedRecords(true)).Get(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.DataRecords[0].PublisherAccountID != "pub-1" {
+		t.Errorf("Expected crawler to sort parsed records")
+	}
+}
+
+// TestDataRecordEqual test data records equality and keys
+func TestDataRecordEqual(t *testing.T) {
+	record := &DataRecord{AdverterDomain: "greenadexchange.com", PublisherAccountID: "XF7342", AccountType: RelationshipDirect, CertAuthorityID: "d75815a79"}
+
+	tests := []struct {
+		other *DataRecord
+		equal bool
+	}{
+		{&DataRecord{AdverterDomain: " GreenAdExchange.com", PublisherAccountID: "XF7342 ", AccountType: "direct", CertAuthorityID: "D75815A79", Comment: "comment"}, true},
+		{&DataRecord{AdverterDomain: "greenadexchange.com", PublisherAccountID: "xf7342", AccountType: RelationshipDirect, CertAuthorityID: "d75815a79"}, false},
+		{&DataRecord{AdverterDomain: "greenadexchange.com", PublisherAccountID: "XF7342", AccountType: RelationshipReseller, CertAuthorityID: "d75815a79"}, false},
+		{&DataRecord{AdverterDomain: "greenadexchange.com", PublisherAccountID: "XF7342", AccountType: RelationshipDirect}, false},
+		{&DataRecord{AdverterDomain: "greenadexchange.com", PublisherAccountID: "XF7342", AccountType: RelationshipDirect, CertAuthorityID: "d75815a79", Extensions: []string{"ext"}}, false},
+		{nil, false},
+	}
+	for _, test := range tests {
+		if eq := record.Equal(test.other); eq != test.equal {
+			t.Errorf("Expected [%s] equal [%v] to be [%t]", record.canonical(), test.other, test.equal)
+		}
+		if test.other != nil && (record.Key() == test.other.Key()) != test.equal {
+			t.Errorf("Expected [%s] key to match [%v] key only when records are equal", record.canonical(), test.other)
+		}
+	}
+
+	// internationalized domain Unicode and punycode forms are equal
+	a := &DataRecord{AdverterDomain: "bücherexchange.com", PublisherAccountID: "1", AccountType: RelationshipDirect}
+	b := &DataRecord{AdverterDomain: "xn--bcherexchange-wob.com", PublisherAccountID: "1", AccountType: RelationshipDirect}
+	if !a.Equal(b) || a.Key() != b.Key() {
+		t.Errorf("Expected internationalized domain forms to be equal")
+	}
+	if len(record.Key()) != 64 {
+		t.Errorf("Expected hex encoded SHA-256 key and not [%s]", record.Key())
+	}
+}
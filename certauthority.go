@@ -0,0 +1,162 @@
+package adstxt
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// certification authority ID verification errors, use errors.Is to check the reason of ValidationError
+var (
+	// ErrMissingCertAuthorityID data record of advertising system that requires certification authority ID has no ID
+	ErrMissingCertAuthorityID = errors.New("missing certification authority ID")
+	// ErrUnregisteredCertAuthorityID certification authority ID is not registered in TAG registry
+	ErrUnregisteredCertAuthorityID = errors.New("unregistered certification authority ID")
+	// ErrCertAuthorityIDMismatch certification authority ID is registered to a different advertising system
+	ErrCertAuthorityIDMismatch = errors.New("certification authority ID registered to different advertising system")
+)
+
+// TAG registry snapshot CSV columns
+const (
+	tagColumnID      = "tag-id"
+	tagColumnCompany = "company"
+	tagColumnDomain  = "domain"
+)
+
+// TAGRegistration single TAG (Trustworthy Accountability Group) registry entry
+type TAGRegistration struct {
+	ID      string `json:"id"`               // ID TAG-ID certification authority ID
+	Company string `json:"company"`          // Company name of the registered company
+	Domain  string `json:"domain,omitempty"` // Domain advertising system domain of the registered company (optional)
+}
+
+// TAGRegistry lookup TAG-ID registrations. Implementations may query TAG registry service, or use local registry
+// snapshot (see TAGSnapshot)
+type TAGRegistry interface {
+	// LookupTAGID return the registration of certification authority ID, or nil if the ID is not registered
+	LookupTAGID(id string) (*TAGRegistration, error)
+}
+
+// TAGSnapshot TAGRegistry of TAG registry snapshot, by lower case TAG-ID
+type TAGSnapshot map[string]*TAGRegistration
+
+// LookupTAGID is the TAGRegistry interface implementation for TAGSnapshot
+func (s TAGSnapshot) LookupTAGID(id string) (*TAGRegistration, error) {
+	return s[strings.ToLower(strings.TrimSpace(id))], nil
+}
+
+// LoadTAGSnapshot load TAG registry snapshot from CSV with header line, that includes "TAG-ID" and "Company" columns
+// and optional "Domain" column (column names are case insensitive)
+func LoadTAGSnapshot(r io.Reader) (TAGSnapshot, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+	columns := map[string]int{}
+	for i, h := range header {
+		columns[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	for _, c := range []string{tagColumnID, tagColumnCompany} {
+		if _, ok := columns[c]; !ok {
+			return nil, fmt.Errorf("TAG registry snapshot column [%s] not found in header %v", c, header)
+		}
+	}
+
+	field := func(record []string, column string) string {
+		if i, ok := columns[column]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	s := TAGSnapshot{}
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return s, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		reg := &TAGRegistration{ID: field(record, tagColumnID), Company: field(record, tagColumnCompany), Domain: field(record, tagColumnDomain)}
+		if len(reg.ID) > 0 {
+			s[strings.ToLower(reg.ID)] = reg
+		}
+	}
+}
+
+// CertAuthorityVerifier verify certification authority ID (field #4) of Ads.txt data records: TAG-ID format, and
+// with a registry, that the ID is registered to the advertising system. Advertising systems may be set to require
+// certification authority ID
+type CertAuthorityVerifier struct {
+	Registry TAGRegistry     // Registry TAG registry lookup (only TAG-ID format is verified if nil)
+	Required map[string]bool // Required lower case domains of advertising systems that require certification authority ID
+}
+
+// NewCertAuthorityVerifier create new certification authority ID verifier with TAG registry (may be nil), and the
+// domains of advertising systems that require certification authority ID
+func NewCertAuthorityVerifier(registry TAGRegistry, required ...string) *CertAuthorityVerifier {
+	v := &CertAuthorityVerifier{Registry: registry, Required: map[string]bool{}}
+	for _, d := range required {
+		v.Required[asciiHost(strings.ToLower(strings.TrimSpace(d)))] = true
+	}
+	return v
+}
+
+// Verify check certification authority ID of all Ads.txt data records. Return nil if all IDs are valid, or
+// ValidationErrors with all missing or invalid IDs (see ErrMissingCertAuthorityID, ErrInvalidCertAuthorityID,
+// ErrUnregisteredCertAuthorityID and ErrCertAuthorityIDMismatch). Registry lookup error is returned as is
+func (v *CertAuthorityVerifier) Verify(records *Records) error {
+	var errs ValidationErrors
+	for _, r := range records.DataRecords {
+		err, lookupErr := v.verify(r)
+		if lookupErr != nil {
+			return lookupErr
+		}
+		if err != nil {
+			errs = append(errs, &ValidationError{Line: records.Line(r), Record: r.canonical(), Field: "CertAuthorityID", Value: r.CertAuthorityID, Err: err})
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// verify check certification authority ID of single data record, return the verification failure reason and
+// registry lookup error
+func (v *CertAuthorityVerifier) verify(r *DataRecord) (reason error, err error) {
+	id := strings.TrimSpace(r.CertAuthorityID)
+	if len(id) == 0 {
+		if v.Required[asciiHost(strings.ToLower(strings.TrimSpace(r.AdverterDomain)))] {
+			return ErrMissingCertAuthorityID, nil
+		}
+		return nil, nil
+	}
+
+	if !tagIDRe.MatchString(id) {
+		return ErrInvalidCertAuthorityID, nil
+	}
+	if v.Registry == nil {
+		return nil, nil
+	}
+
+	reg, err := v.Registry.LookupTAGID(id)
+	if err != nil {
+		return nil, err
+	}
+	if reg == nil {
+		return ErrUnregisteredCertAuthorityID, nil
+	}
+	if len(reg.Domain) > 0 && !sameDomain(reg.Domain, r.AdverterDomain) {
+		return ErrCertAuthorityIDMismatch, nil
+	}
+	return nil, nil
+}
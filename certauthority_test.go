@@ -0,0 +1,82 @@
+package adstxt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// lookupFunc TAGRegistry function, for testing registry lookup failures
+type lookupFunc func(id string) (*TAGRegistration, error)
+
+func (f lookupFunc) LookupTAGID(id string) (*TAGRegistration, error) {
+	return f(id)
+}
+
+// TestLoadTAGSnapshot test loading TAG registry snapshot from CSV
+func TestLoadTAGSnapshot(t *testing.T) {
+	s, err := LoadTAGSnapshot(strings.NewReader("Company,TAG-ID,Domain\nGoogle,F08C47FEC0942FA0,google.com\nOpenX,6a698e2ec38604c6,\n,,\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s) != 2 {
+		t.Fatalf("Expected [2] registrations and not [%d]", len(s))
+	}
+	if reg, _ := s.LookupTAGID("f08c47fec0942fa0"); reg == nil || reg.Company != "Google" || reg.Domain != "google.com" {
+		t.Errorf("Expected TAG-ID lookup to be case insensitive")
+	}
+
+	if _, err := LoadTAGSnapshot(strings.NewReader("company,domain\n")); err == nil {
+		t.Errorf("Expected snapshot without TAG-ID column to fail")
+	}
+}
+
+// TestCertAuthorityVerifier test verifying data records certification authority ID
+func TestCertAuthorityVerifier(t *testing.T) {
+	records, _ := ParseBody([]byte(`google.com, pub-1, DIRECT, f08c47fec0942fa0
+google.com, pub-2, DIRECT
+openx.com, 540, RESELLER, f08c47fec0942fa0
+openx.com, 541, RESELLER, 1234567890abcdef
+openx.com, 542, RESELLER, not-a-tag-id
+greenadexchange.com, XF7342, DIRECT`))
+
+	registry := TAGSnapshot{
+		"f08c47fec0942fa0": {ID: "f08c47fec0942fa0", Company: "Google", Domain: "google.com"},
+	}
+
+	err := NewCertAuthorityVerifier(registry, "Google.com").Verify(records)
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Expected ValidationErrors and not [%v]", err)
+	}
+
+	expected := []struct {
+		line int
+		err  error
+	}{
+		{2, ErrMissingCertAuthorityID},
+		{3, ErrCertAuthorityIDMismatch},
+		{4, ErrUnregisteredCertAuthorityID},
+		{5, ErrInvalidCertAuthorityID},
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("Expected [%d] verification errors and not [%d]: %v", len(expected), len(errs), errs)
+	}
+	for i, e := range expected {
+		if errs[i].Line != e.line || !errors.Is(errs[i], e.err) {
+			t.Errorf("Expected [%s] at line [%d] and not [%s] at line [%d]", e.err, e.line, errs[i].Err, errs[i].Line)
+		}
+	}
+
+	// without registry only TAG-ID format is verified
+	err = NewCertAuthorityVerifier(nil).Verify(records)
+	if !errors.As(err, &errs) || len(errs) != 1 || !errors.Is(errs[0], ErrInvalidCertAuthorityID) {
+		t.Errorf("Expected only invalid TAG-ID format error and not [%v]", err)
+	}
+
+	// registry lookup error is returned as is
+	lookupErr := errors.New("registry unavailable")
+	if err := NewCertAuthorityVerifier(lookupFunc(func(string) (*TAGRegistration, error) { return nil, lookupErr })).Verify(records); err != lookupErr {
+		t.Errorf("Expected registry lookup error and not [%v]", err)
+	}
+}
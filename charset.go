@@ -0,0 +1,86 @@
+package adstxt
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// charset transcoding errors
+const (
+	errUnsupportedCharset = "Ads.txt file charset [%s] is not supported, file is parsed as UTF-8"
+)
+
+// byte order marks of supported Unicode encodings
+var (
+	bomUTF8    = []byte{0xef, 0xbb, 0xbf}
+	bomUTF16LE = []byte{0xff, 0xfe}
+	bomUTF16BE = []byte{0xfe, 0xff}
+)
+
+// windows1252 Unicode code points of windows-1252 bytes 0x80-0x9f (zero for undefined bytes, that are mapped as in
+// Latin-1). Other bytes are mapped as in Latin-1
+var windows1252 = [32]rune{
+	0x20ac, 0, 0x201a, 0x0192, 0x201e, 0x2026, 0x2020, 0x2021, 0x02c6, 0x2030, 0x0160, 0x2039, 0x0152, 0, 0x017d, 0,
+	0, 0x2018, 0x2019, 0x201c, 0x201d, 0x2022, 0x2013, 0x2014, 0x02dc, 0x2122, 0x0161, 0x203a, 0x0153, 0, 0x017e, 0x0178,
+}
+
+// toUTF8 transcode Ads.txt file body to UTF-8, based on body byte order mark (which takes precedence) or the charset
+// parameter of the response Content-Type. Supported charsets are UTF-8, UTF-16 (LE and BE), ISO-8859-1 (Latin-1) and
+// windows-1252. Body with unsupported charset is returned as is with a warning
+func toUTF8(body []byte, contentType string) ([]byte, *Warning) {
+	switch {
+	case bytes.HasPrefix(body, bomUTF8):
+		return body[len(bomUTF8):], nil
+	case bytes.HasPrefix(body, bomUTF16LE):
+		return decodeUTF16(body[len(bomUTF16LE):], false), nil
+	case bytes.HasPrefix(body, bomUTF16BE):
+		return decodeUTF16(body[len(bomUTF16BE):], true), nil
+	}
+
+	_, params, _ := mime.ParseMediaType(contentType)
+	charset := strings.ToLower(strings.TrimSpace(params["charset"]))
+	switch charset {
+	case "", "utf-8", "utf8", "us-ascii", "ascii":
+		return body, nil
+	case "utf-16", "utf-16le":
+		// UTF-16 without byte order mark is usually little endian (files saved by Windows tools)
+		return decodeUTF16(body, false), nil
+	case "utf-16be":
+		return decodeUTF16(body, true), nil
+	case "iso-8859-1", "iso8859-1", "latin1", "latin-1", "l1":
+		return decodeSingleByte(body, false), nil
+	case "windows-1252", "cp1252":
+		return decodeSingleByte(body, true), nil
+	}
+	return body, &Warning{Level: LowSeverity, Message: fmt.Sprintf(errUnsupportedCharset, charset)}
+}
+
+// decodeUTF16 decode UTF-16 body (trailing odd byte is ignored) to UTF-8
+func decodeUTF16(body []byte, bigEndian bool) []byte {
+	units := make([]uint16, len(body)/2)
+	for i := range units {
+		if bigEndian {
+			units[i] = uint16(body[2*i])<<8 | uint16(body[2*i+1])
+		} else {
+			units[i] = uint16(body[2*i+1])<<8 | uint16(body[2*i])
+		}
+	}
+	return []byte(string(utf16.Decode(units)))
+}
+
+// decodeSingleByte decode Latin-1 or windows-1252 body to UTF-8
+func decodeSingleByte(body []byte, cp1252 bool) []byte {
+	b := make([]byte, 0, len(body))
+	for _, c := range body {
+		r := rune(c)
+		if cp1252 && c >= 0x80 && c <= 0x9f && windows1252[c-0x80] != 0 {
+			r = windows1252[c-0x80]
+		}
+		b = utf8.AppendRune(b, r)
+	}
+	return b
+}
@@ -0,0 +1,113 @@
+package adstxt
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+	"unicode/utf16"
+)
+
+// utf16Body encode s as UTF-16 with optional byte order mark
+func utf16Body(s string, bigEndian bool, bom bool) []byte {
+	b := []byte{}
+	if bom {
+		if bigEndian {
+			b = append(b, bomUTF16BE...)
+		} else {
+			b = append(b, bomUTF16LE...)
+		}
+	}
+	for _, u := range utf16.Encode([]rune(s)) {
+		if bigEndian {
+			b = append(b, byte(u>>8), byte(u))
+		} else {
+			b = append(b, byte(u), byte(u>>8))
+		}
+	}
+	return b
+}
+
+// TestToUTF8 test transcoding Ads.txt file body to UTF-8 based on byte order mark and Content-Type charset
+func TestToUTF8(t *testing.T) {
+	txt := "greenadexchange.com,XF7342,DIRECT # café"
+	tests := []struct {
+		name        string
+		body        []byte
+		contentType string
+	}{
+		{"utf-8", []byte(txt), "text/plain"},
+		{"utf-8 bom", append(append([]byte{}, bomUTF8...), txt...), "text/plain"},
+		{"utf-16le bom", utf16Body(txt, false, true), "text/plain"},
+		{"utf-16be bom", utf16Body(txt, true, true), "text/plain; charset=utf-8"},
+		{"utf-16 charset", utf16Body(txt, false, false), "text/plain; charset=UTF-16"},
+		{"utf-16be charset", utf16Body(txt, true, false), "text/plain; charset=utf-16be"},
+		{"latin-1", []byte("greenadexchange.com,XF7342,DIRECT # caf\xe9"), "text/plain; charset=ISO-8859-1"},
+		{"windows-1252", []byte("greenadexchange.com,XF7342,DIRECT # caf\xe9"), "text/plain; charset=windows-1252"},
+	}
+
+	for _, test := range tests {
+		b, w := toUTF8(test.body, test.contentType)
+		if w != nil {
+			t.Errorf("Expected [%s] body to be transcoded without warning [%s]", test.name, w.Message)
+		}
+		if string(b) != txt {
+			t.Errorf("Expected [%s] body to be transcoded to [%s] and not [%s]", test.name, txt, b)
+		}
+	}
+
+	if b, _ := toUTF8([]byte("\x80 \x93quoted\x94"), "text/plain; charset=cp1252"); string(b) != "€ “quoted”" {
+		t.Errorf("Expected windows-1252 characters to be transcoded and not [%s]", b)
+	}
+
+	b, w := toUTF8([]byte(txt), "text/plain; charset=koi8-r")
+	if w == nil || w.Level != LowSeverity || string(b) != txt {
+		t.Errorf("Expected unsupported charset body to be parsed as is with low severity warning")
+	}
+}
+
+// TestGetUTF16 test crawling UTF-16 Ads.txt file
+func TestGetUTF16(t *testing.T) {
+	body := utf16Body("greenadexchange.com,XF7342,DIRECT\r\ncontact=adops@example.com\r\n", false, true)
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       io.NopCloser(bytes.NewReader(body)),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	req, _ := NewRequest("example.com")
+	res, err := NewCrawler(WithHTTPClient(client)).Get(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.DataRecords) != 1 || res.DataRecords[0].PublisherAccountID != "XF7342" {
+		t.Errorf("Expected UTF-16 Ads.txt data record to be parsed")
+	}
+	if len(res.Contacts) != 1 || res.Contacts[0] != "adops@example.com" {
+		t.Errorf("Expected UTF-16 Ads.txt variable to be parsed")
+	}
+	if len(res.Warnings) != 0 {
+		t.Errorf("Expected UTF-16 Ads.txt to be parsed without warnings and not [%d]", len(res.Warnings))
+	}
+	if string(res.RawBody) != string(body) {
+		t.Errorf("Expected raw body to hold the Ads.txt file as received from remote host")
+	}
+}
+
+// TestParseBodyBOM test parsing local Ads.txt file with byte order mark
+func TestParseBodyBOM(t *testing.T) {
+	for _, body := range [][]byte{append(append([]byte{}, bomUTF8...), "greenadexchange.com,XF7342,DIRECT"...), utf16Body("greenadexchange.com,XF7342,DIRECT", true, true)} {
+		records, err := ParseBody(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records.DataRecords) != 1 || records.DataRecords[0].AdverterDomain != "greenadexchange.com" {
+			t.Errorf("Expected Ads.txt file with byte order mark to be parsed [%q]", body)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package adstxt
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// checkpointInterval number of completed requests recorded between checkpoint writer flushes
+const checkpointInterval = 100
+
+// Checkpoint record the outcome of each completed request of batch crawl to a writer, so a crawl that was interrupted
+// (crashed or stopped) can be resumed without crawling already completed Ads.txt files again (see Crawler.Resume).
+// Entries are written as JSON lines, and flushed to the writer every 100 completed requests and by Flush. Checkpoint
+// is safe to use from multiple goroutines
+type Checkpoint struct {
+	w         *bufio.Writer
+	completed map[string]*CheckpointEntry // completed requests by Ads.txt URL
+	keys      map[*Request]string         // original Ads.txt URL of pending requests (request URL is changed by redirects)
+	pending   int                         // number of entries written since the last flush
+	mu        sync.Mutex
+}
+
+// CheckpointEntry outcome of single completed request
+type CheckpointEntry struct {
+	URL     string    `json:"url"`             // URL Ads.txt URL of the request (before redirects)
+	Domain  string    `json:"domain"`          // Domain root domain of the request
+	Outcome string    `json:"outcome"`         // Outcome request status class (see StatusClass)
+	Error   string    `json:"error,omitempty"` // Error request error message, empty if the request succeeded
+	Time    time.Time `json:"time"`            // Time the request was completed
+}
+
+// NewCheckpoint create new Checkpoint that records completed requests to w (for example checkpoint file opened in
+// append mode)
+func NewCheckpoint(w io.Writer) *Checkpoint {
+	return &Checkpoint{w: bufio.NewWriter(w), completed: map[string]*CheckpointEntry{}, keys: map[*Request]string{}}
+}
+
+// Load read completed requests recorded by previous crawl from r. Truncated last line (written by crawl that crashed
+// while writing it) is ignored
+func (cp *Checkpoint) Load(r io.Reader) error {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	dec := json.NewDecoder(r)
+	for {
+		e := &CheckpointEntry{}
+		if err := dec.Decode(e); err != nil {
+			if err == io.EOF || errors.Is(err, io.ErrUnexpectedEOF) {
+				return nil
+			}
+			return err
+		}
+		cp.completed[e.URL] = e
+	}
+}
+
+// Completed return the recorded outcome of Ads.txt request, or nil if the request was not completed
+func (cp *Checkpoint) Completed(req *Request) *CheckpointEntry {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	return cp.completed[req.URL]
+}
+
+// Pending return the requests that were not completed yet, in order of requests
+func (cp *Checkpoint) Pending(req []*Request) []*Request {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	pending := []*Request{}
+	for _, r := range req {
+		if _, ok := cp.completed[r.URL]; ok {
+			continue
+		}
+		cp.keys[r] = r.URL
+		pending = append(pending, r)
+	}
+	return pending
+}
+
+// Handler return Handler that records the outcome of each request and then passes the result to h (h may be nil).
+// Requests aborted since the crawl context is done, or not sent since the crawl budget was exhausted (see WithBudget),
+// are not recorded, so they are crawled again on resume
+func (cp *Checkpoint) Handler(h Handler) Handler {
+	return HandlerFunc(func(req *Request, res *Response, err error) {
+		if !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, ErrBudgetExceeded) {
+			cp.record(req, res, err)
+		}
+		if h != nil {
+			h.Handle(req, res, err)
+		}
+	})
+}
+
+// Flush write all recorded entries to the underlying writer
+func (cp *Checkpoint) Flush() error {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	cp.pending = 0
+	return cp.w.Flush()
+}
+
+// record add completed request entry, and flush entries to the writer every checkpoint interval
+func (cp *Checkpoint) record(req *Request, res *Response, err error) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	url, ok := cp.keys[req]
+	if !ok {
+		url = req.URL
+	}
+	delete(cp.keys, req)
+
+	e := &CheckpointEntry{URL: url, Domain: req.Domain, Outcome: StatusClass(res, err), Time: time.Now().UTC()}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	cp.completed[url] = e
+
+	// checkpoint write errors are reported by Flush
+	b, _ := json.Marshal(e)
+	cp.w.Write(append(b, '\n'))
+	cp.pending++
+	if cp.pending >= checkpointInterval {
+		cp.pending = 0
+		cp.w.Flush()
+	}
+}
+
+// Resume crawl and parse multiple Ads.txt files from remote hosts (see GetMultipleWithContext), skipping requests
+// that were already completed according to checkpoint cp, and record the outcome of each crawled request to cp. Return
+// the checkpoint flush error, if any
+func (c *Crawler) Resume(ctx context.Context, req []*Request, cp *Checkpoint, h Handler) error {
+	c.GetMultipleWithContext(withFlushers(ctx, cp, h), cp.Pending(req), cp.Handler(h))
+	return cp.Flush()
+}
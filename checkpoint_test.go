@@ -0,0 +1,97 @@
+package adstxt
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestCheckpointResume test resuming batch crawl that was interrupted, based on checkpoint of completed requests
+func TestCheckpointResume(t *testing.T) {
+	var mu sync.Mutex
+	crawled := map[string]int{}
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if err := req.Context().Err(); err != nil {
+				return nil, err
+			}
+			mu.Lock()
+			crawled[req.URL.Host]++
+			mu.Unlock()
+
+			switch req.URL.Host {
+			case "missing.com":
+				return &http.Response{StatusCode: http.StatusNotFound, Status: "404 Not Found", Body: http.NoBody, Request: req}, nil
+			case "redirect.com":
+				return &http.Response{StatusCode: http.StatusMovedPermanently, Header: http.Header{"Location": []string{"http://www.redirect.com/ads.txt"}}, Body: http.NoBody, Request: req}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       io.NopCloser(strings.NewReader("greenadexchange.com,XF7342,DIRECT")),
+				Request:    req,
+			}, nil
+		}),
+	}
+	c := NewCrawler(WithHTTPClient(client))
+
+	requests := func(domains ...string) []*Request {
+		req := []*Request{}
+		for _, d := range domains {
+			r, _ := NewRequest(d)
+			req = append(req, r)
+		}
+		return req
+	}
+
+	// first crawl completed part of the requests
+	var file bytes.Buffer
+	cp := NewCheckpoint(&file)
+	if err := c.Resume(context.Background(), requests("example.com", "missing.com", "redirect.com"), cp, nil); err != nil {
+		t.Fatal(err)
+	}
+	if e := cp.Completed(requests("missing.com")[0]); e == nil || e.Outcome != "4xx" || len(e.Error) == 0 {
+		t.Errorf("Expected failed request outcome to be recorded and not [%+v]", e)
+	}
+
+	// requests aborted by crawl shutdown are not recorded
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := c.Resume(ctx, requests("canceled.com"), cp, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// resumed crawl, with checkpoint loaded from file (last line was truncated by crash)
+	file.WriteString(`{"url":"http://trunc`)
+	resumed := NewCheckpoint(io.Discard)
+	if err := resumed.Load(&file); err != nil {
+		t.Fatal(err)
+	}
+
+	handled := []string{}
+	err := c.Resume(context.Background(), requests("example.com", "missing.com", "redirect.com", "canceled.com", "new.com"), resumed,
+		HandlerFunc(func(req *Request, res *Response, err error) {
+			mu.Lock()
+			handled = append(handled, req.Domain)
+			mu.Unlock()
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(handled) != 2 {
+		t.Errorf("Expected only pending requests to be crawled and not %v", handled)
+	}
+	for _, host := range []string{"example.com", "missing.com", "redirect.com"} {
+		if crawled[host] != 1 {
+			t.Errorf("Expected completed request [%s] to be crawled once and not [%d] times", host, crawled[host])
+		}
+	}
+	if crawled["canceled.com"] != 1 || crawled["new.com"] != 1 {
+		t.Errorf("Expected aborted and new requests to be crawled on resume")
+	}
+}
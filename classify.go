@@ -0,0 +1,169 @@
+package adstxt
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+)
+
+// tlsRemoteErrorOp net.OpError operation of TLS alert sent by remote host
+const tlsRemoteErrorOp = "remote error"
+
+// Ads.txt request error classes (see Classify). Class values are stable across releases, so crawl reports of
+// different runs can be compared by class
+const (
+	// ErrorClassDomainDead domain failed the liveness pre-check of batch crawl (see WithLivenessCheck)
+	ErrorClassDomainDead ErrorClass = "DOMAIN_DEAD"
+	// ErrorClassDNSFailure remote host name could not be resolved
+	ErrorClassDNSFailure ErrorClass = "DNS_FAILURE"
+	// ErrorClassConnectTimeout TCP connection to remote host was not established in time
+	ErrorClassConnectTimeout ErrorClass = "CONNECT_TIMEOUT"
+	// ErrorClassTimeout remote host did not respond in time after connection was established
+	ErrorClassTimeout ErrorClass = "TIMEOUT"
+	// ErrorClassTLSError TLS handshake with remote host failed (invalid certificate, protocol error or timeout)
+	ErrorClassTLSError ErrorClass = "TLS_ERROR"
+	// ErrorClassHTTP4xx remote host responded with HTTP 4xx status (including 404 for missing Ads.txt file)
+	ErrorClassHTTP4xx ErrorClass = "HTTP_4XX"
+	// ErrorClassHTTP5xx remote host responded with HTTP 5xx status
+	ErrorClassHTTP5xx ErrorClass = "HTTP_5XX"
+	// ErrorClassRedirectLoop remote host redirected more than the maximum number of redirects
+	ErrorClassRedirectLoop ErrorClass = "REDIRECT_LOOP"
+	// ErrorClassRedirectError HTTP redirect could not be followed (invalid, out of scope or not allowed destination)
+	ErrorClassRedirectError ErrorClass = "REDIRECT_ERROR"
+	// ErrorClassNotPlaintext remote host responded with HTML page or content that is not text/plain
+	ErrorClassNotPlaintext ErrorClass = "NOT_PLAINTEXT"
+	// ErrorClassParseError Ads.txt file could not be parsed
+	ErrorClassParseError ErrorClass = "PARSE_ERROR"
+	// ErrorClassEmptyFile Ads.txt file was fetched, but has no data records
+	ErrorClassEmptyFile ErrorClass = "EMPTY_FILE"
+	// ErrorClassOther request failed for any other reason (connection refused, robots.txt, body size, etc.)
+	ErrorClassOther ErrorClass = "OTHER"
+)
+
+// crawl error messages
+const (
+	errCrawlFailed = "[%s] Ads.txt request of [%s] failed: %s"
+	errEmptyFile   = "Ads.txt file has no data records"
+)
+
+// ErrorClass stable classification of failed Ads.txt request
+type ErrorClass string
+
+// CrawlError failed Ads.txt request classified by ErrorClass, for bucketing failures of large scale crawl (see
+// NewCrawlError)
+type CrawlError struct {
+	Domain string     // Domain root domain of the Ads.txt request
+	URL    string     // URL of the Ads.txt file
+	Class  ErrorClass // Class of the failure
+	Err    error      // Err request error, nil for ErrorClassEmptyFile
+}
+
+func (e *CrawlError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf(errCrawlFailed, e.Class, e.URL, errEmptyFile)
+	}
+	return fmt.Sprintf(errCrawlFailed, e.Class, e.URL, e.Err)
+}
+
+// Unwrap return the request error
+func (e *CrawlError) Unwrap() error {
+	return e.Err
+}
+
+// NewCrawlError return CrawlError of Ads.txt request result, or nil if the request succeeded and the Ads.txt file has
+// data records (see Classify)
+func NewCrawlError(req *Request, res *Response, err error) *CrawlError {
+	class := Classify(res, err)
+	if len(class) == 0 {
+		return nil
+	}
+
+	e := &CrawlError{Domain: req.Domain, URL: req.URL, Class: class, Err: err}
+	if res != nil {
+		e.URL = responseKey(req, res)
+	}
+	return e
+}
+
+// Classify return the error class of Ads.txt request result, or empty class if the request succeeded and the Ads.txt
+// file has data records. Successful request of Ads.txt file without data records (including placeholder only file)
+// is classified as ErrorClassEmptyFile, and NotModified response is not classified
+func Classify(res *Response, err error) ErrorClass {
+	if err == nil {
+		if res != nil && !res.NotModified && res.Records != nil && len(res.DataRecords) == 0 {
+			return ErrorClassEmptyFile
+		}
+		return ""
+	}
+
+	var crawlErr *CrawlError
+	var deadErr *ErrDomainDead
+	var timeoutErr *ErrRequestTimeout
+	var dnsErr *ErrDNS
+	var clientErr *ErrClientError
+	var serverErr *ErrServerError
+	var redirectErr *ErrRedirect
+	var contentTypeErr *ErrInvalidContentType
+	var htmlErr *ErrHTMLPage
+	var parseErr *ParseError
+	var lineErr *ErrLineTooLong
+	switch {
+	case errors.As(err, &crawlErr):
+		return crawlErr.Class
+	case errors.As(err, &deadErr):
+		return ErrorClassDomainDead
+	case errors.As(err, &dnsErr):
+		return ErrorClassDNSFailure
+	case errors.As(err, &timeoutErr):
+		switch timeoutErr.Phase {
+		case TimeoutConnect:
+			return ErrorClassConnectTimeout
+		case TimeoutTLSHandshake:
+			return ErrorClassTLSError
+		}
+		return ErrorClassTimeout
+	case errors.Is(err, ErrTimeout):
+		return ErrorClassTimeout
+	case isTLSError(err):
+		return ErrorClassTLSError
+	case errors.As(err, &clientErr):
+		return ErrorClassHTTP4xx
+	case errors.As(err, &serverErr):
+		if serverErr.StatusCode >= 500 && serverErr.StatusCode < 600 {
+			return ErrorClassHTTP5xx
+		}
+		return ErrorClassOther
+	case errors.As(err, &redirectErr):
+		if errors.Is(err, ErrTooManyRedirects) {
+			return ErrorClassRedirectLoop
+		}
+		return ErrorClassRedirectError
+	case errors.As(err, &contentTypeErr), errors.As(err, &htmlErr):
+		return ErrorClassNotPlaintext
+	case errors.As(err, &parseErr), errors.As(err, &lineErr):
+		return ErrorClassParseError
+	}
+	return ErrorClassOther
+}
+
+// isTLSError check if err is TLS handshake or certificate verification error
+func isTLSError(err error) bool {
+	var verifyErr *tls.CertificateVerificationError
+	var recordErr tls.RecordHeaderError
+	var alertErr tls.AlertError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	var rootsErr x509.SystemRootsError
+	if errors.As(err, &verifyErr) || errors.As(err, &recordErr) || errors.As(err, &alertErr) ||
+		errors.As(err, &authorityErr) || errors.As(err, &hostnameErr) || errors.As(err, &invalidErr) ||
+		errors.As(err, &rootsErr) {
+		return true
+	}
+
+	// TLS alerts sent by remote host are not exported by crypto/tls, and are reported as "remote error" net.OpError
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == tlsRemoteErrorOp
+}
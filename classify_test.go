@@ -0,0 +1,77 @@
+package adstxt
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+)
+
+// TestClassify test classifying Ads.txt request results into error classes
+func TestClassify(t *testing.T) {
+	records, _ := ParseBody([]byte("greenadexchange.com,XF7342,DIRECT"))
+	empty, _ := ParseBody([]byte("# no sellers\ncontact=adops@example.com"))
+
+	tests := []struct {
+		res      *Response
+		err      error
+		expected ErrorClass
+	}{
+		{&Response{Records: records}, nil, ""},
+		{&Response{NotModified: true}, nil, ""},
+		{&Response{Records: empty}, nil, ErrorClassEmptyFile},
+		{nil, &ErrDNS{Host: "example.com", Err: errors.New("no such host")}, ErrorClassDNSFailure},
+		{nil, &ErrRequest{Err: &ErrRequestTimeout{Phase: TimeoutConnect}}, ErrorClassConnectTimeout},
+		{nil, &ErrRequest{Err: &ErrRequestTimeout{Phase: TimeoutTLSHandshake}}, ErrorClassTLSError},
+		{nil, &ErrRequest{Err: &ErrRequestTimeout{Phase: TimeoutResponseHeader}}, ErrorClassTimeout},
+		{nil, &ErrRequest{Err: x509.UnknownAuthorityError{}}, ErrorClassTLSError},
+		{nil, &ErrRequest{Err: &net.OpError{Op: "remote error", Err: errors.New("tls: handshake failure")}}, ErrorClassTLSError},
+		{nil, &ErrRequest{Err: tls.RecordHeaderError{Msg: "first record does not look like a TLS handshake"}}, ErrorClassTLSError},
+		{nil, &ErrRequest{Err: tls.AlertError(40)}, ErrorClassTLSError},
+		{nil, &ErrRequest{Err: errors.New("dial tcp: tls: connection refused")}, ErrorClassOther},
+		{nil, &ErrClientError{StatusCode: http.StatusNotFound}, ErrorClassHTTP4xx},
+		{nil, &ErrClientError{StatusCode: http.StatusForbidden}, ErrorClassHTTP4xx},
+		{nil, &ErrServerError{StatusCode: http.StatusServiceUnavailable}, ErrorClassHTTP5xx},
+		{nil, &ErrServerError{StatusCode: 600}, ErrorClassOther},
+		{nil, &ErrRedirect{Reason: ErrTooManyRedirects}, ErrorClassRedirectLoop},
+		{nil, &ErrRedirect{Reason: ErrRedirectOutOfScope}, ErrorClassRedirectError},
+		{nil, &ErrInvalidContentType{ContentType: "text/html"}, ErrorClassNotPlaintext},
+		{nil, &ErrHTMLPage{}, ErrorClassNotPlaintext},
+		{nil, &ParseError{}, ErrorClassParseError},
+		{nil, fmt.Errorf("parse: %w", &ErrLineTooLong{}), ErrorClassParseError},
+		{nil, &ErrRequest{Err: errors.New("connection refused")}, ErrorClassOther},
+		{nil, &CrawlError{Class: ErrorClassHTTP5xx, Err: errors.New("failed")}, ErrorClassHTTP5xx},
+	}
+
+	for i, test := range tests {
+		if class := Classify(test.res, test.err); class != test.expected {
+			t.Errorf("Expected result [%d] to be classified as [%s] and not [%s]", i, test.expected, class)
+		}
+	}
+}
+
+// TestNewCrawlError test creating CrawlError of Ads.txt request result
+func TestNewCrawlError(t *testing.T) {
+	req, _ := NewRequest("example.com")
+	records, _ := ParseBody([]byte("greenadexchange.com,XF7342,DIRECT"))
+	if e := NewCrawlError(req, &Response{Records: records}, nil); e != nil {
+		t.Errorf("Expected no CrawlError of successful request and not [%s]", e)
+	}
+
+	e := NewCrawlError(req, nil, &ErrClientError{StatusCode: http.StatusNotFound})
+	if e == nil || e.Class != ErrorClassHTTP4xx || e.Domain != "example.com" || e.URL != req.URL {
+		t.Fatalf("Unexpected CrawlError [%v]", e)
+	}
+	if !errors.Is(e, ErrNotFound) {
+		t.Error("Expected CrawlError to match the request error")
+	}
+
+	empty, _ := ParseBody([]byte(""))
+	e = NewCrawlError(req, &Response{Request: req, Records: empty}, nil)
+	if e == nil || e.Class != ErrorClassEmptyFile || e.Err != nil || len(e.Error()) == 0 {
+		t.Errorf("Unexpected CrawlError of empty Ads.txt file [%v]", e)
+	}
+}
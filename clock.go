@@ -0,0 +1,61 @@
+package adstxt
+
+import "time"
+
+// Clock source of the current time and of timers of the crawler (see WithClock): the crawler computes Ads.txt files
+// fetch and expiration dates, cache freshness and Scheduler re-crawl timing by the clock, so tests can simulate
+// expiration and re-crawls with manual clock (see adstxttest.Clock) instead of waiting for real time to pass. Network
+// timeouts, retries and rate limits always use the system time
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer single event timer of Clock, as time.Timer
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// systemClock Clock of the system time
+type systemClock struct{}
+
+// Now is the Clock interface implementation for systemClock
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// Since is the Clock interface implementation for systemClock
+func (systemClock) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
+
+// NewTimer is the Clock interface implementation for systemClock
+func (systemClock) NewTimer(d time.Duration) Timer {
+	return systemTimer{time.NewTimer(d)}
+}
+
+// systemTimer Timer of the system time
+type systemTimer struct {
+	*time.Timer
+}
+
+// C is the Timer interface implementation for systemTimer
+func (t systemTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+// now return the current time of the crawler clock
+func (c *Crawler) now() time.Time {
+	return c.clock.Now()
+}
+
+// clockNow return the current time of clock, or the system time if clock is nil
+func clockNow(clock Clock) time.Time {
+	if clock == nil {
+		return time.Now()
+	}
+	return clock.Now()
+}
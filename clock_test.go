@@ -0,0 +1,36 @@
+package adstxt
+
+import (
+	"sync"
+	"time"
+)
+
+// testClock Clock of tests that sets the current time manually, with timers of the system clock
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// Now is the Clock interface implementation for testClock
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Since is the Clock interface implementation for testClock
+func (c *testClock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+// NewTimer is the Clock interface implementation for testClock
+func (c *testClock) NewTimer(d time.Duration) Timer {
+	return systemClock{}.NewTimer(d)
+}
+
+// advance move the clock forward by d
+func (c *testClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
@@ -0,0 +1,523 @@
+// Command adstxt crawl, parse and validate Ads.txt files from the command line.
+//
+// Usage:
+//
+//	adstxt get [flags] <domain>                     crawl and parse single Ads.txt file
+//	adstxt batch [flags] -f domains.txt -o out.json crawl and parse Ads.txt files of multiple domains
+//	adstxt validate <file>                          parse and validate local Ads.txt file
+//	adstxt lint [flags] <file>                      check local Ads.txt file against lint rules
+//	adstxt validate-dir [flags] <dir>               validate and lint all Ads.txt files of local directory
+//	adstxt diff <old.json> <new.json>               compare Ads.txt files of two batch crawl results
+//	adstxt serve [flags] -addr :8080                serve the crawler as HTTP JSON service
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tzafrirben/go-adstxt-crawler/adstxt"
+	"github.com/tzafrirben/go-adstxt-crawler/adstxt/lint"
+	"github.com/tzafrirben/go-adstxt-crawler/adstxt/server"
+)
+
+const usage = `Usage:
+  adstxt get [flags] <domain>                     crawl and parse single Ads.txt file
+  adstxt batch [flags] -f domains.txt -o out.json crawl and parse Ads.txt files of multiple domains
+  adstxt validate <file>                          parse and validate local Ads.txt file
+  adstxt lint [flags] <file>                      check local Ads.txt file against lint rules
+  adstxt validate-dir [flags] <dir>               validate and lint all Ads.txt files of local directory
+  adstxt diff <old.json> <new.json>               compare Ads.txt files of two batch crawl results
+  adstxt serve [flags] -addr :8080                serve the crawler as HTTP JSON service
+
+Run "adstxt <command> -h" for command flags`
+
+// result of single domain Ads.txt request in batch output
+type result struct {
+	Domain   string           `json:"domain"`
+	Response *adstxt.Response `json:"response,omitempty"`
+	Error    string           `json:"error,omitempty"`
+}
+
+// usageError command line arguments error: the command exits with status 2, as on invalid flags
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+func main() {
+	err := run(os.Args[1:], os.Stdout, os.Stderr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	os.Exit(exitStatus(err))
+}
+
+// run the command of args (without the program name), writing the command output to stdout and its progress and
+// summary to stderr
+func run(args []string, stdout, stderr io.Writer) error {
+	if len(args) < 1 {
+		return &usageError{msg: usage}
+	}
+
+	cmd := command{name: args[0], stdout: stdout, stderr: stderr}
+	var err error
+	switch args[0] {
+	case "get":
+		err = cmd.get(args[1:])
+	case "batch":
+		err = cmd.batch(args[1:])
+	case "validate":
+		err = cmd.validate(args[1:])
+	case "lint":
+		err = cmd.lintFile(args[1:])
+	case "validate-dir":
+		err = cmd.validateDir(args[1:])
+	case "diff":
+		err = cmd.diff(args[1:])
+	case "serve":
+		err = cmd.serve(args[1:])
+	case "-h", "-help", "--help", "help":
+		fmt.Fprintln(stdout, usage)
+		return nil
+	default:
+		return &usageError{msg: fmt.Sprintf("unknown command [%s]\n%s", args[0], usage)}
+	}
+
+	// command help requested by -h flag is not an error
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	return err
+}
+
+// exitStatus return the command exit status of run error: 0 on success, 2 on command line arguments error and 1 on
+// any other error
+func exitStatus(err error) int {
+	var usageErr *usageError
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &usageErr):
+		return 2
+	}
+	return 1
+}
+
+// command single run of command, with its output writers
+type command struct {
+	name   string
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// flagSet return new flag set of the command, that writes flags usage to stderr
+func (cmd *command) flagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	fs.SetOutput(cmd.stderr)
+	return fs
+}
+
+// parse command flags of args. Return usageError if the flags are invalid
+func (cmd *command) parse(fs *flag.FlagSet, args []string) error {
+	err := fs.Parse(args)
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		return &usageError{msg: err.Error()}
+	}
+	return err
+}
+
+// crawlerFlags register crawler settings flags and return function that create Crawler from the parsed flags
+func crawlerFlags(fs *flag.FlagSet) func(opts ...adstxt.Option) *adstxt.Crawler {
+	timeout := fs.Duration("timeout", 30*time.Second, "HTTP request timeout")
+	userAgent := fs.String("user-agent", "", "HTTP User-Agent header (default is the library User-Agent)")
+	robots := fs.Bool("robots", false, "respect robots.txt disallow rules and crawl-delay")
+
+	return func(opts ...adstxt.Option) *adstxt.Crawler {
+		opts = append(opts, adstxt.WithTimeout(*timeout), adstxt.WithRobotsTxt(*robots))
+		if len(*userAgent) > 0 {
+			opts = append(opts, adstxt.WithUserAgent(*userAgent))
+		}
+		return adstxt.NewCrawler(opts...)
+	}
+}
+
+// newRequest return Ads.txt request, or app-ads.txt request if app is set
+func newRequest(domain string, app bool) (*adstxt.Request, error) {
+	if app {
+		return adstxt.NewAppAdsTxtRequest(domain)
+	}
+	return adstxt.NewRequest(domain)
+}
+
+// get crawl and parse single Ads.txt file, and print the response as JSON
+func (cmd *command) get(args []string) error {
+	fs := cmd.flagSet()
+	app := fs.Bool("app", false, "fetch app-ads.txt file instead of Ads.txt")
+	newCrawler := crawlerFlags(fs)
+	if err := cmd.parse(fs, args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		return &usageError{msg: "get command expects single domain argument"}
+	}
+
+	req, err := newRequest(fs.Arg(0), *app)
+	if err != nil {
+		return err
+	}
+
+	res, err := newCrawler().Get(req)
+	if err != nil {
+		return err
+	}
+
+	return writeJSON(cmd.stdout, res)
+}
+
+// batch crawl and parse Ads.txt files of all domains listed in input file, and write the results as JSON array
+func (cmd *command) batch(args []string) error {
+	fs := cmd.flagSet()
+	input := fs.String("f", "", "input file with single domain per line (default is stdin)")
+	output := fs.String("o", "", "output JSON file (default is stdout)")
+	concurrency := fs.Int("c", 50, "maximum number of concurrent requests")
+	app := fs.Bool("app", false, "fetch app-ads.txt files instead of Ads.txt")
+	shards := fs.Int("shards", 1, "number of shards the domains are split across, for distributed crawls")
+	shard := fs.Int("shard", 0, "crawl only the domains of this shard index (0 to shards-1)")
+	maxRequests := fs.Int("max-requests", 0, "stop the crawl after sending this number of requests (0 for no limit)")
+	maxBytes := fs.Int64("max-bytes", 0, "stop the crawl after downloading this number of bytes (0 for no limit)")
+	maxDuration := fs.Duration("max-duration", 0, "stop the crawl after this duration (0 for no limit)")
+	dryRun := fs.Bool("dry-run", false, "print the URLs that would be fetched, and the skipped domains with the reason of each, without crawling")
+	newCrawler := crawlerFlags(fs)
+	if err := cmd.parse(fs, args); err != nil {
+		return err
+	}
+
+	if *shards < 1 || *shard < 0 || *shard >= *shards {
+		return &usageError{msg: fmt.Sprintf("invalid shard [%d] of [%d] shards", *shard, *shards)}
+	}
+
+	in := os.Stdin
+	if len(*input) > 0 {
+		f, err := os.Open(*input)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	domains, err := readDomains(in)
+	if err != nil {
+		return err
+	}
+
+	results := make([]*result, 0, len(domains))
+	requests := make([]*adstxt.Request, 0, len(domains))
+	for _, d := range domains {
+		req, err := newRequest(d, *app)
+		if err != nil {
+			results = append(results, &result{Domain: d, Error: err.Error()})
+			continue
+		}
+		requests = append(requests, req)
+	}
+
+	budget := adstxt.Budget{MaxRequests: *maxRequests, MaxBytes: *maxBytes, MaxDuration: *maxDuration}
+	c := newCrawler(adstxt.WithConcurrency(*concurrency), adstxt.WithOrderedResults(true), adstxt.WithBudget(budget))
+
+	if *dryRun {
+		plan := c.Plan(requests, &adstxt.PlanOptions{Shard: *shard, Shards: *shards})
+		for _, r := range results {
+			plan.Skipped = append(plan.Skipped, &adstxt.PlannedRequest{Request: &adstxt.Request{Domain: r.Domain},
+				Skip: adstxt.SkipInvalid, Detail: r.Error})
+		}
+		if err := cmd.writeOutput(*output, plan); err != nil {
+			return err
+		}
+
+		skipped := plan.SkippedBy()
+		fmt.Fprintf(cmd.stderr, "[%d] URLs would be fetched: [%d] skipped, [%d] invalid, [%d] duplicates, [%d] other shards, [%d] exceed budget\n",
+			len(plan.Fetch), len(plan.Skipped), skipped[adstxt.SkipInvalid], skipped[adstxt.SkipDuplicate], skipped[adstxt.SkipOtherShard], skipped[adstxt.SkipBudget])
+		return nil
+	}
+
+	if *shards > 1 {
+		requests = adstxt.ShardRequests(requests, *shards)[*shard]
+	}
+
+	summary := &adstxt.BatchSummary{}
+	start := time.Now()
+
+	c.GetMultiple(requests, adstxt.HandlerFunc(func(req *adstxt.Request, res *adstxt.Response, err error) {
+		summary.Handle(req, res, err)
+
+		r := &result{Domain: req.Domain, Response: res}
+		if err != nil {
+			r.Error = err.Error()
+		}
+		results = append(results, r)
+	}))
+	summary.Duration = time.Since(start)
+
+	if err := cmd.writeOutput(*output, results); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.stderr, "crawled [%d] domains in [%s]: [%d] succeeded, [%d] not found, [%d] soft not found, [%d] redirect errors, [%d] other errors\n",
+		summary.Total, summary.Duration.Round(time.Millisecond), summary.Succeeded, summary.NotFound, summary.SoftNotFound, summary.RedirectErrors, summary.OtherErrors)
+	return nil
+}
+
+// validate parse local Ads.txt file, and print its parse warnings and records validation errors
+func (cmd *command) validate(args []string) error {
+	w := cmd.stdout
+
+	fs := cmd.flagSet()
+	if err := cmd.parse(fs, args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		return &usageError{msg: "validate command expects single file argument"}
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	records, err := adstxt.ParseReader(f)
+	if err != nil {
+		return err
+	}
+
+	for _, warn := range records.Warnings {
+		fmt.Fprintf(w, "line %d: %s [%s]\n", warn.Index, warn.Message, warn.Text)
+	}
+
+	invalid := 0
+	if err := records.Validate(); err != nil {
+		for _, e := range err.(adstxt.ValidationErrors) {
+			fmt.Fprintf(w, "line %d: %s\n", e.Line, e)
+			invalid++
+		}
+	}
+
+	fmt.Fprintf(w, "[%d] data records, [%d] variables, [%d] warnings, [%d] validation errors\n",
+		len(records.DataRecords), len(records.Variables), len(records.Warnings), invalid)
+
+	if len(records.Warnings) > 0 || invalid > 0 {
+		return fmt.Errorf("[%s] is not a valid Ads.txt file", fs.Arg(0))
+	}
+	return nil
+}
+
+// lintFile check local Ads.txt file against lint rules, and print the findings ranked by severity
+func (cmd *command) lintFile(args []string) error {
+	fs := cmd.flagSet()
+	newLinter := linterFlags(fs)
+	failOn := fs.String("fail-on", "error", "lowest findings severity that fails the command: info, warning or error")
+	asJSON := fs.Bool("json", false, "print findings as JSON")
+	score := fs.Bool("score", false, "print compliance score and grade of the file instead of lint findings")
+	if err := cmd.parse(fs, args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		return &usageError{msg: "lint command expects single file argument"}
+	}
+	threshold, err := lint.ParseSeverity(*failOn)
+	if err != nil {
+		return &usageError{msg: err.Error()}
+	}
+
+	body, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	l := newLinter()
+	if *score {
+		records, err := adstxt.ParseBody(body)
+		if err != nil {
+			return err
+		}
+		report := l.Score(records, nil)
+		report.Domain = fs.Arg(0)
+		if *asJSON {
+			return writeJSON(cmd.stdout, report)
+		}
+		fmt.Fprintln(cmd.stdout, report)
+		return nil
+	}
+
+	findings, err := l.LintBody(body)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		if err := writeJSON(cmd.stdout, findings); err != nil {
+			return err
+		}
+	} else {
+		for _, f := range findings {
+			fmt.Fprintln(cmd.stdout, f)
+		}
+	}
+
+	if max := lint.Max(findings); max >= threshold {
+		return fmt.Errorf("[%s] has [%d] lint findings, highest severity is [%s]", fs.Arg(0), len(findings), max)
+	}
+	return nil
+}
+
+// linterFlags register lint rules settings flags and return function that create Linter from the parsed flags
+func linterFlags(fs *flag.FlagSet) func() *lint.Linter {
+	disable := fs.String("disable", "", "comma separated names of rules to disable")
+	certIDs := fs.String("cert-id-systems", strings.Join(lint.DefaultCertAuthorityAdSystems, ","), "comma separated domains of advertising systems that require certification authority ID")
+
+	return func() *lint.Linter {
+		l := lint.New()
+		for _, r := range l.Rules {
+			if c, ok := r.(*lint.MissingCertAuthorityID); ok {
+				c.AdSystems = strings.Split(*certIDs, ",")
+			}
+		}
+		if len(*disable) > 0 {
+			l.Disable(strings.Split(*disable, ",")...)
+		}
+		return l
+	}
+}
+
+// validateDir validate and lint all Ads.txt files of local directory, and write consolidated JSON report of all files
+func (cmd *command) validateDir(args []string) error {
+	fs := cmd.flagSet()
+	newLinter := linterFlags(fs)
+	output := fs.String("o", "", "output JSON report file (stdout if empty)")
+	if err := cmd.parse(fs, args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		return &usageError{msg: "validate-dir command expects single directory argument"}
+	}
+
+	report, err := newLinter().ValidateDir(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if err := cmd.writeOutput(*output, report); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.stderr, "validated [%d] Ads.txt files: [%d] passed, [%d] failed\n", report.Total, report.Passed, report.Failed)
+	if report.Failed > 0 {
+		return fmt.Errorf("[%d] Ads.txt files of [%s] did not pass validation", report.Failed, fs.Arg(0))
+	}
+	return nil
+}
+
+// diff compare two batch crawl results, and print domains that gained, lost or changed Ads.txt file, and the changes
+// aggregated by advertising system
+func (cmd *command) diff(args []string) error {
+	fs := cmd.flagSet()
+	if err := cmd.parse(fs, args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 2 {
+		return &usageError{msg: "diff command expects old and new batch results file arguments"}
+	}
+
+	snapshots := make([]*adstxt.CrawlSnapshot, 2)
+	for i, path := range fs.Args() {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		snapshots[i], err = adstxt.LoadCrawlSnapshot(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("failed to load batch results [%s]: %s", path, err)
+		}
+	}
+
+	d := adstxt.DiffSnapshots(snapshots[0], snapshots[1])
+	if err := writeJSON(cmd.stdout, d); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.stderr, "[%d] domains gained Ads.txt file, [%d] domains lost Ads.txt file, [%d] domains changed Ads.txt file\n",
+		len(d.GainedFile), len(d.LostFile), len(d.Changed))
+	return nil
+}
+
+// serve the crawler as HTTP JSON service (see package server), until the server fails
+func (cmd *command) serve(args []string) error {
+	fs := cmd.flagSet()
+	addr := fs.String("addr", ":8080", "HTTP listen address")
+	concurrency := fs.Int("c", 50, "maximum number of concurrent requests of batch request")
+	maxDomains := fs.Int("max-domains", 1000, "maximum number of domains in single batch request")
+	newCrawler := crawlerFlags(fs)
+	if err := cmd.parse(fs, args); err != nil {
+		return err
+	}
+
+	s := server.New(newCrawler(adstxt.WithConcurrency(*concurrency)))
+	s.MaxDomains = *maxDomains
+
+	fmt.Fprintf(cmd.stderr, "serving Ads.txt crawler on [%s]\n", *addr)
+	return http.ListenAndServe(*addr, s)
+}
+
+// readDomains read single domain per line, ignoring empty lines and comments
+func readDomains(r io.Reader) ([]string, error) {
+	domains := []string{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		d := strings.TrimSpace(scanner.Text())
+		if len(d) == 0 || strings.HasPrefix(d, "#") {
+			continue
+		}
+		domains = append(domains, d)
+	}
+
+	return domains, scanner.Err()
+}
+
+// writeJSON write v to w as indented JSON
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// writeOutput write v as indented JSON to the output file, or to the command stdout if path is empty
+func (cmd *command) writeOutput(path string, v interface{}) error {
+	if len(path) == 0 {
+		return writeJSON(cmd.stdout, v)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return writeJSON(f, v)
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestRun test command line commands flags parsing, output and exit status
+func TestRun(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, "google.com, pub-1234567890123456, DIRECT, f08c47fec0942fa0")
+	}))
+	defer ts.Close()
+
+	dir := t.TempDir()
+	file := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	valid := file("valid.txt", "google.com, pub-1234567890123456, DIRECT, f08c47fec0942fa0\n")
+	invalid := file("invalid.txt", "google.com, pub-1234567890123456, DIRECT, f08c47fec0942fa0\ngoogle.com\n")
+	mixedCase := file("mixed.txt", "Google.com, pub-1234567890123456, DIRECT, f08c47fec0942fa0\n")
+	domains := file("domains.txt", "# test domains\n"+ts.URL+"\n\ncom\n")
+	if err := os.MkdirAll(filepath.Join(dir, "files", "example.com"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	file(filepath.Join("files", "example.com", "ads.txt"), "google.com, pub-1234567890123456, DIRECT, f08c47fec0942fa0\n")
+
+	tests := []struct {
+		name   string
+		args   []string
+		status int
+		stdout string // stdout holds this string
+		stderr string // stderr holds this string
+	}{
+		{"no command", []string{}, 2, "", ""},
+		{"help", []string{"help"}, 0, "Usage:", ""},
+		{"unknown command", []string{"crawl"}, 2, "", ""},
+		{"get without domain", []string{"get"}, 2, "", ""},
+		{"get invalid flag", []string{"get", "-bogus", "example.com"}, 2, "", "flag provided but not defined"},
+		{"get help", []string{"get", "-h"}, 0, "", "-user-agent"},
+		{"get", []string{"get", "-timeout", "5s", ts.URL}, 0, `"pub-1234567890123456"`, ""},
+		{"batch invalid shard", []string{"batch", "-f", domains, "-shards", "2", "-shard", "2"}, 2, "", ""},
+		{"batch missing input", []string{"batch", "-f", filepath.Join(dir, "missing.txt")}, 1, "", ""},
+		{"batch", []string{"batch", "-f", domains, "-c", "2"}, 0, `"pub-1234567890123456"`, "crawled [1] domains"},
+		{"batch dry run", []string{"batch", "-f", domains, "-dry-run"}, 0, ts.URL + "/ads.txt", "[1] URLs would be fetched"},
+		{"validate", []string{"validate", valid}, 0, "[1] data records", ""},
+		{"validate invalid file", []string{"validate", invalid}, 1, "line 2:", ""},
+		{"validate missing file", []string{"validate", filepath.Join(dir, "missing.txt")}, 1, "", ""},
+		{"lint", []string{"lint", mixedCase}, 0, "mixed-case-domain", ""},
+		{"lint fail on warning", []string{"lint", "-fail-on", "info", mixedCase}, 1, "mixed-case-domain", ""},
+		{"lint disabled rule", []string{"lint", "-disable", "mixed-case-domain", "-fail-on", "info", mixedCase}, 0, "", ""},
+		{"lint invalid severity", []string{"lint", "-fail-on", "fatal", valid}, 2, "", ""},
+		{"validate-dir", []string{"validate-dir", filepath.Join(dir, "files")}, 0, `"passed"`, "validated [1] Ads.txt files"},
+		{"diff without files", []string{"diff", valid}, 2, "", ""},
+	}
+
+	for _, test := range tests {
+		var stdout, stderr bytes.Buffer
+		err := run(test.args, &stdout, &stderr)
+		if status := exitStatus(err); status != test.status {
+			t.Errorf("Expected [%s] exit status [%d] and not [%d] (%v)", test.name, test.status, status, err)
+		}
+		if !strings.Contains(stdout.String(), test.stdout) {
+			t.Errorf("Expected [%s] output to hold [%s] and not [%s]", test.name, test.stdout, stdout.String())
+		}
+		if !strings.Contains(stderr.String(), test.stderr) {
+			t.Errorf("Expected [%s] error output to hold [%s] and not [%s]", test.name, test.stderr, stderr.String())
+		}
+	}
+}
+
+// TestRunBatch test batch command output file of crawl results, in order of the input domains, and diff of batch
+// results files
+func TestRunBatch(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, "google.com, pub-1234567890123456, DIRECT, f08c47fec0942fa0")
+	}))
+	defer ts.Close()
+
+	dir := t.TempDir()
+	domains := filepath.Join(dir, "domains.txt")
+	if err := os.WriteFile(domains, []byte("com\n"+ts.URL+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	output := filepath.Join(dir, "out.json")
+
+	var stdout, stderr bytes.Buffer
+	if err := run([]string{"batch", "-f", domains, "-o", output}, &stdout, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("Expected batch results written to output file and not to stdout [%s]", stdout.String())
+	}
+
+	b, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := []*result{}
+	if err := json.Unmarshal(b, &results); err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[0].Domain != "com" || len(results[0].Error) == 0 || results[0].Response != nil {
+		t.Fatalf("Expected invalid domain result first and not %+v", results)
+	}
+	if res := results[1].Response; res == nil || len(results[1].Error) != 0 || len(res.DataRecords) != 1 {
+		t.Errorf("Expected crawled Ads.txt file result and not %+v", results[1])
+	}
+
+	stdout.Reset()
+	if err := run([]string{"diff", output, output}, &stdout, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(stderr.String(), "[0] domains changed Ads.txt file") || !json.Valid(stdout.Bytes()) {
+		t.Errorf("Expected JSON diff of identical batch results and not [%s]", stderr.String())
+	}
+}
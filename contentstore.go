@@ -0,0 +1,179 @@
+package adstxt
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// contentObjectsDir FileStore sub directory of content addressed objects
+const contentObjectsDir = "objects"
+
+// Codec compression format of FileStore content addressed objects (see NewContentFileStore)
+type Codec interface {
+	// Extension return the file name extension of compressed objects (e.g. ".gz")
+	Extension() string
+	// NewWriter return writer that compresses data written to it into w
+	NewWriter(w io.Writer) (io.WriteCloser, error)
+	// NewReader return reader that decompresses data read from r
+	NewReader(r io.Reader) (io.ReadCloser, error)
+}
+
+// GzipCodec Codec of gzip compression
+var GzipCodec Codec = NewCodec(".gz",
+	func(w io.Writer) (io.WriteCloser, error) { return gzip.NewWriter(w), nil },
+	func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) })
+
+// codecFuncs Codec implementation by compress and decompress functions
+type codecFuncs struct {
+	ext        string
+	compress   func(io.Writer) (io.WriteCloser, error)
+	decompress func(io.Reader) (io.ReadCloser, error)
+}
+
+// NewCodec create new Codec from compress and decompress functions, for example to use zstd compression from
+// github.com/klauspost/compress/zstd without adding it as dependency of this package
+func NewCodec(ext string, compress func(io.Writer) (io.WriteCloser, error), decompress func(io.Reader) (io.ReadCloser, error)) Codec {
+	return &codecFuncs{ext: ext, compress: compress, decompress: decompress}
+}
+
+// Extension is the Codec interface implementation for codecFuncs
+func (c *codecFuncs) Extension() string {
+	return c.ext
+}
+
+// NewWriter is the Codec interface implementation for codecFuncs
+func (c *codecFuncs) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return c.compress(w)
+}
+
+// NewReader is the Codec interface implementation for codecFuncs
+func (c *codecFuncs) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return c.decompress(r)
+}
+
+// NewContentFileStore create new FileStore that saves Ads.txt responses in dir, with the records of each response
+// saved in content addressed object compressed by codec (uncompressed if nil), identified by its SHA-256 digest.
+// Identical Ads.txt files, of different domains or of different crawls of the same domain, share a single object, so
+// archives of daily crawls grow only by the Ads.txt files that changed. Objects are saved in the objects sub
+// directory of dir, and are not removed when responses are replaced (see Prune)
+func NewContentFileStore(dir string, codec Codec) (*FileStore, error) {
+	if err := os.MkdirAll(filepath.Join(dir, contentObjectsDir), 0755); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir, content: true, codec: codec}, nil
+}
+
+// Prune remove content addressed objects that are not referenced by any saved response, and return the number of
+// removed objects
+func (s *FileStore) Prune() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	files, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return 0, err
+	}
+
+	referenced := map[string]bool{}
+	for _, f := range files {
+		e, err := s.read(f)
+		if err != nil {
+			return 0, err
+		}
+		if e.Records != "" {
+			referenced[s.objectPath(e.Records)] = true
+		}
+	}
+
+	objects, err := filepath.Glob(filepath.Join(s.dir, contentObjectsDir, "*"))
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, o := range objects {
+		if referenced[o] || strings.HasPrefix(filepath.Base(o), ".tmp-") {
+			continue
+		}
+		if err := os.Remove(o); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// saveObject save records content addressed object, unless an object with the same content is already saved, and
+// return the object SHA-256 digest
+func (s *FileStore) saveObject(records *Records) (string, error) {
+	b, err := json.Marshal(records)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	digest := hex.EncodeToString(sum[:])
+
+	path := s.objectPath(digest)
+	if _, err := os.Stat(path); err == nil {
+		return digest, nil
+	}
+
+	if s.codec != nil {
+		var buf bytes.Buffer
+		w, err := s.codec.NewWriter(&buf)
+		if err != nil {
+			return "", err
+		}
+		if _, err := w.Write(b); err != nil {
+			w.Close()
+			return "", err
+		}
+		if err := w.Close(); err != nil {
+			return "", err
+		}
+		b = buf.Bytes()
+	}
+
+	return digest, writeFile(filepath.Join(s.dir, contentObjectsDir), path, b)
+}
+
+// loadObject load records content addressed object by its SHA-256 digest
+func (s *FileStore) loadObject(digest string) (*Records, error) {
+	f, err := os.Open(s.objectPath(digest))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if s.codec != nil {
+		zr, err := s.codec.NewReader(f)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		r = zr
+	}
+
+	records := &Records{}
+	if err := json.NewDecoder(r).Decode(records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// objectPath return content addressed object file path
+func (s *FileStore) objectPath(digest string) string {
+	ext := ".json"
+	if s.codec != nil {
+		ext += s.codec.Extension()
+	}
+	return filepath.Join(s.dir, contentObjectsDir, digest+ext)
+}
@@ -0,0 +1,74 @@
+package adstxt
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestContentFileStore test saving identical Ads.txt files of different domains in single compressed object
+func TestContentFileStore(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewContentFileStore(dir, GzipCodec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := []byte("greenadexchange.com,XF7342,DIRECT\nsubdomain=dev.example.com")
+	now := time.Now()
+	for _, domain := range []string{"example.com", "test.com"} {
+		records, _ := ParseBody(body)
+		res := &Response{Request: &Request{Domain: domain, URL: "https://" + domain + "/ads.txt"}, Records: records, Expires: now.Add(time.Hour)}
+		if err := s.SaveResponse(res.URL, res); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	objects, _ := filepath.Glob(filepath.Join(dir, contentObjectsDir, "*.json.gz"))
+	if len(objects) != 1 {
+		t.Errorf("Expected single compressed object of identical Ads.txt files and not [%d]", len(objects))
+	}
+
+	res, err := s.LoadResponse("https://test.com/ads.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Domain != "test.com" || len(res.DataRecords) != 1 || res.DataRecords[0].PublisherAccountID != "XF7342" {
+		t.Errorf("Expected loaded response records from content addressed object [%v]", res)
+	}
+	if res.Line(res.Variables[0]) != 2 {
+		t.Errorf("Expected loaded response to keep records line index")
+	}
+
+	// replaced response object is removed by prune
+	records, _ := ParseBody([]byte("greenadexchange.com,XF7343,RESELLER"))
+	if err := s.SaveResponse("https://test.com/ads.txt", &Response{Request: &Request{Domain: "test.com"}, Records: records}); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := s.Prune(); err != nil || n != 0 {
+		t.Errorf("Expected no unreferenced objects [%d] [%v]", n, err)
+	}
+	if err := s.SaveResponse("https://example.com/ads.txt", &Response{Request: &Request{Domain: "example.com"}, Records: records}); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := s.Prune(); err != nil || n != 1 {
+		t.Errorf("Expected single unreferenced object to be pruned [%d] [%v]", n, err)
+	}
+	if res, err := s.LoadResponse("https://example.com/ads.txt"); err != nil || res.DataRecords[0].PublisherAccountID != "XF7343" {
+		t.Errorf("Expected pruned store to load saved response [%v]", err)
+	}
+
+	// objects of content store without codec are not compressed
+	plain, _ := NewContentFileStore(t.TempDir(), nil)
+	if err := plain.SaveResponse("key", &Response{Request: &Request{Domain: "example.com"}, Records: records}); err != nil {
+		t.Fatal(err)
+	}
+	e, err := plain.read(plain.path("key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, err := os.ReadFile(plain.objectPath(e.Records)); err != nil || b[0] != '{' {
+		t.Errorf("Expected uncompressed JSON object [%v]", err)
+	}
+}
@@ -1,12 +1,23 @@
 package adstxt
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
-	"io/ioutil"
-	"log"
+	"io"
+	"mime"
+	"net"
 	"net/http"
+	"runtime"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/andybalholm/brotli"
 )
 
 // Calling remote host error\warning
@@ -14,6 +25,17 @@ const (
 	errHTTPClientError    = "[%s] remote host [%s] Ads.txt URL [%s]"
 	errHTTPGeneralError   = "[%s] remote host [%s] Ads.txt URL [%s]"
 	errHTTPBadContentType = "[%s] Ads.txt file content type should be ‘text/plain’ and not [%s]"
+	errHTTPHTMLPage       = "[%s] remote host responded with HTML page instead of Ads.txt file (soft 404)"
+	errHTTPHTMLRedirect   = "[%s] remote host responded with HTML page that redirects to [%s] instead of Ads.txt file"
+	errHTTPRequestFailed  = "[%s] failed to send Ads.txt request [%s]"
+	errDNSLookupFailed    = "[%s] failed to resolve Ads.txt host [%s] [%s]"
+	errRequestTimeout     = "[%s] timeout after [%s]: %s"
+	errBlockedAddress     = "connection to private network address [%s] is blocked"
+	errDomainDead         = "[%s] domain is not alive (%s check), Ads.txt request skipped: %s"
+	errInvalidRequestURL  = "[%s] is not usable for Ads.txt crawling: %s"
+	errInvalidRequestPath = "invalid Ads.txt request path [%s]: path must not have query or fragment"
+	errInvalidRequestPort = "invalid Ads.txt request port [%d]: port must be between 1 and 65535"
+	errInvalidScheme      = "invalid Ads.txt request scheme [%s]: scheme must be http or https"
 )
 
 // parsing error\warning: each error includes Ads.txt remote host (domain level) and explanaiton about the error
@@ -21,68 +43,801 @@ const (
 	errFailToParseRedirect       = "[%s] failed to parse root domain from HTTP redirect response header. Ads.txt URL [%s] redirect [%s] error [%s]"
 	errRedirectToInvalidAdsTxt   = "[%s] failed to get Ads.txt file, redirect from [%s] to invalid Ads.txt URL [%s]"
 	errRedirectToDifferentDomain = "Only single redirect out of original root domain scope [%s] is allowed. Additional redirect from [%s] to [%s] is forbidden"
+	errTooManyRedirects          = "[%s] failed to get Ads.txt file, stopped after [%d] redirects at Ads.txt URL [%s]"
 )
 
 // HTTP crawler settings
 const (
 	userAgent      = "+https://github.com/tzafrirben/go-adstxt-crawler"
 	requestTimeout = 30
+	maxRedirects   = 10
+	// maximum size of Ads.txt file body read from remote host, and of decompressed Ads.txt file body (10MB)
+	maxBodySize         = 10 << 20
+	maxDecompressedSize = 10 << 20
 )
 
-// crawler provide methods for downloading Ads.txt files from remote host
-type crawler struct {
-	client    *http.Client // HTTP client used to make HTTP request for Ads.txt file from remote host
-	UserAgent string       // crawler UserAgent string
+// Crawler provide methods for downloading Ads.txt files from remote host. Use NewCrawler to create new Crawler with
+// custom options. Crawler is safe to use from multiple goroutines
+type Crawler struct {
+	client              *http.Client   // HTTP client used to make HTTP request for Ads.txt file from remote host
+	httpClient          *http.Client   // custom HTTP client provided by crawler options
+	userAgent           string         // crawler UserAgent string
+	timeout             time.Duration  // HTTP request timeout
+	connectTimeout      time.Duration  // TCP connection timeout
+	handshakeTimeout    time.Duration  // TLS handshake timeout
+	headerTimeout       time.Duration  // time limit to receive response headers (no limit if zero)
+	totalTimeout        time.Duration  // time limit of Ads.txt request, including redirects and retries (no limit if zero)
+	maxRedirects        int            // maximum number of HTTP redirects to follow for single Ads.txt request
+	redirectPolicy      RedirectPolicy // redirect policy checked for each followed redirect (all redirects allowed if nil)
+	retry               RetryPolicy    // retry policy for transient failures
+	limiter             *hostLimiter   // per host rate limiter (no rate limit if nil)
+	hostDelay           *hostDelay     // per host politeness delay (no delay if nil)
+	concurrency         int            // maximum number of parallel requests in GetMultiple
+	orderedResults      bool           // pass GetMultiple results to the handler in order of requests
+	dedupe              bool           // crawl duplicate GetMultiple requests once, and pass the result to each of them
+	robots              *robotsChecker // robots.txt rules checker (robots.txt is ignored if nil)
+	tlsConfig           *tls.Config    // TLS configuration used by the crawler HTTP transport
+	tlsSessionCache     int            // TLS client session cache size for TLS session resumption (no cache if zero)
+	rootCAs             *x509.CertPool // root CAs used to verify server certificates (system root CAs if nil)
+	minTLSVersion       uint16         // minimum TLS version (TLS configuration default if zero)
+	insecureTLS         bool           // accept invalid server certificates, and report them on the response
+	keepAlive           bool           // reuse HTTP transport connections between requests
+	maxIdleConnsPerHost int            // maximum idle keep-alive connections per host (HTTP transport default if zero)
+	http2               bool           // attempt HTTP/2 connections
+	sniffCompression    bool           // decompress gzip response body even when Content-Encoding header is missing
+	maxBodySize         int64          // maximum number of bytes read from response body
+	maxDecompressedSize int64          // maximum size of decompressed response body
+	fallback            bool           // try www and HTTP variants of Ads.txt URL when the request fails
+	ignoreContentType   bool           // parse Ads.txt file even when response Content-Type is not text/plain
+	parseMode           ParseMode      // how malformed Ads.txt lines are handled (lenient by default)
+	fieldPolicy         FieldPolicy    // how data records with 2 fields or more than 4 fields are handled
+	sortRecords         bool           // sort parsed Ads.txt records (see Records.Sort)
+	profile             FetchProfile   // HTTP headers and cookie handling of Ads.txt requests
+	metrics             Metrics        // crawler metrics collector (no metrics if nil)
+	logger              Logger         // crawler events logger (no logging if nil)
+	logLevels           LogLevels      // log level of each crawler event
+	proxy               ProxyFunc      // proxy selection for each request (no proxy if nil)
+	resolver            *net.Resolver  // DNS resolver used by the crawler HTTP transport (system resolver if nil)
+	dialContext         DialFunc       // custom dial function used by the crawler HTTP transport
+	guard               *addressGuard  // private network addresses guard (addresses are not checked if nil)
+	progress            ProgressFunc   // batch crawl progress callback (no progress reporting if nil)
+	header              http.Header    // custom HTTP headers sent with each request
+	fetcher             Fetcher        // custom Ads.txt fetcher (Ads.txt files are fetched from remote host if nil)
+	minTTL              time.Duration  // minimum time until Ads.txt file expires
+	defaultExpiration   time.Duration  // time until Ads.txt file without expiration headers expires (DefaultExpiration if zero)
+	liveness            LivenessCheck  // domain liveness pre-check of batch crawls (no pre-check if zero)
+	boundedMemory       bool           // release response body and records of batch crawls once handled
+	livenessTimeout     time.Duration  // time limit of domain liveness pre-check
+	httpFallback        bool           // retry HTTPS request that failed at the TLS layer over plain HTTP
+	rangeLimit          int64          // fetch only the first bytes of Ads.txt file (whole file if zero)
+	dnsCache            DNSCache       // cache of resolved remote host addresses (no cache if nil)
+	htmlRedirects       bool           // follow meta refresh and JavaScript redirects of HTML pages
+	enrichers           []Enricher     // enrichment stages of successful Ads.txt responses
+	budget              Budget         // resource limits of batch crawls (no limits if zero)
+	throttle            ThrottlePolicy // rescheduling of batch crawl requests throttled by remote host
+
+	transformers  []RecordTransformer // transformers applied to each parsed Ads.txt data record
+	ipFamily      IPFamily            // address family of connections to remote host (any family if empty)
+	fallbackDelay time.Duration       // Happy Eyeballs fallback delay of the crawler HTTP transport dialer (dialer default if zero)
+	partialBody   bool                // return records parsed from body read before request timed out
+	tracer        Tracer              // tracer of Ads.txt request phases spans (no tracing if nil)
+	allowlist     *DomainList         // hosts the crawler may crawl (any host if nil)
+	blocklist     *DomainList         // hosts the crawler must not crawl (no blocked hosts if nil)
+	lifecycle     *lifecycle          // batch crawls in progress, and shutdown state (see Shutdown)
+	hooks         Hooks               // callbacks of Ads.txt request lifecycle stages
+	clock         Clock               // source of Ads.txt files fetch and expiration dates, and of Scheduler timers
+}
+
+// NewCrawler Create new crawler to fetch Ads.txt file from remote host. The crawler creates a single HTTP client,
+// that is safe to use from multiple goroutines and is shared by all its requests: create one crawler and reuse it for
+// all batches, so keep-alive connections (see WithKeepAlive) are pooled across GetMultiple calls
+func NewCrawler(opts ...Option) *Crawler {
+	c := &Crawler{
+		userAgent:           userAgent,
+		timeout:             time.Second * requestTimeout,
+		connectTimeout:      dialTimeout,
+		handshakeTimeout:    tlsHandshakeTimeout,
+		maxRedirects:        maxRedirects,
+		maxBodySize:         maxBodySize,
+		maxDecompressedSize: maxDecompressedSize,
+		concurrency:         runtime.NumCPU() * 5,
+		logLevels:           defaultLogLevels,
+		dedupe:              true,
+		throttle:            defaultThrottlePolicy,
+		lifecycle:           newLifecycle(),
+		clock:               systemClock{},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	c.client = c.newClient()
+	return c
+}
+
+// newClient create crawler HTTP client based on crawler options
+func (c *Crawler) newClient() *http.Client {
+	// Create client with required custom parameters.
+	// Options: Disable keep-alives (unless enabled by crawler options), 30sec n/w call timeout, do not follow redirects
+	// by default
+	client := &http.Client{Transport: c.newTransport()}
+
+	// use copy of custom HTTP client so we can change its settings without affecting the caller
+	if c.httpClient != nil {
+		custom := *c.httpClient
+		client = &custom
+	}
+
+	// crawler handles HTTP redirects by itself to follow Ads.txt specification redirect rules
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	client.Timeout = c.timeout
+
+	return client
+}
+
+// Get crawl and parse Ads.txt file from remote host
+func (c *Crawler) Get(req *Request) (*Response, error) {
+	return c.GetWithContext(context.Background(), req)
+}
+
+// GetWithContext crawl and parse Ads.txt file from remote host. The provided context controls the entire request,
+// including any redirects: canceling the context or exceeding its deadline aborts the request
+func (c *Crawler) GetWithContext(ctx context.Context, req *Request) (res *Response, err error) {
+	done := c.observe(req)
+	logged := c.logRequest(ctx, req)
+	ctx, traced := c.traceRequest(ctx, req)
+	defer func() {
+		traced(res, err)
+		logged(res, err)
+		done(res, err)
+	}()
+
+	// request Path and Port that were set without SetPath and SetPort (for example request decoded from JSON) are
+	// applied to request URL
+	if err := req.applyPath(); err != nil {
+		return nil, err
+	}
+	if err := req.applyPort(); err != nil {
+		return nil, err
+	}
+	// request may be modified, or short circuited, by the crawler OnRequest hook (see WithHooks)
+	if res, err = c.hooks.request(ctx, req); res != nil || err != nil {
+		return res, err
+	}
+
+	if timeout := c.timeoutOf(req); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	res, err = c.fetchSchemes(ctx, req)
+	if res != nil {
+		res.setEndpoint()
+	}
+	if err == nil && !res.NotModified {
+		c.enrich(ctx, req, res)
+	}
+	return res, err
+}
+
+// get crawl and parse Ads.txt file from remote host, following HTTP redirects
+func (c *Crawler) get(ctx context.Context, req *Request) (*Response, error) {
+	// total number of HTTP requests sent to remote host, including retries, and overall fetch duration
+	attempts := 0
+	start := c.clock.Now()
+
+	// redirect chain followed to the Ads.txt file, and whether it was delegated to third party host by redirect
+	// outside the original root domain
+	redirects := []*Redirect{}
+	delegated := false
+	// request was retried over plain HTTP after HTTPS connection failed at the TLS layer
+	insecure := false
+
+	// HTTP transport selects proxy by the Ads.txt request (request URL is updated in place when following redirects)
+	ctx = withRequest(ctx, req)
+	ctx = c.withCookieJar(ctx)
+
+	// follow HTTP redirect (or HTML redirect, see WithHTMLRedirects) of response to location, after checking that it
+	// is allowed: the request URL is updated to the redirect destination
+	follow := func(res *http.Response, location string, html bool) error {
+		if len(redirects) >= c.maxRedirects {
+			return &ErrRedirect{URL: req.URL, Location: location, Reason: ErrTooManyRedirects,
+				msg: fmt.Sprintf(errTooManyRedirects, req.Domain, c.maxRedirects, req.URL)}
+		}
+		redirect, err := c.resolveRedirect(req, res, location)
+		if err != nil {
+			return err
+		}
+		if err := c.checkRedirectPolicy(req, redirect, len(redirects)+1); err != nil {
+			return err
+		}
+
+		r := &Redirect{URL: req.URL, StatusCode: res.StatusCode, Location: redirect, HTML: html}
+		if err := c.hooks.redirect(req, r); err != nil {
+			return err
+		}
+		redirects = append(redirects, r)
+		delegated = !inRootDomain(redirect, req.Domain)
+		c.log(ctx, c.logLevels.Redirect, "Ads.txt request redirected", "domain", req.Domain, "url", req.URL,
+			"location", redirect, "status", res.StatusCode)
+		req.URL = redirect
+		return nil
+	}
+
+	// send Ads.txt request to remote server and parse response
+	for {
+		// check that Ads.txt URL host (or redirect destination) is allowed by the crawler allowlist and blocklist
+		if err := c.checkDomain(req); err != nil {
+			return nil, err
+		}
+
+		// check that Ads.txt URL is allowed by remote host robots.txt file
+		if c.robots != nil {
+			if err := c.robots.check(ctx, c, req); err != nil {
+				return nil, err
+			}
+		}
+
+		res, n, err := c.sendWithRetry(ctx, req)
+		attempts += n
+		countAttempts(ctx, n)
+		if err != nil {
+			// DNS lookup failure is reported separately from connection errors
+			var dnsErr *net.DNSError
+			if errors.As(err, &dnsErr) {
+				return nil, &ErrDNS{Host: dnsErr.Name, URL: req.URL, Err: err}
+			}
+			// remote host has no address of the crawler address family (see WithIPFamily)
+			if dnsErr := c.familyError(req, err); dnsErr != nil {
+				return nil, dnsErr
+			}
+			// retry HTTPS request that failed at the TLS layer over plain HTTP (see WithHTTPFallback)
+			if c.httpFallback && !insecure && ctx.Err() == nil {
+				if u, ok := insecureURL(req.URL, err); ok {
+					c.log(ctx, c.logLevels.Warning, "Ads.txt HTTPS request failed, retrying over HTTP", "domain", req.Domain,
+						"url", req.URL, "error", err)
+					req.URL, insecure = u, true
+					continue
+				}
+			}
+			return nil, &ErrRequest{URL: req.URL, Err: c.timeoutError(ctx, req, err)}
+		}
+		defer res.Body.Close()
+		if err := c.hooks.response(req, res); err != nil {
+			return nil, err
+		}
+
+		// handle Ads.txt response
+		switch {
+		// Ads.txt file did not change since previous crawl (conditional request)
+		case res.StatusCode == http.StatusNotModified:
+			records := &Records{DataRecords: []*DataRecord{}, Variables: []*Variable{}, Warnings: []*Warning{}, Body: []string{}}
+			r := c.newResponse(req, res, records, nil, attempts, start)
+			r.Redirects = redirects
+			r.OffDomainRedirect = delegated
+			r.NotModified = true
+			r.FetchedInsecurely = insecure
+			// server may omit validators from 304 response: keep the values of the conditional request
+			if len(r.ETag) == 0 {
+				r.ETag = req.IfNoneMatch
+			}
+			if len(r.LastModified) == 0 {
+				r.LastModified = req.IfModifiedSince
+			}
+			return r, nil
+		// nothing was appended to the Ads.txt file since the range request offset (see Request.RangeStart)
+		case c.isRangeAtEnd(req, res):
+			records := &Records{DataRecords: []*DataRecord{}, Variables: []*Variable{}, Warnings: []*Warning{}, Body: []string{}}
+			r := c.newResponse(req, res, records, nil, attempts, start)
+			r.Redirects = redirects
+			r.OffDomainRedirect = delegated
+			r.Partial = true
+			r.FetchedInsecurely = insecure
+			return r, nil
+		// the server response indicates redirect (301, 302, 307 status codes), follow redirect and read Ads.txt
+		// file from the source of the redirect
+		case 300 <= res.StatusCode && res.StatusCode < 400:
+			if err := follow(res, res.Header.Get("Location"), false); err != nil {
+				return nil, err
+			}
+		// remote host throttled the request: batch crawls reschedule it after its Retry-After delay
+		case res.StatusCode == http.StatusTooManyRequests:
+			return nil, throttledError(req, res)
+		// client error in remote server response
+		case 400 <= res.StatusCode && res.StatusCode < 500:
+			return nil, &ErrClientError{StatusCode: res.StatusCode, Status: res.Status, Domain: req.Domain, URL: req.URL}
+		// the server response indicates Success (HTTP Status Code 200): read and parse the content of the Ads.txt file.
+		// Partial content response of range request holds only the content from the request offset
+		case res.StatusCode == 200 || res.StatusCode == http.StatusPartialContent && c.isRangeRequest(req):
+			partial, err := c.isPartial(req, res)
+			if err != nil {
+				return nil, err
+			}
+			_, span := c.startSpan(ctx, SpanBody)
+			body, readErr := c.readBody(req, res)
+			span.SetAttribute(attrBodySize, len(body))
+			span.End(readErr)
+			// body read timed out mid-transfer: parse the complete lines read so far (see WithPartialBody)
+			truncated := readErr != nil && c.partialBody && len(body) > 0 && isTimeout(readErr)
+			if readErr != nil && !truncated {
+				return nil, readErr
+			}
+			// last line of partial content may be cut by the range limit, or by the read timeout
+			if partial && c.rangeLimit > 0 {
+				body = completeLines(res, body)
+			}
+			if truncated {
+				body = body[:bytes.LastIndexAny(body, "\r\n")+1]
+			}
+
+			// transcode non UTF-8 Ads.txt file (e.g. UTF-16 file saved by Windows tools) before parsing
+			text, charsetWarning := toUTF8(body, res.Header.Get("Content-Type"))
+
+			// soft 404: HTML error page served with success status, or HTML page that redirects to Ads.txt file using
+			// meta refresh or JavaScript (followed only if the crawler follows HTML redirects)
+			if htmlPage := c.htmlRedirects && isHTMLContentType(res); htmlPage || isHTML(text) {
+				location := htmlRedirect(text)
+				if c.htmlRedirects && len(location) > 0 {
+					if err := follow(res, location, true); err != nil {
+						return nil, err
+					}
+					continue
+				}
+				if isHTML(text) {
+					return nil, &ErrHTMLPage{URL: req.URL, Redirect: location}
+				}
+				// body with HTML Content-Type was read only to detect HTML redirect
+				if !c.ignoreContentType {
+					return nil, &ErrInvalidContentType{URL: req.URL, ContentType: res.Header.Get("Content-Type")}
+				}
+			}
+
+			// return new response
+			_, span = c.startSpan(ctx, SpanParse)
+			records, err := ParseBodyWithPolicy(text, c.fieldPolicy)
+			if err == nil {
+				records, err = checkParseMode(records, c.parseMode, req.URL)
+			}
+			if err != nil {
+				span.End(err)
+				return nil, err
+			}
+			records.Transform(c.transformers...)
+			span.SetAttribute(attrRecords, len(records.DataRecords))
+			span.End(nil)
+			if charsetWarning != nil {
+				records.Warnings = append(records.Warnings, charsetWarning)
+			}
+			if c.sortRecords {
+				records.Sort()
+			}
+			if err := c.hooks.parseComplete(req, records); err != nil {
+				return nil, err
+			}
+
+			// Ads.txt file with invalid Content-Type is parsed only when the crawler ignores Content-Type: warn about it
+			if err := checkContentType(req, res); err != nil {
+				records.Warnings = append(records.Warnings, &Warning{Level: LowSeverity, Message: err.Error()})
+			}
+
+			r := c.newResponse(req, res, records, body, attempts, start)
+			r.Redirects = redirects
+			r.OffDomainRedirect = delegated
+			r.Partial = partial
+			r.FetchedInsecurely = insecure
+			r.HTMLRedirected = htmlRedirected(redirects)
+			if truncated {
+				r.Truncated = true
+				return r, &ErrRequest{URL: req.URL, Err: c.timeoutError(ctx, req, readErr)}
+			}
+			return r, nil
+		// un known HTTP status
+		default:
+			return nil, &ErrServerError{StatusCode: res.StatusCode, Status: res.Status, Domain: req.Domain, URL: req.URL}
+		}
+	}
+}
+
+// newResponse return new Ads.txt response with expiration date, validators (ETag, Last-Modified) and metadata of the
+// final HTTP response
+func (c *Crawler) newResponse(req *Request, res *http.Response, records *Records, body []byte, attempts int, start time.Time) *Response {
+	r := &Response{
+		Request:  req,
+		Records:  records,
+		Attempts: attempts,
+		// Ads.txt file default expiration date (section 3.6 EXPIRATION of IAB Ads.txt specification), see setExpires
+		Expires:       c.now().UTC().Add(DefaultExpiration),
+		ETag:          res.Header.Get("ETag"),
+		LastModified:  res.Header.Get("Last-Modified"),
+		FinalURL:      req.URL,
+		StatusCode:    res.StatusCode,
+		Header:        res.Header,
+		RobotsTag:     robotsTagDirectives(res.Header, c.userAgent),
+		RequestHeader: requestHeader(res),
+		ContentLength: int64(len(body)),
+		Fetched:       c.now().UTC(),
+		Duration:      c.clock.Since(start),
+		RawBody:       body,
+		TLS:           c.tlsInfo(res),
+	}
+	r.RemoteAddr, r.AddressFamily = remoteAddr(res)
+	if body != nil {
+		r.SHA256 = bodyHash(body)
+	}
+
+	// parse Ads.txt expiration date from response Cache-Control or Expires headers (else default expiration time is used)
+	c.setExpires(r, res)
+
+	return r
+}
+
+// requestHeader return copy of the HTTP headers sent with the request of HTTP response
+func requestHeader(res *http.Response) http.Header {
+	if res.Request == nil {
+		return nil
+	}
+	return res.Request.Header.Clone()
+}
+
+// GetMultiple crawl and parse multiple Ads.txt files from remote hosts, and pass each response to the handler. All
+// requests are sent concurrently using the crawler HTTP client (see NewCrawler)
+func (c *Crawler) GetMultiple(req []*Request, h Handler) {
+	c.GetMultipleWithContext(context.Background(), req, h)
+}
+
+// GetMultipleWithContext crawl and parse multiple Ads.txt files from remote hosts. The provided context is used for
+// all requests: once it is canceled, in-flight requests are aborted and requests that were not sent yet are passed
+// to the handler with the context error
+func (c *Crawler) GetMultipleWithContext(ctx context.Context, req []*Request, h Handler) {
+	ctx = withFlushers(ctx, h)
+	c.getMultiple(ctx, req, c.progressHandler(c.boundedHandler(h), len(req)), c.GetWithContext)
 }
 
-// NewCrawler Create new crawler to fetch Ads.txt file from remote host
-func newCrawler() *crawler {
-	return &crawler{
-		// Create client with required custom parameters.
-		// Options: Disable keep-alives, 30sec n/w call timeout, do not follow redirects by default
-		client: &http.Client{
-			CheckRedirect: func(req *http.Request, via []*http.Request) error {
-				return http.ErrUseLastResponse
-			},
-			Transport: &http.Transport{
-				DisableKeepAlives: true,
-			},
-			Timeout: time.Second * requestTimeout,
-		},
-		UserAgent: userAgent,
+// Result of single Ads.txt request sent by GetMultipleChan
+type Result struct {
+	Request  *Request  // Request Ads.txt request
+	Response *Response // Response Ads.txt response (nil if the request failed)
+	Err      error     // Err request error
+}
+
+// GetMultipleChan crawl and parse multiple Ads.txt files from remote hosts (see GetMultipleWithContext), and send
+// each result to the returned channel, that is closed once all requests were handled. Results of requests that were
+// not sent since ctx is done are sent with the context error, so the caller should keep receiving from the channel
+// until it is closed
+func (c *Crawler) GetMultipleChan(ctx context.Context, req []*Request) <-chan Result {
+	results := make(chan Result, c.concurrency)
+
+	go func() {
+		defer close(results)
+		// results are received after they were handled, so they are not released in bounded memory mode
+		c.getMultiple(ctx, req, c.progressHandler(HandlerFunc(func(r *Request, res *Response, err error) {
+			results <- Result{Request: r, Response: res, Err: err}
+		}), len(req)), c.GetWithContext)
+	}()
+
+	return results
+}
+
+// GetMultipleStream crawl and parse Ads.txt requests received from req channel until it is closed, and pass each
+// response to the handler. Requests are crawled by a fixed pool of workers (see WithConcurrency), so memory use does
+// not depend on the number of requests: use GetMultipleStream to crawl very long lists of domains without holding all
+// requests in memory. Once ctx is done, requests that are still received are passed to the handler with the context
+// error, so the caller should keep sending requests (or close the channel)
+func (c *Crawler) GetMultipleStream(ctx context.Context, req <-chan *Request, h Handler) {
+	ctx = withFlushers(ctx, h)
+	c.getPool(ctx, req, c.progressHandler(c.boundedHandler(h), 0), c.GetWithContext)
+}
+
+// getMultiple send multiple Ads.txt requests in parallel using the specified get function, and pass each response
+// to the handler. Duplicate requests are crawled once, unless the crawler is set not to deduplicate requests
+func (c *Crawler) getMultiple(ctx context.Context, req []*Request, h Handler, get func(context.Context, *Request) (*Response, error)) {
+	if c.dedupe {
+		var duplicates map[*Request][]*Request
+		req, duplicates = dedupeRequests(req)
+		if len(duplicates) > 0 {
+			h = duplicatesHandler(h, duplicates)
+		}
+	}
+
+	requests := make(chan *Request)
+	go func() {
+		defer close(requests)
+		for _, r := range req {
+			requests <- r
+		}
+	}()
+
+	c.getPool(ctx, requests, h, get)
+}
+
+// getPool send Ads.txt requests received from req channel using fixed pool of workers, and pass each response to
+// the handler. getPool returns once req channel is closed and all results were passed to the handler. Once the crawler
+// shuts down, requests are not sent and their results are passed to the handler with ErrShutdown (see Shutdown)
+func (c *Crawler) getPool(ctx context.Context, req <-chan *Request, h Handler, get func(context.Context, *Request) (*Response, error)) {
+	ctx, b, end := c.lifecycle.start(ctx)
+	defer end()
+	closing := c.lifecycle.closing
+
+	// limit the number of requests handled in parallel: guard slot is taken for each request sent to the workers, and
+	// released once its result is passed to the handler
+	guard := make(chan struct{}, c.concurrency)
+	release := func() { <-guard }
+
+	// weed out dead domains before sending the Ads.txt requests (see WithLivenessCheck)
+	get = c.livenessGet(get)
+
+	// stop sending requests once the batch crawl budget is exhausted (see WithBudget)
+	budget := c.newBudgetTracker()
+	get = budget.track(get)
+
+	// pass request result to the handler as soon as it is ready, or in order of requests. Requests that were not
+	// processed due to shutdown are recorded for the shutdown summary
+	deliver := func(index int, r *multiResult) {
+		h.Handle(r.req, r.res, r.err)
+		if r.release {
+			release()
+		}
+	}
+	if c.orderedResults {
+		deliver = newResultSequencer(h, release).deliver
+	}
+	deliverResult := deliver
+	deliver = func(index int, r *multiResult) {
+		if r.err != nil {
+			c.lifecycle.unprocessed(b, r)
+		}
+		deliverResult(index, r)
+	}
+
+	// start fixed pool of workers, each crawl and parse single request at a time
+	jobs := make(chan *multiJob)
+	// requests sent to the workers, or rescheduled after remote host throttled them, whose result was not handled yet
+	var inflight sync.WaitGroup
+	var wg sync.WaitGroup
+	wg.Add(c.concurrency)
+	for i := 0; i < c.concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				res, err := get(ctx, j.req)
+				if delay, ok := c.rescheduleDelay(j.rescheduled, err); ok {
+					c.reschedule(ctx, j, delay, guard, jobs, budget, deliver, &inflight, closing)
+					continue
+				}
+
+				if j.rescheduled > 0 {
+					var throttled *ErrThrottled
+					if res != nil {
+						res.Rescheduled = j.rescheduled
+					} else if errors.As(err, &throttled) {
+						throttled.Rescheduled = j.rescheduled
+					}
+				}
+				deliver(j.index, &multiResult{req: j.req, res: res, err: err, release: true})
+				inflight.Done()
+			}
+		}()
+	}
+
+	index := 0
+	for r := range req {
+		// once the crawler shuts down, do not send any new request
+		if c.lifecycle.isClosing() {
+			deliver(index, &multiResult{req: r, err: ErrShutdown})
+			index++
+			continue
+		}
+
+		// block if guard channel is already filled, to avoid "too many" parallel requests at the same time
+		// once context is done, do not send any new request
+		select {
+		case guard <- struct{}{}:
+			if err := budget.spend(); err != nil {
+				deliver(index, &multiResult{req: r, err: err, release: true})
+				break
+			}
+			inflight.Add(1)
+			jobs <- &multiJob{index: index, req: r, url: r.URL}
+		case <-ctx.Done():
+			deliver(index, &multiResult{req: r, err: ctx.Err()})
+		case <-closing:
+			deliver(index, &multiResult{req: r, err: ErrShutdown})
+		}
+		index++
+	}
+
+	// Wait for all Requests to complete, including rescheduled requests
+	inflight.Wait()
+	close(jobs)
+	wg.Wait()
+}
+
+// reschedule send throttled request back to the workers after delay (see WithThrottlePolicy). The request guard slot
+// is released while it waits, so other requests are crawled in the meantime, unless results are passed to the
+// handler in order of requests: results of later requests wait for the throttled request, and hold their slots
+func (c *Crawler) reschedule(ctx context.Context, j *multiJob, delay time.Duration, guard chan struct{}, jobs chan<- *multiJob,
+	budget *budgetTracker, deliver func(int, *multiResult), inflight *sync.WaitGroup, closing <-chan struct{}) {
+	keep := c.orderedResults
+	if !keep {
+		<-guard
+	}
+
+	c.log(ctx, c.logLevels.Retry, "Ads.txt request throttled, rescheduled", "domain", j.req.Domain, "url", j.req.URL,
+		"rescheduled", j.rescheduled+1, "delay", delay)
+
+	// request URL is changed when following redirects: rescheduled request is sent to the original URL
+	j.req.URL = j.url
+	j.rescheduled++
+
+	go func() {
+		defer inflight.Done()
+
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			deliver(j.index, &multiResult{req: j.req, err: ctx.Err(), release: keep})
+			return
+		case <-closing:
+			deliver(j.index, &multiResult{req: j.req, err: ErrShutdown, release: keep})
+			return
+		}
+
+		if !keep {
+			select {
+			case guard <- struct{}{}:
+			case <-ctx.Done():
+				deliver(j.index, &multiResult{req: j.req, err: ctx.Err()})
+				return
+			case <-closing:
+				deliver(j.index, &multiResult{req: j.req, err: ErrShutdown})
+				return
+			}
+		}
+		if err := budget.spend(); err != nil {
+			deliver(j.index, &multiResult{req: j.req, err: err, release: true})
+			return
+		}
+
+		// rescheduled request is counted as in-flight until its result is handled by the worker
+		inflight.Add(1)
+		jobs <- j
+	}()
+}
+
+// multiJob single request sent to getPool workers
+type multiJob struct {
+	index       int // index of the request, in order of requests
+	req         *Request
+	url         string // original request URL, before redirects
+	rescheduled int    // number of times the request was rescheduled after remote host throttled it
+}
+
+// multiResult result of single request sent by getMultiple
+type multiResult struct {
+	req     *Request
+	res     *Response
+	err     error
+	release bool // release request guard slot once the result is handled
+}
+
+// resultSequencer pass getMultiple results to the handler in order of requests. Results that are ready before
+// results of previous requests are kept, with their guard slot, until all previous results are handled: this
+// limits the number of pending results to the number of parallel requests
+type resultSequencer struct {
+	results map[int]*multiResult
+	next    int // index of the next result to pass to the handler
+	h       Handler
+	release func()
+	mu      sync.Mutex
+}
+
+// newResultSequencer create new results sequencer
+func newResultSequencer(h Handler, release func()) *resultSequencer {
+	return &resultSequencer{results: map[int]*multiResult{}, h: h, release: release}
+}
+
+// deliver add result of request and pass all ready results to the handler in order of requests
+func (s *resultSequencer) deliver(index int, r *multiResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.results[index] = r
+	for {
+		ready, ok := s.results[s.next]
+		if !ok {
+			return
+		}
+		delete(s.results, s.next)
+		s.next++
+
+		s.h.Handle(ready.req, ready.res, ready.err)
+		if ready.release {
+			s.release()
+		}
 	}
 }
 
 // send HTTP request to fetch Ads.txt file from remote host
-func (c *crawler) sendRequest(req *Request) (*http.Response, error) {
-	httpRequest, err := http.NewRequest("GET", req.URL, nil)
+func (c *Crawler) sendRequest(ctx context.Context, req *Request) (*http.Response, error) {
+	// connection phases are traced (see WithTracer), and remote address of the connection is recorded on the response
+	// (see Response.AddressFamily)
+	ctx, traced := c.withTrace(ctx)
+	httpRequest, err := http.NewRequestWithContext(withRemoteConn(ctx), "GET", req.URL, nil)
 	if err != nil {
+		traced(err)
 		return nil, err
 	}
 
-	httpRequest.Header.Add("User-Agent", c.UserAgent)
-	httpRequest.Header.Add("Accept", "text/plain")
-	httpRequest.Header.Add("Accept-Charset", "utf-8")
-	httpRequest.Header.Add("Content-Type", "text/plain; charset=utf-8")
+	c.setProfileHeaders(httpRequest)
+	// response body is decompressed by the crawler (see readBody), so the HTTP transport does not decompress it
+	httpRequest.Header.Add("Accept-Encoding", "gzip, br")
+
+	// custom headers of the crawler, and then of the request, replace default headers with the same name
+	for name, values := range c.header {
+		httpRequest.Header[http.CanonicalHeaderKey(name)] = values
+	}
+	for name, values := range req.Headers {
+		httpRequest.Header[http.CanonicalHeaderKey(name)] = values
+	}
+	if len(req.UserAgent) > 0 {
+		httpRequest.Header.Set("User-Agent", req.UserAgent)
+	}
+
+	// conditional request for previously crawled Ads.txt file
+	if len(req.IfNoneMatch) > 0 {
+		httpRequest.Header.Add("If-None-Match", req.IfNoneMatch)
+	}
+	if len(req.IfModifiedSince) > 0 {
+		httpRequest.Header.Add("If-Modified-Since", req.IfModifiedSince)
+	}
+	// range request for content appended to previously crawled Ads.txt file
+	c.setRangeHeader(httpRequest, req)
+
+	// cookies set by remote host during the Ads.txt request are sent back (browser fetch profile)
+	jar := cookieJarFromContext(ctx)
+	if jar != nil {
+		for _, cookie := range jar.Cookies(httpRequest.URL) {
+			httpRequest.AddCookie(cookie)
+		}
+	}
 
 	res, err := c.client.Do(httpRequest)
+	traced(err)
 	if err != nil {
 		return nil, err
 	}
+	if jar != nil {
+		jar.SetCookies(httpRequest.URL, res.Cookies())
+	}
 
 	return res, nil
 }
 
 // handle HTTP redirect response: parse new redirect destination from HTTP response header
-func (c *crawler) handleRedirect(req *Request, res *http.Response) (string, error) {
-	redirect := res.Header.Get("Location")
+func (c *Crawler) handleRedirect(req *Request, res *http.Response) (string, error) {
+	return c.resolveRedirect(req, res, res.Header.Get("Location"))
+}
 
-	log.Printf("[%s]: redirect from [%s] to [%s]", res.Status, req.URL, redirect)
+// resolveRedirect return redirect destination of redirect location of HTTP response (HTTP Location header or HTML
+// redirect), after checking that it is allowed by Ads.txt specification redirect rules
+func (c *Crawler) resolveRedirect(req *Request, res *http.Response, redirect string) (string, error) {
+	// Location may be relative to the redirect response URL
+	if res.Request != nil && res.Request.URL != nil {
+		if u, err := res.Request.URL.Parse(redirect); err == nil {
+			redirect = u.String()
+		}
+	}
 
-	// Check if redirect destination has the same root domain as the request initial root doamin.
-	d, err := rootDomain(redirect)
-	if err != nil {
-		return "", fmt.Errorf(errFailToParseRedirect, req.Domain, req.URL, redirect, err.Error())
+	// redirect destination must have root domain, to check that it is within the request root domain scope
+	if _, err := rootDomain(redirect); err != nil {
+		return "", &ErrRedirect{URL: req.URL, Location: redirect, Reason: ErrInvalidRedirect,
+			msg: fmt.Sprintf(errFailToParseRedirect, req.Domain, req.URL, redirect, err.Error())}
 	}
 
 	// According to IAB ads.txt specification, section 3.1 "ACCESS METHOD":
@@ -90,55 +845,159 @@ func (c *crawler) handleRedirect(req *Request, res *http.Response) (string, erro
 	// the advertising system should follow the redirect and consume the data as authoritative for the source of the redirect,
 	// if and only if the redirect is within scope of the original root domain as defined above.
 	// Multiple redirects are valid as long as each redirect location remains within the original root domain."
-	if d != req.Domain {
-		// If redirect to different domain, check that this is the first redirect to different domain
-		// According to IAB ads.txt specification, section 3.1 "ACCESS METHOD":
-		// "Only a single HTTP redirect to a destination outside the original root domain is allowed to
-		// facilitate one-hop delegation of authority to a third party's web server domain."
+	// "Only a single HTTP redirect to a destination outside the original root domain is allowed to
+	// facilitate one-hop delegation of authority to a third party's web server domain."
+	// Redirects within the original root domain (compared by the Public Suffix List, see rootDomain) are followed up
+	// to the redirects limit, while the third party host of one-hop delegation must serve the file without any
+	// further redirect, even back to the original root domain
+	if !inRootDomain(req.URL, req.Domain) {
 		prevDomain, _ := rootDomain(req.URL)
-		if prevDomain != req.Domain && prevDomain != d {
-			return "", fmt.Errorf(errRedirectToDifferentDomain, req.Domain, prevDomain, d)
-		}
+		d, _ := rootDomain(redirect)
+		return "", &ErrRedirect{URL: req.URL, Location: redirect, Reason: ErrRedirectOutOfScope,
+			msg: fmt.Sprintf(errRedirectToDifferentDomain, req.Domain, prevDomain, d)}
 	}
 
-	// make sure redirects takes us to another Ads.txt file and not just to home page
-	if !strings.HasSuffix(redirect, "/ads.txt") {
-		return "", fmt.Errorf(errRedirectToInvalidAdsTxt, req.Domain, req.URL, redirect)
+	// make sure redirects takes us to another Ads.txt (or app-ads.txt) file and not just to home page
+	if !strings.HasSuffix(redirect, req.fileName()) {
+		return "", &ErrRedirect{URL: req.URL, Location: redirect, Reason: ErrInvalidRedirect,
+			msg: fmt.Sprintf(errRedirectToInvalidAdsTxt, req.Domain, req.URL, redirect)}
 	}
 
 	return redirect, nil
 }
 
 // Read HTTP response body
-func (c *crawler) readBody(req *Request, res *http.Response) ([]byte, error) {
+func (c *Crawler) readBody(req *Request, res *http.Response) ([]byte, error) {
 	// The HTTP Content-type should be ‘text/plain’, and all other Content-types should be treated as
-	// an error and the content ignored
-	contentType := res.Header.Get("Content-Type")
-	if strings.Index(contentType, "text/plain") != 0 {
-		return nil, fmt.Errorf(errHTTPBadContentType, req.URL, contentType)
+	// an error and the content ignored (unless the crawler is set to ignore Content-Type)
+	if err := checkContentType(req, res); err != nil && !c.ignoreContentType && !(c.htmlRedirects && isHTMLContentType(res)) {
+		return nil, err
 	}
 
-	// read response body
-	body, err := ioutil.ReadAll(res.Body)
+	// read response body, and decompress it according to its Content-Encoding. Body read before read error is
+	// returned with the error
+	body, err := c.decompress(res)
 	if err != nil {
-		return nil, err
+		return body, err
+	}
+
+	// some misconfigured servers gzip Ads.txt file but do not set Content-Encoding header: check body for gzip
+	// magic bytes and decompress it, or fallback to plain text if decompression fails
+	if c.sniffCompression && isGzip(body) {
+		if unzipped, err := c.gunzip(body); err == nil {
+			return unzipped, nil
+		} else if errors.Is(err, ErrDecompressedTooLarge) {
+			return nil, err
+		}
 	}
 
 	return body, nil
 }
 
-// parse Ads.txt file expiration date from the response Expires header
-func (c *crawler) parseExpires(res *http.Response) (time.Time, error) {
-	expires := res.Header.Get("Expires")
-	if len(expires) == 0 {
-		return time.Time{}, fmt.Errorf("Failed to parse expires from response header")
+// decompress read HTTP response body and decompress it according to response Content-Encoding (gzip or br). Body
+// without Content-Encoding, or with any other encoding, is returned as is
+func (c *Crawler) decompress(res *http.Response) ([]byte, error) {
+	// response body is read up to crawler maximum body size (see WithMaxBodySize)
+	if res.ContentLength > c.maxBodySize {
+		return nil, ErrBodyTooLarge
 	}
+	raw := &countingReader{r: io.LimitReader(res.Body, c.maxBodySize+1)}
+
+	body, err := c.decode(res.Header.Get("Content-Encoding"), raw, res.ContentLength)
+	if raw.n > c.maxBodySize {
+		return nil, ErrBodyTooLarge
+	}
+	return body, err
+}
+
+// decode read response body and decode it according to the response Content-Encoding. size is the response
+// Content-Length (negative if unknown), the expected size of body without Content-Encoding
+func (c *Crawler) decode(encoding string, r io.Reader, size int64) ([]byte, error) {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "gzip", "x-gzip":
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		return c.readDecompressed(zr)
+	case "br":
+		return c.readDecompressed(brotli.NewReader(r))
+	}
+	return readAll(r, size)
+}
+
+// countingReader count the number of bytes read from the underlying reader
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
 
-	parsedHeader, err := http.ParseTime(expires)
+// readDecompressed read decompressed body up to crawler maximum decompressed size (see WithMaxDecompressedSize)
+func (c *Crawler) readDecompressed(r io.Reader) ([]byte, error) {
+	body, err := readAll(io.LimitReader(r, c.maxDecompressedSize+1), 0)
 	if err != nil {
-		log.Printf("[%s] Error when parsing HTTP expires header from response [%s]", res.Request.URL, err.Error())
-		return time.Time{}, err
+		return body, err
+	}
+	if int64(len(body)) > c.maxDecompressedSize {
+		return nil, ErrDecompressedTooLarge
+	}
+	return body, nil
+}
+
+// checkContentType check that HTTP response Content-Type media type is text/plain (case insensitive, any parameters
+// such as charset are allowed)
+func checkContentType(req *Request, res *http.Response) error {
+	contentType := res.Header.Get("Content-Type")
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil && mediaType == "text/plain" {
+		return nil
+	}
+	return &ErrInvalidContentType{URL: req.URL, ContentType: contentType}
+}
+
+// isHTML check if body is HTML document: starts with HTML doctype or tag, ignoring leading whitespaces, byte order
+// mark and comments
+func isHTML(body []byte) bool {
+	b := bytes.TrimLeft(bytes.TrimPrefix(body, []byte("\xef\xbb\xbf")), " \t\r\n")
+	for bytes.HasPrefix(b, []byte("<!--")) {
+		end := bytes.Index(b, []byte("-->"))
+		if end == -1 {
+			return false
+		}
+		b = bytes.TrimLeft(b[end+3:], " \t\r\n")
+	}
+
+	if len(b) > 16 {
+		b = b[:16]
+	}
+	b = bytes.ToLower(b)
+
+	for _, prefix := range []string{"<!doctype html", "<html", "<head", "<body"} {
+		if bytes.HasPrefix(b, []byte(prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isGzip check if body starts with gzip magic bytes (1f 8b)
+func isGzip(body []byte) bool {
+	return len(body) >= 2 && body[0] == 0x1f && body[1] == 0x8b
+}
+
+// gunzip decompress gzip body
+func (c *Crawler) gunzip(body []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
 	}
+	defer r.Close()
 
-	return parsedHeader, nil
+	return c.readDecompressed(r)
 }
@@ -1,11 +1,21 @@
 package adstxt
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"runtime"
+	"strings"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/andybalholm/brotli"
 )
 
 // TestSendAndParseRquest test send HTTP request to remote host to Get Ads.txt file
@@ -23,8 +33,8 @@ func TestSendAndParseRquest(t *testing.T) {
 	req, _ := NewRequest(ts.URL)
 
 	// test send request
-	c := newCrawler()
-	res, err := c.sendRequest(req)
+	c := NewCrawler()
+	res, err := c.sendRequest(context.Background(), req)
 	if err != nil {
 		t.Error(err)
 	}
@@ -55,8 +65,8 @@ func TestHandleRedirect(t *testing.T) {
 	req, _ := NewRequest(ts.URL)
 
 	// test send request
-	c := newCrawler()
-	res, err := c.sendRequest(req)
+	c := NewCrawler()
+	res, err := c.sendRequest(context.Background(), req)
 	if err != nil {
 		t.Error(err)
 	}
@@ -89,15 +99,15 @@ func TestParseExpires(t *testing.T) {
 	req, _ := NewRequest(ts.URL)
 
 	// test send request
-	c := newCrawler()
-	res, err := c.sendRequest(req)
+	c := NewCrawler()
+	res, err := c.sendRequest(context.Background(), req)
 	if err != nil {
 		t.Error(err)
 	}
 
 	defer res.Body.Close()
 
-	expires, err := c.parseExpires(res)
+	expires, _, err := c.parseExpires(res)
 	if err != nil {
 		t.Error(err)
 	}
@@ -107,3 +117,789 @@ func TestParseExpires(t *testing.T) {
 	}
 
 }
+
+// TestParseExpiresCacheControl test parse Ads.txt file expires from HTTP response Cache-Control header
+func TestParseExpiresCacheControl(t *testing.T) {
+	expires := time.Now().AddDate(60, 0, 0).Format(http.TimeFormat)
+	tests := []struct {
+		header   http.Header
+		expected time.Duration
+	}{
+		{http.Header{"Cache-Control": []string{"public, max-age=3600"}}, time.Hour},
+		{http.Header{"Cache-Control": []string{"max-age=3600, s-maxage=60"}}, time.Minute},
+		{http.Header{"Cache-Control": []string{"public", `max-age="7200"`}}, 2 * time.Hour},
+		{http.Header{"Cache-Control": []string{"max-age=3600"}, "Age": []string{"600"}}, 50 * time.Minute},
+		{http.Header{"Cache-Control": []string{"max-age=60"}, "Age": []string{"600"}}, 0},
+		{http.Header{"Cache-Control": []string{"max-age=3600"}, "Expires": []string{expires}}, time.Hour},
+	}
+
+	c := NewCrawler()
+	for _, test := range tests {
+		res := &http.Response{Header: test.header, Request: httptest.NewRequest(http.MethodGet, "http://example.com/ads.txt", nil)}
+		parsed, _, err := c.parseExpires(res)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := time.Until(parsed); d > test.expected || d < test.expected-time.Minute {
+			t.Errorf("Expected Cache-Control %v to expire in [%s] and not [%s]", test.header, test.expected, d)
+		}
+	}
+
+	// without freshness directive, Expires header is used
+	res := &http.Response{Header: http.Header{"Cache-Control": []string{"no-transform"}, "Expires": []string{expires}}}
+	if parsed, _, err := c.parseExpires(res); err != nil || parsed.Format(http.TimeFormat) != expires {
+		t.Errorf("Expected Expires header to be used without Cache-Control max-age")
+	}
+
+	// without both headers, default expiration is used
+	res = &http.Response{Header: http.Header{"Cache-Control": []string{"max-age=invalid"}}}
+	if _, _, err := c.parseExpires(res); err == nil {
+		t.Errorf("Expected error without Cache-Control max-age and Expires headers")
+	}
+}
+
+// TestRequestHeaders test sending crawler and request custom headers and User-Agent
+func TestRequestHeaders(t *testing.T) {
+	var received http.Header
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, "greenadexchange.com,XF7342,DIRECT")
+	}))
+	defer ts.Close()
+
+	c := NewCrawler(WithUserAgent("crawler/1.0"), WithHeader("X-Cdn-Token", "crawler"), WithHeader("accept", "text/*"))
+
+	// crawler headers replace default headers
+	res, err := c.Get(&Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if received.Get("User-Agent") != "crawler/1.0" || received.Get("X-Cdn-Token") != "crawler" || received.Get("Accept") != "text/*" {
+		t.Errorf("Expected crawler headers to be sent and not %v", received)
+	}
+	if res.RequestHeader.Get("X-Cdn-Token") != "crawler" || res.RequestHeader.Get("Accept-Encoding") != "gzip, br" {
+		t.Errorf("Expected response to hold the sent request headers and not %v", res.RequestHeader)
+	}
+
+	// request headers and User-Agent replace crawler headers
+	req := &Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1", UserAgent: "Mozilla/5.0",
+		Headers: http.Header{"x-cdn-token": []string{"request"}, "X-Extra": []string{"1", "2"}}}
+	res, err = c.Get(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if received.Get("User-Agent") != "Mozilla/5.0" || received.Get("X-Cdn-Token") != "request" || len(received["X-Extra"]) != 2 {
+		t.Errorf("Expected request headers to be sent and not %v", received)
+	}
+	if res.RequestHeader.Get("User-Agent") != "Mozilla/5.0" {
+		t.Errorf("Expected response to hold the sent User-Agent and not [%s]", res.RequestHeader.Get("User-Agent"))
+	}
+}
+
+// TestReadBodySniffCompression test crawler decompress gzip response body without Content-Encoding header
+func TestReadBodySniffCompression(t *testing.T) {
+	const expected = "greenadexchange.com,XF7342,DIRECT"
+
+	var b bytes.Buffer
+	zw := gzip.NewWriter(&b)
+	io.WriteString(zw, expected)
+	zw.Close()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write(b.Bytes())
+	}))
+	defer ts.Close()
+
+	// request mock
+	req := &Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"}
+
+	// by default response body is not decompressed
+	c := NewCrawler()
+	res, err := c.sendRequest(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	body, err := c.readBody(req, res)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !bytes.Equal(body, b.Bytes()) {
+		t.Errorf("Expected response body to be returned as is when compression sniffing is disabled")
+	}
+
+	// decompress response body when compression sniffing is enabled
+	c = NewCrawler(WithSniffCompression(true))
+	res, err = c.sendRequest(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	body, err = c.readBody(req, res)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if string(body) != expected {
+		t.Errorf("Expected response body [%s] to be \"%s\"", string(body), expected)
+	}
+}
+
+// TestReadBodyContentEncoding test crawler decompress gzip and brotli response body according to Content-Encoding
+func TestReadBodyContentEncoding(t *testing.T) {
+	const expected = "greenadexchange.com,XF7342,DIRECT"
+
+	var gz, br bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	io.WriteString(zw, expected)
+	zw.Close()
+	bw := brotli.NewWriter(&br)
+	io.WriteString(bw, expected)
+	bw.Close()
+	bodies := map[string][]byte{"gzip": gz.Bytes(), "br": br.Bytes(), "": []byte(expected)}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "gzip, br" {
+			t.Errorf("Expected request to accept gzip and br encodings and not [%s]", r.Header.Get("Accept-Encoding"))
+		}
+		encoding := r.URL.Query().Get("encoding")
+		w.Header().Set("Content-Type", "text/plain")
+		if len(encoding) > 0 {
+			w.Header().Set("Content-Encoding", encoding)
+		}
+		w.Write(bodies[encoding])
+	}))
+	defer ts.Close()
+
+	c := NewCrawler()
+	for encoding := range bodies {
+		req := &Request{URL: ts.URL + "/ads.txt?encoding=" + encoding, Domain: "127.0.0.1"}
+		res, err := c.sendRequest(context.Background(), req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+
+		body, err := c.readBody(req, res)
+		if err != nil {
+			t.Error(err)
+		}
+		if string(body) != expected {
+			t.Errorf("Expected [%s] response body [%s] to be \"%s\"", encoding, string(body), expected)
+		}
+	}
+}
+
+// TestReadBodyMaxDecompressedSize test crawler stop decompressing response body that exceeds the maximum size
+func TestReadBodyMaxDecompressedSize(t *testing.T) {
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write(bytes.Repeat([]byte("#"), 1<<20))
+	zw.Close()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		if r.URL.Query().Get("sniff") == "" {
+			w.Header().Set("Content-Encoding", "gzip")
+		}
+		w.Write(gz.Bytes())
+	}))
+	defer ts.Close()
+
+	c := NewCrawler(WithMaxDecompressedSize(1024), WithSniffCompression(true))
+	for _, url := range []string{ts.URL + "/ads.txt", ts.URL + "/ads.txt?sniff=1"} {
+		req := &Request{URL: url, Domain: "127.0.0.1"}
+		res, err := c.sendRequest(context.Background(), req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+
+		if _, err := c.readBody(req, res); !errors.Is(err, ErrDecompressedTooLarge) {
+			t.Errorf("Expected [%s] decompressed body to exceed maximum size and not [%v]", url, err)
+		}
+	}
+}
+
+// TestReadBodyMaxBodySize test crawler stop reading response body that exceeds the maximum size
+func TestReadBodyMaxBodySize(t *testing.T) {
+	body := bytes.Repeat([]byte("greenadexchange.com,XF7342,DIRECT\n"), 100)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		// chunked response has no Content-Length, so the body size is known only while reading it
+		if r.URL.Query().Get("chunked") != "" {
+			w.Write(body[:10])
+			w.(http.Flusher).Flush()
+			w.Write(body[10:])
+			return
+		}
+		w.Write(body)
+	}))
+	defer ts.Close()
+
+	c := NewCrawler(WithMaxBodySize(int64(len(body) - 1)))
+	for _, url := range []string{ts.URL + "/ads.txt", ts.URL + "/ads.txt?chunked=1"} {
+		req := &Request{URL: url, Domain: "127.0.0.1"}
+		res, err := c.sendRequest(context.Background(), req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+
+		if _, err := c.readBody(req, res); !errors.Is(err, ErrBodyTooLarge) {
+			t.Errorf("Expected [%s] body to exceed maximum size and not [%v]", url, err)
+		}
+	}
+
+	// body of exactly the maximum size is read
+	c = NewCrawler(WithMaxBodySize(int64(len(body))))
+	req := &Request{URL: ts.URL + "/ads.txt?chunked=1", Domain: "127.0.0.1"}
+	res, err := c.sendRequest(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	if b, err := c.readBody(req, res); err != nil || !bytes.Equal(b, body) {
+		t.Errorf("Expected body of maximum size to be read [%v]", err)
+	}
+}
+
+// TestReadBodySniffCompressionFallback test crawler fallback to plain text when body is not a valid gzip stream
+func TestReadBodySniffCompressionFallback(t *testing.T) {
+	expected := []byte{0x1f, 0x8b, 'n', 'o', 't', ' ', 'g', 'z', 'i', 'p'}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write(expected)
+	}))
+	defer ts.Close()
+
+	// request mock
+	req := &Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"}
+
+	c := NewCrawler(WithSniffCompression(true))
+	res, err := c.sendRequest(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	body, err := c.readBody(req, res)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !bytes.Equal(body, expected) {
+		t.Errorf("Expected response body [%v] to be [%v]", body, expected)
+	}
+}
+
+// roundTripperFunc mock HTTP transport
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// TestCrawlerOptions test creating crawler with custom User-Agent and HTTP client
+func TestCrawlerOptions(t *testing.T) {
+	const ua = "test-crawler/1.0"
+
+	var sent *http.Request
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			sent = req
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       io.NopCloser(strings.NewReader("greenadexchange.com,XF7342,DIRECT")),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	c := NewCrawler(WithUserAgent(ua), WithHTTPClient(client), WithTimeout(time.Second))
+	if c.client == client {
+		t.Errorf("Expected crawler to use a copy of the custom HTTP client")
+	}
+	if c.client.Timeout != time.Second {
+		t.Errorf("Expected crawler HTTP client timeout to be [%s] and not [%s]", time.Second, c.client.Timeout)
+	}
+
+	res, err := c.Get(&Request{URL: "http://example.com/ads.txt", Domain: "example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sent == nil || sent.Header.Get("User-Agent") != ua {
+		t.Errorf("Expected crawler to send request with User-Agent [%s]", ua)
+	}
+	if len(res.DataRecords) != 1 {
+		t.Errorf("Expected single DataRecord but found [%d]", len(res.DataRecords))
+	}
+}
+
+// TestCrawlerMaxRedirects test crawler stops following redirects once max redirects limit is reached
+func TestCrawlerMaxRedirects(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", "http://gotest.com/ads.txt")
+		w.WriteHeader(http.StatusMovedPermanently)
+	}))
+	defer ts.Close()
+
+	// request mock
+	req := &Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"}
+
+	c := NewCrawler(WithMaxRedirects(0))
+	_, err := c.Get(req)
+	if !errors.Is(err, ErrTooManyRedirects) {
+		t.Errorf("Expected request to fail with redirect error and not [%v]", err)
+	}
+}
+
+// TestCrawlerConcurrency test GetMultiple does not exceed the crawler concurrency limit
+func TestCrawlerConcurrency(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, "greenadexchange.com,XF7342,DIRECT")
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+	}))
+	defer ts.Close()
+
+	requests := make([]*Request, 10)
+	for i := range requests {
+		requests[i] = &Request{URL: fmt.Sprintf("%s/%d/ads.txt", ts.URL, i), Domain: "127.0.0.1"}
+	}
+
+	NewCrawler(WithConcurrency(2)).GetMultiple(requests, HandlerFunc(func(req *Request, res *Response, err error) {
+		if err != nil {
+			t.Error(err)
+		}
+	}))
+
+	if maxInFlight > 2 {
+		t.Errorf("Expected at most [2] parallel requests and not [%d]", maxInFlight)
+	}
+}
+
+// TestCrawlerOrderedResults test GetMultiple passes results to the handler in order of requests
+func TestCrawlerOrderedResults(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// first requests are the slowest
+		var i int
+		fmt.Sscanf(r.URL.Path, "/%d/ads.txt", &i)
+		time.Sleep(time.Duration(10-i) * 5 * time.Millisecond)
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, "greenadexchange.com,XF7342,DIRECT")
+	}))
+	defer ts.Close()
+
+	requests := make([]*Request, 10)
+	for i := range requests {
+		requests[i] = &Request{URL: fmt.Sprintf("%s/%d/ads.txt", ts.URL, i), Domain: "127.0.0.1"}
+	}
+
+	handled := []*Request{}
+	NewCrawler(WithConcurrency(4), WithOrderedResults(true)).GetMultiple(requests, HandlerFunc(func(req *Request, res *Response, err error) {
+		handled = append(handled, req)
+	}))
+
+	if len(handled) != len(requests) {
+		t.Fatalf("Expected [%d] handled requests and not [%d]", len(requests), len(handled))
+	}
+	for i := range requests {
+		if handled[i] != requests[i] {
+			t.Errorf("Expected request #%d to be handled in order", i)
+		}
+	}
+}
+
+// TestGetMultipleStream test crawling requests received from channel by fixed pool of workers
+func TestGetMultipleStream(t *testing.T) {
+	const concurrency, total = 4, 2000
+
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			inFlight++
+			if inFlight > maxInFlight {
+				maxInFlight = inFlight
+			}
+			mu.Unlock()
+			defer func() {
+				mu.Lock()
+				inFlight--
+				mu.Unlock()
+			}()
+
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       io.NopCloser(strings.NewReader("greenadexchange.com,XF7342,DIRECT")),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	requests := make(chan *Request)
+	go func() {
+		defer close(requests)
+		for i := 0; i < total; i++ {
+			requests <- &Request{URL: fmt.Sprintf("http://example%d.com/ads.txt", i), Domain: fmt.Sprintf("example%d.com", i)}
+		}
+	}()
+
+	// goroutines number does not depend on the number of requests
+	base := runtime.NumGoroutine()
+	n, maxGoroutines := 0, 0
+	c := NewCrawler(WithHTTPClient(client), WithConcurrency(concurrency), WithOrderedResults(true))
+	c.GetMultipleStream(context.Background(), requests, HandlerFunc(func(req *Request, res *Response, err error) {
+		if err != nil {
+			t.Error(err)
+		}
+		if expected := fmt.Sprintf("example%d.com", n); req.Domain != expected {
+			t.Errorf("Expected result of [%s] and not [%s]", expected, req.Domain)
+		}
+		n++
+		if g := runtime.NumGoroutine(); g > maxGoroutines {
+			maxGoroutines = g
+		}
+	}))
+
+	if n != total {
+		t.Errorf("Expected [%d] results and not [%d]", total, n)
+	}
+	if maxInFlight > concurrency {
+		t.Errorf("Expected at most [%d] parallel requests and not [%d]", concurrency, maxInFlight)
+	}
+	if maxGoroutines > base+concurrency+10 {
+		t.Errorf("Expected goroutines number to be bound by crawler concurrency and not [%d]", maxGoroutines-base)
+	}
+}
+
+// TestGetMultipleChan test receiving GetMultiple results from channel
+func TestGetMultipleChan(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing/ads.txt" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, "greenadexchange.com,XF7342,DIRECT")
+	}))
+	defer ts.Close()
+
+	requests := []*Request{}
+	for _, p := range []string{"a", "b", "missing"} {
+		requests = append(requests, &Request{URL: fmt.Sprintf("%s/%s/ads.txt", ts.URL, p), Domain: "127.0.0.1"})
+	}
+
+	succeeded, failed := 0, 0
+	for r := range NewCrawler(WithConcurrency(2)).GetMultipleChan(context.Background(), requests) {
+		if r.Request == nil {
+			t.Errorf("Expected result to include its request")
+		}
+		if r.Err != nil {
+			failed++
+			continue
+		}
+		if r.Response == nil || len(r.Response.DataRecords) != 1 {
+			t.Errorf("Expected result to include Ads.txt response of [%s]", r.Request.URL)
+		}
+		succeeded++
+	}
+
+	if succeeded != 2 || failed != 1 {
+		t.Errorf("Expected [2] successful and [1] failed results and not [%d] [%d]", succeeded, failed)
+	}
+
+	// results of requests that were not sent are received with the context error
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	n := 0
+	for r := range NewCrawler().GetMultipleChan(ctx, requests) {
+		if !errors.Is(r.Err, context.Canceled) {
+			t.Errorf("Expected canceled request error and not [%v]", r.Err)
+		}
+		n++
+	}
+	if n != len(requests) {
+		t.Errorf("Expected [%d] results and not [%d]", len(requests), n)
+	}
+}
+
+// TestConditionalRequest test sending conditional request with Ads.txt file validators and handling 304 response
+func TestConditionalRequest(t *testing.T) {
+	const etag, lastModified = `"v1"`, "Wed, 21 Oct 2015 07:28:00 GMT"
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == etag && r.Header.Get("If-Modified-Since") == lastModified {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", lastModified)
+		io.WriteString(w, "greenadexchange.com,XF7342,DIRECT")
+	}))
+	defer ts.Close()
+
+	c := NewCrawler()
+	req := &Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"}
+
+	res, err := c.Get(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.NotModified || res.ETag != etag || res.LastModified != lastModified {
+		t.Errorf("Expected response to hold Ads.txt file validators [%s] [%s]", res.ETag, res.LastModified)
+	}
+
+	res, err = c.Get(res.Conditional(req))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.NotModified || len(res.DataRecords) != 0 {
+		t.Errorf("Expected not modified response with no records")
+	}
+	if res.ETag != etag || res.LastModified != lastModified {
+		t.Errorf("Expected not modified response to keep Ads.txt file validators [%s] [%s]", res.ETag, res.LastModified)
+	}
+}
+
+// TestResponseChanged test detecting changed Ads.txt file by the response body hash
+func TestResponseChanged(t *testing.T) {
+	body := "greenadexchange.com,XF7342,DIRECT"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, body)
+	}))
+	defer ts.Close()
+
+	c := NewCrawler()
+	req := &Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"}
+
+	first, err := c.Get(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// sha256 of the Ads.txt body
+	if first.SHA256 != "9c744a29de6af7b635a1caad2cdede13ed8cc7be3d1d5386d09f5902e186e212" {
+		t.Errorf("Expected SHA-256 hash of the response body and not [%s]", first.SHA256)
+	}
+	if !first.Changed(nil) {
+		t.Errorf("Expected response with no previous response to be changed")
+	}
+
+	second, err := c.Get(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Changed(first) {
+		t.Errorf("Expected response with the same body not to be changed")
+	}
+
+	body = "greenadexchange.com,XF7342,RESELLER"
+	third, err := c.Get(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !third.Changed(second) {
+		t.Errorf("Expected response with different body to be changed")
+	}
+
+	// responses without hash are compared by the Ads.txt file lines
+	second.SHA256, third.SHA256 = "", ""
+	if second.Changed(first) || !third.Changed(first) {
+		t.Errorf("Expected responses without hash to be compared by Ads.txt file lines")
+	}
+	if (&Response{NotModified: true}).Changed(first) {
+		t.Errorf("Expected NotModified response not to be changed")
+	}
+}
+
+// TestResponseMetadata test Response holds metadata of the final HTTP response
+func TestResponseMetadata(t *testing.T) {
+	const body = "greenadexchange.com,XF7342,DIRECT"
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("X-Test", "test")
+		io.WriteString(w, body)
+	}))
+	defer ts.Close()
+
+	res, err := NewCrawler().Get(&Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.FinalURL != ts.URL+"/ads.txt" || res.StatusCode != http.StatusOK {
+		t.Errorf("Unexpected final URL [%s] or status code [%d]", res.FinalURL, res.StatusCode)
+	}
+	if res.Header.Get("X-Test") != "test" {
+		t.Errorf("Expected response to hold HTTP response headers [%v]", res.Header)
+	}
+	if res.ContentLength != int64(len(body)) || string(res.RawBody) != body {
+		t.Errorf("Expected response raw body [%s] of [%d] bytes and not [%s]", body, len(body), string(res.RawBody))
+	}
+	if res.Duration <= 0 {
+		t.Errorf("Expected response fetch duration to be set")
+	}
+}
+
+// TestRedirectChain test Response holds the chain of followed redirects, and that only a single redirect outside the
+// original root domain is allowed
+func TestRedirectChain(t *testing.T) {
+	locations := map[string]string{
+		"example.com":      "http://www.example.com/ads.txt",
+		"www.example.com":  "/other/ads.txt",
+		"test.com":         "http://cdn.delegate.com/ads.txt",
+		"cdn.delegate.com": "http://www.delegate.com/ads.txt",
+		"delegated.com":    "http://cdn.adhost.net/ads.txt",
+		"back.com":         "http://cdn.backhost.net/ads.txt",
+		"cdn.backhost.net": "http://www.back.com/ads.txt",
+	}
+
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if l, ok := locations[req.URL.Host]; ok && req.URL.Path == "/ads.txt" {
+				return &http.Response{StatusCode: http.StatusFound, Status: "302 Found", Header: http.Header{"Location": []string{l}}, Body: http.NoBody, Request: req}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       io.NopCloser(strings.NewReader("greenadexchange.com,XF7342,DIRECT")),
+				Request:    req,
+			}, nil
+		}),
+	}
+	c := NewCrawler(WithHTTPClient(client))
+
+	res, err := c.Get(&Request{URL: "http://example.com/ads.txt", Domain: "example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []*Redirect{
+		&Redirect{URL: "http://example.com/ads.txt", StatusCode: http.StatusFound, Location: "http://www.example.com/ads.txt"},
+		&Redirect{URL: "http://www.example.com/ads.txt", StatusCode: http.StatusFound, Location: "http://www.example.com/other/ads.txt"},
+	}
+	if len(res.Redirects) != len(expected) {
+		t.Fatalf("Expected [%d] redirects and not [%d]", len(expected), len(res.Redirects))
+	}
+	for index, r := range res.Redirects {
+		if *r != *expected[index] {
+			t.Errorf("Expected redirect [%v] and not [%v]", *expected[index], *r)
+		}
+	}
+	if res.FinalURL != "http://www.example.com/other/ads.txt" || res.OffDomainRedirect {
+		t.Errorf("Unexpected final Ads.txt URL [%s] within the original root domain", res.FinalURL)
+	}
+
+	// root domain scope is compared case insensitive
+	res, err = c.Get(&Request{URL: "http://example.com/ads.txt", Domain: "Example.COM"})
+	if err != nil || res.OffDomainRedirect {
+		t.Errorf("Expected redirects within the original root domain and not [%v]", err)
+	}
+
+	// single redirect outside of the original root domain (one-hop delegation)
+	res, err = c.Get(&Request{URL: "http://delegated.com/ads.txt", Domain: "delegated.com"})
+	if err != nil || !res.OffDomainRedirect || res.FinalURL != "http://cdn.adhost.net/ads.txt" {
+		t.Errorf("Expected Ads.txt file of delegated host and not [%v]", err)
+	}
+
+	// delegated host must not redirect, even back to the original root domain
+	_, err = c.Get(&Request{URL: "http://back.com/ads.txt", Domain: "back.com"})
+	if !errors.Is(err, ErrRedirectOutOfScope) {
+		t.Errorf("Expected redirect of delegated host to fail with redirect error and not [%v]", err)
+	}
+
+	// second redirect outside of test.com root domain is forbidden, even within the delegated domain
+	_, err = c.Get(&Request{URL: "http://test.com/ads.txt", Domain: "test.com"})
+	if !errors.Is(err, ErrRedirectOutOfScope) {
+		t.Errorf("Expected request to fail with redirect error and not [%v]", err)
+	}
+}
+
+// TestInvalidContentType test Ads.txt response with Content-Type other than text/plain
+func TestInvalidContentType(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		io.WriteString(w, "greenadexchange.com,XF7342,DIRECT")
+	}))
+	defer ts.Close()
+
+	_, err := NewCrawler().Get(&Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"})
+
+	var ctErr *ErrInvalidContentType
+	if !errors.As(err, &ctErr) || ctErr.ContentType != "text/html; charset=utf-8" {
+		t.Errorf("Expected ErrInvalidContentType error and not [%v]", err)
+	}
+
+	// parse Ads.txt file regardless of Content-Type
+	res, err := NewCrawler(WithIgnoreContentType(true)).Get(&Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.DataRecords) != 1 || len(res.Warnings) != 1 || res.Warnings[0].Level != LowSeverity {
+		t.Errorf("Expected Ads.txt file to be parsed with Content-Type warning [%v]", res.Warnings)
+	}
+
+	// media type is case insensitive
+	req := &Request{URL: ts.URL + "/ads.txt"}
+	if err := checkContentType(req, &http.Response{Header: http.Header{"Content-Type": []string{"Text/Plain; charset=UTF-8"}}}); err != nil {
+		t.Errorf("Expected text/plain media type to be valid [%s]", err)
+	}
+}
+
+// TestHTMLPage test detecting HTML page served instead of Ads.txt file (soft 404)
+func TestHTMLPage(t *testing.T) {
+	bodies := map[string]bool{
+		"<!DOCTYPE html>\n<html><body>Not Found</body></html>":     true,
+		"\xef\xbb\xbf  \n<HTML lang=\"en\">":                       true,
+		"<!-- generated page -->\n<head><title>404</title></head>": true,
+		"greenadexchange.com,XF7342,DIRECT":                        false,
+		"# <html> in comment\ngreenadexchange.com,XF7342,DIRECT":   false,
+		"": false,
+	}
+	for b, expected := range bodies {
+		if isHTML([]byte(b)) != expected {
+			t.Errorf("Expected HTML detection of [%s] to be [%t]", b, expected)
+		}
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, "<!doctype html><html><body>Page not found</body></html>")
+	}))
+	defer ts.Close()
+
+	_, err := NewCrawler().Get(&Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"})
+	if _, ok := err.(*ErrHTMLPage); !ok {
+		t.Errorf("Expected ErrHTMLPage error and not [%v]", err)
+	}
+}
@@ -0,0 +1,64 @@
+package adstxt
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// CrawlMap crawl and parse Ads.txt files of domains, and return the result of each domain by its canonical domain
+// (see Crawler.CrawlMap)
+func CrawlMap(ctx context.Context, domains []string) (map[string]*Result, error) {
+	return defaultCrawler().CrawlMap(ctx, domains)
+}
+
+// CrawlMap crawl and parse Ads.txt files of domains (domain names, host names or URLs), and return the result of each
+// domain by its canonical domain: lower case host name in Unicode form, without trailing dot and "www" subdomain (so
+// "WWW.Example.com" and "http://example.com/" are the same domain "example.com"). Ads.txt requests are created for
+// the domains (see NewRequest), domains of the same canonical domain are crawled once, and the requests are crawled
+// in parallel (see GetMultipleWithContext). Domains that are not valid request URL have result with the request error
+// and nil Request, and requests that failed have result with the request error, so the map holds result of every
+// domain. The returned error is the context error if ctx is done before all requests were crawled
+func (c *Crawler) CrawlMap(ctx context.Context, domains []string) (map[string]*Result, error) {
+	results := make(map[string]*Result, len(domains))
+	keys := make(map[*Request]string, len(domains))
+	req := make([]*Request, 0, len(domains))
+	for _, d := range domains {
+		key := canonicalHost(d)
+		if _, ok := results[key]; ok {
+			continue
+		}
+
+		r, err := NewRequest(d)
+		if err != nil {
+			results[key] = &Result{Err: err}
+			continue
+		}
+		results[key] = &Result{Request: r}
+		keys[r] = key
+		req = append(req, r)
+	}
+
+	// handler may be called by multiple workers
+	var mu sync.Mutex
+	c.getMultiple(ctx, req, c.progressHandler(HandlerFunc(func(r *Request, res *Response, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if result, ok := results[keys[r]]; ok {
+			result.Response, result.Err = res, err
+		}
+	}), len(req)), c.GetWithContext)
+
+	return results, ctx.Err()
+}
+
+// canonicalHost return canonical domain of domain name, host name or URL: lower case host name in Unicode form,
+// without trailing dot and "www" subdomain
+func canonicalHost(domain string) string {
+	host, err := urlHostname(domain)
+	if err != nil || len(host) == 0 {
+		return strings.ToLower(strings.TrimSpace(domain))
+	}
+	host = strings.TrimPrefix(strings.TrimSuffix(strings.ToLower(host), "."), "www.")
+	return unicodeHost(asciiHost(host))
+}
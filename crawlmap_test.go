@@ -0,0 +1,67 @@
+package adstxt
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestCrawlMap test crawling domains and collecting results by canonical domain
+func TestCrawlMap(t *testing.T) {
+	var mu sync.Mutex
+	requested := map[string]int{}
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			requested[req.URL.Host]++
+			mu.Unlock()
+
+			if req.URL.Host == "missing.com" {
+				return &http.Response{StatusCode: http.StatusNotFound, Status: "404 Not Found", Body: http.NoBody, Request: req}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       io.NopCloser(strings.NewReader("greenadexchange.com,XF7342,DIRECT")),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	domains := []string{"example.com", "WWW.Example.com", "http://example.com/", "missing.com", "xn--bcher-kva.de", "http://%zz"}
+	results, err := NewCrawler(WithHTTPClient(client)).CrawlMap(context.Background(), domains)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("Expected results of [4] canonical domains and not [%d]", len(results))
+	}
+
+	if r := results["example.com"]; r == nil || r.Err != nil || r.Response == nil || len(r.Response.DataRecords) != 1 {
+		t.Errorf("Expected Ads.txt file of [example.com] and not %+v", r)
+	}
+	if requested["example.com"] != 1 {
+		t.Errorf("Expected domains of the same canonical domain to be crawled once and not [%d] times", requested["example.com"])
+	}
+	if r := results["missing.com"]; r == nil || !errors.Is(r.Err, ErrNotFound) || r.Response != nil {
+		t.Errorf("Expected ErrNotFound result of [missing.com] and not %+v", r)
+	}
+	if r := results["bücher.de"]; r == nil || r.Err != nil {
+		t.Errorf("Expected result of internationalized domain by its Unicode form and not %+v", r)
+	}
+	if r := results["http://%zz"]; r == nil || r.Err == nil || r.Request != nil {
+		t.Errorf("Expected invalid domain result with request error and not %+v", r)
+	}
+
+	// context error is returned once ctx is done
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if results, err = NewCrawler(WithHTTPClient(client)).CrawlMap(ctx, []string{"example.com"}); !errors.Is(err, context.Canceled) ||
+		results["example.com"] == nil {
+		t.Errorf("Expected result of domain with context error and not [%v]", err)
+	}
+}
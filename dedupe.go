@@ -0,0 +1,62 @@
+package adstxt
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// dedupeRequests return the unique requests of batch request list in order, and the duplicates of each unique
+// request. Requests are duplicates if they fetch the same Ads.txt file (see requestKey)
+func dedupeRequests(req []*Request) ([]*Request, map[*Request][]*Request) {
+	unique := make([]*Request, 0, len(req))
+	duplicates := map[*Request][]*Request{}
+	first := make(map[string]*Request, len(req))
+
+	for _, r := range req {
+		key := requestKey(r)
+		if f, ok := first[key]; ok && len(key) > 0 {
+			duplicates[f] = append(duplicates[f], r)
+			continue
+		}
+		first[key] = r
+		unique = append(unique, r)
+	}
+	return unique, duplicates
+}
+
+// requestKey return canonical form of Ads.txt request: file type, lower case host without "www" subdomain and
+// default port, path and request options (scheme is ignored, since HTTP Ads.txt URL is usually redirected to HTTPS).
+// Return empty key for requests that are never deduplicated: requests with custom headers or invalid URL
+func requestKey(r *Request) string {
+	if len(r.Headers) > 0 {
+		return ""
+	}
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return ""
+	}
+
+	host := strings.TrimPrefix(strings.TrimSuffix(strings.ToLower(u.Hostname()), "."), "www.")
+	if port := u.Port(); len(port) > 0 && port != "80" && port != "443" {
+		host += ":" + port
+	}
+	return fmt.Sprintf("%d|%s%s|%s|%s|%s|%s", r.Type, host, u.EscapedPath(), r.IfNoneMatch, r.IfModifiedSince, r.UserAgent, r.Timeout)
+}
+
+// duplicatesHandler return handler that passes the result of each unique request to h, followed by the result of
+// each of its duplicates. Duplicate request response is shallow copy of the unique request response
+func duplicatesHandler(h Handler, duplicates map[*Request][]*Request) Handler {
+	return HandlerFunc(func(req *Request, res *Response, err error) {
+		h.Handle(req, res, err)
+		for _, d := range duplicates[req] {
+			if res == nil {
+				h.Handle(d, nil, err)
+				continue
+			}
+			c := *res
+			c.Request = d
+			h.Handle(d, &c, err)
+		}
+	})
+}
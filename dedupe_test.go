@@ -0,0 +1,83 @@
+package adstxt
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// TestDeduplicateRequests test GetMultiple crawl duplicate requests once, and pass the result to each of them
+func TestDeduplicateRequests(t *testing.T) {
+	var hits int32
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&hits, 1)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       io.NopCloser(strings.NewReader("greenadexchange.com,XF7342,DIRECT")),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	requests := []*Request{}
+	for _, d := range []string{"example.com", "www.example.com", "EXAMPLE.COM/", "https://example.com:443", "other.com", "example.com:8080"} {
+		req, err := NewRequest(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		requests = append(requests, req)
+	}
+
+	var mu sync.Mutex
+	handled := map[*Request]*Response{}
+	h := HandlerFunc(func(req *Request, res *Response, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			t.Errorf("Expected request [%s] to succeed [%s]", req.URL, err)
+		}
+		handled[req] = res
+	})
+
+	NewCrawler(WithHTTPClient(client)).GetMultiple(requests, h)
+	if hits != 3 {
+		t.Errorf("Expected [3] unique Ads.txt files to be fetched and not [%d]", hits)
+	}
+	if len(handled) != len(requests) {
+		t.Fatalf("Expected result of each of the [%d] requests and not [%d]", len(requests), len(handled))
+	}
+	for _, req := range requests {
+		if res := handled[req]; res == nil || res.Request != req || len(res.DataRecords) != 1 {
+			t.Errorf("Expected response of request [%s] to hold the request and records", req.URL)
+		}
+	}
+
+	// crawl duplicate requests separately
+	hits = 0
+	NewCrawler(WithHTTPClient(client), WithDeduplicateRequests(false)).GetMultiple(requests, h)
+	if hits != int32(len(requests)) {
+		t.Errorf("Expected [%d] Ads.txt files to be fetched and not [%d]", len(requests), hits)
+	}
+}
+
+// TestRequestKey test canonical form of Ads.txt request
+func TestRequestKey(t *testing.T) {
+	req := &Request{URL: "http://www.example.com/ads.txt"}
+	if requestKey(req) != requestKey(&Request{URL: "https://Example.com./ads.txt"}) {
+		t.Errorf("Expected requests of the same Ads.txt file to have the same key")
+	}
+	if requestKey(req) == requestKey(&Request{URL: "http://example.com/app-ads.txt"}) {
+		t.Errorf("Expected requests of different files to have different keys")
+	}
+	if requestKey(req) == requestKey(&Request{URL: req.URL, Type: AppAdsTxt}) || requestKey(req) == requestKey(&Request{URL: req.URL, IfNoneMatch: `"v1"`}) {
+		t.Errorf("Expected requests with different options to have different keys")
+	}
+	if len(requestKey(&Request{URL: req.URL, Headers: http.Header{"X-Test": []string{"test"}}})) != 0 {
+		t.Errorf("Expected request with custom headers not to be deduplicated")
+	}
+}
@@ -0,0 +1,188 @@
+package adstxt
+
+import "strings"
+
+// RecordsDiff holds the changes between two Ads.txt snapshots. Records are compared after normalization (see
+// Records.Normalize), so changes in whitespaces or casing are not reported
+type RecordsDiff struct {
+	AddedRecords      []*DataRecord       `json:"addedRecords"`      // AddedRecords data records found only in the new snapshot
+	RemovedRecords    []*DataRecord       `json:"removedRecords"`    // RemovedRecords data records found only in the old snapshot
+	ModifiedRecords   []*DataRecordChange `json:"modifiedRecords"`   // ModifiedRecords data records with changed account type or certification authority ID
+	AddedVariables    []*Variable         `json:"addedVariables"`    // AddedVariables variables found only in the new snapshot
+	RemovedVariables  []*Variable         `json:"removedVariables"`  // RemovedVariables variables found only in the old snapshot
+	ModifiedVariables []*VariableChange   `json:"modifiedVariables"` // ModifiedVariables single value variables (e.g. OWNERDOMAIN) with changed value
+}
+
+// DataRecordChange single data record that was modified between two Ads.txt snapshots. Data records are identified
+// by advertising system domain and publisher account ID
+type DataRecordChange struct {
+	Old *DataRecord `json:"old"` // Old data record
+	New *DataRecord `json:"new"` // New data record
+}
+
+// VariableChange single variable that was modified between two Ads.txt snapshots
+type VariableChange struct {
+	Old *Variable `json:"old"` // Old variable
+	New *Variable `json:"new"` // New variable
+}
+
+// Empty return true if there are no changes between the two Ads.txt snapshots
+func (d *RecordsDiff) Empty() bool {
+	return len(d.AddedRecords) == 0 && len(d.RemovedRecords) == 0 && len(d.ModifiedRecords) == 0 &&
+		len(d.AddedVariables) == 0 && len(d.RemovedVariables) == 0 && len(d.ModifiedVariables) == 0
+}
+
+// Diff compare two Ads.txt snapshots, and return data records and variables that were added, removed or modified
+// in the new snapshot. Nil snapshot is treated as empty Ads.txt file
+func Diff(old, new *Records) *RecordsDiff {
+	if old == nil {
+		old = &Records{}
+	}
+	if new == nil {
+		new = &Records{}
+	}
+
+	d := &RecordsDiff{
+		AddedRecords:      []*DataRecord{},
+		RemovedRecords:    []*DataRecord{},
+		ModifiedRecords:   []*DataRecordChange{},
+		AddedVariables:    []*Variable{},
+		RemovedVariables:  []*Variable{},
+		ModifiedVariables: []*VariableChange{},
+	}
+
+	// data records
+	removed, added := diffLines(toLines(old.DataRecords), toLines(new.DataRecords))
+	for _, c := range pairChanges(removed, added) {
+		switch {
+		case c.old == nil:
+			d.AddedRecords = append(d.AddedRecords, c.new.(*DataRecord))
+		case c.new == nil:
+			d.RemovedRecords = append(d.RemovedRecords, c.old.(*DataRecord))
+		default:
+			d.ModifiedRecords = append(d.ModifiedRecords, &DataRecordChange{Old: c.old.(*DataRecord), New: c.new.(*DataRecord)})
+		}
+	}
+
+	// variables
+	removed, added = diffLines(toLines(old.Variables), toLines(new.Variables))
+	for _, c := range pairChanges(removed, added) {
+		switch {
+		case c.old == nil:
+			d.AddedVariables = append(d.AddedVariables, c.new.(*Variable))
+		case c.new == nil:
+			d.RemovedVariables = append(d.RemovedVariables, c.old.(*Variable))
+		default:
+			d.ModifiedVariables = append(d.ModifiedVariables, &VariableChange{Old: c.old.(*Variable), New: c.new.(*Variable)})
+		}
+	}
+
+	return d
+}
+
+// diffRecord record that can be compared between Ads.txt snapshots
+type diffRecord interface {
+	canonical() string // canonical record form: records are equal if their canonical form is equal
+	diffKey() string   // identity of the record: records with same key but different canonical form are modified
+}
+
+// diffChange single changed record: added (old is nil), removed (new is nil) or modified
+type diffChange struct {
+	old diffRecord
+	new diffRecord
+}
+
+// toLines convert data records or variables to diff records
+func toLines(records interface{}) []diffRecord {
+	lines := []diffRecord{}
+	switch r := records.(type) {
+	case []*DataRecord:
+		for _, dr := range r {
+			lines = append(lines, dr)
+		}
+	case []*Variable:
+		for _, v := range r {
+			lines = append(lines, v)
+		}
+	}
+	return lines
+}
+
+// diffLines return records found only in old snapshot and records found only in new snapshot
+func diffLines(old, new []diffRecord) ([]diffRecord, []diffRecord) {
+	count := map[string]int{}
+	for _, l := range old {
+		count[l.canonical()]++
+	}
+
+	// records found in both snapshots are not changed
+	added := []diffRecord{}
+	for _, l := range new {
+		if count[l.canonical()] > 0 {
+			count[l.canonical()]--
+			continue
+		}
+		added = append(added, l)
+	}
+
+	removed := []diffRecord{}
+	for _, l := range old {
+		if count[l.canonical()] > 0 {
+			count[l.canonical()]--
+			removed = append(removed, l)
+		}
+	}
+
+	return removed, added
+}
+
+// pairChanges match removed and added records with the same key as modified records, keeping records order
+func pairChanges(removed, added []diffRecord) []*diffChange {
+	changes := []*diffChange{}
+
+	// removed records by key, in order of appearance
+	byKey := map[string][]diffRecord{}
+	for _, l := range removed {
+		byKey[l.diffKey()] = append(byKey[l.diffKey()], l)
+	}
+
+	modified := map[diffRecord]bool{}
+	for _, l := range added {
+		if candidates := byKey[l.diffKey()]; len(candidates) > 0 {
+			byKey[l.diffKey()] = candidates[1:]
+			modified[candidates[0]] = true
+			changes = append(changes, &diffChange{old: candidates[0], new: l})
+			continue
+		}
+		changes = append(changes, &diffChange{new: l})
+	}
+
+	for _, l := range removed {
+		if !modified[l] {
+			changes = append(changes, &diffChange{old: l})
+		}
+	}
+
+	return changes
+}
+
+// diffKey return DataRecord identity: advertising system domain and publisher account ID
+func (r *DataRecord) diffKey() string {
+	n := r.normalized()
+	return n.AdverterDomain + "," + n.PublisherAccountID
+}
+
+// diffKey return Variable identity: single value variables (OWNERDOMAIN, MANAGERDOMAIN per country) are identified by
+// their type, and other variables by their type and value
+func (v *Variable) diffKey() string {
+	n := v.normalized()
+	switch n.Type {
+	case varTypeOwnerDomain:
+		return n.Type
+	case varTypeManagerDomain:
+		if m, err := parseManagerDomain(n.Value); err == nil {
+			return n.Type + "," + strings.ToLower(m.Country)
+		}
+	}
+	return n.canonical()
+}
@@ -0,0 +1,44 @@
+package adstxt
+
+import "testing"
+
+// TestDiff test comparing two Ads.txt snapshots
+func TestDiff(t *testing.T) {
+	old, _ := ParseBody([]byte("greenadexchange.com,XF7342,DIRECT\ngreenadexchange.com,185,RESELLER\ngreenadexchange.com,200,RESELLER,abc\nsubdomain=test.com\nOWNERDOMAIN=example.com"))
+	new, _ := ParseBody([]byte("GreenAdExchange.com, XF7342, direct\ngreenadexchange.com,200,DIRECT,abc\ngreenadexchange.com,300,DIRECT\ncontact=test@example.com\nOWNERDOMAIN=other.com"))
+
+	d := Diff(old, new)
+
+	if len(d.AddedRecords) != 1 || d.AddedRecords[0].PublisherAccountID != "300" {
+		t.Errorf("Expected record [300] to be added and not [%v]", d.AddedRecords)
+	}
+	if len(d.RemovedRecords) != 1 || d.RemovedRecords[0].PublisherAccountID != "185" {
+		t.Errorf("Expected record [185] to be removed and not [%v]", d.RemovedRecords)
+	}
+	if len(d.ModifiedRecords) != 1 || d.ModifiedRecords[0].Old.AccountType != "RESELLER" || d.ModifiedRecords[0].New.AccountType != "DIRECT" {
+		t.Errorf("Expected record [200] to be modified and not [%v]", d.ModifiedRecords)
+	}
+	if len(d.AddedVariables) != 1 || d.AddedVariables[0].Type != varTypeContact {
+		t.Errorf("Expected contact variable to be added and not [%v]", d.AddedVariables)
+	}
+	if len(d.RemovedVariables) != 1 || d.RemovedVariables[0].Type != varTypeSubdomain {
+		t.Errorf("Expected subdomain variable to be removed and not [%v]", d.RemovedVariables)
+	}
+	if len(d.ModifiedVariables) != 1 || d.ModifiedVariables[0].New.Value != "other.com" {
+		t.Errorf("Expected OWNERDOMAIN variable to be modified and not [%v]", d.ModifiedVariables)
+	}
+	if d.Empty() {
+		t.Errorf("Expected diff not to be empty")
+	}
+
+	// same records in different order and casing
+	same, _ := ParseBody([]byte("OWNERDOMAIN=EXAMPLE.COM\nsubdomain=test.com\ngreenadexchange.com,200,reseller,ABC\ngreenadexchange.com,185,RESELLER\ngreenadexchange.com,XF7342,DIRECT"))
+	if d = Diff(old, same); !d.Empty() {
+		t.Errorf("Expected no changes between snapshots and not [%v]", d)
+	}
+
+	// nil snapshot is an empty Ads.txt file
+	if d = Diff(nil, old); len(d.AddedRecords) != 3 || len(d.AddedVariables) != 2 {
+		t.Errorf("Expected all records to be added compared to empty snapshot")
+	}
+}
@@ -0,0 +1,34 @@
+package adstxt
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+// dialTimeout default connection timeout of the crawler HTTP transport dialer
+const dialTimeout = 30 * time.Second
+
+// DialFunc open network connection to address on the named network (see net.Dialer DialContext). Address is
+// "host:port", where host is the remote host name as it appears in the Ads.txt URL, so DialFunc can resolve it by
+// itself. DialFunc that fails to resolve host name should return *net.DNSError, so the failure is reported as ErrDNS
+type DialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// transportDial return HTTP transport dial function based on crawler DNS settings
+func (c *Crawler) transportDial() DialFunc {
+	if c.dialContext != nil {
+		if c.guard != nil {
+			return c.familyDial(c.guard.wrap(c.dialContext))
+		}
+		return c.familyDial(c.dialContext)
+	}
+
+	dialer := &net.Dialer{Timeout: c.connectTimeout, Resolver: c.resolver, FallbackDelay: c.fallbackDelay}
+	if c.guard != nil {
+		dialer.Control = c.guard.control
+	}
+	if c.dnsCache != nil {
+		return c.familyDial(c.cachedDial(dialer))
+	}
+	return c.familyDial(dialer.DialContext)
+}
@@ -0,0 +1,72 @@
+package adstxt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestWithDialContext test crawler connect to remote hosts using custom dial function (pre-resolved host address)
+func TestWithDialContext(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, "greenadexchange.com,XF7342,DIRECT")
+	}))
+	defer ts.Close()
+
+	var dialer net.Dialer
+	c := NewCrawler(WithDialContext(func(ctx context.Context, network, address string) (net.Conn, error) {
+		if address != "example.com:80" {
+			return nil, &net.DNSError{Err: "no such host", Name: strings.Split(address, ":")[0], IsNotFound: true}
+		}
+		return dialer.DialContext(ctx, network, strings.TrimPrefix(ts.URL, "http://"))
+	}))
+
+	req, _ := NewRequest("example.com")
+	res, err := c.Get(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.DataRecords) != 1 {
+		t.Errorf("Expected Ads.txt file of pre-resolved host")
+	}
+
+	req, _ = NewRequest("test.com")
+	_, err = c.Get(req)
+	var dnsErr *ErrDNS
+	if !errors.As(err, &dnsErr) || dnsErr.Host != "test.com" {
+		t.Errorf("Expected ErrDNS for host that failed to be resolved and not [%v]", err)
+	}
+	var requestErr *ErrRequest
+	if errors.As(err, &requestErr) {
+		t.Errorf("Expected DNS failure not to be reported as ErrRequest")
+	}
+	if StatusClass(nil, err) != StatusClassDNSError {
+		t.Errorf("Expected DNS failure status class [%s] and not [%s]", StatusClassDNSError, StatusClass(nil, err))
+	}
+}
+
+// TestWithResolver test crawler resolve remote host names using custom resolver
+func TestWithResolver(t *testing.T) {
+	dialed := false
+	r := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			dialed = true
+			return nil, errors.New("DNS server unreachable")
+		},
+	}
+
+	req, _ := NewRequest("example.com")
+	_, err := NewCrawler(WithResolver(r)).Get(req)
+
+	var dnsErr *ErrDNS
+	if !dialed || !errors.As(err, &dnsErr) {
+		t.Errorf("Expected custom resolver failure to be reported as ErrDNS and not [%v]", err)
+	}
+}
@@ -0,0 +1,166 @@
+package adstxt
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+)
+
+// DNSCache cache of resolved remote host addresses, used by the crawler dialer so host names shared by many Ads.txt
+// hosts (for example CDN host names) are resolved once (see WithDNSCache). Implementations handle the expiration of
+// cached addresses, and must be safe to use from multiple goroutines
+type DNSCache interface {
+	// Get return the cached IP addresses of host, or false if host is not cached or its addresses expired
+	Get(host string) ([]string, bool)
+	// Set add the resolved IP addresses of host to the cache
+	Set(host string, addrs []string)
+}
+
+// TTLDNSCache in-memory DNSCache that holds resolved host addresses for a fixed time (TTL)
+type TTLDNSCache struct {
+	ttl   time.Duration
+	items map[string]*dnsCacheItem
+	swept int // number of items after the last removal of expired items
+	mu    sync.Mutex
+}
+
+// dnsCacheItem single TTLDNSCache entry
+type dnsCacheItem struct {
+	addrs   []string
+	expires time.Time
+}
+
+// NewTTLDNSCache create new in-memory DNS cache that holds resolved host addresses for ttl
+func NewTTLDNSCache(ttl time.Duration) *TTLDNSCache {
+	return &TTLDNSCache{ttl: ttl, items: map[string]*dnsCacheItem{}}
+}
+
+// Get is the DNSCache interface implementation for TTLDNSCache
+func (c *TTLDNSCache) Get(host string) ([]string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, ok := c.items[host]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(item.expires) {
+		delete(c.items, host)
+		return nil, false
+	}
+	return item.addrs, true
+}
+
+// Set is the DNSCache interface implementation for TTLDNSCache. Expired addresses of other hosts are removed once the
+// number of cached hosts doubled since they were last removed
+func (c *TTLDNSCache) Set(host string, addrs []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	c.items[host] = &dnsCacheItem{addrs: addrs, expires: now.Add(c.ttl)}
+
+	if len(c.items) > 2*c.swept {
+		for h, item := range c.items {
+			if now.After(item.expires) {
+				delete(c.items, h)
+			}
+		}
+		c.swept = len(c.items)
+	}
+}
+
+// Len return number of cached hosts, including hosts with expired addresses that were not removed yet
+func (c *TTLDNSCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.items)
+}
+
+// dnsLookup in-flight host name lookup, shared by concurrent connections to the same host
+type dnsLookup struct {
+	done  chan struct{}
+	addrs []string
+	err   error
+}
+
+// cachedDial return dial function that resolves remote host names using the crawler DNS cache, and dials the
+// resolved addresses in order until a connection is made. Concurrent connections to host that is not cached share a
+// single lookup
+func (c *Crawler) cachedDial(dialer *net.Dialer) DialFunc {
+	resolver := c.resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	var mu sync.Mutex
+	lookups := map[string]*dnsLookup{}
+
+	lookup := func(ctx context.Context, host string) ([]string, error) {
+		if addrs, ok := c.dnsCache.Get(host); ok {
+			return addrs, nil
+		}
+
+		mu.Lock()
+		l, ok := lookups[host]
+		if !ok {
+			l = &dnsLookup{done: make(chan struct{})}
+			lookups[host] = l
+		}
+		mu.Unlock()
+
+		if ok {
+			select {
+			case <-l.done:
+				return l.addrs, l.err
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		// lookup is not bound to the context of the first connection, so it is not canceled for the other connections
+		lctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		if c.connectTimeout > 0 {
+			lctx, cancel = context.WithTimeout(context.WithoutCancel(ctx), c.connectTimeout)
+		}
+		l.addrs, l.err = resolver.LookupHost(lctx, host)
+		cancel()
+		if l.err == nil {
+			c.dnsCache.Set(host, l.addrs)
+		}
+
+		mu.Lock()
+		delete(lookups, host)
+		mu.Unlock()
+		close(l.done)
+
+		return l.addrs, l.err
+	}
+
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(address)
+		if err != nil || net.ParseIP(host) != nil {
+			return dialer.DialContext(ctx, network, address)
+		}
+
+		addrs, err := lookup(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		// only addresses of the crawler address family are dialed (see WithIPFamily)
+		addrs = familyAddrs(network, addrs)
+		if len(addrs) == 0 {
+			return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+		}
+
+		dial := func(ctx context.Context, addr string) (net.Conn, error) {
+			return dialer.DialContext(ctx, network, net.JoinHostPort(addr, port))
+		}
+		if c.fallbackDelay > 0 && network == "tcp" {
+			return dialHappyEyeballs(ctx, dial, addrs, c.fallbackDelay)
+		}
+		return dialSerial(ctx, dial, addrs)
+	}
+}
@@ -0,0 +1,80 @@
+package adstxt
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// countingDNSCache DNSCache that counts cached host lookups and stored hosts
+type countingDNSCache struct {
+	*TTLDNSCache
+	sets int
+	mu   sync.Mutex
+}
+
+// Set count and cache host addresses
+func (c *countingDNSCache) Set(host string, addrs []string) {
+	c.mu.Lock()
+	c.sets++
+	c.mu.Unlock()
+	c.TTLDNSCache.Set(host, addrs)
+}
+
+// TestWithDNSCache test crawler resolve remote host name once using DNS cache
+func TestWithDNSCache(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// each request is sent over new connection
+		w.Header().Set("Connection", "close")
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, "greenadexchange.com,XF7342,DIRECT")
+	}))
+	defer ts.Close()
+
+	cache := &countingDNSCache{TTLDNSCache: NewTTLDNSCache(time.Minute)}
+	c := NewCrawler(WithDNSCache(cache))
+	url := strings.Replace(ts.URL, "127.0.0.1", "localhost", 1) + "/ads.txt"
+	for i := 0; i < 3; i++ {
+		if _, err := c.Get(&Request{URL: url, Domain: "localhost"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if cache.sets != 1 {
+		t.Errorf("Expected host name to be resolved once and not [%d] times", cache.sets)
+	}
+	if addrs, ok := cache.Get("localhost"); !ok || len(addrs) == 0 {
+		t.Errorf("Expected cached addresses of [localhost]")
+	}
+
+	// cached addresses are used instead of resolving host name
+	cache.TTLDNSCache.Set("adstxt.test", []string{"127.0.0.1"})
+	url = strings.Replace(ts.URL, "127.0.0.1", "adstxt.test", 1) + "/ads.txt"
+	if _, err := c.Get(&Request{URL: url, Domain: "adstxt.test"}); err != nil {
+		t.Errorf("Expected Ads.txt file of host with cached address [%s]", err)
+	}
+}
+
+// TestTTLDNSCache test cached host addresses expire after TTL
+func TestTTLDNSCache(t *testing.T) {
+	cache := NewTTLDNSCache(20 * time.Millisecond)
+	cache.Set("example.com", []string{"93.184.216.34"})
+
+	if addrs, ok := cache.Get("example.com"); !ok || addrs[0] != "93.184.216.34" {
+		t.Errorf("Expected cached address of [example.com] and not [%v]", addrs)
+	}
+	if _, ok := cache.Get("google.com"); ok {
+		t.Errorf("Expected [google.com] not to be cached")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if _, ok := cache.Get("example.com"); ok {
+		t.Errorf("Expected cached address of [example.com] to expire")
+	}
+	if cache.Len() != 0 {
+		t.Errorf("Expected expired host to be removed and not [%d] cached hosts", cache.Len())
+	}
+}
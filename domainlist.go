@@ -0,0 +1,133 @@
+package adstxt
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// domain list errors
+const (
+	errDomainBlocked    = "[%s] Ads.txt request to host [%s] is blocked by crawler blocklist rule [%s]"
+	errDomainNotAllowed = "[%s] Ads.txt request to host [%s] is not allowed by crawler allowlist"
+	errInvalidRule      = "invalid domain list rule [%s]: %w"
+)
+
+// DomainList list of domain rules, used as crawler allowlist or blocklist of the hosts it may crawl (see
+// WithDomainAllowlist and WithDomainBlocklist). Rules are:
+//   - exact domain, e.g. "example.com": matches only example.com
+//   - suffix pattern, e.g. "*.example.com" or "*.gov": matches any subdomain of the suffix (but not the suffix itself)
+//   - regular expression between slashes, e.g. "/^ads[0-9]*\.example\.com$/": matches domains that match the regular
+//     expression
+//
+// Domains are matched case insensitive by their punycode form (regular expressions are matched against the punycode
+// form). DomainList is safe to use from multiple goroutines
+type DomainList struct {
+	rules    []string
+	exact    map[string]string // exact domain rules, by domain
+	suffixes []string          // suffix pattern rules, including the leading dot
+	patterns []*regexp.Regexp  // regular expression rules
+}
+
+// NewDomainList create new DomainList of rules. Empty rules are ignored, and invalid regular expression rule fails
+func NewDomainList(rules ...string) (*DomainList, error) {
+	l := &DomainList{exact: map[string]string{}}
+	for _, rule := range rules {
+		r := strings.TrimSpace(rule)
+		switch {
+		case len(r) == 0:
+			continue
+		case len(r) > 2 && strings.HasPrefix(r, "/") && strings.HasSuffix(r, "/"):
+			re, err := regexp.Compile(r[1 : len(r)-1])
+			if err != nil {
+				return nil, fmt.Errorf(errInvalidRule, rule, err)
+			}
+			l.patterns = append(l.patterns, re)
+		case strings.HasPrefix(r, "*."):
+			l.suffixes = append(l.suffixes, asciiHost(strings.ToLower(r[1:])))
+		default:
+			l.exact[asciiHost(strings.ToLower(r))] = r
+		}
+		l.rules = append(l.rules, r)
+	}
+	return l, nil
+}
+
+// Match check if domain matches any rule of the list, and return the first rule that matches it (exact domain rules
+// are checked first, then suffix patterns, then regular expressions)
+func (l *DomainList) Match(domain string) (string, bool) {
+	d := asciiHost(strings.ToLower(strings.TrimSuffix(domain, ".")))
+	if len(d) == 0 {
+		return "", false
+	}
+	if rule, ok := l.exact[d]; ok {
+		return rule, true
+	}
+	for _, s := range l.suffixes {
+		if strings.HasSuffix(d, s) {
+			return "*" + s, true
+		}
+	}
+	for _, re := range l.patterns {
+		if re.MatchString(d) {
+			return "/" + re.String() + "/", true
+		}
+	}
+	return "", false
+}
+
+// Rules return the rules of the list
+func (l *DomainList) Rules() []string {
+	return append([]string{}, l.rules...)
+}
+
+// ErrDomainBlocked Ads.txt request was rejected before any network I/O since its host is blocked by the crawler
+// blocklist, or is not allowed by the crawler allowlist (see WithDomainBlocklist and WithDomainAllowlist)
+type ErrDomainBlocked struct {
+	Domain string // Domain root domain of the Ads.txt request
+	Host   string // Host name of the rejected URL (request URL, URL variant or redirect destination)
+	URL    string // URL that was rejected
+	Rule   string // Rule of the blocklist that matched, empty if the host is not in the allowlist
+}
+
+func (e *ErrDomainBlocked) Error() string {
+	if len(e.Rule) == 0 {
+		return fmt.Sprintf(errDomainNotAllowed, e.Domain, e.Host)
+	}
+	return fmt.Sprintf(errDomainBlocked, e.Domain, e.Host, e.Rule)
+}
+
+// checkDomain return ErrDomainBlocked if the URL host of request is blocked by the crawler blocklist or is not allowed
+// by the crawler allowlist. Both the host name and its root domain are matched: host is blocked if either matches the
+// blocklist, and allowed if either matches the allowlist
+func (c *Crawler) checkDomain(req *Request) error {
+	if c.blocklist == nil && c.allowlist == nil {
+		return nil
+	}
+
+	host, err := urlHostname(req.URL)
+	if err != nil {
+		return err
+	}
+	names := []string{host}
+	if root, err := rootDomain(req.URL); err == nil && root != host {
+		names = append(names, root)
+	}
+
+	if c.blocklist != nil {
+		for _, n := range names {
+			if rule, ok := c.blocklist.Match(n); ok {
+				return &ErrDomainBlocked{Domain: req.Domain, Host: host, URL: req.URL, Rule: rule}
+			}
+		}
+	}
+	if c.allowlist != nil {
+		for _, n := range names {
+			if _, ok := c.allowlist.Match(n); ok {
+				return nil
+			}
+		}
+		return &ErrDomainBlocked{Domain: req.Domain, Host: host, URL: req.URL}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package adstxt
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// TestDomainList test matching domains against exact domain, suffix pattern and regular expression rules
+func TestDomainList(t *testing.T) {
+	l, err := NewDomainList("Example.com", "*.gov", "", "/^ads[0-9]+\\.test$/", "bücher.de")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		domain string
+		rule   string
+	}{
+		{"example.com", "Example.com"},
+		{"EXAMPLE.COM.", "Example.com"},
+		{"www.example.com", ""},
+		{"whitehouse.gov", "*.gov"},
+		{"gov", ""},
+		{"ads12.test", "/^ads[0-9]+\\.test$/"},
+		{"ads.test", ""},
+		{"xn--bcher-kva.de", "bücher.de"},
+		{"BÜCHER.de", "bücher.de"},
+	}
+	for _, test := range tests {
+		rule, ok := l.Match(test.domain)
+		if rule != test.rule || ok != (len(test.rule) > 0) {
+			t.Errorf("Expected domain [%s] to match rule [%s] and not [%s]", test.domain, test.rule, rule)
+		}
+	}
+	if len(l.Rules()) != 4 {
+		t.Errorf("Expected [4] rules and not %v", l.Rules())
+	}
+
+	if _, err := NewDomainList("/ads[/"); err == nil {
+		t.Errorf("Expected invalid regular expression rule to fail")
+	}
+}
+
+// TestDomainBlocklist test requests to hosts blocked by the crawler blocklist, or not in the crawler allowlist, are
+// rejected with ErrDomainBlocked before any request is sent
+func TestDomainBlocklist(t *testing.T) {
+	requested := map[string]int{}
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			requested[req.URL.Host]++
+			if req.URL.Host == "redirect.com" {
+				return &http.Response{StatusCode: http.StatusMovedPermanently, Status: "301 Moved Permanently",
+					Header: http.Header{"Location": []string{"http://cdn.blocked.com/ads.txt"}}, Body: http.NoBody, Request: req}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       io.NopCloser(strings.NewReader("greenadexchange.com,XF7342,DIRECT")),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	blocklist, _ := NewDomainList("blocked.com", "*.test")
+	allowlist, _ := NewDomainList("example.com", "redirect.com", "blocked.com", "allowed.test")
+	c := NewCrawler(WithHTTPClient(client), WithDomainBlocklist(blocklist), WithDomainAllowlist(allowlist))
+
+	tests := []struct {
+		url, domain string
+		rule        string
+		blocked     bool
+		requests    int
+	}{
+		{"http://www.example.com/ads.txt", "example.com", "", false, 1},
+		{"http://blocked.com/ads.txt", "blocked.com", "blocked.com", true, 0},
+		{"http://www.blocked.com/ads.txt", "blocked.com", "blocked.com", true, 0},
+		{"http://allowed.test/ads.txt", "allowed.test", "*.test", true, 0},
+		{"http://other.com/ads.txt", "other.com", "", true, 0},
+		{"http://redirect.com/ads.txt", "redirect.com", "blocked.com", true, 1},
+	}
+	for _, test := range tests {
+		requested = map[string]int{}
+		_, err := c.Get(&Request{URL: test.url, Domain: test.domain})
+
+		var blockedErr *ErrDomainBlocked
+		if !test.blocked {
+			if err != nil {
+				t.Errorf("Expected request [%s] to be allowed [%v]", test.url, err)
+			}
+		} else if !errors.As(err, &blockedErr) || blockedErr.Rule != test.rule || blockedErr.Domain != test.domain {
+			t.Errorf("Expected request [%s] to be blocked by rule [%s] and not [%v]", test.url, test.rule, err)
+		}
+		n := 0
+		for _, r := range requested {
+			n += r
+		}
+		if n != test.requests {
+			t.Errorf("Expected [%d] requests of [%s] and not [%d]", test.requests, test.url, n)
+		}
+	}
+
+	// requests of custom fetcher are checked as well
+	fetched := false
+	c = NewCrawler(WithDomainBlocklist(blocklist), WithFetcher(FetcherFunc(func(ctx context.Context, req *Request) (*Response, error) {
+		fetched = true
+		return NewResponse(req, []byte("greenadexchange.com,XF7342,DIRECT"))
+	})))
+	if _, err := c.Get(&Request{URL: "http://blocked.com/ads.txt", Domain: "blocked.com"}); err == nil || fetched {
+		t.Errorf("Expected custom fetcher not to fetch blocked Ads.txt file")
+	}
+}
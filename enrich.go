@@ -0,0 +1,190 @@
+package adstxt
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// MetadataDNS Response metadata key of DNS enrichment (see DNSEnricher)
+const MetadataDNS = "dns"
+
+// enrichment errors
+const (
+	errASNLookup   = "ASN lookup of [%s] failed: %s"
+	errInvalidASN  = "invalid ASN lookup record [%s] of [%s]"
+	errEnrichFinal = "invalid final URL [%s]: %s"
+)
+
+// Enricher add metadata to successful Ads.txt response (see Response.Metadata), for example DNS or WHOIS details of
+// the Ads.txt host, as an enrichment stage of the crawl (see WithEnrichers). Enrichment error does not fail the
+// Ads.txt request: it is logged, and the response is passed to the next enrichers
+type Enricher interface {
+	Enrich(ctx context.Context, res *Response) error
+}
+
+// EnricherFunc is a function signature that implements the Enricher interface
+type EnricherFunc func(ctx context.Context, res *Response) error
+
+// Enrich is the Enricher interface implementation for the EnricherFunc type
+func (f EnricherFunc) Enrich(ctx context.Context, res *Response) error {
+	return f(ctx, res)
+}
+
+// SetMetadata set response metadata value of key
+func (r *Response) SetMetadata(key string, value interface{}) {
+	if r.Metadata == nil {
+		r.Metadata = map[string]interface{}{}
+	}
+	r.Metadata[key] = value
+}
+
+// enrich run the crawler enrichers on successful Ads.txt response, in order
+func (c *Crawler) enrich(ctx context.Context, req *Request, res *Response) {
+	for _, e := range c.enrichers {
+		if err := e.Enrich(ctx, res); err != nil {
+			c.log(ctx, c.logLevels.Error, "Ads.txt response enrichment failed", "domain", req.Domain, "url", res.FinalURL,
+				"error", err)
+		}
+	}
+}
+
+// HostInfo DNS details of Ads.txt host: resolved addresses and the autonomous systems (hosting providers) that announce
+// them
+type HostInfo struct {
+	Host string     `json:"host"`           // Host name of the final Ads.txt URL
+	A    []string   `json:"a"`              // A IPv4 addresses of the host
+	AAAA []string   `json:"aaaa"`           // AAAA IPv6 addresses of the host
+	ASNs []*ASNInfo `json:"asns,omitempty"` // ASNs autonomous systems of the host addresses (if ASN lookup is set)
+}
+
+// ASNInfo autonomous system that announces IP address
+type ASNInfo struct {
+	IP       string `json:"ip"`                 // IP address
+	ASN      int    `json:"asn"`                // ASN autonomous system number
+	Prefix   string `json:"prefix,omitempty"`   // Prefix announced network prefix of the IP address
+	Country  string `json:"country,omitempty"`  // Country code of the autonomous system registration
+	Registry string `json:"registry,omitempty"` // Registry regional internet registry of the autonomous system
+	Name     string `json:"name,omitempty"`     // Name of the autonomous system (hosting provider)
+}
+
+// ASNLookup return the autonomous system that announces IP address
+type ASNLookup func(ctx context.Context, ip netip.Addr) (*ASNInfo, error)
+
+// DNSEnricher Enricher that adds the DNS details of the final Ads.txt URL host to the response metadata, as HostInfo
+// under the MetadataDNS key. Host addresses are resolved with Resolver (system resolver if nil), and their autonomous
+// systems are looked up with ASN (no ASN lookup if nil, see CymruASNLookup)
+type DNSEnricher struct {
+	Resolver *net.Resolver // Resolver DNS resolver of the host addresses
+	ASN      ASNLookup     // ASN lookup of the host addresses autonomous systems
+}
+
+// Enrich is the Enricher interface implementation for DNSEnricher
+func (e *DNSEnricher) Enrich(ctx context.Context, res *Response) error {
+	u, err := url.Parse(res.FinalURL)
+	if err != nil || len(u.Hostname()) == 0 {
+		return fmt.Errorf(errEnrichFinal, res.FinalURL, err)
+	}
+
+	info := &HostInfo{Host: u.Hostname(), A: []string{}, AAAA: []string{}}
+	addrs, err := e.resolver().LookupNetIP(ctx, "ip", info.Host)
+	if err != nil {
+		return err
+	}
+	slices.SortFunc(addrs, func(a, b netip.Addr) int { return a.Compare(b) })
+	addrs = slices.Compact(addrs)
+
+	for _, ip := range addrs {
+		ip = ip.Unmap()
+		if ip.Is4() {
+			info.A = append(info.A, ip.String())
+		} else {
+			info.AAAA = append(info.AAAA, ip.String())
+		}
+
+		if e.ASN != nil {
+			asn, err := e.ASN(ctx, ip)
+			if err != nil {
+				return fmt.Errorf(errASNLookup, ip, err)
+			}
+			info.ASNs = append(info.ASNs, asn)
+		}
+	}
+
+	res.SetMetadata(MetadataDNS, info)
+	return nil
+}
+
+// resolver return the enricher DNS resolver
+func (e *DNSEnricher) resolver() *net.Resolver {
+	if e.Resolver != nil {
+		return e.Resolver
+	}
+	return net.DefaultResolver
+}
+
+// CymruASNLookup return ASNLookup that looks up IP address autonomous system using Team Cymru IP to ASN mapping DNS
+// service (https://www.team-cymru.com/ip-asn-mapping), with resolver r (system resolver if nil)
+func CymruASNLookup(r *net.Resolver) ASNLookup {
+	if r == nil {
+		r = net.DefaultResolver
+	}
+
+	return func(ctx context.Context, ip netip.Addr) (*ASNInfo, error) {
+		// origin record: "<ASN> | <prefix> | <country> | <registry> | <allocated>"
+		records, err := r.LookupTXT(ctx, cymruOriginName(ip))
+		if err != nil {
+			return nil, err
+		}
+		fields := txtFields(records)
+		if len(fields) < 4 {
+			return nil, fmt.Errorf(errInvalidASN, strings.Join(records, ""), ip)
+		}
+		// multiple origin autonomous systems are separated by spaces: the first is used
+		asn, err := strconv.Atoi(strings.Fields(fields[0])[0])
+		if err != nil {
+			return nil, fmt.Errorf(errInvalidASN, strings.Join(records, ""), ip)
+		}
+		info := &ASNInfo{IP: ip.String(), ASN: asn, Prefix: fields[1], Country: fields[2], Registry: fields[3]}
+
+		// autonomous system record: "<ASN> | <country> | <registry> | <allocated> | <name>"
+		if records, err := r.LookupTXT(ctx, fmt.Sprintf("AS%d.asn.cymru.com", asn)); err == nil {
+			if fields := txtFields(records); len(fields) >= 5 {
+				info.Name = fields[4]
+			}
+		}
+		return info, nil
+	}
+}
+
+// cymruOriginName return Team Cymru origin DNS name of IP address: reversed IPv4 octets or IPv6 nibbles
+func cymruOriginName(ip netip.Addr) string {
+	if ip.Is4() {
+		b := ip.As4()
+		return fmt.Sprintf("%d.%d.%d.%d.origin.asn.cymru.com", b[3], b[2], b[1], b[0])
+	}
+
+	b := ip.As16()
+	nibbles := make([]string, 0, 32)
+	for i := len(b) - 1; i >= 0; i-- {
+		nibbles = append(nibbles, strconv.FormatUint(uint64(b[i]&0x0f), 16), strconv.FormatUint(uint64(b[i]>>4), 16))
+	}
+	return strings.Join(nibbles, ".") + ".origin6.asn.cymru.com"
+}
+
+// txtFields return trimmed "|" separated fields of DNS TXT record
+func txtFields(records []string) []string {
+	if len(records) == 0 {
+		return nil
+	}
+	fields := strings.Split(records[0], "|")
+	for i, f := range fields {
+		fields[i] = strings.TrimSpace(f)
+	}
+	return fields
+}
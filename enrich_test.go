@@ -0,0 +1,71 @@
+package adstxt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+// TestWithEnrichers test crawler enrich successful Ads.txt response with DNS details of the Ads.txt host
+func TestWithEnrichers(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, "greenadexchange.com,XF7342,DIRECT")
+	}))
+	defer ts.Close()
+
+	asn := func(ctx context.Context, ip netip.Addr) (*ASNInfo, error) {
+		return &ASNInfo{IP: ip.String(), ASN: 64512, Name: "LOCALHOST"}, nil
+	}
+	failing := EnricherFunc(func(ctx context.Context, res *Response) error {
+		return errors.New("enrichment failed")
+	})
+	c := NewCrawler(WithEnrichers(failing, &DNSEnricher{ASN: asn}))
+
+	res, err := c.Get(&Request{URL: ts.URL + "/ads.txt", Domain: "localhost"})
+	if err != nil {
+		t.Fatalf("Expected enrichment error not to fail the request [%s]", err)
+	}
+	info, ok := res.Metadata[MetadataDNS].(*HostInfo)
+	if !ok {
+		t.Fatalf("Expected DNS metadata of the response [%v]", res.Metadata)
+	}
+	if info.Host != "127.0.0.1" || len(info.A) != 1 || info.A[0] != "127.0.0.1" || len(info.AAAA) != 0 {
+		t.Errorf("Expected [127.0.0.1] host address and not [%+v]", info)
+	}
+	if len(info.ASNs) != 1 || info.ASNs[0].ASN != 64512 || info.ASNs[0].Name != "LOCALHOST" {
+		t.Errorf("Expected [AS64512] autonomous system of host address and not [%+v]", info.ASNs)
+	}
+
+	// metadata is included in the response JSON form
+	b, err := json.Marshal(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := &Response{}
+	if err := json.Unmarshal(b, decoded); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := decoded.Metadata[MetadataDNS]; !ok {
+		t.Errorf("Expected DNS metadata in response JSON form [%s]", b)
+	}
+}
+
+// TestCymruOriginName test Team Cymru origin DNS name of IPv4 and IPv6 addresses
+func TestCymruOriginName(t *testing.T) {
+	tests := map[string]string{
+		"216.90.108.31": "31.108.90.216.origin.asn.cymru.com",
+		"2001:4860:b002::68": "8.6.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.2.0.0.b.0.6.8.4.1.0.0.2" +
+			".origin6.asn.cymru.com",
+	}
+	for ip, expected := range tests {
+		if name := cymruOriginName(netip.MustParseAddr(ip)); name != expected {
+			t.Errorf("Expected origin name [%s] of [%s] and not [%s]", expected, ip, name)
+		}
+	}
+}
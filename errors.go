@@ -0,0 +1,253 @@
+package adstxt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"time"
+)
+
+// Ads.txt request failure classes, use errors.Is to check the failure class of Ads.txt request error
+var (
+	// ErrNotFound remote host responded with HTTP 404: Ads.txt file does not exist
+	ErrNotFound = errors.New("Ads.txt file not found")
+	// ErrTimeout request to remote host timed out
+	ErrTimeout = errors.New("Ads.txt request timeout")
+	// ErrTooManyRedirects request stopped after following the maximum number of redirects (see WithMaxRedirects)
+	ErrTooManyRedirects = errors.New("too many redirects")
+	// ErrRedirectOutOfScope redirect to destination outside the original root domain is not allowed
+	ErrRedirectOutOfScope = errors.New("redirect out of root domain scope")
+	// ErrInvalidRedirect redirect destination is not a valid Ads.txt URL
+	ErrInvalidRedirect = errors.New("invalid redirect destination")
+	// ErrRedirectNotAllowed redirect is not allowed by the crawler redirect policy (see WithRedirectPolicy)
+	ErrRedirectNotAllowed = errors.New("redirect not allowed by redirect policy")
+	// ErrBodyTooLarge response body exceeds the maximum body size (see WithMaxBodySize)
+	ErrBodyTooLarge = errors.New("Ads.txt body is too large")
+	// ErrDecompressedTooLarge compressed response body exceeds the maximum decompressed size (see
+	// WithMaxDecompressedSize)
+	ErrDecompressedTooLarge = errors.New("Ads.txt decompressed body is too large")
+	// ErrBudgetExceeded batch crawl request was not sent since the crawl budget was exhausted (see WithBudget)
+	ErrBudgetExceeded = errors.New("batch crawl budget exceeded")
+	// ErrRateLimited remote host responded with HTTP 429 Too Many Requests (see ErrThrottled)
+	ErrRateLimited = errors.New("Ads.txt request rate limited by remote host")
+	// ErrShutdown batch crawl request was not sent since the crawler is shutting down (see Crawler.Shutdown)
+	ErrShutdown = errors.New("crawler is shutting down")
+)
+
+// ErrClientError Ads.txt request failed due to HTTP 4xx status code of remote host response. ErrClientError with
+// HTTP 404 status code matches ErrNotFound
+type ErrClientError struct {
+	StatusCode int    // StatusCode HTTP status code of remote host response
+	Status     string // Status HTTP status line of remote host response
+	Domain     string // Domain root domain of the Ads.txt request
+	URL        string // URL of the Ads.txt file
+}
+
+func (e *ErrClientError) Error() string {
+	return fmt.Sprintf(errHTTPClientError, e.Status, e.Domain, e.URL)
+}
+
+// Is match ErrNotFound for HTTP 404 status code
+func (e *ErrClientError) Is(target error) bool {
+	return target == ErrNotFound && e.StatusCode == 404
+}
+
+// ErrServerError Ads.txt request failed due to HTTP 5xx status code, or any other unexpected status code, of remote
+// host response
+type ErrServerError struct {
+	StatusCode int    // StatusCode HTTP status code of remote host response
+	Status     string // Status HTTP status line of remote host response
+	Domain     string // Domain root domain of the Ads.txt request
+	URL        string // URL of the Ads.txt file
+}
+
+func (e *ErrServerError) Error() string {
+	return fmt.Sprintf(errHTTPGeneralError, e.Status, e.Domain, e.URL)
+}
+
+// ErrRedirect Ads.txt request failed while following HTTP redirect response. Reason is one of ErrTooManyRedirects,
+// ErrRedirectOutOfScope or ErrInvalidRedirect, or *ErrRedirectPolicy that matches ErrRedirectNotAllowed, and can be
+// checked using errors.Is
+type ErrRedirect struct {
+	URL      string // URL that responded with HTTP redirect
+	Location string // Location redirect destination
+	Reason   error  // Reason redirect failure reason
+	msg      string // msg error message
+}
+
+func (e *ErrRedirect) Error() string {
+	return e.msg
+}
+
+// Unwrap return redirect failure reason
+func (e *ErrRedirect) Unwrap() error {
+	return e.Reason
+}
+
+// ErrRequest Ads.txt request failed to get response from remote host (network error, timeout or canceled request).
+// ErrRequest caused by timeout matches ErrTimeout
+type ErrRequest struct {
+	URL string // URL of the Ads.txt file
+	Err error  // Err underlying HTTP client error
+}
+
+func (e *ErrRequest) Error() string {
+	return fmt.Sprintf(errHTTPRequestFailed, e.URL, e.Err)
+}
+
+// Unwrap return the underlying HTTP client error
+func (e *ErrRequest) Unwrap() error {
+	return e.Err
+}
+
+// Is match ErrTimeout for network timeout or exceeded context deadline
+func (e *ErrRequest) Is(target error) bool {
+	if target != ErrTimeout {
+		return false
+	}
+
+	var netErr net.Error
+	return errors.Is(e.Err, context.DeadlineExceeded) || (errors.As(e.Err, &netErr) && netErr.Timeout())
+}
+
+// ErrRequestTimeout Ads.txt request timed out. ErrRequestTimeout is the underlying error of ErrRequest, and holds the
+// request phase that timed out and its time limit. ErrRequestTimeout matches ErrTimeout
+type ErrRequestTimeout struct {
+	URL     string        // URL of the Ads.txt file
+	Phase   TimeoutPhase  // Phase of the request that timed out
+	Timeout time.Duration // Timeout time limit of the phase, zero if the request context deadline was exceeded
+	Err     error         // Err underlying HTTP client error
+}
+
+func (e *ErrRequestTimeout) Error() string {
+	return fmt.Sprintf(errRequestTimeout, e.Phase, e.Timeout, e.Err)
+}
+
+// Unwrap return the underlying HTTP client error
+func (e *ErrRequestTimeout) Unwrap() error {
+	return e.Err
+}
+
+// Is match ErrTimeout
+func (e *ErrRequestTimeout) Is(target error) bool {
+	return target == ErrTimeout
+}
+
+// ErrDNS Ads.txt request failed since the remote host name could not be resolved. ErrDNS caused by DNS lookup timeout
+// matches ErrTimeout
+type ErrDNS struct {
+	Host string // Host name that failed to be resolved
+	URL  string // URL of the Ads.txt file
+	Err  error  // Err underlying DNS lookup error
+}
+
+func (e *ErrDNS) Error() string {
+	return fmt.Sprintf(errDNSLookupFailed, e.URL, e.Host, e.Err)
+}
+
+// Unwrap return the underlying DNS lookup error
+func (e *ErrDNS) Unwrap() error {
+	return e.Err
+}
+
+// Is match ErrTimeout for DNS lookup timeout
+func (e *ErrDNS) Is(target error) bool {
+	var dnsErr *net.DNSError
+	return target == ErrTimeout && errors.As(e.Err, &dnsErr) && dnsErr.IsTimeout
+}
+
+// ErrRobotsDisallowed Ads.txt request is disallowed by robots.txt file of remote host (see WithRobotsTxt)
+type ErrRobotsDisallowed struct {
+	Domain string // Domain root domain of the Ads.txt request
+	URL    string // URL of the Ads.txt file
+}
+
+func (e *ErrRobotsDisallowed) Error() string {
+	return fmt.Sprintf(errRobotsDisallowed, e.Domain, e.URL)
+}
+
+// ErrInvalidContentType Ads.txt request failed since remote host response Content-Type is not text/plain. Use
+// WithIgnoreContentType crawler option to parse Ads.txt file regardless of its Content-Type
+type ErrInvalidContentType struct {
+	URL         string // URL of the Ads.txt file
+	ContentType string // ContentType of remote host response
+}
+
+func (e *ErrInvalidContentType) Error() string {
+	return fmt.Sprintf(errHTTPBadContentType, e.URL, e.ContentType)
+}
+
+// ErrHTMLPage Ads.txt request failed since remote host responded with HTML page (for example "page not found" error
+// page served with HTTP 200 status) instead of Ads.txt file
+type ErrHTMLPage struct {
+	URL      string // URL of the Ads.txt file
+	Redirect string // Redirect location of HTML redirect (meta refresh or JavaScript) found in the page, that was not followed (see WithHTMLRedirects)
+}
+
+func (e *ErrHTMLPage) Error() string {
+	if len(e.Redirect) > 0 {
+		return fmt.Sprintf(errHTTPHTMLRedirect, e.URL, e.Redirect)
+	}
+	return fmt.Sprintf(errHTTPHTMLPage, e.URL)
+}
+
+// ErrLineTooLong Ads.txt file could not be parsed since one of its lines is longer than the maximum line size
+type ErrLineTooLong struct {
+	Index int // Index of the line in the Ads.txt file
+	Limit int // Limit maximum line size in bytes
+}
+
+func (e *ErrLineTooLong) Error() string {
+	return fmt.Sprintf(errLineTooLong, e.Index, e.Limit)
+}
+
+// ErrDomainDead Ads.txt request of batch crawl was not sent since its domain failed the liveness pre-check (see
+// WithLivenessCheck)
+type ErrDomainDead struct {
+	Domain string        // Domain root domain of the Ads.txt request
+	URL    string        // URL of the Ads.txt file
+	Check  LivenessCheck // Check liveness check method that failed
+	Err    error         // Err underlying DNS lookup or HEAD request error
+}
+
+func (e *ErrDomainDead) Error() string {
+	return fmt.Sprintf(errDomainDead, e.Domain, e.Check, e.Err)
+}
+
+// Unwrap return the underlying DNS lookup or HEAD request error
+func (e *ErrDomainDead) Unwrap() error {
+	return e.Err
+}
+
+// ErrInvalidRequestURL input of NewRequestFromURL is not usable for Ads.txt crawling. Reason describes why the input
+// was rejected, and Err holds the underlying URL parse or hostname validation error (if any)
+type ErrInvalidRequestURL struct {
+	Input  string           // Input URL or hostname passed to NewRequestFromURL
+	Reason InvalidURLReason // Reason the input was rejected
+	Err    error            // Err underlying URL parse or hostname validation error (nil if none)
+}
+
+func (e *ErrInvalidRequestURL) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf(errInvalidRequestURL, e.Input, fmt.Sprintf("%s: %s", e.Reason, e.Err))
+	}
+	return fmt.Sprintf(errInvalidRequestURL, e.Input, e.Reason)
+}
+
+// Unwrap return the underlying URL parse or hostname validation error
+func (e *ErrInvalidRequestURL) Unwrap() error {
+	return e.Err
+}
+
+// ErrBlockedAddress Ads.txt request failed since the remote host address is private, loopback or link-local network
+// address, and the crawler blocks such addresses (see WithBlockPrivateAddresses)
+type ErrBlockedAddress struct {
+	Address string     // Address the crawler tried to connect to
+	IP      netip.Addr // IP address of the remote host (invalid if address is not IP address)
+}
+
+func (e *ErrBlockedAddress) Error() string {
+	return fmt.Sprintf(errBlockedAddress, e.Address)
+}
@@ -0,0 +1,48 @@
+package adstxt
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestTypedErrors test Ads.txt request errors can be checked using errors.Is and errors.As
+func TestTypedErrors(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/forbidden/ads.txt":
+			w.WriteHeader(http.StatusForbidden)
+		case "/slow/ads.txt":
+			time.Sleep(100 * time.Millisecond)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	c := NewCrawler(WithTimeout(20 * time.Millisecond))
+
+	_, err := c.Get(&Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"})
+	var clientErr *ErrClientError
+	if !errors.Is(err, ErrNotFound) || !errors.As(err, &clientErr) || clientErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected ErrNotFound client error and not [%v]", err)
+	}
+
+	_, err = c.Get(&Request{URL: ts.URL + "/forbidden/ads.txt", Domain: "127.0.0.1"})
+	if errors.Is(err, ErrNotFound) || !errors.As(err, &clientErr) || clientErr.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected forbidden client error and not [%v]", err)
+	}
+
+	_, err = c.Get(&Request{URL: ts.URL + "/slow/ads.txt", Domain: "127.0.0.1"})
+	var requestErr *ErrRequest
+	if !errors.Is(err, ErrTimeout) || !errors.As(err, &requestErr) {
+		t.Errorf("Expected ErrTimeout request error and not [%v]", err)
+	}
+
+	redirectErr := &ErrRedirect{Reason: ErrTooManyRedirects}
+	if !errors.Is(redirectErr, ErrTooManyRedirects) || errors.Is(redirectErr, ErrInvalidRedirect) {
+		t.Errorf("Expected redirect error to match its reason only")
+	}
+}
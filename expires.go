@@ -0,0 +1,155 @@
+package adstxt
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Ads.txt file expiration date sources (see Response.ExpiresSource)
+const (
+	// ExpiresFromCacheControl expiration date is based on Cache-Control s-maxage or max-age directive
+	ExpiresFromCacheControl ExpiresSource = "cache-control"
+	// ExpiresFromHeader expiration date is based on Expires header
+	ExpiresFromHeader ExpiresSource = "expires"
+	// ExpiresFromDefault response has no valid Cache-Control or Expires header: default expiration date is used
+	ExpiresFromDefault ExpiresSource = "default"
+)
+
+// ExpiresSource source of Ads.txt file expiration date
+type ExpiresSource string
+
+// DefaultExpiration time until Ads.txt file expires when the response has no valid Cache-Control or Expires header
+// (section 3.6 EXPIRATION of IAB Ads.txt specification), unless the crawler is set with other default (see
+// WithDefaultExpiration)
+const DefaultExpiration = 7 * 24 * time.Hour
+
+// expiresLayouts date formats of Expires header tried in order: RFC 7231 formats (IMF-fixdate, obsolete RFC 850 and
+// ANSI C asctime formats), followed by common malformed variants
+var expiresLayouts = []string{
+	time.RFC1123,
+	time.RFC850,
+	time.ANSIC,
+	time.RFC1123Z,
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"Mon, 02-Jan-2006 15:04:05 MST",
+	"Mon, 2-Jan-2006 15:04:05 MST",
+	"Mon, 02 Jan 06 15:04:05 MST",
+	"Monday, 02 Jan 2006 15:04:05 MST",
+	"02 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 MST",
+	"Mon Jan 2 15:04:05 MST 2006",
+	time.RFC3339,
+}
+
+// setExpires set Ads.txt response expiration date and its source from HTTP response headers (see parseExpires), and
+// the raw Expires header value. Response without valid expiration headers expires after the crawler default
+// expiration (see WithDefaultExpiration). Expiration date earlier than the crawler minimum TTL (see WithMinTTL) is
+// clamped
+func (c *Crawler) setExpires(r *Response, res *http.Response) {
+	r.ExpiresHeader = res.Header.Get("Expires")
+
+	expires, source, err := c.parseExpires(res)
+	if err != nil {
+		expires, source = r.Fetched.Add(c.expiration()), ExpiresFromDefault
+	}
+	if min := r.Fetched.Add(c.minTTL); expires.Before(min) {
+		expires = min
+	}
+	r.Expires, r.ExpiresSource = expires, source
+}
+
+// expiration return the crawler default expiration of Ads.txt files (DefaultExpiration if not set)
+func (c *Crawler) expiration() time.Duration {
+	if c.defaultExpiration > 0 {
+		return c.defaultExpiration
+	}
+	return DefaultExpiration
+}
+
+// ExpiresByDefault return true if the response expiration date is the crawler default expiration, since the response
+// has no valid Cache-Control or Expires header
+func (r *Response) ExpiresByDefault() bool {
+	return r.ExpiresSource == ExpiresFromDefault
+}
+
+// parse Ads.txt file expiration date from the response Cache-Control header (s-maxage or max-age directive) or, if
+// the response has no Cache-Control freshness directive, from the response Expires header. Return the expiration
+// date and the header it is based on
+func (c *Crawler) parseExpires(res *http.Response) (time.Time, ExpiresSource, error) {
+	if maxAge, ok := parseMaxAge(res.Header); ok {
+		return c.now().UTC().Add(maxAge), ExpiresFromCacheControl, nil
+	}
+
+	expires := res.Header.Get("Expires")
+	if len(expires) == 0 {
+		return time.Time{}, ExpiresFromDefault, errors.New("Failed to parse expires from response header")
+	}
+
+	parsedHeader, err := parseExpiresHeader(expires)
+	if err != nil {
+		c.log(res.Request.Context(), c.logLevels.Warning, "Failed to parse Ads.txt Expires header", "url", res.Request.URL.String(),
+			"expires", expires, "error", err)
+		return time.Time{}, ExpiresFromDefault, err
+	}
+
+	return parsedHeader, ExpiresFromHeader, nil
+}
+
+// parseExpiresHeader parse Expires header value in any of the expiresLayouts formats, regardless of case, quotes and
+// extra white space. Numeric value (such as "0" or "-1") represents a time in the past (RFC 7234 section 5.3)
+func parseExpiresHeader(value string) (time.Time, error) {
+	value = strings.ToUpper(strings.Join(strings.Fields(strings.Trim(value, `" `)), " "))
+	if _, err := strconv.Atoi(value); err == nil {
+		return time.Unix(0, 0).UTC(), nil
+	}
+
+	t, err := http.ParseTime(value)
+	if err == nil {
+		return t.UTC(), nil
+	}
+	for _, layout := range expiresLayouts {
+		if parsed, perr := time.Parse(layout, value); perr == nil {
+			return parsed.UTC(), nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// parseMaxAge return the remaining freshness lifetime of response from Cache-Control s-maxage directive, or max-age
+// directive if s-maxage is not set, less the response Age (time the response was held by upstream caches)
+func parseMaxAge(header http.Header) (time.Duration, bool) {
+	maxAge, sMaxAge := -1, -1
+	for _, v := range header.Values("Cache-Control") {
+		for _, directive := range strings.Split(v, ",") {
+			name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+			seconds, err := strconv.Atoi(strings.Trim(strings.TrimSpace(value), `"`))
+			if err != nil || seconds < 0 {
+				continue
+			}
+			switch strings.ToLower(strings.TrimSpace(name)) {
+			case "max-age":
+				maxAge = seconds
+			case "s-maxage":
+				sMaxAge = seconds
+			}
+		}
+	}
+
+	if sMaxAge >= 0 {
+		maxAge = sMaxAge
+	}
+	if maxAge < 0 {
+		return 0, false
+	}
+
+	if age, err := strconv.Atoi(strings.TrimSpace(header.Get("Age"))); err == nil && age > 0 {
+		maxAge -= age
+		if maxAge < 0 {
+			maxAge = 0
+		}
+	}
+	return time.Duration(maxAge) * time.Second, true
+}
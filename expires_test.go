@@ -0,0 +1,115 @@
+package adstxt
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestParseExpiresHeader test parse Expires header in RFC 7231 date formats and common malformed variants
+func TestParseExpiresHeader(t *testing.T) {
+	expected := time.Date(2030, time.January, 2, 15, 4, 5, 0, time.UTC)
+	tests := []string{
+		"Wed, 02 Jan 2030 15:04:05 GMT",
+		"Wednesday, 02-Jan-30 15:04:05 GMT",
+		"Wed Jan  2 15:04:05 2030",
+		"Wed, 02 Jan 2030 15:04:05 +0000",
+		"Wed, 2 Jan 2030 15:04:05 GMT",
+		"Wed, 02-Jan-2030 15:04:05 GMT",
+		"Wed, 02 Jan 30 15:04:05 GMT",
+		"Wednesday, 02 Jan 2030 15:04:05 GMT",
+		"02 Jan 2030 15:04:05 GMT",
+		"Wed, 02 Jan 2030 15:04:05 UTC",
+		"wed, 02 jan 2030 15:04:05 gmt",
+		` "Wed,  02 Jan 2030   15:04:05 GMT" `,
+		"Mon, 02 Jan 2030 15:04:05 GMT",
+		"2030-01-02T15:04:05Z",
+	}
+
+	for _, value := range tests {
+		parsed, err := parseExpiresHeader(value)
+		if err != nil {
+			t.Errorf("Expected Expires [%s] to be parsed and not [%s]", value, err)
+			continue
+		}
+		if !parsed.Equal(expected) {
+			t.Errorf("Expected Expires [%s] to be [%s] and not [%s]", value, expected, parsed)
+		}
+	}
+
+	for _, value := range []string{"0", "-1"} {
+		if parsed, err := parseExpiresHeader(value); err != nil || !parsed.Before(time.Now()) {
+			t.Errorf("Expected Expires [%s] to be in the past and not [%s] [%v]", value, parsed, err)
+		}
+	}
+	if _, err := parseExpiresHeader("tomorrow"); err == nil {
+		t.Error("Expected invalid Expires header to fail")
+	}
+}
+
+// TestSetExpires test Ads.txt response expiration date, source, raw header and minimum TTL
+func TestSetExpires(t *testing.T) {
+	future := time.Now().UTC().AddDate(0, 0, 1).Format(http.TimeFormat)
+	past := time.Now().UTC().AddDate(0, 0, -1).Format(http.TimeFormat)
+
+	tests := []struct {
+		header  http.Header
+		minTTL  time.Duration
+		source  ExpiresSource
+		expires time.Duration
+	}{
+		{http.Header{}, 0, ExpiresFromDefault, 7 * 24 * time.Hour},
+		{http.Header{"Expires": []string{"invalid"}}, 0, ExpiresFromDefault, 7 * 24 * time.Hour},
+		{http.Header{"Expires": []string{future}}, 0, ExpiresFromHeader, 24 * time.Hour},
+		{http.Header{"Expires": []string{past}}, 0, ExpiresFromHeader, 0},
+		{http.Header{"Expires": []string{past}}, time.Hour, ExpiresFromHeader, time.Hour},
+		{http.Header{"Expires": []string{"0"}}, time.Hour, ExpiresFromHeader, time.Hour},
+		{http.Header{"Expires": []string{future}, "Cache-Control": []string{"max-age=60"}}, time.Hour, ExpiresFromCacheControl, time.Hour},
+		{http.Header{"Cache-Control": []string{"max-age=7200"}}, time.Hour, ExpiresFromCacheControl, 2 * time.Hour},
+	}
+
+	for _, test := range tests {
+		c := NewCrawler(WithMinTTL(test.minTTL))
+		req := &Request{URL: "http://example.com/ads.txt", Domain: "example.com"}
+		res := &http.Response{StatusCode: http.StatusOK, Header: test.header, Request: httptest.NewRequest(http.MethodGet, req.URL, nil)}
+
+		r := c.newResponse(req, res, &Records{}, []byte{}, 1, time.Now())
+		if r.ExpiresSource != test.source {
+			t.Errorf("Expected headers %v expires source [%s] and not [%s]", test.header, test.source, r.ExpiresSource)
+		}
+		if r.ExpiresHeader != test.header.Get("Expires") {
+			t.Errorf("Expected raw Expires header [%s] and not [%s]", test.header.Get("Expires"), r.ExpiresHeader)
+		}
+		if d := r.Expires.Sub(r.Fetched); d > test.expires+time.Second || d < test.expires-time.Minute {
+			t.Errorf("Expected headers %v with minimum TTL [%s] to expire in [%s] and not [%s]", test.header, test.minTTL, test.expires, d)
+		}
+	}
+}
+
+// TestWithDefaultExpiration test crawler default expiration of Ads.txt file without expiration headers
+func TestWithDefaultExpiration(t *testing.T) {
+	req := &Request{URL: "http://example.com/ads.txt", Domain: "example.com"}
+	tests := []struct {
+		opts    []Option
+		header  http.Header
+		expires time.Duration
+		byDef   bool
+	}{
+		{nil, http.Header{}, DefaultExpiration, true},
+		{[]Option{WithDefaultExpiration(24 * time.Hour)}, http.Header{}, 24 * time.Hour, true},
+		{[]Option{WithDefaultExpiration(time.Minute), WithMinTTL(time.Hour)}, http.Header{}, time.Hour, true},
+		{[]Option{WithDefaultExpiration(24 * time.Hour)}, http.Header{"Cache-Control": []string{"max-age=60"}}, time.Minute, false},
+	}
+
+	for _, test := range tests {
+		res := &http.Response{StatusCode: http.StatusOK, Header: test.header, Request: httptest.NewRequest(http.MethodGet, req.URL, nil)}
+		r := NewCrawler(test.opts...).newResponse(req, res, &Records{}, []byte{}, 1, time.Now())
+		if r.ExpiresByDefault() != test.byDef {
+			t.Errorf("Expected headers %v default expiration to be [%t] [%s]", test.header, test.byDef, r.ExpiresSource)
+		}
+		if d := r.Expires.Sub(r.Fetched); d > test.expires+time.Second || d < test.expires-time.Second {
+			t.Errorf("Expected headers %v to expire in [%s] and not [%s]", test.header, test.expires, d)
+		}
+	}
+}
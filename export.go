@@ -0,0 +1,126 @@
+package adstxt
+
+import (
+	"sync"
+	"time"
+)
+
+// RecordRow flat row of single Ads.txt data record, for export to columnar analytics formats (e.g. Parquet). Field
+// values are normalized (see Records.Normalize), and the struct tags match github.com/segmentio/parquet-go and
+// github.com/parquet-go/parquet-go schema conventions, so RecordRow can be used as Parquet schema as is
+type RecordRow struct {
+	Domain          string    `json:"domain" parquet:"domain"`                              // Domain root domain of the Ads.txt request
+	URL             string    `json:"url" parquet:"url"`                                    // URL Ads.txt URL of the request (before redirects)
+	Line            int64     `json:"line" parquet:"line"`                                  // Line index of the data record in the Ads.txt file
+	AdSystem        string    `json:"adSystem" parquet:"ad_system"`                         // AdSystem advertising system domain
+	AccountID       string    `json:"accountId" parquet:"account_id"`                       // AccountID publisher account ID
+	Relationship    string    `json:"relationship" parquet:"relationship"`                  // Relationship account type: DIRECT or RESELLER
+	CertAuthorityID string    `json:"certAuthorityId" parquet:"cert_authority_id,optional"` // CertAuthorityID certification authority ID, empty if not set
+	CrawledAt       time.Time `json:"crawledAt" parquet:"crawled_at,timestamp"`             // CrawledAt time the Ads.txt file was fetched
+}
+
+// RecordRowWriter write rows of Ads.txt data records. Parquet files are written with the parquet subpackage Writer
+// (github.com/tzafrirben/go-adstxt-crawler/adstxt/parquet). The interface matches the Write method of Parquet generic
+// writer as well, so Parquet file can be written with github.com/parquet-go/parquet-go:
+//
+//	w := parquet.NewGenericWriter[adstxt.RecordRow](f)
+//	defer w.Close()
+//	exporter := adstxt.NewRecordExporter(w)
+type RecordRowWriter interface {
+	Write(rows []RecordRow) (int, error)
+}
+
+// RecordRows return row of each data record of Ads.txt response, in order of the Ads.txt file. Rows of response
+// without fetch time are set as crawled now
+func RecordRows(res *Response) []RecordRow {
+	return recordRows(res, time.Now())
+}
+
+// recordRows return row of each data record of Ads.txt response, crawled at now if the response has no fetch time
+func recordRows(res *Response, now time.Time) []RecordRow {
+	if res.Records == nil {
+		return []RecordRow{}
+	}
+
+	crawledAt := res.Fetched
+	if crawledAt.IsZero() {
+		crawledAt = now.UTC()
+	}
+	url := responseKey(res.Request, res)
+
+	rows := make([]RecordRow, 0, len(res.DataRecords))
+	for _, dr := range res.DataRecords {
+		n := dr.normalized()
+		rows = append(rows, RecordRow{
+			Domain:          res.Domain,
+			URL:             url,
+			Line:            int64(res.Line(dr)),
+			AdSystem:        n.AdverterDomain,
+			AccountID:       n.PublisherAccountID,
+			Relationship:    string(n.AccountType),
+			CertAuthorityID: n.CertAuthorityID,
+			CrawledAt:       crawledAt,
+		})
+	}
+	return rows
+}
+
+// RecordExporter Handler that writes data records rows of each successful Ads.txt response to RecordRowWriter (see
+// RecordRows), so batch crawl results can be exported while crawling. Failed requests and NotModified responses are
+// not exported. Write errors do not stop the crawl: use Err to check whether all rows were written
+type RecordExporter struct {
+	Clock Clock // Clock source of the crawl time of rows of responses without fetch time (system clock if nil)
+
+	w    RecordRowWriter
+	rows int64 // number of rows written
+	err  error // first write error
+	mu   sync.Mutex
+}
+
+// NewRecordExporter create new RecordExporter that writes rows to w. Writes are serialized, so w does not have to be
+// safe to use from multiple goroutines
+func NewRecordExporter(w RecordRowWriter) *RecordExporter {
+	return &RecordExporter{w: w}
+}
+
+// Handle is the Handler interface implementation for RecordExporter
+func (e *RecordExporter) Handle(req *Request, res *Response, err error) {
+	if err != nil || res == nil || res.NotModified {
+		return
+	}
+	e.Export(res)
+}
+
+// Export write data records rows of Ads.txt response
+func (e *RecordExporter) Export(res *Response) error {
+	rows := recordRows(res, clockNow(e.Clock))
+	if len(rows) == 0 {
+		return nil
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	n, err := e.w.Write(rows)
+	e.rows += int64(n)
+	if err != nil && e.err == nil {
+		e.err = err
+	}
+	return err
+}
+
+// Rows return the number of rows written
+func (e *RecordExporter) Rows() int64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.rows
+}
+
+// Err return the first write error, or nil if all rows were written
+func (e *RecordExporter) Err() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.err
+}
@@ -0,0 +1,63 @@
+package adstxt
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// rowWriter RecordRowWriter mock that collects written rows
+type rowWriter struct {
+	rows []RecordRow
+	err  error
+}
+
+func (w *rowWriter) Write(rows []RecordRow) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	w.rows = append(w.rows, rows...)
+	return len(rows), nil
+}
+
+// TestRecordRows test flattening Ads.txt response data records to rows
+func TestRecordRows(t *testing.T) {
+	req, _ := NewRequest("example.com")
+	records, _ := ParseBody([]byte("# comment\nGreenAdExchange.com, XF7342, direct\ngoogle.com,pub-1,RESELLER,F08C47FEC0942FA0"))
+	fetched := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	rows := RecordRows(&Response{Request: req, Records: records, Fetched: fetched})
+	if len(rows) != 2 {
+		t.Fatalf("Expected [2] rows and not [%d]", len(rows))
+	}
+
+	expected := RecordRow{Domain: "example.com", URL: req.URL, Line: 2, AdSystem: "greenadexchange.com", AccountID: "XF7342", Relationship: "DIRECT", CrawledAt: fetched}
+	if rows[0] != expected {
+		t.Errorf("Expected row [%+v] and not [%+v]", expected, rows[0])
+	}
+	if rows[1].CertAuthorityID != "f08c47fec0942fa0" || rows[1].Line != 3 {
+		t.Errorf("Expected normalized certification authority ID and not [%s]", rows[1].CertAuthorityID)
+	}
+}
+
+// TestRecordExporter test exporting batch crawl results rows
+func TestRecordExporter(t *testing.T) {
+	req, _ := NewRequest("example.com")
+	records, _ := ParseBody([]byte("greenadexchange.com,XF7342,DIRECT"))
+
+	w := &rowWriter{}
+	e := NewRecordExporter(w)
+	e.Handle(req, &Response{Request: req, Records: records}, nil)
+	e.Handle(req, nil, ErrNotFound)
+	e.Handle(req, &Response{Request: req, Records: &Records{}, NotModified: true}, nil)
+
+	if e.Rows() != 1 || len(w.rows) != 1 || e.Err() != nil {
+		t.Errorf("Expected only successful response rows to be exported and not [%d]", e.Rows())
+	}
+
+	w.err = errors.New("disk full")
+	e.Handle(req, &Response{Request: req, Records: records}, nil)
+	if e.Err() != w.err {
+		t.Errorf("Expected write error and not [%v]", e.Err())
+	}
+}
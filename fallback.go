@@ -0,0 +1,134 @@
+package adstxt
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync/atomic"
+)
+
+// getWithFallback crawl and parse Ads.txt file from remote host, and try the other variants of the Ads.txt URL (see
+// urlVariants) if the request fails. Return the response of the first variant that succeeded, or the error of the
+// original request URL if all variants failed
+func (c *Crawler) getWithFallback(ctx context.Context, req *Request) (*Response, error) {
+	urls := urlVariants(req)
+	variants := make([]*Request, len(urls))
+	for i, v := range urls {
+		r := *req
+		r.URL = v
+		variants[i] = &r
+	}
+
+	return c.fetchVariants(ctx, req, variants, func(ctx context.Context, i int, r *Request) (*Response, bool, error) {
+		// partial response of request that timed out while reading the body is returned with the timeout error (see
+		// WithPartialBody)
+		res, err := c.get(ctx, r)
+		if res != nil {
+			res.Variant = urls[i]
+		}
+		return res, err == nil || res != nil, err
+	})
+}
+
+// variantFetch fetch single variant of Ads.txt request (see fetchVariants), and return whether the variant result is
+// the request result, or the next variant should be tried
+type variantFetch func(ctx context.Context, i int, r *Request) (*Response, bool, error)
+
+// fetchVariants crawl variants of Ads.txt request in order (for example the request URL over other schemes or paths),
+// until fetch of variant returns the request result: req is set to the variant request of the response, and HTTP
+// requests sent for the variants that failed before it are added to the response Attempts. Variants are crawled from copies of the
+// original request, since the request URL is changed when following redirects. Return the error of the first variant
+// if no variant returned the request result, or once ctx is done
+func (c *Crawler) fetchVariants(ctx context.Context, req *Request, variants []*Request, fetch variantFetch) (*Response, error) {
+	var firstErr error
+	attempts := 0
+
+	for i, r := range variants {
+		// do not try other variants once context is done
+		if i > 0 && ctx.Err() != nil {
+			break
+		}
+
+		counter := &attemptCounter{}
+		res, done, err := fetch(withAttemptCounter(ctx, counter), i, r)
+		if done {
+			if res != nil {
+				*req = *r
+				res.Request = req
+				res.Attempts += attempts
+			}
+			return res, err
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+		attempts += counter.count()
+	}
+
+	// failed variants are counted by the variant of outer request variants that failed (e.g. failed fallback URLs of
+	// request scheme)
+	countAttempts(ctx, attempts)
+	return nil, firstErr
+}
+
+// attemptCounter count HTTP requests sent for Ads.txt request variant that failed, so the response of the variant that
+// succeeded reports the HTTP requests sent by the failed variants before it (see Response Attempts)
+type attemptCounter struct {
+	n atomic.Int64
+}
+
+// count return the number of HTTP requests counted
+func (a *attemptCounter) count() int {
+	return int(a.n.Load())
+}
+
+// attemptCounterContextKey context key of the attempt counter of Ads.txt request variant
+type attemptCounterContextKey struct{}
+
+// withAttemptCounter return copy of ctx that holds attempt counter of Ads.txt request variant
+func withAttemptCounter(ctx context.Context, counter *attemptCounter) context.Context {
+	return context.WithValue(ctx, attemptCounterContextKey{}, counter)
+}
+
+// countAttempts add n HTTP requests to the attempt counter held by ctx, if any
+func countAttempts(ctx context.Context, n int) {
+	if counter, ok := ctx.Value(attemptCounterContextKey{}).(*attemptCounter); ok {
+		counter.n.Add(int64(n))
+	}
+}
+
+// urlVariants return the Ads.txt URL variants to crawl, starting with the request URL: HTTPS and HTTP, with and
+// without "www" subdomain. "www" variants are added only for Ads.txt URL of the root domain
+func urlVariants(req *Request) []string {
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return []string{req.URL}
+	}
+
+	// URL host of internationalized domain is in punycode form
+	root := asciiHost(req.Domain)
+
+	hosts := []string{u.Host}
+	switch host := u.Hostname(); host {
+	case root:
+		hosts = append(hosts, strings.Replace(u.Host, host, "www."+host, 1))
+	case "www." + root:
+		hosts = append(hosts, strings.Replace(u.Host, host, root, 1))
+	}
+
+	variants := []string{req.URL}
+	for _, scheme := range []string{"https", "http"} {
+		for _, host := range hosts {
+			v := *u
+			v.Scheme = scheme
+			v.Host = host
+
+			s := v.String()
+			if s != req.URL {
+				variants = append(variants, s)
+			}
+		}
+	}
+
+	return variants
+}
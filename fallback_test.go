@@ -0,0 +1,114 @@
+package adstxt
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestURLVariants test Ads.txt URL variants order
+func TestURLVariants(t *testing.T) {
+	tests := []struct {
+		req      *Request
+		expected []string
+	}{
+		{
+			&Request{URL: "https://example.com/ads.txt", Domain: "example.com"},
+			[]string{"https://example.com/ads.txt", "https://www.example.com/ads.txt", "http://example.com/ads.txt", "http://www.example.com/ads.txt"},
+		},
+		{
+			&Request{URL: "http://www.example.com/ads.txt", Domain: "example.com"},
+			[]string{"http://www.example.com/ads.txt", "https://www.example.com/ads.txt", "https://example.com/ads.txt", "http://example.com/ads.txt"},
+		},
+		{
+			&Request{URL: "http://dev.example.com/ads.txt", Domain: "example.com"},
+			[]string{"http://dev.example.com/ads.txt", "https://dev.example.com/ads.txt"},
+		},
+	}
+
+	for _, test := range tests {
+		variants := urlVariants(test.req)
+		if strings.Join(variants, " ") != strings.Join(test.expected, " ") {
+			t.Errorf("Expected [%s] URL variants to be [%v] and not [%v]", test.req.URL, test.expected, variants)
+		}
+	}
+}
+
+// TestCrawlerFallback test crawler try other Ads.txt URL variants when the request fails
+func TestCrawlerFallback(t *testing.T) {
+	sent := []string{}
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			sent = append(sent, req.URL.String())
+
+			// Ads.txt file is served only on HTTP www subdomain
+			if req.URL.String() != "http://www.example.com/ads.txt" {
+				return &http.Response{StatusCode: http.StatusNotFound, Status: "404 Not Found", Body: http.NoBody, Request: req}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       io.NopCloser(strings.NewReader("greenadexchange.com,XF7342,DIRECT")),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	req := &Request{URL: "https://example.com/ads.txt", Domain: "example.com"}
+	res, err := NewCrawler(WithHTTPClient(client), WithFallback(true)).Get(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sent) != 4 {
+		t.Errorf("Expected all [4] URL variants to be crawled and not [%v]", sent)
+	}
+	if res.Variant != "http://www.example.com/ads.txt" || res.Request != req || req.URL != res.Variant {
+		t.Errorf("Expected response to hold the URL variant that succeeded and not [%s]", res.Variant)
+	}
+	if res.Attempts != 4 {
+		t.Errorf("Expected [4] attempts and not [%d]", res.Attempts)
+	}
+
+	// without fallback only the request URL is crawled
+	sent = []string{}
+	if _, err := NewCrawler(WithHTTPClient(client)).Get(&Request{URL: "https://example.com/ads.txt", Domain: "example.com"}); err == nil || len(sent) != 1 {
+		t.Errorf("Expected single failed request without fallback")
+	}
+}
+
+// TestCrawlerFallbackAttempts test response attempts include all HTTP requests sent for the URL variants that failed,
+// including their retries and the request schemes that failed
+func TestCrawlerFallbackAttempts(t *testing.T) {
+	sent := 0
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			sent++
+			switch {
+			case req.URL.Scheme == "https":
+				return nil, errors.New("connection refused")
+			case req.URL.String() != "http://www.example.com/ads.txt":
+				return &http.Response{StatusCode: http.StatusServiceUnavailable, Status: "503 Service Unavailable", Body: http.NoBody, Request: req}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       io.NopCloser(strings.NewReader("greenadexchange.com,XF7342,DIRECT")),
+				Request:    req,
+			}, nil
+		}),
+	}
+	c := NewCrawler(WithHTTPClient(client), WithFallback(true), WithRetry(RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond}))
+
+	req := &Request{URL: "http://example.com/ads.txt", Domain: "example.com", Schemes: []string{"https", "http"}}
+	res, err := c.Get(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Attempts != sent || res.Variant != "http://www.example.com/ads.txt" {
+		t.Errorf("Expected [%d] attempts of all HTTP requests sent and not [%d]", sent, res.Attempts)
+	}
+}
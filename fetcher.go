@@ -0,0 +1,128 @@
+package adstxt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// fetcher errors
+const (
+	errFetchNotFound = "[%s] %w: %s"
+)
+
+// Fetcher retrieve and parse Ads.txt file of request from any source: remote host over HTTP (the crawler default
+// fetcher), local files, object storage or memory, so the crawler pipeline (batch crawls, handlers, cache, diff and
+// validation) can run against stored Ads.txt files in tests and offline audits. Fetcher returns error that matches
+// ErrNotFound if the Ads.txt file does not exist. Implementations must be safe to use from multiple goroutines
+type Fetcher interface {
+	Fetch(ctx context.Context, req *Request) (*Response, error)
+}
+
+// FetcherFunc is a function signature that implements the Fetcher interface
+type FetcherFunc func(ctx context.Context, req *Request) (*Response, error)
+
+// Fetch is the Fetcher interface implementation for the FetcherFunc type
+func (f FetcherFunc) Fetch(ctx context.Context, req *Request) (*Response, error) {
+	return f(ctx, req)
+}
+
+// Fetch is the Fetcher interface implementation for Crawler (see GetWithContext)
+func (c *Crawler) Fetch(ctx context.Context, req *Request) (*Response, error) {
+	return c.GetWithContext(ctx, req)
+}
+
+// fetch retrieve Ads.txt file of request with the crawler fetcher, or from remote host over HTTP by default
+func (c *Crawler) fetch(ctx context.Context, req *Request) (*Response, error) {
+	switch {
+	case c.fetcher != nil:
+		if err := c.checkDomain(req); err != nil {
+			return nil, err
+		}
+		return c.fetcher.Fetch(ctx, req)
+	case c.fallback:
+		return c.getWithFallback(ctx, req)
+	}
+	return c.get(ctx, req)
+}
+
+// NewResponse create new Ads.txt response of request from Ads.txt file body retrieved by custom Fetcher: the body is
+// parsed (see ParseBody), and the response is set as fetched now with HTTP 200 status code
+func NewResponse(req *Request, body []byte) (*Response, error) {
+	return newResponseAt(req, body, time.Now())
+}
+
+// newResponseAt create new Ads.txt response of request from Ads.txt file body, fetched at now
+func newResponseAt(req *Request, body []byte, now time.Time) (*Response, error) {
+	records, err := ParseBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Response{
+		Request:       req,
+		Records:       records,
+		Expires:       now.UTC().Add(DefaultExpiration),
+		ExpiresSource: ExpiresFromDefault,
+		FinalURL:      req.URL,
+		StatusCode:    200,
+		ContentLength: int64(len(body)),
+		Fetched:       now.UTC(),
+		RawBody:       body,
+		SHA256:        bodyHash(body),
+		Redirects:     []*Redirect{},
+	}, nil
+}
+
+// FSFetcher Fetcher that reads Ads.txt files from file system: the file of each request is read from <host>/<path>,
+// where host is the lower case host name of the request URL (for example example.com/ads.txt). Use os.DirFS for local
+// directory, fstest.MapFS for in-memory files, or any fs.FS implementation of object storage
+type FSFetcher struct {
+	Clock Clock // Clock source of the fetch time and expiration date of responses (system clock if nil)
+
+	fsys fs.FS
+}
+
+// NewFSFetcher create new FSFetcher that reads Ads.txt files from fsys
+func NewFSFetcher(fsys fs.FS) *FSFetcher {
+	return &FSFetcher{fsys: fsys}
+}
+
+// Fetch is the Fetcher interface implementation for FSFetcher
+func (f *FSFetcher) Fetch(ctx context.Context, req *Request) (*Response, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	name, err := fsPath(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := fs.ReadFile(f.fsys, name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf(errFetchNotFound, req.URL, ErrNotFound, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newResponseAt(req, body, clockNow(f.Clock))
+}
+
+// fsPath return file system path of Ads.txt request file
+func fsPath(req *Request) (string, error) {
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		return "", err
+	}
+	p := req.filePath()
+	if len(u.Path) > 0 {
+		p = path.Clean(u.Path)
+	}
+	return strings.TrimSuffix(strings.ToLower(u.Hostname()), ".") + p, nil
+}
@@ -0,0 +1,90 @@
+package adstxt
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// TestFSFetcher test crawling Ads.txt files from file system
+func TestFSFetcher(t *testing.T) {
+	fsys := fstest.MapFS{
+		"example.com/ads.txt":     {Data: []byte("greenadexchange.com,XF7342,DIRECT\ngoogle.com,pub-1234,RESELLER")},
+		"example.com/app-ads.txt": {Data: []byte("google.com,pub-5678,DIRECT")},
+	}
+	c := NewCrawler(WithFetcher(NewFSFetcher(fsys)))
+
+	req, _ := NewRequest("https://Example.com")
+	res, err := c.Get(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.DataRecords) != 2 || res.Request != req || res.StatusCode != 200 || len(res.SHA256) == 0 {
+		t.Errorf("Expected Ads.txt response of local file with [2] records and not [%d]", len(res.DataRecords))
+	}
+
+	appReq, _ := NewAppAdsTxtRequest("http://example.com")
+	res, err = c.Get(appReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.DataRecords) != 1 || res.DataRecords[0].PublisherAccountID != "pub-5678" {
+		t.Errorf("Expected app-ads.txt response of local file")
+	}
+
+	missing, _ := NewRequest("missing.com")
+	if _, err = c.Get(missing); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for missing local file and not [%v]", err)
+	}
+
+	// batch crawl with custom fetcher
+	var mu sync.Mutex
+	records := 0
+	c.GetMultiple([]*Request{req, appReq, missing}, HandlerFunc(func(req *Request, res *Response, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err == nil {
+			records += len(res.DataRecords)
+		}
+	}))
+	if records != 3 {
+		t.Errorf("Expected batch crawl of local files to parse [3] records and not [%d]", records)
+	}
+}
+
+// TestFetcherFunc test crawler with custom fetcher function
+func TestFetcherFunc(t *testing.T) {
+	f := FetcherFunc(func(ctx context.Context, req *Request) (*Response, error) {
+		return NewResponse(req, []byte("greenadexchange.com,XF7342,DIRECT"))
+	})
+
+	res, err := NewCrawler(WithFetcher(f)).Get(&Request{URL: "http://example.com/ads.txt", Domain: "example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.DataRecords) != 1 || res.FinalURL != "http://example.com/ads.txt" {
+		t.Errorf("Expected response of custom fetcher")
+	}
+
+	// crawler is HTTP fetcher of other crawlers
+	var _ Fetcher = NewCrawler()
+}
+
+// TestFSFetcherClock test local Ads.txt file responses fetch and expiration time are set by the fetcher clock
+func TestFSFetcherClock(t *testing.T) {
+	fetched := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	f := NewFSFetcher(fstest.MapFS{"example.com/ads.txt": {Data: []byte("greenadexchange.com,XF7342,DIRECT")}})
+	f.Clock = &testClock{now: fetched}
+
+	req, _ := NewRequest("example.com")
+	res, err := NewCrawler(WithFetcher(f)).Get(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Fetched.Equal(fetched) || !res.Expires.Equal(fetched.Add(DefaultExpiration)) {
+		t.Errorf("Expected response fetched at clock time [%s] and not [%s]", fetched, res.Fetched)
+	}
+}
@@ -0,0 +1,62 @@
+package adstxt
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// Ads.txt data record field count tolerances (see FieldPolicy)
+const (
+	// FieldsDefault default handling: records with 2 fields are rejected, and fields that follow the certification
+	// authority ID are stored as extension fields with low severity parse warning
+	FieldsDefault FieldTolerance = iota
+	// FieldsReject reject the record with high severity parse warning
+	FieldsReject
+	// FieldsWarn accept the record with low severity parse warning, and ignore extra fields
+	FieldsWarn
+	// FieldsStore accept the record with low severity parse warning, and store extra fields as extension fields. For
+	// records with 2 fields it is the same as FieldsWarn
+	FieldsStore
+)
+
+// FieldTolerance how Ads.txt data record with unexpected number of fields is handled
+type FieldTolerance int
+
+// FieldPolicy set how Ads.txt data records that do not have 3 or 4 fields are handled when parsed. Real Ads.txt
+// files often have records without account type (2 fields), or stray commas that add empty fields after the
+// certification authority ID. Zero value FieldPolicy is the default parse behavior: records with 2 fields are
+// rejected, and extra fields are stored as extension fields
+type FieldPolicy struct {
+	TwoFields   FieldTolerance // TwoFields handling of records with only 2 fields (no account type): accepted records have empty AccountType
+	ExtraFields FieldTolerance // ExtraFields handling of records with more than 4 fields
+}
+
+// ParseBodyWithPolicy parse Ads.txt file (see ParseBody), and handle data records with unexpected number of fields
+// according to the field policy
+func ParseBodyWithPolicy(b []byte, policy FieldPolicy) (*Records, error) {
+	b, _ = toUTF8(b, "")
+	return parseReader(bytes.NewReader(b), bytes.Count(b, []byte("\n"))+1, policy)
+}
+
+// ParseReaderWithPolicy parse Ads.txt file read from r (see ParseReader), and handle data records with unexpected
+// number of fields according to the field policy
+func ParseReaderWithPolicy(r io.Reader, policy FieldPolicy) (*Records, error) {
+	return parseReader(r, 0, policy)
+}
+
+// accepts return true if records with unexpected number of fields are accepted
+func (t FieldTolerance) accepts() bool {
+	return t == FieldsWarn || t == FieldsStore
+}
+
+// isDataRecord return true if Ads.txt line is parsed as data record: line with at least 3 comma separated fields,
+// or line with 2 fields (and no variable declaration) when the policy does not use the default handling of records
+// with 2 fields, so they are reported as malformed data records
+func (p FieldPolicy) isDataRecord(line string) bool {
+	commas := strings.Count(line, ",")
+	if commas >= 2 && strings.Count(line, "=") <= 5 {
+		return true
+	}
+	return commas == 1 && p.TwoFields != FieldsDefault && !strings.Contains(line, "=")
+}
@@ -0,0 +1,84 @@
+package adstxt
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// TestFieldPolicy test handling of data records with 2 fields or more than 4 fields according to field policy
+func TestFieldPolicy(t *testing.T) {
+	const body = "greenadexchange.com,XF7342\n" +
+		"greenadexchange.com,XF7343,DIRECT,d75815a79,,\n"
+
+	tests := []struct {
+		policy     FieldPolicy
+		records    int
+		twoFields  bool     // record with 2 fields is accepted
+		extensions []string // extension fields of record with more than 4 fields (nil if rejected)
+	}{
+		{FieldPolicy{}, 1, false, []string{"", ""}},
+		{FieldPolicy{TwoFields: FieldsWarn, ExtraFields: FieldsWarn}, 2, true, []string{}},
+		{FieldPolicy{TwoFields: FieldsStore, ExtraFields: FieldsStore}, 2, true, []string{"", ""}},
+		{FieldPolicy{TwoFields: FieldsReject, ExtraFields: FieldsReject}, 0, false, nil},
+	}
+
+	for _, test := range tests {
+		records, err := ParseBodyWithPolicy([]byte(body), test.policy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records.DataRecords) != test.records {
+			t.Errorf("Expected policy %+v to parse [%d] records and not [%d]", test.policy, test.records, len(records.DataRecords))
+			continue
+		}
+		if len(records.Warnings) != 2 {
+			t.Errorf("Expected policy %+v to report both lines and not %v", test.policy, records.Warnings)
+		}
+
+		var two, extra *DataRecord
+		for _, dr := range records.DataRecords {
+			if dr.PublisherAccountID == "XF7342" {
+				two = dr
+			} else {
+				extra = dr
+			}
+		}
+		if (two != nil) != test.twoFields || (two != nil && len(two.AccountType) != 0) {
+			t.Errorf("Expected policy %+v to accept record with 2 fields [%t]", test.policy, test.twoFields)
+		}
+		if (extra != nil) != (test.extensions != nil) || (extra != nil && len(extra.Extensions) != len(test.extensions)) {
+			t.Errorf("Expected policy %+v record with more than 4 fields extensions %v and not [%+v]", test.policy, test.extensions, extra)
+		}
+	}
+
+	// records with 2 fields are reported as malformed data records when rejected by policy
+	records, _ := ParseReaderWithPolicy(strings.NewReader("greenadexchange.com,XF7342"), FieldPolicy{TwoFields: FieldsReject})
+	if len(records.Warnings) != 1 || !strings.Contains(records.Warnings[0].Message, "Data record must be declared") {
+		t.Errorf("Expected record with 2 fields to be rejected as malformed data record %v", records.Warnings)
+	}
+}
+
+// TestWithFieldPolicy test crawler parse Ads.txt file with field policy
+func TestWithFieldPolicy(t *testing.T) {
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       io.NopCloser(strings.NewReader("greenadexchange.com,XF7342\ngoogle.com,pub-1,RESELLER")),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	req, _ := NewRequest("example.com")
+	res, err := NewCrawler(WithHTTPClient(client), WithFieldPolicy(FieldPolicy{TwoFields: FieldsWarn})).Get(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.DataRecords) != 2 {
+		t.Errorf("Expected crawler to accept record with 2 fields by field policy and not %v", res.Warnings)
+	}
+}
@@ -0,0 +1,104 @@
+package adstxt
+
+import (
+	"io"
+	"strings"
+)
+
+// File builder of Ads.txt file content: add data records, variables and comments, and write them as Ads.txt file
+// that complies with IAB Ads.txt specification. File methods return the File, so calls can be chained:
+//
+//	err := adstxt.NewFile().
+//		AddDirect("google.com", "pub-1234567890", "f08c47fec0942fa0").
+//		AddVariable("contact", "adops@example.com").
+//		Write(w)
+type File struct {
+	records *Records
+	errs    ValidationErrors
+}
+
+// NewFile create new empty Ads.txt File builder
+func NewFile() *File {
+	return &File{
+		records: &Records{
+			DataRecords: []*DataRecord{},
+			Variables:   []*Variable{},
+			Warnings:    []*Warning{},
+			Body:        []string{},
+		},
+	}
+}
+
+// AddDirect add DIRECT data record of the publisher account with the advertising system. Certification authority ID
+// is optional (empty string)
+func (f *File) AddDirect(adSystem, accountID, certAuthorityID string) *File {
+	return f.AddRecord(&DataRecord{AdverterDomain: adSystem, PublisherAccountID: accountID, AccountType: accountTypeDirect, CertAuthorityID: certAuthorityID})
+}
+
+// AddReseller add RESELLER data record of the publisher account with the advertising system. Certification authority
+// ID is optional (empty string)
+func (f *File) AddReseller(adSystem, accountID, certAuthorityID string) *File {
+	return f.AddRecord(&DataRecord{AdverterDomain: adSystem, PublisherAccountID: accountID, AccountType: accountTypeReseller, CertAuthorityID: certAuthorityID})
+}
+
+// AddRecord add data record, for example with extension fields or comment
+func (f *File) AddRecord(r *DataRecord) *File {
+	r.Comments = append(f.records.comments, r.Comments...)
+	f.records.comments = nil
+	f.records.DataRecords = append(f.records.DataRecords, r)
+	return f
+}
+
+// AddVariable add variable (e.g. contact, subdomain, ownerdomain or managerdomain). Variable name is case insensitive
+func (f *File) AddVariable(name, value string) *File {
+	v := &Variable{Type: strings.ToLower(strings.TrimSpace(name)), Value: strings.TrimSpace(value)}
+	v.Comments = f.records.comments
+	f.records.comments = nil
+
+	// keep Ads.txt 1.1 variables multiplicity (e.g. single OWNERDOMAIN)
+	if w := f.records.addVariable(v); w != nil {
+		f.errs = append(f.errs, &ValidationError{Record: v.canonical(), Field: "Type", Value: v.Type, Err: ErrDuplicateVariable})
+	}
+	return f
+}
+
+// AddComment add full-line comment, that is written before the next data record or variable (or at the end of the
+// file if no record follows it)
+func (f *File) AddComment(comment string) *File {
+	f.records.comments = append(f.records.comments, comment)
+	return f
+}
+
+// Records return the records of the File
+func (f *File) Records() *Records {
+	return f.records
+}
+
+// Validate check all File records against IAB Ads.txt specification (see Records.Validate). Return nil if all
+// records are valid, or ValidationErrors with all invalid records fields
+func (f *File) Validate() error {
+	errs := append(ValidationErrors{}, f.errs...)
+	if err := f.records.Validate(); err != nil {
+		errs = append(errs, err.(ValidationErrors)...)
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Write validate File records and write them to w as Ads.txt file. Nothing is written if any record is invalid, and
+// ValidationErrors with all invalid records fields is returned
+func (f *File) Write(w io.Writer) error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+
+	// comments that are not followed by any record are written at the end of the file
+	r := *f.records
+	r.TrailingComments = append(append([]string{}, r.TrailingComments...), r.comments...)
+
+	_, err := r.WriteTo(w)
+	return err
+}
@@ -0,0 +1,58 @@
+package adstxt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestFile test building Ads.txt file content
+func TestFile(t *testing.T) {
+	var b strings.Builder
+	err := NewFile().
+		AddComment("Ads.txt file of example.com").
+		AddDirect("google.com", "pub-1234567890", "f08c47fec0942fa0").
+		AddReseller("openx.com", "540", "").
+		AddVariable("CONTACT", "adops@example.com").
+		AddVariable("ownerdomain", "example.com").
+		AddComment("end of file").
+		Write(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `# Ads.txt file of example.com
+google.com, pub-1234567890, DIRECT, f08c47fec0942fa0
+openx.com, 540, RESELLER
+CONTACT=adops@example.com
+OWNERDOMAIN=example.com
+# end of file
+`
+	if b.String() != expected {
+		t.Errorf("Expected Ads.txt file [%s] and not [%s]", expected, b.String())
+	}
+
+	// written Ads.txt file is valid
+	records, _ := ParseBody([]byte(b.String()))
+	if len(records.Warnings) != 0 || len(records.DataRecords) != 2 || records.OwnerDomain != "example.com" {
+		t.Errorf("Expected written Ads.txt file to be parsed without warnings [%v]", records.Warnings)
+	}
+}
+
+// TestFileValidation test Ads.txt file builder reject invalid records
+func TestFileValidation(t *testing.T) {
+	var b strings.Builder
+	err := NewFile().
+		AddDirect("google.com", "pub-1234567890", "not-a-tag-id").
+		AddVariable("ownerdomain", "example.com").
+		AddVariable("ownerdomain", "other.com").
+		AddVariable("unknown", "value").
+		Write(&b)
+
+	if !errors.Is(err, ErrInvalidCertAuthorityID) || !errors.Is(err, ErrDuplicateVariable) || !errors.Is(err, ErrInvalidVariableType) {
+		t.Errorf("Expected validation errors for all invalid records and not [%v]", err)
+	}
+	if b.Len() != 0 {
+		t.Errorf("Expected nothing to be written for invalid Ads.txt file")
+	}
+}
@@ -0,0 +1,225 @@
+// Package gcs Google Cloud Storage writer of Ads.txt data records rows (see adstxt.RecordRow), as newline-delimited
+// JSON files in partitioned layout that BigQuery external tables read as is. Files are uploaded with the Cloud Storage
+// JSON API, so no Cloud client library is required: use any authenticated HTTP client, for example
+// golang.org/x/oauth2/google DefaultClient
+package gcs
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tzafrirben/go-adstxt-crawler/adstxt"
+)
+
+// DefaultEndpoint Cloud Storage JSON API endpoint
+const DefaultEndpoint = "https://storage.googleapis.com"
+
+// defaultMaxRows default maximum number of rows in single uploaded file
+const defaultMaxRows = 100000
+
+// GCS errors
+const (
+	errUpload = "failed to upload [%s] to Cloud Storage bucket [%s]: [%s] %s"
+)
+
+// Schema BigQuery table schema (JSON) of uploaded rows, for example to create external table with Hive partitioning
+// on the dt partition key:
+//
+//	bq mk --external_table_definition=schema.json@NEWLINE_DELIMITED_JSON=gs://bucket/prefix/* \
+//		--hive_partitioning_mode=AUTO --hive_partitioning_source_uri_prefix=gs://bucket/prefix dataset.adstxt
+const Schema = `[
+  {"name": "domain", "type": "STRING", "mode": "REQUIRED"},
+  {"name": "url", "type": "STRING", "mode": "REQUIRED"},
+  {"name": "line", "type": "INTEGER", "mode": "REQUIRED"},
+  {"name": "adSystem", "type": "STRING", "mode": "REQUIRED"},
+  {"name": "accountId", "type": "STRING", "mode": "REQUIRED"},
+  {"name": "relationship", "type": "STRING", "mode": "REQUIRED"},
+  {"name": "certAuthorityId", "type": "STRING", "mode": "NULLABLE"},
+  {"name": "crawledAt", "type": "TIMESTAMP", "mode": "REQUIRED"}
+]`
+
+// Writer write Ads.txt data records rows to Cloud Storage bucket as newline-delimited JSON files, partitioned by crawl
+// date: <prefix>/dt=<YYYY-MM-DD>/<run>-<sequence>.json. Rows are buffered by partition, and each partition is
+// uploaded once it has the maximum number of rows, or when Flush or Close is called. Writer implements
+// adstxt.RecordRowWriter, so batch crawl results are exported while crawling:
+//
+//	w := gcs.NewWriter(client, "my-bucket", "adstxt")
+//	e := adstxt.NewRecordExporter(w)
+//	adstxt.NewCrawler().GetMultipleWithContext(ctx, requests, e)
+//	if err := w.Close(); err != nil || e.Err() != nil {
+//		log.Fatal(err, e.Err())
+//	}
+//
+// Uploads are made from the Write call, so slow uploads throttle the crawl (see adstxt.Handler). Writer is safe to
+// use from multiple goroutines
+type Writer struct {
+	client   *http.Client
+	bucket   string
+	prefix   string
+	endpoint string
+	maxRows  int
+	run      string // run ID prefix of the uploaded files names, unique to the writer
+
+	parts   map[string]*partition // buffered rows by partition date
+	seq     int                   // sequence number of the next uploaded file
+	objects []string              // names of the uploaded files
+	mu      sync.Mutex
+}
+
+// partition buffered rows of single partition
+type partition struct {
+	buf  bytes.Buffer
+	rows int
+}
+
+// Option is a function that configures a Writer
+type Option func(*Writer)
+
+// WithEndpoint set Cloud Storage JSON API endpoint, for example of Cloud Storage emulator (default is
+// DefaultEndpoint)
+func WithEndpoint(endpoint string) Option {
+	return func(w *Writer) {
+		w.endpoint = endpoint
+	}
+}
+
+// WithMaxRows set maximum number of rows in single uploaded file (default is 100000)
+func WithMaxRows(n int) Option {
+	return func(w *Writer) {
+		if n > 0 {
+			w.maxRows = n
+		}
+	}
+}
+
+// NewWriter create new Writer that uploads files to bucket under prefix (files are uploaded to the bucket root if
+// prefix is empty) with client. client must authenticate its requests to Cloud Storage
+func NewWriter(client *http.Client, bucket, prefix string, opts ...Option) *Writer {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	w := &Writer{
+		client:   client,
+		bucket:   bucket,
+		prefix:   prefix,
+		endpoint: DefaultEndpoint,
+		maxRows:  defaultMaxRows,
+		run:      runID(time.Now()),
+		parts:    map[string]*partition{},
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// Write is the adstxt.RecordRowWriter interface implementation for Writer: buffer rows by partition, and upload each
+// partition that reached the maximum number of rows. Return the number of rows buffered before the first error
+func (w *Writer) Write(rows []adstxt.RecordRow) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for i, row := range rows {
+		b, err := json.Marshal(row)
+		if err != nil {
+			return i, err
+		}
+
+		date := row.CrawledAt.UTC().Format("2006-01-02")
+		p, ok := w.parts[date]
+		if !ok {
+			p = &partition{}
+			w.parts[date] = p
+		}
+		p.buf.Write(append(b, '\n'))
+		p.rows++
+
+		if p.rows >= w.maxRows {
+			if err := w.upload(context.Background(), date, p); err != nil {
+				return i + 1, err
+			}
+		}
+	}
+	return len(rows), nil
+}
+
+// Flush upload buffered rows of all partitions. Rows of partitions that failed to be uploaded are kept, so Flush may
+// be retried
+func (w *Writer) Flush(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	dates := []string{}
+	for date := range w.parts {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	for _, date := range dates {
+		if err := w.upload(ctx, date, w.parts[date]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close upload buffered rows of all partitions (see Flush)
+func (w *Writer) Close() error {
+	return w.Flush(context.Background())
+}
+
+// Objects return the names of the files uploaded to the bucket, in order of upload
+func (w *Writer) Objects() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return append([]string{}, w.objects...)
+}
+
+// upload partition buffered rows as new file, and remove the partition once uploaded. Files are never overwritten:
+// upload fails if file with the same name already exists
+func (w *Writer) upload(ctx context.Context, date string, p *partition) error {
+	name := path.Join(w.prefix, "dt="+date, fmt.Sprintf("%s-%06d.json", w.run, w.seq))
+	u := fmt.Sprintf("%s/upload/storage/v1/b/%s/o?uploadType=media&ifGenerationMatch=0&name=%s",
+		w.endpoint, url.PathEscape(w.bucket), url.QueryEscape(name))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(p.buf.Bytes()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+		return fmt.Errorf(errUpload, name, w.bucket, res.Status, bytes.TrimSpace(msg))
+	}
+
+	delete(w.parts, date)
+	w.seq++
+	w.objects = append(w.objects, name)
+	return nil
+}
+
+// runID return unique ID of writer created at t, so files uploaded by parallel crawls do not collide
+func runID(t time.Time) string {
+	b := make([]byte, 4)
+	rand.Read(b)
+	return t.UTC().Format("20060102T150405Z") + "-" + hex.EncodeToString(b)
+}
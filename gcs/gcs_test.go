@@ -0,0 +1,116 @@
+package gcs
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tzafrirben/go-adstxt-crawler/adstxt"
+)
+
+// roundTripperFunc mock HTTP transport
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// bucket mock Cloud Storage bucket: store uploaded files by name, and fail uploads while fail is set
+type bucket struct {
+	files map[string]string
+	fail  bool
+	mu    sync.Mutex
+}
+
+func (b *bucket) client(t *testing.T) *http.Client {
+	return &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+
+			if req.Method != http.MethodPost || req.URL.Path != "/upload/storage/v1/b/crawls/o" ||
+				req.URL.Query().Get("uploadType") != "media" || req.URL.Query().Get("ifGenerationMatch") != "0" {
+				t.Errorf("Expected Cloud Storage media upload request and not [%s %s]", req.Method, req.URL)
+			}
+			if b.fail {
+				return &http.Response{StatusCode: http.StatusServiceUnavailable, Status: "503 Service Unavailable",
+					Body: io.NopCloser(strings.NewReader("backend error")), Request: req}, nil
+			}
+
+			body, _ := io.ReadAll(req.Body)
+			b.files[req.URL.Query().Get("name")] = string(body)
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}")), Request: req}, nil
+		}),
+	}
+}
+
+// TestWriter test rows are uploaded to date partitions as newline-delimited JSON files
+func TestWriter(t *testing.T) {
+	b := &bucket{files: map[string]string{}}
+	w := NewWriter(b.client(t), "crawls", "adstxt", WithMaxRows(2))
+
+	day1 := time.Date(2024, time.March, 1, 23, 0, 0, 0, time.UTC)
+	day2 := day1.Add(2 * time.Hour)
+	rows := []adstxt.RecordRow{
+		{Domain: "example.com", URL: "https://example.com/ads.txt", Line: 1, AdSystem: "google.com", AccountID: "pub-1", Relationship: "DIRECT", CrawledAt: day1},
+		{Domain: "example.com", URL: "https://example.com/ads.txt", Line: 2, AdSystem: "openx.com", AccountID: "2", Relationship: "RESELLER", CrawledAt: day1},
+		{Domain: "test.com", URL: "https://test.com/ads.txt", Line: 1, AdSystem: "google.com", AccountID: "pub-3", Relationship: "DIRECT", CrawledAt: day2},
+	}
+	if n, err := w.Write(rows); n != 3 || err != nil {
+		t.Fatalf("Expected [3] rows to be written and not [%d] [%v]", n, err)
+	}
+
+	// full partition is uploaded by Write, other partitions are uploaded once flushed
+	objects := w.Objects()
+	if len(objects) != 1 || !strings.HasPrefix(objects[0], "adstxt/dt=2024-03-01/") || !strings.HasSuffix(objects[0], "-000000.json") {
+		t.Fatalf("Expected full partition to be uploaded and not %v", objects)
+	}
+	lines := strings.Split(strings.TrimSpace(b.files[objects[0]]), "\n")
+	var row adstxt.RecordRow
+	if len(lines) != 2 || json.Unmarshal([]byte(lines[1]), &row) != nil || row.AdSystem != "openx.com" || !row.CrawledAt.Equal(day1) {
+		t.Errorf("Expected newline-delimited JSON rows and not %v", lines)
+	}
+
+	b.fail = true
+	if err := w.Close(); err == nil || !strings.Contains(err.Error(), "backend error") {
+		t.Errorf("Expected upload error and not [%v]", err)
+	}
+	b.fail = false
+	if err := w.Close(); err != nil {
+		t.Fatalf("Expected buffered rows to be uploaded once upload is retried [%s]", err)
+	}
+	objects = w.Objects()
+	if len(objects) != 2 || !strings.HasPrefix(objects[1], "adstxt/dt=2024-03-02/") || strings.Count(b.files[objects[1]], "\n") != 1 {
+		t.Errorf("Expected partition of the next day to be uploaded and not %v", objects)
+	}
+	if err := w.Flush(context.Background()); err != nil || len(w.Objects()) != 2 {
+		t.Errorf("Expected no upload without buffered rows [%v]", err)
+	}
+}
+
+// TestSchema test BigQuery schema columns match the rows JSON fields
+func TestSchema(t *testing.T) {
+	fields := []struct {
+		Name string `json:"name"`
+	}{}
+	if err := json.Unmarshal([]byte(Schema), &fields); err != nil {
+		t.Fatalf("Failed to parse BigQuery schema: %s", err)
+	}
+
+	b, _ := json.Marshal(adstxt.RecordRow{})
+	row := map[string]interface{}{}
+	json.Unmarshal(b, &row)
+	if len(fields) != len(row) {
+		t.Errorf("Expected [%d] schema columns and not [%d]", len(row), len(fields))
+	}
+	for _, f := range fields {
+		if _, ok := row[f.Name]; !ok {
+			t.Errorf("Expected schema column [%s] to be row JSON field", f.Name)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+// Package graph build graph of publisher to advertising system relationships from many Ads.txt crawl results, for
+// studying the programmatic supply chain in aggregate, and export it to DOT (Graphviz) or GraphML
+package graph
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/tzafrirben/go-adstxt-crawler/adstxt"
+)
+
+// graph node kinds
+const (
+	// Publisher node of Ads.txt file owner domain
+	Publisher Kind = "publisher"
+	// AdSystem node of advertising system domain
+	AdSystem Kind = "adsystem"
+)
+
+// Kind of graph node
+type Kind string
+
+// Node single graph node: publisher or advertising system domain. Domain that is both publisher and advertising
+// system has node of each kind
+type Node struct {
+	ID     string `json:"id"`     // ID unique node identifier: <kind>:<domain>
+	Domain string `json:"domain"` // Domain lower case domain name
+	Kind   Kind   `json:"kind"`   // Kind of the node
+}
+
+// Edge publisher to advertising system relationship, weighted by the number of distinct seller accounts the
+// publisher declares for the advertising system
+type Edge struct {
+	From     string `json:"from"`     // From publisher node ID
+	To       string `json:"to"`       // To advertising system node ID
+	Weight   int    `json:"weight"`   // Weight number of distinct seller accounts (data records)
+	Direct   int    `json:"direct"`   // Direct number of DIRECT seller accounts
+	Reseller int    `json:"reseller"` // Reseller number of RESELLER seller accounts
+}
+
+// Graph of publisher to advertising system relationships. Graph implements the adstxt.Handler interface, so batch
+// crawl results can be added as they are completed, and it is safe to use from multiple goroutines
+type Graph struct {
+	publishers map[string]map[string]*Edge // edges of each publisher domain, by advertising system domain
+	mu         sync.Mutex
+}
+
+// New create new empty Graph
+func New() *Graph {
+	return &Graph{publishers: map[string]map[string]*Edge{}}
+}
+
+// Handle is the adstxt.Handler interface implementation for Graph: add successful Ads.txt response records of the
+// request domain (failed requests and NotModified responses are ignored)
+func (g *Graph) Handle(req *adstxt.Request, res *adstxt.Response, err error) {
+	if err != nil || res == nil || res.NotModified || res.Records == nil {
+		return
+	}
+	g.Add(req.Domain, res.Records)
+}
+
+// Add data records of publisher Ads.txt file to the graph. Records replace any records previously added for the
+// publisher, so re-crawled Ads.txt file is not counted twice
+func (g *Graph) Add(publisher string, records *adstxt.Records) {
+	publisher = normalize(publisher)
+	from := nodeID(Publisher, publisher)
+
+	accounts := map[string]bool{}
+	edges := map[string]*Edge{}
+	for _, r := range records.DataRecords {
+		adSystem := normalize(r.AdverterDomain)
+		accountType := adstxt.ParseRelationship(string(r.AccountType))
+
+		// each seller account is counted once per relationship
+		account := adSystem + "," + strings.TrimSpace(r.PublisherAccountID) + "," + accountType.String()
+		if accounts[account] {
+			continue
+		}
+		accounts[account] = true
+
+		e, ok := edges[adSystem]
+		if !ok {
+			e = &Edge{From: from, To: nodeID(AdSystem, adSystem)}
+			edges[adSystem] = e
+		}
+		e.Weight++
+		switch accountType {
+		case adstxt.RelationshipDirect:
+			e.Direct++
+		case adstxt.RelationshipReseller:
+			e.Reseller++
+		}
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.publishers[publisher] = edges
+}
+
+// Nodes return all graph nodes, sorted by ID
+func (g *Graph) Nodes() []*Node {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	nodes := map[string]*Node{}
+	for publisher, edges := range g.publishers {
+		nodes[nodeID(Publisher, publisher)] = &Node{ID: nodeID(Publisher, publisher), Domain: publisher, Kind: Publisher}
+		for adSystem := range edges {
+			nodes[nodeID(AdSystem, adSystem)] = &Node{ID: nodeID(AdSystem, adSystem), Domain: adSystem, Kind: AdSystem}
+		}
+	}
+
+	sorted := make([]*Node, 0, len(nodes))
+	for _, n := range nodes {
+		sorted = append(sorted, n)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	return sorted
+}
+
+// Edges return all graph edges, sorted by publisher and advertising system node IDs
+func (g *Graph) Edges() []*Edge {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	sorted := []*Edge{}
+	for _, edges := range g.publishers {
+		for _, e := range edges {
+			c := *e
+			sorted = append(sorted, &c)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].From != sorted[j].From {
+			return sorted[i].From < sorted[j].From
+		}
+		return sorted[i].To < sorted[j].To
+	})
+	return sorted
+}
+
+// WriteDOT write the graph in Graphviz DOT format: publishers are box nodes, advertising systems are ellipse nodes
+// and edges are labeled with their weight
+func (g *Graph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph adstxt {\n")
+	for _, n := range g.Nodes() {
+		shape := "ellipse"
+		if n.Kind == Publisher {
+			shape = "box"
+		}
+		fmt.Fprintf(&b, "  %q [label=%q, shape=%s];\n", n.ID, n.Domain, shape)
+	}
+	for _, e := range g.Edges() {
+		fmt.Fprintf(&b, "  %q -> %q [weight=%d, label=\"%d\"];\n", e.From, e.To, e.Weight, e.Weight)
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// graphML GraphML document
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   struct {
+		ID          string        `xml:"id,attr"`
+		EdgeDefault string        `xml:"edgedefault,attr"`
+		Nodes       []graphMLNode `xml:"node"`
+		Edges       []graphMLEdge `xml:"edge"`
+	} `xml:"graph"`
+}
+
+// graphMLKey GraphML attribute declaration
+type graphMLKey struct {
+	ID   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+// graphMLData GraphML attribute value
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// graphMLNode GraphML node
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+// graphMLEdge GraphML edge
+type graphMLEdge struct {
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+// WriteGraphML write the graph in GraphML format, with node domain and kind attributes and edge weight, direct and
+// reseller attributes
+func (g *Graph) WriteGraphML(w io.Writer) error {
+	doc := &graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "domain", For: "node", Name: "domain", Type: "string"},
+			{ID: "kind", For: "node", Name: "kind", Type: "string"},
+			{ID: "weight", For: "edge", Name: "weight", Type: "int"},
+			{ID: "direct", For: "edge", Name: "direct", Type: "int"},
+			{ID: "reseller", For: "edge", Name: "reseller", Type: "int"},
+		},
+	}
+	doc.Graph.ID = "adstxt"
+	doc.Graph.EdgeDefault = "directed"
+
+	for _, n := range g.Nodes() {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{ID: n.ID, Data: []graphMLData{
+			{Key: "domain", Value: n.Domain},
+			{Key: "kind", Value: string(n.Kind)},
+		}})
+	}
+	for _, e := range g.Edges() {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{Source: e.From, Target: e.To, Data: []graphMLData{
+			{Key: "weight", Value: fmt.Sprint(e.Weight)},
+			{Key: "direct", Value: fmt.Sprint(e.Direct)},
+			{Key: "reseller", Value: fmt.Sprint(e.Reseller)},
+		}})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// nodeID return node ID of domain
+func nodeID(kind Kind, domain string) string {
+	return string(kind) + ":" + domain
+}
+
+// normalize return trimmed lower case domain
+func normalize(domain string) string {
+	return strings.ToLower(strings.TrimSpace(domain))
+}
@@ -0,0 +1,95 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/tzafrirben/go-adstxt-crawler/adstxt"
+)
+
+// newTestGraph create graph of two publishers
+func newTestGraph(t *testing.T) *Graph {
+	g := New()
+	for domain, body := range map[string]string{
+		"example.com": "google.com, pub-1, DIRECT\nGoogle.com,pub-1,direct\ngoogle.com, pub-2, RESELLER\nopenx.com, 540, RESELLER",
+		"example.org": "google.com, pub-3, DIRECT",
+	} {
+		records, err := adstxt.ParseBody([]byte(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req, _ := adstxt.NewRequest(domain)
+		g.Handle(req, &adstxt.Response{Request: req, Records: records}, nil)
+	}
+	return g
+}
+
+// TestGraph test building publisher to advertising system graph
+func TestGraph(t *testing.T) {
+	g := newTestGraph(t)
+
+	nodes := g.Nodes()
+	if len(nodes) != 4 {
+		t.Fatalf("Expected [4] nodes and not [%d]", len(nodes))
+	}
+	if nodes[0].ID != "adsystem:google.com" || nodes[0].Kind != AdSystem || nodes[2].ID != "publisher:example.com" {
+		t.Errorf("Expected nodes sorted by ID and not [%s] [%s]", nodes[0].ID, nodes[2].ID)
+	}
+
+	edges := g.Edges()
+	if len(edges) != 3 {
+		t.Fatalf("Expected [3] edges and not [%d]", len(edges))
+	}
+	e := edges[0]
+	if e.From != "publisher:example.com" || e.To != "adsystem:google.com" || e.Weight != 2 || e.Direct != 1 || e.Reseller != 1 {
+		t.Errorf("Expected duplicate seller account to be counted once and not %+v", e)
+	}
+
+	// re-crawled Ads.txt file replaces previous records
+	records, _ := adstxt.ParseBody([]byte("openx.com, 541, DIRECT"))
+	g.Add("Example.org", records)
+	if edges := g.Edges(); len(edges) != 3 || edges[2].To != "adsystem:openx.com" {
+		t.Errorf("Expected publisher records to be replaced")
+	}
+}
+
+// TestWriteDOT test exporting graph to DOT format
+func TestWriteDOT(t *testing.T) {
+	var b bytes.Buffer
+	if err := newTestGraph(t).WriteDOT(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	dot := b.String()
+	for _, s := range []string{
+		"digraph adstxt {",
+		`"publisher:example.com" [label="example.com", shape=box];`,
+		`"adsystem:openx.com" [label="openx.com", shape=ellipse];`,
+		`"publisher:example.com" -> "adsystem:google.com" [weight=2, label="2"];`,
+	} {
+		if !strings.Contains(dot, s) {
+			t.Errorf("Expected DOT output to include [%s]:\n%s", s, dot)
+		}
+	}
+}
+
+// TestWriteGraphML test exporting graph to GraphML format
+func TestWriteGraphML(t *testing.T) {
+	var b bytes.Buffer
+	if err := newTestGraph(t).WriteGraphML(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	doc := &graphML{}
+	if err := xml.Unmarshal(b.Bytes(), doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Graph.Nodes) != 4 || len(doc.Graph.Edges) != 3 || doc.Graph.EdgeDefault != "directed" {
+		t.Errorf("Expected GraphML with [4] nodes and [3] edges")
+	}
+	if e := doc.Graph.Edges[0]; e.Source != "publisher:example.com" || e.Data[0].Key != "weight" || e.Data[0].Value != "2" {
+		t.Errorf("Expected GraphML edge weight and not %+v", e)
+	}
+}
@@ -0,0 +1,96 @@
+package adstxt
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"net/url"
+	"syscall"
+)
+
+// addressGuard block crawler connections to private, loopback and link-local network addresses (see
+// WithBlockPrivateAddresses)
+type addressGuard struct {
+	allowed []netip.Prefix // allowed network prefixes, that are not blocked even if private
+}
+
+// check return ErrBlockedAddress if connection to address ("ip:port") is not allowed
+func (g *addressGuard) check(address string) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		host = address
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		// address is not IP address: it can not be checked, so it is not allowed
+		return &ErrBlockedAddress{Address: address}
+	}
+	ip = ip.Unmap()
+
+	for _, p := range g.allowed {
+		if p.Contains(ip) {
+			return nil
+		}
+	}
+	if isPrivateAddr(ip) {
+		return &ErrBlockedAddress{Address: address, IP: ip}
+	}
+	return nil
+}
+
+// checkHost return ErrBlockedAddress if any address of the remote host of URL is not allowed. It checks requests
+// sent through proxy, whose connection is made to the proxy address: the host name is resolved with resolver (system
+// resolver if nil), and the request is failed if the host name can not be resolved
+func (g *addressGuard) checkHost(ctx context.Context, resolver *net.Resolver, u *url.URL) error {
+	port := u.Port()
+	if len(port) == 0 {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+
+	host := u.Hostname()
+	addrs := []string{host}
+	if _, err := netip.ParseAddr(host); err != nil {
+		if resolver == nil {
+			resolver = net.DefaultResolver
+		}
+		if addrs, err = resolver.LookupHost(ctx, host); err != nil {
+			return err
+		}
+	}
+	for _, addr := range addrs {
+		if err := g.check(net.JoinHostPort(addr, port)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// control is net.Dialer Control function that blocks connection before it is made, once the remote host name was
+// resolved (so host names that resolve to private address are blocked as well)
+func (g *addressGuard) control(network, address string, _ syscall.RawConn) error {
+	return g.check(address)
+}
+
+// wrap custom dial function, so connections to not allowed addresses are closed before any data is sent
+func (g *addressGuard) wrap(dial DialFunc) DialFunc {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		conn, err := dial(ctx, network, address)
+		if err != nil {
+			return nil, err
+		}
+		if err := g.check(conn.RemoteAddr().String()); err != nil {
+			conn.Close()
+			return nil, &net.OpError{Op: "dial", Net: network, Addr: conn.RemoteAddr(), Err: err}
+		}
+		return conn, nil
+	}
+}
+
+// isPrivateAddr return true if ip is private, loopback, link-local or unspecified address
+func isPrivateAddr(ip netip.Addr) bool {
+	return ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsUnspecified()
+}
@@ -0,0 +1,95 @@
+package adstxt
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"testing"
+)
+
+// TestWithBlockPrivateAddresses test crawler refuse to connect to private network addresses
+func TestWithBlockPrivateAddresses(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "greenadexchange.com,XF7342,DIRECT")
+	}))
+	defer ts.Close()
+
+	// test server listens on loopback address
+	_, err := NewCrawler(WithBlockPrivateAddresses()).Get(&Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"})
+	var blockedErr *ErrBlockedAddress
+	if !errors.As(err, &blockedErr) || blockedErr.IP != netip.MustParseAddr("127.0.0.1") {
+		t.Fatalf("Expected ErrBlockedAddress error and not [%v]", err)
+	}
+
+	// custom dial function connections are checked as well
+	dialer := &net.Dialer{}
+	_, err = NewCrawler(WithDialContext(dialer.DialContext), WithBlockPrivateAddresses()).Get(&Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"})
+	if !errors.As(err, &blockedErr) {
+		t.Errorf("Expected ErrBlockedAddress error of custom dial function and not [%v]", err)
+	}
+
+	// allowed network prefix
+	res, err := NewCrawler(WithBlockPrivateAddresses(netip.MustParsePrefix("127.0.0.0/8"))).Get(&Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"})
+	if err != nil || len(res.DataRecords) != 1 {
+		t.Errorf("Expected request to allowed address to succeed [%v]", err)
+	}
+
+	// remote host of request sent through allowed proxy is checked as well
+	proxy, _ := url.Parse(ts.URL)
+	c := NewCrawler(WithProxy(proxy), WithBlockPrivateAddresses(netip.MustParsePrefix("127.0.0.1/32")))
+	_, err = c.Get(&Request{URL: "http://10.0.0.1/ads.txt", Domain: "10.0.0.1"})
+	if !errors.As(err, &blockedErr) || blockedErr.Address != "10.0.0.1:80" {
+		t.Errorf("Expected ErrBlockedAddress error of proxied request to private address and not [%v]", err)
+	}
+	u, _ := url.Parse("http://localhost/ads.txt")
+	if err = (&addressGuard{}).checkHost(context.Background(), nil, u); !errors.As(err, &blockedErr) {
+		t.Errorf("Expected ErrBlockedAddress error of proxied request to host name of private address and not [%v]", err)
+	}
+	if res, err = c.Get(&Request{URL: "http://8.8.8.8/ads.txt", Domain: "8.8.8.8"}); err != nil || len(res.DataRecords) != 1 {
+		t.Errorf("Expected proxied request to public address to succeed [%v]", err)
+	}
+}
+
+// TestAddressGuard test private network addresses classification
+func TestAddressGuard(t *testing.T) {
+	addresses := map[string]bool{
+		"127.0.0.1:80":          true,
+		"10.1.2.3:80":           true,
+		"172.16.0.1:443":        true,
+		"192.168.1.1:80":        true,
+		"169.254.169.254:80":    true,
+		"0.0.0.0:80":            true,
+		"[::1]:80":              true,
+		"[fe80::1]:80":          true,
+		"[fd00::1]:80":          true,
+		"[::ffff:10.0.0.1]:80":  true,
+		"example.com:80":        true,
+		"93.184.216.34:80":      false,
+		"[2606:4700::6810]:443": false,
+	}
+
+	g := &addressGuard{}
+	for address, blocked := range addresses {
+		if err := g.check(address); (err != nil) != blocked {
+			t.Errorf("Expected address [%s] blocked to be [%t] and not [%v]", address, blocked, err)
+		}
+	}
+
+	g = &addressGuard{allowed: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}}
+	if err := g.check("[::ffff:10.0.0.1]:80"); err != nil {
+		t.Errorf("Expected allowed address not to be blocked [%s]", err)
+	}
+
+	// dial errors of custom dial function are returned as is
+	dial := g.wrap(func(ctx context.Context, network, address string) (net.Conn, error) {
+		return nil, errors.New("dial failed")
+	})
+	if _, err := dial(context.Background(), "tcp", "10.0.0.1:80"); err == nil || err.Error() != "dial failed" {
+		t.Errorf("Expected dial error to be returned as is and not [%v]", err)
+	}
+}
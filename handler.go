@@ -2,6 +2,12 @@ package adstxt
 
 // The Handler interface is used to process Ads.txt requests. It is similar to the
 // net/http.Handler interface.
+//
+// Batch crawls call Handle from the crawler workers, so Handle may be called concurrently and must be safe to use
+// from multiple goroutines. Handle applies backpressure on the crawl: the request slot of each result is released
+// only once Handle returns, so while Handle is slow (for example slow database writes) the crawler sends no new
+// requests beyond its concurrency, and no results are queued in memory. Use NewAsyncHandler to decouple slow handler
+// from the crawl with bounded queue.
 type Handler interface {
 	Handle(*Request, *Response, error)
 }
@@ -14,3 +20,19 @@ type HandlerFunc func(*Request, *Response, error)
 func (h HandlerFunc) Handle(req *Request, res *Response, err error) {
 	h(req, res, err)
 }
+
+// MultiHandler return Handler that passes each Ads.txt request result to all the specified handlers, in order (for
+// example one handler that saves responses to database and another that collects metrics)
+func MultiHandler(handlers ...Handler) Handler {
+	return multiHandler(handlers)
+}
+
+// multiHandler Handler that fans out results to multiple handlers
+type multiHandler []Handler
+
+// Handle is the Handler interface implementation for multiHandler
+func (m multiHandler) Handle(req *Request, res *Response, err error) {
+	for _, h := range m {
+		h.Handle(req, res, err)
+	}
+}
@@ -0,0 +1,29 @@
+package adstxt
+
+import (
+	"testing"
+)
+
+// TestMultiHandler test passing Ads.txt request result to multiple handlers
+func TestMultiHandler(t *testing.T) {
+	calls := []string{}
+	first := HandlerFunc(func(req *Request, res *Response, err error) {
+		calls = append(calls, "first "+req.Domain)
+	})
+	s := &BatchSummary{}
+
+	h := MultiHandler(first, s, HandlerFunc(func(req *Request, res *Response, err error) {
+		calls = append(calls, "last "+req.Domain)
+	}))
+	h.Handle(&Request{Domain: "example.com"}, &Response{Records: &Records{}}, nil)
+
+	if len(calls) != 2 || calls[0] != "first example.com" || calls[1] != "last example.com" {
+		t.Errorf("Expected result to be passed to all handlers in order and not %v", calls)
+	}
+	if s.Succeeded != 1 {
+		t.Errorf("Expected result to be passed to BatchSummary handler")
+	}
+
+	// no handlers
+	MultiHandler().Handle(&Request{}, nil, nil)
+}
@@ -0,0 +1,125 @@
+package adstxt
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// healthWindow number of recent crawl results the HealthMonitor error rate is based on
+const healthWindow = 100
+
+// HealthStatus crawl health status reported by HealthMonitor
+type HealthStatus struct {
+	Healthy    bool      `json:"healthy"`          // Healthy crawler is crawling within the MaxIdle and MaxErrorRate limits
+	Reason     string    `json:"reason,omitempty"` // Reason the crawler is not healthy, empty if healthy
+	Started    time.Time `json:"started"`          // Started time the HealthMonitor was created
+	LastCrawl  time.Time `json:"lastCrawl"`        // LastCrawl time the last crawl result was handled, zero if no crawl completed yet
+	Crawls     int64     `json:"crawls"`           // Crawls total number of crawl results handled
+	Errors     int64     `json:"errors"`           // Errors total number of failed crawls
+	ErrorRate  float64   `json:"errorRate"`        // ErrorRate rate of failed crawls in the most recent crawl results (0 to 1)
+	QueueDepth int       `json:"queueDepth"`       // QueueDepth number of Ads.txt files waiting to be crawled (see HealthMonitor.QueueDepth)
+	CacheSize  int       `json:"cacheSize"`        // CacheSize number of cached Ads.txt responses (see HealthMonitor.CacheSize)
+}
+
+// HealthMonitor Handler that tracks crawl results, and serves crawl health status as JSON HTTP endpoint, so crawler
+// running in service or scheduler mode can be deployed behind standard health checks. The endpoint responds with
+// HTTP 200 when healthy and HTTP 503 otherwise. Crawl results are passed to the next handler, and HealthMonitor is
+// safe to use from multiple goroutines:
+//
+//	monitor := adstxt.NewHealthMonitor(h)
+//	monitor.MaxIdle = 10 * time.Minute
+//	monitor.QueueDepth = scheduler.QueueDepth
+//	monitor.CacheSize = cache.Len
+//	http.Handle("/healthz", monitor)
+type HealthMonitor struct {
+	MaxIdle      time.Duration // MaxIdle unhealthy if no crawl result was handled for longer than MaxIdle (not checked if zero)
+	MaxErrorRate float64       // MaxErrorRate unhealthy if recent error rate is higher than MaxErrorRate (not checked if zero)
+	QueueDepth   func() int    // QueueDepth return the number of Ads.txt files waiting to be crawled (for example Scheduler.QueueDepth, optional)
+	CacheSize    func() int    // CacheSize return the number of cached Ads.txt responses (for example LRUCache.Len, optional)
+
+	h       Handler
+	started time.Time
+	last    time.Time
+	crawls  int64
+	errors  int64
+	recent  []bool // recent crawl results (true if failed), used as ring buffer
+	mu      sync.Mutex
+}
+
+// NewHealthMonitor create new HealthMonitor that tracks crawl results and passes them to h (h may be nil)
+func NewHealthMonitor(h Handler) *HealthMonitor {
+	return &HealthMonitor{h: h, started: time.Now().UTC(), recent: make([]bool, 0, healthWindow)}
+}
+
+// Handle is the Handler interface implementation for HealthMonitor
+func (m *HealthMonitor) Handle(req *Request, res *Response, err error) {
+	m.mu.Lock()
+	if len(m.recent) < healthWindow {
+		m.recent = append(m.recent, err != nil)
+	} else {
+		m.recent[m.crawls%healthWindow] = err != nil
+	}
+	m.crawls++
+	if err != nil {
+		m.errors++
+	}
+	m.last = time.Now().UTC()
+	m.mu.Unlock()
+
+	if m.h != nil {
+		m.h.Handle(req, res, err)
+	}
+}
+
+// Status return the current crawl health status
+func (m *HealthMonitor) Status() *HealthStatus {
+	m.mu.Lock()
+	s := &HealthStatus{Healthy: true, Started: m.started, LastCrawl: m.last, Crawls: m.crawls, Errors: m.errors}
+	failed := 0
+	for _, f := range m.recent {
+		if f {
+			failed++
+		}
+	}
+	if len(m.recent) > 0 {
+		s.ErrorRate = float64(failed) / float64(len(m.recent))
+	}
+	m.mu.Unlock()
+
+	if m.QueueDepth != nil {
+		s.QueueDepth = m.QueueDepth()
+	}
+	if m.CacheSize != nil {
+		s.CacheSize = m.CacheSize()
+	}
+
+	// idle time is measured from the last crawl, or from start if no crawl completed yet
+	since := s.LastCrawl
+	if since.IsZero() {
+		since = s.Started
+	}
+	switch {
+	case m.MaxIdle > 0 && time.Since(since) > m.MaxIdle:
+		s.Healthy, s.Reason = false, "no crawl completed within "+m.MaxIdle.String()
+	case m.MaxErrorRate > 0 && s.ErrorRate > m.MaxErrorRate:
+		s.Healthy, s.Reason = false, "error rate is higher than maximum error rate"
+	}
+	return s
+}
+
+// ServeHTTP is the http.Handler interface implementation for HealthMonitor: write the health status as JSON, with
+// HTTP 200 status code when healthy or HTTP 503 otherwise
+func (m *HealthMonitor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s := m.Status()
+	status := http.StatusOK
+	if !s.Healthy {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(s)
+}
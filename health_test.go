@@ -0,0 +1,61 @@
+package adstxt
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestHealthMonitor test health status of crawl results, and health HTTP endpoint status code
+func TestHealthMonitor(t *testing.T) {
+	handled := 0
+	m := NewHealthMonitor(HandlerFunc(func(*Request, *Response, error) { handled++ }))
+	m.MaxErrorRate = 0.5
+	m.CacheSize = func() int { return 3 }
+
+	req, _ := NewRequest("example.com")
+	m.Handle(req, &Response{}, nil)
+	if s := m.Status(); !s.Healthy || s.Crawls != 1 || s.ErrorRate != 0 || s.LastCrawl.IsZero() || s.CacheSize != 3 {
+		t.Errorf("Expected healthy status after successful crawl and not [%+v]", s)
+	}
+
+	m.Handle(req, nil, errors.New("failed"))
+	m.Handle(req, nil, errors.New("failed"))
+	s := m.Status()
+	if s.Healthy || s.Errors != 2 || s.ErrorRate < 0.6 || len(s.Reason) == 0 {
+		t.Errorf("Expected unhealthy status of high error rate and not [%+v]", s)
+	}
+	if handled != 3 {
+		t.Errorf("Expected crawl results to be passed to next handler")
+	}
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	status := &HealthStatus{}
+	if err := json.Unmarshal(rec.Body.Bytes(), status); err != nil || rec.Code != http.StatusServiceUnavailable || status.Crawls != 3 {
+		t.Errorf("Expected unhealthy status endpoint to respond with HTTP 503 [%d] [%s]", rec.Code, rec.Body.String())
+	}
+
+	// error rate is based on the most recent crawl results
+	for i := 0; i < healthWindow; i++ {
+		m.Handle(req, &Response{}, nil)
+	}
+	if s := m.Status(); !s.Healthy || s.ErrorRate != 0 || s.Errors != 2 {
+		t.Errorf("Expected healthy status once recent crawls succeeded and not [%+v]", s)
+	}
+
+	idle := NewHealthMonitor(nil)
+	idle.MaxIdle = time.Millisecond
+	time.Sleep(5 * time.Millisecond)
+	if s := idle.Status(); s.Healthy {
+		t.Errorf("Expected unhealthy status when no crawl completed within maximum idle time")
+	}
+	rec = httptest.NewRecorder()
+	NewHealthMonitor(nil).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("Expected healthy status endpoint to respond with HTTP 200 JSON and not [%d]", rec.Code)
+	}
+}
@@ -0,0 +1,69 @@
+package adstxt
+
+import (
+	"context"
+	"net/http"
+)
+
+// Hooks callbacks of Ads.txt request lifecycle stages (see WithHooks), for custom logging, request mutation or short
+// circuit of the request without wrapping the crawler. Nil hooks are skipped. Hooks are called from the goroutine of
+// the request, concurrently for requests of batch crawls. Error returned by hook aborts the request and is returned to
+// the caller as is, so integrators can match their own errors with errors.Is
+type Hooks struct {
+	// OnRequest called once before Ads.txt request is sent: the hook may modify the request (for example set request
+	// headers or user agent), or short circuit it with non nil response (for example served from integrator cache),
+	// which is returned without sending any HTTP request
+	OnRequest func(ctx context.Context, req *Request) (*Response, error)
+	// OnRedirect called before HTTP redirect (or HTML redirect, see WithHTMLRedirects) that passed the crawler
+	// redirect checks is followed
+	OnRedirect func(req *Request, redirect *Redirect) error
+	// OnRetry called before failed attempt of HTTP request is retried (see WithRetry), with the failed attempt number,
+	// and the HTTP status code (zero on network error) and error of the failed attempt
+	OnRetry func(req *Request, attempt int, status int, err error) error
+	// OnResponse called on HTTP response of each request of the redirect chain, before the response is handled and its
+	// body is read
+	OnResponse func(req *Request, res *http.Response) error
+	// OnParseComplete called after Ads.txt file is parsed and its records transformed (see WithRecordTransformers):
+	// the hook may modify the records before the response is returned
+	OnParseComplete func(req *Request, records *Records) error
+}
+
+// request call OnRequest hook
+func (h Hooks) request(ctx context.Context, req *Request) (*Response, error) {
+	if h.OnRequest == nil {
+		return nil, nil
+	}
+	return h.OnRequest(ctx, req)
+}
+
+// redirect call OnRedirect hook
+func (h Hooks) redirect(req *Request, redirect *Redirect) error {
+	if h.OnRedirect == nil {
+		return nil
+	}
+	return h.OnRedirect(req, redirect)
+}
+
+// retry call OnRetry hook
+func (h Hooks) retry(req *Request, attempt int, status int, err error) error {
+	if h.OnRetry == nil {
+		return nil
+	}
+	return h.OnRetry(req, attempt, status, err)
+}
+
+// response call OnResponse hook
+func (h Hooks) response(req *Request, res *http.Response) error {
+	if h.OnResponse == nil {
+		return nil
+	}
+	return h.OnResponse(req, res)
+}
+
+// parseComplete call OnParseComplete hook
+func (h Hooks) parseComplete(req *Request, records *Records) error {
+	if h.OnParseComplete == nil {
+		return nil
+	}
+	return h.OnParseComplete(req, records)
+}
@@ -0,0 +1,95 @@
+package adstxt
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestHooks test calling request lifecycle hooks in order, and aborting request by hook error
+func TestHooks(t *testing.T) {
+	sent := 0
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			sent++
+			res := &http.Response{StatusCode: http.StatusOK, Header: http.Header{"Content-Type": []string{"text/plain"}},
+				Body: io.NopCloser(strings.NewReader("greenadexchange.com,XF7342,DIRECT")), Request: req}
+			switch {
+			case req.URL.Host == "example.com":
+				res.StatusCode = http.StatusMovedPermanently
+				res.Header.Set("Location", "https://www.example.com/ads.txt")
+			case sent == 2:
+				res.StatusCode = http.StatusServiceUnavailable
+			}
+			if req.Header.Get("X-Crawl-Id") != "crawl-1" {
+				res.StatusCode = http.StatusForbidden
+			}
+			return res, nil
+		}),
+	}
+
+	stages := []string{}
+	hooks := Hooks{
+		OnRequest: func(ctx context.Context, req *Request) (*Response, error) {
+			stages = append(stages, "request")
+			req.Headers = http.Header{"X-Crawl-Id": []string{"crawl-1"}}
+			return nil, nil
+		},
+		OnRedirect: func(req *Request, redirect *Redirect) error {
+			stages = append(stages, "redirect")
+			return nil
+		},
+		OnRetry: func(req *Request, attempt int, status int, err error) error {
+			stages = append(stages, "retry")
+			if attempt != 1 || status != http.StatusServiceUnavailable {
+				t.Errorf("Expected retry of attempt [1] failed with [503] and not [%d] [%d]", attempt, status)
+			}
+			return nil
+		},
+		OnResponse: func(req *Request, res *http.Response) error {
+			stages = append(stages, "response")
+			return nil
+		},
+		OnParseComplete: func(req *Request, records *Records) error {
+			stages = append(stages, "parse")
+			records.DataRecords[0].PublisherAccountID = "XF0000"
+			return nil
+		},
+	}
+	c := NewCrawler(WithHTTPClient(client), WithHooks(hooks), WithRetry(RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond}))
+
+	req, _ := NewRequest("example.com")
+	res, err := c.GetWithContext(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := strings.Join(stages, ","); s != "request,response,redirect,retry,response,parse" {
+		t.Errorf("Expected hooks called in order of request stages and not [%s]", s)
+	}
+	if res.Records.DataRecords[0].PublisherAccountID != "XF0000" || len(res.Redirects) != 1 {
+		t.Errorf("Expected records modified by parse hook and not %v", res.Records.DataRecords[0])
+	}
+
+	// hook error aborts the request
+	errHook := errors.New("hook error")
+	hooks.OnRedirect = func(req *Request, redirect *Redirect) error { return errHook }
+	c = NewCrawler(WithHTTPClient(client), WithHooks(hooks))
+	req, _ = NewRequest("example.com")
+	if _, err := c.GetWithContext(context.Background(), req); !errors.Is(err, errHook) {
+		t.Errorf("Expected request aborted by redirect hook error and not [%v]", err)
+	}
+
+	// request short circuited by OnRequest hook response
+	sent = 0
+	cached := &Response{Records: &Records{}}
+	hooks.OnRequest = func(ctx context.Context, req *Request) (*Response, error) { return cached, nil }
+	c = NewCrawler(WithHTTPClient(client), WithHooks(hooks))
+	req, _ = NewRequest("example.com")
+	if res, err := c.GetWithContext(context.Background(), req); err != nil || res != cached || sent != 0 {
+		t.Errorf("Expected request hook response without HTTP request and not [%v] [%v] [%d]", res, err, sent)
+	}
+}
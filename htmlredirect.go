@@ -0,0 +1,60 @@
+package adstxt
+
+import (
+	"html"
+	"mime"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// HTML redirects patterns: meta refresh tag, and JavaScript location assignment or location replace\assign call
+var (
+	metaTagRe      = regexp.MustCompile(`(?is)<meta\s[^>]*>`)
+	metaRefreshRe  = regexp.MustCompile(`(?is)http-equiv\s*=\s*["']?\s*refresh`)
+	metaContentRe  = regexp.MustCompile(`(?is)content\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>]+))`)
+	refreshURLRe   = regexp.MustCompile(`(?is)^\s*\d*(?:\.\d*)?\s*[;,]?\s*url\s*=\s*['"]?([^'"]+)['"]?\s*$`)
+	jsLocationRe   = regexp.MustCompile(`(?s)(?:window\.|document\.|top\.|self\.)?location(?:\.href)?\s*=\s*["']([^"']+)["']`)
+	jsLocationFnRe = regexp.MustCompile(`(?s)(?:window\.|document\.|top\.|self\.)?location\.(?:replace|assign)\(\s*["']([^"']+)["']\s*\)`)
+)
+
+// htmlRedirect return the redirect location of HTML page that redirects using meta refresh tag or JavaScript location
+// change, or empty string if the page has no redirect. Meta refresh tag is preferred over JavaScript redirect
+func htmlRedirect(page []byte) string {
+	for _, tag := range metaTagRe.FindAll(page, -1) {
+		if !metaRefreshRe.Match(tag) {
+			continue
+		}
+		m := metaContentRe.FindSubmatch(tag)
+		if m == nil {
+			continue
+		}
+		content := string(m[1]) + string(m[2]) + string(m[3])
+		if u := refreshURLRe.FindStringSubmatch(html.UnescapeString(content)); u != nil {
+			return strings.TrimSpace(u[1])
+		}
+	}
+
+	for _, re := range []*regexp.Regexp{jsLocationRe, jsLocationFnRe} {
+		if m := re.FindSubmatch(page); m != nil {
+			return strings.TrimSpace(string(m[1]))
+		}
+	}
+	return ""
+}
+
+// isHTMLContentType check if HTTP response Content-Type is HTML media type
+func isHTMLContentType(res *http.Response) bool {
+	mediaType, _, err := mime.ParseMediaType(res.Header.Get("Content-Type"))
+	return err == nil && (mediaType == "text/html" || mediaType == "application/xhtml+xml")
+}
+
+// htmlRedirected check if any of the redirects is HTML redirect
+func htmlRedirected(redirects []*Redirect) bool {
+	for _, r := range redirects {
+		if r.HTML {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package adstxt
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestHTMLRedirect test detection of meta refresh and JavaScript redirects in HTML page
+func TestHTMLRedirect(t *testing.T) {
+	tests := map[string]string{
+		`<html><head><meta http-equiv="refresh" content="0; url=https://example.com/files/ads.txt"></head></html>`: "https://example.com/files/ads.txt",
+		`<HTML><META CONTENT='5;URL=/ads/ads.txt' HTTP-EQUIV='Refresh'></HTML>`:                                    "/ads/ads.txt",
+		`<html><meta http-equiv=refresh content="0;url=&#39;/x/ads.txt&#39;"></html>`:                              "/x/ads.txt",
+		`<html><script>window.location.href = "https://cdn.example.com/ads.txt";</script></html>`:                  "https://cdn.example.com/ads.txt",
+		`<html><script>location.replace('/static/ads.txt')</script></html>`:                                        "/static/ads.txt",
+		`<html><meta name="viewport" content="width=device-width"><body>Page not found</body></html>`:              "",
+		`<html><meta http-equiv="refresh" content="30"></html>`:                                                    "",
+	}
+	for page, expected := range tests {
+		if location := htmlRedirect([]byte(page)); location != expected {
+			t.Errorf("Expected HTML redirect location of [%s] to be [%s] and not [%s]", page, expected, location)
+		}
+	}
+}
+
+// TestWithHTMLRedirects test crawler follow HTML redirects to Ads.txt file, and flag the response
+func TestWithHTMLRedirects(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ads.txt":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			fmt.Fprint(w, `<html><head><meta http-equiv="refresh" content="0; url=/files/ads.txt"></head></html>`)
+		case "/files/ads.txt":
+			w.Header().Set("Content-Type", "text/plain")
+			fmt.Fprint(w, "greenadexchange.com,XF7342,DIRECT")
+		case "/home/ads.txt":
+			w.Header().Set("Content-Type", "text/plain")
+			fmt.Fprint(w, `<html><script>location.href = "/";</script></html>`)
+		}
+	}))
+	defer ts.Close()
+
+	// HTML redirects are not followed by default, and the redirect location is reported
+	_, err := NewCrawler(WithIgnoreContentType(true)).Get(&Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"})
+	var htmlErr *ErrHTMLPage
+	if !errors.As(err, &htmlErr) || htmlErr.Redirect != "/files/ads.txt" {
+		t.Errorf("Expected ErrHTMLPage with HTML redirect location and not [%v]", err)
+	}
+
+	c := NewCrawler(WithHTMLRedirects(true))
+	res, err := c.Get(&Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.DataRecords) != 1 || !res.HTMLRedirected {
+		t.Errorf("Expected Ads.txt file of HTML redirect destination, flagged as HTML redirected")
+	}
+	if len(res.Redirects) != 1 || !res.Redirects[0].HTML || res.Redirects[0].Location != ts.URL+"/files/ads.txt" {
+		t.Errorf("Expected HTML redirect in redirects chain and not %+v", res.Redirects)
+	}
+
+	// HTML redirect must lead to Ads.txt file
+	var redirectErr *ErrRedirect
+	if _, err := c.Get(&Request{URL: ts.URL + "/home/ads.txt", Domain: "127.0.0.1"}); !errors.As(err, &redirectErr) {
+		t.Errorf("Expected HTML redirect to home page to fail with ErrRedirect and not [%v]", err)
+	}
+}
+
+// TestHTMLRedirectScope test HTML redirects are checked by the root domain scope of HTTP redirects: delegated host
+// must not redirect, even by HTML redirect within its own domain
+func TestHTMLRedirectScope(t *testing.T) {
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Host == "delegated.com" {
+				return &http.Response{StatusCode: http.StatusFound, Status: "302 Found", Body: http.NoBody, Request: req,
+					Header: http.Header{"Location": []string{"http://cdn.adhost.net/ads.txt"}}}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/html"}},
+				Body:       io.NopCloser(strings.NewReader(`<html><meta http-equiv="refresh" content="0; url=/files/ads.txt"></html>`)),
+				Request:    req,
+			}, nil
+		}),
+	}
+	c := NewCrawler(WithHTTPClient(client), WithHTMLRedirects(true))
+
+	_, err := c.Get(&Request{URL: "http://delegated.com/ads.txt", Domain: "delegated.com"})
+	if !errors.Is(err, ErrRedirectOutOfScope) {
+		t.Errorf("Expected HTML redirect of delegated host to fail with redirect error and not [%v]", err)
+	}
+}
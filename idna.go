@@ -0,0 +1,56 @@
+package adstxt
+
+import (
+	"golang.org/x/net/idna"
+)
+
+// asciiHost return the ASCII (punycode) form of internationalized host name, as used in Ads.txt HTTP requests. Host
+// names that are not valid IDNA names (for example IP addresses) are returned as is
+func asciiHost(host string) string {
+	// most host names are already lower case ASCII names: skip IDNA mapping
+	if isLowerASCII(host) {
+		return host
+	}
+	if a, err := idna.Lookup.ToASCII(host); err == nil {
+		return a
+	}
+	return host
+}
+
+// unicodeHost return the Unicode form of internationalized host name (punycode labels are decoded). Host names that
+// are not valid IDNA names are returned as is
+func unicodeHost(host string) string {
+	if u, err := idna.Lookup.ToUnicode(host); err == nil {
+		return u
+	}
+	return host
+}
+
+// isLowerASCII check if host name holds only lower case ASCII letters, digits, hyphens and dots (not leading), so its
+// IDNA ASCII form is the host name itself
+func isLowerASCII(host string) bool {
+	if len(host) == 0 || host[0] == '.' {
+		return false
+	}
+	for i := 0; i < len(host); i++ {
+		c := host[i]
+		if !('a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+// isHostName check if host name holds only ASCII letters, digits, hyphens and dots
+func isHostName(host string) bool {
+	if len(host) == 0 {
+		return false
+	}
+	for i := 0; i < len(host); i++ {
+		c := host[i]
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '-' || c == '.') {
+			return false
+		}
+	}
+	return true
+}
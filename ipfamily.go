@@ -0,0 +1,251 @@
+package adstxt
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// IP address families (see WithIPFamily and Response.AddressFamily)
+const (
+	// IPFamilyAny connect to remote host addresses of any family (default)
+	IPFamilyAny IPFamily = ""
+	// IPv4 IPv4 address family
+	IPv4 IPFamily = "ipv4"
+	// IPv6 IPv6 address family
+	IPv6 IPFamily = "ipv6"
+)
+
+// IPFamily IP address family of the connection to remote host
+type IPFamily string
+
+// network return dial network of TCP connection restricted to the address family
+func (f IPFamily) network(network string) string {
+	if network != "tcp" {
+		return network
+	}
+	switch f {
+	case IPv4:
+		return "tcp4"
+	case IPv6:
+		return "tcp6"
+	}
+	return network
+}
+
+// networkFamily return the address family of dial network, or IPFamilyAny if network is not restricted to single
+// address family
+func networkFamily(network string) IPFamily {
+	switch network {
+	case "tcp4":
+		return IPv4
+	case "tcp6":
+		return IPv6
+	}
+	return IPFamilyAny
+}
+
+// ipFamily return the address family of IP address, or IPFamilyAny if addr is not an IP address
+func ipFamily(addr string) IPFamily {
+	ip := net.ParseIP(addr)
+	switch {
+	case ip == nil:
+		return IPFamilyAny
+	case ip.To4() != nil:
+		return IPv4
+	}
+	return IPv6
+}
+
+// familyDial return dial function that restricts TCP connections to the crawler address family (see WithIPFamily)
+func (c *Crawler) familyDial(dial DialFunc) DialFunc {
+	if c.ipFamily == IPFamilyAny {
+		return dial
+	}
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		return dial(ctx, c.ipFamily.network(network), address)
+	}
+}
+
+// familyError return ErrDNS of request that failed since the remote host has no address of the crawler address family,
+// or nil if err is other failure. The dialer reports such failure as *net.AddrError, before any connection is made
+func (c *Crawler) familyError(req *Request, err error) *ErrDNS {
+	var addrErr *net.AddrError
+	if c.ipFamily == IPFamilyAny || !errors.As(err, &addrErr) {
+		return nil
+	}
+	host := addrErr.Addr
+	if u, perr := url.Parse(req.URL); perr == nil {
+		host = u.Hostname()
+	}
+	return &ErrDNS{Host: host, URL: req.URL, Err: err}
+}
+
+// familyAddrs return the addresses of the dial network address family, in order
+func familyAddrs(network string, addrs []string) []string {
+	family := networkFamily(network)
+	if family == IPFamilyAny {
+		return addrs
+	}
+
+	matched := []string{}
+	for _, addr := range addrs {
+		if ipFamily(addr) == family {
+			matched = append(matched, addr)
+		}
+	}
+	return matched
+}
+
+// dialSerial dial addresses in order until a connection is made. Return the first dial error if no connection was
+// made
+func dialSerial(ctx context.Context, dial func(ctx context.Context, addr string) (net.Conn, error), addrs []string) (net.Conn, error) {
+	var dialErr error
+	for _, addr := range addrs {
+		conn, err := dial(ctx, addr)
+		if err == nil {
+			return conn, nil
+		}
+		if dialErr == nil {
+			dialErr = err
+		}
+	}
+	return nil, dialErr
+}
+
+// dialResult connection made by single dial race, or its error
+type dialResult struct {
+	conn net.Conn
+	err  error
+}
+
+// dialHappyEyeballs dial addresses of the first address family in order, and start dialing addresses of the other
+// family in parallel once the fallback delay has passed or the first family failed (Happy Eyeballs, RFC 8305). Return
+// the first connection made: connection made by the other race is closed
+func dialHappyEyeballs(ctx context.Context, dial func(ctx context.Context, addr string) (net.Conn, error), addrs []string,
+	delay time.Duration) (net.Conn, error) {
+	primaries, fallbacks := []string{}, []string{}
+	for _, addr := range addrs {
+		if ipFamily(addr) == ipFamily(addrs[0]) {
+			primaries = append(primaries, addr)
+		} else {
+			fallbacks = append(fallbacks, addr)
+		}
+	}
+	if len(fallbacks) == 0 {
+		return dialSerial(ctx, dial, primaries)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan dialResult, 2)
+	race := func(addrs []string) {
+		go func() {
+			conn, err := dialSerial(ctx, dial, addrs)
+			results <- dialResult{conn: conn, err: err}
+		}()
+	}
+	race(primaries)
+	pending, fallback := 1, false
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	var dialErr error
+	for {
+		select {
+		case <-timer.C:
+		case r := <-results:
+			pending--
+			if r.err == nil {
+				// the other race is canceled, and its connection is closed if it was made at the same time
+				if pending > 0 {
+					go func() {
+						if r := <-results; r.conn != nil {
+							r.conn.Close()
+						}
+					}()
+				}
+				return r.conn, nil
+			}
+			if dialErr == nil {
+				dialErr = r.err
+			}
+			if fallback && pending == 0 {
+				return nil, dialErr
+			}
+		}
+		if !fallback {
+			fallback = true
+			pending++
+			race(fallbacks)
+		}
+	}
+}
+
+// remoteConnContextKey context key of the remote address of HTTP request connection
+type remoteConnContextKey struct{}
+
+// remoteConn remote address of the connection that served HTTP request
+type remoteConn struct {
+	addr    net.Addr
+	proxied bool // request was sent through proxy, so addr is the proxy address
+	mu      sync.Mutex
+}
+
+// withRemoteConn return copy of ctx that records the remote address of the connection that serves the HTTP request
+// (see remoteAddr)
+func withRemoteConn(ctx context.Context) context.Context {
+	rc := &remoteConn{}
+	ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Conn == nil {
+				return
+			}
+			rc.mu.Lock()
+			rc.addr = info.Conn.RemoteAddr()
+			rc.mu.Unlock()
+		},
+	})
+	return context.WithValue(ctx, remoteConnContextKey{}, rc)
+}
+
+// setProxied mark the HTTP request of ctx as sent through proxy (see WithProxyFunc)
+func setProxied(ctx context.Context) {
+	if rc, _ := ctx.Value(remoteConnContextKey{}).(*remoteConn); rc != nil {
+		rc.mu.Lock()
+		rc.proxied = true
+		rc.mu.Unlock()
+	}
+}
+
+// remoteAddr return the remote address ("ip:port") and address family of the connection that served HTTP response,
+// or empty values if the response was not received over direct network connection to remote host (e.g. custom
+// Fetcher, or request sent through the crawler proxy)
+func remoteAddr(res *http.Response) (string, IPFamily) {
+	if res.Request == nil {
+		return "", IPFamilyAny
+	}
+	rc, _ := res.Request.Context().Value(remoteConnContextKey{}).(*remoteConn)
+	if rc == nil {
+		return "", IPFamilyAny
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if rc.addr == nil || rc.proxied {
+		return "", IPFamilyAny
+	}
+	host, _, err := net.SplitHostPort(rc.addr.String())
+	if err != nil {
+		return "", IPFamilyAny
+	}
+	return rc.addr.String(), ipFamily(host)
+}
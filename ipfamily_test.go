@@ -0,0 +1,137 @@
+package adstxt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestIPFamily test crawler connect only to addresses of the address family, and report the address family that
+// served the response
+func TestIPFamily(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, "greenadexchange.com,XF7342,DIRECT")
+	}))
+	defer ts.Close()
+
+	res, err := NewCrawler().Get(&Request{URL: ts.URL + "/ads.txt", Domain: "example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.AddressFamily != IPv4 || res.RemoteAddr != ts.Listener.Addr().String() {
+		t.Errorf("Expected response served over [%s] from [%s] and not [%s] [%s]", IPv4, ts.Listener.Addr(), res.AddressFamily, res.RemoteAddr)
+	}
+
+	// dual stack host: only addresses of the crawler address family are dialed
+	cache := NewTTLDNSCache(time.Minute)
+	cache.Set("dualstack.test", []string{"::1", "127.0.0.1"})
+	cache.Set("ipv4.test", []string{"127.0.0.1"})
+	c := NewCrawler(WithDNSCache(cache), WithIPFamily(IPv4))
+	u := strings.Replace(ts.URL, "127.0.0.1", "dualstack.test", 1) + "/ads.txt"
+	if res, err := c.Get(&Request{URL: u, Domain: "dualstack.test"}); err != nil || res.AddressFamily != IPv4 {
+		t.Errorf("Expected Ads.txt file to be fetched over [%s] [%v]", IPv4, err)
+	}
+
+	var dnsErr *ErrDNS
+	c = NewCrawler(WithDNSCache(cache), WithIPFamily(IPv6))
+	u = strings.Replace(ts.URL, "127.0.0.1", "ipv4.test", 1) + "/ads.txt"
+	if _, err := c.Get(&Request{URL: u, Domain: "ipv4.test"}); !errors.As(err, &dnsErr) {
+		t.Errorf("Expected [%s] request of host without IPv6 address to fail with ErrDNS and not [%v]", IPv6, err)
+	}
+
+	// host without address of the family fails with ErrDNS when dialed without DNS cache as well
+	c = NewCrawler(WithIPFamily(IPv6))
+	if _, err := c.Get(&Request{URL: ts.URL + "/ads.txt", Domain: "example.com"}); !errors.As(err, &dnsErr) || dnsErr.Host != "127.0.0.1" {
+		t.Errorf("Expected [%s] request of IPv4 address to fail with ErrDNS and not [%v]", IPv6, err)
+	}
+
+	// remote address of proxied request is the proxy address, so it is not reported
+	proxy, _ := url.Parse(ts.URL)
+	if res, err := NewCrawler(WithProxy(proxy)).Get(&Request{URL: "http://example.com/ads.txt", Domain: "example.com"}); err != nil ||
+		res.RemoteAddr != "" || res.AddressFamily != IPFamilyAny {
+		t.Errorf("Expected no remote address of proxied request and not [%v] [%v]", res, err)
+	}
+
+	// custom dial function is called with network of the address family
+	network := ""
+	c = NewCrawler(WithIPFamily(IPv4), WithDialContext(func(ctx context.Context, n, address string) (net.Conn, error) {
+		network = n
+		return (&net.Dialer{}).DialContext(ctx, n, address)
+	}))
+	if _, err := c.Get(&Request{URL: ts.URL + "/ads.txt", Domain: "example.com"}); err != nil || network != "tcp4" {
+		t.Errorf("Expected custom dial function to be called with [tcp4] network and not [%s] [%v]", network, err)
+	}
+}
+
+// TestHappyEyeballs test addresses of the other family are dialed once the first family did not connect within the
+// fallback delay, or failed
+func TestHappyEyeballs(t *testing.T) {
+	var mu sync.Mutex
+	dialed := []string{}
+	dial := func(ctx context.Context, addr string) (net.Conn, error) {
+		mu.Lock()
+		dialed = append(dialed, addr)
+		mu.Unlock()
+
+		switch addr {
+		case "2001:db8::1":
+			// unreachable address: connection attempt hangs until canceled
+			<-ctx.Done()
+			return nil, ctx.Err()
+		case "2001:db8::2", "2001:db8::3":
+			return nil, errors.New("connection refused")
+		}
+		conn, _ := net.Pipe()
+		return conn, nil
+	}
+	// order return the addresses dialed since the previous call, in order
+	order := func() string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		o := strings.Join(dialed, " ")
+		dialed = dialed[:0]
+		return o
+	}
+
+	start := time.Now()
+	conn, err := dialHappyEyeballs(context.Background(), dial, []string{"2001:db8::1", "192.0.2.1"}, 20*time.Millisecond)
+	if err != nil || conn == nil || time.Since(start) < 20*time.Millisecond {
+		t.Fatalf("Expected IPv4 connection after fallback delay [%v]", err)
+	}
+	conn.Close()
+	if o := order(); o != "2001:db8::1 192.0.2.1" {
+		t.Errorf("Expected IPv6 address to be dialed before IPv4 address and not [%s]", o)
+	}
+
+	// fallback is dialed immediately once all addresses of the first family failed, in order
+	start = time.Now()
+	conn, err = dialHappyEyeballs(context.Background(), dial, []string{"2001:db8::2", "192.0.2.1", "2001:db8::3"}, time.Hour)
+	if err != nil || conn == nil || time.Since(start) > time.Second {
+		t.Fatalf("Expected IPv4 connection once IPv6 connections failed [%v]", err)
+	}
+	conn.Close()
+	if o := order(); o != "2001:db8::2 2001:db8::3 192.0.2.1" {
+		t.Errorf("Expected IPv6 addresses to be dialed in order before IPv4 address and not [%s]", o)
+	}
+
+	// single family addresses are dialed in order
+	if _, err := dialHappyEyeballs(context.Background(), dial, []string{"2001:db8::2", "2001:db8::3"}, time.Millisecond); err == nil || err.Error() != "connection refused" {
+		t.Errorf("Expected dial error of single family address and not [%v]", err)
+	}
+	if o := order(); o != "2001:db8::2 2001:db8::3" {
+		t.Errorf("Expected single family addresses to be dialed in order and not [%s]", o)
+	}
+	if addrs := familyAddrs("tcp6", []string{"192.0.2.1", "2001:db8::1", "::ffff:192.0.2.2"}); len(addrs) != 1 || addrs[0] != "2001:db8::1" {
+		t.Errorf("Expected only IPv6 addresses and not %v", addrs)
+	}
+}
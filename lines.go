@@ -0,0 +1,152 @@
+package adstxt
+
+import "sort"
+
+// Ads.txt file line kinds (see Record)
+const (
+	// KindDataRecord line holds data record
+	KindDataRecord RecordKind = "data"
+	// KindVariable line holds variable record
+	KindVariable RecordKind = "variable"
+	// KindComment line is full-line comment, empty line or placeholder record
+	KindComment RecordKind = "comment"
+	// KindInvalid line could not be parsed, or its record was dropped
+	KindInvalid RecordKind = "invalid"
+)
+
+// RecordKind kind of Ads.txt file line
+type RecordKind string
+
+// Record single Ads.txt file line in unified form: *DataRecord, *Variable, *Comment or *Invalid. Records of all
+// lines of Ads.txt file in file order (see Records.Lines) hold exactly the content of the file
+type Record interface {
+	// Kind of the line
+	Kind() RecordKind
+	// Index of the line in the Ads.txt file (starting from 1), 0 if the record was not parsed from Ads.txt file
+	Index() int
+	// Raw original text of the line
+	Raw() string
+}
+
+// Comment Ads.txt line that holds no record: full-line comment, empty line or the IAB placeholder record
+type Comment struct {
+	Line        int    `json:"line"`                  // Line index of the comment in the Ads.txt file
+	Text        string `json:"txt"`                   // Text original Ads.txt line
+	Placeholder bool   `json:"placeholder,omitempty"` // Placeholder line is the placeholder record of Ads.txt file without authorized sellers
+}
+
+// Invalid Ads.txt line that could not be parsed into record, or whose record was dropped (for example variable that
+// is not allowed to be declared more than once)
+type Invalid struct {
+	Line    int      `json:"line"`              // Line index of the invalid line in the Ads.txt file
+	Text    string   `json:"txt"`               // Text original Ads.txt line
+	Warning *Warning `json:"warning,omitempty"` // Warning parse warning of the line, nil if the line was dropped without warning
+}
+
+// Kind is the Record interface implementation for DataRecord
+func (dr *DataRecord) Kind() RecordKind { return KindDataRecord }
+
+// Index is the Record interface implementation for DataRecord
+func (dr *DataRecord) Index() int { return dr.Line }
+
+// Raw is the Record interface implementation for DataRecord
+func (dr *DataRecord) Raw() string { return dr.Text }
+
+// Kind is the Record interface implementation for Variable
+func (v *Variable) Kind() RecordKind { return KindVariable }
+
+// Index is the Record interface implementation for Variable
+func (v *Variable) Index() int { return v.Line }
+
+// Raw is the Record interface implementation for Variable
+func (v *Variable) Raw() string { return v.Text }
+
+// Kind is the Record interface implementation for Comment
+func (c *Comment) Kind() RecordKind { return KindComment }
+
+// Index is the Record interface implementation for Comment
+func (c *Comment) Index() int { return c.Line }
+
+// Raw is the Record interface implementation for Comment
+func (c *Comment) Raw() string { return c.Text }
+
+// Kind is the Record interface implementation for Invalid
+func (i *Invalid) Kind() RecordKind { return KindInvalid }
+
+// Index is the Record interface implementation for Invalid
+func (i *Invalid) Index() int { return i.Line }
+
+// Raw is the Record interface implementation for Invalid
+func (i *Invalid) Raw() string { return i.Text }
+
+// Record return the unified form of parsed Ads.txt line (see Record)
+func (l Line) Record() Record {
+	switch {
+	case l.DataRecord != nil:
+		return l.DataRecord
+	case l.Variable != nil:
+		return l.Variable
+	case l.Warning != nil:
+		return &Invalid{Line: l.Index, Text: l.Text, Warning: l.Warning}
+	}
+	if line := removeComment(l.Text); l.Placeholder || len(line) == 0 {
+		return &Comment{Line: l.Index, Text: l.Text, Placeholder: l.Placeholder}
+	}
+	return &Invalid{Line: l.Index, Text: l.Text}
+}
+
+// Lines return record of each Ads.txt file line, in order of the file (see Record), so the Ads.txt file can be
+// reconstructed from the records raw text. Records that were not parsed from Ads.txt file (without Body) return
+// their data records and variables, ordered by line index
+func (r *Records) Lines() []Record {
+	byIndex := map[int]Record{}
+	for _, dr := range r.DataRecords {
+		if index := r.Line(dr); index > 0 {
+			byIndex[index] = dr
+		}
+	}
+	for _, v := range r.Variables {
+		if index := r.Line(v); index > 0 {
+			byIndex[index] = v
+		}
+	}
+
+	if len(r.Body) == 0 {
+		lines := make([]Record, 0, len(r.DataRecords)+len(r.Variables))
+		for _, dr := range r.DataRecords {
+			lines = append(lines, dr)
+		}
+		for _, v := range r.Variables {
+			lines = append(lines, v)
+		}
+		sort.SliceStable(lines, func(i, j int) bool { return r.Line(lines[i]) < r.Line(lines[j]) })
+		return lines
+	}
+
+	warnings := map[int]*Warning{}
+	for _, w := range r.Warnings {
+		if _, ok := warnings[w.Index]; !ok {
+			warnings[w.Index] = w
+		}
+	}
+
+	lines := make([]Record, 0, len(r.Body))
+	for i, txt := range r.Body {
+		index := i + 1
+		if record, ok := byIndex[index]; ok {
+			lines = append(lines, record)
+			continue
+		}
+
+		line := removeComment(txt)
+		switch {
+		case len(line) == 0:
+			lines = append(lines, &Comment{Line: index, Text: txt})
+		case isPlaceholderRecord(line):
+			lines = append(lines, &Comment{Line: index, Text: txt, Placeholder: true})
+		default:
+			lines = append(lines, &Invalid{Line: index, Text: txt, Warning: warnings[index]})
+		}
+	}
+	return lines
+}
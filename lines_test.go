@@ -0,0 +1,81 @@
+package adstxt
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestRecordsLines test Ads.txt file lines in unified form and file order
+func TestRecordsLines(t *testing.T) {
+	body := strings.Join([]string{
+		"# Ads.txt file",
+		"greenadexchange.com, XF7342, DIRECT # inline",
+		"",
+		"contact=adops@example.com",
+		"invalid line",
+		"ownerdomain=example.com",
+		"ownerdomain=other.com",
+		"placeholder.example.com, placeholder, DIRECT, placeholder",
+	}, "\n")
+	records, _ := ParseBody([]byte(body))
+
+	expected := []RecordKind{KindComment, KindDataRecord, KindComment, KindVariable, KindInvalid, KindVariable, KindInvalid, KindComment}
+	lines := records.Lines()
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected [%d] lines and not [%d]", len(expected), len(lines))
+	}
+
+	raw := []string{}
+	for i, l := range lines {
+		if l.Kind() != expected[i] || l.Index() != i+1 {
+			t.Errorf("Expected line [%d] of kind [%s] and not [%s] [%d]", i+1, expected[i], l.Kind(), l.Index())
+		}
+		raw = append(raw, l.Raw())
+	}
+	if strings.Join(raw, "\n") != body {
+		t.Errorf("Expected lines raw text to reconstruct Ads.txt file and not [%s]", strings.Join(raw, "\n"))
+	}
+
+	if dr, ok := lines[1].(*DataRecord); !ok || dr != records.DataRecords[0] {
+		t.Error("Expected data record line to be the parsed data record")
+	}
+	if inv := lines[4].(*Invalid); inv.Warning == nil || inv.Warning.Index != 5 {
+		t.Error("Expected invalid line with parse warning")
+	}
+	if inv := lines[6].(*Invalid); inv.Warning == nil {
+		t.Error("Expected dropped variable line with multiplicity warning")
+	}
+	if c := lines[7].(*Comment); !c.Placeholder {
+		t.Error("Expected placeholder record comment line")
+	}
+}
+
+// TestRecordsLinesWithoutBody test lines of records that were not parsed from Ads.txt file
+func TestRecordsLinesWithoutBody(t *testing.T) {
+	records := &Records{
+		DataRecords: []*DataRecord{{AdverterDomain: "greenadexchange.com", Line: 2}},
+		Variables:   []*Variable{{Type: "contact", Value: "adops@example.com", Line: 1}},
+	}
+
+	lines := records.Lines()
+	if len(lines) != 2 || lines[0].Kind() != KindVariable || lines[1].Kind() != KindDataRecord {
+		t.Errorf("Expected variable and data record lines ordered by line index and not %v", lines)
+	}
+}
+
+// TestLineRecord test unified form of streamed Ads.txt lines
+func TestLineRecord(t *testing.T) {
+	tests := map[string]RecordKind{
+		"greenadexchange.com, XF7342, DIRECT": KindDataRecord,
+		"contact=adops@example.com":           KindVariable,
+		"# comment":                           KindComment,
+		"":                                    KindComment,
+		"invalid line":                        KindInvalid,
+	}
+
+	for txt, kind := range tests {
+		if r := parseLine(1, txt).Record(); r.Kind() != kind || r.Raw() != txt || r.Index() != 1 {
+			t.Errorf("Expected line [%s] record of kind [%s] and not [%s]", txt, kind, r.Kind())
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package lint
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/tzafrirben/go-adstxt-crawler/adstxt"
+)
+
+// DirReport consolidated validation report of the Ads.txt files of directory (see ValidateDir)
+type DirReport struct {
+	Dir      string         `json:"dir,omitempty"` // Dir path of the validated directory, empty if validated file system is not a directory
+	Total    int            `json:"total"`         // Total number of validated Ads.txt files
+	Passed   int            `json:"passed"`        // Passed number of files without parse warnings, validation errors or lint errors
+	Failed   int            `json:"failed"`        // Failed number of files that did not pass validation
+	Findings map[string]int `json:"findings"`      // Findings number of lint findings of all files, by severity name
+	Files    []*FileReport  `json:"files"`         // Files validation report of each file, sorted by path
+}
+
+// FileReport validation report of single Ads.txt file of directory
+type FileReport struct {
+	Path             string             `json:"path"`                  // Path of the file in the directory, slash separated (e.g. example.com/ads.txt)
+	Domain           string             `json:"domain,omitempty"`      // Domain name of the file parent directory, empty if the file is at the root of the directory
+	Passed           bool               `json:"passed"`                // Passed the file has no parse warnings, validation errors or lint errors
+	DataRecords      int                `json:"dataRecords"`           // DataRecords number of parsed data records
+	Variables        int                `json:"variables"`             // Variables number of parsed variables
+	Warnings         []*adstxt.Warning  `json:"warnings"`              // Warnings parse warnings of the file
+	ValidationErrors []*ValidationIssue `json:"validationErrors"`      // ValidationErrors records validation errors of the file (see adstxt.Records.Validate)
+	Findings         []*Finding         `json:"findings"`              // Findings lint findings of the file, ranked by severity
+	Compliance       *ComplianceReport  `json:"compliance,omitempty"`  // Compliance score of the file (see Score), nil if the file failed to be read or parsed
+	Error            string             `json:"error,omitempty"`       // Error the file failed to be read or parsed
+	Max              Severity           `json:"maxSeverity,omitempty"` // Max highest severity of the file lint findings
+}
+
+// ValidationIssue single record field validation error of Ads.txt file (see adstxt.ValidationError)
+type ValidationIssue struct {
+	Line    int    `json:"line"`    // Line index of the invalid record
+	Record  string `json:"record"`  // Record canonical form of the invalid record
+	Field   string `json:"field"`   // Field name of the invalid record field
+	Value   string `json:"value"`   // Value of the invalid record field
+	Message string `json:"message"` // Message validation failure reason
+}
+
+// ValidateDir parse, validate and lint all Ads.txt files of local directory with the default rules (see
+// Linter.ValidateDir)
+func ValidateDir(dir string) (*DirReport, error) {
+	return New().ValidateDir(dir)
+}
+
+// ValidateDir parse, validate and lint all Ads.txt files (files named ads.txt or app-ads.txt, in any subdirectory) of
+// local directory, for example repository of the Ads.txt files of many domains kept in <domain>/ads.txt layout, and
+// return consolidated report of all files. Files that fail to be read are reported as failed files, and error is
+// returned only if the directory cannot be walked
+func (l *Linter) ValidateDir(dir string) (*DirReport, error) {
+	report, err := l.ValidateFS(os.DirFS(dir))
+	if err != nil {
+		return nil, err
+	}
+	report.Dir = dir
+	return report, nil
+}
+
+// ValidateFS parse, validate and lint all Ads.txt files of file system (see ValidateDir)
+func (l *Linter) ValidateFS(fsys fs.FS) (*DirReport, error) {
+	report := &DirReport{Findings: map[string]int{}, Files: []*FileReport{}}
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !isAdsTxtFile(d.Name()) {
+			return nil
+		}
+
+		f := l.validateFile(fsys, p)
+		report.Files = append(report.Files, f)
+		report.Total++
+		if f.Passed {
+			report.Passed++
+		} else {
+			report.Failed++
+		}
+		for _, finding := range f.Findings {
+			report.Findings[finding.Severity.String()]++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(report.Files, func(i, j int) bool { return report.Files[i].Path < report.Files[j].Path })
+	return report, nil
+}
+
+// validateFile parse, validate and lint single Ads.txt file of file system
+func (l *Linter) validateFile(fsys fs.FS, p string) *FileReport {
+	f := &FileReport{Path: p, Warnings: []*adstxt.Warning{}, ValidationErrors: []*ValidationIssue{}, Findings: []*Finding{}}
+	if dir := path.Dir(p); dir != "." {
+		f.Domain = path.Base(dir)
+	}
+
+	body, err := fs.ReadFile(fsys, p)
+	if err != nil {
+		f.Error = err.Error()
+		return f
+	}
+	records, err := adstxt.ParseBody(body)
+	if err != nil {
+		f.Error = err.Error()
+		return f
+	}
+
+	f.DataRecords, f.Variables = len(records.DataRecords), len(records.Variables)
+	f.Warnings = append(f.Warnings, records.Warnings...)
+	if err := records.Validate(); err != nil {
+		if errs, ok := err.(adstxt.ValidationErrors); ok {
+			for _, e := range errs {
+				f.ValidationErrors = append(f.ValidationErrors, &ValidationIssue{Line: e.Line, Record: e.Record, Field: e.Field,
+					Value: e.Value, Message: e.Err.Error()})
+			}
+		}
+	}
+	f.Findings = l.Lint(records)
+	f.Max = Max(f.Findings)
+	f.Compliance = l.Score(records, nil)
+	f.Compliance.Domain = f.Domain
+
+	f.Passed = len(f.Warnings) == 0 && len(f.ValidationErrors) == 0 && f.Max < Error
+	return f
+}
+
+// isAdsTxtFile check if file name is name of Ads.txt file (ads.txt or app-ads.txt, case insensitive)
+func isAdsTxtFile(name string) bool {
+	switch strings.ToLower(name) {
+	case "ads.txt", "app-ads.txt":
+		return true
+	}
+	return false
+}
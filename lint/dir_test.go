@@ -0,0 +1,74 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+// TestValidateFS test consolidated validation report of Ads.txt files directory
+func TestValidateFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"example.com/ads.txt":     {Data: []byte("google.com, pub-0000000000000001, DIRECT, f08c47fec0942fa0\ncontact=adops@example.com")},
+		"invalid.com/ads.txt":     {Data: []byte("google.com, pub-0000000000000001, DIRECT, f08c47fec0942fa0\ngoogle.com, pub-0000000000000001, RESELLER, f08c47fec0942fa0")},
+		"apps.com/app-ads.txt":    {Data: []byte("google.com, pub-0000000000000002, DIRECT, f08c47fec0942fa0\nnot a record")},
+		"example.com/README.md":   {Data: []byte("# Ads.txt files")},
+		"example.com/ads.txt.bak": {Data: []byte("not a record")},
+	}
+
+	report, err := New().ValidateFS(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Total != 3 || report.Passed != 1 || report.Failed != 2 {
+		t.Fatalf("Expected [3] files, [1] passed and [2] failed and not [%d] [%d] [%d]", report.Total, report.Passed, report.Failed)
+	}
+
+	expected := []struct {
+		path   string
+		domain string
+		passed bool
+	}{
+		{"apps.com/app-ads.txt", "apps.com", false},
+		{"example.com/ads.txt", "example.com", true},
+		{"invalid.com/ads.txt", "invalid.com", false},
+	}
+	for i, e := range expected {
+		if f := report.Files[i]; f.Path != e.path || f.Domain != e.domain || f.Passed != e.passed {
+			t.Errorf("Expected file [%d] to be [%s] of [%s] passed [%t] and not %+v", i, e.path, e.domain, e.passed, f)
+		}
+	}
+	if f := report.Files[0]; len(f.Warnings) != 1 || f.DataRecords != 1 {
+		t.Errorf("Expected parse warning of invalid line and not %+v", f)
+	}
+	if f := report.Files[2]; f.Max != Error || len(f.Findings) != 1 || f.Findings[0].Rule != "conflicting-relationship" {
+		t.Errorf("Expected conflicting relationship lint error and not %v", f.Findings)
+	}
+	if report.Findings["error"] != 1 || report.Files[1].Compliance == nil {
+		t.Errorf("Expected findings by severity and compliance score and not %v", report.Findings)
+	}
+}
+
+// TestValidateDir test validation of local Ads.txt files directory
+func TestValidateDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "example.com"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "example.com", "ads.txt"), []byte("google.com, pub-0000000000000001, DIRECT, f08c47fec0942fa0"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := ValidateDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Dir != dir || report.Total != 1 || report.Passed != 1 {
+		t.Errorf("Expected single passed file of [%s] and not %+v", dir, report)
+	}
+
+	if _, err := ValidateDir(filepath.Join(dir, "missing")); err == nil {
+		t.Error("Expected error of missing directory")
+	}
+}
@@ -0,0 +1,184 @@
+// Package lint check Ads.txt files against configurable rules of common mistakes that the parser accepts or drops
+// silently (duplicate entries, mixed-case domains, missing certification authority IDs etc.), and report findings
+// ranked by severity
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tzafrirben/go-adstxt-crawler/adstxt"
+)
+
+// findings severity levels
+const (
+	// Info finding of style issue, that does not affect how the Ads.txt file is read
+	Info Severity = 1 + iota
+	// Warning finding that may cause the Ads.txt file to be read differently than intended
+	Warning
+	// Error finding of Ads.txt entry that buyers are likely to ignore or misread
+	Error
+)
+
+// Severity of lint finding
+type Severity int
+
+// String return the severity name: info, warning or error
+func (s Severity) String() string {
+	switch s {
+	case Info:
+		return "info"
+	case Warning:
+		return "warning"
+	case Error:
+		return "error"
+	}
+	return fmt.Sprintf("severity(%d)", int(s))
+}
+
+// MarshalJSON encode severity as its name
+func (s Severity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// ParseSeverity parse severity name case-insensitively: info, warning or error
+func ParseSeverity(name string) (Severity, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "info":
+		return Info, nil
+	case "warning":
+		return Warning, nil
+	case "error":
+		return Error, nil
+	}
+	return 0, fmt.Errorf("[%s] is not a valid severity", name)
+}
+
+// Finding single rule violation found in Ads.txt file
+type Finding struct {
+	Rule     string   `json:"rule"`          // Rule name of the violated rule
+	Severity Severity `json:"severity"`      // Severity of the finding
+	Line     int      `json:"line"`          // Line index of the finding in the Ads.txt file, 0 if it applies to the whole file
+	Text     string   `json:"txt,omitempty"` // Text original Ads.txt line of the finding
+	Message  string   `json:"message"`       // Message description of the finding
+}
+
+// String return the finding as single line: line <index>: <severity> [<rule>] <message>
+func (f *Finding) String() string {
+	return fmt.Sprintf("line %d: %s [%s] %s", f.Line, f.Severity, f.Rule, f.Message)
+}
+
+// Rule single lint rule. Check return the rule findings of Ads.txt records: Rule and Severity fields of the findings
+// do not have to be set, as the linter sets them from the rule (see Linter.Severities)
+type Rule interface {
+	// Name unique name of the rule, in kebab case (e.g. duplicate-record)
+	Name() string
+	// Severity default severity of the rule findings
+	Severity() Severity
+	// Check Ads.txt records and return the rule findings
+	Check(records *adstxt.Records) []*Finding
+}
+
+// Linter check Ads.txt files against set of rules
+type Linter struct {
+	Rules      []Rule              // Rules lint rules, in order of checks
+	Severities map[string]Severity // Severities override the default severity of rules, by rule name
+}
+
+// New create new Linter with rules, or with the default rules if no rule is specified (see DefaultRules)
+func New(rules ...Rule) *Linter {
+	if len(rules) == 0 {
+		rules = DefaultRules()
+	}
+	return &Linter{Rules: rules, Severities: map[string]Severity{}}
+}
+
+// DefaultRules return new copy of all rules with their default settings
+func DefaultRules() []Rule {
+	return []Rule{
+		&MissingCertAuthorityID{AdSystems: DefaultCertAuthorityAdSystems},
+		&DuplicateRecord{},
+		&MixedCaseDomain{},
+		&TrailingWhitespace{},
+		&PlaceholderWithRecords{},
+		&UnknownRelationship{},
+		&ConflictingRelationship{},
+		&ConflictingCertAuthorityID{},
+		&MalformedAccountID{Formats: DefaultAccountIDFormats.Clone()},
+	}
+}
+
+// Disable remove rules by name from the linter
+func (l *Linter) Disable(names ...string) {
+	disabled := map[string]bool{}
+	for _, n := range names {
+		disabled[strings.TrimSpace(n)] = true
+	}
+
+	rules := []Rule{}
+	for _, r := range l.Rules {
+		if !disabled[r.Name()] {
+			rules = append(rules, r)
+		}
+	}
+	l.Rules = rules
+}
+
+// AccountIDFormats return the publisher account ID formats checked by the linter malformed-account-id rule, that
+// custom formats of the linter are registered on, or nil if the linter has no such rule
+func (l *Linter) AccountIDFormats() AccountIDFormats {
+	for _, r := range l.Rules {
+		if rule, ok := r.(*MalformedAccountID); ok {
+			return rule.Formats
+		}
+	}
+	return nil
+}
+
+// Lint check Ads.txt records against all linter rules, and return all findings ranked by severity: highest severity
+// first, and by line index for findings of the same severity
+func (l *Linter) Lint(records *adstxt.Records) []*Finding {
+	findings := []*Finding{}
+	for _, r := range l.Rules {
+		severity := r.Severity()
+		if s, ok := l.Severities[r.Name()]; ok {
+			severity = s
+		}
+
+		for _, f := range r.Check(records) {
+			f.Rule = r.Name()
+			f.Severity = severity
+			findings = append(findings, f)
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Severity != findings[j].Severity {
+			return findings[i].Severity > findings[j].Severity
+		}
+		return findings[i].Line < findings[j].Line
+	})
+	return findings
+}
+
+// LintBody parse Ads.txt file body and check it against all linter rules (see Lint)
+func (l *Linter) LintBody(body []byte) ([]*Finding, error) {
+	records, err := adstxt.ParseBody(body)
+	if err != nil {
+		return nil, err
+	}
+	return l.Lint(records), nil
+}
+
+// Max return the highest severity of findings, or 0 if there are no findings
+func Max(findings []*Finding) Severity {
+	var max Severity
+	for _, f := range findings {
+		if f.Severity > max {
+			max = f.Severity
+		}
+	}
+	return max
+}
@@ -0,0 +1,188 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tzafrirben/go-adstxt-crawler/adstxt"
+)
+
+// TestLint test default rules findings of Ads.txt file, ranked by severity
+func TestLint(t *testing.T) {
+	body := strings.Join([]string{
+		"google.com, pub-0000000000000001, DIRECT",
+		"Google.com, pub-0000000000000002, RESELLER, f08c47fec0942fa0 ",
+		"google.com, pub-0000000000000001, direct",
+		"placeholder.example.com, placeholder, DIRECT, placeholder",
+		"google.com, pub-0000000000000003, PARTNER",
+		"contact=adops@example.com",
+	}, "\n")
+
+	findings, err := New().LintBody([]byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		rule     string
+		severity Severity
+		line     int
+	}{
+		{"placeholder-with-records", Error, 4},
+		{"unknown-relationship", Error, 5},
+		{"missing-cert-id", Warning, 1},
+		{"missing-cert-id", Warning, 3},
+		{"duplicate-record", Warning, 3},
+		{"mixed-case-domain", Info, 2},
+		{"trailing-whitespace", Info, 2},
+	}
+	if len(findings) != len(expected) {
+		t.Fatalf("Expected [%d] findings and not %v", len(expected), findings)
+	}
+	for i, e := range expected {
+		if f := findings[i]; f.Rule != e.rule || f.Severity != e.severity || f.Line != e.line {
+			t.Errorf("Expected finding [%d] to be [%s] %s in line [%d] and not [%s]", i, e.rule, e.severity, e.line, f)
+		}
+	}
+	if Max(findings) != Error {
+		t.Errorf("Expected error to be the highest severity and not [%s]", Max(findings))
+	}
+}
+
+// TestLinterConfig test linter with disabled rules and overridden severities
+func TestLinterConfig(t *testing.T) {
+	l := New()
+	l.Disable("missing-cert-id", "mixed-case-domain")
+	l.Severities["duplicate-record"] = Error
+
+	findings, err := l.LintBody([]byte("google.com, pub-0000000000000001, DIRECT\nGoogle.com, pub-0000000000000001, DIRECT"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 1 || findings[0].Rule != "duplicate-record" || findings[0].Severity != Error {
+		t.Errorf("Expected single duplicate record error and not %v", findings)
+	}
+
+	// rules are configurable
+	l = New(&MissingCertAuthorityID{AdSystems: []string{"openx.com"}})
+	if findings, _ := l.LintBody([]byte("google.com, pub-0000000000000001, DIRECT")); len(findings) != 0 {
+		t.Errorf("Expected no findings of advertising system that does not require certification authority ID and not %v", findings)
+	}
+
+	if s, err := ParseSeverity(" Warning"); err != nil || s != Warning {
+		t.Errorf("Expected [warning] severity and not [%s] [%v]", s, err)
+	}
+}
+
+// TestConflictingAccounts test seller accounts declared with conflicting relationships or certification authority IDs
+func TestConflictingAccounts(t *testing.T) {
+	body := strings.Join([]string{
+		"google.com, pub-0000000000000001, DIRECT, f08c47fec0942fa0",
+		"Google.com, pub-0000000000000001, RESELLER, f08c47fec0942fa0",
+		"google.com, pub-0000000000000002, DIRECT, f08c47fec0942fa0",
+		"google.com, pub-0000000000000002, direct, 0123456789abcdef",
+		"google.com, pub-0000000000000002, DIRECT",
+		"openx.com, pub-0000000000000001, DIRECT",
+	}, "\n")
+
+	l := New(&ConflictingRelationship{}, &ConflictingCertAuthorityID{})
+	findings, err := l.LintBody([]byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		rule     string
+		severity Severity
+		line     int
+	}{
+		{"conflicting-relationship", Error, 2},
+		{"conflicting-cert-id", Warning, 4},
+	}
+	if len(findings) != len(expected) {
+		t.Fatalf("Expected [%d] findings and not %v", len(expected), findings)
+	}
+	for i, e := range expected {
+		if f := findings[i]; f.Rule != e.rule || f.Severity != e.severity || f.Line != e.line {
+			t.Errorf("Expected finding [%d] to be [%s] %s in line [%d] and not [%s]", i, e.rule, e.severity, e.line, f)
+		}
+	}
+	if !strings.Contains(findings[0].Message, "in line 1") {
+		t.Errorf("Expected conflict to cite the first declaration and not [%s]", findings[0].Message)
+	}
+}
+
+// TestMalformedAccountID test publisher account IDs that do not have the account ID format of their advertising system
+func TestMalformedAccountID(t *testing.T) {
+	body := strings.Join([]string{
+		"google.com, pub-1234567890123456, DIRECT, f08c47fec0942fa0",
+		"google.com, 1234567890123456, DIRECT, f08c47fec0942fa0",
+		"Google.com, pub-123, RESELLER",
+		"openx.com, 537120563, RESELLER",
+		"openx.com, pub-537120563, RESELLER",
+		"sovrn.com, ab-123, DIRECT",
+	}, "\n")
+
+	formats := DefaultAccountIDFormats.Clone()
+	if err := formats.RegisterPattern("sovrn.com", `\d+`, "numeric account ID"); err != nil {
+		t.Fatal(err)
+	}
+	if err := formats.RegisterPattern("sovrn.com", `(\d+`, ""); err == nil {
+		t.Error("Expected invalid pattern error")
+	}
+
+	records, err := adstxt.ParseBody([]byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings := (&MalformedAccountID{Formats: formats}).Check(records)
+
+	expected := []int{2, 3, 5, 6}
+	if len(findings) != len(expected) {
+		t.Fatalf("Expected [%d] findings and not %v", len(expected), findings)
+	}
+	for i, line := range expected {
+		if findings[i].Line != line {
+			t.Errorf("Expected finding [%d] in line [%d] and not [%d]", i, line, findings[i].Line)
+		}
+	}
+	if !strings.Contains(findings[0].Message, "pub- followed by 16 digits") {
+		t.Errorf("Expected finding message to describe the account ID format and not [%s]", findings[0].Message)
+	}
+}
+
+// TestLinterAccountIDFormats test account ID formats registered on one linter are not checked by other linters
+func TestLinterAccountIDFormats(t *testing.T) {
+	records, err := adstxt.ParseBody([]byte("sovrn.com, ab-123, DIRECT"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, other := New(), New()
+	if err := l.AccountIDFormats().RegisterPattern("sovrn.com", `\d+`, "numeric account ID"); err != nil {
+		t.Fatal(err)
+	}
+	malformed := func(l *Linter) int {
+		n := 0
+		for _, f := range l.Lint(records) {
+			if f.Rule == "malformed-account-id" {
+				n++
+			}
+		}
+		return n
+	}
+	if n := malformed(l); n != 1 {
+		t.Errorf("Expected [1] malformed account ID finding of registered format and not [%d]", n)
+	}
+	if n := malformed(other); n != 0 {
+		t.Errorf("Expected format registered on linter to leave other linter unchanged and not [%d] findings", n)
+	}
+	if _, ok := DefaultAccountIDFormats["sovrn.com"]; ok {
+		t.Error("Expected format registered on linter to leave the default formats unchanged")
+	}
+
+	l.Disable("malformed-account-id")
+	if l.AccountIDFormats() != nil {
+		t.Error("Expected no account ID formats of linter without malformed-account-id rule")
+	}
+}
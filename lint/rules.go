@@ -0,0 +1,340 @@
+package lint
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"strings"
+
+	"github.com/tzafrirben/go-adstxt-crawler/adstxt"
+)
+
+// DefaultCertAuthorityAdSystems domains of advertising systems that are registered with TAG, and whose data records
+// are expected to declare certification authority ID (field #4)
+var DefaultCertAuthorityAdSystems = []string{
+	"google.com",
+	"appnexus.com",
+	"rubiconproject.com",
+	"pubmatic.com",
+	"openx.com",
+	"indexexchange.com",
+}
+
+// MissingCertAuthorityID rule of data records without certification authority ID, of advertising systems that
+// require it. Advertising systems are matched by their canonical domain (see adstxt.CanonicalAdSystem), so records of
+// alias domains are checked as well
+type MissingCertAuthorityID struct {
+	AdSystems []string // AdSystems domains of advertising systems that require certification authority ID
+}
+
+// Name is the Rule interface implementation for MissingCertAuthorityID
+func (r *MissingCertAuthorityID) Name() string { return "missing-cert-id" }
+
+// Severity is the Rule interface implementation for MissingCertAuthorityID
+func (r *MissingCertAuthorityID) Severity() Severity { return Warning }
+
+// Check is the Rule interface implementation for MissingCertAuthorityID
+func (r *MissingCertAuthorityID) Check(records *adstxt.Records) []*Finding {
+	required := map[string]bool{}
+	for _, d := range r.AdSystems {
+		required[adstxt.CanonicalAdSystem(d)] = true
+	}
+
+	findings := []*Finding{}
+	for _, dr := range records.DataRecords {
+		if len(strings.TrimSpace(dr.CertAuthorityID)) == 0 && required[adstxt.CanonicalAdSystem(dr.AdverterDomain)] {
+			findings = append(findings, &Finding{Line: records.Line(dr), Text: dr.Text,
+				Message: fmt.Sprintf("[%s] records should declare certification authority ID", dr.AdverterDomain)})
+		}
+	}
+	return findings
+}
+
+// DuplicateRecord rule of data records that declare the same record as previous record of the Ads.txt file (see
+// adstxt.DataRecord.Equal)
+type DuplicateRecord struct{}
+
+// Name is the Rule interface implementation for DuplicateRecord
+func (r *DuplicateRecord) Name() string { return "duplicate-record" }
+
+// Severity is the Rule interface implementation for DuplicateRecord
+func (r *DuplicateRecord) Severity() Severity { return Warning }
+
+// Check is the Rule interface implementation for DuplicateRecord
+func (r *DuplicateRecord) Check(records *adstxt.Records) []*Finding {
+	findings := []*Finding{}
+	first := map[string]int{} // line index of the first declaration of each record, by record key
+	for _, dr := range records.DataRecords {
+		key := dr.Key()
+		if line, ok := first[key]; ok {
+			findings = append(findings, &Finding{Line: records.Line(dr), Text: dr.Text,
+				Message: fmt.Sprintf("record is already declared in line %d", line)})
+			continue
+		}
+		first[key] = records.Line(dr)
+	}
+	return findings
+}
+
+// MixedCaseDomain rule of data records with advertising system domain that is not in lower case
+type MixedCaseDomain struct{}
+
+// Name is the Rule interface implementation for MixedCaseDomain
+func (r *MixedCaseDomain) Name() string { return "mixed-case-domain" }
+
+// Severity is the Rule interface implementation for MixedCaseDomain
+func (r *MixedCaseDomain) Severity() Severity { return Info }
+
+// Check is the Rule interface implementation for MixedCaseDomain
+func (r *MixedCaseDomain) Check(records *adstxt.Records) []*Finding {
+	findings := []*Finding{}
+	for _, dr := range records.DataRecords {
+		if d := strings.TrimSpace(dr.AdverterDomain); d != strings.ToLower(d) {
+			findings = append(findings, &Finding{Line: records.Line(dr), Text: dr.Text,
+				Message: fmt.Sprintf("advertising system domain [%s] should be lower case", d)})
+		}
+	}
+	return findings
+}
+
+// TrailingWhitespace rule of Ads.txt lines that end with spaces or tabs
+type TrailingWhitespace struct{}
+
+// Name is the Rule interface implementation for TrailingWhitespace
+func (r *TrailingWhitespace) Name() string { return "trailing-whitespace" }
+
+// Severity is the Rule interface implementation for TrailingWhitespace
+func (r *TrailingWhitespace) Severity() Severity { return Info }
+
+// Check is the Rule interface implementation for TrailingWhitespace
+func (r *TrailingWhitespace) Check(records *adstxt.Records) []*Finding {
+	findings := []*Finding{}
+	for i, txt := range records.Body {
+		if len(txt) > 0 && strings.TrimRight(txt, " \t") != txt {
+			findings = append(findings, &Finding{Line: i + 1, Text: txt, Message: "line has trailing whitespace"})
+		}
+	}
+	return findings
+}
+
+// PlaceholderWithRecords rule of Ads.txt file that declares the IAB placeholder record (publisher has no authorized
+// sellers) together with data records, which contradict the placeholder
+type PlaceholderWithRecords struct{}
+
+// Name is the Rule interface implementation for PlaceholderWithRecords
+func (r *PlaceholderWithRecords) Name() string { return "placeholder-with-records" }
+
+// Severity is the Rule interface implementation for PlaceholderWithRecords
+func (r *PlaceholderWithRecords) Severity() Severity { return Error }
+
+// Check is the Rule interface implementation for PlaceholderWithRecords
+func (r *PlaceholderWithRecords) Check(records *adstxt.Records) []*Finding {
+	if !records.Placeholder || len(records.DataRecords) == 0 {
+		return []*Finding{}
+	}
+
+	f := &Finding{Message: fmt.Sprintf("placeholder record is declared with [%d] data records", len(records.DataRecords))}
+	for _, l := range records.Lines() {
+		if c, ok := l.(*adstxt.Comment); ok && c.Placeholder {
+			f.Line, f.Text = c.Line, c.Text
+			break
+		}
+	}
+	return []*Finding{f}
+}
+
+// UnknownRelationship rule of data record lines with account type (field #3) that is not DIRECT or RESELLER. The
+// parser drops these lines, so buyers are likely to ignore the seller as well
+type UnknownRelationship struct{}
+
+// Name is the Rule interface implementation for UnknownRelationship
+func (r *UnknownRelationship) Name() string { return "unknown-relationship" }
+
+// Severity is the Rule interface implementation for UnknownRelationship
+func (r *UnknownRelationship) Severity() Severity { return Error }
+
+// Check is the Rule interface implementation for UnknownRelationship
+func (r *UnknownRelationship) Check(records *adstxt.Records) []*Finding {
+	findings := []*Finding{}
+	for _, l := range records.Lines() {
+		if _, ok := l.(*adstxt.Invalid); !ok {
+			continue
+		}
+
+		// data record fields, without comment and extension fields
+		line := l.Raw()
+		if i := strings.Index(line, "#"); i != -1 {
+			line = line[:i]
+		}
+		if i := strings.Index(line, ";"); i != -1 {
+			line = line[:i]
+		}
+		fields := strings.Split(line, ",")
+		if len(fields) < 3 || strings.Contains(fields[0], "=") {
+			continue
+		}
+
+		if rel := strings.TrimSpace(fields[2]); adstxt.ParseRelationship(rel) == adstxt.RelationshipUnknown {
+			findings = append(findings, &Finding{Line: l.Index(), Text: l.Raw(),
+				Message: fmt.Sprintf("[%s] is not a valid relationship, expected DIRECT or RESELLER", rel)})
+		}
+	}
+	return findings
+}
+
+// accountKey return the key of data record seller account: canonical domain of the advertising system (see
+// adstxt.CanonicalAdSystem) and the publisher account ID
+func accountKey(dr *adstxt.DataRecord) string {
+	return adstxt.CanonicalAdSystem(dr.AdverterDomain) + "," + strings.TrimSpace(dr.PublisherAccountID)
+}
+
+// ConflictingRelationship rule of seller account that is declared both as DIRECT and as RESELLER in the same Ads.txt
+// file, so buyers cannot tell how the seller is authorized. Each data record that declares the account with the other
+// relationship than its first declaration is reported
+type ConflictingRelationship struct{}
+
+// Name is the Rule interface implementation for ConflictingRelationship
+func (r *ConflictingRelationship) Name() string { return "conflicting-relationship" }
+
+// Severity is the Rule interface implementation for ConflictingRelationship
+func (r *ConflictingRelationship) Severity() Severity { return Error }
+
+// Check is the Rule interface implementation for ConflictingRelationship
+func (r *ConflictingRelationship) Check(records *adstxt.Records) []*Finding {
+	findings := []*Finding{}
+	first := map[string]*adstxt.DataRecord{} // first declaration of each seller account, by account key
+	for _, dr := range records.DataRecords {
+		rel := adstxt.ParseRelationship(string(dr.AccountType))
+		if rel == adstxt.RelationshipUnknown {
+			continue
+		}
+
+		key := accountKey(dr)
+		prev, ok := first[key]
+		if !ok {
+			first[key] = dr
+			continue
+		}
+		if prevRel := adstxt.ParseRelationship(string(prev.AccountType)); prevRel != rel {
+			findings = append(findings, &Finding{Line: records.Line(dr), Text: dr.Text,
+				Message: fmt.Sprintf("account [%s] of [%s] is declared as %s, and as %s in line %d",
+					strings.TrimSpace(dr.PublisherAccountID), dr.AdverterDomain, rel, prevRel, records.Line(prev))})
+		}
+	}
+	return findings
+}
+
+// ConflictingCertAuthorityID rule of seller account that is declared with different certification authority IDs
+// (field #4) in the same Ads.txt file, while the certification authority ID identifies the advertising system. Data
+// records without certification authority ID are not reported
+type ConflictingCertAuthorityID struct{}
+
+// Name is the Rule interface implementation for ConflictingCertAuthorityID
+func (r *ConflictingCertAuthorityID) Name() string { return "conflicting-cert-id" }
+
+// Severity is the Rule interface implementation for ConflictingCertAuthorityID
+func (r *ConflictingCertAuthorityID) Severity() Severity { return Warning }
+
+// Check is the Rule interface implementation for ConflictingCertAuthorityID
+func (r *ConflictingCertAuthorityID) Check(records *adstxt.Records) []*Finding {
+	findings := []*Finding{}
+	first := map[string]*adstxt.DataRecord{} // first declaration with certification authority ID, by account key
+	for _, dr := range records.DataRecords {
+		id := strings.ToLower(strings.TrimSpace(dr.CertAuthorityID))
+		if len(id) == 0 {
+			continue
+		}
+
+		key := accountKey(dr)
+		prev, ok := first[key]
+		if !ok {
+			first[key] = dr
+			continue
+		}
+		if prevID := strings.ToLower(strings.TrimSpace(prev.CertAuthorityID)); prevID != id {
+			findings = append(findings, &Finding{Line: records.Line(dr), Text: dr.Text,
+				Message: fmt.Sprintf("account [%s] of [%s] is declared with certification authority ID [%s], and [%s] in line %d",
+					strings.TrimSpace(dr.PublisherAccountID), dr.AdverterDomain, id, prevID, records.Line(prev))})
+		}
+	}
+	return findings
+}
+
+// AccountIDFormat publisher account ID format of advertising system (see AccountIDFormats)
+type AccountIDFormat struct {
+	Description string               // Description of the format, for lint messages (e.g. "pub- followed by 16 digits")
+	Valid       func(id string) bool // Valid check if publisher account ID has the format
+}
+
+// Pattern return account ID format of regular expression pattern, that must match the whole publisher account ID.
+// Pattern panics if the pattern is not a valid regular expression (see RegisterPattern)
+func Pattern(pattern, description string) AccountIDFormat {
+	re := regexp.MustCompile(`^(?:` + pattern + `)$`)
+	return AccountIDFormat{Description: description, Valid: re.MatchString}
+}
+
+// AccountIDFormats publisher account ID formats of advertising systems, by canonical domain of the advertising system
+// (see adstxt.CanonicalAdSystem), so records of alias domains are checked by the format of their advertising system
+type AccountIDFormats map[string]AccountIDFormat
+
+// Register set publisher account ID format of advertising system, replacing its previous format
+func (f AccountIDFormats) Register(domain string, format AccountIDFormat) {
+	f[adstxt.CanonicalAdSystem(domain)] = format
+}
+
+// RegisterPattern set publisher account ID format of advertising system by regular expression pattern, that must
+// match the whole publisher account ID (see Pattern). Return error if the pattern is not a valid regular expression
+func (f AccountIDFormats) RegisterPattern(domain, pattern, description string) error {
+	if _, err := regexp.Compile(pattern); err != nil {
+		return err
+	}
+	f.Register(domain, Pattern(pattern, description))
+	return nil
+}
+
+// Clone return copy of the account ID formats, that formats can be registered on without changing f
+func (f AccountIDFormats) Clone() AccountIDFormats {
+	return maps.Clone(f)
+}
+
+// DefaultAccountIDFormats known publisher account ID formats of major advertising systems. Default rules check copy of
+// the formats taken when the rules are created (see DefaultRules), so custom formats of single linter are registered
+// on its own formats (see Linter.AccountIDFormats)
+var DefaultAccountIDFormats = AccountIDFormats{
+	"google.com":         Pattern(`(?i)pub-\d{16}`, "pub- followed by 16 digits"),
+	"appnexus.com":       Pattern(`\d+`, "numeric member ID"),
+	"rubiconproject.com": Pattern(`\d+`, "numeric account ID"),
+	"pubmatic.com":       Pattern(`\d+`, "numeric publisher ID"),
+	"openx.com":          Pattern(`\d+`, "numeric publisher ID"),
+	"indexexchange.com":  Pattern(`\d+`, "numeric publisher ID"),
+	"triplelift.com":     Pattern(`\d+`, "numeric publisher ID"),
+	"smartadserver.com":  Pattern(`\d+`, "numeric network ID"),
+}
+
+// MalformedAccountID rule of data records whose publisher account ID does not have the account ID format of their
+// advertising system, for example Google publisher ID without the pub- prefix. Records of advertising systems without
+// registered format are not checked
+type MalformedAccountID struct {
+	Formats AccountIDFormats // Formats publisher account ID formats of advertising systems
+}
+
+// Name is the Rule interface implementation for MalformedAccountID
+func (r *MalformedAccountID) Name() string { return "malformed-account-id" }
+
+// Severity is the Rule interface implementation for MalformedAccountID
+func (r *MalformedAccountID) Severity() Severity { return Warning }
+
+// Check is the Rule interface implementation for MalformedAccountID
+func (r *MalformedAccountID) Check(records *adstxt.Records) []*Finding {
+	findings := []*Finding{}
+	for _, dr := range records.DataRecords {
+		format, ok := r.Formats[adstxt.CanonicalAdSystem(dr.AdverterDomain)]
+		id := strings.TrimSpace(dr.PublisherAccountID)
+		if !ok || len(id) == 0 || format.Valid(id) {
+			continue
+		}
+		findings = append(findings, &Finding{Line: records.Line(dr), Text: dr.Text,
+			Message: fmt.Sprintf("account [%s] of [%s] is not a valid account ID (%s)", id, dr.AdverterDomain, format.Description)})
+	}
+	return findings
+}
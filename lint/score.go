@@ -0,0 +1,137 @@
+package lint
+
+import (
+	"fmt"
+	"mime"
+	"net/url"
+	"strings"
+
+	"github.com/tzafrirben/go-adstxt-crawler/adstxt"
+)
+
+// compliance checks weights, out of 100 when all checks apply
+const (
+	weightHTTPS         = 15
+	weightContentType   = 10
+	weightNoInvalid     = 20
+	weightValidRecords  = 15
+	weightOwnerDomain   = 10
+	weightContact       = 5
+	weightNoLintErrors  = 15
+	weightNoLintWarning = 10
+)
+
+// ComplianceReport compliance score of single domain Ads.txt file, for dashboard reporting
+type ComplianceReport struct {
+	Domain string             `json:"domain,omitempty"` // Domain root domain of the Ads.txt request, empty if scored without response
+	Score  int                `json:"score"`            // Score weighted share of passed checks, from 0 to 100
+	Grade  string             `json:"grade"`            // Grade letter grade of the score: A (90 and above) to F (below 60)
+	Checks []*ComplianceCheck `json:"checks"`           // Checks all applied compliance checks, in order
+}
+
+// ComplianceCheck single compliance check of Ads.txt file
+type ComplianceCheck struct {
+	Name    string `json:"name"`              // Name of the check, in kebab case (e.g. served-over-https)
+	Weight  int    `json:"weight"`            // Weight of the check in the score
+	Passed  bool   `json:"passed"`            // Passed the Ads.txt file passed the check
+	Message string `json:"message,omitempty"` // Message reason the check failed
+}
+
+// Score Ads.txt file compliance by weighted checks: served over HTTPS with text/plain content type, no invalid lines,
+// valid records, OWNERDOMAIN and CONTACT declared, and no lint errors or warnings (see Linter). Checks of the HTTP
+// response are not applied if res is nil (for example local Ads.txt file), and the score is the weighted share of
+// passed checks out of the applied checks
+func Score(records *adstxt.Records, res *adstxt.Response) *ComplianceReport {
+	return New().Score(records, res)
+}
+
+// Score Ads.txt file compliance (see Score), using the linter rules for the lint checks
+func (l *Linter) Score(records *adstxt.Records, res *adstxt.Response) *ComplianceReport {
+	report := &ComplianceReport{Checks: []*ComplianceCheck{}}
+	check := func(name string, weight int, passed bool, msg string, args ...interface{}) {
+		c := &ComplianceCheck{Name: name, Weight: weight, Passed: passed}
+		if !passed {
+			c.Message = fmt.Sprintf(msg, args...)
+		}
+		report.Checks = append(report.Checks, c)
+	}
+
+	if res != nil {
+		if res.Request != nil {
+			report.Domain = res.Domain
+		}
+
+		u, err := url.Parse(res.FinalURL)
+		check("served-over-https", weightHTTPS, err == nil && u.Scheme == "https", "Ads.txt file was served from [%s]",
+			res.FinalURL)
+
+		contentType := res.Header.Get("Content-Type")
+		mediaType, _, _ := mime.ParseMediaType(contentType)
+		check("text-plain-content-type", weightContentType, mediaType == "text/plain",
+			"Ads.txt file was served with content type [%s]", contentType)
+	}
+
+	check("no-invalid-lines", weightNoInvalid, len(records.Warnings) == 0, "Ads.txt file has [%d] invalid lines",
+		len(records.Warnings))
+
+	invalid := 0
+	if err := records.Validate(); err != nil {
+		if errs, ok := err.(adstxt.ValidationErrors); ok {
+			invalid = len(errs)
+		}
+	}
+	check("valid-records", weightValidRecords, invalid == 0, "Ads.txt file has [%d] records validation errors", invalid)
+
+	check("ownerdomain-declared", weightOwnerDomain, len(records.OwnerDomain) > 0, "OWNERDOMAIN is not declared")
+	check("contact-declared", weightContact, len(records.Contacts) > 0, "CONTACT is not declared")
+
+	bySeverity := map[Severity]int{}
+	for _, f := range l.Lint(records) {
+		bySeverity[f.Severity]++
+	}
+	check("no-lint-errors", weightNoLintErrors, bySeverity[Error] == 0, "Ads.txt file has [%d] lint errors",
+		bySeverity[Error])
+	check("no-lint-warnings", weightNoLintWarning, bySeverity[Warning] == 0, "Ads.txt file has [%d] lint warnings",
+		bySeverity[Warning])
+
+	total, passed := 0, 0
+	for _, c := range report.Checks {
+		total += c.Weight
+		if c.Passed {
+			passed += c.Weight
+		}
+	}
+	report.Score = passed * 100 / total
+	report.Grade = grade(report.Score)
+	return report
+}
+
+// grade return the letter grade of compliance score
+func grade(score int) string {
+	switch {
+	case score >= 90:
+		return "A"
+	case score >= 80:
+		return "B"
+	case score >= 70:
+		return "C"
+	case score >= 60:
+		return "D"
+	}
+	return "F"
+}
+
+// String return the report as single line: <domain>: <grade> (<score>) followed by the failed checks
+func (r *ComplianceReport) String() string {
+	failed := []string{}
+	for _, c := range r.Checks {
+		if !c.Passed {
+			failed = append(failed, c.Name)
+		}
+	}
+	s := fmt.Sprintf("%s: %s (%d)", r.Domain, r.Grade, r.Score)
+	if len(failed) > 0 {
+		s += " failed " + strings.Join(failed, ", ")
+	}
+	return s
+}
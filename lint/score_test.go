@@ -0,0 +1,49 @@
+package lint
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/tzafrirben/go-adstxt-crawler/adstxt"
+)
+
+// TestScore test compliance score and grade of compliant and non compliant Ads.txt files
+func TestScore(t *testing.T) {
+	compliant := "OWNERDOMAIN=example.com\nCONTACT=adops@example.com\ngoogle.com, pub-0000000000000001, DIRECT, f08c47fec0942fa0"
+	res, err := adstxt.NewResponse(&adstxt.Request{URL: "https://example.com/ads.txt", Domain: "example.com"}, []byte(compliant))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Header = http.Header{"Content-Type": []string{"text/plain; charset=utf-8"}}
+
+	report := Score(res.Records, res)
+	if report.Score != 100 || report.Grade != "A" || report.Domain != "example.com" || len(report.Checks) != 8 {
+		t.Errorf("Expected compliant Ads.txt file to be graded [A] and not [%s]", report)
+	}
+
+	// served over plain HTTP as HTML, without OWNERDOMAIN and with missing certification authority ID
+	res, err = adstxt.NewResponse(&adstxt.Request{URL: "http://example.com/ads.txt", Domain: "example.com"},
+		[]byte("CONTACT=adops@example.com\ngoogle.com, pub-0000000000000001, DIRECT"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Header = http.Header{"Content-Type": []string{"text/html"}}
+
+	report = Score(res.Records, res)
+	if report.Score != 55 || report.Grade != "F" {
+		t.Errorf("Expected non compliant Ads.txt file to be graded [F] (55) and not [%s]", report)
+	}
+	for _, c := range report.Checks {
+		failed := c.Name == "served-over-https" || c.Name == "text-plain-content-type" || c.Name == "ownerdomain-declared" ||
+			c.Name == "no-lint-warnings"
+		if c.Passed == failed {
+			t.Errorf("Expected check [%s] passed to be [%t] [%s]", c.Name, !failed, c.Message)
+		}
+	}
+
+	// response checks are not applied without response
+	records, _ := adstxt.ParseBody([]byte(compliant))
+	if report = Score(records, nil); report.Score != 100 || len(report.Checks) != 6 {
+		t.Errorf("Expected only records checks without response and not [%s]", report)
+	}
+}
@@ -0,0 +1,120 @@
+package adstxt
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// domain liveness pre-check methods (see WithLivenessCheck)
+const (
+	// LivenessDNS resolve the Ads.txt URL host name (and its www subdomain when fallback is enabled)
+	LivenessDNS LivenessCheck = iota + 1
+	// LivenessHEAD send HEAD request to the root path of the Ads.txt URL host: any HTTP response means the domain
+	// is alive
+	LivenessHEAD
+)
+
+// defaultLivenessTimeout default time limit of domain liveness pre-check
+const defaultLivenessTimeout = 5 * time.Second
+
+// LivenessCheck method of domain liveness pre-check of batch crawls
+type LivenessCheck int
+
+// String return the liveness check method name
+func (l LivenessCheck) String() string {
+	switch l {
+	case LivenessDNS:
+		return "dns"
+	case LivenessHEAD:
+		return "head"
+	}
+	return "none"
+}
+
+// livenessGet return get function that checks the request domain is alive before sending the Ads.txt request, or
+// get as is if the crawler has no liveness check (or fetches Ads.txt files with custom Fetcher)
+func (c *Crawler) livenessGet(get func(context.Context, *Request) (*Response, error)) func(context.Context, *Request) (*Response, error) {
+	if c.liveness == 0 || c.fetcher != nil {
+		return get
+	}
+
+	return func(ctx context.Context, req *Request) (*Response, error) {
+		if err := c.checkLiveness(ctx, req); err != nil {
+			c.log(ctx, c.logLevels.Warning, "Ads.txt domain is not alive", "domain", req.Domain, "url", req.URL,
+				"check", c.liveness.String(), "error", err)
+			return nil, err
+		}
+		return get(ctx, req)
+	}
+}
+
+// checkLiveness run the crawler liveness check of the request domain. Return *ErrDomainDead if the check failed, or
+// the context error if ctx is done
+func (c *Crawler) checkLiveness(ctx context.Context, req *Request) error {
+	u, err := url.Parse(req.URL)
+	if err != nil || len(u.Hostname()) == 0 {
+		// invalid URL is reported by the Ads.txt request
+		return nil
+	}
+
+	timeout := c.livenessTimeout
+	if timeout <= 0 {
+		timeout = defaultLivenessTimeout
+	}
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	switch c.liveness {
+	case LivenessDNS:
+		err = c.lookupHost(checkCtx, u.Hostname())
+	case LivenessHEAD:
+		err = c.head(checkCtx, u)
+	}
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return &ErrDomainDead{Domain: req.Domain, URL: req.URL, Check: c.liveness, Err: err}
+}
+
+// lookupHost resolve host name using the crawler resolver. When the crawler tries Ads.txt URL variants (see
+// WithFallback), host that fails to be resolved is alive if its www subdomain is resolved
+func (c *Crawler) lookupHost(ctx context.Context, host string) error {
+	if net.ParseIP(host) != nil {
+		return nil
+	}
+
+	resolver := c.resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	_, err := resolver.LookupHost(ctx, host)
+	if err != nil && c.fallback && ctx.Err() == nil {
+		_, werr := resolver.LookupHost(ctx, "www."+host)
+		if werr == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// head send HEAD request to the root path of the Ads.txt URL host using the crawler HTTP client
+func (c *Crawler) head(ctx context.Context, u *url.URL) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, u.Scheme+"://"+u.Host+"/", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	return nil
+}
@@ -0,0 +1,95 @@
+package adstxt
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// TestLivenessHEAD test batch crawl HEAD liveness pre-check
+func TestLivenessHEAD(t *testing.T) {
+	var mu sync.Mutex
+	methods := []string{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		methods = append(methods, r.Method)
+		mu.Unlock()
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, "greenadexchange.com,XF7342,DIRECT")
+	}))
+	defer ts.Close()
+
+	// listener that is closed right away: connections to its address are refused
+	l, _ := net.Listen("tcp", "127.0.0.1:0")
+	dead := "http://" + l.Addr().String() + "/ads.txt"
+	l.Close()
+
+	requests := []*Request{{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"}, {URL: dead, Domain: "dead.com"}}
+	errs := map[string]error{}
+	c := NewCrawler(WithLivenessCheck(LivenessHEAD, 0))
+	c.GetMultiple(requests, HandlerFunc(func(req *Request, res *Response, err error) {
+		mu.Lock()
+		errs[req.Domain] = err
+		mu.Unlock()
+	}))
+
+	if errs["127.0.0.1"] != nil {
+		t.Errorf("Expected alive domain Ads.txt request to succeed and not [%s]", errs["127.0.0.1"])
+	}
+	var deadErr *ErrDomainDead
+	if !errors.As(errs["dead.com"], &deadErr) || deadErr.Check != LivenessHEAD || Classify(nil, errs["dead.com"]) != ErrorClassDomainDead {
+		t.Errorf("Expected ErrDomainDead error and not [%v]", errs["dead.com"])
+	}
+	if len(methods) != 2 || methods[0] != http.MethodHead || methods[1] != http.MethodGet {
+		t.Errorf("Expected HEAD request before GET request and not %v", methods)
+	}
+}
+
+// TestLivenessDNS test DNS liveness pre-check
+func TestLivenessDNS(t *testing.T) {
+	resolver := &net.Resolver{PreferGo: true, Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+		return nil, errors.New("DNS server is not available")
+	}}
+	c := NewCrawler(WithLivenessCheck(LivenessDNS, 0), WithResolver(resolver))
+
+	var deadErr *ErrDomainDead
+	if err := c.checkLiveness(context.Background(), &Request{URL: "http://example.com/ads.txt", Domain: "example.com"}); !errors.As(err, &deadErr) {
+		t.Errorf("Expected unresolved domain to be dead and not [%v]", err)
+	}
+	if err := c.checkLiveness(context.Background(), &Request{URL: "http://127.0.0.1/ads.txt", Domain: "127.0.0.1"}); err != nil {
+		t.Errorf("Expected IP address host to be alive and not [%v]", err)
+	}
+
+	// canceled request is not reported as dead domain
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := c.checkLiveness(ctx, &Request{URL: "http://example.com/ads.txt", Domain: "example.com"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context canceled error and not [%v]", err)
+	}
+}
+
+// TestLivenessDisabled test liveness pre-check is not used by default and with custom fetcher
+func TestLivenessDisabled(t *testing.T) {
+	called := false
+	get := func(ctx context.Context, req *Request) (*Response, error) {
+		called = true
+		return nil, nil
+	}
+
+	for _, c := range []*Crawler{NewCrawler(), NewCrawler(WithLivenessCheck(LivenessDNS, 0), WithFetcher(NewFSFetcher(nil)))} {
+		called = false
+		c.livenessGet(get)(context.Background(), &Request{URL: "http://example.invalid/ads.txt"})
+		if !called {
+			t.Error("Expected request to be sent without liveness check")
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package adstxt
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Logger log crawler events (see WithLogger). Logger is compatible with *slog.Logger, so any slog logger can be used
+// as is. Args are alternating key-value pairs, as in slog
+type Logger interface {
+	Log(ctx context.Context, level slog.Level, msg string, args ...any)
+}
+
+// LogLevels set the log level of each crawler event (see WithLogLevels)
+type LogLevels struct {
+	Request  slog.Level // Request level of Ads.txt request start and successful finish
+	Redirect slog.Level // Redirect level of followed HTTP redirects
+	Retry    slog.Level // Retry level of retried HTTP requests
+	Warning  slog.Level // Warning level of Ads.txt file parse warnings
+	Error    slog.Level // Error level of failed Ads.txt requests
+}
+
+// defaultLogLevels crawler events default log levels
+var defaultLogLevels = LogLevels{
+	Request:  slog.LevelDebug,
+	Redirect: slog.LevelDebug,
+	Retry:    slog.LevelInfo,
+	Warning:  slog.LevelInfo,
+	Error:    slog.LevelWarn,
+}
+
+// log crawler event, if crawler has logger
+func (c *Crawler) log(ctx context.Context, level slog.Level, msg string, args ...any) {
+	if c.logger != nil {
+		c.logger.Log(ctx, level, msg, args...)
+	}
+}
+
+// logRequest log Ads.txt request start, return function to log the request result once it is completed
+func (c *Crawler) logRequest(ctx context.Context, req *Request) func(*Response, error) {
+	if c.logger == nil {
+		return func(*Response, error) {}
+	}
+
+	// request URL is changed when following redirects: log the original URL
+	url := req.URL
+	start := time.Now()
+	c.log(ctx, c.logLevels.Request, "Ads.txt request started", "domain", req.Domain, "url", url)
+
+	return func(res *Response, err error) {
+		if err != nil {
+			c.log(ctx, c.logLevels.Error, "Ads.txt request failed", "domain", req.Domain, "url", url,
+				"duration", time.Since(start), "error", err)
+			return
+		}
+
+		for _, w := range res.Warnings {
+			c.log(ctx, c.logLevels.Warning, "Ads.txt parse warning", "domain", req.Domain, "url", res.FinalURL,
+				"line", w.Index, "text", w.Text, "message", w.Message, "severity", int(w.Level))
+		}
+		c.log(ctx, c.logLevels.Request, "Ads.txt request finished", "domain", req.Domain, "url", url,
+			"finalURL", res.FinalURL, "status", res.StatusCode, "notModified", res.NotModified,
+			"attempts", res.Attempts, "records", len(res.DataRecords), "duration", time.Since(start))
+	}
+}
@@ -0,0 +1,72 @@
+package adstxt
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// TestWithLogger test crawler log request events to slog logger
+func TestWithLogger(t *testing.T) {
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			switch req.URL.Host {
+			case "example.com":
+				return &http.Response{
+					StatusCode: http.StatusMovedPermanently,
+					Header:     http.Header{"Location": []string{"http://www.example.com/ads.txt"}},
+					Body:       io.NopCloser(strings.NewReader("")),
+					Request:    req,
+				}, nil
+			case "missing.com":
+				return &http.Response{
+					StatusCode: http.StatusNotFound,
+					Status:     "404 Not Found",
+					Body:       io.NopCloser(strings.NewReader("")),
+					Request:    req,
+				}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       io.NopCloser(strings.NewReader("greenadexchange.com,XF7342,DIRECT\ninvalid line")),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	c := NewCrawler(WithHTTPClient(client), WithLogger(logger))
+
+	req, _ := NewRequest("example.com")
+	if _, err := c.Get(req); err != nil {
+		t.Fatal(err)
+	}
+	req, _ = NewRequest("missing.com")
+	c.Get(req)
+
+	for _, msg := range []string{
+		`level=DEBUG msg="Ads.txt request started" domain=example.com`,
+		`level=DEBUG msg="Ads.txt request redirected" domain=example.com url=http://example.com/ads.txt location=http://www.example.com/ads.txt`,
+		`level=INFO msg="Ads.txt parse warning" domain=example.com url=http://www.example.com/ads.txt line=2`,
+		`level=DEBUG msg="Ads.txt request finished" domain=example.com url=http://example.com/ads.txt finalURL=http://www.example.com/ads.txt status=200`,
+		`level=WARN msg="Ads.txt request failed" domain=missing.com`,
+	} {
+		if !strings.Contains(buf.String(), msg) {
+			t.Errorf("Expected crawler log to include [%s]", msg)
+		}
+	}
+
+	// events below logger level are not logged
+	buf.Reset()
+	c = NewCrawler(WithHTTPClient(client), WithLogger(logger), WithLogLevels(LogLevels{Request: slog.LevelDebug - 4, Error: slog.LevelError}))
+	req, _ = NewRequest("missing.com")
+	c.Get(req)
+	if !strings.Contains(buf.String(), `level=ERROR msg="Ads.txt request failed"`) || strings.Contains(buf.String(), "started") {
+		t.Errorf("Expected crawler to log events at the configured levels and not [%s]", buf.String())
+	}
+}
@@ -0,0 +1,165 @@
+package adstxt
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// authorization matrix cell values (see AuthorizationMatrix)
+const (
+	// NotAuthorized seller account is not authorized by the publisher Ads.txt file
+	NotAuthorized Authorization = ""
+	// AuthorizedDirect seller account is authorized with DIRECT relationship only
+	AuthorizedDirect Authorization = accountTypeDirect
+	// AuthorizedReseller seller account is authorized with RESELLER relationship only
+	AuthorizedReseller Authorization = accountTypeReseller
+	// AuthorizedBoth seller account is authorized with both DIRECT and RESELLER relationships
+	AuthorizedBoth Authorization = "BOTH"
+	// AuthorizationUnknown publisher Ads.txt file is not available (for example the Ads.txt request failed)
+	AuthorizationUnknown Authorization = "UNKNOWN"
+)
+
+// Authorization of seller account on publisher Ads.txt file
+type Authorization string
+
+// SellerAccount seller account of advertising system, as declared by Ads.txt data record
+type SellerAccount struct {
+	AdSystem  string `json:"adSystem"`  // AdSystem advertising system domain
+	AccountID string `json:"accountId"` // AccountID seller (publisher) account ID in the advertising system
+}
+
+// AuthorizationMatrix authorization of seller accounts on publishers: which seller account is authorized by which
+// publisher Ads.txt file, and with which relationship. Use json.Marshal or WriteCSV to export the matrix
+type AuthorizationMatrix struct {
+	Publishers []string          `json:"publishers"` // Publishers domains, matrix rows
+	Sellers    []SellerAccount   `json:"sellers"`    // Sellers accounts, matrix columns
+	Cells      [][]Authorization `json:"cells"`      // Cells authorization of each seller account (column) on each publisher (row)
+}
+
+// NewAuthorizationMatrix build authorization matrix of seller accounts on publishers Ads.txt records (by publisher
+// domain, nil records for publisher whose Ads.txt file is not available). Publishers are sorted by domain, and
+// sellers are kept in order. Advertising system domain is matched case insensitive and account ID is matched exactly
+// (see Records.IsAuthorized)
+func NewAuthorizationMatrix(publishers map[string]*Records, sellers []SellerAccount) *AuthorizationMatrix {
+	m := &AuthorizationMatrix{Publishers: make([]string, 0, len(publishers)), Sellers: sellers, Cells: [][]Authorization{}}
+	for p := range publishers {
+		m.Publishers = append(m.Publishers, p)
+	}
+	sort.Strings(m.Publishers)
+
+	for _, p := range m.Publishers {
+		row := make([]Authorization, len(sellers))
+		for i, s := range sellers {
+			row[i] = authorization(publishers[p], s)
+		}
+		m.Cells = append(m.Cells, row)
+	}
+	return m
+}
+
+// Authorization return the authorization of seller account on publisher, or AuthorizationUnknown if the publisher
+// or seller account is not in the matrix
+func (m *AuthorizationMatrix) Authorization(publisher string, seller SellerAccount) Authorization {
+	row := sort.SearchStrings(m.Publishers, publisher)
+	if row == len(m.Publishers) || m.Publishers[row] != publisher {
+		return AuthorizationUnknown
+	}
+	for i, s := range m.Sellers {
+		if s.AccountID == seller.AccountID && sameDomain(s.AdSystem, seller.AdSystem) {
+			return m.Cells[row][i]
+		}
+	}
+	return AuthorizationUnknown
+}
+
+// WriteCSV write the matrix to w as CSV: header row of seller accounts (<adsystem>:<account ID>), followed by row of
+// each publisher
+func (m *AuthorizationMatrix) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	header := make([]string, 0, len(m.Sellers)+1)
+	header = append(header, "publisher")
+	for _, s := range m.Sellers {
+		header = append(header, strings.ToLower(strings.TrimSpace(s.AdSystem))+":"+s.AccountID)
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for i, p := range m.Publishers {
+		row := make([]string, 0, len(m.Sellers)+1)
+		row = append(row, p)
+		for _, a := range m.Cells[i] {
+			row = append(row, string(a))
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// authorization return the authorization of seller account on publisher Ads.txt records
+func authorization(records *Records, seller SellerAccount) Authorization {
+	if records == nil {
+		return AuthorizationUnknown
+	}
+
+	direct, _ := records.IsAuthorized(seller.AdSystem, seller.AccountID, RelationshipDirect)
+	reseller, _ := records.IsAuthorized(seller.AdSystem, seller.AccountID, RelationshipReseller)
+	switch {
+	case direct && reseller:
+		return AuthorizedBoth
+	case direct:
+		return AuthorizedDirect
+	case reseller:
+		return AuthorizedReseller
+	}
+	return NotAuthorized
+}
+
+// MatrixBuilder Handler that collects Ads.txt records of crawled publishers, to build authorization matrix of seller
+// accounts once the crawl is completed. Publishers are keyed by request root domain, and failed requests mark the
+// publisher Ads.txt file as not available. MatrixBuilder is safe to use from multiple goroutines
+type MatrixBuilder struct {
+	sellers    []SellerAccount
+	publishers map[string]*Records
+	mu         sync.Mutex
+}
+
+// NewMatrixBuilder create new MatrixBuilder of seller accounts authorization matrix
+func NewMatrixBuilder(sellers []SellerAccount) *MatrixBuilder {
+	return &MatrixBuilder{sellers: sellers, publishers: map[string]*Records{}}
+}
+
+// Handle is the Handler interface implementation for MatrixBuilder. NotModified responses are ignored, so records
+// of previous crawl are kept
+func (b *MatrixBuilder) Handle(req *Request, res *Response, err error) {
+	if err == nil && res != nil && res.NotModified {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err != nil || res == nil || res.Records == nil {
+		if _, ok := b.publishers[req.Domain]; !ok {
+			b.publishers[req.Domain] = nil
+		}
+		return
+	}
+	b.publishers[req.Domain] = res.Records
+}
+
+// Matrix build authorization matrix of the collected publishers
+func (b *MatrixBuilder) Matrix() *AuthorizationMatrix {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return NewAuthorizationMatrix(b.publishers, b.sellers)
+}
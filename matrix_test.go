@@ -0,0 +1,76 @@
+package adstxt
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// TestAuthorizationMatrix test building seller accounts authorization matrix
+func TestAuthorizationMatrix(t *testing.T) {
+	a, _ := ParseBody([]byte("greenadexchange.com,XF7342,DIRECT\ngoogle.com,pub-1,RESELLER"))
+	b, _ := ParseBody([]byte("GreenAdExchange.com,XF7342,DIRECT\ngreenadexchange.com,XF7342,RESELLER"))
+	sellers := []SellerAccount{{AdSystem: "greenadexchange.com", AccountID: "XF7342"}, {AdSystem: "google.com", AccountID: "pub-1"}}
+
+	m := NewAuthorizationMatrix(map[string]*Records{"b.com": b, "a.com": a, "c.com": nil}, sellers)
+	if len(m.Publishers) != 3 || m.Publishers[0] != "a.com" || m.Publishers[2] != "c.com" {
+		t.Fatalf("Expected publishers sorted by domain and not %v", m.Publishers)
+	}
+
+	expected := [][]Authorization{
+		{AuthorizedDirect, AuthorizedReseller},
+		{AuthorizedBoth, NotAuthorized},
+		{AuthorizationUnknown, AuthorizationUnknown},
+	}
+	for i, row := range expected {
+		for j, cell := range row {
+			if m.Cells[i][j] != cell {
+				t.Errorf("Expected publisher [%s] seller [%v] to be [%s] and not [%s]", m.Publishers[i], sellers[j], cell, m.Cells[i][j])
+			}
+		}
+	}
+
+	if a := m.Authorization("a.com", SellerAccount{AdSystem: "Google.com", AccountID: "pub-1"}); a != AuthorizedReseller {
+		t.Errorf("Expected seller to be authorized as RESELLER and not [%s]", a)
+	}
+	if a := m.Authorization("d.com", sellers[0]); a != AuthorizationUnknown {
+		t.Errorf("Expected unknown publisher authorization and not [%s]", a)
+	}
+}
+
+// TestAuthorizationMatrixExport test exporting authorization matrix to CSV and JSON
+func TestAuthorizationMatrixExport(t *testing.T) {
+	a, _ := ParseBody([]byte("greenadexchange.com,XF7342,DIRECT"))
+	m := NewAuthorizationMatrix(map[string]*Records{"a.com": a}, []SellerAccount{{AdSystem: "GreenAdExchange.com", AccountID: "XF7342"}})
+
+	var buf bytes.Buffer
+	if err := m.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	rows, _ := csv.NewReader(&buf).ReadAll()
+	if len(rows) != 2 || rows[0][1] != "greenadexchange.com:XF7342" || rows[1][0] != "a.com" || rows[1][1] != "DIRECT" {
+		t.Errorf("Unexpected authorization matrix CSV %v", rows)
+	}
+
+	b, _ := json.Marshal(m)
+	if !bytes.Contains(b, []byte(`"cells":[["DIRECT"]]`)) {
+		t.Errorf("Unexpected authorization matrix JSON [%s]", b)
+	}
+}
+
+// TestMatrixBuilder test building authorization matrix from crawl results
+func TestMatrixBuilder(t *testing.T) {
+	records, _ := ParseBody([]byte("greenadexchange.com,XF7342,DIRECT"))
+	builder := NewMatrixBuilder([]SellerAccount{{AdSystem: "greenadexchange.com", AccountID: "XF7342"}})
+
+	builder.Handle(&Request{Domain: "a.com"}, &Response{Records: records}, nil)
+	builder.Handle(&Request{Domain: "a.com"}, &Response{NotModified: true, Records: &Records{}}, nil)
+	builder.Handle(&Request{Domain: "b.com"}, nil, errors.New("connection refused"))
+
+	m := builder.Matrix()
+	if len(m.Publishers) != 2 || m.Cells[0][0] != AuthorizedDirect || m.Cells[1][0] != AuthorizationUnknown {
+		t.Errorf("Unexpected authorization matrix %v %v", m.Publishers, m.Cells)
+	}
+}
@@ -0,0 +1,99 @@
+package adstxt
+
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+// Ads.txt request status classes reported to Metrics, in addition to HTTP status classes ("2xx", "3xx", "4xx" and
+// "5xx")
+const (
+	StatusClassRedirectError = "redirect_error" // request failed while following HTTP redirects
+	StatusClassRequestError  = "request_error"  // request failed to get response from remote host
+	StatusClassDNSError      = "dns_error"      // remote host name could not be resolved
+	StatusClassTimeout       = "timeout"        // request timed out
+	StatusClassThrottled     = "throttled"      // remote host throttled the request with HTTP 429 response (see ErrThrottled)
+	StatusClassOther         = "other"          // request failed for any other reason (robots.txt, content type, etc.)
+)
+
+// Metrics collect crawler metrics (see WithMetrics). The crawler calls RequestStarted before sending each Ads.txt
+// request and RequestFinished once the request is completed (after following redirects, retries and fallback URLs).
+// Implementations must be safe to use from multiple goroutines
+type Metrics interface {
+	// RequestStarted is called before sending Ads.txt request
+	RequestStarted(req *Request)
+	// RequestFinished is called once Ads.txt request is completed with the request status class (see StatusClass),
+	// the request duration and the size of the Ads.txt file body (zero for failed requests)
+	RequestFinished(req *Request, statusClass string, duration time.Duration, bodySize int64)
+}
+
+// StatusClass return the status class of Ads.txt request result: HTTP status class ("2xx", "3xx", "4xx" or "5xx")
+// when remote host responded, StatusClassThrottled when remote host throttled the request, or one of
+// StatusClassRedirectError, StatusClassRequestError, StatusClassDNSError, StatusClassTimeout and StatusClassOther when
+// the request failed for any other reason. Failed requests status class is derived from their error class (see
+// Classify), so metrics and crawl reports agree on the failure cause
+func StatusClass(res *Response, err error) string {
+	if err == nil {
+		if res != nil && res.NotModified {
+			return "3xx"
+		}
+		return "2xx"
+	}
+	if errors.Is(err, ErrRateLimited) {
+		return StatusClassThrottled
+	}
+
+	switch Classify(res, err) {
+	case ErrorClassRedirectLoop, ErrorClassRedirectError:
+		return StatusClassRedirectError
+	case ErrorClassDNSFailure:
+		return StatusClassDNSError
+	case ErrorClassConnectTimeout, ErrorClassTimeout:
+		return StatusClassTimeout
+	case ErrorClassTLSError:
+		if errors.Is(err, ErrTimeout) {
+			return StatusClassTimeout
+		}
+		return StatusClassRequestError
+	}
+
+	// HTTP status class of remote host response is kept for status codes out of the 4xx and 5xx error classes
+	var clientErr *ErrClientError
+	var serverErr *ErrServerError
+	var requestErr *ErrRequest
+	switch {
+	case errors.As(err, &clientErr):
+		return httpStatusClass(clientErr.StatusCode)
+	case errors.As(err, &serverErr):
+		return httpStatusClass(serverErr.StatusCode)
+	case errors.As(err, &requestErr):
+		return StatusClassRequestError
+	}
+	return StatusClassOther
+}
+
+// httpStatusClass return HTTP status code class ("1xx" to "5xx")
+func httpStatusClass(code int) string {
+	if code < 100 || code > 599 {
+		return StatusClassOther
+	}
+	return strconv.Itoa(code/100) + "xx"
+}
+
+// observe report Ads.txt request to crawler metrics, return function to call once the request is completed
+func (c *Crawler) observe(req *Request) func(*Response, error) {
+	if c.metrics == nil {
+		return func(*Response, error) {}
+	}
+
+	start := time.Now()
+	c.metrics.RequestStarted(req)
+	return func(res *Response, err error) {
+		var size int64
+		if err == nil && res != nil {
+			size = res.ContentLength
+		}
+		c.metrics.RequestFinished(req, StatusClass(res, err), time.Since(start), size)
+	}
+}
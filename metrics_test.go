@@ -0,0 +1,138 @@
+package adstxt
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testMetrics Metrics implementation that records reported requests
+type testMetrics struct {
+	mu       sync.Mutex
+	inFlight int
+	classes  []string
+	sizes    []int64
+}
+
+func (m *testMetrics) RequestStarted(req *Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inFlight++
+}
+
+func (m *testMetrics) RequestFinished(req *Request, statusClass string, duration time.Duration, bodySize int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inFlight--
+	m.classes = append(m.classes, statusClass)
+	m.sizes = append(m.sizes, bodySize)
+}
+
+// TestWithMetrics test crawler report each Ads.txt request to Metrics
+func TestWithMetrics(t *testing.T) {
+	body := "greenadexchange.com,XF7342,DIRECT"
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			status := http.StatusOK
+			if req.URL.Host == "missing.com" {
+				status = http.StatusNotFound
+			}
+			return &http.Response{
+				StatusCode: status,
+				Status:     http.StatusText(status),
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       io.NopCloser(strings.NewReader(body)),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	m := &testMetrics{}
+	c := NewCrawler(WithHTTPClient(client), WithMetrics(m))
+
+	req, _ := NewRequest("example.com")
+	c.Get(req)
+	req, _ = NewRequest("missing.com")
+	c.Get(req)
+
+	if m.inFlight != 0 {
+		t.Errorf("Expected no requests in flight and not [%d]", m.inFlight)
+	}
+	if len(m.classes) != 2 || m.classes[0] != "2xx" || m.classes[1] != "4xx" {
+		t.Errorf("Expected [2xx 4xx] status classes and not %v", m.classes)
+	}
+	if m.sizes[0] != int64(len(body)) || m.sizes[1] != 0 {
+		t.Errorf("Expected body size of successful request only and not %v", m.sizes)
+	}
+}
+
+// TestStatusClass test status class of Ads.txt request results
+func TestStatusClass(t *testing.T) {
+	tests := []struct {
+		res   *Response
+		err   error
+		class string
+	}{
+		{&Response{}, nil, "2xx"},
+		{&Response{NotModified: true}, nil, "3xx"},
+		{nil, &ErrClientError{StatusCode: 404}, "4xx"},
+		{nil, &ErrServerError{StatusCode: 503}, "5xx"},
+		{nil, &ErrRedirect{Reason: ErrTooManyRedirects}, StatusClassRedirectError},
+		{nil, &ErrRequest{Err: context.DeadlineExceeded}, StatusClassTimeout},
+		{nil, &ErrRequest{Err: errors.New("connection refused")}, StatusClassRequestError},
+		{nil, &ErrRobotsDisallowed{}, StatusClassOther},
+	}
+
+	for _, test := range tests {
+		if class := StatusClass(test.res, test.err); class != test.class {
+			t.Errorf("Expected status class [%s] for [%v] and not [%s]", test.class, test.err, class)
+		}
+	}
+}
+
+// TestStatusClassClassify test status class of failed Ads.txt requests agrees with their error class
+func TestStatusClassClassify(t *testing.T) {
+	statusClasses := map[ErrorClass][]string{
+		ErrorClassDNSFailure:     {StatusClassDNSError},
+		ErrorClassConnectTimeout: {StatusClassTimeout},
+		ErrorClassTimeout:        {StatusClassTimeout},
+		ErrorClassTLSError:       {StatusClassRequestError, StatusClassTimeout},
+		ErrorClassHTTP4xx:        {"4xx", StatusClassThrottled},
+		ErrorClassHTTP5xx:        {"5xx"},
+		ErrorClassRedirectLoop:   {StatusClassRedirectError},
+		ErrorClassRedirectError:  {StatusClassRedirectError},
+		ErrorClassNotPlaintext:   {StatusClassOther},
+		ErrorClassParseError:     {StatusClassOther},
+		ErrorClassOther:          {StatusClassRequestError, StatusClassOther},
+	}
+
+	errs := []error{
+		&ErrDNS{Host: "example.com", Err: errors.New("no such host")},
+		&ErrRequest{Err: &ErrRequestTimeout{Phase: TimeoutConnect}},
+		&ErrRequest{Err: &ErrRequestTimeout{Phase: TimeoutTLSHandshake}},
+		&ErrRequest{Err: context.DeadlineExceeded},
+		&ErrRequest{Err: x509.UnknownAuthorityError{}},
+		&ErrClientError{StatusCode: 404},
+		&ErrThrottled{ErrClientError: ErrClientError{StatusCode: 429}},
+		&ErrServerError{StatusCode: 503},
+		&ErrRedirect{Reason: ErrTooManyRedirects},
+		&ErrRedirect{Reason: ErrRedirectOutOfScope},
+		&ErrInvalidContentType{ContentType: "text/html"},
+		&ParseError{},
+		&ErrRequest{Err: errors.New("connection refused")},
+		&ErrRobotsDisallowed{},
+	}
+	for _, err := range errs {
+		class, status := Classify(nil, err), StatusClass(nil, err)
+		if !slices.Contains(statusClasses[class], status) {
+			t.Errorf("Expected status class of [%v] to agree with its error class [%s] and not [%s]", err, class, status)
+		}
+	}
+}
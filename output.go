@@ -0,0 +1,159 @@
+package adstxt
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// csvResultHeader CSV batch output columns (see CSVHandler)
+var csvResultHeader = []string{"domain", "url", "status", "error", "line", "adSystem", "accountId", "relationship", "certAuthorityId", "crawledAt"}
+
+// CSVHandler Handler that writes each crawl result to CSV as it is completed, with header row first: one row per data
+// record of successful Ads.txt response (see RecordRows), and single row without record fields for failed request or
+// response without data records. Rows are flushed after each result, and write errors do not stop the crawl: use
+// Err to check whether all results were written
+type CSVHandler struct {
+	cw     *csv.Writer
+	header bool  // header row was written
+	err    error // first write error
+	mu     sync.Mutex
+}
+
+// NewCSVHandler create new CSVHandler that writes crawl results to w. Writes are serialized, so w does not have to
+// be safe to use from multiple goroutines
+func NewCSVHandler(w io.Writer) *CSVHandler {
+	return &CSVHandler{cw: csv.NewWriter(w)}
+}
+
+// Handle is the Handler interface implementation for CSVHandler
+func (h *CSVHandler) Handle(req *Request, res *Response, err error) {
+	status, url, errMsg := resultFields(req, res, err)
+
+	rows := [][]string{}
+	if err == nil && res != nil {
+		for _, r := range RecordRows(res) {
+			rows = append(rows, []string{r.Domain, r.URL, status, "", strconv.FormatInt(r.Line, 10), r.AdSystem, r.AccountID,
+				r.Relationship, r.CertAuthorityID, r.CrawledAt.Format(time.RFC3339)})
+		}
+	}
+	if len(rows) == 0 {
+		rows = append(rows, []string{req.Domain, url, status, errMsg, "", "", "", "", "", ""})
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.header {
+		h.header = true
+		h.cw.Write(csvResultHeader)
+	}
+	h.cw.WriteAll(rows)
+	if werr := h.cw.Error(); werr != nil && h.err == nil {
+		h.err = werr
+	}
+}
+
+// Err return the first write error, or nil if all results were written
+func (h *CSVHandler) Err() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.err
+}
+
+// NewJSONLHandler create new Handler that writes each crawl result to w as single JSON line (see SinkMessage and
+// WriterSink) as it is completed
+func NewJSONLHandler(w io.Writer) *SinkHandler {
+	return NewSinkHandler(NewWriterSink(w), nil)
+}
+
+// SQLiteHandler Handler that inserts each crawl result as it is completed to SQLite database table: request domain,
+// URL, status class (see StatusClass), error message, completion time and JSON encoded response (NULL for failed
+// request). SQLiteHandler uses database/sql, so the caller has to open the database with SQLite driver of its choice
+// (see SQLiteStore). Insert errors do not stop the crawl: use Err to check whether all results were inserted
+type SQLiteHandler struct {
+	db    *sql.DB
+	table string
+	err   error // first insert error
+	mu    sync.Mutex
+}
+
+// NewSQLiteHandler create new SQLiteHandler that inserts crawl results to table of db, and create the table if it
+// does not exist
+func NewSQLiteHandler(db *sql.DB, table string) (*SQLiteHandler, error) {
+	h := &SQLiteHandler{db: db, table: `"` + strings.ReplaceAll(table, `"`, `""`) + `"`}
+
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS ` + h.table + ` (
+		domain TEXT NOT NULL,
+		url TEXT NOT NULL,
+		status TEXT NOT NULL,
+		error TEXT,
+		crawled INTEGER NOT NULL,
+		response BLOB
+	)`)
+	if err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+// Handle is the Handler interface implementation for SQLiteHandler
+func (h *SQLiteHandler) Handle(req *Request, res *Response, err error) {
+	status, url, errMsg := resultFields(req, res, err)
+
+	var b []byte
+	if res != nil {
+		var merr error
+		if b, merr = json.Marshal(res); merr != nil {
+			h.setErr(merr)
+			return
+		}
+	}
+
+	var errValue interface{}
+	if err != nil {
+		errValue = errMsg
+	}
+	_, ierr := h.db.Exec(`INSERT INTO `+h.table+` (domain, url, status, error, crawled, response) VALUES (?, ?, ?, ?, ?, ?)`,
+		req.Domain, url, status, errValue, time.Now().Unix(), b)
+	if ierr != nil {
+		h.setErr(ierr)
+	}
+}
+
+// Err return the first insert error, or nil if all results were inserted
+func (h *SQLiteHandler) Err() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.err
+}
+
+// setErr keep the first insert error
+func (h *SQLiteHandler) setErr(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.err == nil {
+		h.err = err
+	}
+}
+
+// resultFields return the status class, Ads.txt URL (before redirects) and error message of crawl result
+func resultFields(req *Request, res *Response, err error) (status, url, errMsg string) {
+	status, url = StatusClass(res, err), req.URL
+	if res != nil {
+		url = responseKey(req, res)
+	}
+	if err != nil {
+		errMsg = err.Error()
+	}
+	return status, url, errMsg
+}
@@ -0,0 +1,150 @@
+package adstxt
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestCSVHandler test writing crawl results to CSV
+func TestCSVHandler(t *testing.T) {
+	req, _ := NewRequest("example.com")
+	records, _ := ParseBody([]byte("greenadexchange.com,XF7342,DIRECT\ngoogle.com,pub-1,RESELLER"))
+	failed, _ := NewRequest("missing.com")
+
+	var b bytes.Buffer
+	h := NewCSVHandler(&b)
+	h.Handle(req, &Response{Request: req, Records: records}, nil)
+	h.Handle(failed, nil, &ErrClientError{StatusCode: 404, Status: "404 Not Found", Domain: "missing.com", URL: failed.URL})
+	if err := h.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := csv.NewReader(&b).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 4 {
+		t.Fatalf("Expected header row and [3] result rows and not [%d] rows", len(rows))
+	}
+	if rows[0][0] != "domain" || rows[1][0] != "example.com" || rows[1][5] != "greenadexchange.com" || rows[2][6] != "pub-1" {
+		t.Errorf("Unexpected CSV data record rows %v", rows[:3])
+	}
+	if rows[3][0] != "missing.com" || rows[3][2] != "4xx" || len(rows[3][3]) == 0 || rows[3][5] != "" {
+		t.Errorf("Unexpected CSV failed request row %v", rows[3])
+	}
+}
+
+// TestCSVHandlerError test CSV write error is reported by Err
+func TestCSVHandlerError(t *testing.T) {
+	req, _ := NewRequest("example.com")
+	h := NewCSVHandler(&failingWriter{})
+	h.Handle(req, nil, errors.New("connection refused"))
+	if h.Err() == nil {
+		t.Error("Expected CSV write error")
+	}
+}
+
+// TestJSONLHandler test writing crawl results as JSON lines
+func TestJSONLHandler(t *testing.T) {
+	req, _ := NewRequest("example.com")
+	records, _ := ParseBody([]byte("greenadexchange.com,XF7342,DIRECT"))
+
+	var b bytes.Buffer
+	h := NewJSONLHandler(&b)
+	h.Handle(req, &Response{Request: req, Records: records}, nil)
+	h.Handle(req, nil, errors.New("connection refused"))
+
+	lines := bytes.Split(bytes.TrimSpace(b.Bytes()), []byte("\n"))
+	if len(lines) != 2 {
+		t.Fatalf("Expected [2] JSON lines and not [%d]", len(lines))
+	}
+	msg := &SinkMessage{}
+	if err := json.Unmarshal(lines[1], msg); err != nil || msg.Domain != "example.com" || msg.Error != "connection refused" {
+		t.Errorf("Unexpected JSON line [%s] [%v]", lines[1], err)
+	}
+}
+
+// TestSQLiteHandler test inserting crawl results to SQLite database table
+func TestSQLiteHandler(t *testing.T) {
+	req, _ := NewRequest("example.com")
+	records, _ := ParseBody([]byte("greenadexchange.com,XF7342,DIRECT"))
+	failed, _ := NewRequest("missing.com")
+
+	db := openTestDB(t)
+	h, err := NewSQLiteHandler(db, "results")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now().Unix()
+	h.Handle(req, &Response{Request: req, Records: records}, nil)
+	h.Handle(failed, nil, &ErrClientError{StatusCode: 404, Status: "404 Not Found", Domain: "missing.com", URL: failed.URL})
+	if err := h.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query(`SELECT domain, url, status, error, crawled, response FROM "results"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	type result struct {
+		domain, url, status string
+		errMsg              sql.NullString
+		crawled             int64
+		response            []byte
+	}
+	results := []*result{}
+	for rows.Next() {
+		r := &result{}
+		if err := rows.Scan(&r.domain, &r.url, &r.status, &r.errMsg, &r.crawled, &r.response); err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, r)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected [2] result rows and not [%d]", len(results))
+	}
+
+	ok := results[0]
+	res := &Response{}
+	if ok.domain != "example.com" || ok.url != req.URL || ok.status != "2xx" || ok.errMsg.Valid || ok.crawled < start {
+		t.Errorf("Unexpected successful request row %v", ok)
+	}
+	if err := json.Unmarshal(ok.response, res); err != nil || len(res.DataRecords) != 1 {
+		t.Errorf("Expected JSON encoded response of successful request and not [%s] (%v)", ok.response, err)
+	}
+
+	missing := results[1]
+	if missing.domain != "missing.com" || missing.status != "4xx" || !missing.errMsg.Valid || len(missing.errMsg.String) == 0 ||
+		missing.response != nil {
+		t.Errorf("Unexpected failed request row %v", missing)
+	}
+}
+
+// TestSQLiteHandlerError test SQLite insert error is reported by Err
+func TestSQLiteHandlerError(t *testing.T) {
+	h, err := NewSQLiteHandler(openTestDB(t), "results")
+	if err != nil {
+		t.Fatal(err)
+	}
+	delete(testDBs[t.Name()].tables, `"results"`)
+
+	req, _ := NewRequest("example.com")
+	h.Handle(req, nil, errors.New("connection refused"))
+	if h.Err() == nil {
+		t.Error("Expected SQLite insert error")
+	}
+}
+
+// failingWriter io.Writer mock that fails every write
+type failingWriter struct{}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
@@ -0,0 +1,373 @@
+// Package parquet Parquet file writer of Ads.txt data records rows (see adstxt.RecordRow), for loading batch crawl
+// results into columnar analytics tools (e.g. BigQuery, Spark or DuckDB) without additional dependencies. Files have
+// flat schema of the RecordRow columns, uncompressed PLAIN encoded pages, and CrawledAt column as UTC timestamp in
+// microseconds
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/tzafrirben/go-adstxt-crawler/adstxt"
+)
+
+// Parquet file magic number, at the start and the end of the file
+const magic = "PAR1"
+
+// defaultRowGroupSize default maximum number of rows in single row group
+const defaultRowGroupSize = 100000
+
+// ErrClosed rows written to closed Writer
+var ErrClosed = errors.New("parquet writer is closed")
+
+// Parquet physical types, repetition types, converted types, encodings and page type (see parquet.thrift)
+const (
+	physicalInt64     = 2
+	physicalByteArray = 6
+
+	repetitionRequired = 0
+	repetitionOptional = 1
+
+	convertedUTF8            = 0
+	convertedTimestampMicros = 10
+
+	encodingPlain = 0
+	encodingRLE   = 3
+
+	pageData = 0
+)
+
+// column single column of the RecordRow schema
+type column struct {
+	name      string
+	physical  int32
+	optional  bool // optional column: empty values are null
+	timestamp bool // INT64 column of UTC timestamp in microseconds
+	value     func(r *adstxt.RecordRow) interface{}
+}
+
+// columns RecordRow schema, in order of the RecordRow fields. Column names match the RecordRow parquet struct tags
+var columns = []column{
+	{name: "domain", physical: physicalByteArray, value: func(r *adstxt.RecordRow) interface{} { return r.Domain }},
+	{name: "url", physical: physicalByteArray, value: func(r *adstxt.RecordRow) interface{} { return r.URL }},
+	{name: "line", physical: physicalInt64, value: func(r *adstxt.RecordRow) interface{} { return r.Line }},
+	{name: "ad_system", physical: physicalByteArray, value: func(r *adstxt.RecordRow) interface{} { return r.AdSystem }},
+	{name: "account_id", physical: physicalByteArray, value: func(r *adstxt.RecordRow) interface{} { return r.AccountID }},
+	{name: "relationship", physical: physicalByteArray, value: func(r *adstxt.RecordRow) interface{} { return r.Relationship }},
+	{name: "cert_authority_id", physical: physicalByteArray, optional: true, value: func(r *adstxt.RecordRow) interface{} { return r.CertAuthorityID }},
+	{name: "crawled_at", physical: physicalInt64, timestamp: true, value: func(r *adstxt.RecordRow) interface{} { return r.CrawledAt.UnixMicro() }},
+}
+
+// chunk written column chunk of row group
+type chunk struct {
+	offset int64 // offset of the chunk data page in the file
+	size   int64 // size of the chunk (page header and page data)
+	values int64 // number of values, including nulls
+}
+
+// rowGroup written row group
+type rowGroup struct {
+	chunks []chunk
+	rows   int64
+}
+
+// Writer write Ads.txt data records rows to Parquet file. Rows are buffered, and written as row group once the row
+// group has the maximum number of rows, or when Flush is called. Close must be called to write the file footer: the
+// file is not valid Parquet file until it is closed. Writer implements adstxt.RecordRowWriter, so batch crawl results
+// are exported while crawling:
+//
+//	w := parquet.NewWriter(f)
+//	e := adstxt.NewRecordExporter(w)
+//	adstxt.NewCrawler().GetMultipleWithContext(ctx, requests, e)
+//	if err := w.Close(); err != nil || e.Err() != nil {
+//		log.Fatal(err, e.Err())
+//	}
+//
+// Writer is safe to use from multiple goroutines
+type Writer struct {
+	w            io.Writer
+	rowGroupSize int
+
+	rows   []adstxt.RecordRow // buffered rows of the next row group
+	groups []rowGroup         // written row groups
+	offset int64              // number of bytes written to w
+	closed bool
+	err    error // first write error: once w failed, the file is invalid
+	mu     sync.Mutex
+}
+
+// Option is a function that configures a Writer
+type Option func(*Writer)
+
+// WithRowGroupSize set maximum number of rows in single row group (default is 100000)
+func WithRowGroupSize(n int) Option {
+	return func(w *Writer) {
+		if n > 0 {
+			w.rowGroupSize = n
+		}
+	}
+}
+
+// NewWriter create new Writer that writes Parquet file to w
+func NewWriter(w io.Writer, opts ...Option) *Writer {
+	pw := &Writer{w: w, rowGroupSize: defaultRowGroupSize}
+	for _, opt := range opts {
+		opt(pw)
+	}
+	return pw
+}
+
+// Write is the adstxt.RecordRowWriter interface implementation for Writer: buffer rows, and write each full row group
+func (w *Writer) Write(rows []adstxt.RecordRow) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, ErrClosed
+	}
+	for i := range rows {
+		w.rows = append(w.rows, rows[i])
+		if len(w.rows) >= w.rowGroupSize {
+			if err := w.flush(); err != nil {
+				return i + 1, err
+			}
+		}
+	}
+	return len(rows), nil
+}
+
+// Flush write buffered rows as row group
+func (w *Writer) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return ErrClosed
+	}
+	return w.flush()
+}
+
+// Close write buffered rows and the file footer. Close does not close the underlying writer
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return w.err
+	}
+	if err := w.flush(); err != nil {
+		return err
+	}
+	w.closed = true
+
+	footer := w.footer()
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, uint32(len(footer)))
+	return w.write(append(append(footer, b...), magic...))
+}
+
+// Rows return the number of rows written to the file, not including buffered rows
+func (w *Writer) Rows() int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	rows := int64(0)
+	for _, g := range w.groups {
+		rows += g.rows
+	}
+	return rows
+}
+
+// write b to the underlying writer: the file magic number is written first
+func (w *Writer) write(b []byte) error {
+	if w.err != nil {
+		return w.err
+	}
+	if w.offset == 0 {
+		if _, w.err = io.WriteString(w.w, magic); w.err != nil {
+			return w.err
+		}
+		w.offset = int64(len(magic))
+	}
+	n, err := w.w.Write(b)
+	w.offset += int64(n)
+	w.err = err
+	return err
+}
+
+// flush write buffered rows as row group of single data page per column
+func (w *Writer) flush() error {
+	if len(w.rows) == 0 {
+		// file without rows has only the magic number and footer
+		return w.write(nil)
+	}
+
+	g := rowGroup{rows: int64(len(w.rows))}
+	for _, col := range columns {
+		page := encodePage(col, w.rows)
+		header := encodePageHeader(len(page), len(w.rows))
+
+		c := chunk{offset: w.offset, size: int64(len(header) + len(page)), values: int64(len(w.rows))}
+		if w.offset == 0 {
+			c.offset = int64(len(magic))
+		}
+		if err := w.write(append(header, page...)); err != nil {
+			return err
+		}
+		g.chunks = append(g.chunks, c)
+	}
+
+	w.groups = append(w.groups, g)
+	w.rows = w.rows[:0]
+	return nil
+}
+
+// encodePage return data page of column values: definition levels of optional column, and the non-null values PLAIN
+// encoded
+func encodePage(col column, rows []adstxt.RecordRow) []byte {
+	var page, values bytes.Buffer
+	levels := []bool{}
+	for i := range rows {
+		switch v := col.value(&rows[i]).(type) {
+		case string:
+			if col.optional {
+				levels = append(levels, len(v) > 0)
+				if len(v) == 0 {
+					continue
+				}
+			}
+			binary.Write(&values, binary.LittleEndian, uint32(len(v)))
+			values.WriteString(v)
+		case int64:
+			binary.Write(&values, binary.LittleEndian, v)
+		}
+	}
+
+	if col.optional {
+		rle := encodeLevels(levels)
+		binary.Write(&page, binary.LittleEndian, uint32(len(rle)))
+		page.Write(rle)
+	}
+	page.Write(values.Bytes())
+	return page.Bytes()
+}
+
+// encodeLevels return definition levels (0 null, 1 defined) in RLE/bit-packed hybrid encoding of bit width 1, as runs
+// of repeated level
+func encodeLevels(levels []bool) []byte {
+	var b bytes.Buffer
+	for i := 0; i < len(levels); {
+		j := i
+		for j < len(levels) && levels[j] == levels[i] {
+			j++
+		}
+		header := make([]byte, binary.MaxVarintLen64)
+		b.Write(header[:binary.PutUvarint(header, uint64(j-i)<<1)])
+		if levels[i] {
+			b.WriteByte(1)
+		} else {
+			b.WriteByte(0)
+		}
+		i = j
+	}
+	return b.Bytes()
+}
+
+// encodePageHeader return Thrift PageHeader of uncompressed data page
+func encodePageHeader(size, values int) []byte {
+	e := newEncoder()
+	e.i32(1, pageData)
+	e.i32(2, int32(size))
+	e.i32(3, int32(size))
+	e.beginStruct(5)
+	e.i32(1, int32(values))
+	e.i32(2, encodingPlain)
+	e.i32(3, encodingRLE)
+	e.i32(4, encodingRLE)
+	e.endStruct()
+	return e.bytes()
+}
+
+// footer return Thrift FileMetaData of the written row groups
+func (w *Writer) footer() []byte {
+	e := newEncoder()
+	e.i32(1, 1)
+
+	// schema: root element followed by the columns
+	e.list(2, typeStruct, len(columns)+1)
+	e.beginElem()
+	e.binary(4, "schema")
+	e.i32(5, int32(len(columns)))
+	e.endStruct()
+	for _, col := range columns {
+		e.beginElem()
+		e.i32(1, col.physical)
+		if col.optional {
+			e.i32(3, repetitionOptional)
+		} else {
+			e.i32(3, repetitionRequired)
+		}
+		e.binary(4, col.name)
+		if col.physical == physicalByteArray {
+			e.i32(6, convertedUTF8)
+			e.beginStruct(10)
+			e.beginStruct(1) // STRING
+			e.endStruct()
+			e.endStruct()
+		}
+		if col.timestamp {
+			e.i32(6, convertedTimestampMicros)
+			e.beginStruct(10)
+			e.beginStruct(8) // TIMESTAMP
+			e.bool(1, true)  // isAdjustedToUTC
+			e.beginStruct(2) // unit
+			e.beginStruct(2) // MICROS
+			e.endStruct()
+			e.endStruct()
+			e.endStruct()
+			e.endStruct()
+		}
+		e.endStruct()
+	}
+
+	rows := int64(0)
+	for _, g := range w.groups {
+		rows += g.rows
+	}
+	e.i64(3, rows)
+
+	e.list(4, typeStruct, len(w.groups))
+	for _, g := range w.groups {
+		e.beginElem()
+		size := int64(0)
+		e.list(1, typeStruct, len(g.chunks))
+		for i, c := range g.chunks {
+			size += c.size
+			e.beginElem()
+			e.i64(2, c.offset)
+			e.beginStruct(3)
+			e.i32(1, columns[i].physical)
+			e.list(2, typeI32, 2)
+			e.i32Elem(encodingPlain)
+			e.i32Elem(encodingRLE)
+			e.list(3, typeBinary, 1)
+			e.binaryElem(columns[i].name)
+			e.i32(4, 0) // UNCOMPRESSED
+			e.i64(5, c.values)
+			e.i64(6, c.size)
+			e.i64(7, c.size)
+			e.i64(9, c.offset)
+			e.endStruct()
+			e.endStruct()
+		}
+		e.i64(2, size)
+		e.i64(3, g.rows)
+		e.endStruct()
+	}
+
+	e.binary(6, "github.com/tzafrirben/go-adstxt-crawler")
+	return e.bytes()
+}
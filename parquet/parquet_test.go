@@ -0,0 +1,206 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/tzafrirben/go-adstxt-crawler/adstxt"
+)
+
+// decoder Thrift compact protocol decoder of Parquet metadata structs: structs are decoded as map by field ID,
+// integers as int64, binaries as string and lists as slice
+type decoder struct {
+	b   []byte
+	pos int
+}
+
+func (d *decoder) varint() uint64 {
+	v, n := binary.Uvarint(d.b[d.pos:])
+	d.pos += n
+	return v
+}
+
+func (d *decoder) int() int64 {
+	v := d.varint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (d *decoder) value(typ byte) interface{} {
+	switch typ {
+	case typeTrue:
+		return true
+	case typeFalse:
+		return false
+	case typeI32, typeI64:
+		return d.int()
+	case typeBinary:
+		n := int(d.varint())
+		d.pos += n
+		return string(d.b[d.pos-n : d.pos])
+	case typeList:
+		h := d.b[d.pos]
+		d.pos++
+		n := int(h >> 4)
+		if n == 15 {
+			n = int(d.varint())
+		}
+		list := []interface{}{}
+		for i := 0; i < n; i++ {
+			list = append(list, d.value(h&0x0f))
+		}
+		return list
+	case typeStruct:
+		s := map[int16]interface{}{}
+		id := int16(0)
+		for {
+			h := d.b[d.pos]
+			d.pos++
+			if h == 0 {
+				return s
+			}
+			if delta := int16(h >> 4); delta != 0 {
+				id += delta
+			} else {
+				id = int16(d.int())
+			}
+			s[id] = d.value(h & 0x0f)
+		}
+	}
+	panic("unsupported Thrift type")
+}
+
+// readFile decode Parquet file: return the file metadata, and values of each column (nil for null values)
+func readFile(t *testing.T, b []byte) (map[int16]interface{}, map[string][]interface{}) {
+	if string(b[:4]) != magic || string(b[len(b)-4:]) != magic {
+		t.Fatalf("Expected Parquet file magic number")
+	}
+	size := int(binary.LittleEndian.Uint32(b[len(b)-8:]))
+	d := &decoder{b: b[len(b)-8-size : len(b)-8]}
+	meta := d.value(typeStruct).(map[int16]interface{})
+	if d.pos != size {
+		t.Fatalf("Expected footer of [%d] bytes and not [%d]", size, d.pos)
+	}
+
+	schema := meta[2].([]interface{})
+	values := map[string][]interface{}{}
+	for _, g := range meta[4].([]interface{}) {
+		for i, c := range g.(map[int16]interface{})[1].([]interface{}) {
+			md := c.(map[int16]interface{})[3].(map[int16]interface{})
+			name := md[3].([]interface{})[0].(string)
+			optional := schema[i+1].(map[int16]interface{})[3].(int64) == repetitionOptional
+
+			d := &decoder{b: b, pos: int(md[9].(int64))}
+			header := d.value(typeStruct).(map[int16]interface{})
+			n := int(header[5].(map[int16]interface{})[1].(int64))
+			page := b[d.pos : d.pos+int(header[2].(int64))]
+
+			// definition levels of optional column
+			defined := []bool{}
+			if optional {
+				size := int(binary.LittleEndian.Uint32(page))
+				levels := &decoder{b: page[4 : 4+size]}
+				for levels.pos < size {
+					run := int(levels.varint() >> 1)
+					for j := 0; j < run; j++ {
+						defined = append(defined, levels.b[levels.pos] == 1)
+					}
+					levels.pos++
+				}
+				page = page[4+size:]
+			}
+
+			for j := 0; j < n; j++ {
+				if optional && !defined[j] {
+					values[name] = append(values[name], nil)
+					continue
+				}
+				if md[1].(int64) == physicalInt64 {
+					values[name] = append(values[name], int64(binary.LittleEndian.Uint64(page)))
+					page = page[8:]
+					continue
+				}
+				l := int(binary.LittleEndian.Uint32(page))
+				values[name] = append(values[name], string(page[4:4+l]))
+				page = page[4+l:]
+			}
+		}
+	}
+	return meta, values
+}
+
+// TestWriter test rows are written as Parquet file of row groups with the RecordRow schema
+func TestWriter(t *testing.T) {
+	crawledAt := time.Date(2024, time.March, 1, 12, 0, 0, 123456000, time.UTC)
+	rows := []adstxt.RecordRow{}
+	for i := 0; i < 20; i++ {
+		row := adstxt.RecordRow{Domain: "example.com", URL: "https://example.com/ads.txt", Line: int64(i + 1), AdSystem: "google.com",
+			AccountID: "pub-1", Relationship: "DIRECT", CrawledAt: crawledAt}
+		if i%3 == 0 {
+			row.CertAuthorityID = "f08c47fec0942fa0"
+		}
+		rows = append(rows, row)
+	}
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf, WithRowGroupSize(8))
+	if n, err := w.Write(rows[:10]); n != 10 || err != nil {
+		t.Fatalf("Expected [10] rows to be written and not [%d] [%v]", n, err)
+	}
+	w.Write(rows[10:])
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write(rows); err != ErrClosed || w.Rows() != 20 {
+		t.Errorf("Expected [%s] rows written to closed writer and not [%v]", ErrClosed, err)
+	}
+
+	meta, values := readFile(t, buf.Bytes())
+	if meta[3].(int64) != 20 || len(meta[4].([]interface{})) != 3 {
+		t.Errorf("Expected [20] rows in [3] row groups and not [%d] rows %v", meta[3], meta[4])
+	}
+	schema := meta[2].([]interface{})
+	if len(schema) != len(columns)+1 || schema[6].(map[int16]interface{})[4] != "relationship" {
+		t.Errorf("Expected RecordRow schema and not %v", schema)
+	}
+	for i, row := range rows {
+		var ca interface{}
+		if len(row.CertAuthorityID) > 0 {
+			ca = row.CertAuthorityID
+		}
+		if values["line"][i] != row.Line || values["ad_system"][i] != row.AdSystem || values["cert_authority_id"][i] != ca ||
+			values["crawled_at"][i] != crawledAt.UnixMicro() {
+			t.Errorf("Expected row [%d] values %+v", i, row)
+		}
+	}
+
+	// file without rows
+	buf.Reset()
+	if err := NewWriter(&buf).Close(); err != nil {
+		t.Fatal(err)
+	}
+	if meta, values = readFile(t, buf.Bytes()); meta[3].(int64) != 0 || len(values) != 0 {
+		t.Errorf("Expected Parquet file without rows")
+	}
+}
+
+// TestRecordExporter test batch crawl results are exported to Parquet file with adstxt.RecordExporter
+func TestRecordExporter(t *testing.T) {
+	records, _ := adstxt.ParseBody([]byte("google.com, pub-1, DIRECT\nopenx.com, XF7342, RESELLER"))
+	res := &adstxt.Response{Request: &adstxt.Request{URL: "https://example.com/ads.txt", Domain: "example.com"}, Records: records,
+		Fetched: time.Now()}
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	e := adstxt.NewRecordExporter(w)
+	e.Handle(res.Request, res, nil)
+	if err := w.Close(); err != nil || e.Err() != nil {
+		t.Fatal(err, e.Err())
+	}
+
+	_, values := readFile(t, buf.Bytes())
+	if len(values["domain"]) != 2 || values["account_id"][1] != "XF7342" || values["cert_authority_id"][0] != nil {
+		t.Errorf("Expected exported data records rows and not %v", values)
+	}
+}
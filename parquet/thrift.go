@@ -0,0 +1,125 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Thrift compact protocol field types (Parquet file metadata and page headers are Thrift structs)
+const (
+	typeTrue   = 1
+	typeFalse  = 2
+	typeI32    = 5
+	typeI64    = 6
+	typeBinary = 8
+	typeList   = 9
+	typeStruct = 12
+)
+
+// encoder Thrift compact protocol encoder of Parquet metadata structs
+type encoder struct {
+	buf  bytes.Buffer
+	last []int16 // last field ID of each open struct, the innermost at the end
+}
+
+// newEncoder create new encoder with open top level struct
+func newEncoder() *encoder {
+	return &encoder{last: []int16{0}}
+}
+
+// field write field header: field ID delta from the previous field of the struct, or the full field ID
+func (e *encoder) field(id int16, typ byte) {
+	last := &e.last[len(e.last)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		e.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		e.buf.WriteByte(typ)
+		e.varint(uint64(zigzag(int64(id))))
+	}
+	*last = id
+}
+
+// varint write unsigned variable length integer
+func (e *encoder) varint(v uint64) {
+	b := make([]byte, binary.MaxVarintLen64)
+	e.buf.Write(b[:binary.PutUvarint(b, v)])
+}
+
+// zigzag encode signed integer so small negative values are small varints
+func zigzag(v int64) uint64 {
+	return uint64(v<<1) ^ uint64(v>>63)
+}
+
+// i32 write i32 field
+func (e *encoder) i32(id int16, v int32) {
+	e.field(id, typeI32)
+	e.varint(zigzag(int64(v)))
+}
+
+// i64 write i64 field
+func (e *encoder) i64(id int16, v int64) {
+	e.field(id, typeI64)
+	e.varint(zigzag(v))
+}
+
+// binary write string (binary) field
+func (e *encoder) binary(id int16, v string) {
+	e.field(id, typeBinary)
+	e.varint(uint64(len(v)))
+	e.buf.WriteString(v)
+}
+
+// bool write bool field: the value is the field type
+func (e *encoder) bool(id int16, v bool) {
+	if v {
+		e.field(id, typeTrue)
+	} else {
+		e.field(id, typeFalse)
+	}
+}
+
+// list write list field header of n elements of type typ. Elements are written without field headers: structs with
+// beginElem and endStruct
+func (e *encoder) list(id int16, typ byte, n int) {
+	e.field(id, typeList)
+	if n < 15 {
+		e.buf.WriteByte(byte(n)<<4 | typ)
+		return
+	}
+	e.buf.WriteByte(0xf0 | typ)
+	e.varint(uint64(n))
+}
+
+// i32Elem write i32 list element
+func (e *encoder) i32Elem(v int32) {
+	e.varint(zigzag(int64(v)))
+}
+
+// binaryElem write string (binary) list element
+func (e *encoder) binaryElem(v string) {
+	e.varint(uint64(len(v)))
+	e.buf.WriteString(v)
+}
+
+// beginStruct open struct field
+func (e *encoder) beginStruct(id int16) {
+	e.field(id, typeStruct)
+	e.last = append(e.last, 0)
+}
+
+// beginElem open struct list element
+func (e *encoder) beginElem() {
+	e.last = append(e.last, 0)
+}
+
+// endStruct close the innermost open struct (including the top level struct)
+func (e *encoder) endStruct() {
+	e.buf.WriteByte(0)
+	e.last = e.last[:len(e.last)-1]
+}
+
+// bytes close the top level struct, and return the encoded struct
+func (e *encoder) bytes() []byte {
+	e.endStruct()
+	return e.buf.Bytes()
+}
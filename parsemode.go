@@ -0,0 +1,72 @@
+package adstxt
+
+import (
+	"fmt"
+	"io"
+)
+
+// Ads.txt parse modes
+const (
+	// ParseLenient skip malformed lines and report them as parse warnings (default)
+	ParseLenient ParseMode = iota
+	// ParseStrict reject Ads.txt file with any malformed line, and return ParseError with all malformed lines
+	ParseStrict
+)
+
+// ParseMode set how malformed Ads.txt lines are handled: skipped (ParseLenient) or rejecting the whole Ads.txt file
+// (ParseStrict). Malformed lines are lines with high severity parse warning (see HighSeverity)
+type ParseMode int
+
+// parse mode errors
+const (
+	errStrictParse = "Ads.txt file [%s] has [%d] malformed lines"
+)
+
+// ParseError Ads.txt file rejected by strict parse mode (see ParseStrict) since it has malformed lines
+type ParseError struct {
+	URL      string     // URL of the Ads.txt file, empty for local Ads.txt file
+	Warnings []*Warning // Warnings high severity parse warnings of all malformed lines, in order of the Ads.txt file
+	Records  *Records   // Records parsed from the Ads.txt file lines, including all parse warnings, for diagnostics
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf(errStrictParse, e.URL, len(e.Warnings))
+}
+
+// ParseBodyWithMode parse Ads.txt file (see ParseBody) with parse mode. In strict mode, Ads.txt file with malformed
+// lines is rejected with ParseError
+func ParseBodyWithMode(b []byte, mode ParseMode) (*Records, error) {
+	records, err := ParseBody(b)
+	if err != nil {
+		return nil, err
+	}
+	return checkParseMode(records, mode, "")
+}
+
+// ParseReaderWithMode parse Ads.txt file read from r (see ParseReader) with parse mode. In strict mode, Ads.txt file
+// with malformed lines is rejected with ParseError
+func ParseReaderWithMode(r io.Reader, mode ParseMode) (*Records, error) {
+	records, err := ParseReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return checkParseMode(records, mode, "")
+}
+
+// checkParseMode return parsed records, or ParseError if Ads.txt file has malformed lines in strict mode
+func checkParseMode(records *Records, mode ParseMode, url string) (*Records, error) {
+	if mode != ParseStrict {
+		return records, nil
+	}
+
+	malformed := []*Warning{}
+	for _, w := range records.Warnings {
+		if w.Level == HighSeverity {
+			malformed = append(malformed, w)
+		}
+	}
+	if len(malformed) > 0 {
+		return nil, &ParseError{URL: url, Warnings: malformed, Records: records}
+	}
+	return records, nil
+}
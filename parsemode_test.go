@@ -0,0 +1,81 @@
+package adstxt
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// parse mode test Ads.txt file: 2 valid data records and 2 malformed lines
+const parseModeBody = "greenadexchange.com,XF7342,DIRECT\n" +
+	"greenadexchange.com,XF7343\n" +
+	"google.com,pub-1234,RESELLER\n" +
+	"google.com,pub-1234,PARTNER\n"
+
+// TestParseModeLenient test lenient parse mode skip malformed lines and report them as parse warnings
+func TestParseModeLenient(t *testing.T) {
+	records, err := ParseBodyWithMode([]byte(parseModeBody), ParseLenient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records.DataRecords) != 2 || len(records.Warnings) != 2 {
+		t.Errorf("Expected 2 data records and 2 warnings and not [%d] and [%d]", len(records.DataRecords), len(records.Warnings))
+	}
+}
+
+// TestParseModeStrict test strict parse mode reject Ads.txt file with malformed lines and return all of them
+func TestParseModeStrict(t *testing.T) {
+	records, err := ParseReaderWithMode(strings.NewReader(parseModeBody), ParseStrict)
+	if records != nil {
+		t.Errorf("Expected no records for Ads.txt file with malformed lines")
+	}
+
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("Expected ParseError and not [%v]", err)
+	}
+	if len(parseErr.Warnings) != 2 || parseErr.Warnings[0].Index != 2 || parseErr.Warnings[1].Index != 4 {
+		t.Errorf("Expected ParseError with malformed lines [2] and [4] and not [%v]", parseErr.Warnings)
+	}
+	if parseErr.Records == nil || len(parseErr.Records.DataRecords) != 2 {
+		t.Errorf("Expected ParseError to hold the parsed records for diagnostics")
+	}
+	if err.Error() != "Ads.txt file [] has [2] malformed lines" {
+		t.Errorf("Expected ParseError message and not [%s]", err)
+	}
+
+	// low severity warnings are not malformed lines
+	records, err = ParseBodyWithMode([]byte("greenadexchange.com,XF7342,DIRECT,,extra\n"), ParseStrict)
+	if err != nil {
+		t.Errorf("Expected Ads.txt file with low severity warnings to be parsed in strict mode [%s]", err)
+	} else if len(records.DataRecords) != 1 {
+		t.Errorf("Expected 1 data record and not [%d]", len(records.DataRecords))
+	}
+}
+
+// TestWithParseMode test crawler strict parse mode
+func TestWithParseMode(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, parseModeBody)
+	}))
+	defer ts.Close()
+
+	req := &Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"}
+	res, err := NewCrawler().Get(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.DataRecords) != 2 {
+		t.Errorf("Expected lenient crawler to parse 2 data records and not [%d]", len(res.DataRecords))
+	}
+
+	_, err = NewCrawler(WithParseMode(ParseStrict)).Get(req)
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.URL != req.URL || len(parseErr.Warnings) != 2 {
+		t.Errorf("Expected strict crawler to fail with ParseError and not [%v]", err)
+	}
+}
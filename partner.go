@@ -0,0 +1,112 @@
+package adstxt
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// partnerMaxDepth maximum depth of inventory partners resolution: partners declared by partners Ads.txt files are
+// followed up to this depth, to avoid crawling long partner chains
+const partnerMaxDepth = 3
+
+// PartnerResolution Ads.txt responses of the inventory partners declared by Ads.txt file (INVENTORYPARTNERDOMAIN
+// variables), and the data records of all partners Ads.txt files merged into a single list
+type PartnerResolution struct {
+	Partners    map[string]*PartnerResponse `json:"partners"`    // Partners inventory partners Ads.txt responses by partner domain
+	DataRecords []*PartnerDataRecord        `json:"dataRecords"` // DataRecords data records of all partners Ads.txt files, annotated with the partner domain
+}
+
+// PartnerResponse Ads.txt response of single inventory partner
+type PartnerResponse struct {
+	Domain     string    `json:"domain"`               // Domain inventory partner domain, as declared by INVENTORYPARTNERDOMAIN variable
+	Request    *Request  `json:"request"`              // Request partner Ads.txt request
+	Response   *Response `json:"response,omitempty"`   // Response partner Ads.txt response, nil if request failed
+	Err        error     `json:"-"`                    // Err partner Ads.txt request error
+	DeclaredBy string    `json:"declaredBy,omitempty"` // DeclaredBy partner domain that declared this partner, empty if declared by the resolved Ads.txt file
+	Depth      int       `json:"depth"`                // Depth of the partner in the partners chain, 1 for partners declared by the resolved Ads.txt file
+}
+
+// PartnerDataRecord data record declared in inventory partner Ads.txt file
+type PartnerDataRecord struct {
+	*DataRecord
+	Partner string `json:"partner"` // Partner domain of the inventory partner whose Ads.txt file declares the record
+}
+
+// ResolvePartners crawl and parse Ads.txt files of inventory partners declared in records (see
+// Crawler.ResolvePartners)
+func ResolvePartners(ctx context.Context, records *Records) *PartnerResolution {
+	return defaultCrawler().ResolvePartners(ctx, records)
+}
+
+// ResolvePartners crawl and parse Ads.txt files of all inventory partners declared in records using
+// INVENTORYPARTNERDOMAIN variable. According to IAB Ads.txt 1.1 specification, authorization of inventory sold by
+// partners is listed in the partners Ads.txt files: data records of all partners are merged into the resolution
+// DataRecords, annotated with the partner domain. Partners declared by partners Ads.txt files are resolved as well, up
+// to 3 levels deep, and each partner is crawled once
+func (c *Crawler) ResolvePartners(ctx context.Context, records *Records) *PartnerResolution {
+	resolution := &PartnerResolution{Partners: map[string]*PartnerResponse{}, DataRecords: []*PartnerDataRecord{}}
+
+	// partners of current depth, in declaration order
+	level := resolution.declare(records, "", 1)
+	for depth := 1; len(level) > 0 && ctx.Err() == nil; depth++ {
+		requests := []*Request{}
+		for _, p := range level {
+			if p.Err == nil {
+				requests = append(requests, p.Request)
+			}
+		}
+
+		// crawl partners of the same depth in parallel
+		var mu sync.Mutex
+		c.getMultiple(ctx, requests, HandlerFunc(func(r *Request, res *Response, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			for _, p := range level {
+				if p.Request == r {
+					p.Response = res
+					p.Err = err
+				}
+			}
+		}), c.GetWithContext)
+
+		next := []*PartnerResponse{}
+		for _, p := range level {
+			if p.Response == nil {
+				continue
+			}
+			for _, r := range p.Response.DataRecords {
+				resolution.DataRecords = append(resolution.DataRecords, &PartnerDataRecord{DataRecord: r, Partner: p.Domain})
+			}
+			if depth < partnerMaxDepth {
+				next = append(next, resolution.declare(p.Response.Records, p.Domain, depth+1)...)
+			}
+		}
+		level = next
+	}
+
+	return resolution
+}
+
+// declare add inventory partners declared in records to resolution, and return the newly declared partners. Partners
+// that are already declared are ignored
+func (r *PartnerResolution) declare(records *Records, declaredBy string, depth int) []*PartnerResponse {
+	partners := []*PartnerResponse{}
+	if records == nil {
+		return partners
+	}
+
+	for _, d := range records.InventoryPartnerDomains {
+		domain := strings.ToLower(d)
+		if _, ok := r.Partners[domain]; ok {
+			continue
+		}
+
+		req, err := NewRequest(domain)
+		p := &PartnerResponse{Domain: domain, Request: req, Err: err, DeclaredBy: declaredBy, Depth: depth}
+		r.Partners[domain] = p
+		partners = append(partners, p)
+	}
+	return partners
+}
@@ -0,0 +1,88 @@
+package adstxt
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestResolvePartners test crawling Ads.txt files of inventory partners declared in Ads.txt file
+func TestResolvePartners(t *testing.T) {
+	files := map[string]string{
+		"partner.com":    "greenadexchange.com,1001,DIRECT\ninventorypartnerdomain=reseller.com\ninventorypartnerdomain=partner.com",
+		"reseller.com":   "greenadexchange.com,2002,RESELLER\ninventorypartnerdomain=level3.com",
+		"level3.com":     "greenadexchange.com,3003,DIRECT\ninventorypartnerdomain=level4.com",
+		"level4.com":     "greenadexchange.com,4004,DIRECT",
+		"no-records.com": "contact=adops@no-records.com",
+	}
+
+	var mu sync.Mutex
+	requested := map[string]int{}
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			requested[req.URL.Host]++
+			mu.Unlock()
+
+			body, ok := files[req.URL.Host]
+			if !ok {
+				return &http.Response{StatusCode: http.StatusNotFound, Status: "404 Not Found", Body: http.NoBody, Request: req}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       io.NopCloser(strings.NewReader(body)),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	records, _ := ParseBody([]byte("greenadexchange.com,XF7342,DIRECT\ninventorypartnerdomain=Partner.com\ninventorypartnerdomain=missing.com\ninventorypartnerdomain=no-records.com"))
+	resolution := NewCrawler(WithHTTPClient(client)).ResolvePartners(context.Background(), records)
+
+	if len(resolution.Partners) != 5 {
+		t.Fatalf("Expected [5] partners and not [%d]", len(resolution.Partners))
+	}
+	partner := resolution.Partners["partner.com"]
+	if partner.Err != nil || partner.Response == nil || partner.Depth != 1 || partner.DeclaredBy != "" {
+		t.Errorf("Expected partner [partner.com] Ads.txt to be parsed [%v]", partner.Err)
+	}
+	if reseller := resolution.Partners["reseller.com"]; reseller.Depth != 2 || reseller.DeclaredBy != "partner.com" {
+		t.Errorf("Expected partner [reseller.com] to be declared by [partner.com] and not [%s]", reseller.DeclaredBy)
+	}
+	if resolution.Partners["missing.com"].Err == nil {
+		t.Errorf("Expected missing partner Ads.txt to fail")
+	}
+
+	// partners are crawled once and resolved up to 3 levels deep
+	if requested["partner.com"] != 1 {
+		t.Errorf("Expected partner Ads.txt to be crawled once and not [%d] times", requested["partner.com"])
+	}
+	if _, ok := resolution.Partners["level4.com"]; ok || requested["level4.com"] != 0 {
+		t.Errorf("Expected partners to be resolved up to [%d] levels deep", partnerMaxDepth)
+	}
+
+	// partners data records are merged and annotated with the partner domain
+	expected := []struct{ partner, id string }{{"partner.com", "1001"}, {"reseller.com", "2002"}, {"level3.com", "3003"}}
+	if len(resolution.DataRecords) != len(expected) {
+		t.Fatalf("Expected [%d] partners data records and not [%d]", len(expected), len(resolution.DataRecords))
+	}
+	for i, e := range expected {
+		r := resolution.DataRecords[i]
+		if r.Partner != e.partner || r.PublisherAccountID != e.id {
+			t.Errorf("Expected data record [%s] of partner [%s] and not [%s] of [%s]", e.id, e.partner, r.PublisherAccountID, r.Partner)
+		}
+	}
+}
+
+// TestResolvePartnersEmpty test resolving Ads.txt file without inventory partners
+func TestResolvePartnersEmpty(t *testing.T) {
+	records, _ := ParseBody([]byte("greenadexchange.com,XF7342,DIRECT"))
+	resolution := ResolvePartners(context.Background(), records)
+	if len(resolution.Partners) != 0 || len(resolution.DataRecords) != 0 {
+		t.Errorf("Expected no partners to be resolved")
+	}
+}
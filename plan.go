@@ -0,0 +1,143 @@
+package adstxt
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// SkipReason reason planned Ads.txt request would not be fetched by batch crawl (see CrawlPlan)
+type SkipReason string
+
+const (
+	// SkipInvalid request URL is not a valid Ads.txt URL
+	SkipInvalid SkipReason = "invalid request"
+	// SkipDuplicate request fetch the same Ads.txt file as previous request (see WithDeduplicateRequests)
+	SkipDuplicate SkipReason = "duplicate request"
+	// SkipOtherShard request domain belongs to another shard (see ShardOf)
+	SkipOtherShard SkipReason = "other shard"
+	// SkipCached cached response of the request did not expire yet (see CachingCrawler)
+	SkipCached SkipReason = "cached response"
+	// SkipBudget request would exceed the crawler budget maximum number of requests (see WithBudget)
+	SkipBudget SkipReason = "budget exceeded"
+)
+
+// PlanOptions batch crawl settings applied by Plan, in addition to the crawler settings
+type PlanOptions struct {
+	Shard  int   // Shard index of the shard crawled by this node (0 to Shards-1)
+	Shards int   // Shards number of shards the requests are split across, no sharding if 1 or less (see ShardRequests)
+	Cache  Cache // Cache of Ads.txt responses by request URL, requests with fresh cached response are skipped (optional)
+}
+
+// PlannedRequest single Ads.txt request of CrawlPlan
+type PlannedRequest struct {
+	Request *Request   `json:"request"`          // Request Ads.txt request as passed to Plan
+	URL     string     `json:"url"`              // URL of the Ads.txt file that would be fetched, with the request path applied
+	Key     string     `json:"key,omitempty"`    // Key canonical form of the request, used to detect duplicate requests
+	Shard   int        `json:"shard"`            // Shard index of the request domain
+	Skip    SkipReason `json:"skip,omitempty"`   // Skip reason the request would not be fetched, empty if it would be fetched
+	Detail  string     `json:"detail,omitempty"` // Detail of skip reason (e.g. URL of the request it duplicates)
+}
+
+// CrawlPlan requests that batch crawl would fetch, and requests it would skip with the reason of each, for verifying
+// large crawl configurations without sending any request (see Crawler.Plan)
+type CrawlPlan struct {
+	Fetch   []*PlannedRequest `json:"fetch"`   // Fetch requests that would be fetched, in crawl order
+	Skipped []*PlannedRequest `json:"skipped"` // Skipped requests that would not be fetched, in order of requests
+}
+
+// Plan report which Ads.txt requests batch crawl of req (see GetMultipleWithContext) would fetch, and which it would
+// skip and why, without any network I/O: requests are canonicalized and deduplicated as by the crawler (see
+// WithDeduplicateRequests), split across shards and checked against the cache by opts (optional), and limited by the
+// crawler budget maximum number of requests (see WithBudget). Checks that require network access, such as robots.txt
+// rules and liveness checks, and budget limits of bytes and duration are not applied
+func (c *Crawler) Plan(req []*Request, opts *PlanOptions) *CrawlPlan {
+	if opts == nil {
+		opts = &PlanOptions{}
+	}
+
+	plan := &CrawlPlan{Fetch: []*PlannedRequest{}, Skipped: []*PlannedRequest{}}
+	skip := func(p *PlannedRequest, reason SkipReason, detail string, args ...interface{}) {
+		p.Skip, p.Detail = reason, fmt.Sprintf(detail, args...)
+		plan.Skipped = append(plan.Skipped, p)
+	}
+
+	first := map[string]*PlannedRequest{}
+	for _, r := range req {
+		p := &PlannedRequest{Request: r, URL: r.URL, Key: requestKey(r)}
+
+		domain := r.Domain
+		if len(domain) == 0 {
+			domain = r.URL
+		}
+		p.Shard = ShardOf(domain, opts.Shards)
+
+		// request path is applied to copy of the request, so the planned requests are not changed
+		planned := *r
+		if err := planned.applyPath(); err != nil {
+			skip(p, SkipInvalid, "%s", err)
+			continue
+		}
+		if err := planned.applyPort(); err != nil {
+			skip(p, SkipInvalid, "%s", err)
+			continue
+		}
+		p.URL = planned.URL
+		if u, err := url.Parse(p.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+			skip(p, SkipInvalid, "[%s] is not a valid Ads.txt URL", p.URL)
+			continue
+		}
+
+		if c.dedupe && len(p.Key) > 0 {
+			if f, ok := first[p.Key]; ok {
+				skip(p, SkipDuplicate, "duplicate of [%s]", f.URL)
+				continue
+			}
+			first[p.Key] = p
+		}
+
+		if opts.Shards > 1 && p.Shard != opts.Shard {
+			skip(p, SkipOtherShard, "domain belongs to shard [%d]", p.Shard)
+			continue
+		}
+
+		// partial response of range request is never cached (see CachingCrawler)
+		if opts.Cache != nil && r.RangeStart == 0 {
+			if cached, ok := opts.Cache.Get(r.URL); ok && c.now().Before(cached.Expires) {
+				skip(p, SkipCached, "cached response expires at [%s]", cached.Expires.Format(time.RFC3339))
+				continue
+			}
+		}
+
+		if c.budget.MaxRequests > 0 && len(plan.Fetch) >= c.budget.MaxRequests {
+			skip(p, SkipBudget, "[%d] requests limit", c.budget.MaxRequests)
+			continue
+		}
+
+		plan.Fetch = append(plan.Fetch, p)
+	}
+
+	return plan
+}
+
+// Plan report which Ads.txt requests batch crawl of req would fetch, and which it would skip and why (see
+// Crawler.Plan), skipping requests with fresh response in the crawler cache unless opts has another cache
+func (c *CachingCrawler) Plan(req []*Request, opts *PlanOptions) *CrawlPlan {
+	o := PlanOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.Cache == nil {
+		o.Cache = c.cache
+	}
+	return c.Crawler.Plan(req, &o)
+}
+
+// SkippedBy return the number of skipped requests by skip reason
+func (p *CrawlPlan) SkippedBy() map[SkipReason]int {
+	counts := map[SkipReason]int{}
+	for _, s := range p.Skipped {
+		counts[s.Skip]++
+	}
+	return counts
+}
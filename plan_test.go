@@ -0,0 +1,64 @@
+package adstxt
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestPlan test dry-run plan of batch crawl report the URLs that would be fetched, and the skipped requests with the
+// reason of each, without sending any request
+func TestPlan(t *testing.T) {
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			t.Errorf("Expected no request to be sent by dry-run plan [%s]", req.URL)
+			return nil, http.ErrNotSupported
+		}),
+	}
+
+	req := []*Request{
+		{URL: "https://example.com/ads.txt", Domain: "example.com"},
+		{URL: "http://www.example.com/ads.txt", Domain: "example.com"},
+		{URL: "ftp://test.com/ads.txt", Domain: "test.com"},
+		{URL: "https://cached.com/ads.txt", Domain: "cached.com"},
+		{URL: "https://staging.com/ads.txt", Domain: "staging.com", Path: "/staging/ads.txt"},
+		{URL: "https://other.com/ads.txt", Domain: "other.com"},
+	}
+
+	cache := NewLRUCache(10)
+	cache.Set("https://cached.com/ads.txt", &Response{Expires: time.Now().Add(time.Hour)})
+
+	c := NewCachingCrawler(NewCrawler(WithHTTPClient(client), WithBudget(Budget{MaxRequests: 2})), cache)
+	plan := c.Plan(req, nil)
+
+	if len(plan.Fetch) != 2 || plan.Fetch[0].URL != "https://example.com/ads.txt" || plan.Fetch[1].URL != "https://staging.com/staging/ads.txt" {
+		t.Errorf("Expected [2] URLs to be fetched and not %v", plan.Fetch)
+	}
+	if req[4].URL != "https://staging.com/ads.txt" {
+		t.Errorf("Expected planned request not to be changed [%s]", req[4].URL)
+	}
+
+	expected := []SkipReason{SkipDuplicate, SkipInvalid, SkipCached, SkipBudget}
+	if len(plan.Skipped) != len(expected) {
+		t.Fatalf("Expected [%d] skipped requests and not [%d]", len(expected), len(plan.Skipped))
+	}
+	for i, reason := range expected {
+		if plan.Skipped[i].Skip != reason {
+			t.Errorf("Expected request [%s] to be skipped as [%s] and not [%s]", plan.Skipped[i].URL, reason, plan.Skipped[i].Skip)
+		}
+	}
+	if plan.Skipped[0].Detail != "duplicate of [https://example.com/ads.txt]" {
+		t.Errorf("Expected duplicate request detail and not [%s]", plan.Skipped[0].Detail)
+	}
+
+	// requests of other shards are skipped
+	plan = NewCrawler().Plan(req, &PlanOptions{Shard: ShardOf("example.com", 4), Shards: 4})
+	for _, p := range plan.Fetch {
+		if p.Shard != ShardOf("example.com", 4) {
+			t.Errorf("Expected only requests of shard [%d] to be fetched and not [%s]", ShardOf("example.com", 4), p.URL)
+		}
+	}
+	if skipped := plan.SkippedBy(); len(plan.Fetch)+skipped[SkipInvalid]+skipped[SkipDuplicate]+skipped[SkipOtherShard] != len(req) {
+		t.Errorf("Expected all requests to be planned %v", skipped)
+	}
+}
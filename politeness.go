@@ -0,0 +1,53 @@
+package adstxt
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// hostDelay politeness delay keyed by host root domain: consecutive HTTP requests to the same host are sent at least
+// delay apart, regardless of the number of parallel requests
+type hostDelay struct {
+	delay time.Duration        // delay minimum time between consecutive requests to the same host
+	next  map[string]time.Time // next time a request may be sent to each host, by host root domain
+	mu    sync.Mutex
+}
+
+// newHostDelay create new per host politeness delay
+func newHostDelay(delay time.Duration) *hostDelay {
+	return &hostDelay{delay: delay, next: map[string]time.Time{}}
+}
+
+// wait block until the politeness delay since the previous request to the host has passed, or context is done
+func (d *hostDelay) wait(ctx context.Context, host string) error {
+	w := d.reserve(host, time.Now())
+	if w <= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(w)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// reserve take the next request slot of the host and return how long the caller should wait before sending the
+// request
+func (d *hostDelay) reserve(host string, now time.Time) time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	next, ok := d.next[host]
+	if !ok || next.Before(now) {
+		next = now
+	}
+	d.next[host] = next.Add(d.delay)
+
+	return next.Sub(now)
+}
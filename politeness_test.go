@@ -0,0 +1,105 @@
+package adstxt
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestHostDelay test per host politeness delay
+func TestHostDelay(t *testing.T) {
+	d := newHostDelay(time.Second)
+	now := time.Now()
+
+	if w := d.reserve("example.com", now); w != 0 {
+		t.Errorf("Expected first request to be allowed and not wait [%s]", w)
+	}
+	if w := d.reserve("example.com", now); w != time.Second {
+		t.Errorf("Expected second request to wait [1s] and not [%s]", w)
+	}
+	if w := d.reserve("example.com", now.Add(500*time.Millisecond)); w != 1500*time.Millisecond {
+		t.Errorf("Expected third request to wait [1.5s] and not [%s]", w)
+	}
+
+	// other hosts are not affected
+	if w := d.reserve("test.com", now); w != 0 {
+		t.Errorf("Expected request to other host to be allowed and not wait [%s]", w)
+	}
+
+	// delay is not accumulated while the host is idle
+	if w := d.reserve("example.com", now.Add(time.Minute)); w != 0 {
+		t.Errorf("Expected request after idle period to be allowed and not wait [%s]", w)
+	}
+}
+
+// TestHostDelayWait test waiting for politeness delay is canceled with context
+func TestHostDelayWait(t *testing.T) {
+	d := newHostDelay(time.Hour)
+	d.reserve("example.com", time.Now())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := d.wait(ctx, "example.com"); err != context.DeadlineExceeded {
+		t.Errorf("Expected wait to be canceled with context deadline and not [%v]", err)
+	}
+}
+
+// TestCrawlerHostDelay test crawler delays consecutive requests to the same host
+func TestCrawlerHostDelay(t *testing.T) {
+	var mu sync.Mutex
+	sent := map[string][]time.Time{}
+
+	client := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		sent[req.URL.Host] = append(sent[req.URL.Host], time.Now())
+		mu.Unlock()
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"text/plain"}},
+			Body:       io.NopCloser(strings.NewReader("greenadexchange.com,XF7342,DIRECT")),
+			Request:    req,
+		}, nil
+	})}
+
+	requests := []*Request{}
+	for _, domain := range []string{"example.com", "example.com", "example.com", "test.com"} {
+		req, _ := NewRequest(domain)
+		requests = append(requests, req)
+	}
+
+	delay := 50 * time.Millisecond
+	c := NewCrawler(WithHTTPClient(client), WithHostDelay(delay), WithDeduplicateRequests(false))
+	c.GetMultiple(requests, HandlerFunc(func(req *Request, res *Response, err error) {
+		if err != nil {
+			t.Error(err)
+		}
+	}))
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	times := sent["example.com"]
+	if len(times) != 3 {
+		t.Fatalf("Expected [3] requests to example.com and not [%d]", len(times))
+	}
+	first, last := times[0], times[0]
+	for _, ts := range times {
+		if ts.Before(first) {
+			first = ts
+		}
+		if ts.After(last) {
+			last = ts
+		}
+	}
+	if last.Sub(first) < 2*delay-5*time.Millisecond {
+		t.Errorf("Expected requests to example.com to be at least [%s] apart and not [%s]", delay, last.Sub(first)/2)
+	}
+	if len(sent["test.com"]) != 1 {
+		t.Errorf("Expected [1] request to test.com and not [%d]", len(sent["test.com"]))
+	}
+}
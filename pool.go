@@ -0,0 +1,50 @@
+package adstxt
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// maxPooledBufferSize buffers that grew larger than this size while reading very large Ads.txt file are not returned to
+// the pools, so the pools do not hold memory of rare large files for the crawler lifetime
+const maxPooledBufferSize = 4 << 20
+
+// bodyBuffers pool of buffers that Ads.txt response bodies are read into: bodies are read into reused buffer, and
+// copied once to body slice of their exact size, instead of allocating growing slices for each response
+var bodyBuffers = sync.Pool{New: func() interface{} { return new(bytes.Buffer) }}
+
+// lineBuffers pool of the initial line buffers of Ads.txt lines scanner (see scanLines)
+var lineBuffers = sync.Pool{New: func() interface{} {
+	b := make([]byte, 0, lineBufferSize)
+	return &b
+}}
+
+// readAll read r until EOF into pooled buffer, and return copy of the content read. size is the expected content
+// size (for example response Content-Length), or zero or negative if unknown. Content read before read error is
+// returned with the error
+func readAll(r io.Reader, size int64) ([]byte, error) {
+	buf := bodyBuffers.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer func() {
+		if buf.Cap() <= maxPooledBufferSize {
+			bodyBuffers.Put(buf)
+		}
+	}()
+
+	// buffer of known size does not grow while the body is read (ReadFrom grows buffer with less than MinRead free bytes)
+	if size > 0 && size <= maxPooledBufferSize {
+		buf.Grow(int(size) + bytes.MinRead)
+	}
+	_, err := buf.ReadFrom(r)
+
+	body := make([]byte, buf.Len())
+	copy(body, buf.Bytes())
+	return body, err
+}
+
+// getLineBuffer return pooled initial line buffer of Ads.txt lines scanner, and function that returns it to the pool
+func getLineBuffer() ([]byte, func()) {
+	b := lineBuffers.Get().(*[]byte)
+	return (*b)[:0], func() { lineBuffers.Put(b) }
+}
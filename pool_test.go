@@ -0,0 +1,109 @@
+package adstxt
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// TestReadAll test reading body into pooled buffer
+func TestReadAll(t *testing.T) {
+	body := benchmarkBody(1000)
+	for _, size := range []int64{-1, 0, int64(len(body)), 10} {
+		b, err := readAll(bytes.NewReader(body), size)
+		if err != nil || !bytes.Equal(b, body) {
+			t.Errorf("Expected body of [%d] bytes with size hint [%d] and not [%d] bytes [%v]", len(body), size, len(b), err)
+		}
+	}
+
+	// returned body does not share the pooled buffer
+	first, _ := readAll(strings.NewReader("greenadexchange.com,XF7342,DIRECT"), 0)
+	readAll(strings.NewReader("google.com,pub-1,RESELLER"), 0)
+	if string(first) != "greenadexchange.com,XF7342,DIRECT" {
+		t.Errorf("Expected body to be kept after buffer reuse and not [%s]", first)
+	}
+
+	if b, err := readAll(strings.NewReader(""), 0); err != nil || b == nil || len(b) != 0 {
+		t.Errorf("Expected empty body and not [%v] [%v]", b, err)
+	}
+
+	// content read before read error is returned with the error
+	r := io.MultiReader(strings.NewReader("google.com,pub-1,DIRECT\n"), iotest.ErrReader(errors.New("connection reset")))
+	if b, err := readAll(r, 0); err == nil || string(b) != "google.com,pub-1,DIRECT\n" {
+		t.Errorf("Expected partial body with read error and not [%s] [%v]", b, err)
+	}
+}
+
+// BenchmarkReadBody benchmark reading 2k lines Ads.txt body without pooled buffers (before) and with pooled buffer
+// sized from Content-Length (after)
+func BenchmarkReadBody(b *testing.B) {
+	body := benchmarkBody(2000)
+
+	b.Run("ReadAll", func(b *testing.B) {
+		b.SetBytes(int64(len(body)))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := io.ReadAll(bytes.NewReader(body)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("Pooled", func(b *testing.B) {
+		b.SetBytes(int64(len(body)))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := readAll(bytes.NewReader(body), int64(len(body))); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkParseBodySmall benchmark parsing 100 lines Ads.txt file, the typical size of publishers Ads.txt files
+func BenchmarkParseBodySmall(b *testing.B) {
+	body := benchmarkBody(100)
+	b.SetBytes(int64(len(body)))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseBody(body); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkGet benchmark crawling 2k lines Ads.txt file from mock transport: body read and parse
+func BenchmarkGet(b *testing.B) {
+	body := benchmarkBody(2000)
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode:    http.StatusOK,
+				Header:        http.Header{"Content-Type": []string{"text/plain"}},
+				Body:          io.NopCloser(bytes.NewReader(body)),
+				ContentLength: int64(len(body)),
+				Request:       req,
+			}, nil
+		}),
+	}
+	c := NewCrawler(WithHTTPClient(client))
+	req, _ := NewRequest("example.com")
+
+	b.SetBytes(int64(len(body)))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		r := *req
+		if _, err := c.GetWithContext(context.Background(), &r); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package adstxt
+
+import (
+	"sync"
+	"time"
+)
+
+// Progress of batch crawl (GetMultiple, GetMultipleChan, GetMultipleStream or Crawl), reported to the crawler progress
+// callback once each request result was passed to the handler (see WithProgress)
+type Progress struct {
+	Completed int           // Completed number of requests that were handled so far
+	Total     int           // Total number of requests in the batch crawl, zero if unknown (GetMultipleStream)
+	Errors    int           // Errors number of failed requests so far
+	Domain    string        // Domain root domain of the last handled request
+	Elapsed   time.Duration // Elapsed time since the batch crawl started
+}
+
+// ProgressFunc receive batch crawl progress (see WithProgress). Progress is reported in order, one call at a time
+type ProgressFunc func(p Progress)
+
+// progressHandler return handler that passes each result to h and then reports batch crawl progress, or h itself if
+// the crawler has no progress callback
+func (c *Crawler) progressHandler(h Handler, total int) Handler {
+	if c.progress == nil {
+		return h
+	}
+
+	start := time.Now()
+	var mu sync.Mutex
+	p := Progress{Total: total}
+	return HandlerFunc(func(req *Request, res *Response, err error) {
+		if h != nil {
+			h.Handle(req, res, err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		p.Completed++
+		if err != nil {
+			p.Errors++
+		}
+		p.Domain = req.Domain
+		p.Elapsed = time.Since(start)
+		c.progress(p)
+	})
+}
@@ -0,0 +1,85 @@
+package adstxt
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// TestProgress test batch crawl progress reporting
+func TestProgress(t *testing.T) {
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Host == "missing.com" {
+				return &http.Response{StatusCode: http.StatusNotFound, Status: "404 Not Found", Body: http.NoBody, Request: req}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       io.NopCloser(strings.NewReader("greenadexchange.com,XF7342,DIRECT")),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	requests := []*Request{}
+	for _, d := range []string{"example.com", "missing.com", "test.com"} {
+		req, _ := NewRequest(d)
+		requests = append(requests, req)
+	}
+
+	reports := []Progress{}
+	c := NewCrawler(WithHTTPClient(client), WithOrderedResults(true), WithProgress(func(p Progress) {
+		reports = append(reports, p)
+	}))
+
+	handled := 0
+	c.GetMultiple(requests, HandlerFunc(func(req *Request, res *Response, err error) {
+		handled++
+		if len(reports) != handled-1 {
+			t.Errorf("Expected progress to be reported once the result is handled")
+		}
+	}))
+
+	if len(reports) != 3 {
+		t.Fatalf("Expected [3] progress reports and not [%d]", len(reports))
+	}
+	for i, p := range reports {
+		if p.Completed != i+1 || p.Total != 3 || len(p.Domain) == 0 {
+			t.Errorf("Expected progress [%d] of [3] requests and not [%+v]", i+1, p)
+		}
+	}
+	if last := reports[2]; last.Errors != 1 {
+		t.Errorf("Expected [1] failed request and not [%d]", last.Errors)
+	}
+
+	// total number of requests is unknown when crawling requests stream
+	reports = nil
+	stream := make(chan *Request, len(requests))
+	for _, r := range requests {
+		stream <- r
+	}
+	close(stream)
+	c.GetMultipleStream(context.Background(), stream, HandlerFunc(func(*Request, *Response, error) {}))
+	if len(reports) != 3 || reports[2].Total != 0 || reports[2].Completed != 3 {
+		t.Errorf("Expected [3] progress reports with unknown total")
+	}
+
+	// crawl report with nil handler
+	reports = nil
+	c.Crawl(context.Background(), requests, nil)
+	if len(reports) != 3 || reports[2].Total != 3 {
+		t.Errorf("Expected Crawl progress to be reported")
+	}
+
+	// caching crawler batch crawl progress, including cached responses
+	reports = nil
+	cc := NewCachingCrawler(c, NewLRUCache(10))
+	cc.GetMultiple(requests, HandlerFunc(func(*Request, *Response, error) {}))
+	cc.GetMultiple(requests, HandlerFunc(func(*Request, *Response, error) {}))
+	if len(reports) != 6 || reports[5].Total != 3 || reports[5].Completed != 3 {
+		t.Errorf("Expected progress of caching crawler batch crawls to be reported and not [%d] reports", len(reports))
+	}
+}
@@ -0,0 +1,74 @@
+// Package prometheus Prometheus implementation of Ads.txt crawler Metrics (see adstxt.WithMetrics)
+package prometheus
+
+import (
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+
+	"github.com/tzafrirben/go-adstxt-crawler/adstxt"
+)
+
+// Metrics collect Ads.txt crawler metrics as Prometheus metrics:
+//   - <namespace>_requests_total counter of completed requests, by status class (see adstxt.StatusClass)
+//   - <namespace>_request_duration_seconds histogram of request latency, by status class
+//   - <namespace>_response_size_bytes histogram of Ads.txt file body size of successful requests
+//   - <namespace>_requests_in_flight gauge of requests in progress
+type Metrics struct {
+	requests *prom.CounterVec
+	duration *prom.HistogramVec
+	size     prom.Histogram
+	inFlight prom.Gauge
+}
+
+// NewMetrics create new Metrics with the specified metrics namespace (e.g. "adstxt") and register its metrics with
+// reg (use prometheus.DefaultRegisterer to expose the metrics with the default Prometheus HTTP handler)
+func NewMetrics(reg prom.Registerer, namespace string) (*Metrics, error) {
+	m := &Metrics{
+		requests: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: namespace,
+			Name:      "requests_total",
+			Help:      "Number of completed Ads.txt requests by status class.",
+		}, []string{"class"}),
+		duration: prom.NewHistogramVec(prom.HistogramOpts{
+			Namespace: namespace,
+			Name:      "request_duration_seconds",
+			Help:      "Ads.txt request latency in seconds by status class.",
+			Buckets:   prom.DefBuckets,
+		}, []string{"class"}),
+		size: prom.NewHistogram(prom.HistogramOpts{
+			Namespace: namespace,
+			Name:      "response_size_bytes",
+			Help:      "Ads.txt file body size in bytes of successful requests.",
+			Buckets:   prom.ExponentialBuckets(256, 4, 8),
+		}),
+		inFlight: prom.NewGauge(prom.GaugeOpts{
+			Namespace: namespace,
+			Name:      "requests_in_flight",
+			Help:      "Number of Ads.txt requests in progress.",
+		}),
+	}
+
+	for _, c := range []prom.Collector{m.requests, m.duration, m.size, m.inFlight} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// RequestStarted is the adstxt.Metrics interface implementation for Metrics
+func (m *Metrics) RequestStarted(req *adstxt.Request) {
+	m.inFlight.Inc()
+}
+
+// RequestFinished is the adstxt.Metrics interface implementation for Metrics
+func (m *Metrics) RequestFinished(req *adstxt.Request, statusClass string, duration time.Duration, bodySize int64) {
+	m.inFlight.Dec()
+	m.requests.WithLabelValues(statusClass).Inc()
+	m.duration.WithLabelValues(statusClass).Observe(duration.Seconds())
+	if statusClass == "2xx" {
+		m.size.Observe(float64(bodySize))
+	}
+}
@@ -0,0 +1,70 @@
+package prometheus
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/tzafrirben/go-adstxt-crawler/adstxt"
+)
+
+// roundTripperFunc mock HTTP transport
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// TestMetrics test crawler requests are reported as Prometheus metrics
+func TestMetrics(t *testing.T) {
+	reg := prom.NewRegistry()
+	m, err := NewMetrics(reg, "adstxt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := "google.com, pub-1234567890, DIRECT"
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			status := http.StatusOK
+			if req.URL.Host == "missing.com" {
+				status = http.StatusNotFound
+			}
+			return &http.Response{
+				StatusCode: status,
+				Status:     http.StatusText(status),
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       io.NopCloser(strings.NewReader(body)),
+				Request:    req,
+			}, nil
+		}),
+	}
+	c := adstxt.NewCrawler(adstxt.WithHTTPClient(client), adstxt.WithMetrics(m))
+
+	for _, domain := range []string{"example.com", "test.com", "missing.com"} {
+		req, _ := adstxt.NewRequest(domain)
+		c.Get(req)
+	}
+
+	if n := testutil.ToFloat64(m.requests.WithLabelValues("2xx")); n != 2 {
+		t.Errorf("Expected [2] successful requests and not [%v]", n)
+	}
+	if n := testutil.ToFloat64(m.requests.WithLabelValues("4xx")); n != 1 {
+		t.Errorf("Expected [1] failed request and not [%v]", n)
+	}
+	if n := testutil.ToFloat64(m.inFlight); n != 0 {
+		t.Errorf("Expected no requests in flight and not [%v]", n)
+	}
+	if n := testutil.CollectAndCount(m.duration); n != 2 {
+		t.Errorf("Expected request duration histogram for [2] status classes and not [%d]", n)
+	}
+
+	// registering metrics with the same namespace twice fails
+	if _, err := NewMetrics(reg, "adstxt"); err == nil {
+		t.Errorf("Expected error when registering metrics twice")
+	}
+}
@@ -0,0 +1,148 @@
+package adstxt
+
+import (
+	"context"
+	"sort"
+)
+
+// Ads.txt file source kinds of merged data records (see Provenance)
+const (
+	// SourceRoot data record declared by the root domain Ads.txt file
+	SourceRoot SourceKind = "root"
+	// SourceSubdomain data record declared by subdomain Ads.txt file (subdomain variable of the root domain)
+	SourceSubdomain SourceKind = "subdomain"
+	// SourcePartner data record declared by inventory partner Ads.txt file (INVENTORYPARTNERDOMAIN variable)
+	SourcePartner SourceKind = "partner"
+)
+
+// SourceKind how the Ads.txt file that declares merged data record is referred from the root domain
+type SourceKind string
+
+// Provenance the Ads.txt file that declares merged data record, so authorization decisions can cite the file that
+// authorized the seller
+type Provenance struct {
+	Kind SourceKind `json:"kind"` // Kind how the Ads.txt file is referred from the root domain
+	URL  string     `json:"url"`  // URL of the Ads.txt file (after redirects)
+	Path []string   `json:"path"` // Path declaration path of the Ads.txt file: domains from the root domain to the domain of the file, e.g. [example.com partner.com]
+	Line int        `json:"line"` // Line index of the data record in the Ads.txt file
+}
+
+// SourcedDataRecord data record merged from multiple Ads.txt files, with the Ads.txt file that declares it
+type SourcedDataRecord struct {
+	*DataRecord
+	Source Provenance `json:"source"`
+}
+
+// MergedRecords data records of root domain Ads.txt file, of the subdomains and of the inventory partners Ads.txt
+// files, each with its provenance (see MergeRecords)
+type MergedRecords struct {
+	DataRecords []*SourcedDataRecord `json:"dataRecords"`
+}
+
+// GetMerged crawl Ads.txt files of root domain, its subdomains and inventory partners, and merge their data records
+// (see Crawler.GetMerged)
+func GetMerged(ctx context.Context, req *Request) (*MergedRecords, error) {
+	return defaultCrawler().GetMerged(ctx, req)
+}
+
+// GetMerged crawl and parse Ads.txt file of root domain and Ads.txt files of the subdomains it declares (see
+// GetWithSubdomains), resolve the inventory partners declared by the root domain (see ResolvePartners), and merge the
+// data records of all files with their provenance. Failed subdomain and partner requests are skipped
+func (c *Crawler) GetMerged(ctx context.Context, req *Request) (*MergedRecords, error) {
+	site, err := c.GetWithSubdomains(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return MergeRecords(site, c.ResolvePartners(ctx, site.Root.Records)), nil
+}
+
+// MergeRecords merge data records of root domain Ads.txt file, the subdomains Ads.txt files (sorted by subdomain) and
+// the inventory partners Ads.txt files (in order of resolution), and tag each record with the Ads.txt file that
+// declares it. partners may be nil
+func MergeRecords(site *SiteResponse, partners *PartnerResolution) *MergedRecords {
+	m := &MergedRecords{DataRecords: []*SourcedDataRecord{}}
+	if site == nil || site.Root == nil {
+		return m
+	}
+
+	root := site.Root.Domain
+	m.add(site.Root, SourceRoot, []string{root})
+
+	subdomains := []string{}
+	for s, res := range site.Subdomains {
+		if res.Response != nil {
+			subdomains = append(subdomains, s)
+		}
+	}
+	sort.Strings(subdomains)
+	for _, s := range subdomains {
+		m.add(site.Subdomains[s].Response, SourceSubdomain, []string{root, s})
+	}
+
+	if partners == nil {
+		return m
+	}
+	for _, r := range partners.DataRecords {
+		p := partners.Partners[r.Partner]
+		if p == nil || p.Response == nil {
+			continue
+		}
+		m.DataRecords = append(m.DataRecords, &SourcedDataRecord{
+			DataRecord: r.DataRecord,
+			Source:     Provenance{Kind: SourcePartner, URL: sourceURL(p.Response), Path: partners.path(root, p), Line: p.Response.Line(r.DataRecord)},
+		})
+	}
+	return m
+}
+
+// add data records of Ads.txt response
+func (m *MergedRecords) add(res *Response, kind SourceKind, path []string) {
+	if res.Records == nil {
+		return
+	}
+	for _, d := range res.DataRecords {
+		m.DataRecords = append(m.DataRecords, &SourcedDataRecord{
+			DataRecord: d,
+			Source:     Provenance{Kind: kind, URL: sourceURL(res), Path: path, Line: res.Line(d)},
+		})
+	}
+}
+
+// IsAuthorized check if the seller account of the advertising system is authorized by any of the merged Ads.txt files
+// (see Records.IsAuthorized), and return the first data record that authorizes it, with the file that declares it
+func (m *MergedRecords) IsAuthorized(adSystemDomain, sellerAccountID string, rel Relationship) (bool, *SourcedDataRecord) {
+	for _, d := range m.DataRecords {
+		if d.PublisherAccountID != sellerAccountID || !sameAdSystem(d.AdverterDomain, adSystemDomain) {
+			continue
+		}
+		if rel == RelationshipAny || d.AccountType.canonical() == rel.canonical() {
+			return true, d
+		}
+	}
+	return false, nil
+}
+
+// path return the declaration path of inventory partner: the root domain, and the partners chain that declared it
+func (r *PartnerResolution) path(root string, p *PartnerResponse) []string {
+	chain := []string{p.Domain}
+	for declaredBy := p.DeclaredBy; len(declaredBy) > 0 && len(chain) <= partnerMaxDepth; {
+		chain = append([]string{declaredBy}, chain...)
+		parent := r.Partners[declaredBy]
+		if parent == nil {
+			break
+		}
+		declaredBy = parent.DeclaredBy
+	}
+	return append([]string{root}, chain...)
+}
+
+// sourceURL return the URL of Ads.txt response after redirects
+func sourceURL(res *Response) string {
+	if len(res.FinalURL) > 0 {
+		return res.FinalURL
+	}
+	if res.Request != nil {
+		return res.URL
+	}
+	return ""
+}
@@ -0,0 +1,79 @@
+package adstxt
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// TestGetMerged test data records of root domain, subdomains and inventory partners Ads.txt files are merged with the
+// Ads.txt file that declares each record
+func TestGetMerged(t *testing.T) {
+	files := map[string]string{
+		"example.com":     "greenadexchange.com,1001,DIRECT\nsubdomain=dev.example.com\ninventorypartnerdomain=partner.com",
+		"dev.example.com": "# dev\ngreenadexchange.com,2002,RESELLER",
+		"partner.com":     "greenadexchange.com,3003,DIRECT\ninventorypartnerdomain=reseller.com",
+		"reseller.com":    "greenadexchange.com,4004,RESELLER\ngreenadexchange.com,1001,RESELLER",
+	}
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			body, ok := files[req.URL.Host]
+			if !ok {
+				return &http.Response{StatusCode: http.StatusNotFound, Status: "404 Not Found", Body: http.NoBody, Request: req}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       io.NopCloser(strings.NewReader(body)),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	req, _ := NewRequest("http://example.com")
+	merged, err := NewCrawler(WithHTTPClient(client)).GetMerged(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		id   string
+		kind SourceKind
+		url  string
+		path string
+		line int
+	}{
+		{"1001", SourceRoot, "http://example.com/ads.txt", "example.com", 1},
+		{"2002", SourceSubdomain, "http://dev.example.com/ads.txt", "example.com dev.example.com", 2},
+		{"3003", SourcePartner, "http://partner.com/ads.txt", "example.com partner.com", 1},
+		{"4004", SourcePartner, "http://reseller.com/ads.txt", "example.com partner.com reseller.com", 1},
+		{"1001", SourcePartner, "http://reseller.com/ads.txt", "example.com partner.com reseller.com", 2},
+	}
+	if len(merged.DataRecords) != len(expected) {
+		t.Fatalf("Expected [%d] merged data records and not [%d]", len(expected), len(merged.DataRecords))
+	}
+	for i, e := range expected {
+		r := merged.DataRecords[i]
+		if r.PublisherAccountID != e.id || r.Source.Kind != e.kind || r.Source.URL != e.url || strings.Join(r.Source.Path, " ") != e.path ||
+			r.Source.Line != e.line {
+			t.Errorf("Expected data record [%s] declared by [%s] %s line [%d] and not %+v", e.id, e.url, e.path, e.line, r.Source)
+		}
+	}
+
+	// authorization cite the first Ads.txt file that authorize the seller
+	if ok, r := merged.IsAuthorized("greenadexchange.com", "1001", RelationshipReseller); !ok || r.Source.URL != "http://reseller.com/ads.txt" {
+		t.Errorf("Expected RESELLER seller to be authorized by partner Ads.txt file and not %v", r)
+	}
+	if ok, r := merged.IsAuthorized("greenadexchange.com", "1001", RelationshipAny); !ok || r.Source.Kind != SourceRoot {
+		t.Errorf("Expected seller to be authorized by root Ads.txt file and not %v", r)
+	}
+	if ok, _ := merged.IsAuthorized("greenadexchange.com", "5005", RelationshipAny); ok {
+		t.Errorf("Expected undeclared seller not to be authorized")
+	}
+
+	if m := MergeRecords(nil, nil); len(m.DataRecords) != 0 {
+		t.Errorf("Expected no merged data records of nil site")
+	}
+}
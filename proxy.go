@@ -0,0 +1,54 @@
+package adstxt
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// ProxyFunc return the proxy URL to use for Ads.txt request (see WithProxyFunc), or nil to send the request without
+// proxy. Supported proxy URL schemes are "http", "https" and "socks5". ProxyFunc is called for each HTTP request sent
+// by the crawler, including redirects, retries and robots.txt requests, so it can rotate proxies per request. It may
+// be called from multiple goroutines
+type ProxyFunc func(req *Request) *url.URL
+
+// requestContextKey context key of the Ads.txt request that HTTP request is sent for
+type requestContextKey struct{}
+
+// withRequest return copy of ctx that holds Ads.txt request
+func withRequest(ctx context.Context, req *Request) context.Context {
+	return context.WithValue(ctx, requestContextKey{}, req)
+}
+
+// requestFromContext return Ads.txt request held by ctx, or nil if ctx does not hold Ads.txt request
+func requestFromContext(ctx context.Context) *Request {
+	req, _ := ctx.Value(requestContextKey{}).(*Request)
+	return req
+}
+
+// transportProxy return HTTP transport proxy function based on crawler proxy settings
+func (c *Crawler) transportProxy() func(*http.Request) (*url.URL, error) {
+	if c.proxy == nil {
+		return nil
+	}
+
+	return func(r *http.Request) (*url.URL, error) {
+		req := requestFromContext(r.Context())
+		if req == nil {
+			req = &Request{URL: r.URL.String()}
+			req.Domain, _ = rootDomain(req.URL)
+		}
+		proxy := c.proxy(req)
+		// connection remote address is the proxy address, so it is not reported on the response (see remoteAddr)
+		if proxy != nil {
+			setProxied(r.Context())
+			// proxy connects to the remote host, so its addresses are checked before the request is sent to the proxy
+			if c.guard != nil {
+				if err := c.guard.checkHost(r.Context(), c.resolver, r.URL); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return proxy, nil
+	}
+}
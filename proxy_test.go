@@ -0,0 +1,77 @@
+package adstxt
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+)
+
+// TestWithProxyFunc test crawler send requests through the proxy selected for each request
+func TestWithProxyFunc(t *testing.T) {
+	var mu sync.Mutex
+	proxied := map[string]int{}
+
+	// proxy server: serve Ads.txt file of the requested host
+	newProxy := func(name string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			proxied[name+" "+r.URL.Host]++
+			mu.Unlock()
+			w.Header().Set("Content-Type", "text/plain")
+			fmt.Fprint(w, "greenadexchange.com,XF7342,DIRECT")
+		}))
+	}
+	p1, p2 := newProxy("p1"), newProxy("p2")
+	defer p1.Close()
+	defer p2.Close()
+
+	u1, _ := url.Parse(p1.URL)
+	u2, _ := url.Parse(p2.URL)
+
+	c := NewCrawler(WithProxyFunc(func(req *Request) *url.URL {
+		if req.Domain == "example.com" {
+			return u1
+		}
+		return u2
+	}))
+
+	for _, domain := range []string{"example.com", "test.com"} {
+		req, _ := NewRequest(domain)
+		res, err := c.Get(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res.DataRecords) != 1 {
+			t.Errorf("Expected Ads.txt file served by proxy for [%s]", domain)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if proxied["p1 example.com"] != 1 || proxied["p2 test.com"] != 1 || len(proxied) != 2 {
+		t.Errorf("Expected each request to be sent through its selected proxy and not %v", proxied)
+	}
+}
+
+// TestWithProxy test crawler send all requests through a single proxy
+func TestWithProxy(t *testing.T) {
+	var host string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host = r.URL.Host
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, "greenadexchange.com,XF7342,DIRECT")
+	}))
+	defer ts.Close()
+
+	u, _ := url.Parse(ts.URL)
+	req, _ := NewRequest("example.com")
+	if _, err := NewCrawler(WithProxy(u)).Get(req); err != nil {
+		t.Fatal(err)
+	}
+	if host != "example.com" {
+		t.Errorf("Expected request to example.com to be sent through proxy and not [%s]", host)
+	}
+}
@@ -0,0 +1,100 @@
+package adstxt
+
+import (
+	"strings"
+)
+
+// FindByAdSystem return all data records of the advertising system domain. Domains are matched case insensitive, and
+// internationalized domain matches both its Unicode and punycode forms
+func (r *Records) FindByAdSystem(domain string) []*DataRecord {
+	return r.Filter(func(d DataRecord) bool {
+		return sameDomain(d.AdverterDomain, domain)
+	})
+}
+
+// FindByAccountID return all data records of the publisher account ID (exact match), across all advertising systems
+func (r *Records) FindByAccountID(id string) []*DataRecord {
+	return r.Filter(func(d DataRecord) bool {
+		return d.PublisherAccountID == id
+	})
+}
+
+// IsPlaceholderOnly check if the Ads.txt file declares only the IAB placeholder record
+// (placeholder.example.com, placeholder, DIRECT, placeholder): the publisher has no authorized sellers, as opposed to
+// missing Ads.txt file
+func (r *Records) IsPlaceholderOnly() bool {
+	return r.Placeholder && len(r.DataRecords) == 0
+}
+
+// IsEmpty check if the Ads.txt file has no data records and no parse warnings: empty file, or file with only
+// comments or variables. Unlike IsPlaceholderOnly, the publisher did not declare explicitly that it has no authorized
+// sellers
+func (r *Records) IsEmpty() bool {
+	return len(r.DataRecords) == 0 && len(r.Warnings) == 0 && !r.Placeholder
+}
+
+// Filter return all data records for which f returns true, in the order they appear in the Ads.txt file
+func (r *Records) Filter(f func(DataRecord) bool) []*DataRecord {
+	records := []*DataRecord{}
+	for _, d := range r.DataRecords {
+		if f(*d) {
+			records = append(records, d)
+		}
+	}
+	return records
+}
+
+// HasDirectRelationship check if the Ads.txt file declares DIRECT relationship with the publisher account of the
+// advertising system
+func (r *Records) HasDirectRelationship(adsystem, account string) bool {
+	ok, _ := r.IsAuthorized(adsystem, account, RelationshipDirect)
+	return ok
+}
+
+// IsAuthorized check if the seller account of the advertising system is authorized to sell the publisher inventory
+// with the specified relationship (use RelationshipAny to accept both DIRECT and RESELLER), and return the data
+// record that authorizes it. Following IAB Ads.txt specification, advertising system domain is matched case
+// insensitive and seller account ID is matched exactly. Alias domains of the same advertising system match each other
+// (see SetAdSystemAliases)
+func (r *Records) IsAuthorized(adSystemDomain, sellerAccountID string, rel Relationship) (bool, *DataRecord) {
+	for _, d := range r.DataRecords {
+		if d.PublisherAccountID != sellerAccountID || !sameAdSystem(d.AdverterDomain, adSystemDomain) {
+			continue
+		}
+		if rel == RelationshipAny || d.AccountType.canonical() == rel.canonical() {
+			return true, d
+		}
+	}
+	return false, nil
+}
+
+// sameDomain compare domain names case insensitive, by their punycode form
+func sameDomain(a, b string) bool {
+	return asciiHost(strings.ToLower(a)) == asciiHost(strings.ToLower(b))
+}
+
+// VariableValues return the values of all variables of type name, in the order they are declared in the Ads.txt file.
+// Variable names are matched case insensitive (CONTACT, contact and Contact are the same variable), and repeated
+// declarations are all returned, as Ads.txt files commonly declare multiple CONTACT and SUBDOMAIN variables
+func (r *Records) VariableValues(name string) []string {
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	values := []string{}
+	for _, v := range r.Variables {
+		if strings.ToLower(strings.TrimSpace(v.Type)) == name {
+			values = append(values, v.Value)
+		}
+	}
+	return values
+}
+
+// VariableMap return the values of all variables by lower case variable type, in the order they are declared in the
+// Ads.txt file (see VariableValues)
+func (r *Records) VariableMap() map[string][]string {
+	m := map[string][]string{}
+	for _, v := range r.Variables {
+		t := strings.ToLower(strings.TrimSpace(v.Type))
+		m[t] = append(m[t], v.Value)
+	}
+	return m
+}
@@ -0,0 +1,140 @@
+package adstxt
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestRecordsQuery test looking up data records by advertising system and publisher account
+func TestRecordsQuery(t *testing.T) {
+	records, _ := ParseBody([]byte(`greenadexchange.com, XF7342, DIRECT
+greenadexchange.com, 185, RESELLER
+testexchange.net, XF7342, RESELLER
+bücherexchange.com, 1001, DIRECT`))
+
+	if n := len(records.FindByAdSystem("GreenAdExchange.com")); n != 2 {
+		t.Errorf("Expected [2] records of greenadexchange.com and not [%d]", n)
+	}
+	if n := len(records.FindByAdSystem("xn--bcherexchange-wob.com")); n != 1 {
+		t.Errorf("Expected internationalized ad system to match its punycode form")
+	}
+	if n := len(records.FindByAdSystem("other.com")); n != 0 {
+		t.Errorf("Expected no records of other.com and not [%d]", n)
+	}
+
+	if d := records.FindByAccountID("XF7342"); len(d) != 2 || d[1].AdverterDomain != "testexchange.net" {
+		t.Errorf("Expected [2] records of account XF7342 in Ads.txt file order and not %v", d)
+	}
+	if n := len(records.FindByAccountID("xf7342")); n != 0 {
+		t.Errorf("Expected account ID to be matched exactly")
+	}
+
+	resellers := records.Filter(func(d DataRecord) bool { return d.AccountType == "RESELLER" })
+	if len(resellers) != 2 {
+		t.Errorf("Expected [2] RESELLER records and not [%d]", len(resellers))
+	}
+
+	if !records.HasDirectRelationship("greenadexchange.com", "XF7342") {
+		t.Errorf("Expected DIRECT relationship with greenadexchange.com account XF7342")
+	}
+	if records.HasDirectRelationship("greenadexchange.com", "185") || records.HasDirectRelationship("testexchange.net", "XF7342") {
+		t.Errorf("Expected no DIRECT relationship for RESELLER records")
+	}
+}
+
+// TestRecordsIsAuthorized test seller authorization check against Ads.txt data records
+func TestRecordsIsAuthorized(t *testing.T) {
+	records, _ := ParseBody([]byte(`greenadexchange.com, XF7342, DIRECT
+greenadexchange.com, 185, RESELLER, d75815a79`))
+
+	tests := []struct {
+		domain     string
+		account    string
+		rel        Relationship
+		authorized bool
+	}{
+		{"greenadexchange.com", "XF7342", RelationshipDirect, true},
+		{"GREENADEXCHANGE.COM", "XF7342", RelationshipDirect, true},
+		{"greenadexchange.com", "XF7342", RelationshipReseller, false},
+		{"greenadexchange.com", "185", RelationshipReseller, true},
+		{"greenadexchange.com", "185", RelationshipAny, true},
+		{"greenadexchange.com", "xf7342", RelationshipAny, false},
+		{"testexchange.net", "XF7342", RelationshipAny, false},
+	}
+
+	for _, test := range tests {
+		ok, d := records.IsAuthorized(test.domain, test.account, test.rel)
+		if ok != test.authorized {
+			t.Errorf("Expected [%s] account [%s] %s authorization to be [%t]", test.domain, test.account, test.rel, test.authorized)
+		}
+		if ok && (d == nil || d.PublisherAccountID != test.account) {
+			t.Errorf("Expected data record that authorized [%s] account [%s] and not [%v]", test.domain, test.account, d)
+		}
+		if !ok && d != nil {
+			t.Errorf("Expected no data record for unauthorized [%s] account [%s]", test.domain, test.account)
+		}
+	}
+}
+
+// TestRecordsIsPlaceholderOnly test detecting Ads.txt file with placeholder record, and empty Ads.txt file
+func TestRecordsIsPlaceholderOnly(t *testing.T) {
+	tests := []struct {
+		body        string
+		placeholder bool
+		empty       bool
+	}{
+		{"placeholder.example.com, placeholder, DIRECT, placeholder", true, false},
+		{"# no sellers\nPlaceholder.Example.com,PLACEHOLDER,direct,placeholder # placeholder\ncontact=adops@example.com", true, false},
+		{"placeholder.example.com, placeholder, DIRECT, placeholder\ngreenadexchange.com,XF7342,DIRECT", false, false},
+		{"placeholder.example.com, placeholder, RESELLER, placeholder", false, false},
+		{"", false, true},
+		{"# comment only\ncontact=adops@example.com", false, true},
+		{"not a record", false, false},
+	}
+
+	for _, test := range tests {
+		records, err := ParseBody([]byte(test.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if records.IsPlaceholderOnly() != test.placeholder {
+			t.Errorf("Expected [%q] placeholder only to be [%t]", test.body, test.placeholder)
+		}
+		if records.IsEmpty() != test.empty {
+			t.Errorf("Expected [%q] empty to be [%t]", test.body, test.empty)
+		}
+	}
+
+	// placeholder record is not parsed as data record
+	records, _ := ParseBody([]byte("placeholder.example.com, placeholder, DIRECT, placeholder"))
+	if len(records.DataRecords) != 0 || len(records.Warnings) != 0 {
+		t.Errorf("Expected placeholder record not to be parsed as data record")
+	}
+}
+
+// TestVariableValues test case insensitive variable lookup, with multiple declarations and original casing kept
+func TestVariableValues(t *testing.T) {
+	body := "CONTACT=adops@example.com\ncontact=https://example.com/contact\nSubDomain=news.example.com\nContact=sales@example.com\nx-custom=a\nX-CUSTOM=b"
+	records, err := ParseBody([]byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"adops@example.com", "https://example.com/contact", "sales@example.com"}
+	for _, name := range []string{"contact", "CONTACT", " Contact "} {
+		if values := records.VariableValues(name); !reflect.DeepEqual(values, expected) {
+			t.Errorf("Expected %v values of [%s] and not %v", expected, name, values)
+		}
+	}
+	if values := records.VariableMap()["x-custom"]; !reflect.DeepEqual(values, []string{"a", "b"}) {
+		t.Errorf("Expected both values of [x-custom] variable and not %v", values)
+	}
+	if values := records.VariableValues("inventorypartnerdomain"); len(values) != 0 {
+		t.Errorf("Expected no values of undeclared variable and not %v", values)
+	}
+
+	// original casing is kept in the variable name and line
+	if v := records.Variables[2]; v.Type != "subdomain" || v.Name != "SubDomain" || v.Text != "SubDomain=news.example.com" {
+		t.Errorf("Expected original casing of [SubDomain] variable and not [%s] [%s]", v.Name, v.Text)
+	}
+}
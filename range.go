@@ -0,0 +1,82 @@
+package adstxt
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// range request errors
+const (
+	errHTTPContentRange = "[%s] invalid Content-Range [%s] of range request from offset [%d]"
+)
+
+// isRangeRequest check if Ads.txt request is sent as range request: request for content appended from offset (see
+// Request.RangeStart), or for the first bytes of the file (see WithRangeLimit)
+func (c *Crawler) isRangeRequest(req *Request) bool {
+	return req.RangeStart > 0 || c.rangeLimit > 0
+}
+
+// setRangeHeader set Range header of range request, and If-Range header of conditional range request. Range of
+// compressed response would be of the compressed bytes, so range request asks for identity encoding
+func (c *Crawler) setRangeHeader(httpRequest *http.Request, req *Request) {
+	if !c.isRangeRequest(req) {
+		return
+	}
+
+	end := ""
+	if c.rangeLimit > 0 {
+		end = strconv.FormatInt(req.RangeStart+c.rangeLimit-1, 10)
+	}
+	httpRequest.Header.Set("Range", fmt.Sprintf("bytes=%d-%s", req.RangeStart, end))
+	httpRequest.Header.Set("Accept-Encoding", "identity")
+	if len(req.IfRange) > 0 {
+		httpRequest.Header.Set("If-Range", req.IfRange)
+	}
+}
+
+// isPartial check if HTTP response is partial content response (206) of range request, and that its content starts
+// at the request offset
+func (c *Crawler) isPartial(req *Request, res *http.Response) (bool, error) {
+	if res.StatusCode != http.StatusPartialContent || !c.isRangeRequest(req) {
+		return false, nil
+	}
+
+	cr := res.Header.Get("Content-Range")
+	start, _, ok := strings.Cut(strings.TrimPrefix(cr, "bytes "), "-")
+	if n, err := strconv.ParseInt(start, 10, 64); !ok || err != nil || n != req.RangeStart {
+		return false, fmt.Errorf(errHTTPContentRange, req.URL, cr, req.RangeStart)
+	}
+	return true, nil
+}
+
+// isRangeAtEnd check if HTTP response is range not satisfiable response (416) of range request from the end of the
+// Ads.txt file: the file size reported by Content-Range equals the request offset, so nothing was appended (or the
+// Ads.txt file is empty). Any other range not satisfiable response (for example Ads.txt file that was truncated) is
+// client error
+func (c *Crawler) isRangeAtEnd(req *Request, res *http.Response) bool {
+	if res.StatusCode != http.StatusRequestedRangeNotSatisfiable || !c.isRangeRequest(req) {
+		return false
+	}
+	size, err := strconv.ParseInt(strings.TrimPrefix(res.Header.Get("Content-Range"), "bytes */"), 10, 64)
+	return err == nil && size == req.RangeStart
+}
+
+// completeLines return partial content body without its last line, if the line was cut by the range limit (the
+// Ads.txt file has more content after the range), so parsed records are not truncated
+func completeLines(res *http.Response, body []byte) []byte {
+	cr := res.Header.Get("Content-Range")
+	r, size, ok := strings.Cut(strings.TrimPrefix(cr, "bytes "), "/")
+	_, end, _ := strings.Cut(r, "-")
+	last, err := strconv.ParseInt(end, 10, 64)
+	if total, serr := strconv.ParseInt(size, 10, 64); ok && err == nil && serr == nil && last+1 >= total {
+		return body
+	}
+
+	if i := bytes.LastIndexAny(body, "\r\n"); i >= 0 {
+		return body[:i+1]
+	}
+	return body[:0]
+}
@@ -0,0 +1,113 @@
+package adstxt
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestRangeRequest test range request of content appended to Ads.txt file
+func TestRangeRequest(t *testing.T) {
+	content := "greenadexchange.com, XF7342, DIRECT\n"
+	appended := "google.com, pub-1, RESELLER\n"
+	file := content + appended
+
+	var rangeHeader, encoding string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rangeHeader, encoding = r.Header.Get("Range"), r.Header.Get("Accept-Encoding")
+		w.Header().Set("Content-Type", "text/plain")
+		if len(rangeHeader) == 0 {
+			fmt.Fprint(w, file)
+			return
+		}
+		start, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rangeHeader, "bytes="), "-"))
+		if start >= len(file) {
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", len(file)))
+			w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(file)-1, len(file)))
+		w.WriteHeader(http.StatusPartialContent)
+		fmt.Fprint(w, file[start:])
+	}))
+	defer ts.Close()
+
+	previous, _ := ParseBody([]byte(content))
+
+	c := NewCrawler()
+	res, err := c.Get(&Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1", RangeStart: int64(len(content))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rangeHeader != fmt.Sprintf("bytes=%d-", len(content)) || encoding != "identity" {
+		t.Errorf("Expected range request with identity encoding and not [%s] [%s]", rangeHeader, encoding)
+	}
+	if !res.Partial || string(res.RawBody) != appended {
+		t.Fatalf("Expected partial response of appended content and not [%t] [%s]", res.Partial, res.RawBody)
+	}
+	if err := previous.AppendParse(res.RawBody, len(previous.Body)+1); err != nil || len(previous.DataRecords) != 2 {
+		t.Errorf("Expected appended record to be added to previous records [%v]", err)
+	}
+
+	// nothing appended since the offset
+	res, err = c.Get(&Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1", RangeStart: int64(len(file))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Partial || len(res.DataRecords) != 0 {
+		t.Errorf("Expected empty partial response when nothing was appended")
+	}
+
+	// Ads.txt file was truncated
+	if _, err := c.Get(&Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1", RangeStart: int64(len(file) + 10)}); err == nil {
+		t.Errorf("Expected range request beyond the end of truncated file to fail")
+	}
+}
+
+// TestWithRangeLimit test fetching only the first bytes of Ads.txt file
+func TestWithRangeLimit(t *testing.T) {
+	file := "placeholder.example.com, placeholder, DIRECT, placeholder\ngreenadexchange.com, XF7342, DIRECT\n"
+
+	var rangeHeader string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rangeHeader = r.Header.Get("Range")
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("ETag", `"v1"`)
+		http.ServeContent(w, r, "ads.txt", time.Time{}, strings.NewReader(file))
+	}))
+	defer ts.Close()
+
+	res, err := NewCrawler(WithRangeLimit(64)).Get(&Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rangeHeader != "bytes=0-63" {
+		t.Errorf("Expected range request of the first [64] bytes and not [%s]", rangeHeader)
+	}
+	// second line is cut by the range limit and is not parsed
+	if !res.Partial || !res.Placeholder || len(res.Warnings) != 0 || len(res.RawBody) >= 64 {
+		t.Errorf("Expected partial placeholder response without cut line and not [%t] [%t] [%q]", res.Partial, res.Placeholder, res.RawBody)
+	}
+
+	// range limit larger than the file
+	res, err = NewCrawler(WithRangeLimit(1024)).Get(&Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.DataRecords) != 1 || string(res.RawBody) != file {
+		t.Errorf("Expected whole file content within range limit and not [%q]", res.RawBody)
+	}
+
+	// conditional range request of changed file returns the whole file
+	res, err = NewCrawler(WithRangeLimit(64)).Get(&Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1", IfRange: `"v0"`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Partial || res.StatusCode != http.StatusOK || len(res.DataRecords) != 1 {
+		t.Errorf("Expected whole file of changed conditional range request and not [%t] [%d]", res.Partial, res.StatusCode)
+	}
+}
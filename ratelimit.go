@@ -0,0 +1,74 @@
+package adstxt
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// hostLimiter token bucket rate limiter keyed by host root domain: each host bucket is refilled with rate tokens per
+// second, up to burst tokens, and each HTTP request to the host consumes single token
+type hostLimiter struct {
+	rate    float64                // rate number of tokens added to each bucket per second
+	burst   int                    // burst maximum number of tokens in each bucket
+	buckets map[string]*hostBucket // buckets by host root domain
+	mu      sync.Mutex
+}
+
+// hostBucket single host token bucket
+type hostBucket struct {
+	tokens float64   // tokens available tokens (negative when requests are waiting for tokens)
+	last   time.Time // last time the bucket was refilled
+}
+
+// newHostLimiter create new per host rate limiter
+func newHostLimiter(rate float64, burst int) *hostLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &hostLimiter{rate: rate, burst: burst, buckets: map[string]*hostBucket{}}
+}
+
+// wait block until a request to the host is allowed, or context is done
+func (l *hostLimiter) wait(ctx context.Context, host string) error {
+	d := l.reserve(host, time.Now())
+	if d <= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// reserve take single token from the host bucket and return how long the caller should wait before sending the request
+func (l *hostLimiter) reserve(host string, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[host]
+	if !ok {
+		b = &hostBucket{tokens: float64(l.burst), last: now}
+		l.buckets[host] = b
+	}
+
+	// refill bucket based on the time passed since last refill
+	b.tokens += now.Sub(b.last).Seconds() * l.rate
+	if b.tokens > float64(l.burst) {
+		b.tokens = float64(l.burst)
+	}
+	b.last = now
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens / l.rate * float64(time.Second))
+}
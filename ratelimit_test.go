@@ -0,0 +1,49 @@
+package adstxt
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestHostLimiter test per host token bucket rate limiter
+func TestHostLimiter(t *testing.T) {
+	l := newHostLimiter(10, 2)
+	now := time.Now()
+
+	// burst of 2 requests is allowed
+	if d := l.reserve("example.com", now); d != 0 {
+		t.Errorf("Expected first request to be allowed and not wait [%s]", d)
+	}
+	if d := l.reserve("example.com", now); d != 0 {
+		t.Errorf("Expected second request to be allowed and not wait [%s]", d)
+	}
+
+	// third request should wait for single token (100ms in rate of 10 requests per second)
+	if d := l.reserve("example.com", now); d != 100*time.Millisecond {
+		t.Errorf("Expected third request to wait [100ms] and not [%s]", d)
+	}
+
+	// other hosts are not affected
+	if d := l.reserve("test.com", now); d != 0 {
+		t.Errorf("Expected request to other host to be allowed and not wait [%s]", d)
+	}
+
+	// bucket is refilled over time
+	if d := l.reserve("example.com", now.Add(time.Second)); d != 0 {
+		t.Errorf("Expected request to be allowed after bucket refill and not wait [%s]", d)
+	}
+}
+
+// TestHostLimiterWait test waiting for rate limit is canceled with context
+func TestHostLimiterWait(t *testing.T) {
+	l := newHostLimiter(0.001, 1)
+	l.reserve("example.com", time.Now())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := l.wait(ctx, "example.com"); err != context.DeadlineExceeded {
+		t.Errorf("Expected wait to be canceled with context deadline and not [%v]", err)
+	}
+}
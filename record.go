@@ -12,6 +12,15 @@ const (
 	commentDenote = "#"
 )
 
+// Ads.txt data record extension fields
+const (
+	// Extension fields are separated from the data record fields, and from each other, by the character ";"
+	extensionDenote = ";"
+)
+
+// countryCodeRe MANAGERDOMAIN country code is ISO 3166-1 alpha-2 code
+var countryCodeRe = regexp.MustCompile("^[A-Z]{2}$")
+
 // Ads.txt supported account types
 const (
 	// Direct indicates that the Publisher (content owner) directly controls the account
@@ -20,38 +29,173 @@ const (
 	accountTypeReseller = "RESELLER"
 )
 
+// Relationship type of account/relationship declared by Ads.txt data record (see Records.IsAuthorized). Data record
+// AccountType holds the upper case relationship as written in the Ads.txt file, so invalid relationships are kept for
+// validation: use IsValid to check it is DIRECT or RESELLER, and compare with the Relationship constants instead of
+// raw strings
+type Relationship string
+
+const (
+	// RelationshipAny match any type of account/relationship
+	RelationshipAny Relationship = ""
+	// RelationshipDirect the Publisher (content owner) directly controls the account
+	RelationshipDirect Relationship = accountTypeDirect
+	// RelationshipReseller the Publisher has authorized another entity to control the account
+	RelationshipReseller Relationship = accountTypeReseller
+	// RelationshipUnknown relationship that is not DIRECT or RESELLER (see ParseRelationship)
+	RelationshipUnknown Relationship = "UNKNOWN"
+)
+
+// ParseRelationship parse relationship case-insensitively, ignoring surrounding white space: return RelationshipDirect
+// or RelationshipReseller, or RelationshipUnknown for any other value
+func ParseRelationship(s string) Relationship {
+	switch r := Relationship(s).canonical(); r {
+	case RelationshipDirect, RelationshipReseller:
+		return r
+	}
+	return RelationshipUnknown
+}
+
+// String return the relationship as string
+func (r Relationship) String() string {
+	return string(r)
+}
+
+// IsValid check if the relationship is DIRECT or RESELLER (case-insensitive)
+func (r Relationship) IsValid() bool {
+	return ParseRelationship(string(r)) != RelationshipUnknown
+}
+
+// IsDirect check if the relationship is DIRECT (case-insensitive)
+func (r Relationship) IsDirect() bool {
+	return r.canonical() == RelationshipDirect
+}
+
+// IsReseller check if the relationship is RESELLER (case-insensitive)
+func (r Relationship) IsReseller() bool {
+	return r.canonical() == RelationshipReseller
+}
+
+// MarshalText is the encoding.TextMarshaler interface implementation for Relationship, used for JSON encoding
+func (r Relationship) MarshalText() ([]byte, error) {
+	return []byte(r), nil
+}
+
+// UnmarshalText is the encoding.TextUnmarshaler interface implementation for Relationship: relationship is decoded
+// in upper case without surrounding white space, so JSON encoded "direct" is decoded as RelationshipDirect
+func (r *Relationship) UnmarshalText(b []byte) error {
+	*r = Relationship(b).canonical()
+	return nil
+}
+
+// canonical return upper case relationship without surrounding white space
+func (r Relationship) canonical() Relationship {
+	return Relationship(strings.ToUpper(strings.TrimSpace(string(r))))
+}
+
 // Ads.txt supported Variables types
 const (
 	// Subdomain within the root domain on which Ads.txt can be found
 	varTypeSubdomain = "subdomain"
 	// Contact information for the owner of the Ads.txt file
 	varTypeContact = "contact"
+	// Business domain of the owner of the Ads.txt file (Ads.txt 1.1)
+	varTypeOwnerDomain = "ownerdomain"
+	// Business domain of the primary or exclusive monetization partner of the publisher inventory (Ads.txt 1.1)
+	varTypeManagerDomain = "managerdomain"
+	// Domain of a partner whose Ads.txt file declares inventory sold on behalf of the publisher (Ads.txt 1.1)
+	varTypeInventoryPartnerDomain = "inventorypartnerdomain"
 )
 
 // DataRecord hold single Ads.txt data record
 type DataRecord struct {
-	AdverterDomain     string `json:"adverterdomain"`            // AdverterDomain Domain name of the advertising system (required)
-	PublisherAccountID string `json:"publisheraccountid"`        // PublisherAccountID the identifier associated with the seller (required)
-	AccountType        string `json:"accountype"`                // AccountType enumeration of the type of account: DIRECT or RESELLER (required)
-	CertAuthorityID    string `json:"certauthorityid,omitempty"` // CertAuthorityID An ID that uniquely identifies the advertising system within a certification authority (optional)
+	AdverterDomain     string       `json:"adverterdomain"`            // AdverterDomain Domain name of the advertising system (required)
+	PublisherAccountID string       `json:"publisheraccountid"`        // PublisherAccountID the identifier associated with the seller (required)
+	AccountType        Relationship `json:"accountype"`                // AccountType enumeration of the type of account: DIRECT or RESELLER (required)
+	CertAuthorityID    string       `json:"certauthorityid,omitempty"` // CertAuthorityID An ID that uniquely identifies the advertising system within a certification authority (optional)
+
+	Extensions []string `json:"extensions,omitempty"` // Extensions extension fields that follow the certification authority ID (Ads.txt 1.1, optional)
+	Comment    string   `json:"comment,omitempty"`    // Comment inline comment that follows the record in the Ads.txt line (optional)
+	Comments   []string `json:"comments,omitempty"`   // Comments full-line comments that precede the record in the Ads.txt file (optional)
+
+	Line int    `json:"line,omitempty"` // Line index of the record in the Ads.txt file, 0 if record was not parsed from Ads.txt file
+	Text string `json:"txt,omitempty"`  // Text original Ads.txt line the record was parsed from
 }
 
 // Variable hold single of Ads.txt variable record
 type Variable struct {
-	Type  string `json:"type"`  // Type of variable record. Supported types are subdomain and contact
-	Value string `json:"value"` // Value of variable record
+	Type  string `json:"type"`           // Type of variable record, lower case. Supported types are subdomain and contact
+	Value string `json:"value"`          // Value of variable record
+	Name  string `json:"name,omitempty"` // Name of the variable as declared in the Ads.txt line, in its original casing (e.g. Contact)
+
+	Comment  string   `json:"comment,omitempty"`  // Comment inline comment that follows the variable in the Ads.txt line (optional)
+	Comments []string `json:"comments,omitempty"` // Comments full-line comments that precede the variable in the Ads.txt file (optional)
+
+	Line int    `json:"line,omitempty"` // Line index of the variable in the Ads.txt file, 0 if variable was not parsed from Ads.txt file
+	Text string `json:"txt,omitempty"`  // Text original Ads.txt line the variable was parsed from
+}
+
+// ManagerDomain hold Ads.txt MANAGERDOMAIN variable: business domain of the primary or exclusive monetization partner
+// of the publisher inventory, either globally (no country code) or for a specific country
+type ManagerDomain struct {
+	Domain  string `json:"domain"`            // Domain business domain of the manager
+	Country string `json:"country,omitempty"` // Country ISO 3166-1 alpha-2 country code the manager is declared for (optional)
 }
 
-// parseDataRecord return new DataRecord parsed from single Ads.txt line
+// parseDataRecord return new DataRecord parsed from single Ads.txt line, with the default field count policy
 func parseDataRecord(line string) (*DataRecord, *Warning) {
-	// Data record declaraion: <FIELD #1>, <FIELD #2>, <FIELD #3>, <FIELD #4> (optional)
-	fields := strings.Split(line, ",")
+	return FieldPolicy{}.parseDataRecord(line)
+}
 
-	fieldsLen := len(fields)
-	if fieldsLen < 3 || fieldsLen > 4 {
+// parseDataRecord return new DataRecord parsed from single Ads.txt line, and handle records with 2 fields or more
+// than 4 fields according to the field count policy
+func (p FieldPolicy) parseDataRecord(line string) (*DataRecord, *Warning) {
+	// extension fields (Ads.txt 1.1) follow the data record fields: <FIELD #4>;<EXTENSION>;<EXTENSION>
+	var extensions []string
+	if index := strings.Index(line, extensionDenote); index != -1 {
+		for _, ext := range strings.Split(line[index+1:], extensionDenote) {
+			if ext = strings.TrimSpace(ext); len(ext) > 0 {
+				extensions = append(extensions, ext)
+			}
+		}
+		line = line[0:index]
+	}
+
+	// Data record declaraion: <FIELD #1>, <FIELD #2>, <FIELD #3>, <FIELD #4> (optional). Fields are extracted in
+	// place, without allocating slice of all fields
+	var fields [4]string
+	fieldsLen := 0
+	rest, more := line, true
+	for more && fieldsLen < len(fields) {
+		fields[fieldsLen], rest, more = strings.Cut(rest, ",")
+		fieldsLen++
+	}
+
+	// record with only 2 fields (no account type) is rejected, unless accepted by the field count policy
+	twoFields := fieldsLen == 2 && p.TwoFields.accepts()
+	if fieldsLen < 3 && !twoFields {
 		return nil, &Warning{Level: HighSeverity, Message: fmt.Sprintf("Data record must be declared as <FIELD #1>, <FIELD #2>, <FIELD #3>, <FIELD #4> (optional) pattern")}
 	}
 
+	// fields that follow the certification authority ID are kept as extension fields by default, so forward
+	// compatible records do not lose information
+	var extra *Warning
+	if more {
+		trailing := []string{}
+		for _, f := range strings.Split(rest, ",") {
+			trailing = append(trailing, strings.TrimSpace(f))
+		}
+		switch p.ExtraFields {
+		case FieldsReject:
+			return nil, &Warning{Level: HighSeverity, Message: fmt.Sprintf("Data record has more than 4 fields, extra fields %v", trailing)}
+		case FieldsWarn:
+			extra = &Warning{Level: LowSeverity, Message: fmt.Sprintf("Data record extra fields %v are ignored", trailing)}
+		default:
+			extensions = append(trailing, extensions...)
+			extra = &Warning{Level: LowSeverity, Message: fmt.Sprintf("Data record extension fields %v should be separated by '%s' and not ','", trailing, extensionDenote)}
+		}
+	}
+
 	// make sure required fields are not empty
 	adverterDomain := strings.TrimSpace(fields[0])
 	if len(adverterDomain) == 0 {
@@ -74,12 +218,16 @@ func parseDataRecord(line string) (*DataRecord, *Warning) {
 	}
 
 	accountType := strings.TrimSpace(fields[2])
+	if twoFields {
+		r := DataRecord{AdverterDomain: adverterDomain, PublisherAccountID: publisherAccountID, Extensions: extensions}
+		return &r, &Warning{Level: LowSeverity, Message: fmt.Sprintf("Missing type of account/relationship (required): data record has only 2 fields")}
+	}
 	if len(accountType) == 0 {
 		return nil, &Warning{Level: HighSeverity, Message: fmt.Sprintf("Missing type of account/relationship (required)")}
 	}
 
 	// make sure account type is supported (case insensitive)
-	if strings.ToUpper(accountType) != accountTypeReseller && strings.ToUpper(accountType) != accountTypeDirect {
+	if !strings.EqualFold(accountType, accountTypeReseller) && !strings.EqualFold(accountType, accountTypeDirect) {
 		return nil, &Warning{Level: HighSeverity, Message: fmt.Sprintf("[%s] is not a valid account type. Account type must be [%s] or [%s]",
 			accountType, accountTypeDirect, accountTypeReseller)}
 	}
@@ -87,7 +235,8 @@ func parseDataRecord(line string) (*DataRecord, *Warning) {
 	r := DataRecord{
 		AdverterDomain:     adverterDomain,
 		PublisherAccountID: publisherAccountID,
-		AccountType:        strings.ToUpper(accountType),
+		AccountType:        Relationship(strings.ToUpper(accountType)),
+		Extensions:         extensions,
 	}
 
 	// optional value
@@ -96,8 +245,7 @@ func parseDataRecord(line string) (*DataRecord, *Warning) {
 		r.CertAuthorityID = certAuthorityID
 
 		// check if cert authority id is alphanumeric (if not, it might indicate an error also it is not part of Ads.txt specification)
-		re := regexp.MustCompile("^[a-zA-Z0-9]*$")
-		if !re.MatchString(r.CertAuthorityID) {
+		if !isAlphanumeric(r.CertAuthorityID) {
 			return &r, &Warning{
 				Level:   LowSeverity,
 				Message: fmt.Sprintf("Certification Authority ID %s may not be correct as it is not alphanumeric", r.CertAuthorityID),
@@ -105,7 +253,7 @@ func parseDataRecord(line string) (*DataRecord, *Warning) {
 		}
 	}
 
-	return &r, nil
+	return &r, extra
 }
 
 // parseVariable return new Variable record parsed from Ads.txt line
@@ -114,21 +262,125 @@ func parseVariable(line string) (*Variable, *Warning) {
 	fields := strings.Split(line, "=")
 
 	// check that record type is supported, and return new variable of that type
-	t := fields[0]
-	switch strings.ToLower(t) {
-	case varTypeSubdomain:
-		return &Variable{
-			Type:  varTypeSubdomain,
-			Value: fields[1],
-		}, nil
-	case varTypeContact:
-		return &Variable{
-			Type:  varTypeContact,
-			Value: fields[1],
-		}, nil
+	t := strings.TrimSpace(fields[0])
+	value := strings.TrimSpace(fields[1])
+
+	varType := strings.ToLower(t)
+	known := true
+	switch varType {
+	case varTypeSubdomain, varTypeContact, varTypeOwnerDomain, varTypeManagerDomain, varTypeInventoryPartnerDomain:
 	default:
-		return nil, &Warning{Level: HighSeverity, Message: fmt.Sprintf("[%s] is not a valid Variable type", t)}
+		if len(value) == 0 {
+			return nil, &Warning{Level: HighSeverity, Message: fmt.Sprintf("[%s] is not a valid Variable type", t)}
+		}
+		known = false
+	}
+
+	if len(value) == 0 {
+		return nil, &Warning{Level: HighSeverity, Message: fmt.Sprintf("Missing value of [%s] variable (required)", t)}
+	}
+
+	// unknown variables are kept (see Records.OtherVariables), so forward compatible files do not lose information
+	if !known {
+		return &Variable{Type: varType, Value: value, Name: t}, &Warning{Level: LowSeverity, Message: fmt.Sprintf("[%s] is not a valid Variable type", t)}
+	}
+
+	switch varType {
+	case varTypeSubdomain:
+		if !validateDomainName(value) {
+			return nil, &Warning{Level: HighSeverity, Message: fmt.Sprintf("[%s] is not a valid subdomain", value)}
+		}
+	case varTypeInventoryPartnerDomain:
+		if !validateDomainName(value) {
+			return nil, &Warning{Level: HighSeverity, Message: fmt.Sprintf("[%s] is not a valid INVENTORYPARTNERDOMAIN domain", value)}
+		}
+	case varTypeOwnerDomain:
+		if !validateDomainName(value) {
+			return nil, &Warning{Level: HighSeverity, Message: fmt.Sprintf("[%s] is not a valid OWNERDOMAIN domain", value)}
+		}
+	case varTypeManagerDomain:
+		if _, err := parseManagerDomain(value); err != nil {
+			return nil, &Warning{Level: HighSeverity, Message: err.Error()}
+		}
+	}
+
+	return &Variable{
+		Type:  varType,
+		Value: value,
+		Name:  t,
+	}, nil
+}
+
+// parseManagerDomain parse MANAGERDOMAIN variable value: <DOMAIN>[,<COUNTRY CODE>]
+func parseManagerDomain(value string) (*ManagerDomain, error) {
+	fields := strings.Split(value, ",")
+	if len(fields) > 2 {
+		return nil, fmt.Errorf("MANAGERDOMAIN must be declared as <DOMAIN>[,<COUNTRY CODE>] and not [%s]", value)
+	}
+
+	m := &ManagerDomain{Domain: strings.TrimSpace(fields[0])}
+	if !validateDomainName(m.Domain) {
+		return nil, fmt.Errorf("[%s] is not a valid MANAGERDOMAIN domain", m.Domain)
+	}
+
+	// optional country code (two letters ISO 3166-1 alpha-2)
+	if len(fields) == 2 {
+		m.Country = strings.ToUpper(strings.TrimSpace(fields[1]))
+		if !countryCodeRe.MatchString(m.Country) {
+			return nil, fmt.Errorf("[%s] is not a valid MANAGERDOMAIN ISO 3166-1 alpha-2 country code", m.Country)
+		}
+	}
+
+	return m, nil
+}
+
+// isAlphanumeric check if s holds only ASCII letters and digits
+func isAlphanumeric(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// lineComment return the comment of Ads.txt line (without the comment denote), or empty string if the line has no
+// comment
+func lineComment(line string) string {
+	index := strings.Index(line, commentDenote)
+	if index == -1 {
+		return ""
+	}
+	return strings.TrimSpace(line[index+1:])
+}
+
+// isCommentLine check if Ads.txt line holds only comment
+func isCommentLine(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), commentDenote)
+}
+
+// placeholderFields fields of the IAB placeholder record, declared by Ads.txt file of publisher that has no
+// authorized sellers
+var placeholderFields = [...]string{"placeholder.example.com", "placeholder", accountTypeDirect, "placeholder"}
+
+// isPlaceholderRecord check if Ads.txt line (without comment) is the placeholder record. Fields are matched case
+// insensitive, and extension fields are ignored
+func isPlaceholderRecord(line string) bool {
+	line, _, _ = strings.Cut(line, extensionDenote)
+	if strings.Count(line, ",") != len(placeholderFields)-1 {
+		return false
+	}
+
+	rest := line
+	for _, p := range placeholderFields {
+		var f string
+		f, rest, _ = strings.Cut(rest, ",")
+		if !strings.EqualFold(strings.TrimSpace(f), p) {
+			return false
+		}
 	}
+	return true
 }
 
 // removeComment removes any comment from Ads.txt line before parsing
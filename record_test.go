@@ -1,7 +1,9 @@
 package adstxt
 
 import (
+	"bytes"
 	"encoding/json"
+	"strings"
 	"testing"
 )
 
@@ -267,11 +269,11 @@ func TestParseNotSupportedVariable(t *testing.T) {
 	notSupported := "notSupported=dev.example.com"
 
 	v, w := parseVariable(notSupported)
-	if w == nil {
-		t.Errorf("Expected parsing error when parsing [%s]", notSupported)
+	if w == nil || w.Level != LowSeverity {
+		t.Fatalf("Expected low severity parsing warning when parsing [%s]", notSupported)
 	}
-	if v != nil {
-		t.Errorf("Expected parsing error when parsing [%s] and empty variable [%v]", notSupported, v)
+	if v == nil || v.Type != "notsupported" || v.Value != "dev.example.com" {
+		t.Errorf("Expected not supported variable to be kept when parsing [%s] and not [%v]", notSupported, v)
 	}
 	if w.Message != "[notSupported] is not a valid Variable type" {
 		t.Errorf("Expected error type for [%s] to be [%s] but received [%v]", notSupported, "[notSupported] is not a valid Variable type", w)
@@ -293,3 +295,234 @@ func TestRemoveComment(t *testing.T) {
 	}
 
 }
+
+// TestParseOwnerDomainVariable test parsing Ads.txt 1.1 OWNERDOMAIN Variable type
+func TestParseOwnerDomainVariable(t *testing.T) {
+	ownerDomain := "OWNERDOMAIN= example.com"
+
+	v, w := parseVariable(ownerDomain)
+	if w != nil {
+		t.Errorf("Expected no errors when parsing [%s] [%v]", ownerDomain, w)
+	}
+	if v.Type != varTypeOwnerDomain {
+		t.Errorf("Expected variable type for [%s] to be [%s] but received [%s]", ownerDomain, varTypeOwnerDomain, v.Type)
+	}
+	if v.Value != "example.com" {
+		t.Errorf("Expected variable value for [%s] to be [example.com] but received [%s]", ownerDomain, v.Value)
+	}
+
+	ownerDomain = "OWNERDOMAIN=http://example.com"
+	if _, w = parseVariable(ownerDomain); w == nil {
+		t.Errorf("Expected parsing error when parsing [%s]", ownerDomain)
+	}
+}
+
+// TestParseManagerDomainVariable test parsing Ads.txt 1.1 MANAGERDOMAIN Variable type
+func TestParseManagerDomainVariable(t *testing.T) {
+	valid := map[string]ManagerDomain{
+		"MANAGERDOMAIN=manager.com":      ManagerDomain{Domain: "manager.com"},
+		"managerdomain=manager.com, us":  ManagerDomain{Domain: "manager.com", Country: "US"},
+		"ManagerDomain=manager.com,GB  ": ManagerDomain{Domain: "manager.com", Country: "GB"},
+	}
+
+	for line, expected := range valid {
+		v, w := parseVariable(line)
+		if w != nil {
+			t.Errorf("Expected no errors when parsing [%s] [%v]", line, w)
+			continue
+		}
+
+		m, err := parseManagerDomain(v.Value)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if *m != expected {
+			t.Errorf("Expected MANAGERDOMAIN for [%s] to be [%v] but received [%v]", line, expected, *m)
+		}
+	}
+
+	invalid := []string{
+		"MANAGERDOMAIN=manager.com,USA",
+		"MANAGERDOMAIN=manager.com,US,GB",
+		"MANAGERDOMAIN=manager.com/path",
+	}
+
+	for _, line := range invalid {
+		if _, w := parseVariable(line); w == nil {
+			t.Errorf("Expected parsing error when parsing [%s]", line)
+		}
+	}
+}
+
+// TestParseVariableWarnings test parsing invalid Variable values
+func TestParseVariableWarnings(t *testing.T) {
+	warnings := map[string]string{
+		"contact=":                      "Missing value of [contact] variable (required)",
+		"SUBDOMAIN=   ":                 "Missing value of [SUBDOMAIN] variable (required)",
+		"subdomain=http://dev.test.com": "[http://dev.test.com] is not a valid subdomain",
+		"subdomain=dev.test.com/path":   "[dev.test.com/path] is not a valid subdomain",
+	}
+
+	for line, msg := range warnings {
+		v, w := parseVariable(line)
+		if w == nil {
+			t.Errorf("Expected parsing error when parsing [%s] and not [%v]", line, v)
+			continue
+		}
+		if w.Message != msg {
+			t.Errorf("Expected warning for [%s] to be [%s] but received [%s]", line, msg, w.Message)
+		}
+	}
+
+	// variable value whitespaces should be trimmed
+	v, w := parseVariable("subdomain = dev.example.com ")
+	if w != nil {
+		t.Errorf("Expected no errors when parsing variable [%v]", w)
+	} else if v.Value != "dev.example.com" {
+		t.Errorf("Expected variable value to be [dev.example.com] but received [%s]", v.Value)
+	}
+}
+
+// TestParseDataRecordIDN test parsing Ads.txt data record of internationalized advertising system domain, in both
+// Unicode and punycode forms
+func TestParseDataRecordIDN(t *testing.T) {
+	for _, line := range []string{"bücherexchange.com, XF7342, DIRECT", "XN--BCHEREXCHANGE-WOB.com, XF7342, DIRECT"} {
+		r, w := parseDataRecord(line)
+		if w != nil {
+			t.Errorf("Expected no parse warning when parsing [%s] [%v]", line, w)
+			continue
+		}
+		if r.PublisherAccountID != "XF7342" {
+			t.Errorf("Expected Publisher Account Id for [%s] to be [XF7342] but received [%s]", line, r.PublisherAccountID)
+		}
+		if err := r.Validate(); err != nil {
+			t.Errorf("Expected internationalized Ad system domain [%s] to be valid [%v]", r.AdverterDomain, err)
+		}
+	}
+}
+
+// TestParseDataRecordExtensions test parsing Ads.txt 1.1 data record extension fields and inline comment
+func TestParseDataRecordExtensions(t *testing.T) {
+	line := "greenadexchange.com, XF7342, DIRECT, 5jyxf8k54; ext1 ;ext2=a"
+
+	r, w := parseDataRecord(line)
+	if w != nil {
+		t.Errorf("Expected no parse warning when parsing [%s] [%v]", line, w)
+	}
+	if r.CertAuthorityID != "5jyxf8k54" {
+		t.Errorf("Expected Cert Authority Id for [%s] to be [5jyxf8k54] but received [%s]", line, r.CertAuthorityID)
+	}
+	if len(r.Extensions) != 2 || r.Extensions[0] != "ext1" || r.Extensions[1] != "ext2=a" {
+		t.Errorf("Expected extension fields for [%s] to be [ext1 ext2=a] but received %v", line, r.Extensions)
+	}
+
+	// extension fields separated by comma are kept with low severity warning
+	line = "greenadexchange.com, XF7342, DIRECT, 5jyxf8k54, ext1; ext2"
+	r, w = parseDataRecord(line)
+	if r == nil || w == nil || w.Level != LowSeverity {
+		t.Fatalf("Expected data record with low severity warning when parsing [%s] [%v]", line, w)
+	}
+	if len(r.Extensions) != 2 || r.Extensions[0] != "ext1" || r.Extensions[1] != "ext2" {
+		t.Errorf("Expected extension fields for [%s] to be [ext1 ext2] but received %v", line, r.Extensions)
+	}
+
+	// inline comment is kept with the data record
+	records, _ := ParseBody([]byte("greenadexchange.com, XF7342, DIRECT; ext # reseller since 2020"))
+	if len(records.DataRecords) != 1 {
+		t.Fatalf("Expected single data record")
+	}
+	if d := records.DataRecords[0]; d.Comment != "reseller since 2020" || len(d.Extensions) != 1 || d.CertAuthorityID != "" {
+		t.Errorf("Expected data record with extension field and inline comment and not [%v]", d)
+	}
+}
+
+// TestParseTypedVariables test setting typed Records fields of known variables, and keeping unknown variables
+func TestParseTypedVariables(t *testing.T) {
+	records, _ := ParseBody([]byte(`contact=adops@example.com
+CONTACT=https://example.com/contact
+subdomain=dev.example.com
+subdomain=dev.example.com
+inventorypartnerdomain=partner.com
+INVENTORYPARTNERDOMAIN=other-partner.com
+ownerdomain=example.com
+managerdomain=manager.com
+futurevar=value1
+FutureVar=value2
+inventorypartnerdomain=not a domain`))
+
+	if len(records.Contacts) != 2 || records.Contacts[1] != "https://example.com/contact" {
+		t.Errorf("Expected [2] contacts and not %v", records.Contacts)
+	}
+	if len(records.Subdomains) != 1 || records.Subdomains[0] != "dev.example.com" {
+		t.Errorf("Expected duplicate subdomain to be listed once and not %v", records.Subdomains)
+	}
+	if len(records.InventoryPartnerDomains) != 2 || records.InventoryPartnerDomains[1] != "other-partner.com" {
+		t.Errorf("Expected [2] inventory partner domains and not %v", records.InventoryPartnerDomains)
+	}
+	if records.OwnerDomain != "example.com" || len(records.ManagerDomains) != 1 {
+		t.Errorf("Expected OWNERDOMAIN and MANAGERDOMAIN typed fields")
+	}
+	if v := records.OtherVariables["futurevar"]; len(v) != 2 || v[0] != "value1" || v[1] != "value2" {
+		t.Errorf("Expected unknown variables to be kept by type and not %v", records.OtherVariables)
+	}
+
+	// unknown variables are reported as low severity warnings, and invalid partner domain as high severity warning
+	low, high := 0, 0
+	for _, w := range records.Warnings {
+		if w.Level == LowSeverity {
+			low++
+		} else {
+			high++
+		}
+	}
+	if low != 2 || high != 1 {
+		t.Errorf("Expected [2] low and [1] high severity warnings and not [%d] [%d]", low, high)
+	}
+
+	// typed fields are kept in JSON form
+	b, _ := json.Marshal(records)
+	loaded, err := FromJSON(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Contacts) != 2 || len(loaded.InventoryPartnerDomains) != 2 || len(loaded.OtherVariables["futurevar"]) != 2 {
+		t.Errorf("Expected typed variables fields to be loaded from JSON")
+	}
+}
+
+// TestRelationship test case-insensitive relationship parsing, validation and JSON encoding
+func TestRelationship(t *testing.T) {
+	tests := map[string]Relationship{
+		"DIRECT":       RelationshipDirect,
+		" direct ":     RelationshipDirect,
+		"Reseller":     RelationshipReseller,
+		"RESELLER\t":   RelationshipReseller,
+		"":             RelationshipUnknown,
+		"INTERMEDIARY": RelationshipUnknown,
+	}
+	for s, expected := range tests {
+		if r := ParseRelationship(s); r != expected {
+			t.Errorf("Expected relationship [%s] to be parsed as [%s] and not [%s]", s, expected, r)
+		}
+		if Relationship(s).IsValid() != (expected != RelationshipUnknown) {
+			t.Errorf("Expected relationship [%s] to be valid [%t]", s, expected != RelationshipUnknown)
+		}
+	}
+	if !Relationship("direct").IsDirect() || Relationship("direct").IsReseller() || !RelationshipReseller.IsReseller() {
+		t.Errorf("Expected relationship predicates to be case-insensitive")
+	}
+
+	records, _ := ParseBody([]byte("greenadexchange.com, XF7342, direct"))
+	if len(records.DataRecords) != 1 || records.DataRecords[0].AccountType != RelationshipDirect {
+		t.Errorf("Expected parsed account type to be [%s]", RelationshipDirect)
+	}
+
+	dr := &DataRecord{}
+	if err := json.Unmarshal([]byte(`{"accountype":" reseller"}`), dr); err != nil || dr.AccountType != RelationshipReseller {
+		t.Errorf("Expected JSON relationship to be decoded as [%s] and not [%s] [%v]", RelationshipReseller, dr.AccountType, err)
+	}
+	if b, _ := json.Marshal(dr); !strings.Contains(string(b), `"accountype":"RESELLER"`) {
+		t.Errorf("Expected relationship to be encoded as JSON string [%s]", b)
+	}
+}
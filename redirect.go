@@ -0,0 +1,119 @@
+package adstxt
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"strings"
+)
+
+// redirect policy errors
+const (
+	errRedirectPolicy  = "[%s] redirect from [%s] to [%s] is not allowed by redirect policy: %s"
+	errHTTPSDowngrade  = "redirect from HTTPS to HTTP is not allowed"
+	errRedirectToIP    = "redirect to IP address [%s] is not allowed"
+	errRedirectToTLD   = "redirect from top-level domain [%s] to [%s] is not allowed"
+	errPolicyViolation = "redirect hop [%d] from [%s] to [%s]: %s"
+)
+
+// RedirectPolicy check HTTP redirect followed by the crawler from Ads.txt URL to redirect destination, hop is the
+// number of the redirect in the redirect chain (starting from 1). Return non nil error to stop following the redirect:
+// the request fails with ErrRedirect, whose reason is ErrRedirectPolicy holding the policy error. Redirect policy is
+// checked after the Ads.txt specification redirect rules (see WithRedirectPolicy)
+type RedirectPolicy func(from, to *url.URL, hop int) error
+
+// ErrRedirectPolicy redirect is not allowed by the crawler redirect policy (see WithRedirectPolicy). ErrRedirectPolicy
+// matches ErrRedirectNotAllowed, and its policy error can be checked using errors.Is and errors.As
+type ErrRedirectPolicy struct {
+	From string // From URL that responded with HTTP redirect
+	To   string // To redirect destination
+	Hop  int    // Hop number of the redirect in the redirect chain (starting from 1)
+	Err  error  // Err redirect policy error
+}
+
+func (e *ErrRedirectPolicy) Error() string {
+	return fmt.Sprintf(errPolicyViolation, e.Hop, e.From, e.To, e.Err)
+}
+
+// Unwrap return redirect policy error
+func (e *ErrRedirectPolicy) Unwrap() error {
+	return e.Err
+}
+
+// Is match ErrRedirectNotAllowed
+func (e *ErrRedirectPolicy) Is(target error) bool {
+	return target == ErrRedirectNotAllowed
+}
+
+// RedirectPolicies return redirect policy that allows redirect only if it is allowed by all policies, checked in order
+func RedirectPolicies(policies ...RedirectPolicy) RedirectPolicy {
+	return func(from, to *url.URL, hop int) error {
+		for _, p := range policies {
+			if err := p(from, to, hop); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// DenyHTTPSDowngrade redirect policy that does not allow redirect from HTTPS URL to HTTP URL
+func DenyHTTPSDowngrade(from, to *url.URL, hop int) error {
+	if strings.EqualFold(from.Scheme, "https") && strings.EqualFold(to.Scheme, "http") {
+		return errors.New(errHTTPSDowngrade)
+	}
+	return nil
+}
+
+// DenyIPRedirect redirect policy that does not allow redirect to IP address host
+func DenyIPRedirect(from, to *url.URL, hop int) error {
+	if _, err := netip.ParseAddr(to.Hostname()); err == nil {
+		return fmt.Errorf(errRedirectToIP, to.Hostname())
+	}
+	return nil
+}
+
+// DenyOtherTLD redirect policy that does not allow redirect to host of different top-level domain (for example from
+// example.com to example.net)
+func DenyOtherTLD(from, to *url.URL, hop int) error {
+	if f, t := topLevelDomain(from.Hostname()), topLevelDomain(to.Hostname()); f != t {
+		return fmt.Errorf(errRedirectToTLD, f, t)
+	}
+	return nil
+}
+
+// topLevelDomain return the lower case last label of host name
+func topLevelDomain(host string) string {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	return host[strings.LastIndex(host, ".")+1:]
+}
+
+// checkRedirectPolicy return ErrRedirect if redirect from the request URL is not allowed by the crawler redirect policy
+func (c *Crawler) checkRedirectPolicy(req *Request, redirect string, hop int) error {
+	if c.redirectPolicy == nil {
+		return nil
+	}
+
+	from, err := url.Parse(req.URL)
+	if err != nil {
+		return err
+	}
+	to, err := url.Parse(redirect)
+	if err != nil {
+		return err
+	}
+
+	if err := c.redirectPolicy(from, to, hop); err != nil {
+		return &ErrRedirect{URL: req.URL, Location: redirect, Reason: &ErrRedirectPolicy{From: req.URL, To: redirect, Hop: hop, Err: err},
+			msg: fmt.Sprintf(errRedirectPolicy, req.Domain, req.URL, redirect, err)}
+	}
+	return nil
+}
+
+// inRootDomain check if the host of URL is within scope of root domain: its root domain (see rootDomain) is the root
+// domain, compared case insensitive by punycode form
+func inRootDomain(rawurl, domain string) bool {
+	d, err := rootDomain(rawurl)
+	return err == nil && sameDomain(d, domain)
+}
@@ -0,0 +1,85 @@
+package adstxt
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// TestWithRedirectPolicy test crawler stop following redirect that is not allowed by redirect policy
+func TestWithRedirectPolicy(t *testing.T) {
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Host == "example.com" {
+				return &http.Response{
+					StatusCode: http.StatusMovedPermanently,
+					Header:     http.Header{"Location": []string{"http://www.example.com/ads.txt"}},
+					Body:       io.NopCloser(strings.NewReader("")),
+					Request:    req,
+				}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       io.NopCloser(strings.NewReader("greenadexchange.com,XF7342,DIRECT")),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	hops := []int{}
+	policy := func(from, to *url.URL, hop int) error {
+		hops = append(hops, hop)
+		return nil
+	}
+	res, err := NewCrawler(WithHTTPClient(client), WithRedirectPolicy(policy)).Get(&Request{URL: "https://example.com/ads.txt", Domain: "example.com"})
+	if err != nil || len(res.DataRecords) != 1 {
+		t.Fatalf("Expected redirect allowed by policy to be followed [%v]", err)
+	}
+	if len(hops) != 1 || hops[0] != 1 {
+		t.Errorf("Expected redirect policy to be checked for single hop and not [%v]", hops)
+	}
+
+	c := NewCrawler(WithHTTPClient(client), WithRedirectPolicy(RedirectPolicies(DenyOtherTLD, DenyHTTPSDowngrade)))
+	_, err = c.Get(&Request{URL: "https://example.com/ads.txt", Domain: "example.com"})
+
+	var redirectErr *ErrRedirect
+	var policyErr *ErrRedirectPolicy
+	if !errors.Is(err, ErrRedirectNotAllowed) || !errors.As(err, &redirectErr) || !errors.As(err, &policyErr) {
+		t.Fatalf("Expected ErrRedirectPolicy error and not [%v]", err)
+	}
+	if policyErr.Hop != 1 || policyErr.From != "https://example.com/ads.txt" || policyErr.To != "http://www.example.com/ads.txt" {
+		t.Errorf("Expected ErrRedirectPolicy of HTTPS downgrade redirect and not [%+v]", policyErr)
+	}
+	if policyErr.Err.Error() != errHTTPSDowngrade {
+		t.Errorf("Expected HTTPS downgrade policy error and not [%s]", policyErr.Err)
+	}
+}
+
+// TestRedirectPolicies test built-in redirect policies
+func TestRedirectPolicies(t *testing.T) {
+	tests := []struct {
+		policy   RedirectPolicy
+		from, to string
+		allowed  bool
+	}{
+		{DenyHTTPSDowngrade, "https://example.com/ads.txt", "http://example.com/ads.txt", false},
+		{DenyHTTPSDowngrade, "http://example.com/ads.txt", "https://example.com/ads.txt", true},
+		{DenyIPRedirect, "http://example.com/ads.txt", "http://10.0.0.1/ads.txt", false},
+		{DenyIPRedirect, "http://example.com/ads.txt", "http://[::1]:8080/ads.txt", false},
+		{DenyIPRedirect, "http://example.com/ads.txt", "http://cdn.example.net/ads.txt", true},
+		{DenyOtherTLD, "http://example.com/ads.txt", "http://example.net/ads.txt", false},
+		{DenyOtherTLD, "http://example.com/ads.txt", "http://WWW.EXAMPLE.COM./ads.txt", true},
+	}
+
+	for _, test := range tests {
+		from, _ := url.Parse(test.from)
+		to, _ := url.Parse(test.to)
+		if err := test.policy(from, to, 1); (err == nil) != test.allowed {
+			t.Errorf("Expected redirect from [%s] to [%s] allowed to be [%t] and not [%v]", test.from, test.to, test.allowed, err)
+		}
+	}
+}
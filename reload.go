@@ -0,0 +1,208 @@
+package adstxt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Scheduler config errors
+const (
+	errConfigDuration = "invalid Scheduler config [%s] duration [%s]: %s"
+)
+
+// SchedulerConfig Ads.txt requests and settings of Scheduler, applied to running Scheduler by Reload. Zero settings
+// are set to the Scheduler default values, and negative Jitter disables jitter
+type SchedulerConfig struct {
+	Requests      []*Request    // Requests Ads.txt requests of the Scheduler (see RequestsFromDomains)
+	Jitter        time.Duration // Jitter maximum random delay added to each crawl (see Scheduler.Jitter)
+	ErrorInterval time.Duration // ErrorInterval delay before re-crawling failed Ads.txt file (see Scheduler.ErrorInterval)
+	MinInterval   time.Duration // MinInterval minimum delay between crawls of the same file (see Scheduler.MinInterval)
+}
+
+// ReloadSummary changes of the Scheduler Ads.txt files applied by Reload
+type ReloadSummary struct {
+	Added   []string // Added Ads.txt URLs of the requests added to the Scheduler
+	Removed []string // Removed Ads.txt URLs of the requests removed from the Scheduler
+	Kept    int      // Kept number of requests that were kept, with their crawl schedule
+}
+
+// ConfigSource load Scheduler config (see Scheduler.Watch), for example from config file (see ConfigFile) or from
+// remote config service. Source return nil config if the config did not change since it was previously loaded
+type ConfigSource func(ctx context.Context) (*SchedulerConfig, error)
+
+// Reload replace the Scheduler Ads.txt requests and settings with cfg, without restarting the Scheduler. Requests are
+// diffed by their Ads.txt URL against the current requests: requests that were removed stop being crawled (their
+// in-flight crawls are aborted and not passed to the handler), new requests are scheduled with random jitter as on
+// Run, and requests of unchanged Ads.txt URL keep their crawl schedule and last response (so their re-crawls are
+// still conditional requests). New settings apply from the next crawl of each Ads.txt file. Reload is safe to call
+// while the Scheduler runs, and from multiple goroutines
+func (s *Scheduler) Reload(cfg *SchedulerConfig) *ReloadSummary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Jitter = configDuration(cfg.Jitter, schedulerJitter)
+	s.ErrorInterval = configDuration(cfg.ErrorInterval, schedulerErrorInterval)
+	s.MinInterval = configDuration(cfg.MinInterval, schedulerMinInterval)
+
+	current := make(map[string]*Request, len(s.requests))
+	for _, req := range s.requests {
+		current[req.URL] = req
+	}
+
+	summary := &ReloadSummary{Added: []string{}, Removed: []string{}}
+	requests := make([]*Request, 0, len(cfg.Requests))
+	seen := make(map[string]bool, len(cfg.Requests))
+	for _, req := range cfg.Requests {
+		if seen[req.URL] {
+			continue
+		}
+		seen[req.URL] = true
+
+		if r, ok := current[req.URL]; ok {
+			requests = append(requests, r)
+			summary.Kept++
+			continue
+		}
+		requests = append(requests, req)
+		summary.Added = append(summary.Added, req.URL)
+		if s.ctx != nil {
+			s.start(req)
+		}
+	}
+	for _, req := range s.requests {
+		if seen[req.URL] {
+			continue
+		}
+		summary.Removed = append(summary.Removed, req.URL)
+		if cancel, ok := s.monitors[req.URL]; ok {
+			cancel()
+			delete(s.monitors, req.URL)
+		}
+	}
+
+	s.requests = requests
+	return summary
+}
+
+// Watch load the Scheduler config from src, and then again every interval, applying each changed config with Reload
+// until ctx is done, and return the context error. Watch is typically run next to Run, to reload the Scheduler once
+// its config file changes. Config load errors are logged (see WithLogger), and the Scheduler keeps its current config
+func (s *Scheduler) Watch(ctx context.Context, src ConfigSource, interval time.Duration) error {
+	timer := s.crawler.clock.NewTimer(interval)
+	defer timer.Stop()
+
+	for {
+		cfg, err := src(ctx)
+		switch {
+		case err != nil:
+			s.crawler.log(ctx, s.crawler.logLevels.Error, "Scheduler config load failed", "error", err)
+		case cfg != nil:
+			summary := s.Reload(cfg)
+			s.crawler.log(ctx, s.crawler.logLevels.Request, "Scheduler config reloaded", "added", len(summary.Added),
+				"removed", len(summary.Removed), "kept", summary.Kept)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C():
+			timer.Reset(interval)
+		}
+	}
+}
+
+// configDuration return Scheduler setting of config duration d, or the setting default value if d is zero
+func configDuration(d, def time.Duration) time.Duration {
+	if d == 0 {
+		return def
+	}
+	return d
+}
+
+// schedulerConfigJSON JSON config file of Scheduler (see ConfigFile)
+type schedulerConfigJSON struct {
+	Domains       []string `json:"domains"`
+	Jitter        string   `json:"jitter,omitempty"`
+	ErrorInterval string   `json:"errorInterval,omitempty"`
+	MinInterval   string   `json:"minInterval,omitempty"`
+}
+
+// ConfigFile ConfigSource of JSON config file, that is loaded again each time the file modification time or size
+// changes. The file holds the Scheduler domains (or Ads.txt URLs, see RequestsFromDomains), and optional settings
+// durations in time.ParseDuration format:
+//
+//	{"domains": ["example.com", "test.com"], "jitter": "5m", "errorInterval": "1h", "minInterval": "1m"}
+//
+// Config with invalid domain or duration fails to load as a whole
+func ConfigFile(path string) ConfigSource {
+	var (
+		mu      sync.Mutex
+		modTime time.Time
+		size    int64 = -1
+	)
+
+	return func(ctx context.Context) (*SchedulerConfig, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if info.ModTime().Equal(modTime) && info.Size() == size {
+			return nil, nil
+		}
+		// file is not loaded again until it changes, even if it fails to load
+		modTime, size = info.ModTime(), info.Size()
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return parseSchedulerConfig(b)
+	}
+}
+
+// parseSchedulerConfig parse JSON config file of Scheduler
+func parseSchedulerConfig(b []byte) (*SchedulerConfig, error) {
+	var j schedulerConfigJSON
+	if err := json.Unmarshal(b, &j); err != nil {
+		return nil, err
+	}
+
+	seeds := RequestsFromDomains(j.Domains)
+	if len(seeds.Errors) > 0 {
+		errs := make([]error, len(seeds.Errors))
+		for i, e := range seeds.Errors {
+			errs[i] = e
+		}
+		return nil, errors.Join(errs...)
+	}
+
+	cfg := &SchedulerConfig{Requests: seeds.Requests}
+	settings := []struct {
+		name  string
+		value string
+		d     *time.Duration
+	}{
+		{"jitter", j.Jitter, &cfg.Jitter},
+		{"errorInterval", j.ErrorInterval, &cfg.ErrorInterval},
+		{"minInterval", j.MinInterval, &cfg.MinInterval},
+	}
+	for _, setting := range settings {
+		if len(setting.value) == 0 {
+			continue
+		}
+		d, err := time.ParseDuration(setting.value)
+		if err != nil {
+			return nil, fmt.Errorf(errConfigDuration, setting.name, setting.value, err)
+		}
+		*setting.d = d
+	}
+	return cfg, nil
+}
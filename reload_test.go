@@ -0,0 +1,135 @@
+package adstxt
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestSchedulerReload test adding and removing Ads.txt files of running Scheduler
+func TestSchedulerReload(t *testing.T) {
+	var mu sync.Mutex
+	crawls := map[string]int{}
+	count := func(domain string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return crawls[domain]
+	}
+	waitFor := func(cond func() bool) bool {
+		deadline := time.Now().Add(time.Second)
+		for !cond() && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		return cond()
+	}
+
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			header := http.Header{"Content-Type": []string{"text/plain"}}
+			// test.com Ads.txt file is already expired
+			if req.URL.Host == "test.com" {
+				header.Set("Expires", time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat))
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     header,
+				Body:       io.NopCloser(strings.NewReader("greenadexchange.com,XF7342,DIRECT")),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	s, _ := NewScheduler(NewCrawler(WithHTTPClient(client)), []string{"example.com"}, HandlerFunc(func(req *Request, res *Response, err error) {
+		if err != nil {
+			t.Error(err)
+		}
+		mu.Lock()
+		crawls[req.Domain]++
+		mu.Unlock()
+	}))
+	s.Jitter = 0
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	if !waitFor(func() bool { return count("example.com") == 1 }) {
+		t.Fatal("Expected example.com Ads.txt to be crawled")
+	}
+
+	// test.com is added, while example.com keeps its schedule (its Ads.txt file is not expired)
+	summary := s.Reload(&SchedulerConfig{Requests: RequestsFromDomains([]string{"example.com", "test.com"}).Requests,
+		Jitter: -1, MinInterval: 10 * time.Millisecond})
+	if len(summary.Added) != 1 || len(summary.Removed) != 0 || summary.Kept != 1 {
+		t.Errorf("Expected [1] added and [1] kept Ads.txt files and not %+v", summary)
+	}
+	if !waitFor(func() bool { return count("test.com") >= 2 }) {
+		t.Errorf("Expected added test.com Ads.txt to be re-crawled and not [%d] times", count("test.com"))
+	}
+	if n := count("example.com"); n != 1 {
+		t.Errorf("Expected kept example.com Ads.txt not to be crawled again and not [%d] times", n)
+	}
+	if s.MinInterval != 10*time.Millisecond || s.ErrorInterval != schedulerErrorInterval {
+		t.Errorf("Expected reloaded settings and not [%s] [%s]", s.MinInterval, s.ErrorInterval)
+	}
+
+	// test.com is removed, and is not crawled any more
+	summary = s.Reload(&SchedulerConfig{Requests: RequestsFromDomains([]string{"example.com"}).Requests})
+	if len(summary.Removed) != 1 || summary.Removed[0] != RequestsFromDomains([]string{"test.com"}).Requests[0].URL {
+		t.Errorf("Expected test.com Ads.txt to be removed and not %v", summary.Removed)
+	}
+	n := count("test.com")
+	time.Sleep(50 * time.Millisecond)
+	if count("test.com") != n {
+		t.Errorf("Expected removed test.com Ads.txt not to be crawled and not [%d] times", count("test.com")-n)
+	}
+}
+
+// TestConfigFile test loading Scheduler config from JSON config file once it changes
+func TestConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scheduler.json")
+	write := func(config string) {
+		if err := os.WriteFile(path, []byte(config), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	src := ConfigFile(path)
+
+	if _, err := src(context.Background()); err == nil {
+		t.Error("Expected error of missing config file")
+	}
+
+	write(`{"domains": ["example.com", "test.com", "example.com"], "minInterval": "30s"}`)
+	cfg, err := src(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Requests) != 2 || cfg.MinInterval != 30*time.Second || cfg.Jitter != 0 {
+		t.Errorf("Expected [2] requests with minimum interval [30s] and not %+v", cfg)
+	}
+	if cfg, err := src(context.Background()); cfg != nil || err != nil {
+		t.Errorf("Expected no config of unchanged file and not [%v] [%v]", cfg, err)
+	}
+
+	write(`{"domains": ["example.com"], "jitter": "5 minutes"}`)
+	if _, err := src(context.Background()); err == nil {
+		t.Error("Expected error of invalid duration")
+	}
+	write(`{"domains": ["example.com", "not a domain"]}`)
+	if _, err := src(context.Background()); err == nil {
+		t.Error("Expected error of invalid domain")
+	}
+}
@@ -0,0 +1,178 @@
+package adstxt
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// CrawlReport aggregate report of multiple Ads.txt requests crawled using Crawl: totals by outcome, failed requests
+// by error class, total number of parsed records, request latency percentiles and per domain summaries
+type CrawlReport struct {
+	Total       int                      `json:"total"`       // Total number of handled Ads.txt requests
+	Succeeded   int                      `json:"succeeded"`   // Succeeded Ads.txt requests
+	Failed      int                      `json:"failed"`      // Failed Ads.txt requests
+	Errors      map[string]int           `json:"errors"`      // Errors number of failed requests by error class (see StatusClass)
+	DataRecords int                      `json:"dataRecords"` // DataRecords total number of parsed data records
+	Variables   int                      `json:"variables"`   // Variables total number of parsed variables
+	Warnings    int                      `json:"warnings"`    // Warnings total number of parse warnings
+	Duration    time.Duration            `json:"duration"`    // Duration overall wall-clock duration of the crawl
+	Latency     Percentiles              `json:"latency"`     // Latency percentiles of sent Ads.txt requests duration
+	Domains     map[string]*DomainReport `json:"domains"`     // Domains summary of Ads.txt requests of each root domain
+	Remaining   []string                 `json:"remaining"`   // Remaining URLs of Ads.txt requests that were not sent since the crawl budget was exhausted (see WithBudget), not included in Total
+	Throttling  ThrottlingReport         `json:"throttling"`  // Throttling Ads.txt requests throttled by remote hosts with HTTP 429 response (see WithThrottlePolicy)
+
+	mu        sync.Mutex
+	durations []time.Duration
+}
+
+// DomainReport summary of Ads.txt requests of single root domain
+type DomainReport struct {
+	Requests    int           `json:"requests"`        // Requests number of Ads.txt requests of the domain
+	Succeeded   int           `json:"succeeded"`       // Succeeded Ads.txt requests of the domain
+	DataRecords int           `json:"dataRecords"`     // DataRecords number of parsed data records
+	Duration    time.Duration `json:"duration"`        // Duration total duration of the domain Ads.txt requests
+	Error       string        `json:"error,omitempty"` // Error of the last failed Ads.txt request of the domain
+}
+
+// ThrottlingReport summary of Ads.txt requests throttled by remote hosts with HTTP 429 response
+type ThrottlingReport struct {
+	Throttled   int            `json:"throttled"`   // Throttled number of Ads.txt requests throttled at least once
+	Rescheduled int            `json:"rescheduled"` // Rescheduled total number of times throttled requests were rescheduled
+	Failed      int            `json:"failed"`      // Failed number of throttled requests that failed with ErrThrottled
+	Hosts       map[string]int `json:"hosts"`       // Hosts number of throttled requests by root domain
+}
+
+// Percentiles of Ads.txt requests duration
+type Percentiles struct {
+	P50 time.Duration `json:"p50"`
+	P90 time.Duration `json:"p90"`
+	P99 time.Duration `json:"p99"`
+	Max time.Duration `json:"max"`
+}
+
+// newCrawlReport return new empty CrawlReport
+func newCrawlReport() *CrawlReport {
+	return &CrawlReport{Errors: map[string]int{}, Domains: map[string]*DomainReport{}, Remaining: []string{},
+		Throttling: ThrottlingReport{Hosts: map[string]int{}}}
+}
+
+// add single Ads.txt request outcome to the report. d is the request duration (zero if the request was not sent)
+func (r *CrawlReport) add(req *Request, res *Response, err error, d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if errors.Is(err, ErrBudgetExceeded) {
+		r.Remaining = append(r.Remaining, req.URL)
+		return
+	}
+
+	r.Total++
+	if d > 0 {
+		r.durations = append(r.durations, d)
+	}
+
+	domain := r.Domains[req.Domain]
+	if domain == nil {
+		domain = &DomainReport{}
+		r.Domains[req.Domain] = domain
+	}
+	domain.Requests++
+	domain.Duration += d
+
+	var throttled *ErrThrottled
+	if errors.As(err, &throttled) {
+		r.Throttling.Throttled++
+		r.Throttling.Rescheduled += throttled.Rescheduled
+		r.Throttling.Failed++
+		r.Throttling.Hosts[req.Domain]++
+	} else if res != nil && res.Rescheduled > 0 {
+		r.Throttling.Throttled++
+		r.Throttling.Rescheduled += res.Rescheduled
+		r.Throttling.Hosts[req.Domain]++
+	}
+
+	if err != nil {
+		r.Failed++
+		r.Errors[StatusClass(res, err)]++
+		domain.Error = err.Error()
+		return
+	}
+
+	r.Succeeded++
+	domain.Succeeded++
+	if res != nil && res.Records != nil {
+		r.DataRecords += len(res.DataRecords)
+		r.Variables += len(res.Variables)
+		r.Warnings += len(res.Warnings)
+		domain.DataRecords += len(res.DataRecords)
+	}
+}
+
+// finish set the report overall duration and latency percentiles
+func (r *CrawlReport) finish(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.Duration = d
+	r.Latency = percentiles(r.durations)
+}
+
+// percentiles return nearest-rank percentiles of durations
+func percentiles(durations []time.Duration) Percentiles {
+	if len(durations) == 0 {
+		return Percentiles{}
+	}
+
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	rank := func(p int) time.Duration {
+		i := (p*len(sorted)+99)/100 - 1
+		if i < 0 {
+			i = 0
+		}
+		return sorted[i]
+	}
+	return Percentiles{P50: rank(50), P90: rank(90), P99: rank(99), Max: sorted[len(sorted)-1]}
+}
+
+// Crawl crawl and parse multiple Ads.txt files from remote hosts (see GetMultipleWithContext), pass each response to
+// the handler (h may be nil), and return aggregate report of all requests once all of them were handled
+func (c *Crawler) Crawl(ctx context.Context, req []*Request, h Handler) *CrawlReport {
+	ctx = withFlushers(ctx, h)
+	report := newCrawlReport()
+
+	// duration of each sent request, by request, until the request result is handled
+	var mu sync.Mutex
+	durations := map[*Request]time.Duration{}
+
+	get := func(ctx context.Context, r *Request) (*Response, error) {
+		start := time.Now()
+		res, err := c.GetWithContext(ctx, r)
+
+		mu.Lock()
+		durations[r] = time.Since(start)
+		mu.Unlock()
+
+		return res, err
+	}
+
+	start := time.Now()
+	c.getMultiple(ctx, req, c.progressHandler(HandlerFunc(func(r *Request, res *Response, err error) {
+		mu.Lock()
+		d := durations[r]
+		delete(durations, r)
+		mu.Unlock()
+
+		report.add(r, res, err, d)
+		if h != nil {
+			h.Handle(r, res, err)
+		}
+	}), len(req)), get)
+	report.finish(time.Since(start))
+
+	return report
+}
@@ -0,0 +1,80 @@
+package adstxt
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestCrawl test aggregate report of multiple Ads.txt requests
+func TestCrawl(t *testing.T) {
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			status := http.StatusOK
+			switch req.URL.Host {
+			case "missing.com":
+				status = http.StatusNotFound
+			case "error.com":
+				status = http.StatusInternalServerError
+			}
+			return &http.Response{
+				StatusCode: status,
+				Status:     http.StatusText(status),
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       io.NopCloser(strings.NewReader("greenadexchange.com,XF7342,DIRECT\ngreenadexchange.com,185,RESELLER\ninvalid line")),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	req := []*Request{}
+	for _, d := range []string{"example.com", "www.example.com", "test.com", "missing.com", "error.com"} {
+		r, _ := NewRequest(d)
+		req = append(req, r)
+	}
+
+	handled := 0
+	// ordered results: handler calls are not concurrent
+	report := NewCrawler(WithHTTPClient(client), WithOrderedResults(true)).Crawl(context.Background(), req, HandlerFunc(func(*Request, *Response, error) {
+		handled++
+	}))
+
+	if handled != 5 || report.Total != 5 {
+		t.Errorf("Expected all [5] requests to be handled and reported and not [%d] [%d]", handled, report.Total)
+	}
+	if report.Succeeded != 3 || report.Failed != 2 || report.Errors["4xx"] != 1 || report.Errors["5xx"] != 1 {
+		t.Errorf("Unexpected report outcome counts [%d] [%d] %v", report.Succeeded, report.Failed, report.Errors)
+	}
+	if report.DataRecords != 6 || report.Warnings != 3 {
+		t.Errorf("Unexpected report records counts [%d] [%d]", report.DataRecords, report.Warnings)
+	}
+	if report.Latency.Max <= 0 || report.Latency.P50 > report.Latency.Max || report.Duration < report.Latency.Max {
+		t.Errorf("Unexpected report latency %+v", report.Latency)
+	}
+
+	if d := report.Domains["example.com"]; d == nil || d.Requests != 2 || d.Succeeded != 2 || d.DataRecords != 4 {
+		t.Errorf("Expected example.com summary of [2] successful requests and not %+v", d)
+	}
+	if d := report.Domains["missing.com"]; d == nil || d.Succeeded != 0 || len(d.Error) == 0 {
+		t.Errorf("Expected missing.com summary of failed request and not %+v", d)
+	}
+}
+
+// TestPercentiles test nearest-rank percentiles of requests duration
+func TestPercentiles(t *testing.T) {
+	durations := []time.Duration{}
+	for i := 100; i > 0; i-- {
+		durations = append(durations, time.Duration(i)*time.Millisecond)
+	}
+
+	p := percentiles(durations)
+	if p.P50 != 50*time.Millisecond || p.P90 != 90*time.Millisecond || p.P99 != 99*time.Millisecond || p.Max != 100*time.Millisecond {
+		t.Errorf("Unexpected percentiles %+v", p)
+	}
+	if p := percentiles(nil); p.Max != 0 {
+		t.Errorf("Expected zero percentiles without durations and not %+v", p)
+	}
+}
@@ -2,29 +2,166 @@ package adstxt
 
 import (
 	"fmt"
+	"net"
+	"net/http"
 	"net/url"
 	"strings"
+	"time"
+)
+
+// RequestType type of the Ads.txt file to fetch from remote host
+type RequestType int
+
+const (
+	// AdsTxt request for Ads.txt file (IAB Ads.txt Specification)
+	AdsTxt RequestType = iota
+	// AppAdsTxt request for app-ads.txt file (IAB app-ads.txt Specification), located on mobile app developer website
+	AppAdsTxt
+)
+
+// path return the file path of the request type on remote host
+func (t RequestType) path() string {
+	if t == AppAdsTxt {
+		return "/app-ads.txt"
+	}
+	return "/ads.txt"
+}
+
+// InvalidURLReason reason NewRequestFromURL rejected its input (see ErrInvalidRequestURL)
+type InvalidURLReason string
+
+const (
+	// InvalidURLEmpty input is empty
+	InvalidURLEmpty InvalidURLReason = "empty URL"
+	// InvalidURLSyntax input is not a valid URL
+	InvalidURLSyntax InvalidURLReason = "malformed URL"
+	// InvalidURLScheme URL scheme is not http or https
+	InvalidURLScheme InvalidURLReason = "unsupported URL scheme"
+	// InvalidURLNoHost URL has no hostname
+	InvalidURLNoHost InvalidURLReason = "missing hostname"
+	// InvalidURLIPAddress URL host is an IP address: Ads.txt files are posted on domain names
+	InvalidURLIPAddress InvalidURLReason = "IP address host is not supported"
+	// InvalidURLHostname URL hostname is not a valid domain name
+	InvalidURLHostname InvalidURLReason = "invalid hostname"
+	// InvalidURLPublicSuffix URL hostname is a public suffix (for example co.uk), and has no root domain
+	InvalidURLPublicSuffix InvalidURLReason = "hostname is a public suffix"
+)
+
+// hostname length limits (RFC 1035)
+const (
+	maxHostnameLength = 253
+	maxLabelLength    = 63
 )
 
 // Request to fetch Ads.txt file from remote host
 type Request struct {
-	Domain string `json:"domain"` // Domain holds the root domain of the remote host
-	URL    string `json:"url"`    // URL of the Ads.txt file to fetch
+	Domain string      `json:"domain"` // Domain holds the root domain of the remote host
+	URL    string      `json:"url"`    // URL of the Ads.txt file to fetch
+	Type   RequestType `json:"type"`   // Type of the file to fetch (Ads.txt or app-ads.txt)
+
+	IfNoneMatch     string `json:"ifNoneMatch,omitempty"`     // IfNoneMatch ETag of previously crawled Ads.txt file, sent as If-None-Match header (optional)
+	IfModifiedSince string `json:"ifModifiedSince,omitempty"` // IfModifiedSince Last-Modified of previously crawled Ads.txt file, sent as If-Modified-Since header (optional)
+
+	RangeStart int64  `json:"rangeStart,omitempty"` // RangeStart byte offset of content appended to previously crawled Ads.txt file, sent as Range header (optional, see Records.AppendParse)
+	IfRange    string `json:"ifRange,omitempty"`    // IfRange ETag or Last-Modified of previously crawled Ads.txt file, sent as If-Range header with range request: remote host replies with the whole file if it changed (optional)
+
+	UserAgent string      `json:"userAgent,omitempty"` // UserAgent User-Agent header sent with this request, instead of the crawler User-Agent (optional)
+	Headers   http.Header `json:"headers,omitempty"`   // Headers additional HTTP headers sent with this request, replacing crawler headers with the same name (optional)
+
+	Timeout time.Duration `json:"timeout,omitempty"` // Timeout time limit of this request including redirects and retries, instead of the crawler total timeout (optional)
+
+	Path           string   `json:"path,omitempty"`           // Path of the file to fetch on remote host instead of /ads.txt or /app-ads.txt, for example staging or mirrored location (optional, see SetPath)
+	AlternatePaths []string `json:"alternatePaths,omitempty"` // AlternatePaths ordered alternate paths of the file on remote host (e.g. WellKnownAdsTxtPath), tried in order when the file is not found at the request path (optional)
+	Schemes        []string `json:"schemes,omitempty"`        // Schemes URL schemes (https or http) in order of preference: the request is sent over the first scheme, and the next schemes are tried in order when remote host is unreachable over the previous one, e.g. TLS handshake failed (optional, scheme of the request URL if empty)
+	Port           int      `json:"port,omitempty"`           // Port of remote host instead of the default port of the URL scheme, for example staging server on port 8443 (optional, see SetPort)
+
+	Meta map[string]interface{} `json:"meta,omitempty"` // Meta opaque caller metadata of the request (e.g. database key or tenant ID), not used by the crawler and passed to the handler with the request and its Response (optional)
 }
 
-// NewRequest create new Ads.txt file request from remote host
-func NewRequest(rawurl string) (*Request, error) {
-	u, err := url.Parse(rawurl)
+// SetPath override the path of the file to fetch on remote host (for example /app-ads.txt, staging path of
+// pre-publication file or mirrored location), and update the request URL accordingly. Redirects must lead to file
+// with the same file name. Empty path restores the default path of the request type
+func (r *Request) SetPath(p string) error {
+	if strings.ContainsAny(p, "?#") {
+		return fmt.Errorf(errInvalidRequestPath, p)
+	}
+	if len(p) > 0 && !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+
+	// restore default path of request URL whose path was overridden
+	if len(p) == 0 && len(r.Path) > 0 {
+		u, err := url.Parse(r.URL)
+		if err != nil {
+			return err
+		}
+		u.Path = r.Type.path()
+		r.URL, r.Path = u.String(), ""
+		return nil
+	}
+
+	r.Path = p
+	return r.applyPath()
+}
+
+// filePath return the path of the file to fetch on remote host: the request Path if set, or the default path of the
+// request type
+func (r *Request) filePath() string {
+	if len(r.Path) > 0 {
+		return r.Path
+	}
+	return r.Type.path()
+}
+
+// fileName return the file name (last path segment, with leading slash) of the file to fetch on remote host, which
+// redirect destinations must end with
+func (r *Request) fileName() string {
+	p := r.filePath()
+	return p[strings.LastIndex(p, "/"):]
+}
+
+// applyPath set the path of request URL to the request Path, if set
+func (r *Request) applyPath() error {
+	if len(r.Path) == 0 {
+		return nil
+	}
+	u, err := url.Parse(r.URL)
 	if err != nil {
-		return nil, err
+		return err
 	}
+	if u.Path != r.Path {
+		u.Path = r.Path
+		r.URL = u.String()
+	}
+	return nil
+}
+
+// NewRequest create new Ads.txt file request from remote host. rawurl may be any URL or hostname (for example
+// shop.news.example.co.uk/page): the request root domain is derived using the Public Suffix List. Internationalized
+// domain names are supported: request URL holds the punycode form of the host name, and request Domain holds the
+// Unicode form of the root domain
+func NewRequest(rawurl string) (*Request, error) {
+	rawurl = strings.TrimSpace(rawurl)
 
 	// add scheme to Ads.txt URL if it's missing (by default we will add http and not https since it seems more common. If the site is
 	// running using HTTPS, we will usually get an HTTP redirect response and will handle it)
-	if u.Scheme == "" {
-		u.Scheme = "http"
+	if !strings.Contains(rawurl, "://") {
+		rawurl = "http://" + rawurl
+	}
+
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
 	}
 
+	// Ads.txt URL has no user info, query or fragment, and its host is lower case (internationalized host is converted
+	// to punycode, while request Domain keeps its Unicode form)
+	u.User = nil
+	u.RawQuery = ""
+	u.Fragment = ""
+	u.Host = strings.ToLower(u.Host)
+	u.Host = strings.Replace(u.Host, u.Hostname(), asciiHost(u.Hostname()), 1)
+
 	// add "/ads.txt" to URL path
 	if !strings.HasSuffix(u.Path, "/ads.txt") {
 		u.Path = fmt.Sprintf("%s/ads.txt", strings.TrimSuffix(u.Path, "/"))
@@ -40,3 +177,116 @@ func NewRequest(rawurl string) (*Request, error) {
 	adsTxtURL := fmt.Sprintf("%v", u)
 	return &Request{URL: adsTxtURL, Domain: d}, nil
 }
+
+// NewRequestFromURL create new Ads.txt file request from full URL or hostname, such as URL of any page of the
+// publisher website. Unlike NewRequest, the input is validated: path, port, user info, query and fragment are
+// stripped (Ads.txt file is posted at the root of the host), the hostname must be a valid domain name and IP address
+// hosts are rejected, as the IAB Ads.txt specification requires Ads.txt files to be posted on domain names. Input
+// without scheme is requested over http. Return *ErrInvalidRequestURL describing why the input is not usable for
+// Ads.txt crawling
+func NewRequestFromURL(rawurl string) (*Request, error) {
+	input := rawurl
+	invalid := func(reason InvalidURLReason, err error) (*Request, error) {
+		return nil, &ErrInvalidRequestURL{Input: input, Reason: reason, Err: err}
+	}
+
+	rawurl = strings.TrimSpace(rawurl)
+	if len(rawurl) == 0 {
+		return invalid(InvalidURLEmpty, nil)
+	}
+	if !strings.Contains(rawurl, "://") {
+		rawurl = "http://" + rawurl
+	}
+
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return invalid(InvalidURLSyntax, err)
+	}
+	scheme := strings.ToLower(u.Scheme)
+	if scheme != "http" && scheme != "https" {
+		return invalid(InvalidURLScheme, nil)
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if len(host) == 0 {
+		return invalid(InvalidURLNoHost, nil)
+	}
+	if net.ParseIP(host) != nil {
+		return invalid(InvalidURLIPAddress, nil)
+	}
+	host, err = validHostname(host)
+	if err != nil {
+		return invalid(InvalidURLHostname, err)
+	}
+
+	d, err := rootDomain(host)
+	if err != nil {
+		return invalid(InvalidURLPublicSuffix, err)
+	}
+
+	adsTxtURL := fmt.Sprintf("%s://%s%s", scheme, host, AdsTxt.path())
+	return &Request{URL: adsTxtURL, Domain: d}, nil
+}
+
+// validHostname return the punycode form of hostname, or error if hostname is not a valid domain name: labels of
+// letters, digits and hyphens that do not start or end with hyphen, within the RFC 1035 length limits
+func validHostname(host string) (string, error) {
+	ascii := asciiHost(host)
+	if len(ascii) > maxHostnameLength {
+		return "", fmt.Errorf("hostname is longer than [%d] characters", maxHostnameLength)
+	}
+
+	labels := strings.Split(ascii, ".")
+	if len(labels) < 2 {
+		return "", fmt.Errorf("hostname [%s] has no top level domain", host)
+	}
+	for _, label := range labels {
+		if len(label) == 0 || len(label) > maxLabelLength {
+			return "", fmt.Errorf("hostname label [%s] length must be between 1 and [%d] characters", label, maxLabelLength)
+		}
+		if label[0] == '-' || label[len(label)-1] == '-' {
+			return "", fmt.Errorf("hostname label [%s] starts or ends with hyphen", label)
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-') {
+				return "", fmt.Errorf("hostname label [%s] has invalid character [%c]", label, r)
+			}
+		}
+	}
+	return ascii, nil
+}
+
+// NewAppAdsTxtRequest create new app-ads.txt file request from mobile app developer website URL, as listed in the app
+// store listing of the app. The app-ads.txt file is requested from the root domain of the developer website ("public
+// suffix" plus one string in the name) over the developer URL scheme: any subdomain of the developer URL (not only
+// "www" and "m"), its port, path and query are dropped
+func NewAppAdsTxtRequest(developerURL string) (*Request, error) {
+	u, err := url.Parse(developerURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// developer URL without scheme is parsed as path: add default scheme and parse it again
+	if u.Scheme == "" {
+		u, err = url.Parse("http://" + developerURL)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// According to IAB app-ads.txt specification, app-ads.txt file should be posted on the root domain of the developer
+	// website: developer URL is reduced to its root domain by the Public Suffix List, so "www" and "m" subdomains
+	// are removed as any other subdomain (e.g. games.example.com)
+	d, err := rootDomain(developerURL)
+	if err != nil {
+		return nil, err
+	}
+
+	u.Host = asciiHost(d)
+	u.Path = AppAdsTxt.path()
+	u.RawQuery = ""
+	u.Fragment = ""
+
+	adsTxtURL := fmt.Sprintf("%v", u)
+	return &Request{URL: adsTxtURL, Domain: d, Type: AppAdsTxt}, nil
+}
@@ -1,8 +1,10 @@
 package adstxt
 
 import (
-	"encoding/json"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 )
@@ -14,6 +16,20 @@ type Records struct {
 	Variables   []*Variable   `json:"variables"`
 	Warnings    []*Warning    `json:"warnings"`
 	Body        []string      `json:"body"` // Original Ads.txt file content
+
+	OwnerDomain             string              `json:"ownerDomain,omitempty"`             // OwnerDomain business domain of the Ads.txt file owner (OWNERDOMAIN variable)
+	ManagerDomains          []*ManagerDomain    `json:"managerDomains,omitempty"`          // ManagerDomains declared monetization partners (MANAGERDOMAIN variables)
+	Contacts                []string            `json:"contacts,omitempty"`                // Contacts contact information of the Ads.txt file owner (CONTACT variables)
+	Subdomains              []string            `json:"subdomains,omitempty"`              // Subdomains subdomains with their own Ads.txt file (SUBDOMAIN variables)
+	InventoryPartnerDomains []string            `json:"inventoryPartnerDomains,omitempty"` // InventoryPartnerDomains inventory partners domains (INVENTORYPARTNERDOMAIN variables)
+	OtherVariables          map[string][]string `json:"otherVariables,omitempty"`          // OtherVariables values of variables not defined by Ads.txt specification, by lower case variable type
+
+	TrailingComments []string `json:"trailingComments,omitempty"` // TrailingComments full-line comments that follow the last record in the Ads.txt file
+	Placeholder      bool     `json:"placeholder,omitempty"`      // Placeholder Ads.txt file declares the IAB placeholder record (publisher has no authorized sellers)
+
+	ParseStats ParseStats `json:"parseStats"` // ParseStats line statistics of the Ads.txt file, counted while it is parsed
+
+	comments []string // full-line comments parsed since the last record, attached to the next record
 }
 
 // Response to an Ads.txt request: collection of Data\Variable records parsed from Ads.txt file and
@@ -21,86 +37,219 @@ type Records struct {
 type Response struct {
 	*Request
 	*Records
-	Expires time.Time `json:"expires"` // Ads.txt file expiration date
+	Expires       time.Time     `json:"expires"`                 // Ads.txt file expiration date
+	ExpiresSource ExpiresSource `json:"expiresSource,omitempty"` // ExpiresSource header the expiration date is based on
+	ExpiresHeader string        `json:"expiresHeader,omitempty"` // ExpiresHeader raw Expires header value of the final HTTP response
+	Attempts      int           `json:"attempts"`                // Attempts number of HTTP requests sent to remote host, including retries
+
+	ETag         string `json:"etag,omitempty"`         // ETag of Ads.txt file from response header
+	LastModified string `json:"lastModified,omitempty"` // LastModified date of Ads.txt file from response header
+	NotModified  bool   `json:"notModified,omitempty"`  // NotModified remote host replied 304: Ads.txt file did not change since the previous crawl and Records are empty
+	Partial      bool   `json:"partial,omitempty"`      // Partial remote host replied to range request: Records and RawBody hold only the content from Request.RangeStart, up to the range limit (see WithRangeLimit)
+
+	FinalURL      string        `json:"finalUrl"`                // FinalURL of the Ads.txt file after following redirects
+	StatusCode    int           `json:"statusCode"`              // StatusCode of the final HTTP response
+	Header        http.Header   `json:"header,omitempty"`        // Header of the final HTTP response
+	RobotsTag     []string      `json:"robotsTag,omitempty"`     // RobotsTag lower case X-Robots-Tag directives of the final HTTP response that apply to the crawler (see NoArchive)
+	RequestHeader http.Header   `json:"requestHeader,omitempty"` // RequestHeader HTTP headers sent with the final HTTP request, for auditing
+	ContentLength int64         `json:"contentLength"`           // ContentLength size of the Ads.txt file body in bytes
+	Fetched       time.Time     `json:"fetched"`                 // Fetched time the Ads.txt file was fetched from remote host
+	Duration      time.Duration `json:"duration"`                // Duration of the fetch, including redirects and retries
+	RawBody       []byte        `json:"-"`                       // RawBody Ads.txt file body as received from remote host (after decompression)
+	SHA256        string        `json:"sha256,omitempty"`        // SHA256 hex encoded SHA-256 hash of the raw body, empty for NotModified response
+	Redirects     []*Redirect   `json:"redirects"`               // Redirects chain of HTTP redirects followed to the Ads.txt file
+	Variant       string        `json:"variant,omitempty"`       // Variant Ads.txt URL variant (before redirects) that succeeded when crawling with fallback
+	AlternatePath string        `json:"alternatePath,omitempty"` // AlternatePath alternate path of the request (see Request AlternatePaths) where the file was found, empty if found at the request path
+	FinalScheme   string        `json:"finalScheme,omitempty"`   // FinalScheme URL scheme (https or http) of the final Ads.txt URL (see Request Schemes)
+	FinalPort     int           `json:"finalPort,omitempty"`     // FinalPort port of the final Ads.txt URL: its explicit port, or the default port of its scheme
+	TLS           *TLSInfo      `json:"tls,omitempty"`           // TLS connection and server certificate details, nil if the Ads.txt file was not fetched over TLS
+	RemoteAddr    string        `json:"remoteAddr,omitempty"`    // RemoteAddr remote address ("ip:port") of the connection that served the final HTTP response, empty if unknown (e.g. custom Fetcher) or the request was sent through the crawler proxy (see WithProxyFunc). With custom HTTP client that uses proxy, it is the proxy address
+	AddressFamily IPFamily      `json:"addressFamily,omitempty"` // AddressFamily IP address family (ipv4 or ipv6) of the connection that served the final HTTP response, empty if unknown
+
+	FetchedInsecurely bool `json:"fetchedInsecurely,omitempty"` // FetchedInsecurely Ads.txt file was fetched over plain HTTP after HTTPS request failed at the TLS layer (see WithHTTPFallback)
+	HTMLRedirected    bool `json:"htmlRedirected,omitempty"`    // HTMLRedirected Ads.txt file was reached by following HTML redirect, which is not compliant with IAB Ads.txt specification (see WithHTMLRedirects)
+	Rescheduled       int  `json:"rescheduled,omitempty"`       // Rescheduled number of times the request was rescheduled within the batch crawl after remote host throttled it (see WithThrottlePolicy)
+	Truncated         bool `json:"truncated,omitempty"`         // Truncated request timed out while the Ads.txt file body was read: Records hold only the complete lines read before the timeout (see WithPartialBody)
+	OffDomainRedirect bool `json:"offDomainRedirect,omitempty"` // OffDomainRedirect Ads.txt file was served by third party host, reached by single redirect outside the original root domain (one-hop delegation of IAB Ads.txt specification)
+
+	Cached bool          `json:"cached,omitempty"` // Cached response was served from cache (see CachingCrawler) or archive (see Archive) and not fetched by this request: Fetched is the original fetch time
+	Age    time.Duration `json:"age,omitempty"`    // Age of cached response: time since the Ads.txt file was fetched, when it was served
+	Stale  bool          `json:"stale,omitempty"`  // Stale cached response was served after it expired (see Expires and CachingCrawler.StaleWhileRevalidate)
+
+	Metadata map[string]interface{} `json:"metadata,omitempty"` // Metadata added to the response by the crawler enrichers, by key (see WithEnrichers)
+}
+
+// Redirect single HTTP redirect followed while fetching Ads.txt file
+type Redirect struct {
+	URL        string `json:"url"`            // URL that responded with HTTP redirect
+	StatusCode int    `json:"statusCode"`     // StatusCode of the HTTP redirect response
+	Location   string `json:"location"`       // Location redirect destination
+	HTML       bool   `json:"html,omitempty"` // HTML redirect (meta refresh or JavaScript) of HTML page, which is not compliant with IAB Ads.txt specification (see WithHTMLRedirects)
+}
+
+// Conditional return copy of the Ads.txt request, that is sent as conditional request with response ETag and
+// Last-Modified: if the Ads.txt file did not change since this response, the crawler returns NotModified response
+func (r *Response) Conditional(req *Request) *Request {
+	c := *req
+	c.IfNoneMatch = r.ETag
+	c.IfModifiedSince = r.LastModified
+	return &c
 }
 
-// parseRecords parse Ads.txt file content
-func parseRecords(lines []string) *Records {
-	r := &Records{
-		DataRecords: []*DataRecord{},
-		Variables:   []*Variable{},
-		Warnings:    []*Warning{},
-		Body:        lines,
+// setCached mark response as cached response served at now, with its age and staleness
+func (r *Response) setCached(now time.Time) {
+	r.Cached = true
+	r.Age = 0
+	if !r.Fetched.IsZero() && now.After(r.Fetched) {
+		r.Age = now.Sub(r.Fetched)
 	}
+	r.Stale = !r.Expires.IsZero() && !now.Before(r.Expires)
+}
 
-	// loop over Ads.txt file lines and parse each line into Ads.txt record
-	for index, l := range lines {
-		r.parseRecord(index+1, l)
+// Changed return true if Ads.txt file of the response changed since the previous response prev (nil if there is no
+// previous response), by comparing the raw body hashes, so periodic crawlers can skip comparing the records (see Diff)
+// of unchanged files. NotModified response is never changed. When either response has no hash (for example response
+// loaded from JSON encoded before hashes were added), the Ads.txt file lines are compared
+func (r *Response) Changed(prev *Response) bool {
+	if r.NotModified {
+		return false
+	}
+	if prev == nil {
+		return true
 	}
+	if len(r.SHA256) > 0 && len(prev.SHA256) > 0 {
+		return r.SHA256 != prev.SHA256
+	}
+	if r.Records == nil || prev.Records == nil {
+		return r.Records != prev.Records
+	}
+	return strings.Join(r.Body, "\n") != strings.Join(prev.Body, "\n")
+}
 
-	return r
+// bodyHash return hex encoded SHA-256 hash of Ads.txt file body
+func bodyHash(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
 }
 
 // parseRecord parse a single Ads.txt line into Data\Variable record
-func (r *Records) parseRecord(index int, txt string) {
-	line := removeComment(txt)
+func (r *Records) parseRecord(index int, txt string, policy FieldPolicy) {
+	l := policy.parseLine(index, txt)
+	r.addLine(&l)
+	r.ParseStats.add(&l)
+}
 
-	// ignore comments and empty line
-	if len(line) == 0 || string(line) == commentDenote {
+// addLine add parsed Ads.txt line Data\Variable record and parse warning to Ads.txt records. Full-line comments are
+// attached to the next record
+func (r *Records) addLine(l *Line) {
+	if l.Placeholder {
+		r.Placeholder = true
+		r.comments = nil
+		return
+	}
+	if l.DataRecord == nil && l.Variable == nil && l.Warning == nil && isCommentLine(l.Text) {
+		r.comments = append(r.comments, lineComment(l.Text))
 		return
 	}
 
-	// parse line into Data\Variable record
-	if strings.Count(line, ",") >= 2 && strings.Count(line, "=") <= 5 {
-		dr, w := parseDataRecord(line)
-		if w != nil {
-			w.Index = index
-			w.Text = txt
-			r.Warnings = append(r.Warnings, w)
-		}
-		if dr != nil {
-			r.DataRecords = append(r.DataRecords, dr)
-		}
-	} else if strings.Index(line, "=") != -1 && strings.Count(line, "=") == 1 {
-		v, w := parseVariable(txt)
-		if w != nil {
-			w.Index = index
-			w.Text = txt
-			r.Warnings = append(r.Warnings, w)
-		} else {
-			r.Variables = append(r.Variables, v)
+	if l.Variable != nil {
+		if w := r.addVariable(l.Variable); w != nil {
+			w.Index = l.Index
+			w.Text = l.Text
+			l.Variable = nil
+			l.Warning = w
 		}
-	} else {
-		w := &Warning{Text: txt, Index: index, Level: HighSeverity, Message: "could not parse this line"}
-		r.Warnings = append(r.Warnings, w)
+	}
+	if l.Variable != nil {
+		r.setLine(l.Variable, l.Index)
+		l.Variable.Comments, r.comments = r.comments, nil
+	}
+	if l.DataRecord != nil {
+		r.DataRecords = append(r.DataRecords, l.DataRecord)
+		r.setLine(l.DataRecord, l.Index)
+		l.DataRecord.Comments, r.comments = r.comments, nil
+	}
+	if l.Warning != nil {
+		r.Warnings = append(r.Warnings, l.Warning)
 	}
 }
 
-// custom "toString" method
-func (r *Records) String() string {
-	str := []string{}
-	if len(r.Warnings) > 0 {
-		str = append(str, fmt.Sprintf("Warnings: [%d]", len(r.Warnings)))
-		for _, w := range r.Warnings {
-			j, _ := json.Marshal(w)
-			str = append(str, string(j))
-		}
+// setLine set the line index in which Data\Variable record was declared in the Ads.txt file
+func (r *Records) setLine(record interface{}, index int) {
+	switch rec := record.(type) {
+	case *DataRecord:
+		rec.Line = index
+	case *Variable:
+		rec.Line = index
 	}
+}
 
-	if len(r.DataRecords) > 0 {
-		str = append(str, fmt.Sprintf("Data Records: [%d]", len(r.DataRecords)))
-		for _, r := range r.DataRecords {
-			j, _ := json.Marshal(r)
-			str = append(str, string(j))
+// Line return the line index in which Data\Variable record was declared in the Ads.txt file, or 0 if record was not
+// parsed from Ads.txt file (e.g. added manually to Records)
+func (r *Records) Line(record interface{}) int {
+	switch rec := record.(type) {
+	case *DataRecord:
+		return rec.Line
+	case *Variable:
+		return rec.Line
+	}
+	return 0
+}
+
+// addVariable add parsed variable to Ads.txt records, and set typed variables fields. Return Warning if variable
+// multiplicity is not allowed (more than single OWNERDOMAIN, or single MANAGERDOMAIN per country)
+func (r *Records) addVariable(v *Variable) *Warning {
+	switch v.Type {
+	case varTypeContact:
+		r.Contacts = append(r.Contacts, v.Value)
+	// subdomains and inventory partners are sets of domains: duplicate declarations are listed once
+	case varTypeSubdomain:
+		if !contains(r.Subdomains, v.Value) {
+			r.Subdomains = append(r.Subdomains, v.Value)
+		}
+	case varTypeInventoryPartnerDomain:
+		if !contains(r.InventoryPartnerDomains, v.Value) {
+			r.InventoryPartnerDomains = append(r.InventoryPartnerDomains, v.Value)
+		}
+	case varTypeOwnerDomain:
+		// only single OWNERDOMAIN variable is allowed in Ads.txt file
+		if len(r.OwnerDomain) > 0 {
+			return &Warning{Level: HighSeverity, Message: fmt.Sprintf("OWNERDOMAIN is already declared as [%s], only single OWNERDOMAIN is allowed", r.OwnerDomain)}
 		}
+		r.OwnerDomain = v.Value
+	case varTypeManagerDomain:
+		m, err := parseManagerDomain(v.Value)
+		if err != nil {
+			return &Warning{Level: HighSeverity, Message: err.Error()}
+		}
+
+		// only single MANAGERDOMAIN is allowed per country, and single MANAGERDOMAIN without country code
+		for _, d := range r.ManagerDomains {
+			if d.Country == m.Country {
+				if len(m.Country) == 0 {
+					return &Warning{Level: HighSeverity, Message: fmt.Sprintf("MANAGERDOMAIN is already declared as [%s], only single MANAGERDOMAIN without country code is allowed", d.Domain)}
+				}
+				return &Warning{Level: HighSeverity, Message: fmt.Sprintf("MANAGERDOMAIN for country [%s] is already declared as [%s]", m.Country, d.Domain)}
+			}
+		}
+		r.ManagerDomains = append(r.ManagerDomains, m)
+	default:
+		if r.OtherVariables == nil {
+			r.OtherVariables = map[string][]string{}
+		}
+		r.OtherVariables[v.Type] = append(r.OtherVariables[v.Type], v.Value)
 	}
 
-	if len(r.Variables) > 0 {
-		str = append(str, fmt.Sprintf("Variables: [%d]", len(r.Variables)))
-		for _, v := range r.Variables {
-			j, _ := json.Marshal(v)
-			str = append(str, string(j))
+	r.Variables = append(r.Variables, v)
+	return nil
+}
+
+// contains check case insensitive if domain is in domains
+func contains(domains []string, domain string) bool {
+	for _, d := range domains {
+		if strings.EqualFold(d, domain) {
+			return true
 		}
 	}
-	return strings.Join(str, "\n")
+	return false
 }
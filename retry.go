@@ -0,0 +1,113 @@
+package adstxt
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy control how the crawler retries requests that failed due to transient failures: HTTP 5xx responses
+// and network timeouts. The delay between attempts grows exponentially, starting with Backoff and doubled after
+// each attempt up to MaxBackoff, with random jitter added to spread retries of parallel requests
+type RetryPolicy struct {
+	MaxAttempts int           // MaxAttempts maximum number of attempts for single HTTP request, including the first one
+	Backoff     time.Duration // Backoff delay before the first retry
+	MaxBackoff  time.Duration // MaxBackoff maximum delay between attempts (no limit if zero)
+	Jitter      float64       // Jitter fraction of the delay (0 to 1) that is randomly added to each delay
+}
+
+// maxRetryDelay longest delay between attempts: delays that overflow time.Duration are clamped to it
+const maxRetryDelay = time.Duration(math.MaxInt64)
+
+// delay return the delay before the specified retry attempt (first retry is attempt 1)
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	d := p.Backoff
+	for i := 1; i < attempt && d > 0; i++ {
+		if d > maxRetryDelay/2 {
+			d = maxRetryDelay
+			break
+		}
+		d *= 2
+		if p.MaxBackoff > 0 && d >= p.MaxBackoff {
+			break
+		}
+	}
+
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+
+	if p.Jitter > 0 && d > 0 {
+		jitter := rand.Float64() * p.Jitter * float64(d)
+		if jitter >= float64(maxRetryDelay-d) {
+			return maxRetryDelay
+		}
+		d += time.Duration(jitter)
+	}
+
+	return d
+}
+
+// sendWithRetry send HTTP request to remote host, and retry it on transient failures based on crawler retry policy.
+// Return the last response or error, and the number of attempts made
+func (c *Crawler) sendWithRetry(ctx context.Context, req *Request) (*http.Response, int, error) {
+	for attempt := 1; ; attempt++ {
+		// wait for per host rate limit
+		if c.limiter != nil {
+			if err := c.limiter.wait(ctx, req.Domain); err != nil {
+				return nil, attempt - 1, err
+			}
+		}
+		// wait for per host politeness delay
+		if c.hostDelay != nil {
+			if err := c.hostDelay.wait(ctx, req.Domain); err != nil {
+				return nil, attempt - 1, err
+			}
+		}
+
+		res, err := c.sendRequest(ctx, req)
+		if attempt >= c.retry.MaxAttempts || !retryable(ctx, res, err) {
+			return res, attempt, err
+		}
+
+		// discard failed response before retrying
+		status := 0
+		if res != nil {
+			status = res.StatusCode
+			res.Body.Close()
+		}
+
+		if err := c.hooks.retry(req, attempt, status, err); err != nil {
+			return nil, attempt, err
+		}
+
+		delay := c.retry.delay(attempt)
+		c.log(ctx, c.logLevels.Retry, "Ads.txt request retry", "domain", req.Domain, "url", req.URL,
+			"attempt", attempt, "status", status, "error", err, "delay", delay)
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, attempt, ctx.Err()
+		}
+	}
+}
+
+// retryable check if request failure is transient: HTTP 5xx response or network timeout
+func retryable(ctx context.Context, res *http.Response, err error) bool {
+	// request was canceled by the caller
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if err != nil {
+		var netErr net.Error
+		return errors.As(err, &netErr) && netErr.Timeout()
+	}
+
+	return res.StatusCode >= 500
+}
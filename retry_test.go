@@ -0,0 +1,79 @@
+package adstxt
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestRetryPolicyDelay test exponential backoff delay between attempts
+func TestRetryPolicyDelay(t *testing.T) {
+	p := RetryPolicy{Backoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second}
+	for index, d := range expected {
+		if p.delay(index+1) != d {
+			t.Errorf("Expected delay of retry #%d to be [%s] and not [%s]", index+1, d, p.delay(index+1))
+		}
+	}
+
+	// jitter is added to delay
+	p.Jitter = 0.5
+	for i := 0; i < 10; i++ {
+		if d := p.delay(1); d < 100*time.Millisecond || d > 150*time.Millisecond {
+			t.Errorf("Expected delay with jitter to be between [100ms] and [150ms] and not [%s]", d)
+		}
+	}
+
+	// delay without maximum backoff does not overflow at large attempt count
+	p = RetryPolicy{Backoff: 100 * time.Millisecond}
+	for _, attempt := range []int{40, 64, 100, 1000} {
+		if d := p.delay(attempt); d != maxRetryDelay {
+			t.Errorf("Expected delay of retry #%d to be clamped to [%s] and not [%s]", attempt, maxRetryDelay, d)
+		}
+	}
+	if d := p.delay(30); d != 100*time.Millisecond<<29 {
+		t.Errorf("Expected delay of retry #30 to be [%s] and not [%s]", 100*time.Millisecond<<29, d)
+	}
+	p.Jitter = 1
+	if d := p.delay(100); d != maxRetryDelay {
+		t.Errorf("Expected delay with jitter to be clamped to [%s] and not [%s]", maxRetryDelay, d)
+	}
+}
+
+// TestCrawlerRetry test crawler retries request on HTTP 5xx response
+func TestCrawlerRetry(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, "greenadexchange.com,XF7342,DIRECT")
+	}))
+	defer ts.Close()
+
+	c := NewCrawler(WithRetry(RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}))
+	res, err := c.Get(&Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Attempts != 3 {
+		t.Errorf("Expected [3] attempts and not [%d]", res.Attempts)
+	}
+
+	// fail once max attempts is reached
+	requests = 0
+	c = NewCrawler(WithRetry(RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond}))
+	_, err = c.Get(&Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"})
+	if e, ok := err.(*ErrServerError); !ok || e.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected request to fail with HTTP 503 error and not [%v]", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected [2] requests and not [%d]", requests)
+	}
+}
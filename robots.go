@@ -0,0 +1,276 @@
+package adstxt
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// robots.txt error\warning
+const (
+	errRobotsDisallowed = "[%s] Ads.txt URL [%s] is disallowed by robots.txt file of remote host"
+)
+
+// robots.txt file settings
+const (
+	robotsMaxSize = 500 * 1024 // robots.txt files larger than 500KB are truncated (RFC 9309)
+	// robots.txt rules are cached up to 24 hours (RFC 9309), while the complete disallow of unreachable robots.txt
+	// file is cached only briefly, so temporary server error does not block the host for the crawler lifetime
+	robotsCacheTTL       = 24 * time.Hour
+	robotsUnreachableTTL = time.Minute
+)
+
+// robotsRules robots.txt rules that apply to the crawler User-Agent
+type robotsRules struct {
+	allow      []string      // allow path patterns
+	disallow   []string      // disallow path patterns
+	crawlDelay time.Duration // crawlDelay minimum delay between consecutive requests to the host
+	expires    time.Time     // expires time the cached rules must be fetched again (by the crawler clock)
+}
+
+// robotsChecker fetch, cache and apply robots.txt rules of remote hosts
+type robotsChecker struct {
+	rules    map[string]*robotsRules // rules by host (scheme and host name)
+	lastSent map[string]time.Time    // last time a request was sent to the host
+	mu       sync.Mutex
+}
+
+// newRobotsChecker create new robots.txt checker
+func newRobotsChecker() *robotsChecker {
+	return &robotsChecker{rules: map[string]*robotsRules{}, lastSent: map[string]time.Time{}}
+}
+
+// check that Ads.txt URL is allowed by robots.txt of remote host, and wait for robots.txt crawl delay
+func (r *robotsChecker) check(ctx context.Context, c *Crawler, req *Request) error {
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		return err
+	}
+	host := u.Scheme + "://" + u.Host
+
+	rules, err := r.get(ctx, c, req, host)
+	if err != nil {
+		return err
+	}
+
+	if !rules.allowed(u.EscapedPath()) {
+		return &ErrRobotsDisallowed{Domain: req.Domain, URL: req.URL}
+	}
+
+	return r.wait(ctx, host, rules.crawlDelay)
+}
+
+// get return cached robots.txt rules of the host, or fetch them from remote host if they are not cached or expired.
+// robots.txt fetch errors are not cached
+func (r *robotsChecker) get(ctx context.Context, c *Crawler, req *Request, host string) (*robotsRules, error) {
+	r.mu.Lock()
+	rules, ok := r.rules[host]
+	r.mu.Unlock()
+	if ok && c.now().Before(rules.expires) {
+		return rules, nil
+	}
+
+	rules, err := fetchRobots(ctx, c, req, host)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.rules[host] = rules
+	r.mu.Unlock()
+
+	return rules, nil
+}
+
+// wait until crawl delay passed since the last request to the host
+func (r *robotsChecker) wait(ctx context.Context, host string, delay time.Duration) error {
+	if delay <= 0 {
+		return nil
+	}
+
+	// reserve the next request slot of the host
+	r.mu.Lock()
+	now := time.Now()
+	next := r.lastSent[host].Add(delay)
+	if next.Before(now) {
+		next = now
+	}
+	r.lastSent[host] = next
+	r.mu.Unlock()
+
+	t := time.NewTimer(next.Sub(now))
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// fetchRobots fetch and parse robots.txt file of the host. According to RFC 9309, if robots.txt file is not
+// available (HTTP 4xx) the crawler may access any resource, and if it is unreachable (HTTP 5xx) the crawler must
+// assume complete disallow. Transport errors are returned as ErrDNS or ErrRequest of the robots.txt URL, as errors of
+// Ads.txt requests
+func fetchRobots(ctx context.Context, c *Crawler, req *Request, host string) (*robotsRules, error) {
+	robotsURL := host + "/robots.txt"
+	httpRequest, err := http.NewRequestWithContext(ctx, "GET", robotsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	httpRequest.Header.Add("User-Agent", c.userAgent)
+	httpRequest.Header.Add("Accept", "text/plain")
+
+	// robots.txt redirects are followed (unlike Ads.txt redirects that follow Ads.txt specification rules)
+	client := *c.client
+	client.CheckRedirect = nil
+
+	res, err := client.Do(httpRequest)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) {
+			return nil, &ErrDNS{Host: dnsErr.Name, URL: robotsURL, Err: err}
+		}
+		return nil, &ErrRequest{URL: robotsURL, Err: c.timeoutError(ctx, req, err)}
+	}
+	defer res.Body.Close()
+
+	now := c.now()
+	switch {
+	case res.StatusCode == http.StatusOK:
+		rules := parseRobots(io.LimitReader(res.Body, robotsMaxSize), c.userAgent)
+		rules.expires = now.Add(robotsCacheTTL)
+		return rules, nil
+	case 400 <= res.StatusCode && res.StatusCode < 500:
+		return &robotsRules{expires: now.Add(robotsCacheTTL)}, nil
+	default:
+		return &robotsRules{disallow: []string{"/"}, expires: now.Add(robotsUnreachableTTL)}, nil
+	}
+}
+
+// parseRobots parse robots.txt rules that apply to the User-Agent: rules of the most specific matching User-Agent
+// group, or rules of "*" group if no group matches
+func parseRobots(r io.Reader, userAgent string) *robotsRules {
+	ua := strings.ToLower(userAgent)
+
+	matched, wildcard := &robotsRules{}, &robotsRules{}
+	matchedLen := 0
+
+	// current group rules: groups start with one or more User-Agent lines
+	var group []*robotsRules
+	inAgents := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i != -1 {
+			line = line[:i]
+		}
+
+		i := strings.Index(line, ":")
+		if i == -1 {
+			continue
+		}
+		key := strings.ToLower(strings.TrimSpace(line[:i]))
+		value := strings.TrimSpace(line[i+1:])
+
+		switch key {
+		case "user-agent":
+			if !inAgents {
+				group = nil
+				inAgents = true
+			}
+			agent := strings.ToLower(value)
+			switch {
+			case agent == "*":
+				group = append(group, wildcard)
+			case len(agent) > 0 && strings.Contains(ua, agent) && len(agent) >= matchedLen:
+				if len(agent) > matchedLen {
+					matched = &robotsRules{}
+					matchedLen = len(agent)
+				}
+				group = append(group, matched)
+			}
+		case "allow", "disallow", "crawl-delay":
+			inAgents = false
+			for _, g := range group {
+				switch key {
+				case "allow":
+					if len(value) > 0 {
+						g.allow = append(g.allow, value)
+					}
+				case "disallow":
+					if len(value) > 0 {
+						g.disallow = append(g.disallow, value)
+					}
+				case "crawl-delay":
+					if d, err := strconv.ParseFloat(value, 64); err == nil && d > 0 {
+						g.crawlDelay = time.Duration(d * float64(time.Second))
+					}
+				}
+			}
+		}
+	}
+
+	if matchedLen > 0 {
+		return matched
+	}
+	return wildcard
+}
+
+// allowed check if path is allowed by robots.txt rules: the most specific (longest) matching rule is used, and
+// allow rule is used if allow and disallow rules are equally specific
+func (r *robotsRules) allowed(path string) bool {
+	allowLen, disallowLen := -1, -1
+
+	for _, p := range r.allow {
+		if robotsMatch(p, path) && len(p) > allowLen {
+			allowLen = len(p)
+		}
+	}
+	for _, p := range r.disallow {
+		if robotsMatch(p, path) && len(p) > disallowLen {
+			disallowLen = len(p)
+		}
+	}
+
+	return disallowLen == -1 || allowLen >= disallowLen
+}
+
+// robotsMatch check if path matches robots.txt path pattern: "*" matches any sequence of characters and "$" matches
+// the end of the path
+func robotsMatch(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+
+	parts := strings.Split(pattern, "*")
+
+	// first part must be a prefix of the path
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	rest := path[len(parts[0]):]
+
+	for index, part := range parts[1:] {
+		// last part of anchored pattern must match the end of the path
+		if anchored && index == len(parts)-2 {
+			return strings.HasSuffix(rest, part)
+		}
+		i := strings.Index(rest, part)
+		if i == -1 {
+			return false
+		}
+		rest = rest[i+len(part):]
+	}
+
+	return !anchored || len(rest) == 0
+}
@@ -0,0 +1,150 @@
+package adstxt
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestParseRobots test parsing robots.txt rules for crawler User-Agent
+func TestParseRobots(t *testing.T) {
+	robots := `# robots.txt
+User-agent: *
+Disallow: /
+Allow: /ads.txt
+
+User-agent: other-bot
+User-agent: adstxt-crawler
+Disallow: /ads.txt
+Crawl-delay: 1.5
+`
+
+	// crawler User-Agent matches specific group
+	rules := parseRobots(strings.NewReader(robots), "adstxt-crawler/1.0")
+	if rules.allowed("/ads.txt") {
+		t.Errorf("Expected [/ads.txt] to be disallowed for [adstxt-crawler]")
+	}
+	if !rules.allowed("/index.html") {
+		t.Errorf("Expected [/index.html] to be allowed for [adstxt-crawler]")
+	}
+	if rules.crawlDelay != 1500*time.Millisecond {
+		t.Errorf("Expected crawl delay to be [1.5s] and not [%s]", rules.crawlDelay)
+	}
+
+	// other User-Agent uses "*" group
+	rules = parseRobots(strings.NewReader(robots), "test-bot")
+	if !rules.allowed("/ads.txt") {
+		t.Errorf("Expected [/ads.txt] to be allowed for [test-bot]")
+	}
+	if rules.allowed("/index.html") {
+		t.Errorf("Expected [/index.html] to be disallowed for [test-bot]")
+	}
+}
+
+// TestRobotsMatch test robots.txt path patterns
+func TestRobotsMatch(t *testing.T) {
+	patterns := map[string]map[string]bool{
+		"/":          {"/ads.txt": true, "/a/b": true},
+		"/ads":       {"/ads.txt": true, "/app-ads.txt": false},
+		"/*.txt":     {"/ads.txt": true, "/a/b.txt.bak": true, "/a/b.html": false},
+		"/*.txt$":    {"/ads.txt": true, "/a/b.txt.bak": false},
+		"/ads.txt$":  {"/ads.txt": true, "/ads.txt?x": false},
+		"/private/*": {"/private/ads.txt": true, "/ads.txt": false},
+	}
+
+	for pattern, paths := range patterns {
+		for path, expected := range paths {
+			if robotsMatch(pattern, path) != expected {
+				t.Errorf("Expected pattern [%s] match for path [%s] to be [%t]", pattern, path, expected)
+			}
+		}
+	}
+}
+
+// TestCrawlerRobotsTxt test crawler respects robots.txt file of remote host
+func TestCrawlerRobotsTxt(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		if r.URL.Path == "/robots.txt" {
+			io.WriteString(w, "User-agent: *\nDisallow: /private/\n")
+			return
+		}
+		io.WriteString(w, "greenadexchange.com,XF7342,DIRECT")
+	}))
+	defer ts.Close()
+
+	c := NewCrawler(WithRobotsTxt(true))
+
+	if _, err := c.Get(&Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"}); err != nil {
+		t.Error(err)
+	}
+
+	_, err := c.Get(&Request{URL: ts.URL + "/private/ads.txt", Domain: "127.0.0.1"})
+	if _, ok := err.(*ErrRobotsDisallowed); !ok {
+		t.Errorf("Expected request to be disallowed by robots.txt and not [%v]", err)
+	}
+}
+
+// TestRobotsTxtExpiration test unreachable robots.txt file complete disallow is cached only until it expires
+func TestRobotsTxtExpiration(t *testing.T) {
+	var robotsRequests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		if r.URL.Path == "/robots.txt" {
+			// robots.txt file is temporarily unavailable on the first request
+			if robotsRequests.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			io.WriteString(w, "User-agent: *\nDisallow: /private/\n")
+			return
+		}
+		io.WriteString(w, "greenadexchange.com,XF7342,DIRECT")
+	}))
+	defer ts.Close()
+
+	clock := &testClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	c := NewCrawler(WithRobotsTxt(true), WithClock(clock))
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.Get(&Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"}); !errors.As(err, new(*ErrRobotsDisallowed)) {
+			t.Errorf("Expected complete disallow of unreachable robots.txt and not [%v]", err)
+		}
+	}
+	if n := robotsRequests.Load(); n != 1 {
+		t.Errorf("Expected unreachable robots.txt file to be cached and not fetched [%d] times", n)
+	}
+
+	clock.advance(robotsUnreachableTTL)
+	if _, err := c.Get(&Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"}); err != nil {
+		t.Errorf("Expected robots.txt to be fetched again once expired and not [%v]", err)
+	}
+
+	// robots.txt rules are fetched again after 24 hours
+	clock.advance(robotsCacheTTL - time.Second)
+	c.Get(&Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"})
+	clock.advance(time.Second)
+	c.Get(&Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"})
+	if n := robotsRequests.Load(); n != 3 {
+		t.Errorf("Expected robots.txt rules to expire after 24 hours and not [%d] fetches", n)
+	}
+}
+
+// TestRobotsTxtRequestError test robots.txt transport errors are returned as request errors
+func TestRobotsTxtRequestError(t *testing.T) {
+	client := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	})}
+	c := NewCrawler(WithRobotsTxt(true), WithHTTPClient(client))
+
+	req, _ := NewRequest("example.com")
+	var reqErr *ErrRequest
+	if _, err := c.Get(req); !errors.As(err, &reqErr) || !strings.HasSuffix(reqErr.URL, "/robots.txt") {
+		t.Errorf("Expected request error of robots.txt URL and not [%v]", err)
+	}
+}
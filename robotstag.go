@@ -0,0 +1,105 @@
+package adstxt
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// ErrStoragePolicy Ads.txt response was not saved since the store policy does not allow storing it (see PolicyStore)
+var ErrStoragePolicy = errors.New("response storage not allowed by policy")
+
+// X-Robots-Tag directives that signal the Ads.txt file should not be indexed or archived
+const (
+	RobotsNoIndex   = "noindex"
+	RobotsNoArchive = "noarchive"
+	RobotsNone      = "none"
+)
+
+// robotsTagParams X-Robots-Tag directives with value (e.g. "max-snippet: 20"), that are not User-Agent prefixes
+var robotsTagParams = map[string]bool{
+	"unavailable_after": true,
+	"max-snippet":       true,
+	"max-image-preview": true,
+	"max-video-preview": true,
+}
+
+// robotsTagDirectives return the lower case directives of X-Robots-Tag response headers that apply to the
+// User-Agent: directives of header values without User-Agent prefix, or with prefix that matches the User-Agent (for
+// example "adstxt-crawler: noarchive")
+func robotsTagDirectives(h http.Header, userAgent string) []string {
+	ua := strings.ToLower(userAgent)
+
+	directives := []string{}
+	seen := map[string]bool{}
+	for _, value := range h.Values("X-Robots-Tag") {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if i := strings.Index(value, ":"); i != -1 {
+			agent := strings.TrimSpace(value[:i])
+			if !robotsTagParams[agent] && !strings.Contains(agent, ",") {
+				if len(agent) == 0 || !strings.Contains(ua, agent) {
+					continue
+				}
+				value = value[i+1:]
+			}
+		}
+
+		for _, d := range strings.Split(value, ",") {
+			d = strings.TrimSpace(d)
+			if len(d) == 0 || seen[d] {
+				continue
+			}
+			seen[d] = true
+			directives = append(directives, d)
+		}
+	}
+	return directives
+}
+
+// NoArchive return true if the remote host signaled that the Ads.txt file should not be archived: X-Robots-Tag
+// noarchive, noindex or none directive (see Response.RobotsTag), or Cache-Control no-store response header
+func (r *Response) NoArchive() bool {
+	for _, d := range r.RobotsTag {
+		if d == RobotsNoArchive || d == RobotsNoIndex || d == RobotsNone {
+			return true
+		}
+	}
+	for _, v := range r.Header.Values("Cache-Control") {
+		for _, d := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(d), "no-store") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// StoragePolicy return true if Ads.txt response may be stored (see PolicyStore)
+type StoragePolicy func(res *Response) bool
+
+// RespectNoArchive StoragePolicy that does not allow storing Ads.txt responses whose remote host signaled that the
+// file should not be archived (see Response.NoArchive)
+func RespectNoArchive(res *Response) bool {
+	return !res.NoArchive()
+}
+
+// PolicyStore Store that saves only Ads.txt responses allowed by its storage policy, for operators with data
+// retention requirements. Saving response that is not allowed returns ErrStoragePolicy, and any response previously
+// saved with the same key is kept. Archive and Watcher skip responses that are not allowed
+type PolicyStore struct {
+	Store
+	policy StoragePolicy
+}
+
+// NewPolicyStore create new PolicyStore that saves responses allowed by policy to store
+func NewPolicyStore(store Store, policy StoragePolicy) *PolicyStore {
+	return &PolicyStore{Store: store, policy: policy}
+}
+
+// SaveResponse is the Store interface implementation for PolicyStore
+func (s *PolicyStore) SaveResponse(key string, res *Response) error {
+	if !s.policy(res) {
+		return ErrStoragePolicy
+	}
+	return s.Store.SaveResponse(key, res)
+}
@@ -0,0 +1,75 @@
+package adstxt
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// TestRobotsTag test crawler capture X-Robots-Tag directives that apply to the crawler User-Agent
+func TestRobotsTag(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Add("X-Robots-Tag", "otherbot: noindex")
+		w.Header().Add("X-Robots-Tag", "adstxt-test: NoArchive")
+		w.Header().Add("X-Robots-Tag", "nofollow, unavailable_after: 25 Jun 2030")
+		fmt.Fprint(w, "greenadexchange.com,XF7342,DIRECT")
+	}))
+	defer ts.Close()
+
+	c := NewCrawler(WithUserAgent("adstxt-test/1.0"))
+	res, err := c.Get(&Request{URL: ts.URL + "/ads.txt", Domain: "localhost"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"noarchive", "nofollow", "unavailable_after: 25 jun 2030"}
+	if !reflect.DeepEqual(res.RobotsTag, expected) {
+		t.Errorf("Expected robots tag directives %v and not %v", expected, res.RobotsTag)
+	}
+	if !res.NoArchive() {
+		t.Errorf("Expected noarchive response")
+	}
+
+	// Cache-Control no-store signals the response should not be archived
+	res = &Response{Header: http.Header{"Cache-Control": []string{"private, No-Store"}}}
+	if !res.NoArchive() {
+		t.Errorf("Expected Cache-Control no-store response not to be archived")
+	}
+	if res = (&Response{Header: http.Header{}}); res.NoArchive() {
+		t.Errorf("Expected response without robots directives to be archived")
+	}
+}
+
+// TestPolicyStore test archive skip responses that are not allowed by the store policy
+func TestPolicyStore(t *testing.T) {
+	fs, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := NewPolicyStore(fs, RespectNoArchive)
+	archive := NewArchive(store)
+
+	res, _ := NewResponse(&Request{URL: "https://example.com/ads.txt", Domain: "example.com"}, []byte("greenadexchange.com,XF7342,DIRECT"))
+	if err := store.SaveResponse("denied", &Response{Header: http.Header{}, RobotsTag: []string{RobotsNone}}); err != ErrStoragePolicy {
+		t.Errorf("Expected storage policy error and not [%v]", err)
+	}
+
+	res.RobotsTag = []string{RobotsNoIndex}
+	if err := archive.Save(res.URL, res); err != nil {
+		t.Fatalf("Expected archive to skip response [%s]", err)
+	}
+	if n, _ := archive.Versions(res.URL); n != 0 {
+		t.Errorf("Expected noindex response not to be archived and not [%d] versions", n)
+	}
+
+	res.RobotsTag = nil
+	if err := archive.Save(res.URL, res); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := archive.Versions(res.URL); n != 1 {
+		t.Errorf("Expected single archived version and not [%d]", n)
+	}
+}
@@ -0,0 +1,188 @@
+package adstxt
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Scheduler settings default values
+const (
+	schedulerJitter        = 5 * time.Minute
+	schedulerErrorInterval = time.Hour
+	schedulerMinInterval   = time.Minute
+)
+
+// Scheduler crawl Ads.txt files of a set of domains and automatically re-crawl each Ads.txt file once its response
+// expires (based on response Expires, by the crawler clock, see WithClock), turning the crawler into Ads.txt
+// monitoring component. Re-crawls are sent as conditional requests (see Response.Conditional), so unchanged Ads.txt
+// files are passed to the handler as NotModified responses. Each crawl result is passed to the handler, that may be
+// called from multiple goroutines. Settings must be set before Run: the domains and settings of running Scheduler are
+// changed with Reload
+type Scheduler struct {
+	Jitter        time.Duration // Jitter maximum random delay added to each crawl, to spread load on remote hosts
+	ErrorInterval time.Duration // ErrorInterval delay before re-crawling Ads.txt file that failed to be crawled
+	MinInterval   time.Duration // MinInterval minimum delay between crawls of the same Ads.txt file
+
+	crawler  *Crawler
+	requests []*Request
+	h        Handler
+	waiting  atomic.Int64 // number of due crawls waiting for free request slot
+
+	mu       sync.Mutex                    // guards requests and settings, and the running monitors
+	ctx      context.Context               // context of running Scheduler, nil if the Scheduler is not running
+	guard    chan struct{}                 // request slots of running Scheduler
+	wg       sync.WaitGroup                // running monitors
+	monitors map[string]context.CancelFunc // cancel functions of running monitors, by request Ads.txt URL
+}
+
+// NewScheduler create new Scheduler that use crawler c to crawl Ads.txt files of the specified domains, and pass
+// each crawl result to h
+func NewScheduler(c *Crawler, domains []string, h Handler) (*Scheduler, error) {
+	requests := make([]*Request, 0, len(domains))
+	for _, d := range domains {
+		req, err := NewRequest(d)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, req)
+	}
+
+	return NewRequestScheduler(c, requests, h), nil
+}
+
+// NewRequestScheduler create new Scheduler that use crawler c to crawl the specified Ads.txt requests, and pass each
+// crawl result to h
+func NewRequestScheduler(c *Crawler, req []*Request, h Handler) *Scheduler {
+	return &Scheduler{
+		Jitter:        schedulerJitter,
+		ErrorInterval: schedulerErrorInterval,
+		MinInterval:   schedulerMinInterval,
+		crawler:       c,
+		requests:      req,
+		h:             h,
+	}
+}
+
+// Run crawl all Scheduler Ads.txt files, and keep re-crawling them as they expire until ctx is done. First crawl of
+// each Ads.txt file is delayed by random jitter as well. The number of parallel requests is limited by the crawler
+// concurrency (see WithConcurrency). Run blocks until ctx is done, even if the Scheduler has no Ads.txt files (they
+// may be added by Reload), and return the context error
+func (s *Scheduler) Run(ctx context.Context) error {
+	s.mu.Lock()
+	s.ctx = ctx
+	s.guard = make(chan struct{}, s.crawler.concurrency)
+	s.monitors = map[string]context.CancelFunc{}
+	for _, req := range s.requests {
+		s.start(req)
+	}
+	s.mu.Unlock()
+
+	<-ctx.Done()
+	s.mu.Lock()
+	s.ctx, s.monitors = nil, nil
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return ctx.Err()
+}
+
+// start monitor of request Ads.txt file, unless the Ads.txt file is already monitored: must be called with the
+// Scheduler lock held, while the Scheduler runs
+func (s *Scheduler) start(req *Request) {
+	if _, ok := s.monitors[req.URL]; ok {
+		return
+	}
+	ctx, cancel := context.WithCancel(s.ctx)
+	s.monitors[req.URL] = cancel
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer cancel()
+		s.monitor(ctx, req, s.guard)
+	}()
+}
+
+// monitor crawl single Ads.txt file each time its response expires, until ctx is done
+func (s *Scheduler) monitor(ctx context.Context, req *Request, guard chan struct{}) {
+	timer := s.crawler.clock.NewTimer(s.jitter())
+	defer timer.Stop()
+
+	// last successful response, used to send conditional requests on re-crawl
+	var last *Response
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C():
+		}
+
+		s.waiting.Add(1)
+		select {
+		case guard <- struct{}{}:
+			s.waiting.Add(-1)
+		case <-ctx.Done():
+			s.waiting.Add(-1)
+			return
+		}
+
+		// request URL is changed when following redirects: crawl copy of the original request
+		r := *req
+		if last != nil {
+			r = *last.Conditional(req)
+		}
+		res, err := s.crawler.GetWithContext(ctx, &r)
+		<-guard
+
+		// do not pass results of requests aborted by Scheduler shutdown, or by removal of the Ads.txt file (see Reload)
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			last = res
+		}
+		s.h.Handle(&r, res, err)
+
+		timer.Reset(s.next(res, err))
+	}
+}
+
+// QueueDepth return the number of Ads.txt files that are due to be crawled, and are waiting for free request slot
+// since the crawler concurrency is reached (see WithConcurrency)
+func (s *Scheduler) QueueDepth() int {
+	return int(s.waiting.Load())
+}
+
+// next return the delay before next crawl of Ads.txt file, based on the last crawl result
+func (s *Scheduler) next(res *Response, err error) time.Duration {
+	s.mu.Lock()
+	d, min, jitter := s.ErrorInterval, s.MinInterval, s.Jitter
+	s.mu.Unlock()
+
+	if err == nil && res != nil {
+		d = res.Expires.Sub(s.crawler.now())
+	}
+
+	if d < min {
+		d = min
+	}
+	return d + randomJitter(jitter)
+}
+
+// jitter return random delay up to Scheduler jitter
+func (s *Scheduler) jitter() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return randomJitter(s.Jitter)
+}
+
+// randomJitter return random delay up to jitter
+func randomJitter(jitter time.Duration) time.Duration {
+	if jitter <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(jitter)))
+}
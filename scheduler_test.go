@@ -0,0 +1,126 @@
+package adstxt
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestScheduler test Scheduler re-crawl Ads.txt files once their response expires
+func TestScheduler(t *testing.T) {
+	var mu sync.Mutex
+	crawls := map[string]int{}
+
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			header := http.Header{"Content-Type": []string{"text/plain"}}
+			// test.com Ads.txt file is already expired
+			if req.URL.Host == "test.com" {
+				header.Set("Expires", time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat))
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     header,
+				Body:       io.NopCloser(strings.NewReader("greenadexchange.com,XF7342,DIRECT")),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	s, err := NewScheduler(NewCrawler(WithHTTPClient(client)), []string{"example.com", "test.com"}, HandlerFunc(func(req *Request, res *Response, err error) {
+		if err != nil {
+			t.Error(err)
+		}
+		mu.Lock()
+		crawls[req.Domain]++
+		mu.Unlock()
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Jitter = 0
+	s.MinInterval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := s.Run(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Expected scheduler to stop with context error and not [%v]", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	// example.com Ads.txt file expires in 7 days (default expiration)
+	if crawls["example.com"] != 1 {
+		t.Errorf("Expected example.com Ads.txt to be crawled once and not [%d] times", crawls["example.com"])
+	}
+	if crawls["test.com"] < 3 {
+		t.Errorf("Expected expired test.com Ads.txt to be re-crawled and not [%d] times", crawls["test.com"])
+	}
+}
+
+// TestSchedulerNext test delay before next crawl of Ads.txt file
+func TestSchedulerNext(t *testing.T) {
+	s := NewRequestScheduler(NewCrawler(), nil, nil)
+	s.Jitter = 0
+
+	if d := s.next(nil, io.EOF); d != s.ErrorInterval {
+		t.Errorf("Expected failed crawl to be retried after [%s] and not [%s]", s.ErrorInterval, d)
+	}
+	if d := s.next(&Response{Expires: time.Now().Add(-time.Hour)}, nil); d != s.MinInterval {
+		t.Errorf("Expected expired response to be re-crawled after [%s] and not [%s]", s.MinInterval, d)
+	}
+	if d := s.next(&Response{Expires: time.Now().Add(2 * time.Hour)}, nil); d <= time.Hour || d > 2*time.Hour {
+		t.Errorf("Expected response to be re-crawled once it expires and not after [%s]", d)
+	}
+
+	s.Jitter = time.Minute
+	if d := s.next(&Response{Expires: time.Now().Add(-time.Hour)}, nil); d < s.MinInterval || d >= s.MinInterval+s.Jitter {
+		t.Errorf("Expected jitter to be added up to [%s] and not [%s]", s.Jitter, d-s.MinInterval)
+	}
+}
+
+// TestSchedulerQueueDepth test Scheduler queue depth of Ads.txt files waiting for free request slot
+func TestSchedulerQueueDepth(t *testing.T) {
+	block := make(chan struct{})
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			<-block
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       io.NopCloser(strings.NewReader("greenadexchange.com,XF7342,DIRECT")),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	s, _ := NewScheduler(NewCrawler(WithHTTPClient(client), WithConcurrency(1)), []string{"example.com", "google.com", "test.com"}, HandlerFunc(func(*Request, *Response, error) {}))
+	s.Jitter = 0
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for s.QueueDepth() != 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if d := s.QueueDepth(); d != 2 {
+		t.Errorf("Expected 2 Ads.txt files waiting for free request slot and not [%d]", d)
+	}
+
+	cancel()
+	close(block)
+	<-done
+	if d := s.QueueDepth(); d != 0 {
+		t.Errorf("Expected empty queue once scheduler stopped and not [%d]", d)
+	}
+}
@@ -0,0 +1,131 @@
+package adstxt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// maxPort highest TCP port number
+const maxPort = 65535
+
+// SetPort override the port of remote host (for example staging server on port 8443), and update the request URL
+// accordingly. Zero port restores the default port of the URL scheme
+func (r *Request) SetPort(port int) error {
+	if port < 0 || port > maxPort {
+		return fmt.Errorf(errInvalidRequestPort, port)
+	}
+
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return err
+	}
+	u.Host = joinHostPort(u.Hostname(), port)
+	r.URL, r.Port = u.String(), port
+	return nil
+}
+
+// applyPort set the port of request URL to the request Port, if set
+func (r *Request) applyPort() error {
+	if r.Port == 0 {
+		return nil
+	}
+	if r.Port < 0 || r.Port > maxPort {
+		return fmt.Errorf(errInvalidRequestPort, r.Port)
+	}
+
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return err
+	}
+	if u.Port() != strconv.Itoa(r.Port) {
+		u.Host = joinHostPort(u.Hostname(), r.Port)
+		r.URL = u.String()
+	}
+	return nil
+}
+
+// setScheme set the scheme of request URL to scheme (http or https)
+func (r *Request) setScheme(scheme string) error {
+	s := strings.ToLower(strings.TrimSpace(scheme))
+	if s != "http" && s != "https" {
+		return fmt.Errorf(errInvalidScheme, scheme)
+	}
+
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return err
+	}
+	u.Scheme = s
+	r.URL = u.String()
+	return nil
+}
+
+// fetchSchemes crawl Ads.txt file over the request schemes in order of preference (see Request Schemes), and try the
+// next scheme if remote host is unreachable over the previous one (see isUnreachableAt). Return the response of the
+// first scheme that reached remote host, or the error of the first scheme if remote host is unreachable over all
+// schemes. Request without schemes is crawled over the scheme of its URL
+func (c *Crawler) fetchSchemes(ctx context.Context, req *Request) (*Response, error) {
+	if len(req.Schemes) == 0 {
+		return c.fetchAlternates(ctx, req)
+	}
+
+	variants := make([]*Request, len(req.Schemes))
+	for i, s := range req.Schemes {
+		r := *req
+		if err := r.setScheme(s); err != nil {
+			return nil, err
+		}
+		variants[i] = &r
+	}
+
+	return c.fetchVariants(ctx, req, variants, func(ctx context.Context, i int, r *Request) (*Response, bool, error) {
+		res, err := c.fetchAlternates(ctx, r)
+		return res, err == nil || res != nil || !isUnreachableAt(err), err
+	})
+}
+
+// isUnreachableAt check if Ads.txt request error means remote host is unreachable over the request scheme: the HTTP
+// request failed without response, for example connection refused or TLS handshake failure. Host name that failed to
+// be resolved is unreachable over any scheme
+func isUnreachableAt(err error) bool {
+	var requestErr *ErrRequest
+	var dnsErr *ErrDNS
+	if errors.As(err, &dnsErr) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	return errors.As(err, &requestErr)
+}
+
+// setEndpoint set the scheme and port of the final Ads.txt URL of response
+func (r *Response) setEndpoint() {
+	u, err := url.Parse(sourceURL(r))
+	if err != nil {
+		return
+	}
+
+	r.FinalScheme = u.Scheme
+	switch port := u.Port(); {
+	case len(port) > 0:
+		r.FinalPort, _ = strconv.Atoi(port)
+	case u.Scheme == "https":
+		r.FinalPort = 443
+	case u.Scheme == "http":
+		r.FinalPort = 80
+	}
+}
+
+// joinHostPort return URL host of host name and port, without port if it is zero
+func joinHostPort(host string, port int) string {
+	if port == 0 {
+		if strings.Contains(host, ":") {
+			return "[" + host + "]"
+		}
+		return host
+	}
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
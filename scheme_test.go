@@ -0,0 +1,78 @@
+package adstxt
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// TestSchemePreference test crawling Ads.txt file over request schemes in order of preference, on non-standard port
+func TestSchemePreference(t *testing.T) {
+	requested := []string{}
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			requested = append(requested, req.URL.String())
+			if req.URL.Scheme == "https" {
+				return nil, errors.New("remote error: tls: handshake failure")
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       io.NopCloser(strings.NewReader("greenadexchange.com,XF7342,DIRECT")),
+				Request:    req,
+			}, nil
+		}),
+	}
+	c := NewCrawler(WithHTTPClient(client))
+
+	req, _ := NewRequest("staging.example.com")
+	req.Schemes = []string{"https", "HTTP"}
+	if err := req.SetPort(8443); err != nil {
+		t.Fatal(err)
+	}
+	if req.URL != "http://staging.example.com:8443/ads.txt" {
+		t.Errorf("Expected request URL with port and not [%s]", req.URL)
+	}
+
+	res, err := c.GetWithContext(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(requested) != 2 || requested[0] != "https://staging.example.com:8443/ads.txt" {
+		t.Errorf("Expected request over https first and not %v", requested)
+	}
+	if res.FinalScheme != "http" || res.FinalPort != 8443 || res.Attempts != 2 {
+		t.Errorf("Expected final scheme [http] and port [8443] after [2] attempts and not [%s] [%d] [%d]", res.FinalScheme, res.FinalPort,
+			res.Attempts)
+	}
+
+	// request port set without SetPort, and default port of the final scheme
+	requested = requested[:0]
+	req, _ = NewRequest("example.com")
+	req.Port = 8080
+	if res, err := c.GetWithContext(context.Background(), req); err != nil || requested[0] != "http://example.com:8080/ads.txt" || res.FinalPort != 8080 {
+		t.Errorf("Expected request on port [8080] and not %v [%v]", requested, err)
+	}
+	req, _ = NewRequest("example.com")
+	if res, err := c.GetWithContext(context.Background(), req); err != nil || res.FinalScheme != "http" || res.FinalPort != 80 {
+		t.Errorf("Expected default port [80] of final scheme and not [%v] [%v]", res, err)
+	}
+
+	// remote host unreachable over all schemes
+	req, _ = NewRequest("example.com")
+	req.Schemes = []string{"https"}
+	if _, err := c.GetWithContext(context.Background(), req); !errors.As(err, new(*ErrRequest)) {
+		t.Errorf("Expected request error of unreachable host and not [%v]", err)
+	}
+
+	req.Schemes = []string{"ftp"}
+	if _, err := c.GetWithContext(context.Background(), req); err == nil {
+		t.Error("Expected invalid scheme error")
+	}
+	if err := req.SetPort(70000); err == nil {
+		t.Error("Expected invalid port error")
+	}
+}
@@ -0,0 +1,164 @@
+package adstxt
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+)
+
+// seed list errors
+const (
+	errSeedInvalidHost   = "[%s] is not a valid domain name"
+	errSeedMissingColumn = "CSV column [%s] not found in header %v"
+)
+
+// SeedError invalid seed found when building Ads.txt requests from seed list
+type SeedError struct {
+	Line int    // Line number of the seed in the seed list (starting from 1)
+	Seed string // Seed invalid seed value
+	Err  error  // Err seed validation error
+}
+
+func (e *SeedError) Error() string {
+	return fmt.Sprintf("line [%d] seed [%s]: %s", e.Line, e.Seed, e.Err)
+}
+
+// Unwrap return the seed validation error
+func (e *SeedError) Unwrap() error {
+	return e.Err
+}
+
+// Seeds Ads.txt requests built from seed list (domains, URLs etc.), in order of the seed list. Invalid seeds are
+// reported in Errors, and duplicate seeds are crawled once
+type Seeds struct {
+	Requests   []*Request   // Requests unique Ads.txt requests, in order of the seed list
+	Errors     []*SeedError // Errors invalid seeds
+	Duplicates int          // Duplicates number of seeds that duplicate previous seeds
+
+	seen map[string]bool // Ads.txt URLs of the seeds added so far
+}
+
+// RequestsFromDomains build Ads.txt requests from list of domains (or Ads.txt URLs), as in NewRequest. Seeds are
+// deduplicated by the request Ads.txt URL, so subdomains are crawled as listed
+func RequestsFromDomains(domains []string) *Seeds {
+	s := newSeeds()
+	for i, d := range domains {
+		s.add(i+1, d, false)
+	}
+	return s
+}
+
+// RequestsFromURLs build Ads.txt requests of the root domains of list of page URLs (for example sitemap URLs). Seeds
+// are deduplicated by root domain, so each site is crawled once
+func RequestsFromURLs(urls []string) *Seeds {
+	s := newSeeds()
+	for i, u := range urls {
+		s.add(i+1, u, true)
+	}
+	return s
+}
+
+// ReadDomains build Ads.txt requests from domains file: single domain per line (see RequestsFromDomains). Empty lines
+// and comment lines (starting with "#") are ignored
+func ReadDomains(r io.Reader) (*Seeds, error) {
+	s := newSeeds()
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		d := strings.TrimSpace(scanner.Text())
+		if len(d) == 0 || strings.HasPrefix(d, commentDenote) {
+			continue
+		}
+		s.add(line, d, false)
+	}
+
+	return s, scanner.Err()
+}
+
+// ReadCSVColumn build Ads.txt requests from the domains listed in column of CSV file with header line (see
+// RequestsFromDomains). Column is matched by its header name, case insensitive
+func ReadCSVColumn(r io.Reader, column string) (*Seeds, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+	index := -1
+	for i, h := range header {
+		if strings.EqualFold(strings.TrimSpace(h), column) {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		return nil, fmt.Errorf(errSeedMissingColumn, column, header)
+	}
+
+	s := newSeeds()
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return s, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := cr.FieldPos(0)
+		if index >= len(record) || len(strings.TrimSpace(record[index])) == 0 {
+			continue
+		}
+		s.add(line, record[index], false)
+	}
+}
+
+// newSeeds create new empty seeds
+func newSeeds() *Seeds {
+	return &Seeds{Requests: []*Request{}, Errors: []*SeedError{}, seen: map[string]bool{}}
+}
+
+// add validate single seed and add its Ads.txt request, unless it duplicates previous seed. Seed of page URL is
+// crawled by its root domain
+func (s *Seeds) add(line int, seed string, root bool) {
+	seed = strings.TrimSpace(seed)
+
+	req, err := newSeedRequest(seed, root)
+	if err != nil {
+		s.Errors = append(s.Errors, &SeedError{Line: line, Seed: seed, Err: err})
+		return
+	}
+
+	if s.seen[req.URL] {
+		s.Duplicates++
+		return
+	}
+	s.seen[req.URL] = true
+	s.Requests = append(s.Requests, req)
+}
+
+// newSeedRequest return Ads.txt request of seed, after validating the seed host name syntax
+func newSeedRequest(seed string, root bool) (*Request, error) {
+	host, err := urlHostname(seed)
+	if err != nil {
+		return nil, err
+	}
+	if net.ParseIP(host) == nil && !isDomainName(host) {
+		return nil, fmt.Errorf(errSeedInvalidHost, host)
+	}
+
+	if root {
+		d, err := rootDomain(seed)
+		if err != nil {
+			return nil, err
+		}
+		return NewRequest(d)
+	}
+	return NewRequest(seed)
+}
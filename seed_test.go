@@ -0,0 +1,78 @@
+package adstxt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestReadDomains test building Ads.txt requests from domains file
+func TestReadDomains(t *testing.T) {
+	seeds, err := ReadDomains(strings.NewReader("example.com\n\n# comment\nshop.example.com\nEXAMPLE.com\nnot a domain\nhttps://test.com/ads.txt\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"http://example.com/ads.txt", "http://shop.example.com/ads.txt", "https://test.com/ads.txt"}
+	if len(seeds.Requests) != len(expected) {
+		t.Fatalf("Expected [%d] requests and not [%d]", len(expected), len(seeds.Requests))
+	}
+	for i, url := range expected {
+		if seeds.Requests[i].URL != url {
+			t.Errorf("Expected request [%s] and not [%s]", url, seeds.Requests[i].URL)
+		}
+	}
+	if seeds.Duplicates != 1 {
+		t.Errorf("Expected [1] duplicate seed and not [%d]", seeds.Duplicates)
+	}
+	if len(seeds.Errors) != 1 || seeds.Errors[0].Line != 6 || seeds.Errors[0].Seed != "not a domain" {
+		t.Errorf("Expected invalid seed at line [6] to be reported and not %v", seeds.Errors)
+	}
+}
+
+// TestReadCSVColumn test building Ads.txt requests from CSV file column
+func TestReadCSVColumn(t *testing.T) {
+	seeds, err := ReadCSVColumn(strings.NewReader("name,Domain\nExample,example.com\nTest,test.com\nEmpty,\nInvalid,bad_domain!.com\n"), "domain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(seeds.Requests) != 2 || seeds.Requests[1].Domain != "test.com" {
+		t.Errorf("Expected [2] requests from CSV column and not [%d]", len(seeds.Requests))
+	}
+	if len(seeds.Errors) != 1 || seeds.Errors[0].Line != 5 {
+		t.Errorf("Expected invalid seed at line [5] to be reported and not %v", seeds.Errors)
+	}
+
+	if _, err := ReadCSVColumn(strings.NewReader("name,site\n"), "domain"); err == nil {
+		t.Errorf("Expected missing CSV column to fail")
+	}
+}
+
+// TestRequestsFromURLs test building Ads.txt requests of page URLs root domains
+func TestRequestsFromURLs(t *testing.T) {
+	seeds := RequestsFromURLs([]string{
+		"https://www.example.com/news/page.html",
+		"https://shop.example.com/",
+		"http://blog.test.co.uk/post?id=1",
+		"://",
+	})
+
+	if len(seeds.Requests) != 2 || seeds.Requests[0].URL != "http://example.com/ads.txt" || seeds.Requests[1].Domain != "test.co.uk" {
+		t.Errorf("Expected root domains requests and not %v", seeds.Requests)
+	}
+	if seeds.Duplicates != 1 {
+		t.Errorf("Expected [1] duplicate root domain and not [%d]", seeds.Duplicates)
+	}
+	if len(seeds.Errors) != 1 || seeds.Errors[0].Line != 4 {
+		t.Errorf("Expected invalid URL to be reported")
+	}
+
+	var seedErr *SeedError
+	if !errors.As(error(seeds.Errors[0]), &seedErr) || len(seedErr.Error()) == 0 {
+		t.Errorf("Expected seed error message")
+	}
+
+	if seeds := RequestsFromDomains([]string{"example.com", "example.com"}); len(seeds.Requests) != 1 || seeds.Duplicates != 1 {
+		t.Errorf("Expected duplicate domains to be crawled once")
+	}
+}
@@ -0,0 +1,67 @@
+package sellersjson
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tzafrirben/go-adstxt-crawler/adstxt"
+)
+
+// EnrichedRecord Ads.txt data record annotated with the metadata of its seller in the advertising system
+// sellers.json file, for transparency reports. Seller metadata is empty if the seller was not found, or if the
+// sellers.json file is not available
+type EnrichedRecord struct {
+	Record         *adstxt.DataRecord `json:"record"`               // Record Ads.txt data record
+	Found          bool               `json:"found"`                // Found seller ID was found in sellers.json
+	Name           string             `json:"name,omitempty"`       // Name of the company paid for inventory, empty for confidential seller
+	Domain         string             `json:"domain,omitempty"`     // Domain business domain name of the seller, empty for confidential seller
+	SellerType     string             `json:"sellerType,omitempty"` // SellerType PUBLISHER, INTERMEDIARY or BOTH
+	IsConfidential bool               `json:"isConfidential"`       // IsConfidential seller identity is confidential
+	Comment        string             `json:"comment,omitempty"`    // Comment description of the seller
+	Error          string             `json:"error,omitempty"`      // Error sellers.json of the advertising system could not be fetched or parsed
+}
+
+// Enrich annotate each data record of Ads.txt records with the metadata of its seller, using sellers.json files by
+// lower case advertising system domain. Return enriched record for each data record, in Ads.txt records order
+func Enrich(records *adstxt.Records, sellers map[string]*SellersJSON) []*EnrichedRecord {
+	return enrich(records, func(domain string) (*SellersJSON, error) {
+		if s, ok := sellers[domain]; ok && s != nil {
+			return s, nil
+		}
+		return nil, fmt.Errorf("sellers.json of [%s] is not available", domain)
+	})
+}
+
+// Enrich annotate each data record of Ads.txt records with the metadata of its seller (see Enrich), and fetch the
+// sellers.json file of each advertising system declared in Ads.txt records. Each sellers.json file is fetched once
+// per enrichment, or once across enrichments with shared Fetcher
+func (v *Validator) Enrich(ctx context.Context, records *adstxt.Records) []*EnrichedRecord {
+	return enrich(records, v.sellers(ctx))
+}
+
+// enrich annotate each data record in order, using sellers to get the sellers.json file of each advertising system
+// by its lower case domain
+func enrich(records *adstxt.Records, sellers func(domain string) (*SellersJSON, error)) []*EnrichedRecord {
+	enriched := make([]*EnrichedRecord, 0, len(records.DataRecords))
+	for _, r := range records.DataRecords {
+		e := &EnrichedRecord{Record: r}
+		enriched = append(enriched, e)
+
+		s, err := sellers(strings.ToLower(strings.TrimSpace(r.AdverterDomain)))
+		if err != nil {
+			e.Error = err.Error()
+			continue
+		}
+
+		seller := s.Seller(strings.TrimSpace(r.PublisherAccountID))
+		if seller == nil {
+			continue
+		}
+		e.Found = true
+		e.Name, e.Domain, e.Comment = seller.Name, seller.Domain, seller.Comment
+		e.SellerType = strings.ToUpper(seller.SellerType)
+		e.IsConfidential = seller.IsConfidential == 1
+	}
+	return enriched
+}
@@ -0,0 +1,63 @@
+package sellersjson
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tzafrirben/go-adstxt-crawler/adstxt"
+)
+
+// TestEnrich test annotating Ads.txt data records with sellers.json seller metadata
+func TestEnrich(t *testing.T) {
+	s, _ := Parse([]byte(testSellersJSON))
+	records, _ := adstxt.ParseBody([]byte("Google.com,XF7342,DIRECT\ngoogle.com,999,RESELLER\ngoogle.com,unknown,DIRECT\nopenx.com,540,RESELLER"))
+
+	enriched := Enrich(records, map[string]*SellersJSON{"google.com": s})
+	if len(enriched) != 4 {
+		t.Fatalf("Expected [4] enriched records and not [%d]", len(enriched))
+	}
+
+	if e := enriched[0]; !e.Found || e.Name != "Example Publisher" || e.Domain != "example.com" || e.SellerType != SellerTypePublisher || e.IsConfidential {
+		t.Errorf("Unexpected enriched record [%+v]", e)
+	}
+	if e := enriched[1]; !e.Found || !e.IsConfidential || len(e.Name) != 0 || e.SellerType != SellerTypeBoth {
+		t.Errorf("Expected confidential seller and not [%+v]", e)
+	}
+	if e := enriched[2]; e.Found || len(e.Error) != 0 {
+		t.Errorf("Expected seller not to be found and not [%+v]", e)
+	}
+	if e := enriched[3]; e.Found || len(e.Error) == 0 {
+		t.Errorf("Expected missing sellers.json error and not [%+v]", e)
+	}
+	if enriched[0].Record != records.DataRecords[0] {
+		t.Error("Expected enriched record to hold Ads.txt data record")
+	}
+}
+
+// TestValidatorEnrich test enriching Ads.txt data records with fetched sellers.json files
+func TestValidatorEnrich(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Host != "google.com" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, testSellersJSON)
+	}))
+	defer ts.Close()
+
+	records, _ := adstxt.ParseBody([]byte("google.com,XF7342,DIRECT\ngoogle.com,185,RESELLER\nopenx.com,540,RESELLER"))
+	enriched := NewValidator(testClient(ts)).Enrich(context.Background(), records)
+
+	if len(enriched) != 3 || !enriched[0].Found || enriched[1].Name != "Example Reseller" || len(enriched[2].Error) == 0 {
+		t.Errorf("Unexpected enriched records %+v", enriched)
+	}
+	if requests != 2 {
+		t.Errorf("Expected each sellers.json to be fetched once and not [%d] requests", requests)
+	}
+}
@@ -0,0 +1,147 @@
+package sellersjson
+
+import (
+	"container/list"
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Fetcher shared cache of fetched sellers.json files, by lower case advertising system domain, for batch validation
+// of many publishers: each sellers.json file is fetched once and shared by all validations (see Validator.Fetcher),
+// and concurrent requests of the same file wait for a single fetch. The cache is size-aware: once the total size of
+// cached files exceeds the size limit, the least recently used files are evicted. Failed fetches are not cached.
+// Fetcher is safe to use from multiple goroutines
+type Fetcher struct {
+	Client *http.Client // Client HTTP client used to fetch sellers.json files (default client is used if nil)
+
+	maxBytes int64         // maximum total size of cached files (no limit if zero)
+	ttl      time.Duration // time until cached file expires (cached until evicted if zero)
+	entries  map[string]*fetcherEntry
+	lru      *list.List // cached domains, most recently used first
+	size     int64      // total size of cached files
+	mu       sync.Mutex
+}
+
+// fetcherEntry single cached, or in-flight, sellers.json file
+type fetcherEntry struct {
+	domain  string
+	done    chan struct{} // closed once the fetch is completed
+	sellers *SellersJSON
+	err     error
+	size    int64
+	expires time.Time
+	elem    *list.Element // LRU element, nil until the file is cached
+}
+
+// NewFetcher create new Fetcher that caches up to maxBytes of sellers.json files (no limit if zero) for ttl (until
+// evicted if zero). File that is larger than maxBytes by itself is returned, but not cached
+func NewFetcher(client *http.Client, maxBytes int64, ttl time.Duration) *Fetcher {
+	return &Fetcher{Client: client, maxBytes: maxBytes, ttl: ttl, entries: map[string]*fetcherEntry{}, lru: list.New()}
+}
+
+// Get return the sellers.json file of an advertising system domain, from the cache or fetched from the advertising
+// system. Concurrent calls of the same domain share a single fetch
+func (f *Fetcher) Get(ctx context.Context, domain string) (*SellersJSON, error) {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+
+	f.mu.Lock()
+	e, ok := f.entries[domain]
+	if ok && e.elem != nil && !e.expires.IsZero() && time.Now().After(e.expires) {
+		f.remove(e)
+		ok = false
+	}
+	if ok {
+		if e.elem != nil {
+			f.lru.MoveToFront(e.elem)
+		}
+		f.mu.Unlock()
+
+		select {
+		case <-e.done:
+			return e.sellers, e.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	e = &fetcherEntry{domain: domain, done: make(chan struct{})}
+	f.entries[domain] = e
+	f.mu.Unlock()
+
+	e.sellers, e.size, e.err = fetch(ctx, f.Client, URL(domain))
+	close(e.done)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if e.err != nil || (f.maxBytes > 0 && e.size > f.maxBytes) {
+		delete(f.entries, domain)
+		return e.sellers, e.err
+	}
+	if f.ttl > 0 {
+		e.expires = time.Now().Add(f.ttl)
+	}
+	e.elem = f.lru.PushFront(e)
+	f.size += e.size
+	for f.maxBytes > 0 && f.size > f.maxBytes {
+		f.remove(f.lru.Back().Value.(*fetcherEntry))
+	}
+	return e.sellers, nil
+}
+
+// Prefetch fetch the sellers.json files of advertising system domains in parallel, with up to concurrency fetches at
+// the same time, so the files are cached before they are validated against. Return the fetch error of each domain
+// that failed to be fetched, by lower case domain
+func (f *Fetcher) Prefetch(ctx context.Context, domains []string, concurrency int) map[string]error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	errs := map[string]error{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	guard := make(chan struct{}, concurrency)
+	for _, d := range domains {
+		guard <- struct{}{}
+		wg.Add(1)
+		go func(domain string) {
+			defer func() {
+				<-guard
+				wg.Done()
+			}()
+			if _, err := f.Get(ctx, domain); err != nil {
+				mu.Lock()
+				errs[strings.ToLower(strings.TrimSpace(domain))] = err
+				mu.Unlock()
+			}
+		}(d)
+	}
+	wg.Wait()
+	return errs
+}
+
+// Len return the number of cached sellers.json files
+func (f *Fetcher) Len() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.lru.Len()
+}
+
+// Size return the total size in bytes of cached sellers.json files
+func (f *Fetcher) Size() int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.size
+}
+
+// remove cached entry, must be called with the fetcher lock held
+func (f *Fetcher) remove(e *fetcherEntry) {
+	delete(f.entries, e.domain)
+	f.lru.Remove(e.elem)
+	f.size -= e.size
+}
@@ -0,0 +1,105 @@
+package sellersjson
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/tzafrirben/go-adstxt-crawler/adstxt"
+)
+
+// TestFetcher test shared sellers.json cache fetch each file once, and evict files once the size limit is exceeded
+func TestFetcher(t *testing.T) {
+	fetches := map[string]int{}
+	var mu sync.Mutex
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		fetches[r.Host]++
+		mu.Unlock()
+		if r.Host == "missing.com" {
+			http.NotFound(w, r)
+			return
+		}
+		io.WriteString(w, testSellersJSON)
+	}))
+	defer ts.Close()
+
+	f := NewFetcher(testClient(ts), int64(len(testSellersJSON))+1, 0)
+
+	// concurrent requests of the same file share a single fetch
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if s, err := f.Get(context.Background(), "Google.com"); err != nil || s.Seller("XF7342") == nil {
+				t.Errorf("Expected cached sellers.json of [google.com] [%v]", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// validations share the cached file
+	records, _ := adstxt.ParseBody([]byte("google.com, XF7342, DIRECT\ngoogle.com, 185, RESELLER"))
+	v := &Validator{Fetcher: f}
+	for i := 0; i < 2; i++ {
+		if report := v.Validate(context.Background(), records); !report.Valid() {
+			t.Errorf("Expected valid records of cached sellers.json")
+		}
+	}
+	if fetches["google.com"] != 1 || f.Len() != 1 || f.Size() != int64(len(testSellersJSON)) {
+		t.Errorf("Expected sellers.json of [google.com] to be fetched once and not [%d] times", fetches["google.com"])
+	}
+
+	// cache size limit allows single file: the least recently used file is evicted
+	errs := f.Prefetch(context.Background(), []string{"openx.com", "missing.com"}, 2)
+	if len(errs) != 1 || errs["missing.com"] == nil {
+		t.Errorf("Expected prefetch error of [missing.com] and not %v", errs)
+	}
+	if f.Len() != 1 {
+		t.Errorf("Expected single cached sellers.json within size limit and not [%d]", f.Len())
+	}
+	f.Get(context.Background(), "google.com")
+	if fetches["google.com"] != 2 {
+		t.Errorf("Expected evicted sellers.json of [google.com] to be fetched again")
+	}
+
+	// failed fetch is not cached
+	f.Get(context.Background(), "missing.com")
+	if fetches["missing.com"] != 2 {
+		t.Errorf("Expected failed sellers.json fetch not to be cached")
+	}
+}
+
+// TestParseReader test streaming parse of sellers.json file with unknown fields
+func TestParseReader(t *testing.T) {
+	body := `{"ext": {"key": [1, 2]}, "Version": "1.0", "sellers": [{"seller_id": "1", "seller_type": "PUBLISHER"},
+		{"seller_id": "1", "seller_type": "BOTH"}, {"seller_id": "2", "seller_type": "INTERMEDIARY"}]}`
+
+	s, err := ParseReader(strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Version != "1.0" || len(s.Sellers) != 3 {
+		t.Errorf("Expected version [1.0] with [3] sellers and not [%s] [%d]", s.Version, len(s.Sellers))
+	}
+	if seller := s.Seller("1"); seller == nil || seller.SellerType != SellerTypePublisher {
+		t.Errorf("Expected first seller of seller ID [1] and not [%v]", seller)
+	}
+
+	// sellers added after parse are found
+	s.Sellers = append(s.Sellers, &Seller{SellerID: "3"})
+	if s.Seller("3") == nil {
+		t.Errorf("Expected seller added after parse to be found")
+	}
+
+	for _, invalid := range []string{`[]`, `{"sellers": {}}`, `{"sellers": [`} {
+		if _, err := ParseReader(strings.NewReader(invalid)); err == nil {
+			t.Errorf("Expected parse error of [%s]", invalid)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package sellersjson
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tzafrirben/go-adstxt-crawler/adstxt"
+)
+
+const (
+	// StatusUnauthorized supply chain seller is not authorized by the publisher Ads.txt file
+	StatusUnauthorized Status = "UNAUTHORIZED"
+	// StatusIncompleteChain supply chain is not complete (complete is not 1) or has no nodes
+	StatusIncompleteChain Status = "INCOMPLETE_CHAIN"
+)
+
+// SupplyChain OpenRTB SupplyChain object (schain) of bid request: the sellers that participated in the sale of the
+// impression, in order from the first seller (the publisher direct seller) to the last seller
+type SupplyChain struct {
+	Complete int                `json:"complete"` // Complete 1 if the chain contains all nodes back to the owner of the inventory
+	Nodes    []*SupplyChainNode `json:"nodes"`    // Nodes sellers of the supply chain, in order of the sale
+	Ver      string             `json:"ver"`      // Ver version of the SupplyChain specification
+}
+
+// SupplyChainNode single seller (hop) of OpenRTB SupplyChain object
+type SupplyChainNode struct {
+	ASI    string `json:"asi"`              // ASI canonical domain of the advertising system (as in Ads.txt and sellers.json)
+	SID    string `json:"sid"`              // SID seller ID in the advertising system (Ads.txt publisher account ID)
+	RID    string `json:"rid,omitempty"`    // RID bid request ID issued by the seller
+	Name   string `json:"name,omitempty"`   // Name of the company paid for inventory
+	Domain string `json:"domain,omitempty"` // Domain business domain name of the seller
+	HP     int    `json:"hp"`               // HP 1 if the seller is involved in the flow of payment for the inventory
+}
+
+// HopError unauthorized hop of supply chain
+type HopError struct {
+	Index   int              // Index of the hop in the supply chain nodes (starting from 0)
+	Node    *SupplyChainNode // Node unauthorized supply chain node, nil for incomplete chain
+	Seller  *Seller          // Seller matching sellers.json seller (if found)
+	Status  Status           // Status reason the hop is unauthorized
+	Message string           // Message explanation of validation failure
+}
+
+func (e *HopError) Error() string {
+	return fmt.Sprintf("supply chain hop [%d]: %s", e.Index, e.Message)
+}
+
+// ValidateSupplyChain validate that each hop of supply chain is authorized: the first seller must be authorized by
+// the publisher Ads.txt records, with relationship matching its sellers.json seller type, and every seller must be
+// listed in the sellers.json file of its advertising system (sellers by lower case advertising system domain) as
+// intermediary for all hops after the first. Return nil if the supply chain is authorized, or *HopError of the first
+// unauthorized hop
+func ValidateSupplyChain(chain *SupplyChain, records *adstxt.Records, sellers map[string]*SellersJSON) error {
+	return validateSupplyChain(chain, records, func(domain string) (*SellersJSON, error) {
+		if s, ok := sellers[domain]; ok && s != nil {
+			return s, nil
+		}
+		return nil, fmt.Errorf("sellers.json of [%s] is not available", domain)
+	})
+}
+
+// ValidateSupplyChain validate supply chain against the publisher Ads.txt records (see ValidateSupplyChain), and
+// fetch the sellers.json file of each advertising system of the supply chain as its hop is validated. Each
+// sellers.json file is fetched once per validation (or once across validations with shared Fetcher), and hop of
+// advertising system whose sellers.json file failed to be fetched is unauthorized with StatusFetchFailed
+func (v *Validator) ValidateSupplyChain(ctx context.Context, chain *SupplyChain, records *adstxt.Records) error {
+	return validateSupplyChain(chain, records, v.sellers(ctx))
+}
+
+// validateSupplyChain validate each hop of supply chain in order, using sellers to get the sellers.json file of each
+// advertising system by its lower case domain
+func validateSupplyChain(chain *SupplyChain, records *adstxt.Records, sellers func(domain string) (*SellersJSON, error)) error {
+	if chain.Complete != 1 || len(chain.Nodes) == 0 {
+		return &HopError{Status: StatusIncompleteChain, Message: "supply chain is not complete"}
+	}
+
+	for i, node := range chain.Nodes {
+		if err := validateHop(i, node, records, sellers); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateHop validate single supply chain hop
+func validateHop(index int, node *SupplyChainNode, records *adstxt.Records, sellers func(domain string) (*SellersJSON, error)) error {
+	hopErr := func(seller *Seller, status Status, format string, args ...interface{}) error {
+		return &HopError{Index: index, Node: node, Seller: seller, Status: status, Message: fmt.Sprintf(format, args...)}
+	}
+
+	// first seller is the publisher direct seller, authorized by the publisher Ads.txt file
+	var record *adstxt.DataRecord
+	if index == 0 {
+		var ok bool
+		if ok, record = records.IsAuthorized(node.ASI, node.SID, adstxt.RelationshipAny); !ok {
+			return hopErr(nil, StatusUnauthorized, "seller ID [%s] of [%s] is not authorized by Ads.txt", node.SID, node.ASI)
+		}
+	}
+
+	s, err := sellers(strings.ToLower(strings.TrimSpace(node.ASI)))
+	if err != nil {
+		return hopErr(nil, StatusFetchFailed, "%s", err)
+	}
+	seller := s.Seller(node.SID)
+	if seller == nil {
+		return hopErr(nil, StatusSellerNotFound, "seller ID [%s] not found in [%s] sellers.json", node.SID, node.ASI)
+	}
+
+	if record != nil && !matchSellerType(record.AccountType, seller.SellerType) {
+		return hopErr(seller, StatusRelationshipMismatch, "relationship [%s] does not match seller type [%s]", record.AccountType, seller.SellerType)
+	}
+	if index > 0 && !matchSellerType("RESELLER", seller.SellerType) {
+		return hopErr(seller, StatusRelationshipMismatch, "seller type [%s] of intermediary hop is not [%s]", seller.SellerType, SellerTypeIntermediary)
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package sellersjson
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tzafrirben/go-adstxt-crawler/adstxt"
+)
+
+// TestValidateSupplyChain test validating each hop of OpenRTB supply chain
+func TestValidateSupplyChain(t *testing.T) {
+	google, _ := Parse([]byte(testSellersJSON))
+	openx, _ := Parse([]byte(`{"version": "1.0", "sellers": [{"seller_id": "540", "seller_type": "INTERMEDIARY"}, {"seller_id": "541", "seller_type": "PUBLISHER"}]}`))
+	sellers := map[string]*SellersJSON{"google.com": google, "openx.com": openx}
+
+	records, _ := adstxt.ParseBody([]byte("google.com,XF7342,DIRECT\ngoogle.com,185,DIRECT\nopenx.com,540,RESELLER"))
+
+	chain := func(complete int, nodes ...string) *SupplyChain {
+		c := &SupplyChain{Complete: complete, Ver: "1.0"}
+		for i := 0; i < len(nodes); i += 2 {
+			c.Nodes = append(c.Nodes, &SupplyChainNode{ASI: nodes[i], SID: nodes[i+1], HP: 1})
+		}
+		return c
+	}
+
+	tests := []struct {
+		chain  *SupplyChain
+		index  int
+		status Status
+	}{
+		{chain(1, "google.com", "XF7342", "OpenX.com", "540"), -1, ""},
+		{chain(0, "google.com", "XF7342"), 0, StatusIncompleteChain},
+		{chain(1, "google.com", "999", "openx.com", "540"), 0, StatusUnauthorized},
+		{chain(1, "google.com", "185"), 0, StatusRelationshipMismatch},
+		{chain(1, "google.com", "XF7342", "openx.com", "404"), 1, StatusSellerNotFound},
+		{chain(1, "google.com", "XF7342", "openx.com", "541"), 1, StatusRelationshipMismatch},
+		{chain(1, "google.com", "XF7342", "unknown.com", "1"), 1, StatusFetchFailed},
+	}
+
+	for i, test := range tests {
+		err := ValidateSupplyChain(test.chain, records, sellers)
+		if test.index == -1 {
+			if err != nil {
+				t.Errorf("Expected supply chain #%d to be authorized and not [%s]", i, err)
+			}
+			continue
+		}
+
+		var hopErr *HopError
+		if !errors.As(err, &hopErr) {
+			t.Errorf("Expected supply chain #%d to fail with HopError and not [%v]", i, err)
+			continue
+		}
+		if hopErr.Index != test.index || hopErr.Status != test.status {
+			t.Errorf("Expected supply chain #%d hop [%d] status [%s] and not hop [%d] status [%s] [%s]", i, test.index, test.status, hopErr.Index, hopErr.Status, hopErr.Message)
+		}
+	}
+}
+
+// TestValidatorValidateSupplyChain test validating supply chain with fetched sellers.json files
+func TestValidatorValidateSupplyChain(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Host != "google.com" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, testSellersJSON)
+	}))
+	defer ts.Close()
+
+	records, _ := adstxt.ParseBody([]byte("google.com,XF7342,DIRECT"))
+	chain := &SupplyChain{}
+	json.Unmarshal([]byte(`{"complete": 1, "ver": "1.0", "nodes": [{"asi": "google.com", "sid": "XF7342", "hp": 1}, {"asi": "google.com", "sid": "185", "hp": 1}]}`), chain)
+
+	v := NewValidator(testClient(ts))
+	if err := v.ValidateSupplyChain(context.Background(), chain, records); err != nil {
+		t.Errorf("Expected supply chain to be authorized and not [%s]", err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected sellers.json to be fetched once and not [%d] times", requests)
+	}
+
+	chain.Nodes = append(chain.Nodes, &SupplyChainNode{ASI: "openx.com", SID: "540"})
+	var hopErr *HopError
+	if err := v.ValidateSupplyChain(context.Background(), chain, records); !errors.As(err, &hopErr) || hopErr.Index != 2 || hopErr.Status != StatusFetchFailed {
+		t.Errorf("Expected hop with missing sellers.json to fail and not [%v]", err)
+	}
+}
@@ -0,0 +1,227 @@
+// Package sellersjson fetch and parse IAB sellers.json files of advertising systems, and cross-validate Ads.txt data
+// records against them based on IAB sellers.json Specification Version 1.0
+// https://iabtechlab.com/wp-content/uploads/2019/07/Sellers.json_Final.pdf
+package sellersjson
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// sellers.json seller types
+const (
+	// SellerTypePublisher the inventory is owned by the seller (DIRECT relationship)
+	SellerTypePublisher = "PUBLISHER"
+	// SellerTypeIntermediary the seller is not the owner of the inventory (RESELLER relationship)
+	SellerTypeIntermediary = "INTERMEDIARY"
+	// SellerTypeBoth the seller is both owner and intermediary of the inventory
+	SellerTypeBoth = "BOTH"
+)
+
+// HTTP client settings
+const (
+	userAgent      = "+https://github.com/tzafrirben/go-adstxt-crawler"
+	requestTimeout = 30
+)
+
+// SellersJSON hold parsed sellers.json file of an advertising system
+type SellersJSON struct {
+	ContactEmail   string        `json:"contact_email,omitempty"`   // ContactEmail email address to use to contact the advertising system
+	ContactAddress string        `json:"contact_address,omitempty"` // ContactAddress business address of the advertising system
+	Version        string        `json:"version"`                   // Version of sellers.json specification
+	Identifiers    []*Identifier `json:"identifiers,omitempty"`     // Identifiers of the advertising system (e.g. TAG-ID, DUNS)
+	Sellers        []*Seller     `json:"sellers"`                   // Sellers list of all sellers of the advertising system
+
+	index   map[string]*Seller // sellers by seller ID, of the first indexed sellers (see Seller)
+	indexed int                // number of indexed sellers
+}
+
+// Identifier of the advertising system
+type Identifier struct {
+	Name  string `json:"name"`  // Name of the identifier (e.g. TAG-ID)
+	Value string `json:"value"` // Value of the identifier
+}
+
+// Seller single seller record in sellers.json file
+type Seller struct {
+	SellerID       string `json:"seller_id"`                // SellerID identifier associated with the seller (Ads.txt publisher account ID)
+	Name           string `json:"name,omitempty"`           // Name of the company paid for inventory
+	Domain         string `json:"domain,omitempty"`         // Domain business domain name of the seller
+	SellerType     string `json:"seller_type"`              // SellerType PUBLISHER, INTERMEDIARY or BOTH
+	IsConfidential int    `json:"is_confidential"`          // IsConfidential indicates whether the seller identity is confidential (1)
+	IsPassthrough  int    `json:"is_passthrough,omitempty"` // IsPassthrough indicates that the seller is passing through the inventory (1)
+	Comment        string `json:"comment,omitempty"`        // Comment description of the seller
+}
+
+// Parse sellers.json file content
+func Parse(b []byte) (*SellersJSON, error) {
+	return ParseReader(bytes.NewReader(b))
+}
+
+// ParseReader parse sellers.json file content from r. The sellers list is decoded one seller at a time, so very large
+// sellers.json files (hundreds of MB) are not held in memory twice, as raw bytes and as parsed sellers
+func ParseReader(r io.Reader) (*SellersJSON, error) {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, err
+	}
+
+	s := &SellersJSON{Sellers: []*Seller{}}
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		var field interface{}
+		// fields are matched case insensitive, as by json.Unmarshal
+		key, _ := t.(string)
+		switch strings.ToLower(key) {
+		case "contact_email":
+			field = &s.ContactEmail
+		case "contact_address":
+			field = &s.ContactAddress
+		case "version":
+			field = &s.Version
+		case "identifiers":
+			field = &s.Identifiers
+		case "sellers":
+			if err := decodeSellers(dec, s); err != nil {
+				return nil, err
+			}
+			continue
+		default:
+			// unknown fields (e.g. ext) are skipped
+			field = &json.RawMessage{}
+		}
+		if err := dec.Decode(field); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := expectDelim(dec, '}'); err != nil {
+		return nil, err
+	}
+	s.indexSellers()
+	return s, nil
+}
+
+// decodeSellers decode sellers array one seller at a time
+func decodeSellers(dec *json.Decoder, s *SellersJSON) error {
+	// null sellers list is decoded as empty list
+	if t, err := dec.Token(); err != nil || t == nil {
+		return err
+	} else if d, ok := t.(json.Delim); !ok || d != '[' {
+		return fmt.Errorf("invalid sellers.json sellers [%v]", t)
+	}
+
+	for dec.More() {
+		seller := &Seller{}
+		if err := dec.Decode(seller); err != nil {
+			return err
+		}
+		s.Sellers = append(s.Sellers, seller)
+	}
+	return expectDelim(dec, ']')
+}
+
+// expectDelim read the next JSON token, and return error if it is not the expected delimiter
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := t.(json.Delim); !ok || d != delim {
+		return fmt.Errorf("invalid sellers.json: expected [%s] and not [%v]", delim, t)
+	}
+	return nil
+}
+
+// indexSellers index sellers by seller ID, so sellers of very large sellers.json files are found without scanning
+// the sellers list. The first seller of each seller ID is indexed
+func (s *SellersJSON) indexSellers() {
+	s.index = make(map[string]*Seller, len(s.Sellers))
+	for _, seller := range s.Sellers {
+		if _, ok := s.index[seller.SellerID]; !ok {
+			s.index[seller.SellerID] = seller
+		}
+	}
+	s.indexed = len(s.Sellers)
+}
+
+// Seller find seller by seller ID (exact match), return nil if seller ID is not found
+func (s *SellersJSON) Seller(sellerID string) *Seller {
+	// parsed sellers are found by index, unless the sellers list was changed since it was parsed
+	if s.index != nil && s.indexed == len(s.Sellers) {
+		return s.index[sellerID]
+	}
+
+	for _, seller := range s.Sellers {
+		if seller.SellerID == sellerID {
+			return seller
+		}
+	}
+	return nil
+}
+
+// URL return sellers.json file URL of an advertising system domain
+func URL(domain string) string {
+	return fmt.Sprintf("https://%s/sellers.json", strings.ToLower(strings.TrimSpace(domain)))
+}
+
+// Get fetch and parse sellers.json file of an advertising system domain
+func Get(ctx context.Context, client *http.Client, domain string) (*SellersJSON, error) {
+	return get(ctx, client, URL(domain))
+}
+
+// get fetch and parse sellers.json file from the specified URL
+func get(ctx context.Context, client *http.Client, url string) (*SellersJSON, error) {
+	s, _, err := fetch(ctx, client, url)
+	return s, err
+}
+
+// fetch fetch and parse sellers.json file from the specified URL, and return the size of the file in bytes
+func fetch(ctx context.Context, client *http.Client, url string) (*SellersJSON, int64, error) {
+	if client == nil {
+		client = &http.Client{Timeout: time.Second * requestTimeout}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Add("User-Agent", userAgent)
+	req.Header.Add("Accept", "application/json")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("[%s] failed to get sellers.json file [%s]", res.Status, url)
+	}
+
+	body := &countingReader{r: res.Body}
+	s, err := ParseReader(body)
+	return s, body.n, err
+}
+
+// countingReader io.Reader that counts the bytes read from the underlying reader
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
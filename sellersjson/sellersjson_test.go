@@ -0,0 +1,101 @@
+package sellersjson
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/tzafrirben/go-adstxt-crawler/adstxt"
+)
+
+const testSellersJSON = `{
+	"contact_email": "adops@google.com",
+	"version": "1.0",
+	"identifiers": [{"name": "TAG-ID", "value": "28cb65e5bbc0bd5f"}],
+	"sellers": [
+		{"seller_id": "XF7342", "name": "Example Publisher", "domain": "example.com", "seller_type": "PUBLISHER", "is_confidential": 0},
+		{"seller_id": "185", "name": "Example Reseller", "domain": "reseller.com", "seller_type": "INTERMEDIARY", "is_confidential": 0},
+		{"seller_id": "999", "seller_type": "BOTH", "is_confidential": 1}
+	]
+}`
+
+// testClient return HTTP client that sends all requests to the test server
+func testClient(ts *httptest.Server) *http.Client {
+	u, _ := url.Parse(ts.URL)
+	return &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req.URL.Scheme = u.Scheme
+			req.URL.Host = u.Host
+			return http.DefaultTransport.RoundTrip(req)
+		}),
+	}
+}
+
+// roundTripperFunc mock HTTP transport
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// TestParse test parsing sellers.json file
+func TestParse(t *testing.T) {
+	s, err := Parse([]byte(testSellersJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(s.Sellers) != 3 {
+		t.Errorf("Expected [3] sellers and not [%d]", len(s.Sellers))
+	}
+
+	seller := s.Seller("999")
+	if seller == nil || seller.IsConfidential != 1 || seller.SellerType != SellerTypeBoth {
+		t.Errorf("Expected to find confidential seller [999] and not [%v]", seller)
+	}
+
+	if s.Seller("unknown") != nil {
+		t.Errorf("Expected unknown seller not to be found")
+	}
+}
+
+// TestValidate test cross-validation of Ads.txt records against sellers.json file
+func TestValidate(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Host != "google.com" || r.URL.Path != "/sellers.json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, testSellersJSON)
+	}))
+	defer ts.Close()
+
+	records, _ := adstxt.ParseBody([]byte("google.com,XF7342,DIRECT\ngoogle.com,185,DIRECT\ngoogle.com,999,RESELLER\ngoogle.com,404,RESELLER\nopenx.com,185,RESELLER"))
+
+	report := NewValidator(testClient(ts)).Validate(context.Background(), records)
+	if len(report.Records) != 5 {
+		t.Fatalf("Expected [5] records in report and not [%d]", len(report.Records))
+	}
+
+	expected := []Status{StatusValid, StatusRelationshipMismatch, StatusValid, StatusSellerNotFound, StatusFetchFailed}
+	for index, s := range expected {
+		if report.Records[index].Status != s {
+			t.Errorf("Expected record #%d status to be [%s] and not [%s] [%s]", index, s, report.Records[index].Status, report.Records[index].Message)
+		}
+	}
+
+	if report.Valid() {
+		t.Errorf("Expected report to be invalid")
+	}
+
+	// each sellers.json should be fetched once
+	if requests != 2 {
+		t.Errorf("Expected [2] sellers.json requests and not [%d]", requests)
+	}
+}
@@ -0,0 +1,142 @@
+package sellersjson
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/tzafrirben/go-adstxt-crawler/adstxt"
+)
+
+// Status of single Ads.txt data record validation against advertising system sellers.json file
+type Status string
+
+const (
+	// StatusValid publisher account ID found in sellers.json and relationship type matches seller type
+	StatusValid Status = "VALID"
+	// StatusSellerNotFound publisher account ID was not found in sellers.json
+	StatusSellerNotFound Status = "SELLER_NOT_FOUND"
+	// StatusRelationshipMismatch relationship type (DIRECT/RESELLER) does not match sellers.json seller type
+	StatusRelationshipMismatch Status = "RELATIONSHIP_MISMATCH"
+	// StatusFetchFailed sellers.json file of the advertising system could not be fetched or parsed
+	StatusFetchFailed Status = "FETCH_FAILED"
+)
+
+// RecordReport validation result of single Ads.txt data record
+type RecordReport struct {
+	Record  *adstxt.DataRecord `json:"record"`           // Record validated Ads.txt data record
+	Seller  *Seller            `json:"seller,omitempty"` // Seller matching sellers.json seller (if found)
+	Status  Status             `json:"status"`           // Status of the validation
+	Message string             `json:"msg,omitempty"`    // Message explanation of validation failure
+}
+
+// Report validation result of all Ads.txt data records
+type Report struct {
+	Records []*RecordReport `json:"records"` // Records validation result per data record, in Ads.txt records order
+}
+
+// Valid return true if all data records are valid
+func (r *Report) Valid() bool {
+	for _, rr := range r.Records {
+		if rr.Status != StatusValid {
+			return false
+		}
+	}
+	return true
+}
+
+// Validator cross-validate Ads.txt data records against sellers.json files of the advertising systems
+type Validator struct {
+	Client  *http.Client // Client HTTP client used to fetch sellers.json files (default client is used if nil)
+	Fetcher *Fetcher     // Fetcher shared cache of sellers.json files across validations (optional, see NewFetcher)
+}
+
+// NewValidator create new sellers.json validator
+func NewValidator(client *http.Client) *Validator {
+	return &Validator{Client: client}
+}
+
+// Validate fetch sellers.json file of each advertising system declared in Ads.txt records, and verify that each
+// publisher account ID exists and that the relationship type matches its seller type. Each sellers.json file is
+// fetched once per validation, or once across validations with shared Fetcher
+func (v *Validator) Validate(ctx context.Context, records *adstxt.Records) *Report {
+	sellers := v.sellers(ctx)
+
+	report := &Report{Records: make([]*RecordReport, 0, len(records.DataRecords))}
+	for _, r := range records.DataRecords {
+		s, err := sellers(strings.ToLower(r.AdverterDomain))
+		if err != nil {
+			report.Records = append(report.Records, &RecordReport{Record: r, Status: StatusFetchFailed, Message: err.Error()})
+			continue
+		}
+
+		report.Records = append(report.Records, ValidateRecord(r, s))
+	}
+
+	return report
+}
+
+// sellers return function that gets the sellers.json file of advertising system domain: from the validator shared
+// Fetcher, or fetched once per returned function if the validator has no Fetcher
+func (v *Validator) sellers(ctx context.Context) func(domain string) (*SellersJSON, error) {
+	if v.Fetcher != nil {
+		return func(domain string) (*SellersJSON, error) {
+			return v.Fetcher.Get(ctx, domain)
+		}
+	}
+
+	type result struct {
+		sellers *SellersJSON
+		err     error
+	}
+	fetched := map[string]*result{}
+
+	return func(domain string) (*SellersJSON, error) {
+		res, ok := fetched[domain]
+		if !ok {
+			s, err := Get(ctx, v.Client, domain)
+			res = &result{sellers: s, err: err}
+			fetched[domain] = res
+		}
+		return res.sellers, res.err
+	}
+}
+
+// ValidateRecord validate single Ads.txt data record against advertising system sellers.json file
+func ValidateRecord(r *adstxt.DataRecord, s *SellersJSON) *RecordReport {
+	seller := s.Seller(r.PublisherAccountID)
+	if seller == nil {
+		return &RecordReport{
+			Record:  r,
+			Status:  StatusSellerNotFound,
+			Message: fmt.Sprintf("publisher account ID [%s] not found in [%s] sellers.json", r.PublisherAccountID, r.AdverterDomain),
+		}
+	}
+
+	if !matchSellerType(r.AccountType, seller.SellerType) {
+		return &RecordReport{
+			Record:  r,
+			Seller:  seller,
+			Status:  StatusRelationshipMismatch,
+			Message: fmt.Sprintf("relationship [%s] does not match seller type [%s]", r.AccountType, seller.SellerType),
+		}
+	}
+
+	return &RecordReport{Record: r, Seller: seller, Status: StatusValid}
+}
+
+// matchSellerType check that Ads.txt relationship matches sellers.json seller type: DIRECT relationship is expected
+// for PUBLISHER sellers, RESELLER relationship for INTERMEDIARY sellers, and BOTH sellers match any relationship
+func matchSellerType(accountType adstxt.Relationship, sellerType string) bool {
+	switch strings.ToUpper(sellerType) {
+	case SellerTypeBoth:
+		return true
+	case SellerTypePublisher:
+		return accountType.IsDirect()
+	case SellerTypeIntermediary:
+		return accountType.IsReseller()
+	default:
+		return false
+	}
+}
@@ -0,0 +1,388 @@
+package adstxt
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// CSV serialization record types
+const (
+	csvDataRecord = "data"
+	csvVariable   = "variable"
+)
+
+// csvHeader CSV serialization columns
+var csvHeader = []string{"line", "record", "adverterDomain", "publisherAccountID", "accountType", "certAuthorityID", "variableType", "variableValue"}
+
+// recordsJSON Records JSON form: same as Records fields, with line index of each Data\Variable record
+type recordsJSON struct {
+	DataRecords    []*dataRecordJSON `json:"dataRecords"`
+	Variables      []*variableJSON   `json:"variables"`
+	Warnings       []*Warning        `json:"warnings"`
+	Body           []string          `json:"body"`
+	OwnerDomain    string            `json:"ownerDomain,omitempty"`
+	ManagerDomains []*ManagerDomain  `json:"managerDomains,omitempty"`
+
+	Contacts                []string            `json:"contacts,omitempty"`
+	Subdomains              []string            `json:"subdomains,omitempty"`
+	InventoryPartnerDomains []string            `json:"inventoryPartnerDomains,omitempty"`
+	OtherVariables          map[string][]string `json:"otherVariables,omitempty"`
+
+	TrailingComments []string `json:"trailingComments,omitempty"`
+	Placeholder      bool     `json:"placeholder,omitempty"`
+
+	ParseStats ParseStats `json:"parseStats"`
+}
+
+// dataRecordJSON DataRecord JSON form with line index of the record in Ads.txt file
+type dataRecordJSON struct {
+	Line int `json:"line,omitempty"`
+	DataRecord
+}
+
+// variableJSON Variable JSON form with line index of the record in Ads.txt file
+type variableJSON struct {
+	Line int `json:"line,omitempty"`
+	Variable
+}
+
+// MarshalJSON encode Records as JSON, including the line index of each Data\Variable record in the Ads.txt file and
+// parse warnings. Use FromJSON (or json.Unmarshal) to load Records back from JSON
+func (r Records) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.toJSON())
+}
+
+// responseJSON Response JSON form: Request fields, Records JSON form and Response metadata
+type responseJSON struct {
+	*Request
+	recordsJSON
+	Expires       time.Time     `json:"expires"`
+	ExpiresSource ExpiresSource `json:"expiresSource,omitempty"`
+	ExpiresHeader string        `json:"expiresHeader,omitempty"`
+	Attempts      int           `json:"attempts"`
+	ETag          string        `json:"etag,omitempty"`
+	LastModified  string        `json:"lastModified,omitempty"`
+	NotModified   bool          `json:"notModified,omitempty"`
+	FinalURL      string        `json:"finalUrl"`
+	StatusCode    int           `json:"statusCode"`
+	Header        http.Header   `json:"header,omitempty"`
+	RobotsTag     []string      `json:"robotsTag,omitempty"`
+	RequestHeader http.Header   `json:"requestHeader,omitempty"`
+	ContentLength int64         `json:"contentLength"`
+	Fetched       time.Time     `json:"fetched"`
+	Duration      time.Duration `json:"duration"`
+	SHA256        string        `json:"sha256,omitempty"`
+	Redirects     []*Redirect   `json:"redirects"`
+	Variant       string        `json:"variant,omitempty"`
+	AlternatePath string        `json:"alternatePath,omitempty"`
+	FinalScheme   string        `json:"finalScheme,omitempty"`
+	FinalPort     int           `json:"finalPort,omitempty"`
+	TLS           *TLSInfo      `json:"tls,omitempty"`
+	RemoteAddr    string        `json:"remoteAddr,omitempty"`
+	AddressFamily IPFamily      `json:"addressFamily,omitempty"`
+
+	FetchedInsecurely bool `json:"fetchedInsecurely,omitempty"`
+	Partial           bool `json:"partial,omitempty"`
+	HTMLRedirected    bool `json:"htmlRedirected,omitempty"`
+	Rescheduled       int  `json:"rescheduled,omitempty"`
+	Truncated         bool `json:"truncated,omitempty"`
+	OffDomainRedirect bool `json:"offDomainRedirect,omitempty"`
+
+	Cached bool          `json:"cached,omitempty"`
+	Age    time.Duration `json:"age,omitempty"`
+	Stale  bool          `json:"stale,omitempty"`
+
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// MarshalJSON encode Response as JSON: Request fields, Records JSON form (see Records.MarshalJSON) and Response
+// metadata. Response must implement json.Marshaler since it embeds Records. Raw body is not encoded, as Records
+// already hold the original Ads.txt file content
+func (r Response) MarshalJSON() ([]byte, error) {
+	res := &responseJSON{
+		Request:       r.Request,
+		Expires:       r.Expires,
+		ExpiresSource: r.ExpiresSource,
+		ExpiresHeader: r.ExpiresHeader,
+		Attempts:      r.Attempts,
+		ETag:          r.ETag,
+		LastModified:  r.LastModified,
+		NotModified:   r.NotModified,
+		FinalURL:      r.FinalURL,
+		StatusCode:    r.StatusCode,
+		Header:        r.Header,
+		RobotsTag:     r.RobotsTag,
+		RequestHeader: r.RequestHeader,
+		ContentLength: r.ContentLength,
+		Fetched:       r.Fetched,
+		Duration:      r.Duration,
+		SHA256:        r.SHA256,
+		Redirects:     r.Redirects,
+		Variant:       r.Variant,
+		AlternatePath: r.AlternatePath,
+		FinalScheme:   r.FinalScheme,
+		FinalPort:     r.FinalPort,
+		TLS:           r.TLS,
+		RemoteAddr:    r.RemoteAddr,
+		AddressFamily: r.AddressFamily,
+	}
+
+	res.FetchedInsecurely = r.FetchedInsecurely
+	res.Partial = r.Partial
+	res.HTMLRedirected = r.HTMLRedirected
+	res.Rescheduled = r.Rescheduled
+	res.Truncated = r.Truncated
+	res.OffDomainRedirect = r.OffDomainRedirect
+	res.Cached = r.Cached
+	res.Age = r.Age
+	res.Stale = r.Stale
+	res.Metadata = r.Metadata
+
+	if r.Records != nil {
+		res.recordsJSON = *r.Records.toJSON()
+	}
+
+	return json.Marshal(res)
+}
+
+// toJSON return Records JSON form
+func (r *Records) toJSON() *recordsJSON {
+	j := &recordsJSON{
+		DataRecords:    make([]*dataRecordJSON, 0, len(r.DataRecords)),
+		Variables:      make([]*variableJSON, 0, len(r.Variables)),
+		Warnings:       r.Warnings,
+		Body:           r.Body,
+		OwnerDomain:    r.OwnerDomain,
+		ManagerDomains: r.ManagerDomains,
+
+		Contacts:                r.Contacts,
+		Subdomains:              r.Subdomains,
+		InventoryPartnerDomains: r.InventoryPartnerDomains,
+		OtherVariables:          r.OtherVariables,
+
+		TrailingComments: r.TrailingComments,
+		Placeholder:      r.Placeholder,
+
+		ParseStats: r.ParseStats,
+	}
+
+	for _, dr := range r.DataRecords {
+		j.DataRecords = append(j.DataRecords, &dataRecordJSON{Line: r.Line(dr), DataRecord: *dr})
+	}
+	for _, v := range r.Variables {
+		j.Variables = append(j.Variables, &variableJSON{Line: r.Line(v), Variable: *v})
+	}
+
+	return j
+}
+
+// UnmarshalJSON decode Records from JSON encoded by Records.MarshalJSON, including records line index in the
+// Ads.txt file
+func (r *Records) UnmarshalJSON(b []byte) error {
+	var j recordsJSON
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+
+	*r = *j.records()
+	return nil
+}
+
+// UnmarshalJSON decode Response from JSON encoded by Response.MarshalJSON
+func (r *Response) UnmarshalJSON(b []byte) error {
+	var res responseJSON
+	if err := json.Unmarshal(b, &res); err != nil {
+		return err
+	}
+
+	*r = Response{
+		Request:       res.Request,
+		Records:       res.recordsJSON.records(),
+		Expires:       res.Expires,
+		ExpiresSource: res.ExpiresSource,
+		ExpiresHeader: res.ExpiresHeader,
+		Attempts:      res.Attempts,
+		ETag:          res.ETag,
+		LastModified:  res.LastModified,
+		NotModified:   res.NotModified,
+		FinalURL:      res.FinalURL,
+		StatusCode:    res.StatusCode,
+		Header:        res.Header,
+		RobotsTag:     res.RobotsTag,
+		RequestHeader: res.RequestHeader,
+		ContentLength: res.ContentLength,
+		Fetched:       res.Fetched,
+		Duration:      res.Duration,
+		SHA256:        res.SHA256,
+		Redirects:     res.Redirects,
+		Variant:       res.Variant,
+		AlternatePath: res.AlternatePath,
+		FinalScheme:   res.FinalScheme,
+		FinalPort:     res.FinalPort,
+		TLS:           res.TLS,
+		RemoteAddr:    res.RemoteAddr,
+		AddressFamily: res.AddressFamily,
+	}
+	r.FetchedInsecurely = res.FetchedInsecurely
+	r.Partial = res.Partial
+	r.HTMLRedirected = res.HTMLRedirected
+	r.Rescheduled = res.Rescheduled
+	r.Truncated = res.Truncated
+	r.OffDomainRedirect = res.OffDomainRedirect
+	r.Cached = res.Cached
+	r.Age = res.Age
+	r.Stale = res.Stale
+	r.Metadata = res.Metadata
+	return nil
+}
+
+// records return Records from Records JSON form
+func (j *recordsJSON) records() *Records {
+	r := &Records{
+		DataRecords:    make([]*DataRecord, 0, len(j.DataRecords)),
+		Variables:      make([]*Variable, 0, len(j.Variables)),
+		Warnings:       j.Warnings,
+		Body:           j.Body,
+		OwnerDomain:    j.OwnerDomain,
+		ManagerDomains: j.ManagerDomains,
+
+		Contacts:                j.Contacts,
+		Subdomains:              j.Subdomains,
+		InventoryPartnerDomains: j.InventoryPartnerDomains,
+		OtherVariables:          j.OtherVariables,
+
+		TrailingComments: j.TrailingComments,
+		Placeholder:      j.Placeholder,
+
+		ParseStats: j.ParseStats,
+	}
+	if r.Warnings == nil {
+		r.Warnings = []*Warning{}
+	}
+	if r.Body == nil {
+		r.Body = []string{}
+	}
+
+	for _, d := range j.DataRecords {
+		dr := d.DataRecord
+		r.DataRecords = append(r.DataRecords, &dr)
+		if d.Line > 0 {
+			r.setLine(&dr, d.Line)
+		}
+	}
+	for _, v := range j.Variables {
+		vr := v.Variable
+		r.Variables = append(r.Variables, &vr)
+		if v.Line > 0 {
+			r.setLine(&vr, v.Line)
+		}
+	}
+
+	return r
+}
+
+// FromJSON load Records from JSON encoded by Records.MarshalJSON, including records line index in the Ads.txt file
+func FromJSON(rd io.Reader) (*Records, error) {
+	r := &Records{}
+	if err := json.NewDecoder(rd).Decode(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// ToCSV write Ads.txt data records and variables to w as CSV, one record per row with header row first. Each row
+// holds the line index of the record in Ads.txt file, record type (data or variable) and the record fields. Parse
+// warnings, data records extension fields and comments, and original Ads.txt file content are not included in CSV
+// form
+func (r *Records) ToCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	line := func(record interface{}) string {
+		if index := r.Line(record); index > 0 {
+			return strconv.Itoa(index)
+		}
+		return ""
+	}
+
+	for _, dr := range r.DataRecords {
+		row := []string{line(dr), csvDataRecord, dr.AdverterDomain, dr.PublisherAccountID, string(dr.AccountType), dr.CertAuthorityID, "", ""}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	for _, v := range r.Variables {
+		row := []string{line(v), csvVariable, "", "", "", "", v.Type, v.Value}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// FromCSV load Ads.txt data records and variables from CSV written by Records.ToCSV. Typed variables
+// (OwnerDomain, ManagerDomains, Contacts, etc.) are set from the loaded variables
+func FromCSV(rd io.Reader) (*Records, error) {
+	cr := csv.NewReader(rd)
+	cr.FieldsPerRecord = len(csvHeader)
+
+	// skip header row
+	if _, err := cr.Read(); err != nil {
+		return nil, err
+	}
+
+	r := &Records{
+		DataRecords: []*DataRecord{},
+		Variables:   []*Variable{},
+		Warnings:    []*Warning{},
+		Body:        []string{},
+	}
+
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		index := 0
+		if len(row[0]) > 0 {
+			if index, err = strconv.Atoi(row[0]); err != nil {
+				return nil, fmt.Errorf("invalid CSV line index [%s]: %s", row[0], err)
+			}
+		}
+
+		switch row[1] {
+		case csvDataRecord:
+			dr := &DataRecord{AdverterDomain: row[2], PublisherAccountID: row[3], AccountType: Relationship(row[4]), CertAuthorityID: row[5]}
+			r.DataRecords = append(r.DataRecords, dr)
+			if index > 0 {
+				r.setLine(dr, index)
+			}
+		case csvVariable:
+			v := &Variable{Type: row[6], Value: row[7]}
+			if w := r.addVariable(v); w != nil {
+				return nil, fmt.Errorf("invalid CSV variable [%s=%s]: %s", v.Type, v.Value, w.Message)
+			}
+			if index > 0 {
+				r.setLine(v, index)
+			}
+		default:
+			return nil, fmt.Errorf("invalid CSV record type [%s]", row[1])
+		}
+	}
+
+	return r, nil
+}
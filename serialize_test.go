@@ -0,0 +1,103 @@
+package adstxt
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const serializeBody = "# comment\ngreenadexchange.com,XF7342,DIRECT,5jyxf8k54\n\ngreenadexchange.com,185,RESELLER\nsubdomain=dev.example.com\nownerdomain=example.com\ninvalid line"
+
+// TestRecordsJSON test Records JSON form includes records line index and warnings, and can be loaded back
+func TestRecordsJSON(t *testing.T) {
+	records, err := ParseBody([]byte(serializeBody))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := json.Marshal(records)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var j struct {
+		DataRecords []struct {
+			Line int `json:"line"`
+		} `json:"dataRecords"`
+		Warnings []*Warning `json:"warnings"`
+	}
+	if err := json.Unmarshal(b, &j); err != nil {
+		t.Fatal(err)
+	}
+	if len(j.DataRecords) != 2 || j.DataRecords[0].Line != 2 || j.DataRecords[1].Line != 4 {
+		t.Errorf("Expected data records line index in JSON form [%s]", string(b))
+	}
+	if len(j.Warnings) != 1 || j.Warnings[0].Index != 7 {
+		t.Errorf("Expected parse warning in JSON form [%s]", string(b))
+	}
+
+	loaded, err := FromJSON(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Hash() != records.Hash() {
+		t.Errorf("Expected records loaded from JSON to be equal to original records")
+	}
+	if loaded.Line(loaded.Variables[0]) != 5 || loaded.OwnerDomain != "example.com" || len(loaded.Warnings) != 1 {
+		t.Errorf("Expected records loaded from JSON to keep line index, variables and warnings")
+	}
+}
+
+// TestResponseJSON test Response JSON form holds Request, Records and Response fields
+func TestResponseJSON(t *testing.T) {
+	records, _ := ParseBody([]byte(serializeBody))
+	res := &Response{Request: &Request{Domain: "example.com", URL: "http://example.com/ads.txt"}, Records: records, Attempts: 2, SHA256: "abc"}
+
+	b, err := json.Marshal(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{`"url":"http://example.com/ads.txt"`, `"attempts":2`, `"sha256":"abc"`, `"dataRecords":[{"line":2`} {
+		if !strings.Contains(string(b), s) {
+			t.Errorf("Expected Response JSON form to contain [%s] [%s]", s, string(b))
+		}
+	}
+}
+
+// TestRecordsCSV test writing Records to CSV and loading them back
+func TestRecordsCSV(t *testing.T) {
+	records, err := ParseBody([]byte(serializeBody))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	if err := records.ToCSV(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	rows := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(rows) != 5 {
+		t.Errorf("Expected header and [4] records CSV rows and not [%d]", len(rows))
+	}
+	if rows[1] != "2,data,greenadexchange.com,XF7342,DIRECT,5jyxf8k54,," {
+		t.Errorf("Unexpected data record CSV row [%s]", rows[1])
+	}
+
+	loaded, err := FromCSV(strings.NewReader(b.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Hash() != records.Hash() {
+		t.Errorf("Expected records loaded from CSV to be equal to original records")
+	}
+	if loaded.Line(loaded.DataRecords[1]) != 4 || loaded.OwnerDomain != "example.com" {
+		t.Errorf("Expected records loaded from CSV to keep line index and typed variables")
+	}
+
+	if _, err := FromCSV(strings.NewReader(strings.Join(csvHeader, ",") + "\n1,other,,,,,,\n")); err == nil {
+		t.Errorf("Expected unknown CSV record type to fail")
+	}
+}
@@ -0,0 +1,356 @@
+// Package server expose Ads.txt crawler as HTTP JSON service, so Ads.txt files can be crawled, parsed and validated
+// from any language:
+//
+//	GET  /v1/adstxt?domain=<domain>[&app=true]  crawl and parse single Ads.txt file
+//	POST /v1/batch                              crawl and parse Ads.txt files of multiple domains
+//	POST /v1/validate                           parse and validate Ads.txt file content sent as request body
+//
+// Responses are JSON encoded adstxt.Response (see adstxt.Response.MarshalJSON) or Result. Errors are returned as
+// Error JSON with matching HTTP status code.
+//
+// Batch results are returned as JSON array once the whole batch is crawled, or streamed as each request completes
+// when the batch request Accept header is application/x-ndjson (JSON Lines: single Result per line) or
+// text/event-stream (server-sent events: "result" event of each Result, and final "done" event), so clients of large
+// batches can consume the parsed Ads.txt files before the whole batch finishes
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/tzafrirben/go-adstxt-crawler/adstxt"
+)
+
+// server settings default values
+const (
+	defaultMaxDomains  = 1000
+	defaultMaxBodySize = 10 << 20
+)
+
+// batch results stream content types (see streamFormat)
+const (
+	contentTypeJSONL = "application/x-ndjson"
+	contentTypeSSE   = "text/event-stream"
+)
+
+// server request errors
+const (
+	errMissingDomain   = "missing domain query parameter"
+	errNoDomains       = "batch request has no domains"
+	errTooManyDomains  = "batch request has [%d] domains, maximum is [%d]"
+	errInvalidBatch    = "invalid batch request: %s"
+	errBodyTooLarge    = "request body is larger than [%d] bytes"
+	errInvalidAppParam = "invalid app query parameter [%s]"
+)
+
+// BatchRequest body of batch crawl request
+type BatchRequest struct {
+	Domains []string `json:"domains"`       // Domains to crawl Ads.txt files of
+	App     bool     `json:"app,omitempty"` // App crawl app-ads.txt files instead of Ads.txt
+}
+
+// Result of single domain Ads.txt request in batch response
+type Result struct {
+	Index    int              `json:"index"`              // Index of the domain in the batch request
+	Domain   string           `json:"domain"`             // Domain as listed in the batch request
+	Response *adstxt.Response `json:"response,omitempty"` // Response parsed Ads.txt file, nil if the request failed
+	Error    string           `json:"error,omitempty"`    // Error request error message
+}
+
+// ValidationResult response of validate request: parsed Ads.txt records, parse warnings and validation errors
+type ValidationResult struct {
+	Records *adstxt.Records    `json:"records"`          // Records parsed Ads.txt records, including parse warnings
+	Errors  []*ValidationError `json:"validationErrors"` // Errors records that do not comply with IAB Ads.txt specification
+	Valid   bool               `json:"valid"`            // Valid Ads.txt file has no parse warnings and validation errors
+}
+
+// ValidationError JSON form of adstxt.ValidationError
+type ValidationError struct {
+	Line    int    `json:"line"`    // Line index of the invalid record in the Ads.txt file
+	Record  string `json:"record"`  // Record canonical form of the invalid record
+	Field   string `json:"field"`   // Field name of the invalid record field
+	Value   string `json:"value"`   // Value of the invalid record field
+	Message string `json:"message"` // Message validation failure reason
+}
+
+// Error JSON body of failed request
+type Error struct {
+	Error string `json:"error"` // Error message
+}
+
+// Server HTTP handler that serves the Ads.txt crawler JSON API
+type Server struct {
+	MaxDomains  int   // MaxDomains maximum number of domains in single batch request
+	MaxBodySize int64 // MaxBodySize maximum size of batch and validate request body in bytes
+
+	crawler *adstxt.Crawler
+	mux     *http.ServeMux
+}
+
+// New create new Server that uses crawler c to crawl Ads.txt files. Batch requests are crawled with the crawler
+// concurrency (see adstxt.WithConcurrency)
+func New(c *adstxt.Crawler) *Server {
+	s := &Server{MaxDomains: defaultMaxDomains, MaxBodySize: defaultMaxBodySize, crawler: c, mux: http.NewServeMux()}
+
+	s.mux.HandleFunc("GET /v1/adstxt", s.get)
+	s.mux.HandleFunc("POST /v1/batch", s.batch)
+	s.mux.HandleFunc("POST /v1/validate", s.validate)
+
+	return s
+}
+
+// ServeHTTP is the http.Handler interface implementation for Server
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// get crawl and parse single Ads.txt file
+func (s *Server) get(w http.ResponseWriter, r *http.Request) {
+	domain := r.URL.Query().Get("domain")
+	if len(domain) == 0 {
+		writeError(w, http.StatusBadRequest, errors.New(errMissingDomain))
+		return
+	}
+	app, err := appParam(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	req, err := newRequest(domain, app)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	res, err := s.crawler.GetWithContext(r.Context(), req)
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+// batch crawl and parse Ads.txt files of all domains in batch request, and return the results in order of the
+// request domains
+func (s *Server) batch(w http.ResponseWriter, r *http.Request) {
+	body, err := s.readBody(w, r)
+	if err != nil {
+		writeError(w, http.StatusRequestEntityTooLarge, err)
+		return
+	}
+
+	batch := &BatchRequest{}
+	if err := json.Unmarshal(body, batch); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf(errInvalidBatch, err))
+		return
+	}
+	if len(batch.Domains) == 0 {
+		writeError(w, http.StatusBadRequest, errors.New(errNoDomains))
+		return
+	}
+	if len(batch.Domains) > s.MaxDomains {
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Errorf(errTooManyDomains, len(batch.Domains), s.MaxDomains))
+		return
+	}
+
+	results := make([]*Result, len(batch.Domains))
+	requests := make([]*adstxt.Request, 0, len(batch.Domains))
+	index := map[*adstxt.Request]int{}
+	for i, d := range batch.Domains {
+		results[i] = &Result{Index: i, Domain: d}
+		req, err := newRequest(d, batch.App)
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		index[req] = i
+		requests = append(requests, req)
+	}
+
+	if format := streamFormat(r); len(format) > 0 {
+		s.streamBatch(w, r, format, results, requests, index)
+		return
+	}
+
+	s.crawler.GetMultipleWithContext(r.Context(), requests, adstxt.HandlerFunc(func(req *adstxt.Request, res *adstxt.Response, err error) {
+		// each result is set once by its request index, so no lock is needed
+		result := results[index[req]]
+		result.Response = res
+		if err != nil {
+			result.Error = err.Error()
+		}
+	}))
+
+	writeJSON(w, http.StatusOK, results)
+}
+
+// streamBatch crawl batch request Ads.txt files, and stream each result in the stream format as soon as its request
+// completes: results of invalid domains are streamed first, and then results in order of completion
+func (s *Server) streamBatch(w http.ResponseWriter, r *http.Request, format string, results []*Result, requests []*adstxt.Request,
+	index map[*adstxt.Request]int) {
+	w.Header().Set("Content-Type", format)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	stream := &resultStream{w: w, rc: http.NewResponseController(w), sse: format == contentTypeSSE}
+	for _, result := range results {
+		if len(result.Error) > 0 {
+			stream.write(result)
+		}
+	}
+
+	s.crawler.GetMultipleWithContext(r.Context(), requests, adstxt.HandlerFunc(func(req *adstxt.Request, res *adstxt.Response, err error) {
+		result := results[index[req]]
+		result.Response = res
+		if err != nil {
+			result.Error = err.Error()
+		}
+		stream.write(result)
+	}))
+
+	stream.done(len(results))
+}
+
+// resultStream stream of batch results, written from the crawler handler goroutines
+type resultStream struct {
+	mu  sync.Mutex
+	w   http.ResponseWriter
+	rc  *http.ResponseController
+	sse bool // stream results as server-sent events, or as JSON lines
+}
+
+// write result to the stream, and flush it to the client. Write errors (for example client that closed the
+// connection) are ignored: once the client is gone the batch is canceled with the request context
+func (s *resultStream) write(result *Result) {
+	b, err := json.Marshal(result)
+	if err != nil {
+		b, _ = json.Marshal(&Result{Index: result.Index, Domain: result.Domain, Error: err.Error()})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sse {
+		fmt.Fprintf(s.w, "event: result\ndata: %s\n\n", b)
+	} else {
+		s.w.Write(append(b, '\n'))
+	}
+	s.rc.Flush()
+}
+
+// done end the stream: server-sent events stream ends with "done" event of the number of streamed results
+func (s *resultStream) done(total int) {
+	if !s.sse {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fmt.Fprintf(s.w, "event: done\ndata: {\"results\":%d}\n\n", total)
+	s.rc.Flush()
+}
+
+// streamFormat return the content type of batch results stream accepted by the client (see the request Accept
+// header), or empty string if the client accepts only complete JSON response
+func streamFormat(r *http.Request) string {
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case contentTypeJSONL, "application/jsonl":
+			return contentTypeJSONL
+		case contentTypeSSE:
+			return contentTypeSSE
+		}
+	}
+	return ""
+}
+
+// validate parse and validate Ads.txt file content sent as request body
+func (s *Server) validate(w http.ResponseWriter, r *http.Request) {
+	body, err := s.readBody(w, r)
+	if err != nil {
+		writeError(w, http.StatusRequestEntityTooLarge, err)
+		return
+	}
+
+	records, err := adstxt.ParseBody(body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	result := &ValidationResult{Records: records, Errors: []*ValidationError{}}
+	if err := records.Validate(); err != nil {
+		for _, e := range err.(adstxt.ValidationErrors) {
+			result.Errors = append(result.Errors, &ValidationError{Line: e.Line, Record: e.Record, Field: e.Field, Value: e.Value, Message: e.Err.Error()})
+		}
+	}
+	result.Valid = len(records.Warnings) == 0 && len(result.Errors) == 0
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// readBody read request body, up to the server maximum body size
+func (s *Server) readBody(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.MaxBodySize))
+	if err != nil {
+		return nil, fmt.Errorf(errBodyTooLarge, s.MaxBodySize)
+	}
+	return body, nil
+}
+
+// appParam return the value of the app query parameter (false if not set)
+func appParam(r *http.Request) (bool, error) {
+	v := r.URL.Query().Get("app")
+	if len(v) == 0 {
+		return false, nil
+	}
+	app, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf(errInvalidAppParam, v)
+	}
+	return app, nil
+}
+
+// newRequest return Ads.txt request, or app-ads.txt request if app is set
+func newRequest(domain string, app bool) (*adstxt.Request, error) {
+	if app {
+		return adstxt.NewAppAdsTxtRequest(domain)
+	}
+	return adstxt.NewRequest(domain)
+}
+
+// errorStatus return the HTTP status code of failed Ads.txt request: 404 if the remote host has no Ads.txt file, 504
+// if the request timed out and 502 for any other remote host failure
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, adstxt.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, adstxt.ErrTimeout):
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusBadGateway
+}
+
+// writeError write error JSON with HTTP status code
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, &Error{Error: err.Error()})
+}
+
+// writeJSON write v as JSON with HTTP status code
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
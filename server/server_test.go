@@ -0,0 +1,195 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tzafrirben/go-adstxt-crawler/adstxt"
+)
+
+// roundTripperFunc mock HTTP transport
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// newTestServer create Server with crawler that serves Ads.txt file for every host, except missing.com
+func newTestServer() *Server {
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Host == "missing.com" {
+				return &http.Response{StatusCode: http.StatusNotFound, Status: "404 Not Found", Body: http.NoBody, Request: req}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       io.NopCloser(strings.NewReader("google.com,pub-" + req.URL.Host + ",DIRECT")),
+				Request:    req,
+			}, nil
+		}),
+	}
+	return New(adstxt.NewCrawler(adstxt.WithHTTPClient(client)))
+}
+
+// TestGet test single Ads.txt fetch endpoint
+func TestGet(t *testing.T) {
+	s := newTestServer()
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/adstxt?domain=example.com", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected HTTP status [200] and not [%d]: %s", rec.Code, rec.Body)
+	}
+	res := &adstxt.Response{}
+	if err := json.Unmarshal(rec.Body.Bytes(), res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.DataRecords) != 1 || res.DataRecords[0].PublisherAccountID != "pub-example.com" {
+		t.Errorf("Expected parsed Ads.txt response")
+	}
+
+	tests := []struct {
+		url    string
+		status int
+	}{
+		{"/v1/adstxt", http.StatusBadRequest},
+		{"/v1/adstxt?domain=example.com&app=maybe", http.StatusBadRequest},
+		{"/v1/adstxt?domain=missing.com", http.StatusNotFound},
+	}
+	for _, test := range tests {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, test.url, nil))
+		if rec.Code != test.status {
+			t.Errorf("Expected [%s] HTTP status [%d] and not [%d]", test.url, test.status, rec.Code)
+		}
+		e := &Error{}
+		if err := json.Unmarshal(rec.Body.Bytes(), e); err != nil || len(e.Error) == 0 {
+			t.Errorf("Expected [%s] error JSON and not [%s]", test.url, rec.Body)
+		}
+	}
+}
+
+// TestBatch test batch Ads.txt fetch endpoint
+func TestBatch(t *testing.T) {
+	s := newTestServer()
+
+	body, _ := json.Marshal(&BatchRequest{Domains: []string{"example.com", "missing.com", "not a domain", "test.com"}})
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/batch", bytes.NewReader(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected HTTP status [200] and not [%d]: %s", rec.Code, rec.Body)
+	}
+
+	results := []*Result{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &results); err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("Expected [4] results and not [%d]", len(results))
+	}
+	for i, d := range []string{"example.com", "missing.com", "not a domain", "test.com"} {
+		if results[i].Domain != d {
+			t.Errorf("Expected result [%d] domain [%s] and not [%s]", i, d, results[i].Domain)
+		}
+	}
+	if results[0].Response == nil || results[0].Response.DataRecords[0].PublisherAccountID != "pub-example.com" || len(results[0].Error) > 0 {
+		t.Errorf("Expected [example.com] Ads.txt response")
+	}
+	if results[1].Response != nil || len(results[1].Error) == 0 || len(results[2].Error) == 0 {
+		t.Errorf("Expected failed requests error message")
+	}
+
+	s.MaxDomains = 1
+	for _, body := range []string{`{"domains":[]}`, `{"domains":`, `{"domains":["a.com","b.com"]}`} {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/batch", strings.NewReader(body)))
+		if rec.Code < 400 {
+			t.Errorf("Expected invalid batch request [%s] to fail", body)
+		}
+	}
+}
+
+// TestBatchStream test streaming batch results as JSON lines and server-sent events
+func TestBatchStream(t *testing.T) {
+	s := newTestServer()
+	domains := []string{"example.com", "missing.com", "not a domain", "test.com"}
+	body, _ := json.Marshal(&BatchRequest{Domains: domains})
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/batch", bytes.NewReader(body))
+	req.Header.Set("Accept", "application/x-ndjson")
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != contentTypeJSONL || !rec.Flushed {
+		t.Fatalf("Expected flushed JSON lines stream and not [%d] [%s]", rec.Code, rec.Header().Get("Content-Type"))
+	}
+
+	lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	if len(lines) != len(domains) {
+		t.Fatalf("Expected [%d] result lines and not [%d]", len(domains), len(lines))
+	}
+	seen := map[int]bool{}
+	for _, line := range lines {
+		result := &Result{}
+		if err := json.Unmarshal([]byte(line), result); err != nil {
+			t.Fatal(err)
+		}
+		if domains[result.Index] != result.Domain || seen[result.Index] {
+			t.Errorf("Expected single result of each domain by index and not [%d] [%s]", result.Index, result.Domain)
+		}
+		seen[result.Index] = true
+	}
+	// result of invalid domain is streamed before any request is sent
+	if !strings.Contains(lines[0], `"domain":"not a domain"`) {
+		t.Errorf("Expected invalid domain result first and not [%s]", lines[0])
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/v1/batch", bytes.NewReader(body))
+	req.Header.Set("Accept", "text/event-stream, application/json;q=0.5")
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Header().Get("Content-Type") != contentTypeSSE {
+		t.Fatalf("Expected server-sent events stream and not [%s]", rec.Header().Get("Content-Type"))
+	}
+	events := strings.Split(strings.TrimSpace(rec.Body.String()), "\n\n")
+	if len(events) != len(domains)+1 || !strings.HasPrefix(events[0], "event: result\ndata: {") {
+		t.Fatalf("Expected [%d] result events and done event and not %q", len(domains), events)
+	}
+	if done := events[len(events)-1]; done != `event: done`+"\n"+`data: {"results":4}` {
+		t.Errorf("Expected done event of [4] results and not [%s]", done)
+	}
+}
+
+// TestValidate test Ads.txt validation endpoint
+func TestValidate(t *testing.T) {
+	s := newTestServer()
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/validate", strings.NewReader("google.com,pub-1,DIRECT\nopenx.com,540,RESELLER,not-a-tag-id")))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected HTTP status [200] and not [%d]: %s", rec.Code, rec.Body)
+	}
+
+	result := &ValidationResult{}
+	if err := json.Unmarshal(rec.Body.Bytes(), result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Valid || len(result.Errors) != 1 || result.Errors[0].Line != 2 || result.Errors[0].Field != "CertAuthorityID" {
+		t.Errorf("Expected single certification authority ID validation error and not %+v", result.Errors)
+	}
+	if result.Records == nil || len(result.Records.DataRecords) != 2 {
+		t.Errorf("Expected parsed Ads.txt records")
+	}
+
+	s.MaxBodySize = 8
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/validate", strings.NewReader("google.com,pub-1,DIRECT")))
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected HTTP status [413] for large body and not [%d]", rec.Code)
+	}
+}
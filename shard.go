@@ -0,0 +1,58 @@
+package adstxt
+
+import (
+	"hash/fnv"
+	"strings"
+)
+
+// ShardOf return the shard index (0 to shards-1) of domain, for splitting domain list across crawl workers or
+// machines. Domains are sharded by their root domain, so all Ads.txt requests of a site are crawled by the same
+// shard, and with consistent hashing (jump consistent hash), so changing the number of shards moves only the minimum
+// number of domains between shards. Shard index is stable across processes and versions
+func ShardOf(domain string, shards int) int {
+	if shards <= 1 {
+		return 0
+	}
+
+	root, err := rootDomain(domain)
+	if err != nil {
+		root = strings.ToLower(strings.TrimSpace(domain))
+	}
+	h := fnv.New64a()
+	h.Write([]byte(asciiHost(root)))
+	return jumpHash(h.Sum64(), shards)
+}
+
+// ShardRequests split Ads.txt requests across shards by the root domain of each request (see ShardOf), and return the
+// requests of each shard in their original order. Each node of a distributed crawl crawls the requests of its own
+// shard index, so no Ads.txt file is fetched by more than one node
+func ShardRequests(req []*Request, shards int) [][]*Request {
+	if shards < 1 {
+		shards = 1
+	}
+
+	sharded := make([][]*Request, shards)
+	for i := range sharded {
+		sharded[i] = []*Request{}
+	}
+	for _, r := range req {
+		domain := r.Domain
+		if len(domain) == 0 {
+			domain = r.URL
+		}
+		i := ShardOf(domain, shards)
+		sharded[i] = append(sharded[i], r)
+	}
+	return sharded
+}
+
+// jumpHash jump consistent hash of key into buckets (https://arxiv.org/abs/1406.2294)
+func jumpHash(key uint64, buckets int) int {
+	var b, j int64 = -1, 0
+	for j < int64(buckets) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return int(b)
+}
@@ -0,0 +1,62 @@
+package adstxt
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestShardOf test domains are sharded by root domain, and consistently when the number of shards changes
+func TestShardOf(t *testing.T) {
+	if ShardOf("www.greenadexchange.com", 16) != ShardOf("GreenAdExchange.com", 16) {
+		t.Errorf("Expected subdomain to be sharded by its root domain")
+	}
+	if s := ShardOf("greenadexchange.com", 1); s != 0 {
+		t.Errorf("Expected single shard index [0] and not [%d]", s)
+	}
+
+	// adding shard moves only domains that are sharded to the new shard
+	moved := 0
+	for i := 0; i < 1000; i++ {
+		d := fmt.Sprintf("domain%d.com", i)
+		a, b := ShardOf(d, 10), ShardOf(d, 11)
+		if a < 0 || a >= 10 {
+			t.Fatalf("Expected shard index of [%s] in range [0, 10) and not [%d]", d, a)
+		}
+		if a != b {
+			moved++
+			if b != 10 {
+				t.Errorf("Expected [%s] to move to the new shard and not [%d]", d, b)
+			}
+		}
+	}
+	if moved == 0 || moved > 200 {
+		t.Errorf("Expected about 1/11 of domains to move to the new shard and not [%d]", moved)
+	}
+}
+
+// TestShardRequests test requests are split across shards without duplicates, in their original order
+func TestShardRequests(t *testing.T) {
+	seeds := RequestsFromDomains([]string{"greenadexchange.com", "google.com", "example.com", "sub.example.com", "example.org"})
+
+	shards := ShardRequests(seeds.Requests, 3)
+	if len(shards) != 3 {
+		t.Fatalf("Expected [3] shards and not [%d]", len(shards))
+	}
+
+	total := 0
+	for i, shard := range shards {
+		for _, req := range shard {
+			if s := ShardOf(req.Domain, 3); s != i {
+				t.Errorf("Expected [%s] in shard [%d] and not [%d]", req.URL, s, i)
+			}
+		}
+		total += len(shard)
+	}
+	if total != len(seeds.Requests) {
+		t.Errorf("Expected [%d] sharded requests and not [%d]", len(seeds.Requests), total)
+	}
+
+	if ShardOf("sub.example.com", 3) != ShardOf("example.com", 3) {
+		t.Errorf("Expected requests of the same site in the same shard")
+	}
+}
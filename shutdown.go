@@ -0,0 +1,195 @@
+package adstxt
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Flusher handler (or sink) that buffers batch crawl results, and writes them by Flush, for example Checkpoint. Batch
+// crawl handlers that implement Flusher are flushed by Crawler.Shutdown
+type Flusher interface {
+	Flush() error
+}
+
+// ShutdownSummary summary of batch crawls shut down by Crawler.Shutdown
+type ShutdownSummary struct {
+	Unprocessed []*Request // Unprocessed requests that were not sent since the crawler shut down, or were aborted once the shutdown deadline was exceeded. Their results were passed to the handlers with ErrShutdown or the context error
+	Aborted     int        // Aborted number of in-flight requests that were aborted once the shutdown deadline was exceeded
+	Flushed     int        // Flushed number of batch crawl handlers that were flushed
+}
+
+// lifecycle batch crawls of crawler, and its shutdown state
+type lifecycle struct {
+	closing  chan struct{} // closed once the crawler shuts down
+	batches  map[*batch]bool
+	active   int             // number of batch crawls in progress
+	idle     []chan struct{} // closed once no batch crawl is in progress
+	summary  ShutdownSummary
+	flushErr error // first flush error of batch crawl handlers
+	once     sync.Once
+	mu       sync.Mutex
+}
+
+// batch single batch crawl
+type batch struct {
+	cancel   context.CancelFunc // abort in-flight requests of the batch crawl
+	flushers []Flusher          // handlers of the batch crawl, flushed if it ends once the crawler shuts down
+	aborted  bool
+}
+
+// newLifecycle create new crawler lifecycle
+func newLifecycle() *lifecycle {
+	return &lifecycle{closing: make(chan struct{}), batches: map[*batch]bool{}}
+}
+
+// start batch crawl: return batch context, that is canceled once the shutdown deadline is exceeded, and function that
+// ends the batch crawl. Handlers of ctx (see withFlushers) are flushed when the batch crawl ends after shutdown
+func (l *lifecycle) start(ctx context.Context) (context.Context, *batch, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	// handlers are flushed once: nested batch crawls (e.g. subdomains crawled by request of the batch) do not flush them
+	b := &batch{cancel: cancel, flushers: flushersFromContext(ctx)}
+	ctx = context.WithValue(ctx, flushersContextKey{}, []Flusher(nil))
+
+	l.mu.Lock()
+	l.batches[b] = true
+	l.active++
+	l.mu.Unlock()
+
+	return ctx, b, func() {
+		defer l.end()
+		cancel()
+
+		l.mu.Lock()
+		delete(l.batches, b)
+		l.mu.Unlock()
+		if !l.isClosing() {
+			return
+		}
+
+		for _, f := range b.flushers {
+			err := f.Flush()
+			l.mu.Lock()
+			l.summary.Flushed++
+			if err != nil && l.flushErr == nil {
+				l.flushErr = err
+			}
+			l.mu.Unlock()
+		}
+	}
+}
+
+// end batch crawl: notify the waiters once no batch crawl is in progress
+func (l *lifecycle) end() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.active--
+	if l.active > 0 {
+		return
+	}
+	for _, idle := range l.idle {
+		close(idle)
+	}
+	l.idle = nil
+}
+
+// wait return channel that is closed once no batch crawl is in progress
+func (l *lifecycle) wait() <-chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	idle := make(chan struct{})
+	if l.active == 0 {
+		close(idle)
+		return idle
+	}
+	l.idle = append(l.idle, idle)
+	return idle
+}
+
+// isClosing check if the crawler is shutting down
+func (l *lifecycle) isClosing() bool {
+	select {
+	case <-l.closing:
+		return true
+	default:
+		return false
+	}
+}
+
+// unprocessed record result of batch crawl request that was not sent or was aborted due to shutdown
+func (l *lifecycle) unprocessed(b *batch, r *multiResult) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	switch {
+	case errors.Is(r.err, ErrShutdown):
+		l.summary.Unprocessed = append(l.summary.Unprocessed, r.req)
+	case b.aborted && errors.Is(r.err, context.Canceled):
+		l.summary.Unprocessed = append(l.summary.Unprocessed, r.req)
+		l.summary.Aborted++
+	}
+}
+
+// abort cancel in-flight requests of all batch crawls
+func (l *lifecycle) abort() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for b := range l.batches {
+		b.aborted = true
+		b.cancel()
+	}
+}
+
+// Shutdown gracefully shut down batch crawls of the crawler (see GetMultipleWithContext), for example when service
+// that embeds the crawler receives SIGTERM: new requests are not sent, and their results are passed to the handler
+// with ErrShutdown, while in-flight requests are completed. Once ctx is done (e.g. shutdown deadline exceeded),
+// in-flight requests are aborted and Shutdown returns the context error. Handlers of the batch crawls that implement
+// Flusher are flushed once their batch crawl ended. Return summary of the requests that were not processed. Batch
+// crawls started after Shutdown do not send any request, and single requests (see GetWithContext) are not affected
+func (c *Crawler) Shutdown(ctx context.Context) (*ShutdownSummary, error) {
+	l := c.lifecycle
+	l.once.Do(func() { close(l.closing) })
+
+	done := l.wait()
+	var err error
+	select {
+	case <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+		// aborted requests return immediately, and their results are passed to the handlers
+		l.abort()
+		<-done
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err == nil {
+		err = l.flushErr
+	}
+	summary := l.summary
+	summary.Unprocessed = append([]*Request{}, l.summary.Unprocessed...)
+	return &summary, err
+}
+
+// flushersContextKey context key of batch crawl handlers flushed on shutdown
+type flushersContextKey struct{}
+
+// withFlushers return context of batch crawl, with the handlers that implement Flusher
+func withFlushers(ctx context.Context, handlers ...interface{}) context.Context {
+	flushers := flushersFromContext(ctx)
+	for _, h := range handlers {
+		if f, ok := h.(Flusher); ok {
+			flushers = append(flushers, f)
+		}
+	}
+	return context.WithValue(ctx, flushersContextKey{}, flushers)
+}
+
+// flushersFromContext return the batch crawl handlers of ctx that implement Flusher
+func flushersFromContext(ctx context.Context) []Flusher {
+	flushers, _ := ctx.Value(flushersContextKey{}).([]Flusher)
+	return append([]Flusher{}, flushers...)
+}
@@ -0,0 +1,225 @@
+package adstxt
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flushingHandler test handler that collects batch crawl results, and counts its flushes
+type flushingHandler struct {
+	mu      sync.Mutex
+	errs    map[string]error
+	flushed int
+}
+
+func (h *flushingHandler) Handle(req *Request, res *Response, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.errs[req.Domain] = err
+}
+
+func (h *flushingHandler) Flush() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.flushed++
+	return nil
+}
+
+// TestShutdown test graceful shutdown of batch crawl: in-flight requests are completed, and requests that were not
+// sent yet are passed to the handler with ErrShutdown
+func TestShutdown(t *testing.T) {
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			close(started)
+			select {
+			case <-unblock:
+			case <-req.Context().Done():
+				return nil, req.Context().Err()
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       io.NopCloser(strings.NewReader("greenadexchange.com,XF7342,DIRECT")),
+				Request:    req,
+			}, nil
+		}),
+	}
+	c := NewCrawler(WithHTTPClient(client), WithConcurrency(1))
+
+	req := []*Request{}
+	for _, d := range []string{"example.com", "pending1.com", "pending2.com"} {
+		r, _ := NewRequest(d)
+		req = append(req, r)
+	}
+
+	h := &flushingHandler{errs: map[string]error{}}
+	crawled := make(chan struct{})
+	go func() {
+		defer close(crawled)
+		c.GetMultipleWithContext(context.Background(), req, h)
+	}()
+	<-started
+
+	type shutdownResult struct {
+		summary *ShutdownSummary
+		err     error
+	}
+	result := make(chan shutdownResult)
+	go func() {
+		summary, err := c.Shutdown(context.Background())
+		result <- shutdownResult{summary, err}
+	}()
+
+	// Shutdown waits for the in-flight request
+	select {
+	case <-result:
+		t.Fatal("Expected Shutdown to wait for in-flight request")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(unblock)
+	r := <-result
+	<-crawled
+
+	if r.err != nil {
+		t.Fatal(r.err)
+	}
+	if err := h.errs["example.com"]; err != nil {
+		t.Errorf("Expected in-flight request to complete and not [%s]", err)
+	}
+	for _, d := range []string{"pending1.com", "pending2.com"} {
+		if err := h.errs[d]; !errors.Is(err, ErrShutdown) {
+			t.Errorf("Expected ErrShutdown of [%s] request that was not sent and not [%v]", d, err)
+		}
+	}
+	if len(r.summary.Unprocessed) != 2 || r.summary.Aborted != 0 {
+		t.Errorf("Expected summary of [2] unprocessed requests and not %+v", r.summary)
+	}
+	if h.flushed != 1 || r.summary.Flushed != 1 {
+		t.Errorf("Expected handler to be flushed once and not [%d]", h.flushed)
+	}
+
+	// batch crawl after shutdown does not send any request
+	after := &flushingHandler{errs: map[string]error{}}
+	c.GetMultipleWithContext(context.Background(), req[:1], after)
+	if err := after.errs["example.com"]; !errors.Is(err, ErrShutdown) {
+		t.Errorf("Expected ErrShutdown of batch crawl after shutdown and not [%v]", err)
+	}
+}
+
+// TestShutdownDeadline test shutdown that exceeds its deadline: in-flight requests are aborted
+func TestShutdownDeadline(t *testing.T) {
+	started := make(chan struct{})
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			close(started)
+			<-req.Context().Done()
+			return nil, req.Context().Err()
+		}),
+	}
+	c := NewCrawler(WithHTTPClient(client), WithConcurrency(1))
+
+	r, _ := NewRequest("example.com")
+	h := &flushingHandler{errs: map[string]error{}}
+	crawled := make(chan struct{})
+	go func() {
+		defer close(crawled)
+		c.GetMultipleWithContext(context.Background(), []*Request{r}, h)
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	summary, err := c.Shutdown(ctx)
+	<-crawled
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected shutdown deadline error and not [%v]", err)
+	}
+	if summary.Aborted != 1 || len(summary.Unprocessed) != 1 || summary.Unprocessed[0] != r {
+		t.Errorf("Expected summary of [1] aborted request and not %+v", summary)
+	}
+	if !errors.Is(h.errs["example.com"], context.Canceled) {
+		t.Errorf("Expected aborted request context error and not [%v]", h.errs["example.com"])
+	}
+	if h.flushed != 1 {
+		t.Errorf("Expected handler to be flushed once and not [%d]", h.flushed)
+	}
+}
+
+// TestShutdownRescheduled test throttled request waiting for free request slot to be rescheduled is passed to the
+// handler with ErrShutdown, and is not sent once the slot is released
+func TestShutdownRescheduled(t *testing.T) {
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	var mu sync.Mutex
+	throttled := 0
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			status := http.StatusOK
+			if req.URL.Hostname() == "throttled.com" {
+				mu.Lock()
+				if throttled++; throttled == 1 {
+					status = http.StatusTooManyRequests
+				}
+				mu.Unlock()
+			} else {
+				close(started)
+				<-unblock
+			}
+			return &http.Response{
+				StatusCode: status,
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       io.NopCloser(strings.NewReader("greenadexchange.com,XF7342,DIRECT")),
+				Request:    req,
+			}, nil
+		}),
+	}
+	c := NewCrawler(WithHTTPClient(client), WithConcurrency(1),
+		WithThrottlePolicy(ThrottlePolicy{MaxReschedules: 1, DefaultDelay: 10 * time.Millisecond}))
+
+	req := []*Request{}
+	for _, d := range []string{"throttled.com", "blocking.com"} {
+		r, _ := NewRequest(d)
+		req = append(req, r)
+	}
+
+	h := &flushingHandler{errs: map[string]error{}}
+	crawled := make(chan struct{})
+	go func() {
+		defer close(crawled)
+		c.GetMultipleWithContext(context.Background(), req, h)
+	}()
+	// throttled request waits for the request slot held by the blocking request
+	<-started
+	time.Sleep(50 * time.Millisecond)
+
+	result := make(chan error)
+	go func() {
+		_, err := c.Shutdown(context.Background())
+		result <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(unblock)
+	if err := <-result; err != nil {
+		t.Fatal(err)
+	}
+	<-crawled
+
+	if err := h.errs["throttled.com"]; !errors.Is(err, ErrShutdown) {
+		t.Errorf("Expected ErrShutdown of rescheduled request and not [%v]", err)
+	}
+	if err := h.errs["blocking.com"]; err != nil {
+		t.Errorf("Expected in-flight request to complete and not [%s]", err)
+	}
+	if throttled != 1 {
+		t.Errorf("Expected rescheduled request not to be sent after shutdown and not [%d] requests", throttled)
+	}
+}
@@ -0,0 +1,150 @@
+package adstxt
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// SinkMessage single crawl result published to Sink
+type SinkMessage struct {
+	Domain   string    `json:"domain"`             // Domain root domain of the Ads.txt request
+	URL      string    `json:"url"`                // URL Ads.txt URL of the request (before redirects)
+	Status   string    `json:"status"`             // Status request status class (see StatusClass)
+	Time     time.Time `json:"time"`               // Time the request was completed
+	Response *Response `json:"response,omitempty"` // Response parsed Ads.txt file, nil if the request failed
+	Error    string    `json:"error,omitempty"`    // Error request error message, empty if the request succeeded
+}
+
+// Sink publish crawl results as they are completed (for example to message queue or data warehouse loader), so
+// results of large batch crawl are not collected in memory. Implementations must be safe to use from multiple
+// goroutines
+type Sink interface {
+	Publish(ctx context.Context, msg *SinkMessage) error
+}
+
+// SinkFunc is a function signature that implements the Sink interface
+type SinkFunc func(ctx context.Context, msg *SinkMessage) error
+
+// Publish is the Sink interface implementation for the SinkFunc type
+func (f SinkFunc) Publish(ctx context.Context, msg *SinkMessage) error {
+	return f(ctx, msg)
+}
+
+// WriterSink Sink that writes each message as single JSON line to io.Writer. Each message is written with a single
+// Write call, so writers of message based queues receive one message per Write
+type WriterSink struct {
+	w  io.Writer
+	mu sync.Mutex
+}
+
+// NewWriterSink create new WriterSink that writes messages to w
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w}
+}
+
+// Publish is the Sink interface implementation for WriterSink
+func (s *WriterSink) Publish(ctx context.Context, msg *SinkMessage) error {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err = s.w.Write(append(b, '\n'))
+	return err
+}
+
+// MessageSink Sink that publishes each message as JSON value keyed by the request domain to key-value message queue,
+// such as Kafka topic (messages of the same domain are published to the same partition). Use it with any queue client
+// by wrapping the client publish call, for example with github.com/segmentio/kafka-go:
+//
+//	sink := adstxt.NewMessageSink(func(ctx context.Context, key, value []byte) error {
+//		return writer.WriteMessages(ctx, kafka.Message{Key: key, Value: value})
+//	})
+type MessageSink struct {
+	publish func(ctx context.Context, key, value []byte) error
+}
+
+// NewMessageSink create new MessageSink that publishes messages with publish function
+func NewMessageSink(publish func(ctx context.Context, key, value []byte) error) *MessageSink {
+	return &MessageSink{publish: publish}
+}
+
+// Publish is the Sink interface implementation for MessageSink
+func (s *MessageSink) Publish(ctx context.Context, msg *SinkMessage) error {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return s.publish(ctx, []byte(msg.Domain), b)
+}
+
+// SinkHandler Handler that publishes each crawl result to Sink, and then passes the result to the next handler.
+// Publish errors do not stop the crawl: use Err and Failed to check whether all results were published
+type SinkHandler struct {
+	DropRawLines bool // DropRawLines pass response without raw Ads.txt body lines (Records.Body) to the next handler once published
+
+	sink   Sink
+	h      Handler
+	failed int   // number of results that failed to be published
+	err    error // first publish error
+	mu     sync.Mutex
+}
+
+// NewSinkHandler create new SinkHandler that publishes crawl results to sink and then passes them to h (h may be
+// nil)
+func NewSinkHandler(sink Sink, h Handler) *SinkHandler {
+	return &SinkHandler{sink: sink, h: h}
+}
+
+// Handle is the Handler interface implementation for SinkHandler
+func (s *SinkHandler) Handle(req *Request, res *Response, err error) {
+	msg := &SinkMessage{Domain: req.Domain, URL: req.URL, Status: StatusClass(res, err), Time: time.Now().UTC(), Response: res}
+	if res != nil {
+		msg.URL = responseKey(req, res)
+	}
+	if err != nil {
+		msg.Error = err.Error()
+	}
+
+	if perr := s.sink.Publish(context.Background(), msg); perr != nil {
+		s.mu.Lock()
+		s.failed++
+		if s.err == nil {
+			s.err = perr
+		}
+		s.mu.Unlock()
+	}
+
+	if s.h != nil {
+		if s.DropRawLines && res != nil && res.Records != nil {
+			// response may be shared (for example cached response): pass copy without the raw lines
+			r, records := *res, *res.Records
+			records.Body = nil
+			r.Records, r.RawBody = &records, nil
+			res = &r
+		}
+		s.h.Handle(req, res, err)
+	}
+}
+
+// Err return the first publish error, or nil if all results were published
+func (s *SinkHandler) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.err
+}
+
+// Failed return the number of results that failed to be published
+func (s *SinkHandler) Failed() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.failed
+}
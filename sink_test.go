@@ -0,0 +1,89 @@
+package adstxt
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// TestSinkHandler test publishing batch crawl results to writer sink as JSON lines
+func TestSinkHandler(t *testing.T) {
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Host == "missing.com" {
+				return &http.Response{StatusCode: http.StatusNotFound, Status: "404 Not Found", Body: http.NoBody, Request: req}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       io.NopCloser(strings.NewReader("greenadexchange.com,XF7342,DIRECT")),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	req1, _ := NewRequest("example.com")
+	req2, _ := NewRequest("missing.com")
+
+	var out bytes.Buffer
+	handled := 0
+	h := NewSinkHandler(NewWriterSink(&out), HandlerFunc(func(*Request, *Response, error) { handled++ }))
+	NewCrawler(WithHTTPClient(client), WithOrderedResults(true)).GetMultiple([]*Request{req1, req2}, h)
+
+	if h.Err() != nil || h.Failed() != 0 || handled != 2 {
+		t.Errorf("Expected all results to be published and passed to next handler")
+	}
+
+	messages := []*SinkMessage{}
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		msg := &SinkMessage{}
+		if err := json.Unmarshal(scanner.Bytes(), msg); err != nil {
+			t.Fatal(err)
+		}
+		messages = append(messages, msg)
+	}
+	if len(messages) != 2 {
+		t.Fatalf("Expected [2] JSON lines and not [%d]", len(messages))
+	}
+	if messages[0].Domain != "example.com" || messages[0].Status != "2xx" || messages[0].Response == nil || len(messages[0].Response.DataRecords) != 1 {
+		t.Errorf("Expected [example.com] message with parsed records")
+	}
+	if messages[1].Domain != "missing.com" || messages[1].Status != "4xx" || messages[1].Response != nil || len(messages[1].Error) == 0 {
+		t.Errorf("Expected [missing.com] message with request error")
+	}
+
+	// publish errors are counted
+	publishErr := errors.New("queue unavailable")
+	h = NewSinkHandler(SinkFunc(func(context.Context, *SinkMessage) error { return publishErr }), nil)
+	NewCrawler(WithHTTPClient(client)).GetMultiple([]*Request{req1, req2}, h)
+	if h.Err() != publishErr || h.Failed() != 2 {
+		t.Errorf("Expected publish errors to be reported and not [%v] [%d]", h.Err(), h.Failed())
+	}
+}
+
+// TestMessageSink test publishing crawl results as key-value messages
+func TestMessageSink(t *testing.T) {
+	var key, value []byte
+	s := NewMessageSink(func(ctx context.Context, k, v []byte) error {
+		key, value = k, v
+		return nil
+	})
+
+	if err := s.Publish(context.Background(), &SinkMessage{Domain: "example.com", URL: "http://example.com/ads.txt", Status: "2xx"}); err != nil {
+		t.Fatal(err)
+	}
+	if string(key) != "example.com" {
+		t.Errorf("Expected message key [example.com] and not [%s]", key)
+	}
+	msg := &SinkMessage{}
+	if err := json.Unmarshal(value, msg); err != nil || msg.URL != "http://example.com/ads.txt" {
+		t.Errorf("Expected message value to be JSON encoded crawl result")
+	}
+}
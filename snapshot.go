@@ -0,0 +1,217 @@
+package adstxt
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// CrawlSnapshot results of batch crawl: the Ads.txt file of each crawled domain, by lower case domain. Crawled domain
+// without Ads.txt file (failed request) has nil Records. CrawlSnapshot implements the Handler interface, so it can be
+// collected while crawling, or loaded from crawl results file (see LoadCrawlSnapshot), and it is safe to use from
+// multiple goroutines
+type CrawlSnapshot struct {
+	domains map[string]*Records
+	mu      sync.Mutex
+}
+
+// NewCrawlSnapshot create new empty CrawlSnapshot
+func NewCrawlSnapshot() *CrawlSnapshot {
+	return &CrawlSnapshot{domains: map[string]*Records{}}
+}
+
+// LoadCrawlSnapshot load CrawlSnapshot from crawl results: JSON lines of crawl results (see NewJSONLHandler), or JSON
+// array of crawl results (as written by the adstxt batch command), each with "domain", "response" and "error" fields
+func LoadCrawlSnapshot(r io.Reader) (*CrawlSnapshot, error) {
+	br := bufio.NewReader(r)
+	dec := json.NewDecoder(br)
+
+	// JSON array of results is decoded element by element, as JSON lines
+	if b, err := peekNonSpace(br); err == nil && b == '[' {
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+	}
+
+	s := NewCrawlSnapshot()
+	for dec.More() {
+		msg := &SinkMessage{}
+		if err := dec.Decode(msg); err != nil {
+			return nil, err
+		}
+		if msg.Response != nil && len(msg.Error) == 0 {
+			s.Add(msg.Domain, msg.Response.Records)
+			continue
+		}
+		s.Add(msg.Domain, nil)
+	}
+	return s, nil
+}
+
+// peekNonSpace return the first non whitespace byte of r, without consuming it
+func peekNonSpace(r *bufio.Reader) (byte, error) {
+	for {
+		b, err := r.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		if !strings.ContainsRune(" \t\r\n", rune(b[0])) {
+			return b[0], nil
+		}
+		r.ReadByte()
+	}
+}
+
+// Handle is the Handler interface implementation for CrawlSnapshot: add the request domain Ads.txt file records, or
+// the domain without Ads.txt file if the request failed. NotModified responses are ignored, as they do not hold the
+// Ads.txt file records
+func (s *CrawlSnapshot) Handle(req *Request, res *Response, err error) {
+	switch {
+	case err != nil || res == nil:
+		s.Add(req.Domain, nil)
+	case !res.NotModified:
+		s.Add(req.Domain, res.Records)
+	}
+}
+
+// Add Ads.txt file records of domain to the snapshot, or domain without Ads.txt file if records is nil. Records replace
+// any records previously added for the domain
+func (s *CrawlSnapshot) Add(domain string, records *Records) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.domains[strings.ToLower(strings.TrimSpace(domain))] = records
+}
+
+// Records return Ads.txt file records of domain, and whether the domain was crawled. Records are nil if the domain has
+// no Ads.txt file
+func (s *CrawlSnapshot) Records(domain string) (*Records, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.domains[strings.ToLower(strings.TrimSpace(domain))]
+	return r, ok
+}
+
+// Domains return all crawled domains of the snapshot, sorted
+func (s *CrawlSnapshot) Domains() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	domains := make([]string, 0, len(s.domains))
+	for d := range s.domains {
+		domains = append(domains, d)
+	}
+	sort.Strings(domains)
+	return domains
+}
+
+// SnapshotDiff changes between two batch crawl snapshots (see DiffSnapshots): domains that gained or lost their
+// Ads.txt file, the records changes of domains with changed Ads.txt file, and the changes aggregated by advertising
+// system
+type SnapshotDiff struct {
+	GainedFile []string                 `json:"gainedFile"` // GainedFile domains with Ads.txt file only in the new snapshot
+	LostFile   []string                 `json:"lostFile"`   // LostFile domains with Ads.txt file only in the old snapshot
+	Changed    map[string]*RecordsDiff  `json:"changed"`    // Changed records changes of domains with changed Ads.txt file, by domain
+	AdSystems  map[string]*AdSystemDiff `json:"adSystems"`  // AdSystems changes aggregated by canonical advertising system domain (see CanonicalAdSystem)
+}
+
+// AdSystemDiff changes of single advertising system between two batch crawl snapshots
+type AdSystemDiff struct {
+	GainedDomains   int `json:"gainedDomains"`   // GainedDomains domains that gained Ads.txt file with records of the advertising system
+	LostDomains     int `json:"lostDomains"`     // LostDomains domains that lost Ads.txt file with records of the advertising system
+	ChangedDomains  int `json:"changedDomains"`  // ChangedDomains domains with changed records of the advertising system
+	AddedRecords    int `json:"addedRecords"`    // AddedRecords data records of the advertising system found only in the new snapshot
+	RemovedRecords  int `json:"removedRecords"`  // RemovedRecords data records of the advertising system found only in the old snapshot
+	ModifiedRecords int `json:"modifiedRecords"` // ModifiedRecords data records of the advertising system with changed account type or certification authority ID
+}
+
+// DiffSnapshots compare two batch crawl snapshots, for example of week over week crawls of the same domain list, and
+// return the domains that gained Ads.txt file, lost Ads.txt file or changed their Ads.txt file records (see Diff) in
+// the new snapshot. Domain that was crawled in one snapshot only is treated as domain without Ads.txt file in the
+// other snapshot
+func DiffSnapshots(old, new *CrawlSnapshot) *SnapshotDiff {
+	d := &SnapshotDiff{
+		GainedFile: []string{},
+		LostFile:   []string{},
+		Changed:    map[string]*RecordsDiff{},
+		AdSystems:  map[string]*AdSystemDiff{},
+	}
+
+	domains := map[string]bool{}
+	for _, domain := range old.Domains() {
+		domains[domain] = true
+	}
+	for _, domain := range new.Domains() {
+		domains[domain] = true
+	}
+
+	sorted := make([]string, 0, len(domains))
+	for domain := range domains {
+		sorted = append(sorted, domain)
+	}
+	sort.Strings(sorted)
+
+	for _, domain := range sorted {
+		o, _ := old.Records(domain)
+		n, _ := new.Records(domain)
+		switch {
+		case o == nil && n == nil:
+			continue
+		case o == nil:
+			d.GainedFile = append(d.GainedFile, domain)
+		case n == nil:
+			d.LostFile = append(d.LostFile, domain)
+		}
+
+		diff := Diff(o, n)
+		if diff.Empty() {
+			continue
+		}
+		if o != nil && n != nil {
+			d.Changed[domain] = diff
+		}
+		d.aggregate(diff, o == nil, n == nil)
+	}
+	return d
+}
+
+// aggregate add records changes of single domain to the advertising systems changes. gained and lost are set when the
+// domain gained or lost its Ads.txt file
+func (d *SnapshotDiff) aggregate(diff *RecordsDiff, gained, lost bool) {
+	// each domain is counted once per advertising system
+	counted := map[string]bool{}
+	adSystem := func(r *DataRecord) *AdSystemDiff {
+		c := CanonicalAdSystem(r.AdverterDomain)
+		s, ok := d.AdSystems[c]
+		if !ok {
+			s = &AdSystemDiff{}
+			d.AdSystems[c] = s
+		}
+		if !counted[c] {
+			counted[c] = true
+			switch {
+			case gained:
+				s.GainedDomains++
+			case lost:
+				s.LostDomains++
+			default:
+				s.ChangedDomains++
+			}
+		}
+		return s
+	}
+
+	for _, r := range diff.AddedRecords {
+		adSystem(r).AddedRecords++
+	}
+	for _, r := range diff.RemovedRecords {
+		adSystem(r).RemovedRecords++
+	}
+	for _, c := range diff.ModifiedRecords {
+		adSystem(c.New).ModifiedRecords++
+	}
+}
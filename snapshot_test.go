@@ -0,0 +1,88 @@
+package adstxt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestDiffSnapshots test batch crawl snapshots diff of domains that gained, lost or changed Ads.txt file
+func TestDiffSnapshots(t *testing.T) {
+	parse := func(body string) *Records {
+		records, err := ParseBody([]byte(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return records
+	}
+
+	old := NewCrawlSnapshot()
+	old.Add("lost.com", parse("greenadexchange.com,XF7342,DIRECT"))
+	old.Add("changed.com", parse("greenadexchange.com,XF7342,DIRECT\ngoogle.com,pub-1,RESELLER"))
+	old.Add("same.com", parse("google.com,pub-2,DIRECT"))
+	old.Add("gained.com", nil)
+
+	new := NewCrawlSnapshot()
+	new.Handle(&Request{Domain: "lost.com"}, nil, errors.New("HTTP 404"))
+	new.Add("changed.com", parse("greenadexchange.com,XF7342,RESELLER\ngoogle.com,pub-3,DIRECT"))
+	new.Add("same.com", parse("google.com, pub-2, direct"))
+	new.Add("Gained.com", parse("google.com,pub-4,DIRECT"))
+
+	d := DiffSnapshots(old, new)
+	if len(d.GainedFile) != 1 || d.GainedFile[0] != "gained.com" {
+		t.Errorf("Expected [gained.com] to gain Ads.txt file and not %v", d.GainedFile)
+	}
+	if len(d.LostFile) != 1 || d.LostFile[0] != "lost.com" {
+		t.Errorf("Expected [lost.com] to lose Ads.txt file and not %v", d.LostFile)
+	}
+	if len(d.Changed) != 1 || d.Changed["changed.com"] == nil {
+		t.Fatalf("Expected only [changed.com] Ads.txt file to change and not %v", d.Changed)
+	}
+	if c := d.Changed["changed.com"]; len(c.ModifiedRecords) != 1 || len(c.AddedRecords) != 1 || len(c.RemovedRecords) != 1 {
+		t.Errorf("Expected modified, added and removed records of [changed.com] and not [%+v]", c)
+	}
+
+	google := d.AdSystems["google.com"]
+	if google == nil || google.GainedDomains != 1 || google.ChangedDomains != 1 || google.LostDomains != 0 ||
+		google.AddedRecords != 2 || google.RemovedRecords != 1 {
+		t.Errorf("Expected aggregated changes of [google.com] and not [%+v]", google)
+	}
+	exchange := d.AdSystems["greenadexchange.com"]
+	if exchange == nil || exchange.LostDomains != 1 || exchange.ChangedDomains != 1 || exchange.ModifiedRecords != 1 ||
+		exchange.RemovedRecords != 1 {
+		t.Errorf("Expected aggregated changes of [greenadexchange.com] and not [%+v]", exchange)
+	}
+}
+
+// TestLoadCrawlSnapshot test load crawl snapshot from JSON lines and JSON array of crawl results
+func TestLoadCrawlSnapshot(t *testing.T) {
+	res, err := NewResponse(&Request{Domain: "example.com"}, []byte("greenadexchange.com,XF7342,DIRECT"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var lines bytes.Buffer
+	h := NewJSONLHandler(&lines)
+	h.Handle(res.Request, res, nil)
+	h.Handle(&Request{Domain: "missing.com"}, nil, ErrNotFound)
+
+	results, err := json.Marshal([]*SinkMessage{{Domain: "example.com", Response: res}, {Domain: "missing.com", Error: "not found"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, input := range map[string]string{"lines": lines.String(), "array": " \n" + string(results)} {
+		s, err := LoadCrawlSnapshot(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("Expected crawl snapshot of JSON %s [%s]", name, err)
+		}
+		if r, ok := s.Records("example.com"); !ok || r == nil || len(r.DataRecords) != 1 {
+			t.Errorf("Expected Ads.txt file of [example.com] in crawl snapshot of JSON %s", name)
+		}
+		if r, ok := s.Records("missing.com"); !ok || r != nil {
+			t.Errorf("Expected [missing.com] without Ads.txt file in crawl snapshot of JSON %s", name)
+		}
+	}
+}
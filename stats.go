@@ -0,0 +1,98 @@
+package adstxt
+
+import (
+	"strings"
+)
+
+// ParseStats line statistics of Ads.txt file, counted while the file is parsed, so data quality metrics of batch
+// crawls are computed without iterating the records and body lines
+type ParseStats struct {
+	TotalLines     int `json:"totalLines"`     // TotalLines number of lines in the Ads.txt file
+	BlankLines     int `json:"blankLines"`     // BlankLines number of empty or whitespace only lines
+	CommentLines   int `json:"commentLines"`   // CommentLines number of full-line comments
+	ValidRecords   int `json:"validRecords"`   // ValidRecords number of lines parsed into data record
+	InvalidRecords int `json:"invalidRecords"` // InvalidRecords number of lines that failed to be parsed into Data\Variable record
+	Variables      int `json:"variables"`      // Variables number of lines parsed into variable
+}
+
+// add count parsed Ads.txt line. The IAB placeholder record line is counted only in the total number of lines
+func (s *ParseStats) add(l *Line) {
+	s.TotalLines++
+	switch {
+	case l.DataRecord != nil:
+		s.ValidRecords++
+	case l.Variable != nil:
+		s.Variables++
+	case l.Warning != nil:
+		s.InvalidRecords++
+	case l.Placeholder:
+	case len(strings.TrimSpace(l.Text)) == 0:
+		s.BlankLines++
+	case isCommentLine(l.Text):
+		s.CommentLines++
+	}
+}
+
+// RecordsStats summary statistics of Ads.txt records, for monitoring Ads.txt files without iterating the records
+type RecordsStats struct {
+	DataRecords  int            `json:"dataRecords"`  // DataRecords number of parsed data records
+	Direct       int            `json:"direct"`       // Direct number of DIRECT data records
+	Reseller     int            `json:"reseller"`     // Reseller number of RESELLER data records
+	AdSystems    int            `json:"adSystems"`    // AdSystems number of distinct advertising systems domains
+	Duplicates   int            `json:"duplicates"`   // Duplicates number of data records and variables that repeat a previous record (after normalization)
+	Variables    map[string]int `json:"variables"`    // Variables number of variables by lower case variable type
+	Warnings     int            `json:"warnings"`     // Warnings number of parse warnings
+	InvalidLines int            `json:"invalidLines"` // InvalidLines number of lines that failed to be parsed into Data\Variable record
+}
+
+// Stats return summary statistics of Ads.txt records
+func (r *Records) Stats() *RecordsStats {
+	s := &RecordsStats{DataRecords: len(r.DataRecords), Variables: map[string]int{}, Warnings: len(r.Warnings)}
+
+	parsed := map[int]bool{}
+	seen := map[string]bool{}
+	adSystems := map[string]bool{}
+	for _, dr := range r.DataRecords {
+		parsed[r.Line(dr)] = true
+
+		n := dr.normalized()
+		switch n.AccountType {
+		case accountTypeDirect:
+			s.Direct++
+		case accountTypeReseller:
+			s.Reseller++
+		}
+		adSystems[n.AdverterDomain] = true
+
+		if c := dr.canonical(); seen[c] {
+			s.Duplicates++
+		} else {
+			seen[c] = true
+		}
+	}
+	s.AdSystems = len(adSystems)
+
+	for _, v := range r.Variables {
+		parsed[r.Line(v)] = true
+
+		n := v.normalized()
+		s.Variables[n.Type]++
+
+		if c := v.canonical(); seen[c] {
+			s.Duplicates++
+		} else {
+			seen[c] = true
+		}
+	}
+
+	// lines with warnings that produced no record were skipped by the parser (line index 0 is file level warning)
+	invalid := map[int]bool{}
+	for _, w := range r.Warnings {
+		if w.Index > 0 && !parsed[w.Index] {
+			invalid[w.Index] = true
+		}
+	}
+	s.InvalidLines = len(invalid)
+
+	return s
+}
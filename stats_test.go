@@ -0,0 +1,71 @@
+package adstxt
+
+import "testing"
+
+// TestRecordsStats test Ads.txt records summary statistics
+func TestRecordsStats(t *testing.T) {
+	records, err := ParseBody([]byte(`greenadexchange.com, XF7342, DIRECT
+GreenAdExchange.com,XF7342,direct
+google.com, pub-1, RESELLER
+google.com, pub-2, DIRECT
+this is not a record
+unknown.invalid#, 1, DIRECT
+contact=adops@example.com
+CONTACT=adops@example.com
+subdomain=divisionone.example.com`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := records.Stats()
+	if s.DataRecords != 4 || s.Direct != 3 || s.Reseller != 1 {
+		t.Errorf("Expected [4] data records, [3] DIRECT and [1] RESELLER and not %+v", s)
+	}
+	if s.AdSystems != 2 {
+		t.Errorf("Expected [2] advertising systems and not [%d]", s.AdSystems)
+	}
+	if s.Duplicates != 2 {
+		t.Errorf("Expected [2] duplicates and not [%d]", s.Duplicates)
+	}
+	if s.Variables["contact"] != 2 || s.Variables["subdomain"] != 1 {
+		t.Errorf("Expected variable counts by type and not %v", s.Variables)
+	}
+	if s.InvalidLines != 2 || s.Warnings < 2 {
+		t.Errorf("Expected [2] invalid lines and not [%d]", s.InvalidLines)
+	}
+}
+
+// TestParseStats test Ads.txt line statistics counted while parsing
+func TestParseStats(t *testing.T) {
+	records, err := ParseBody([]byte("# Ads.txt file\n" +
+		"greenadexchange.com, XF7342, DIRECT # inline comment\n" +
+		"\n" +
+		"   \n" +
+		"google.com, pub-1, RESELLER\n" +
+		"this is not a record\n" +
+		"contact=adops@example.com\n" +
+		"ownerdomain=example.com\n" +
+		"ownerdomain=example.org\n" +
+		"  # trailing comment"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := ParseStats{TotalLines: 10, BlankLines: 2, CommentLines: 2, ValidRecords: 2, InvalidRecords: 2, Variables: 2}
+	if records.ParseStats != expected {
+		t.Errorf("Expected parse stats %+v and not %+v", expected, records.ParseStats)
+	}
+
+	// appended lines are counted, and stats are kept in JSON form
+	if err := records.AppendParse([]byte("google.com, pub-2, DIRECT\n"), 11); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := records.MarshalJSON()
+	decoded := &Records{}
+	if err := decoded.UnmarshalJSON(b); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.ParseStats.TotalLines != 11 || decoded.ParseStats.ValidRecords != 3 {
+		t.Errorf("Expected decoded parse stats of appended records and not %+v", decoded.ParseStats)
+	}
+}
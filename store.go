@@ -0,0 +1,259 @@
+package adstxt
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrResponseNotFound Store does not hold Ads.txt response for the requested key
+var ErrResponseNotFound = errors.New("response not found")
+
+// Store persist Ads.txt responses by key between crawler runs. As for Cache, the key identifies a single Ads.txt file
+// (the request Ads.txt URL before redirects). Use ListExpired to re-crawl only Ads.txt files whose response expired
+// (based on response Expires). Implementations must be safe to use from multiple goroutines
+type Store interface {
+	// SaveResponse save Ads.txt response, replacing any previous response stored with the same key
+	SaveResponse(key string, res *Response) error
+	// LoadResponse load Ads.txt response, or return ErrResponseNotFound if no response is stored with the key
+	LoadResponse(key string) (*Response, error)
+	// ListExpired return keys of all stored Ads.txt responses that expire before now
+	ListExpired(now time.Time) ([]string, error)
+}
+
+// FileStore Store that saves each Ads.txt response as JSON file in a local directory
+type FileStore struct {
+	dir     string
+	content bool  // records are saved in content addressed objects (see NewContentFileStore)
+	codec   Codec // compression of content addressed objects, uncompressed if nil
+	mu      sync.RWMutex
+}
+
+// fileStoreEntry single FileStore file content
+type fileStoreEntry struct {
+	Key      string    `json:"key"`
+	Response *Response `json:"response"`
+	Records  string    `json:"records,omitempty"` // SHA-256 digest of the response records content addressed object
+}
+
+// NewFileStore create new FileStore that saves Ads.txt responses in dir. The directory is created if it does not
+// exist
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// SaveResponse is the Store interface implementation for FileStore
+func (s *FileStore) SaveResponse(key string, res *Response) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e := &fileStoreEntry{Key: key, Response: res}
+	if s.content && res.Records != nil {
+		digest, err := s.saveObject(res.Records)
+		if err != nil {
+			return err
+		}
+		// records are saved in the object, so entry holds only the response metadata
+		meta := *res
+		meta.Records = nil
+		e.Response, e.Records = &meta, digest
+	}
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return writeFile(s.dir, s.path(key), b)
+}
+
+// writeFile write file content to temporary file in dir first, and then rename it to path, so a failed write does
+// not corrupt previously written file
+func writeFile(dir, path string, b []byte) error {
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// LoadResponse is the Store interface implementation for FileStore
+func (s *FileStore) LoadResponse(key string) (*Response, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, err := s.read(s.path(key))
+	if os.IsNotExist(err) {
+		return nil, ErrResponseNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if e.Records != "" && e.Response != nil {
+		if e.Response.Records, err = s.loadObject(e.Records); err != nil {
+			return nil, err
+		}
+	}
+	return e.Response, nil
+}
+
+// ListExpired is the Store interface implementation for FileStore
+func (s *FileStore) ListExpired(now time.Time) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	files, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []string{}
+	for _, f := range files {
+		e, err := s.read(f)
+		if err != nil {
+			return nil, err
+		}
+		if e.Response == nil || e.Response.Expires.Before(now) {
+			keys = append(keys, e.Key)
+		}
+	}
+
+	return keys, nil
+}
+
+// read Ads.txt response file
+func (s *FileStore) read(path string) (*fileStoreEntry, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &fileStoreEntry{}
+	if err := json.Unmarshal(b, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// path return Ads.txt response file path: keys are URLs, so file name is the key SHA-256 digest
+func (s *FileStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// SQLiteStore Store that saves Ads.txt responses in SQLite database table. SQLiteStore uses database/sql, so the
+// caller has to open the database with SQLite driver of its choice (e.g. github.com/mattn/go-sqlite3 or
+// modernc.org/sqlite)
+type SQLiteStore struct {
+	db    *sql.DB
+	table string
+}
+
+// NewSQLiteStore create new SQLiteStore that saves Ads.txt responses in table of db, and create the table if it does
+// not exist
+func NewSQLiteStore(db *sql.DB, table string) (*SQLiteStore, error) {
+	s := &SQLiteStore{db: db, table: `"` + strings.ReplaceAll(table, `"`, `""`) + `"`}
+
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS ` + s.table + ` (
+		key TEXT PRIMARY KEY,
+		expires INTEGER NOT NULL,
+		response BLOB NOT NULL
+	)`)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.Exec(`CREATE INDEX IF NOT EXISTS ` + strings.TrimSuffix(s.table, `"`) + `_expires" ON ` + s.table + ` (expires)`)
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// SaveResponse is the Store interface implementation for SQLiteStore
+func (s *SQLiteStore) SaveResponse(key string, res *Response) error {
+	b, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`INSERT OR REPLACE INTO `+s.table+` (key, expires, response) VALUES (?, ?, ?)`, key, sqliteTime(res.Expires), b)
+	return err
+}
+
+// LoadResponse is the Store interface implementation for SQLiteStore
+func (s *SQLiteStore) LoadResponse(key string) (*Response, error) {
+	var b []byte
+	err := s.db.QueryRow(`SELECT response FROM `+s.table+` WHERE key = ?`, key).Scan(&b)
+	if err == sql.ErrNoRows {
+		return nil, ErrResponseNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Response{}
+	if err := json.Unmarshal(b, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ListExpired is the Store interface implementation for SQLiteStore
+func (s *SQLiteStore) ListExpired(now time.Time) ([]string, error) {
+	rows, err := s.db.Query(`SELECT key FROM `+s.table+` WHERE expires < ? ORDER BY expires`, sqliteTime(now))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []string{}
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+// Unix time range in nanoseconds
+var (
+	minUnixNano = time.Unix(0, math.MinInt64)
+	maxUnixNano = time.Unix(0, math.MaxInt64)
+)
+
+// sqliteTime return SQLiteStore expires column value of time t: Unix time in nanoseconds, so responses expire at the
+// same precision as FileStore responses. Times out of the nanoseconds range (e.g. zero time) are clamped to the range
+func sqliteTime(t time.Time) int64 {
+	switch {
+	case t.Before(minUnixNano):
+		return math.MinInt64
+	case t.After(maxUnixNano):
+		return math.MaxInt64
+	}
+	return t.UnixNano()
+}
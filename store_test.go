@@ -0,0 +1,403 @@
+package adstxt
+
+import (
+	"cmp"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestFileStore test saving, loading and listing expired Ads.txt responses in local directory
+func TestFileStore(t *testing.T) {
+	s, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records, _ := ParseBody([]byte("greenadexchange.com,XF7342,DIRECT\nsubdomain=dev.example.com"))
+	now := time.Now()
+
+	fresh := &Response{Request: &Request{Domain: "example.com", URL: "https://example.com/ads.txt"}, Records: records, Expires: now.Add(time.Hour)}
+	expired := &Response{Request: &Request{Domain: "test.com", URL: "http://test.com/ads.txt"}, Records: &Records{}, Expires: now.Add(-time.Hour)}
+
+	if err := s.SaveResponse("http://example.com/ads.txt", fresh); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveResponse("http://test.com/ads.txt", expired); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := s.LoadResponse("http://example.com/ads.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.URL != fresh.URL || res.Hash() != fresh.Hash() || !res.Expires.Equal(fresh.Expires) {
+		t.Errorf("Expected loaded response to be equal to saved response [%v]", res)
+	}
+	if res.Line(res.Variables[0]) != 2 {
+		t.Errorf("Expected loaded response to keep records line index")
+	}
+
+	if _, err := s.LoadResponse("http://other.com/ads.txt"); err != ErrResponseNotFound {
+		t.Errorf("Expected [%s] for missing response and not [%v]", ErrResponseNotFound, err)
+	}
+
+	keys, err := s.ListExpired(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || keys[0] != "http://test.com/ads.txt" {
+		t.Errorf("Expected single expired response key and not [%v]", keys)
+	}
+
+	// saving response with the same key replace the previous response
+	expired.Expires = now.Add(time.Hour)
+	if err := s.SaveResponse("http://test.com/ads.txt", expired); err != nil {
+		t.Fatal(err)
+	}
+	if keys, _ := s.ListExpired(now); len(keys) != 0 {
+		t.Errorf("Expected no expired responses and not [%v]", keys)
+	}
+}
+
+// TestSQLiteStore test saving, loading and listing expired Ads.txt responses in SQLite database table
+func TestSQLiteStore(t *testing.T) {
+	db := openTestDB(t)
+	s, err := NewSQLiteStore(db, `ads"txt`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// table and index are created only if they do not exist
+	if _, err := NewSQLiteStore(db, `ads"txt`); err != nil {
+		t.Fatal(err)
+	}
+	table := testDBs[t.Name()].tables[`"ads""txt"`]
+	if table == nil || !slices.Equal(table.columns, []string{"key", "expires", "response"}) || table.key != 0 ||
+		!slices.Equal(table.indexes, []string{`"ads""txt_expires"`}) {
+		t.Fatalf("Expected responses table with expires index and not [%v]", table)
+	}
+
+	records, _ := ParseBody([]byte("greenadexchange.com,XF7342,DIRECT\nsubdomain=dev.example.com"))
+	now := time.Now()
+
+	fresh := &Response{Request: &Request{Domain: "example.com", URL: "https://example.com/ads.txt"}, Records: records, Expires: now.Add(time.Hour)}
+	expired := &Response{Request: &Request{Domain: "test.com", URL: "http://test.com/ads.txt"}, Records: &Records{}, Expires: now.Add(-time.Hour)}
+
+	if err := s.SaveResponse("http://example.com/ads.txt", fresh); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveResponse("http://test.com/ads.txt", expired); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := s.LoadResponse("http://example.com/ads.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.URL != fresh.URL || res.Hash() != fresh.Hash() || !res.Expires.Equal(fresh.Expires) {
+		t.Errorf("Expected loaded response to be equal to saved response [%v]", res)
+	}
+	if res.Line(res.Variables[0]) != 2 {
+		t.Errorf("Expected loaded response to keep records line index")
+	}
+
+	if _, err := s.LoadResponse("http://other.com/ads.txt"); err != ErrResponseNotFound {
+		t.Errorf("Expected [%s] for missing response and not [%v]", ErrResponseNotFound, err)
+	}
+
+	keys, err := s.ListExpired(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || keys[0] != "http://test.com/ads.txt" {
+		t.Errorf("Expected single expired response key and not [%v]", keys)
+	}
+
+	// saving response with the same key replace the previous response
+	expired.Expires = now.Add(time.Hour)
+	if err := s.SaveResponse("http://test.com/ads.txt", expired); err != nil {
+		t.Fatal(err)
+	}
+	if keys, _ := s.ListExpired(now); len(keys) != 0 {
+		t.Errorf("Expected no expired responses and not [%v]", keys)
+	}
+	if len(table.rows) != 2 {
+		t.Errorf("Expected [2] responses rows and not [%d]", len(table.rows))
+	}
+}
+
+// TestStoreExpiration test responses stored in FileStore and in SQLiteStore expire at the same precision
+func TestStoreExpiration(t *testing.T) {
+	files, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := NewSQLiteStore(openTestDB(t), "responses")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Unix(1700000000, int64(500*time.Millisecond))
+	responses := map[string]time.Time{
+		"http://expired.com/ads.txt":  now.Add(-time.Millisecond),
+		"http://fresh.com/ads.txt":    now.Add(time.Millisecond),
+		"http://unknown.com/ads.txt":  {},
+		"http://distant.com/ads.txt":  now.AddDate(1000, 0, 0),
+		"http://expiring.com/ads.txt": now,
+	}
+	for _, s := range []Store{files, db} {
+		for key, expires := range responses {
+			if err := s.SaveResponse(key, &Response{Records: &Records{}, Expires: expires}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		keys, err := s.ListExpired(now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		slices.Sort(keys)
+		if !slices.Equal(keys, []string{"http://expired.com/ads.txt", "http://unknown.com/ads.txt"}) {
+			t.Errorf("Expected responses that expire before now of [%T] and not %v", s, keys)
+		}
+	}
+}
+
+// testDBs databases of the test database/sql driver, by data source name (test name, see openTestDB)
+var testDBs = map[string]*testDB{}
+
+// testDBsMu guards testDBs
+var testDBsMu sync.Mutex
+
+func init() {
+	sql.Register("adstxt-test", testDriver{})
+}
+
+// openTestDB open empty database of the test database/sql driver, that runs the statements of SQLiteStore and
+// SQLiteHandler on in memory tables
+func openTestDB(t *testing.T) *sql.DB {
+	testDBsMu.Lock()
+	testDBs[t.Name()] = &testDB{tables: map[string]*testTable{}}
+	testDBsMu.Unlock()
+
+	db, err := sql.Open("adstxt-test", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// test database/sql driver statements
+var (
+	testCreateTable = regexp.MustCompile(`(?s)^CREATE TABLE IF NOT EXISTS ("(?:[^"]|"")+") \((.*)\)$`)
+	testCreateIndex = regexp.MustCompile(`^CREATE INDEX IF NOT EXISTS ("(?:[^"]|"")+") ON ("(?:[^"]|"")+") \((\w+)\)$`)
+	testInsert      = regexp.MustCompile(`^INSERT (OR REPLACE )?INTO ("(?:[^"]|"")+") \(([\w, ]+)\) VALUES \([?, ]+\)$`)
+	testSelect      = regexp.MustCompile(`^SELECT ([\w, ]+) FROM ("(?:[^"]|"")+")(?: WHERE (\w+) ([=<]) \?)?(?: ORDER BY (\w+))?$`)
+)
+
+// testDriver database/sql driver of in memory tables
+type testDriver struct{}
+
+// Open is the driver.Driver interface implementation for testDriver
+func (testDriver) Open(name string) (driver.Conn, error) {
+	testDBsMu.Lock()
+	defer testDBsMu.Unlock()
+
+	db, ok := testDBs[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown test database [%s]", name)
+	}
+	return &testConn{db: db}, nil
+}
+
+// testDB test driver database
+type testDB struct {
+	mu     sync.Mutex
+	tables map[string]*testTable
+}
+
+// testTable test driver table
+type testTable struct {
+	columns []string
+	key     int // index of the primary key column, -1 if the table has no primary key
+	indexes []string
+	rows    [][]driver.Value
+}
+
+// testConn test driver connection
+type testConn struct {
+	db *testDB
+}
+
+// Prepare is the driver.Conn interface implementation for testConn
+func (c *testConn) Prepare(query string) (driver.Stmt, error) {
+	return &testStmt{db: c.db, query: strings.TrimSpace(query)}, nil
+}
+
+// Close is the driver.Conn interface implementation for testConn
+func (c *testConn) Close() error { return nil }
+
+// Begin is the driver.Conn interface implementation for testConn
+func (c *testConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported by test driver")
+}
+
+// testStmt test driver statement
+type testStmt struct {
+	db    *testDB
+	query string
+}
+
+// Close is the driver.Stmt interface implementation for testStmt
+func (s *testStmt) Close() error { return nil }
+
+// NumInput is the driver.Stmt interface implementation for testStmt
+func (s *testStmt) NumInput() int { return strings.Count(s.query, "?") }
+
+// Exec is the driver.Stmt interface implementation for testStmt
+func (s *testStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if m := testCreateTable.FindStringSubmatch(s.query); m != nil {
+		if _, ok := s.db.tables[m[1]]; ok {
+			return driver.RowsAffected(0), nil
+		}
+		table := &testTable{key: -1}
+		for i, def := range strings.Split(m[2], ",") {
+			table.columns = append(table.columns, strings.Fields(def)[0])
+			if strings.Contains(def, "PRIMARY KEY") {
+				table.key = i
+			}
+		}
+		s.db.tables[m[1]] = table
+		return driver.RowsAffected(0), nil
+	}
+	if m := testCreateIndex.FindStringSubmatch(s.query); m != nil {
+		table, err := s.table(m[2])
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(table.indexes, m[1]) {
+			table.indexes = append(table.indexes, m[1])
+		}
+		return driver.RowsAffected(0), nil
+	}
+	if m := testInsert.FindStringSubmatch(s.query); m != nil {
+		table, err := s.table(m[2])
+		if err != nil {
+			return nil, err
+		}
+		row := make([]driver.Value, len(table.columns))
+		for i, col := range strings.Split(m[3], ",") {
+			c := slices.Index(table.columns, strings.TrimSpace(col))
+			if c < 0 {
+				return nil, fmt.Errorf("unknown column [%s]", col)
+			}
+			row[c] = args[i]
+		}
+		if table.key >= 0 {
+			for i, r := range table.rows {
+				if r[table.key] != row[table.key] {
+					continue
+				}
+				if len(m[1]) == 0 {
+					return nil, errors.New("UNIQUE constraint failed")
+				}
+				table.rows[i] = row
+				return driver.RowsAffected(1), nil
+			}
+		}
+		table.rows = append(table.rows, row)
+		return driver.RowsAffected(1), nil
+	}
+	return nil, fmt.Errorf("statement is not supported by test driver [%s]", s.query)
+}
+
+// Query is the driver.Stmt interface implementation for testStmt
+func (s *testStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	m := testSelect.FindStringSubmatch(s.query)
+	if m == nil {
+		return nil, fmt.Errorf("query is not supported by test driver [%s]", s.query)
+	}
+	table, err := s.table(m[2])
+	if err != nil {
+		return nil, err
+	}
+	column := func(name string) int {
+		return slices.Index(table.columns, name)
+	}
+
+	rows := [][]driver.Value{}
+	for _, r := range table.rows {
+		if len(m[3]) > 0 {
+			v := r[column(m[3])]
+			if m[4] == "=" && v != args[0] || m[4] == "<" && v.(int64) >= args[0].(int64) {
+				continue
+			}
+		}
+		rows = append(rows, r)
+	}
+	if len(m[5]) > 0 {
+		c := column(m[5])
+		slices.SortStableFunc(rows, func(a, b []driver.Value) int {
+			return cmp.Compare(a[c].(int64), b[c].(int64))
+		})
+	}
+
+	res := &testRows{}
+	for _, col := range strings.Split(m[1], ",") {
+		res.columns = append(res.columns, strings.TrimSpace(col))
+	}
+	for _, r := range rows {
+		row := make([]driver.Value, len(res.columns))
+		for i, col := range res.columns {
+			row[i] = r[column(col)]
+		}
+		res.rows = append(res.rows, row)
+	}
+	return res, nil
+}
+
+// table return statement table by its quoted name
+func (s *testStmt) table(name string) (*testTable, error) {
+	table, ok := s.db.tables[name]
+	if !ok {
+		return nil, fmt.Errorf("no such table [%s]", name)
+	}
+	return table, nil
+}
+
+// testRows test driver query result rows
+type testRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+// Columns is the driver.Rows interface implementation for testRows
+func (r *testRows) Columns() []string { return r.columns }
+
+// Close is the driver.Rows interface implementation for testRows
+func (r *testRows) Close() error { return nil }
+
+// Next is the driver.Rows interface implementation for testRows
+func (r *testRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
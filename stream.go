@@ -0,0 +1,203 @@
+package adstxt
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+)
+
+// Ads.txt lines scanner settings
+const (
+	// initial size of the line buffer, grown as needed up to the maximum line size
+	lineBufferSize = 64 << 10
+	// maximum length of single Ads.txt line (1MB)
+	maxLineSize = 1 << 20
+)
+
+// parse line error\warning
+const (
+	errBinaryLine  = "line contains binary data (null bytes or control characters)"
+	errLineTooLong = "Ads.txt line [%d] is longer than [%d] bytes"
+)
+
+// Line single parsed Ads.txt file line. Each line holds at most one Data\Variable record, and parse warning if
+// the line is not valid. Comments and empty lines holds neither record nor warning
+type Line struct {
+	Index      int         `json:"index"`                // Index of the line in the Ads.txt file
+	Text       string      `json:"txt"`                  // Text original text of the line
+	DataRecord *DataRecord `json:"dataRecord,omitempty"` // DataRecord parsed from the line
+	Variable   *Variable   `json:"variable,omitempty"`   // Variable parsed from the line
+	Warning    *Warning    `json:"warning,omitempty"`    // Warning found when parsing the line
+
+	Placeholder bool `json:"placeholder,omitempty"` // Placeholder line is the placeholder record of Ads.txt file without authorized sellers
+}
+
+// ParseReader parse Ads.txt file read from r based on Ads.txt Specification Version 1.0.1
+func ParseReader(r io.Reader) (*Records, error) {
+	return parseReader(r, 0, FieldPolicy{})
+}
+
+// parseReader parse Ads.txt file read from r with the field count policy. Records are preallocated for the expected
+// number of lines, when known
+func parseReader(r io.Reader, lines int, policy FieldPolicy) (*Records, error) {
+	records := &Records{
+		DataRecords: make([]*DataRecord, 0, lines),
+		Variables:   []*Variable{},
+		Warnings:    []*Warning{},
+		Body:        make([]string, 0, lines),
+	}
+
+	err := scanLines(r, func(index int, txt string) error {
+		records.Body = append(records.Body, txt)
+		records.parseRecord(index, txt, policy)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// comments that are not followed by any record
+	records.TrailingComments = records.comments
+	records.comments = nil
+
+	return records, nil
+}
+
+// ParseStream parse Ads.txt file read from r line by line, and call fn for each parsed line without holding the
+// whole file content or its records in memory. Parsing stops on the first error returned by fn
+func ParseStream(r io.Reader, fn func(line Line) error) error {
+	// keep variables state to validate Ads.txt variables multiplicity (e.g. single OWNERDOMAIN)
+	state := &Records{}
+
+	return scanLines(r, func(index int, txt string) error {
+		l := parseLine(index, txt)
+		if l.Variable != nil {
+			state.addLine(&l)
+		}
+		return fn(l)
+	})
+}
+
+// scanLines read lines from r and call fn for each line with the line index (starting from 1)
+func scanLines(r io.Reader, fn func(index int, txt string) error) error {
+	buf, release := getLineBuffer()
+	defer release()
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(buf, maxLineSize)
+	scanner.Split(scanLinesSplit)
+
+	index := 0
+	for scanner.Scan() {
+		index++
+		if err := fn(index, scanner.Text()); err != nil {
+			return err
+		}
+	}
+
+	if err := scanner.Err(); errors.Is(err, bufio.ErrTooLong) {
+		return &ErrLineTooLong{Index: index + 1, Limit: maxLineSize}
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// scanLinesSplit custom split function to support different end-of-line marker (CR, CRLF etc)
+func scanLinesSplit(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		if data[i] == '\n' {
+			// We have a line terminated by single newline.
+			return i + 1, data[0:i], nil
+		}
+		// CR is the last byte read so far: request more data to check if it is followed by LF
+		if len(data) == i+1 && !atEOF {
+			return 0, nil, nil
+		}
+		advance = i + 1
+		if len(data) > i+1 && data[i+1] == '\n' {
+			advance++
+		}
+		return advance, data[0:i], nil
+	}
+	// If we're at EOF, we have a final, non-terminated line. Return it.
+	if atEOF {
+		return len(data), data, nil
+	}
+	// Request more data.
+	return 0, nil, nil
+}
+
+// parseLine parse a single Ads.txt line into Data\Variable record, with the default field count policy
+func parseLine(index int, txt string) Line {
+	return FieldPolicy{}.parseLine(index, txt)
+}
+
+// parseLine parse a single Ads.txt line into Data\Variable record, with the field count policy
+func (p FieldPolicy) parseLine(index int, txt string) Line {
+	l := Line{Index: index, Text: txt}
+	line := removeComment(txt)
+	// byte order mark at the start of the file is not a copy-paste artifact, and is removed without warning
+	if index == 1 {
+		line = strings.TrimPrefix(line, "\ufeff")
+	}
+	line, normalizeWarning := normalizeUnicode(line)
+	line = strings.TrimSpace(line)
+
+	// ignore comments and empty line
+	if len(line) == 0 || string(line) == commentDenote {
+		return l
+	}
+
+	// placeholder record declares that the publisher has no authorized sellers, and it is not a real data record
+	if isPlaceholderRecord(line) {
+		l.Placeholder = true
+		return l
+	}
+
+	// parse line into Data\Variable record
+	if isBinaryLine(line) {
+		l.Warning = &Warning{Level: HighSeverity, Message: errBinaryLine}
+	} else if p.isDataRecord(line) {
+		l.DataRecord, l.Warning = p.parseDataRecord(line)
+		if l.DataRecord != nil {
+			l.DataRecord.Comment = lineComment(txt)
+			l.DataRecord.Line, l.DataRecord.Text = index, txt
+		}
+	} else if strings.Index(line, "=") != -1 && strings.Count(line, "=") == 1 {
+		l.Variable, l.Warning = parseVariable(line)
+		if l.Variable != nil {
+			l.Variable.Comment = lineComment(txt)
+			l.Variable.Line, l.Variable.Text = index, txt
+		}
+	} else {
+		l.Warning = &Warning{Level: HighSeverity, Message: "could not parse this line"}
+	}
+
+	// warning of normalized characters is reported only if the line has no other parse warning
+	if l.Warning == nil && (l.DataRecord != nil || l.Variable != nil) {
+		l.Warning = normalizeWarning
+	}
+	if l.Warning != nil {
+		l.Warning.Index = index
+		l.Warning.Text = txt
+	}
+
+	return l
+}
+
+// isBinaryLine return true if line holds null bytes or control characters other than tab, which are never part of
+// valid Ads.txt record (e.g. binary file served as Ads.txt file)
+func isBinaryLine(line string) bool {
+	for i := 0; i < len(line); i++ {
+		if c := line[i]; (c < 0x20 && c != '\t') || c == 0x7f {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,167 @@
+package adstxt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// TestParseReader test parsing Ads.txt file from reader
+func TestParseReader(t *testing.T) {
+	body := "greenadexchange.com,XF7342,DIRECT\r\ngreenadexchange.com,185,RESELLER\rsubdomain=test.com\ninvalid line"
+
+	// read one byte at a time to make sure lines split across reads are handled
+	res, err := ParseReader(iotest.OneByteReader(strings.NewReader(body)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res.Body) != 4 {
+		t.Errorf("Expected number of lines to be [4] and not [%d]", len(res.Body))
+	}
+	if len(res.DataRecords) != 2 {
+		t.Errorf("Expected [2] DataRecords but found [%d]", len(res.DataRecords))
+	}
+	if len(res.Variables) != 1 {
+		t.Errorf("Expected single Variable record but found [%d]", len(res.Variables))
+	}
+	if len(res.Warnings) != 1 || res.Warnings[0].Index != 4 {
+		t.Errorf("Expected single warning for line #4 but found [%d]", len(res.Warnings))
+	}
+}
+
+// TestParseStream test parsing Ads.txt file line by line
+func TestParseStream(t *testing.T) {
+	body := "# comment\ngreenadexchange.com,XF7342,DIRECT\nOWNERDOMAIN=example.com\nOWNERDOMAIN=other.com\ninvalid line"
+
+	lines := []Line{}
+	err := ParseStream(strings.NewReader(body), func(l Line) error {
+		lines = append(lines, l)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(lines) != 5 {
+		t.Fatalf("Expected [5] lines and not [%d]", len(lines))
+	}
+	if lines[0].DataRecord != nil || lines[0].Variable != nil || lines[0].Warning != nil {
+		t.Errorf("Expected comment line to hold no record")
+	}
+	if lines[1].DataRecord == nil || lines[1].Index != 2 {
+		t.Errorf("Expected line #2 to hold DataRecord")
+	}
+	if lines[2].Variable == nil {
+		t.Errorf("Expected line #3 to hold Variable")
+	}
+	if lines[3].Variable != nil || lines[3].Warning == nil {
+		t.Errorf("Expected warning for duplicate OWNERDOMAIN in line #4")
+	}
+	if lines[4].Warning == nil || lines[4].Warning.Index != 5 {
+		t.Errorf("Expected warning for invalid line #5")
+	}
+
+	// stop parsing on callback error
+	stop := errors.New("stop")
+	count := 0
+	err = ParseStream(strings.NewReader(body), func(l Line) error {
+		count++
+		return stop
+	})
+	if err != stop || count != 1 {
+		t.Errorf("Expected parsing to stop on first callback error")
+	}
+}
+
+// TestParseRecordsSourcePosition test parsed Data\Variable records hold their line index and original line text
+func TestParseRecordsSourcePosition(t *testing.T) {
+	body := "# comment\ngreenadexchange.com, XF7342, DIRECT # inline\n\ncontact=ads@example.com"
+
+	res, err := ParseReader(strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dr := res.DataRecords[0]
+	if dr.Line != 2 || dr.Text != "greenadexchange.com, XF7342, DIRECT # inline" {
+		t.Errorf("Expected DataRecord source position to be line [2] and not [%d] [%s]", dr.Line, dr.Text)
+	}
+	v := res.Variables[0]
+	if v.Line != 4 || v.Text != "contact=ads@example.com" {
+		t.Errorf("Expected Variable source position to be line [4] and not [%d] [%s]", v.Line, v.Text)
+	}
+
+	// line index of record copy is the record source position
+	c := *dr
+	if res.Line(&c) != 2 {
+		t.Errorf("Expected line index of DataRecord copy to be [2] and not [%d]", res.Line(&c))
+	}
+}
+
+// TestParseLongLine test parsing Ads.txt file with lines longer than the default scanner token size
+func TestParseLongLine(t *testing.T) {
+	// line of 100KB is parsed
+	long := "greenadexchange.com,XF7342,DIRECT # " + strings.Repeat("x", 100<<10)
+	res, err := ParseBody([]byte("google.com,pub-1234,RESELLER\n" + long))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.DataRecords) != 2 {
+		t.Errorf("Expected [2] DataRecords but found [%d]", len(res.DataRecords))
+	}
+
+	// line longer than the maximum line size is rejected
+	_, err = ParseBody([]byte("google.com,pub-1234,RESELLER\n" + strings.Repeat("x", maxLineSize+1)))
+	var lineErr *ErrLineTooLong
+	if !errors.As(err, &lineErr) || lineErr.Index != 2 || lineErr.Limit != maxLineSize {
+		t.Errorf("Expected ErrLineTooLong for line #2 and not [%v]", err)
+	}
+}
+
+// TestParseBinaryLine test lines with null bytes or control characters are reported as high severity warnings
+func TestParseBinaryLine(t *testing.T) {
+	res, err := ParseBody([]byte("greenadexchange.com,XF\x007342,DIRECT\n\x01\x02\x03\ngoogle.com,\tpub-1234,RESELLER\n# \x00 comment"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.DataRecords) != 1 || res.DataRecords[0].PublisherAccountID != "pub-1234" {
+		t.Errorf("Expected single DataRecord but found [%d]", len(res.DataRecords))
+	}
+	if len(res.Warnings) != 2 || res.Warnings[0].Message != errBinaryLine || res.Warnings[1].Index != 2 {
+		t.Errorf("Expected binary data warnings for line #1 and #2 and not [%v]", res.Warnings)
+	}
+}
+
+// FuzzParseStream test line by line parsing of arbitrary input never panics, and matches parsing the whole body
+func FuzzParseStream(f *testing.F) {
+	f.Add("greenadexchange.com,XF7342,DIRECT\nOWNERDOMAIN=example.com\n# comment")
+	f.Add("\r\n\r\r\n,,,;;;===\x00\xff\xfe")
+	f.Add("placeholder.example.com, placeholder, DIRECT, placeholder")
+
+	f.Fuzz(func(t *testing.T, body string) {
+		lines := 0
+		err := ParseStream(strings.NewReader(body), func(l Line) error {
+			lines++
+			if l.Index != lines {
+				t.Fatalf("Expected line index [%d] and not [%d]", lines, l.Index)
+			}
+			if l.DataRecord != nil && l.DataRecord.Line != l.Index {
+				t.Fatalf("Expected DataRecord line index [%d] and not [%d]", l.Index, l.DataRecord.Line)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res, err := ParseReader(strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res.Body) != lines {
+			t.Fatalf("Expected [%d] lines and not [%d]", lines, len(res.Body))
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package adstxt
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// subdomain referral errors
+const (
+	errSubdomainOutOfScope = "[%s] subdomain [%s] is not within the root domain scope"
+)
+
+// SiteResponse Ads.txt response of root domain, and responses of the subdomains declared by the root domain Ads.txt
+// file (subdomain variables)
+type SiteResponse struct {
+	Root       *Response                     `json:"root"`       // Root domain Ads.txt response
+	Subdomains map[string]*SubdomainResponse `json:"subdomains"` // Subdomains Ads.txt responses by subdomain
+}
+
+// SubdomainResponse Ads.txt response of single subdomain declared by root domain Ads.txt file
+type SubdomainResponse struct {
+	Request  *Request  `json:"request"`            // Request subdomain Ads.txt request
+	Response *Response `json:"response,omitempty"` // Response subdomain Ads.txt response, nil if request failed
+	Err      error     `json:"-"`                  // Err subdomain Ads.txt request error
+}
+
+// GetWithSubdomains crawl and parse Ads.txt file of root domain, and Ads.txt files of subdomains declared by it (see
+// Crawler.GetWithSubdomains)
+func GetWithSubdomains(ctx context.Context, req *Request) (*SiteResponse, error) {
+	return defaultCrawler().GetWithSubdomains(ctx, req)
+}
+
+// GetWithSubdomains crawl and parse Ads.txt file of root domain, and then crawl Ads.txt files of all subdomains
+// declared in it using subdomain variable. According to IAB Ads.txt specification, subdomain referral is followed
+// exactly one level deep: subdomain variables of subdomains Ads.txt files are ignored, and subdomains must be within
+// the root domain scope
+func (c *Crawler) GetWithSubdomains(ctx context.Context, req *Request) (*SiteResponse, error) {
+	// request URL may change due to redirects: keep the original Ads.txt URL scheme for subdomain requests
+	scheme := "http"
+	if u, err := url.Parse(req.URL); err == nil && len(u.Scheme) > 0 {
+		scheme = u.Scheme
+	}
+
+	root, err := c.GetWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	site := &SiteResponse{Root: root, Subdomains: map[string]*SubdomainResponse{}}
+
+	requests := []*Request{}
+	for _, v := range root.Variables {
+		if v.Type != varTypeSubdomain {
+			continue
+		}
+
+		subdomain := strings.ToLower(v.Value)
+		if _, ok := site.Subdomains[subdomain]; ok {
+			continue
+		}
+
+		r, err := NewRequest(scheme + "://" + subdomain)
+		if err == nil && r.Domain != req.Domain {
+			err = fmt.Errorf(errSubdomainOutOfScope, req.Domain, subdomain)
+		}
+		if err != nil {
+			site.Subdomains[subdomain] = &SubdomainResponse{Request: r, Err: err}
+			continue
+		}
+		// keep the same file type as root request (Ads.txt or app-ads.txt)
+		if req.Type != r.Type {
+			r.Type = req.Type
+			r.URL = strings.TrimSuffix(r.URL, AdsTxt.path()) + req.Type.path()
+		}
+		if len(req.Path) > 0 {
+			r.SetPath(req.Path)
+		}
+		// subdomain requests carry the root request metadata
+		r.Meta = req.Meta
+
+		site.Subdomains[subdomain] = &SubdomainResponse{Request: r}
+		requests = append(requests, r)
+	}
+
+	// crawl subdomains in parallel
+	var mu sync.Mutex
+	c.getMultiple(ctx, requests, HandlerFunc(func(r *Request, res *Response, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		for _, s := range site.Subdomains {
+			if s.Request == r {
+				s.Response = res
+				s.Err = err
+			}
+		}
+	}), c.GetWithContext)
+
+	return site, nil
+}
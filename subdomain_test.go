@@ -0,0 +1,64 @@
+package adstxt
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// TestGetWithSubdomains test crawling Ads.txt files of subdomains declared in root domain Ads.txt file
+func TestGetWithSubdomains(t *testing.T) {
+	files := map[string]string{
+		"example.com":     "greenadexchange.com,XF7342,DIRECT\nsubdomain=dev.example.com\nsubdomain=shop.example.com\nsubdomain=other.com",
+		"dev.example.com": "greenadexchange.com,185,RESELLER\nsubdomain=deep.example.com",
+	}
+
+	requested := map[string]bool{}
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			requested[req.URL.Host] = true
+
+			body, ok := files[req.URL.Host]
+			if !ok {
+				return &http.Response{StatusCode: http.StatusNotFound, Status: "404 Not Found", Body: http.NoBody, Request: req}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       io.NopCloser(strings.NewReader(body)),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	req, _ := NewRequest("http://example.com")
+	site, err := NewCrawler(WithHTTPClient(client), WithConcurrency(1)).GetWithSubdomains(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(site.Root.DataRecords) != 1 {
+		t.Errorf("Expected single root DataRecord but found [%d]", len(site.Root.DataRecords))
+	}
+	if len(site.Subdomains) != 3 {
+		t.Fatalf("Expected [3] subdomains and not [%d]", len(site.Subdomains))
+	}
+
+	dev := site.Subdomains["dev.example.com"]
+	if dev.Err != nil || dev.Response == nil || len(dev.Response.DataRecords) != 1 {
+		t.Errorf("Expected subdomain [dev.example.com] Ads.txt to be parsed [%v]", dev.Err)
+	}
+	if site.Subdomains["shop.example.com"].Err == nil {
+		t.Errorf("Expected missing subdomain Ads.txt to fail")
+	}
+	if site.Subdomains["other.com"].Err == nil || requested["other.com"] {
+		t.Errorf("Expected subdomain out of root domain scope not to be crawled")
+	}
+
+	// subdomain referral is exactly one level deep
+	if requested["deep.example.com"] {
+		t.Errorf("Expected subdomain of subdomain not to be crawled")
+	}
+}
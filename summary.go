@@ -0,0 +1,70 @@
+package adstxt
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// BatchSummary aggregate summary of multiple Ads.txt requests: number of requests by outcome, total number of
+// parsed records and warnings and the overall crawl duration. BatchSummary implements the Handler interface, and
+// it is safe to use from multiple goroutines
+type BatchSummary struct {
+	Total          int           `json:"total"`          // Total number of handled Ads.txt requests
+	Succeeded      int           `json:"succeeded"`      // Succeeded Ads.txt requests (HTTP 200)
+	NotFound       int           `json:"notFound"`       // NotFound Ads.txt requests that ended with HTTP 404 response
+	SoftNotFound   int           `json:"softNotFound"`   // SoftNotFound Ads.txt requests that ended with HTML page instead of Ads.txt file
+	RedirectErrors int           `json:"redirectErrors"` // RedirectErrors Ads.txt requests that failed to follow HTTP redirect response
+	OtherErrors    int           `json:"otherErrors"`    // OtherErrors Ads.txt requests that failed due to any other error
+	DataRecords    int           `json:"dataRecords"`    // DataRecords total number of parsed data records
+	Variables      int           `json:"variables"`      // Variables total number of parsed variables
+	Warnings       int           `json:"warnings"`       // Warnings total number of parse warnings
+	Duration       time.Duration `json:"duration"`       // Duration overall wall-clock duration of the crawl
+	mu             sync.Mutex
+}
+
+// Handle is the Handler interface implementation for BatchSummary: add single Ads.txt request outcome to summary
+func (s *BatchSummary) Handle(req *Request, res *Response, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Total++
+
+	if err != nil {
+		var redirectErr *ErrRedirect
+		var htmlErr *ErrHTMLPage
+		switch {
+		case errors.Is(err, ErrNotFound):
+			s.NotFound++
+		case errors.As(err, &htmlErr):
+			s.SoftNotFound++
+		case errors.As(err, &redirectErr):
+			s.RedirectErrors++
+		default:
+			s.OtherErrors++
+		}
+		return
+	}
+
+	s.Succeeded++
+	if res != nil && res.Records != nil {
+		s.DataRecords += len(res.DataRecords)
+		s.Variables += len(res.Variables)
+		s.Warnings += len(res.Warnings)
+	}
+}
+
+// GetMultipleWithSummary crawl and parse multiple Ads.txt files from remote hosts (see GetMultiple), and return
+// aggregate summary of all requests once all of them were handled
+func GetMultipleWithSummary(req []*Request, h Handler) *BatchSummary {
+	s := &BatchSummary{}
+
+	start := time.Now()
+	GetMultiple(req, HandlerFunc(func(r *Request, res *Response, err error) {
+		s.Handle(r, res, err)
+		h.Handle(r, res, err)
+	}))
+	s.Duration = time.Since(start)
+
+	return s
+}
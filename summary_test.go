@@ -0,0 +1,29 @@
+package adstxt
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestBatchSummary test aggregating multiple Ads.txt requests outcome into batch summary
+func TestBatchSummary(t *testing.T) {
+	records, _ := ParseBody([]byte("greenadexchange.com,XF7342,DIRECT\ngreenadexchange.com,185,RESELLER\nsubdomain=test.com\ninvalid line"))
+
+	s := &BatchSummary{}
+	s.Handle(&Request{}, &Response{Records: records}, nil)
+	s.Handle(&Request{}, nil, &ErrClientError{StatusCode: 404})
+	s.Handle(&Request{}, nil, &ErrClientError{StatusCode: 403})
+	s.Handle(&Request{}, nil, &ErrRedirect{Reason: ErrInvalidRedirect})
+	s.Handle(&Request{}, nil, errors.New("connection refused"))
+	s.Handle(&Request{}, nil, &ErrHTMLPage{})
+
+	if s.Total != 6 {
+		t.Errorf("Expected summary total to be [6] and not [%d]", s.Total)
+	}
+	if s.Succeeded != 1 || s.NotFound != 1 || s.SoftNotFound != 1 || s.RedirectErrors != 1 || s.OtherErrors != 2 {
+		t.Errorf("Unexpected summary outcome counts [%d] [%d] [%d] [%d] [%d]", s.Succeeded, s.NotFound, s.SoftNotFound, s.RedirectErrors, s.OtherErrors)
+	}
+	if s.DataRecords != 2 || s.Variables != 1 || s.Warnings != 1 {
+		t.Errorf("Unexpected summary records counts [%d] [%d] [%d]", s.DataRecords, s.Variables, s.Warnings)
+	}
+}
@@ -0,0 +1,123 @@
+package adstxt
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// throttle errors
+const (
+	errThrottled = "[%s] remote host throttled Ads.txt request of domain [%s], retry after [%s]. Ads.txt URL [%s]"
+)
+
+// default throttle policy (see ThrottlePolicy)
+const (
+	throttleMaxReschedules = 3
+	throttleDefaultDelay   = 30 * time.Second
+	throttleMaxDelay       = 5 * time.Minute
+)
+
+// maxRetryAfter Retry-After delay of header value too long to be represented as time.Duration: request with such
+// delay is never rescheduled
+const maxRetryAfter = time.Duration(math.MaxInt64)
+
+// ErrThrottled Ads.txt request failed since remote host responded with HTTP 429 Too Many Requests. ErrThrottled
+// matches ErrRateLimited, and unwraps to *ErrClientError of the 429 response
+type ErrThrottled struct {
+	ErrClientError
+	RetryAfter  time.Duration // RetryAfter delay requested by the response Retry-After header, zero if missing or invalid
+	Rescheduled int           // Rescheduled number of times the request was rescheduled within the batch crawl before it failed
+
+	retryAfter bool // response has valid Retry-After header
+}
+
+func (e *ErrThrottled) Error() string {
+	return fmt.Sprintf(errThrottled, e.Status, e.Domain, e.RetryAfter, e.URL)
+}
+
+// Is match ErrRateLimited
+func (e *ErrThrottled) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// Unwrap return the client error of the 429 response
+func (e *ErrThrottled) Unwrap() error {
+	return &e.ErrClientError
+}
+
+// ThrottlePolicy control how batch crawls reschedule requests throttled by remote host with HTTP 429 response (see
+// WithThrottlePolicy). Throttled request is rescheduled within the batch after the delay requested by the response
+// Retry-After header (seconds or HTTP-date), or DefaultDelay if the header is missing, and the crawler workers go on
+// with other requests in the meantime. Request is failed with ErrThrottled once it was rescheduled MaxReschedules
+// times, or if the requested delay is longer than MaxDelay
+type ThrottlePolicy struct {
+	MaxReschedules int           // MaxReschedules maximum number of times single request is rescheduled, never rescheduled if zero
+	DefaultDelay   time.Duration // DefaultDelay delay before rescheduled request is sent when the response has no Retry-After header
+	MaxDelay       time.Duration // MaxDelay maximum delay of rescheduled request (no limit if zero)
+}
+
+// defaultThrottlePolicy crawler default ThrottlePolicy
+var defaultThrottlePolicy = ThrottlePolicy{
+	MaxReschedules: throttleMaxReschedules,
+	DefaultDelay:   throttleDefaultDelay,
+	MaxDelay:       throttleMaxDelay,
+}
+
+// throttledError return ErrThrottled of HTTP 429 response
+func throttledError(req *Request, res *http.Response) *ErrThrottled {
+	delay, ok := parseRetryAfter(res.Header.Get("Retry-After"), time.Now())
+	return &ErrThrottled{
+		ErrClientError: ErrClientError{StatusCode: res.StatusCode, Status: res.Status, Domain: req.Domain, URL: req.URL},
+		RetryAfter:     delay,
+		retryAfter:     ok,
+	}
+}
+
+// parseRetryAfter parse Retry-After header value (RFC 9110): delay in seconds, or HTTP-date after which the request
+// may be retried. HTTP-date in the past is zero delay, and delay that overflows time.Duration is maxRetryAfter
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	v = strings.TrimSpace(v)
+	if len(v) == 0 {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseInt(v, 10, 64); err == nil || errors.Is(err, strconv.ErrRange) {
+		if strings.HasPrefix(v, "-") {
+			return 0, false
+		}
+		if err != nil || seconds > math.MaxInt64/int64(time.Second) {
+			return maxRetryAfter, true
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	t, err := http.ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+	if d := t.Sub(now); d > 0 {
+		return d, true
+	}
+	return 0, true
+}
+
+// rescheduleDelay return the delay before throttled request is rescheduled within the batch crawl, or false if the
+// request failure is not throttling, or it should not be rescheduled by the crawler throttle policy
+func (c *Crawler) rescheduleDelay(rescheduled int, err error) (time.Duration, bool) {
+	var throttled *ErrThrottled
+	if !errors.As(err, &throttled) || rescheduled >= c.throttle.MaxReschedules {
+		return 0, false
+	}
+
+	delay := throttled.RetryAfter
+	if !throttled.retryAfter {
+		delay = c.throttle.DefaultDelay
+	}
+	if delay == maxRetryAfter || (c.throttle.MaxDelay > 0 && delay > c.throttle.MaxDelay) {
+		return 0, false
+	}
+	return delay, true
+}
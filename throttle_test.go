@@ -0,0 +1,162 @@
+package adstxt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestParseRetryAfter test parsing Retry-After header in seconds and HTTP-date forms
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		delay time.Duration
+		ok    bool
+	}{
+		{"120", 2 * time.Minute, true},
+		{" 0 ", 0, true},
+		{"Fri, 01 Mar 2024 12:01:30 GMT", 90 * time.Second, true},
+		{"Fri, 01 Mar 2024 11:00:00 GMT", 0, true},
+		{"", 0, false},
+		{"-1", 0, false},
+		{"9223372036", 9223372036 * time.Second, true},
+		{"9223372037", maxRetryAfter, true},
+		{"99999999999999999999", maxRetryAfter, true},
+		{"soon", 0, false},
+	}
+
+	for _, test := range tests {
+		delay, ok := parseRetryAfter(test.value, now)
+		if delay != test.delay || ok != test.ok {
+			t.Errorf("Expected Retry-After [%s] delay [%s] [%t] and not [%s] [%t]", test.value, test.delay, test.ok, delay, ok)
+		}
+	}
+}
+
+// throttlingClient HTTP client that throttles requests of each host the specified number of times with Retry-After
+// header, and serves Ads.txt file afterwards
+func throttlingClient(throttles map[string]int, retryAfter string) (*http.Client, func() []string) {
+	sent := []string{}
+	var mu sync.Mutex
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			host := req.URL.Hostname()
+			sent = append(sent, host)
+			if throttles[host] != 0 {
+				throttles[host]--
+				return &http.Response{StatusCode: http.StatusTooManyRequests, Status: "429 Too Many Requests",
+					Header: http.Header{"Retry-After": []string{retryAfter}}, Body: http.NoBody, Request: req}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       io.NopCloser(strings.NewReader("greenadexchange.com,XF7342,DIRECT")),
+				Request:    req,
+			}, nil
+		}),
+	}
+	return client, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string{}, sent...)
+	}
+}
+
+// TestCrawlerThrottled test HTTP 429 response fails single request with ErrThrottled
+func TestCrawlerThrottled(t *testing.T) {
+	client, _ := throttlingClient(map[string]int{"example.com": 1}, "120")
+	_, err := NewCrawler(WithHTTPClient(client)).Get(&Request{URL: "https://example.com/ads.txt", Domain: "example.com"})
+
+	var throttled *ErrThrottled
+	var clientErr *ErrClientError
+	if !errors.As(err, &throttled) || throttled.RetryAfter != 2*time.Minute || !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("Expected [%s] with Retry-After delay and not [%v]", ErrRateLimited, err)
+	}
+	if !errors.As(err, &clientErr) || clientErr.StatusCode != http.StatusTooManyRequests || errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected throttled error to unwrap to HTTP 429 client error [%v]", err)
+	}
+	if class := StatusClass(nil, err); class != StatusClassThrottled {
+		t.Errorf("Expected status class [%s] and not [%s]", StatusClassThrottled, class)
+	}
+}
+
+// TestThrottleReschedule test batch crawl reschedule throttled requests, and report throttling stats
+func TestThrottleReschedule(t *testing.T) {
+	for _, ordered := range []bool{false, true} {
+		client, sent := throttlingClient(map[string]int{"a.com": 1, "b.com": -1, "c.com": 0}, "0")
+		c := NewCrawler(WithHTTPClient(client), WithConcurrency(1), WithOrderedResults(ordered),
+			WithThrottlePolicy(ThrottlePolicy{MaxReschedules: 2}))
+
+		req := []*Request{}
+		for _, d := range []string{"a.com", "b.com", "c.com"} {
+			req = append(req, &Request{URL: fmt.Sprintf("https://%s/ads.txt", d), Domain: d})
+		}
+
+		handled := []string{}
+		results := map[string]error{}
+		var mu sync.Mutex
+		report := c.Crawl(context.Background(), req, HandlerFunc(func(req *Request, res *Response, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			handled = append(handled, req.Domain)
+			results[req.Domain] = err
+			if req.Domain == "a.com" && (err != nil || res.Rescheduled != 1) {
+				t.Errorf("Expected rescheduled request to succeed [%v]", err)
+			}
+		}))
+
+		var throttled *ErrThrottled
+		if !errors.As(results["b.com"], &throttled) || throttled.Rescheduled != 2 {
+			t.Errorf("Expected request throttled at each attempt to fail after [2] reschedules [%v]", results["b.com"])
+		}
+		if results["c.com"] != nil || len(sent()) != 6 {
+			t.Errorf("Expected [6] requests to be sent and not %v", sent())
+		}
+		if ordered && strings.Join(handled, " ") != "a.com b.com c.com" {
+			t.Errorf("Expected results in order of requests and not %v", handled)
+		}
+
+		expected := ThrottlingReport{Throttled: 2, Rescheduled: 3, Failed: 1, Hosts: map[string]int{"a.com": 1, "b.com": 1}}
+		if r := report.Throttling; r.Throttled != expected.Throttled || r.Rescheduled != expected.Rescheduled ||
+			r.Failed != expected.Failed || r.Hosts["a.com"] != 1 || r.Hosts["b.com"] != 1 {
+			t.Errorf("Expected throttling report %+v and not %+v", expected, r)
+		}
+		if report.Succeeded != 2 || report.Errors[StatusClassThrottled] != 1 {
+			t.Errorf("Expected [2] succeeded requests and single throttled request %v", report.Errors)
+		}
+	}
+
+	// delay longer than the maximum delay is not rescheduled
+	client, sent := throttlingClient(map[string]int{"a.com": 1}, "3600")
+	c := NewCrawler(WithHTTPClient(client), WithThrottlePolicy(ThrottlePolicy{MaxReschedules: 2, MaxDelay: time.Minute}))
+	c.GetMultiple([]*Request{{URL: "https://a.com/ads.txt", Domain: "a.com"}}, HandlerFunc(func(req *Request, res *Response, err error) {
+		if !errors.Is(err, ErrRateLimited) {
+			t.Errorf("Expected [%s] of long Retry-After delay and not [%v]", ErrRateLimited, err)
+		}
+	}))
+	if len(sent()) != 1 {
+		t.Errorf("Expected single request of long Retry-After delay and not %v", sent())
+	}
+
+	// delay that overflows time.Duration is not rescheduled, even without maximum delay
+	client, sent = throttlingClient(map[string]int{"a.com": 1}, "9999999999999")
+	c = NewCrawler(WithHTTPClient(client), WithThrottlePolicy(ThrottlePolicy{MaxReschedules: 2}))
+	c.GetMultiple([]*Request{{URL: "https://a.com/ads.txt", Domain: "a.com"}}, HandlerFunc(func(req *Request, res *Response, err error) {
+		if !errors.Is(err, ErrRateLimited) {
+			t.Errorf("Expected [%s] of overflowing Retry-After delay and not [%v]", ErrRateLimited, err)
+		}
+	}))
+	if len(sent()) != 1 {
+		t.Errorf("Expected single request of overflowing Retry-After delay and not %v", sent())
+	}
+}
@@ -0,0 +1,72 @@
+package adstxt
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"time"
+)
+
+// Ads.txt request timeout phases (see ErrRequestTimeout)
+const (
+	// TimeoutConnect TCP connection to remote host was not established in time (see WithConnectTimeout)
+	TimeoutConnect TimeoutPhase = "connect"
+	// TimeoutTLSHandshake TLS handshake with remote host was not completed in time (see WithTLSHandshakeTimeout)
+	TimeoutTLSHandshake TimeoutPhase = "tls handshake"
+	// TimeoutResponseHeader remote host did not send response headers in time (see WithResponseHeaderTimeout)
+	TimeoutResponseHeader TimeoutPhase = "response header"
+	// TimeoutRequest single HTTP request was not completed in time (see WithTimeout)
+	TimeoutRequest TimeoutPhase = "request"
+	// TimeoutTotal Ads.txt request, including redirects and retries, was not completed in time (see WithTotalTimeout
+	// and Request.Timeout), or the request context deadline was exceeded
+	TimeoutTotal TimeoutPhase = "total"
+)
+
+// TimeoutPhase phase of Ads.txt request that timed out
+type TimeoutPhase string
+
+// HTTP transport timeout error messages (the errors are not exported by net/http)
+const (
+	tlsHandshakeTimeoutMessage   = "TLS handshake timeout"
+	responseHeaderTimeoutMessage = "timeout awaiting response headers"
+)
+
+// timeoutOf return the time limit of Ads.txt request: the request timeout if set, or else the crawler total
+// timeout (zero means no time limit)
+func (c *Crawler) timeoutOf(req *Request) time.Duration {
+	if req.Timeout > 0 {
+		return req.Timeout
+	}
+	return c.totalTimeout
+}
+
+// timeoutError return ErrRequestTimeout of the timed out request phase if err is timeout error, or else err as is
+func (c *Crawler) timeoutError(ctx context.Context, req *Request, err error) error {
+	if !isTimeout(err) {
+		return err
+	}
+
+	e := &ErrRequestTimeout{URL: req.URL, Err: err}
+	var opErr *net.OpError
+	switch {
+	// deadline of the whole Ads.txt request is checked first, since it aborts the request in any phase
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		e.Phase, e.Timeout = TimeoutTotal, c.timeoutOf(req)
+	case errors.As(err, &opErr) && opErr.Op == "dial":
+		e.Phase, e.Timeout = TimeoutConnect, c.connectTimeout
+	case strings.Contains(err.Error(), tlsHandshakeTimeoutMessage):
+		e.Phase, e.Timeout = TimeoutTLSHandshake, c.handshakeTimeout
+	case strings.Contains(err.Error(), responseHeaderTimeoutMessage):
+		e.Phase, e.Timeout = TimeoutResponseHeader, c.headerTimeout
+	default:
+		e.Phase, e.Timeout = TimeoutRequest, c.timeout
+	}
+	return e
+}
+
+// isTimeout check if err is network timeout or exceeded context deadline
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+}
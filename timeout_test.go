@@ -0,0 +1,138 @@
+package adstxt
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// checkTimeout check that err is ErrRequest caused by timeout of the expected request phase
+func checkTimeout(t *testing.T, err error, phase TimeoutPhase, timeout time.Duration) {
+	t.Helper()
+
+	var timeoutErr *ErrRequestTimeout
+	var requestErr *ErrRequest
+	if !errors.Is(err, ErrTimeout) || !errors.As(err, &requestErr) || !errors.As(err, &timeoutErr) {
+		t.Fatalf("Expected ErrRequestTimeout error and not [%v]", err)
+	}
+	if timeoutErr.Phase != phase || timeoutErr.Timeout != timeout {
+		t.Errorf("Expected [%s] timeout after [%s] and not [%s] after [%s]", phase, timeout, timeoutErr.Phase, timeoutErr.Timeout)
+	}
+}
+
+// TestTimeouts test crawler request phases timeouts
+func TestTimeouts(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		io.WriteString(w, "greenadexchange.com,XF7342,DIRECT")
+	}))
+	defer ts.Close()
+
+	timeout := 20 * time.Millisecond
+	req := &Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"}
+
+	_, err := NewCrawler(WithResponseHeaderTimeout(timeout)).Get(req)
+	checkTimeout(t, err, TimeoutResponseHeader, timeout)
+
+	_, err = NewCrawler(WithTimeout(timeout)).Get(req)
+	checkTimeout(t, err, TimeoutRequest, timeout)
+
+	_, err = NewCrawler(WithTotalTimeout(timeout)).Get(req)
+	checkTimeout(t, err, TimeoutTotal, timeout)
+
+	// request timeout overrides the crawler total timeout
+	_, err = NewCrawler(WithTotalTimeout(time.Minute)).Get(&Request{URL: req.URL, Domain: req.Domain, Timeout: timeout})
+	checkTimeout(t, err, TimeoutTotal, timeout)
+
+	// caller context deadline
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	_, err = NewCrawler().GetWithContext(ctx, req)
+	checkTimeout(t, err, TimeoutTotal, 0)
+
+	res, err := NewCrawler(WithResponseHeaderTimeout(time.Second)).Get(req)
+	if err != nil || len(res.DataRecords) != 1 {
+		t.Errorf("Expected Ads.txt request within timeout to succeed [%v]", err)
+	}
+}
+
+// TestTLSHandshakeTimeout test crawler TLS handshake timeout of remote host that does not complete the handshake
+func TestTLSHandshakeTimeout(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	// accept connections and never respond
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	timeout := 20 * time.Millisecond
+	_, err = NewCrawler(WithTLSHandshakeTimeout(timeout)).Get(&Request{URL: "https://" + l.Addr().String() + "/ads.txt", Domain: "127.0.0.1"})
+	checkTimeout(t, err, TimeoutTLSHandshake, timeout)
+}
+
+// TestConnectTimeout test classification of TCP connection timeout
+func TestConnectTimeout(t *testing.T) {
+	c := NewCrawler(WithConnectTimeout(time.Second))
+	req := &Request{URL: "http://example.com/ads.txt", Domain: "example.com"}
+
+	err := c.timeoutError(context.Background(), req, &net.OpError{Op: "dial", Net: "tcp", Err: context.DeadlineExceeded})
+	checkTimeout(t, &ErrRequest{URL: req.URL, Err: err}, TimeoutConnect, time.Second)
+
+	// errors other than timeout are not changed
+	refused := errors.New("connection refused")
+	if err := c.timeoutError(context.Background(), req, refused); err != refused {
+		t.Errorf("Expected non timeout error to be returned as is and not [%v]", err)
+	}
+}
+
+// TestPartialBody test records read before the request timed out mid-transfer are returned with the timeout error
+func TestPartialBody(t *testing.T) {
+	done := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, "greenadexchange.com,1001,DIRECT\ngreenadexchange.com,2002,RESELLER\ngreenadexchange.com,30")
+		w.(http.Flusher).Flush()
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	}))
+	defer ts.Close()
+	defer close(done)
+
+	timeout := 100 * time.Millisecond
+	req := &Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"}
+
+	res, err := NewCrawler(WithTimeout(timeout), WithPartialBody(true)).Get(req)
+	checkTimeout(t, err, TimeoutRequest, timeout)
+	if res == nil || !res.Truncated || len(res.DataRecords) != 2 || res.DataRecords[1].PublisherAccountID != "2002" {
+		t.Fatalf("Expected truncated response with the [2] complete records read before the timeout and not %v", res)
+	}
+
+	// request timeout of fallback crawl
+	res, err = NewCrawler(WithFallback(true), WithPartialBody(true)).Get(&Request{URL: req.URL, Domain: req.Domain, Timeout: timeout})
+	checkTimeout(t, err, TimeoutTotal, timeout)
+	if res == nil || !res.Truncated || len(res.DataRecords) != 2 {
+		t.Errorf("Expected truncated response of fallback crawl and not %v", res)
+	}
+
+	// body read so far is discarded by default
+	if res, err := NewCrawler(WithTimeout(timeout)).Get(req); res != nil || err == nil {
+		t.Errorf("Expected request that timed out to fail without response and not %v", res)
+	}
+}
@@ -0,0 +1,125 @@
+package adstxt
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// TLSInfo TLS connection and server certificate details of Ads.txt response, for auditing
+type TLSInfo struct {
+	Version     string    `json:"version"`               // Version negotiated TLS version (e.g. TLS 1.3)
+	CipherSuite string    `json:"cipherSuite"`           // CipherSuite negotiated cipher suite
+	ServerName  string    `json:"serverName"`            // ServerName host name the certificate was verified for
+	Subject     string    `json:"subject"`               // Subject of the server certificate
+	Issuer      string    `json:"issuer"`                // Issuer of the server certificate
+	NotBefore   time.Time `json:"notBefore"`             // NotBefore start of the server certificate validity period
+	NotAfter    time.Time `json:"notAfter"`              // NotAfter expiry of the server certificate
+	Verified    bool      `json:"verified"`              // Verified server certificate chain is valid and trusted
+	VerifyError string    `json:"verifyError,omitempty"` // VerifyError reason the server certificate is invalid (see WithInsecureTLS)
+}
+
+// transportTLS return HTTP transport TLS configuration: copy of the crawler TLS configuration with root CAs, minimum
+// TLS version, certificate verification policy and client session cache crawler options
+func (c *Crawler) transportTLS() *tls.Config {
+	if c.tlsSessionCache <= 0 && c.rootCAs == nil && c.minTLSVersion == 0 && !c.insecureTLS {
+		return c.tlsConfig
+	}
+
+	// use copy of TLS configuration so we can set it without affecting the caller
+	config := &tls.Config{}
+	if c.tlsConfig != nil {
+		config = c.tlsConfig.Clone()
+	}
+	if c.tlsSessionCache > 0 {
+		config.ClientSessionCache = tls.NewLRUClientSessionCache(c.tlsSessionCache)
+	}
+	if c.rootCAs != nil {
+		config.RootCAs = c.rootCAs
+	}
+	if c.minTLSVersion != 0 {
+		config.MinVersion = c.minTLSVersion
+	}
+	// invalid certificates are accepted, and verified once the response is received to report them (see tlsInfo)
+	if c.insecureTLS {
+		config.InsecureSkipVerify = true
+	}
+	return config
+}
+
+// tlsInfo return TLS details of HTTP response, or nil if the response was not received over TLS. When the crawler
+// accepts invalid certificates the server certificate chain is verified, and invalid certificate is logged
+func (c *Crawler) tlsInfo(res *http.Response) *TLSInfo {
+	state := res.TLS
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return nil
+	}
+
+	cert := state.PeerCertificates[0]
+	info := &TLSInfo{
+		Version:     tls.VersionName(state.Version),
+		CipherSuite: tls.CipherSuiteName(state.CipherSuite),
+		ServerName:  state.ServerName,
+		Subject:     cert.Subject.String(),
+		Issuer:      cert.Issuer.String(),
+		NotBefore:   cert.NotBefore,
+		NotAfter:    cert.NotAfter,
+		Verified:    true,
+	}
+
+	// certificates are verified by the TLS handshake, unless the crawler is set to skip verification
+	if !c.skipVerify() {
+		return info
+	}
+	if err := c.verifyCertificate(state); err != nil {
+		info.Verified = false
+		info.VerifyError = err.Error()
+		if res.Request != nil {
+			c.log(res.Request.Context(), c.logLevels.Warning, "Ads.txt invalid TLS certificate accepted", "url", res.Request.URL.String(),
+				"subject", info.Subject, "issuer", info.Issuer, "notAfter", info.NotAfter, "error", err)
+		}
+	}
+	return info
+}
+
+// skipVerify return true if the crawler HTTP transport does not verify server certificates
+func (c *Crawler) skipVerify() bool {
+	return c.insecureTLS || (c.tlsConfig != nil && c.tlsConfig.InsecureSkipVerify)
+}
+
+// verifyCertificate verify server certificate chain of TLS connection, against the crawler root CAs (or the system
+// root CAs, if not set)
+func (c *Crawler) verifyCertificate(state *tls.ConnectionState) error {
+	roots := c.rootCAs
+	if roots == nil && c.tlsConfig != nil {
+		roots = c.tlsConfig.RootCAs
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range state.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err := state.PeerCertificates[0].Verify(x509.VerifyOptions{DNSName: state.ServerName, Roots: roots, Intermediates: intermediates})
+	var hostErr x509.HostnameError
+	if errors.As(err, &hostErr) && state.ServerName == "" {
+		// connection to IP address without server name: the certificate host name is not checked
+		return nil
+	}
+	return err
+}
+
+// insecureURL return plain HTTP URL of HTTPS Ads.txt URL whose request failed at the TLS layer (certificate, TLS
+// protocol error or TLS handshake timeout), or false if the request did not fail at the TLS layer
+func insecureURL(rawURL string, err error) (string, bool) {
+	if !strings.HasPrefix(rawURL, "https://") {
+		return "", false
+	}
+	if !isTLSError(err) && !strings.Contains(err.Error(), tlsHandshakeTimeoutMessage) {
+		return "", false
+	}
+	return "http://" + strings.TrimPrefix(rawURL, "https://"), true
+}
@@ -0,0 +1,153 @@
+package adstxt
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTLSTestServer return TLS test server serving single Ads.txt record
+func newTLSTestServer() *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, "greenadexchange.com,XF7342,DIRECT")
+	}))
+}
+
+// TestWithRootCAs test crawler verify server certificate with custom root CAs, and expose certificate details
+func TestWithRootCAs(t *testing.T) {
+	ts := newTLSTestServer()
+	defer ts.Close()
+
+	req := &Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"}
+
+	// test server certificate is not trusted by system root CAs
+	if _, err := NewCrawler().Get(req); err == nil {
+		t.Errorf("Expected request with untrusted certificate to fail")
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ts.Certificate())
+	res, err := NewCrawler(WithRootCAs(pool)).Get(&Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.TLS == nil || !res.TLS.Verified || res.TLS.Issuer != ts.Certificate().Issuer.String() {
+		t.Fatalf("Expected verified TLS details of test server certificate and not [%+v]", res.TLS)
+	}
+	if !res.TLS.NotAfter.Equal(ts.Certificate().NotAfter) || len(res.TLS.Version) == 0 {
+		t.Errorf("Expected TLS details to hold certificate expiry and TLS version [%+v]", res.TLS)
+	}
+}
+
+// TestWithInsecureTLS test crawler accept invalid server certificate, and report and log it
+func TestWithInsecureTLS(t *testing.T) {
+	ts := newTLSTestServer()
+	defer ts.Close()
+
+	var b bytes.Buffer
+	c := NewCrawler(WithInsecureTLS(true), WithLogger(slog.New(slog.NewTextHandler(&b, nil))))
+	res, err := c.Get(&Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.DataRecords) != 1 {
+		t.Errorf("Expected Ads.txt file with invalid certificate to be parsed")
+	}
+	if res.TLS == nil || res.TLS.Verified || len(res.TLS.VerifyError) == 0 {
+		t.Errorf("Expected TLS details to report invalid certificate and not [%+v]", res.TLS)
+	}
+	if !strings.Contains(b.String(), "Ads.txt invalid TLS certificate accepted") {
+		t.Errorf("Expected invalid certificate to be logged [%s]", b.String())
+	}
+
+	// plain HTTP response has no TLS details
+	if info := c.tlsInfo(&http.Response{}); info != nil {
+		t.Errorf("Expected no TLS details of plain HTTP response and not [%+v]", info)
+	}
+}
+
+// TestWithMinTLSVersion test crawler reject server that does not support the minimum TLS version
+func TestWithMinTLSVersion(t *testing.T) {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "greenadexchange.com,XF7342,DIRECT")
+	}))
+	ts.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+	ts.StartTLS()
+	defer ts.Close()
+
+	req := &Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"}
+	if _, err := NewCrawler(WithInsecureTLS(true), WithMinTLSVersion(tls.VersionTLS13)).Get(req); err == nil {
+		t.Errorf("Expected request to server without TLS 1.3 support to fail")
+	}
+
+	res, err := NewCrawler(WithInsecureTLS(true), WithMinTLSVersion(tls.VersionTLS12)).Get(&Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.TLS.Version != "TLS 1.2" {
+		t.Errorf("Expected TLS 1.2 connection and not [%s]", res.TLS.Version)
+	}
+}
+
+// TestWithHTTPFallback test crawler retry HTTPS request that failed at the TLS layer over plain HTTP, and mark the
+// response as fetched insecurely
+func TestWithHTTPFallback(t *testing.T) {
+	var urls []string
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			urls = append(urls, req.URL.String())
+			if req.URL.Scheme == "https" && req.URL.Host == "greenadexchange.com" {
+				return nil, x509.UnknownAuthorityError{}
+			}
+			if req.URL.Scheme == "https" {
+				return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader("")), Request: req}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       io.NopCloser(strings.NewReader("greenadexchange.com,XF7342,DIRECT")),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	// by default TLS failure is not retried over HTTP
+	req := &Request{URL: "https://greenadexchange.com/ads.txt", Domain: "greenadexchange.com"}
+	if _, err := NewCrawler(WithHTTPClient(client)).Get(req); err == nil || !isTLSError(err) {
+		t.Errorf("Expected TLS error without HTTP fallback and not [%v]", err)
+	}
+
+	urls = nil
+	c := NewCrawler(WithHTTPClient(client), WithHTTPFallback(true))
+	res, err := c.Get(&Request{URL: "https://greenadexchange.com/ads.txt", Domain: "greenadexchange.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.FetchedInsecurely || len(res.DataRecords) != 1 {
+		t.Errorf("Expected Ads.txt file fetched over HTTP to be marked as fetched insecurely [%+v]", res)
+	}
+	if len(urls) != 2 || urls[1] != "http://greenadexchange.com/ads.txt" {
+		t.Errorf("Expected HTTPS request to be retried over HTTP and not [%v]", urls)
+	}
+
+	// HTTP status errors are not retried over HTTP
+	urls = nil
+	if _, err := c.Get(&Request{URL: "https://google.com/ads.txt", Domain: "google.com"}); err == nil || len(urls) != 1 {
+		t.Errorf("Expected HTTP status error not to be retried over HTTP [%v] [%v]", err, urls)
+	}
+
+	if u, ok := insecureURL("https://google.com/ads.txt", errors.New("net/http: TLS handshake timeout")); !ok || u != "http://google.com/ads.txt" {
+		t.Errorf("Expected TLS handshake timeout to be retried over HTTP and not [%s]", u)
+	}
+	if _, ok := insecureURL("http://google.com/ads.txt", x509.UnknownAuthorityError{}); ok {
+		t.Errorf("Expected plain HTTP URL not to be retried over HTTP")
+	}
+}
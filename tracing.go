@@ -0,0 +1,185 @@
+package adstxt
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+)
+
+// Span names of Ads.txt request phases (see Tracer)
+const (
+	// SpanRequest whole Ads.txt request, including redirects and retries. Request phases spans are its children
+	SpanRequest = "adstxt.request"
+	// SpanDNS DNS lookup of remote host
+	SpanDNS = "adstxt.dns"
+	// SpanConnect TCP connection to remote host address (one span per dialed address)
+	SpanConnect = "adstxt.connect"
+	// SpanTLS TLS handshake with remote host
+	SpanTLS = "adstxt.tls"
+	// SpanFirstByte time from writing HTTP request until the first byte of the response is received
+	SpanFirstByte = "adstxt.first_byte"
+	// SpanBody read (and decompression) of Ads.txt file body
+	SpanBody = "adstxt.body"
+	// SpanParse parse of Ads.txt file body
+	SpanParse = "adstxt.parse"
+)
+
+// Span attribute keys, following OpenTelemetry semantic conventions where they apply
+const (
+	attrDomain     = "adstxt.domain"
+	attrURL        = "url.full"
+	attrStatusCode = "http.response.status_code"
+	attrRecords    = "adstxt.records"
+	attrBodySize   = "adstxt.body_size"
+	attrHost       = "server.address"
+	attrPeer       = "network.peer.address"
+	attrTLSVersion = "tls.protocol.version"
+)
+
+// Tracer start spans of Ads.txt request phases, so crawl latency can be diagnosed in tracing backends. Tracer is an
+// adapter of tracing library (e.g. OpenTelemetry trace.Tracer): spans are started with the context of the caller, and
+// the returned context carries the new span, so request phases spans are children of SpanRequest span, which is child
+// of the caller span. Implementations must be safe to use from multiple goroutines
+type Tracer interface {
+	// Start start new span named name (see SpanRequest) as child of the span in ctx, and return context that holds it
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span single traced Ads.txt request phase
+type Span interface {
+	// SetAttribute set span attribute (string, int or bool value)
+	SetAttribute(key string, value interface{})
+	// End end the span, with the phase error (nil if the phase succeeded)
+	End(err error)
+}
+
+// noopSpan span of crawler without tracer
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(string, interface{}) {}
+func (noopSpan) End(error)                        {}
+
+// startSpan start span of the crawler tracer, or no-op span if the crawler has no tracer (see WithTracer)
+func (c *Crawler) startSpan(ctx context.Context, name string) (context.Context, Span) {
+	if c.tracer == nil {
+		return ctx, noopSpan{}
+	}
+	return c.tracer.Start(ctx, name)
+}
+
+// traceRequest start SpanRequest span of Ads.txt request, and return function that ends it with the request result
+func (c *Crawler) traceRequest(ctx context.Context, req *Request) (context.Context, func(*Response, error)) {
+	if c.tracer == nil {
+		return ctx, func(*Response, error) {}
+	}
+
+	ctx, span := c.tracer.Start(ctx, SpanRequest)
+	span.SetAttribute(attrDomain, req.Domain)
+	span.SetAttribute(attrURL, req.URL)
+	return ctx, func(res *Response, err error) {
+		if res != nil {
+			span.SetAttribute(attrStatusCode, res.StatusCode)
+			if res.Records != nil {
+				span.SetAttribute(attrRecords, len(res.DataRecords))
+			}
+		}
+		span.End(err)
+	}
+}
+
+// withTrace return context of HTTP request that traces DNS lookup, connection, TLS handshake and first response byte
+// spans of the crawler tracer as children of the span in ctx, and function that ends the spans still open once the
+// HTTP request is done (e.g. first byte span of request that failed)
+func (c *Crawler) withTrace(ctx context.Context) (context.Context, func(error)) {
+	if c.tracer == nil {
+		return ctx, func(error) {}
+	}
+
+	// connection phases may run concurrently (e.g. Happy Eyeballs dial of multiple addresses)
+	var mu sync.Mutex
+	var dns, handshake, firstByte Span
+	connect := map[string]Span{}
+
+	start := func(name string) Span {
+		_, span := c.tracer.Start(ctx, name)
+		return span
+	}
+	end := func(span *Span, err error) {
+		mu.Lock()
+		s := *span
+		*span = nil
+		mu.Unlock()
+		if s != nil {
+			s.End(err)
+		}
+	}
+
+	done := func(err error) {
+		end(&dns, err)
+		end(&handshake, err)
+		end(&firstByte, err)
+		mu.Lock()
+		open := connect
+		connect = map[string]Span{}
+		mu.Unlock()
+		for _, span := range open {
+			span.End(err)
+		}
+	}
+
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		DNSStart: func(info httptrace.DNSStartInfo) {
+			span := start(SpanDNS)
+			span.SetAttribute(attrHost, info.Host)
+			mu.Lock()
+			dns = span
+			mu.Unlock()
+		},
+		DNSDone: func(info httptrace.DNSDoneInfo) {
+			end(&dns, info.Err)
+		},
+		ConnectStart: func(network, addr string) {
+			span := start(SpanConnect)
+			span.SetAttribute(attrPeer, addr)
+			mu.Lock()
+			connect[addr] = span
+			mu.Unlock()
+		},
+		ConnectDone: func(network, addr string, err error) {
+			mu.Lock()
+			span := connect[addr]
+			delete(connect, addr)
+			mu.Unlock()
+			if span != nil {
+				span.End(err)
+			}
+		},
+		TLSHandshakeStart: func() {
+			span := start(SpanTLS)
+			mu.Lock()
+			handshake = span
+			mu.Unlock()
+		},
+		TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+			mu.Lock()
+			if handshake != nil && err == nil {
+				handshake.SetAttribute(attrTLSVersion, tls.VersionName(state.Version))
+			}
+			mu.Unlock()
+			end(&handshake, err)
+		},
+		WroteRequest: func(info httptrace.WroteRequestInfo) {
+			if info.Err != nil {
+				return
+			}
+			span := start(SpanFirstByte)
+			mu.Lock()
+			firstByte = span
+			mu.Unlock()
+		},
+		GotFirstResponseByte: func() {
+			end(&firstByte, nil)
+		},
+	}), done
+}
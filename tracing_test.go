@@ -0,0 +1,112 @@
+package adstxt
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testSpan span recorded by testTracer
+type testSpan struct {
+	name   string
+	parent string
+	attrs  map[string]interface{}
+	err    error
+	ended  bool
+	t      *testTracer
+}
+
+func (s *testSpan) SetAttribute(key string, value interface{}) {
+	s.t.mu.Lock()
+	defer s.t.mu.Unlock()
+	s.attrs[key] = value
+}
+
+func (s *testSpan) End(err error) {
+	s.t.mu.Lock()
+	defer s.t.mu.Unlock()
+	s.err, s.ended = err, true
+}
+
+type spanContextKey struct{}
+
+// testTracer record started spans, and the name of their parent span
+type testTracer struct {
+	mu    sync.Mutex
+	spans []*testSpan
+}
+
+func (t *testTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := &testSpan{name: name, attrs: map[string]interface{}{}, t: t}
+	if parent, ok := ctx.Value(spanContextKey{}).(string); ok {
+		s.parent = parent
+	}
+	t.spans = append(t.spans, s)
+	return context.WithValue(ctx, spanContextKey{}, name), s
+}
+
+// find return the first span named name
+func (t *testTracer) find(name string) *testSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, s := range t.spans {
+		if s.name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// TestTracer test Ads.txt request phases are traced as children spans of the request span, which is child of the
+// caller span
+func TestTracer(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, "greenadexchange.com,XF7342,DIRECT\ngreenadexchange.com,185,RESELLER")
+	}))
+	defer ts.Close()
+
+	tracer := &testTracer{}
+	c := NewCrawler(WithTracer(tracer), WithInsecureTLS(true))
+	u := strings.Replace(ts.URL, "127.0.0.1", "localhost", 1) + "/ads.txt"
+	ctx := context.WithValue(context.Background(), spanContextKey{}, "caller")
+	if _, err := c.GetWithContext(ctx, &Request{URL: u, Domain: "localhost"}); err != nil {
+		t.Fatal(err)
+	}
+
+	request := tracer.find(SpanRequest)
+	if request == nil || request.parent != "caller" || !request.ended || request.attrs[attrStatusCode] != http.StatusOK ||
+		request.attrs[attrRecords] != 2 {
+		t.Fatalf("Expected request span child of the caller span and not %+v", request)
+	}
+	for _, name := range []string{SpanDNS, SpanConnect, SpanTLS, SpanFirstByte, SpanBody, SpanParse} {
+		s := tracer.find(name)
+		if s == nil || s.parent != SpanRequest || !s.ended || s.err != nil {
+			t.Errorf("Expected [%s] span child of the request span and not %+v", name, s)
+		}
+	}
+	if v := tracer.find(SpanTLS).attrs[attrTLSVersion]; v != tls.VersionName(tls.VersionTLS13) {
+		t.Errorf("Expected TLS version span attribute and not [%v]", v)
+	}
+
+	// failed request ends the request span and the open phase spans with the error
+	tracer = &testTracer{}
+	c = NewCrawler(WithTracer(tracer), WithInsecureTLS(true))
+	if _, err := c.Get(&Request{URL: "http://127.0.0.1:1/ads.txt", Domain: "127.0.0.1"}); err == nil {
+		t.Fatal("Expected request to closed port to fail")
+	}
+	if s := tracer.find(SpanRequest); s == nil || !s.ended || s.err == nil {
+		t.Errorf("Expected failed request span and not %+v", s)
+	}
+	if s := tracer.find(SpanConnect); s == nil || !s.ended || s.err == nil {
+		t.Errorf("Expected failed connect span and not %+v", s)
+	}
+}
@@ -0,0 +1,93 @@
+package adstxt
+
+import (
+	"io"
+	"strings"
+)
+
+// RecordTransformer transform single Ads.txt data record while Ads.txt file is parsed, for example to normalize
+// account IDs, map legacy advertising system domains, or redact fields. Transformer may change the record in place and
+// return it, return new record that replaces it, or return nil to drop the record from the parsed records
+type RecordTransformer func(*DataRecord) *DataRecord
+
+// ChainTransformers return RecordTransformer that applies transformers in order, middleware style: each transformer
+// gets the record returned by the previous one. Once any transformer drops the record, the following transformers are
+// not applied
+func ChainTransformers(transformers ...RecordTransformer) RecordTransformer {
+	return func(d *DataRecord) *DataRecord {
+		for _, t := range transformers {
+			if d = t(d); d == nil {
+				return nil
+			}
+		}
+		return d
+	}
+}
+
+// MapAdSystems return RecordTransformer that replaces the advertising system domain of data records by domains map
+// (matched case insensitive), for example to map legacy exchange domains to their current domain
+func MapAdSystems(domains map[string]string) RecordTransformer {
+	m := map[string]string{}
+	for from, to := range domains {
+		m[strings.ToLower(strings.TrimSpace(from))] = to
+	}
+	return func(d *DataRecord) *DataRecord {
+		if to, ok := m[strings.ToLower(d.AdverterDomain)]; ok {
+			d.AdverterDomain = to
+		}
+		return d
+	}
+}
+
+// ParseBodyWithTransformers parse Ads.txt file (see ParseBody), and apply the transformers to each parsed data record
+// (see Records.Transform)
+func ParseBodyWithTransformers(b []byte, transformers ...RecordTransformer) (*Records, error) {
+	records, err := ParseBody(b)
+	if err != nil {
+		return nil, err
+	}
+	records.Transform(transformers...)
+	return records, nil
+}
+
+// ParseReaderWithTransformers parse Ads.txt file read from r (see ParseReader), and apply the transformers to each
+// parsed data record (see Records.Transform)
+func ParseReaderWithTransformers(r io.Reader, transformers ...RecordTransformer) (*Records, error) {
+	records, err := ParseReader(r)
+	if err != nil {
+		return nil, err
+	}
+	records.Transform(transformers...)
+	return records, nil
+}
+
+// Transform apply the transformers in order to each data record (see ChainTransformers). Record replaced by a
+// transformer keeps the line index of the original record, and records dropped by a transformer are removed. Return
+// the number of data records that were dropped
+func (r *Records) Transform(transformers ...RecordTransformer) int {
+	if len(transformers) == 0 {
+		return 0
+	}
+
+	t := ChainTransformers(transformers...)
+	dropped := 0
+	dr := r.DataRecords[:0]
+	for _, d := range r.DataRecords {
+		index := r.Line(d)
+		td := t(d)
+		if td == nil {
+			dropped++
+			continue
+		}
+		if td != d && index > 0 {
+			r.setLine(td, index)
+		}
+		dr = append(dr, td)
+	}
+	// clear dropped records tail, so they are not held by the underlying array
+	for i := len(dr); i < len(r.DataRecords); i++ {
+		r.DataRecords[i] = nil
+	}
+	r.DataRecords = dr
+	return dropped
+}
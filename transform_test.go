@@ -0,0 +1,87 @@
+package adstxt
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const transformAdsTxt = `# Ads.txt file
+appnexus.com, 1234, DIRECT
+greenadexchange.com, pub-XF7342, RESELLER
+openx.com, 5678, DIRECT`
+
+// normalizeAccountID test transformer that strips the "pub-" prefix of publisher account IDs
+func normalizeAccountID(d *DataRecord) *DataRecord {
+	d.PublisherAccountID = strings.TrimPrefix(d.PublisherAccountID, "pub-")
+	return d
+}
+
+// TestTransform test transformers are applied in order to each parsed data record, and records dropped by
+// transformer are removed
+func TestTransform(t *testing.T) {
+	calls := 0
+	redact := func(d *DataRecord) *DataRecord {
+		if d.AdverterDomain == "openx.com" {
+			return nil
+		}
+		return d
+	}
+	replace := func(d *DataRecord) *DataRecord {
+		calls++
+		c := *d
+		c.CertAuthorityID = "f08c47fec0942fa0"
+		return &c
+	}
+
+	records, err := ParseBodyWithTransformers([]byte(transformAdsTxt), MapAdSystems(map[string]string{"AppNexus.com": "greenadexchange.com"}),
+		normalizeAccountID, redact, replace)
+	if err != nil {
+		t.Fatalf("Failed to parse Ads.txt file with transformers: %s", err)
+	}
+
+	if len(records.DataRecords) != 2 || calls != 2 {
+		t.Fatalf("Expected [2] data records after redacted record was dropped and not [%d]", len(records.DataRecords))
+	}
+	expected := []string{"greenadexchange.com,1234,DIRECT,f08c47fec0942fa0", "greenadexchange.com,XF7342,RESELLER,f08c47fec0942fa0"}
+	for i, d := range records.DataRecords {
+		if d.canonical() != expected[i] {
+			t.Errorf("Expected transformed record [%s] and not [%s]", expected[i], d.canonical())
+		}
+		if records.Line(d) != i+2 || len(d.Comments) != 1-i {
+			t.Errorf("Expected replaced record [%s] to keep line [%d] of original record and not [%d]", d.canonical(), i+2, records.Line(d))
+		}
+	}
+
+	// no transformers
+	if dropped := records.Transform(); dropped != 0 || len(records.DataRecords) != 2 {
+		t.Errorf("Expected no records to be dropped without transformers and not [%d]", dropped)
+	}
+}
+
+// TestCrawlerRecordTransformers test crawler apply record transformers to the parsed Ads.txt response
+func TestCrawlerRecordTransformers(t *testing.T) {
+	client := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       io.NopCloser(strings.NewReader(transformAdsTxt)),
+				Request:    req,
+			}, nil
+		}),
+	}
+
+	c := NewCrawler(WithHTTPClient(client), WithRecordTransformers(normalizeAccountID), WithSortedRecords(true))
+	res, err := c.Get(&Request{URL: "https://example.com/ads.txt", Domain: "example.com"})
+	if err != nil {
+		t.Fatalf("Failed to crawl Ads.txt file with record transformers: %s", err)
+	}
+	if d := res.FindByAccountID("XF7342"); len(d) != 1 || d[0].AdverterDomain != "greenadexchange.com" {
+		t.Errorf("Expected transformed account ID to be found %v", d)
+	}
+	if res.Body[2] != "greenadexchange.com, pub-XF7342, RESELLER" {
+		t.Errorf("Expected original Ads.txt content to be kept and not [%s]", res.Body[2])
+	}
+}
@@ -0,0 +1,32 @@
+package adstxt
+
+import (
+	"net/http"
+	"time"
+)
+
+// crawler HTTP transport timeouts
+const (
+	// maximum time idle keep-alive connection remains open in the crawler HTTP transport
+	idleConnTimeout = 90 * time.Second
+	// default TLS handshake timeout
+	tlsHandshakeTimeout = 10 * time.Second
+)
+
+// newTransport create crawler HTTP transport based on crawler options. By default keep-alives are disabled, so each
+// Ads.txt request opens a new connection (see WithKeepAlive)
+func (c *Crawler) newTransport() *http.Transport {
+	t := &http.Transport{
+		DisableKeepAlives:     !c.keepAlive,
+		MaxIdleConnsPerHost:   c.maxIdleConnsPerHost,
+		IdleConnTimeout:       idleConnTimeout,
+		TLSHandshakeTimeout:   c.handshakeTimeout,
+		ResponseHeaderTimeout: c.headerTimeout,
+		TLSClientConfig:       c.transportTLS(),
+		Proxy:                 c.transportProxy(),
+		DialContext:           c.transportDial(),
+		// HTTP/2 is not attempted by default since the transport has custom dialer (see WithHTTP2)
+		ForceAttemptHTTP2: c.http2,
+	}
+	return t
+}
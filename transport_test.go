@@ -0,0 +1,124 @@
+package adstxt
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// TestNewTransport test crawler HTTP transport settings
+func TestNewTransport(t *testing.T) {
+	transport := NewCrawler().client.Transport.(*http.Transport)
+	if !transport.DisableKeepAlives || transport.ForceAttemptHTTP2 || transport.TLSClientConfig != nil {
+		t.Errorf("Expected default transport without keep-alives, HTTP/2 and TLS configuration")
+	}
+
+	config := &tls.Config{ServerName: "example.com"}
+	transport = NewCrawler(WithKeepAlive(true), WithMaxIdleConnsPerHost(20), WithHTTP2(true), WithTLSConfig(config),
+		WithTLSSessionCache(64)).client.Transport.(*http.Transport)
+	if transport.DisableKeepAlives || transport.MaxIdleConnsPerHost != 20 || !transport.ForceAttemptHTTP2 {
+		t.Errorf("Expected transport keep-alives, max idle connections per host and HTTP/2 to be set")
+	}
+	if transport.TLSClientConfig.ClientSessionCache == nil || transport.TLSClientConfig.ServerName != "example.com" {
+		t.Errorf("Expected TLS configuration with client session cache")
+	}
+	if config.ClientSessionCache != nil {
+		t.Errorf("Expected caller TLS configuration not to be changed")
+	}
+}
+
+// TestKeepAlive test crawler reuse connections between requests when keep-alive is enabled
+func TestKeepAlive(t *testing.T) {
+	var mu sync.Mutex
+	conns := 0
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, "greenadexchange.com,XF7342,DIRECT")
+	}))
+	ts.Config.ConnState = func(c net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			mu.Lock()
+			conns++
+			mu.Unlock()
+		}
+	}
+	ts.Start()
+	defer ts.Close()
+
+	tests := map[bool]int{false: 3, true: 1}
+	for keepAlive, expected := range tests {
+		mu.Lock()
+		conns = 0
+		mu.Unlock()
+
+		c := NewCrawler(WithKeepAlive(keepAlive))
+		for i := 0; i < 3; i++ {
+			req := &Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"}
+			if _, err := c.GetWithContext(context.Background(), req); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		mu.Lock()
+		if conns != expected {
+			t.Errorf("Expected [%d] connections with keep-alive [%t] and not [%d]", expected, keepAlive, conns)
+		}
+		mu.Unlock()
+	}
+}
+
+// TestKeepAliveGetMultiple test concurrent GetMultiple requests share the crawler connection pool
+func TestKeepAliveGetMultiple(t *testing.T) {
+	var mu sync.Mutex
+	conns := 0
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, "greenadexchange.com,XF7342,DIRECT")
+	}))
+	ts.Config.ConnState = func(c net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			mu.Lock()
+			conns++
+			mu.Unlock()
+		}
+	}
+	ts.Start()
+	defer ts.Close()
+
+	requests := []*Request{}
+	for i := 0; i < 10; i++ {
+		requests = append(requests, &Request{URL: ts.URL + "/ads.txt", Domain: "127.0.0.1"})
+	}
+
+	c := NewCrawler(WithKeepAlive(true), WithConcurrency(2), WithDeduplicateRequests(false))
+	for i := 0; i < 2; i++ {
+		c.GetMultiple(requests, HandlerFunc(func(req *Request, res *Response, err error) {
+			if err != nil {
+				t.Error(err)
+			}
+		}))
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if conns > 2 {
+		t.Errorf("Expected at most [2] connections for [20] requests with concurrency [2] and not [%d]", conns)
+	}
+}
+
+// TestDefaultCrawler test package level functions share keep-alive crawler
+func TestDefaultCrawler(t *testing.T) {
+	if defaultCrawler() != defaultCrawler() {
+		t.Fatal("Expected package level functions to share single crawler")
+	}
+	if transport := defaultCrawler().client.Transport.(*http.Transport); transport.DisableKeepAlives {
+		t.Error("Expected shared crawler transport with keep-alives")
+	}
+}
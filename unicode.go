@@ -0,0 +1,88 @@
+package adstxt
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// parse line warning of normalized Unicode characters
+const errNormalizedLine = "line contains %s, normalized to plain ASCII before parsing"
+
+// Unicode characters classes found in copy-pasted Ads.txt files (e.g. from word processor, web page or spreadsheet)
+const (
+	unicodeSpace     = "non-breaking or Unicode spaces"
+	unicodeZeroWidth = "zero-width characters"
+	unicodeComma     = "full-width commas"
+	unicodeEquals    = "full-width equals signs"
+)
+
+// unicodeReplacements ASCII replacement of Unicode characters by character class, empty replacement removes the
+// character
+var unicodeReplacements = map[rune]struct {
+	class       string
+	replacement string
+}{
+	'\u00a0': {unicodeSpace, " "},    // no-break space
+	'\u1680': {unicodeSpace, " "},    // ogham space mark
+	'\u2000': {unicodeSpace, " "},    // en quad
+	'\u2001': {unicodeSpace, " "},    // em quad
+	'\u2002': {unicodeSpace, " "},    // en space
+	'\u2003': {unicodeSpace, " "},    // em space
+	'\u2004': {unicodeSpace, " "},    // three-per-em space
+	'\u2005': {unicodeSpace, " "},    // four-per-em space
+	'\u2006': {unicodeSpace, " "},    // six-per-em space
+	'\u2007': {unicodeSpace, " "},    // figure space
+	'\u2008': {unicodeSpace, " "},    // punctuation space
+	'\u2009': {unicodeSpace, " "},    // thin space
+	'\u200a': {unicodeSpace, " "},    // hair space
+	'\u202f': {unicodeSpace, " "},    // narrow no-break space
+	'\u205f': {unicodeSpace, " "},    // medium mathematical space
+	'\u3000': {unicodeSpace, " "},    // ideographic space
+	'\u00ad': {unicodeZeroWidth, ""}, // soft hyphen
+	'\u200b': {unicodeZeroWidth, ""}, // zero width space
+	'\u200c': {unicodeZeroWidth, ""}, // zero width non-joiner
+	'\u200d': {unicodeZeroWidth, ""}, // zero width joiner
+	'\u2060': {unicodeZeroWidth, ""}, // word joiner
+	'\ufeff': {unicodeZeroWidth, ""}, // zero width no-break space (byte order mark)
+	'\uff0c': {unicodeComma, ","},    // full-width comma
+	'\u3001': {unicodeComma, ","},    // ideographic comma
+	'\ufe50': {unicodeComma, ","},    // small comma
+	'\u060c': {unicodeComma, ","},    // arabic comma
+	'\uff1d': {unicodeEquals, "="},   // full-width equals sign
+}
+
+// normalizeUnicode replace Unicode spaces, commas and equals signs of Ads.txt line with their ASCII form, and remove
+// zero-width characters, so copy-pasted lines are not parsed into bogus field values. Return the normalized line,
+// and warning listing the normalized character classes if the line was changed
+func normalizeUnicode(line string) (string, *Warning) {
+	ascii := true
+	for i := 0; i < len(line); i++ {
+		if line[i] >= utf8.RuneSelf {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return line, nil
+	}
+
+	var b strings.Builder
+	classes := []string{}
+	for _, c := range line {
+		r, ok := unicodeReplacements[c]
+		if !ok {
+			b.WriteRune(c)
+			continue
+		}
+		b.WriteString(r.replacement)
+		if !contains(classes, r.class) {
+			classes = append(classes, r.class)
+		}
+	}
+
+	if len(classes) == 0 {
+		return line, nil
+	}
+	return b.String(), &Warning{Level: LowSeverity, Message: fmt.Sprintf(errNormalizedLine, strings.Join(classes, ", "))}
+}
@@ -0,0 +1,50 @@
+package adstxt
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestNormalizeUnicode test parsing Ads.txt lines with non-breaking spaces, zero-width characters and full-width
+// commas
+func TestNormalizeUnicode(t *testing.T) {
+	body := strings.Join([]string{
+		"\ufeffgreenadexchange.com, XF7342, DIRECT",
+		"greenadexchange.com,\u00a0XF7343,\u00a0DIRECT\u00a0",
+		"greenadexchange.com\uff0c XF7344\u200b, RESELLER # 日本、テスト",
+		"contact\uff1dadops@example.com",
+		"\u200b",
+	}, "\n")
+
+	records, err := ParseBody([]byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records.DataRecords) != 3 || len(records.Contacts) != 1 || records.Contacts[0] != "adops@example.com" {
+		t.Fatalf("Expected all records to be parsed and not [%d] data records [%v] contacts", len(records.DataRecords), records.Contacts)
+	}
+	for i, id := range []string{"XF7342", "XF7343", "XF7344"} {
+		if r := records.DataRecords[i]; r.PublisherAccountID != id || r.AdverterDomain != "greenadexchange.com" {
+			t.Errorf("Expected normalized data record [%s] and not [%s] [%s]", id, r.AdverterDomain, r.PublisherAccountID)
+		}
+	}
+	if records.DataRecords[1].AccountType != RelationshipDirect || records.DataRecords[2].AccountType != RelationshipReseller {
+		t.Errorf("Expected normalized account type")
+	}
+
+	// byte order mark at the start of the file is not reported, and comments are not normalized
+	expected := map[int]string{
+		2: "line contains non-breaking or Unicode spaces, normalized to plain ASCII before parsing",
+		3: "line contains full-width commas, zero-width characters, normalized to plain ASCII before parsing",
+		4: "line contains full-width equals signs, normalized to plain ASCII before parsing",
+	}
+	if len(records.Warnings) != len(expected) {
+		t.Errorf("Expected [%d] parse warnings and not [%v]", len(expected), records.Warnings)
+	}
+	for _, w := range records.Warnings {
+		if w.Message != expected[w.Index] || w.Level != LowSeverity {
+			t.Errorf("Expected warning [%s] of line [%d] and not [%s]", expected[w.Index], w.Index, w.Message)
+		}
+	}
+}
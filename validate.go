@@ -0,0 +1,186 @@
+package adstxt
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Ads.txt records validation errors, use errors.Is to check the reason of ValidationError
+var (
+	// ErrMissingField required record field is empty
+	ErrMissingField = errors.New("missing required field")
+	// ErrInvalidDomain record field is not a valid domain name
+	ErrInvalidDomain = errors.New("invalid domain name")
+	// ErrInvalidRelationship data record account type is not DIRECT or RESELLER
+	ErrInvalidRelationship = errors.New("invalid relationship")
+	// ErrInvalidCertAuthorityID data record certification authority ID is not a valid TAG-ID
+	ErrInvalidCertAuthorityID = errors.New("invalid certification authority ID")
+	// ErrInvalidVariableType variable type is not supported by Ads.txt specification
+	ErrInvalidVariableType = errors.New("invalid variable type")
+	// ErrInvalidVariableValue variable value does not match its type
+	ErrInvalidVariableValue = errors.New("invalid variable value")
+	// ErrDuplicateVariable variable is declared more times than allowed (e.g. more than single OWNERDOMAIN)
+	ErrDuplicateVariable = errors.New("duplicate variable")
+)
+
+var (
+	// domain name labels: letters, digits and hyphens, with at least two labels
+	domainNameRe = regexp.MustCompile(`^(?i)([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+	// TAG-ID certification authority ID is 16 hex characters
+	tagIDRe = regexp.MustCompile("^(?i)[0-9a-f]{16}$")
+)
+
+// ValidationError represent single Ads.txt record field that does not comply with IAB Ads.txt specification
+type ValidationError struct {
+	Line   int    // Line index of the invalid record in the Ads.txt file (set by Records.Validate, 0 if unknown)
+	Record string // Record canonical form of the invalid record
+	Field  string // Field name of the invalid record field
+	Value  string // Value of the invalid record field
+	Err    error  // Err validation failure reason (one of the Err* validation errors)
+}
+
+// Error return ValidationError description
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("[%s] %s [%s]: %s", e.Record, e.Field, e.Value, e.Err)
+}
+
+// Unwrap return ValidationError reason
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationErrors list of Ads.txt records validation errors
+type ValidationErrors []*ValidationError
+
+// Error return ValidationErrors description
+func (e ValidationErrors) Error() string {
+	msg := make([]string, 0, len(e))
+	for _, err := range e {
+		msg = append(msg, err.Error())
+	}
+	return strings.Join(msg, "; ")
+}
+
+// Unwrap return ValidationErrors as list of errors, so errors.Is and errors.As check each validation error
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// Validate check DataRecord against IAB Ads.txt specification: advertising system domain name syntax, required
+// publisher account ID, allowed relationship values (DIRECT or RESELLER) and certification authority ID format
+// (TAG-ID of 16 hex characters). Return nil if DataRecord is valid, or ValidationErrors with all invalid fields
+func (r *DataRecord) Validate() error {
+	var errs ValidationErrors
+	add := func(field, value string, err error) {
+		errs = append(errs, &ValidationError{Record: r.canonical(), Field: field, Value: value, Err: err})
+	}
+
+	domain := strings.TrimSpace(r.AdverterDomain)
+	if len(domain) == 0 {
+		add("AdverterDomain", domain, ErrMissingField)
+	} else if !isDomainName(domain) {
+		add("AdverterDomain", domain, ErrInvalidDomain)
+	}
+
+	if len(strings.TrimSpace(r.PublisherAccountID)) == 0 {
+		add("PublisherAccountID", r.PublisherAccountID, ErrMissingField)
+	}
+
+	switch {
+	case r.AccountType.IsValid():
+	case len(r.AccountType.canonical()) == 0:
+		add("AccountType", string(r.AccountType), ErrMissingField)
+	default:
+		add("AccountType", string(r.AccountType), ErrInvalidRelationship)
+	}
+
+	// certification authority ID is optional
+	if id := strings.TrimSpace(r.CertAuthorityID); len(id) > 0 && !tagIDRe.MatchString(id) {
+		add("CertAuthorityID", r.CertAuthorityID, ErrInvalidCertAuthorityID)
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Validate check Variable against IAB Ads.txt specification: supported variable type, required value and valid
+// domain name for subdomain, ownerdomain and managerdomain variables. Return nil if Variable is valid, or
+// ValidationErrors with the invalid field
+func (v *Variable) Validate() error {
+	invalid := func(field, value string, err error) error {
+		return ValidationErrors{&ValidationError{Record: v.canonical(), Field: field, Value: value, Err: err}}
+	}
+
+	value := strings.TrimSpace(v.Value)
+	varType := strings.ToLower(strings.TrimSpace(v.Type))
+	switch varType {
+	case varTypeSubdomain, varTypeContact, varTypeOwnerDomain, varTypeManagerDomain, varTypeInventoryPartnerDomain:
+	default:
+		return invalid("Type", v.Type, ErrInvalidVariableType)
+	}
+
+	if len(value) == 0 {
+		return invalid("Value", v.Value, ErrMissingField)
+	}
+
+	switch varType {
+	case varTypeSubdomain, varTypeOwnerDomain, varTypeInventoryPartnerDomain:
+		if !isDomainName(value) {
+			return invalid("Value", v.Value, ErrInvalidDomain)
+		}
+	case varTypeManagerDomain:
+		m, err := parseManagerDomain(value)
+		if err != nil {
+			return invalid("Value", v.Value, ErrInvalidVariableValue)
+		}
+		if !isDomainName(m.Domain) {
+			return invalid("Value", v.Value, ErrInvalidDomain)
+		}
+	}
+
+	return nil
+}
+
+// Validate check all Ads.txt data records and variables against IAB Ads.txt specification (see DataRecord.Validate
+// and Variable.Validate). Return nil if all records are valid, or ValidationErrors with all invalid records fields
+func (r *Records) Validate() error {
+	var errs ValidationErrors
+	add := func(record interface{}, err error) {
+		for _, e := range err.(ValidationErrors) {
+			e.Line = r.Line(record)
+			errs = append(errs, e)
+		}
+	}
+
+	for _, dr := range r.DataRecords {
+		if err := dr.Validate(); err != nil {
+			add(dr, err)
+		}
+	}
+
+	for _, v := range r.Variables {
+		if err := v.Validate(); err != nil {
+			add(v, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// isDomainName check that domain is a syntactically valid domain name (without scheme, port or path)
+func isDomainName(domain string) bool {
+	// internationalized domain name is validated by its punycode form
+	ascii := asciiHost(domain)
+	return len(ascii) <= 253 && validateDomainName(domain) && domainNameRe.MatchString(ascii)
+}
@@ -0,0 +1,81 @@
+package adstxt
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestDataRecordValidate test validating single Ads.txt data record against IAB rules
+func TestDataRecordValidate(t *testing.T) {
+	valid := &DataRecord{AdverterDomain: "greenadexchange.com", PublisherAccountID: "XF7342", AccountType: "direct", CertAuthorityID: "5JYXF8K54A1B2C3D"}
+	if err := valid.Validate(); err == nil {
+		t.Errorf("Expected TAG-ID [%s] with non hex characters to be invalid", valid.CertAuthorityID)
+	}
+
+	valid.CertAuthorityID = "f08c47fec0942fa0"
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Expected DataRecord to be valid [%s]", err)
+	}
+
+	tests := []struct {
+		record DataRecord
+		field  string
+		err    error
+	}{
+		{DataRecord{AdverterDomain: "", PublisherAccountID: "1", AccountType: "DIRECT"}, "AdverterDomain", ErrMissingField},
+		{DataRecord{AdverterDomain: "http://greenadexchange.com", PublisherAccountID: "1", AccountType: "DIRECT"}, "AdverterDomain", ErrInvalidDomain},
+		{DataRecord{AdverterDomain: "greenadexchange", PublisherAccountID: "1", AccountType: "DIRECT"}, "AdverterDomain", ErrInvalidDomain},
+		{DataRecord{AdverterDomain: "greenadexchange.com", PublisherAccountID: " ", AccountType: "DIRECT"}, "PublisherAccountID", ErrMissingField},
+		{DataRecord{AdverterDomain: "greenadexchange.com", PublisherAccountID: "1", AccountType: "PARTNER"}, "AccountType", ErrInvalidRelationship},
+		{DataRecord{AdverterDomain: "greenadexchange.com", PublisherAccountID: "1", AccountType: "DIRECT", CertAuthorityID: "f08c47fec0942fa"}, "CertAuthorityID", ErrInvalidCertAuthorityID},
+	}
+
+	for _, test := range tests {
+		err := test.record.Validate()
+
+		var errs ValidationErrors
+		if !errors.As(err, &errs) || len(errs) != 1 {
+			t.Errorf("Expected single validation error for [%v] and not [%v]", test.record, err)
+			continue
+		}
+		if errs[0].Field != test.field || !errors.Is(errs[0], test.err) {
+			t.Errorf("Expected [%s] [%s] validation error and not [%s]", test.field, test.err, errs[0])
+		}
+	}
+}
+
+// TestRecordsValidate test validating all Ads.txt records
+func TestRecordsValidate(t *testing.T) {
+	r := &Records{
+		DataRecords: []*DataRecord{
+			&DataRecord{AdverterDomain: "greenadexchange.com", PublisherAccountID: "XF7342", AccountType: "DIRECT"},
+			&DataRecord{AdverterDomain: "greenadexchange", PublisherAccountID: "", AccountType: "OTHER"},
+		},
+		Variables: []*Variable{
+			&Variable{Type: "contact", Value: "test@example.com"},
+			&Variable{Type: "subdomain", Value: "dev"},
+			&Variable{Type: "managerdomain", Value: "manager.com,USA"},
+			&Variable{Type: "owner", Value: "example.com"},
+		},
+	}
+
+	err := r.Validate()
+
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Expected ValidationErrors and not [%v]", err)
+	}
+	if len(errs) != 6 {
+		t.Errorf("Expected [6] validation errors and not [%d] [%s]", len(errs), err)
+	}
+
+	for _, e := range []error{ErrInvalidDomain, ErrMissingField, ErrInvalidRelationship, ErrInvalidVariableValue, ErrInvalidVariableType} {
+		if !errors.Is(err, e) {
+			t.Errorf("Expected [%s] validation error", e)
+		}
+	}
+
+	if err := (&Records{}).Validate(); err != nil {
+		t.Errorf("Expected empty records to be valid [%s]", err)
+	}
+}
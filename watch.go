@@ -0,0 +1,141 @@
+package adstxt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// watcher settings default values
+const (
+	watcherTimeout = 10 * time.Second
+)
+
+// watcher errors
+const (
+	errWebhookStatus = "webhook [%s] replied with HTTP status [%s]"
+)
+
+// ChangeEvent Ads.txt file change found by Watcher, posted as JSON to the watcher webhooks
+type ChangeEvent struct {
+	Domain   string       `json:"domain"`   // Domain root domain of the Ads.txt request
+	URL      string       `json:"url"`      // URL Ads.txt URL of the request (before redirects)
+	Previous time.Time    `json:"previous"` // Previous fetch time of the previous stored version
+	Current  time.Time    `json:"current"`  // Current fetch time of the new version
+	Diff     *RecordsDiff `json:"diff"`     // Diff changes between the previous and the new version
+}
+
+// Watcher compare each crawled Ads.txt file with its previous version in a Store (see Diff), and post ChangeEvent
+// JSON to the webhook URLs when records were added, removed or modified. The new version is then saved to the store,
+// so the next crawl is compared to it. First crawl of Ads.txt file (that has no previous version) is only saved. Use
+// Handler to watch the results of batch crawl or Scheduler. Watcher is safe to use from multiple goroutines
+type Watcher struct {
+	Webhooks []string     // Webhooks URLs that change events are posted to
+	Client   *http.Client // Client HTTP client used to post change events
+	Logger   Logger       // Logger log watcher failures in Handler (optional)
+
+	store Store
+}
+
+// NewWatcher create new Watcher that compares crawled Ads.txt files with their previous version in store, and post
+// change events to the specified webhook URLs
+func NewWatcher(store Store, webhooks ...string) *Watcher {
+	return &Watcher{Webhooks: webhooks, Client: &http.Client{Timeout: watcherTimeout}, store: store}
+}
+
+// Watch compare Ads.txt response with the previous stored version, post change event to the webhooks if the Ads.txt
+// file changed and save the response as the current version. Return the change event, or nil if Ads.txt file did not
+// change. NotModified responses are not compared, since the Ads.txt file did not change
+func (w *Watcher) Watch(ctx context.Context, req *Request, res *Response) (*ChangeEvent, error) {
+	if res.NotModified {
+		return nil, nil
+	}
+
+	key := responseKey(req, res)
+	prev, err := w.store.LoadResponse(key)
+	if err != nil && err != ErrResponseNotFound {
+		return nil, err
+	}
+	// response not allowed by the store policy is compared, but not saved as the current version (see PolicyStore)
+	if err := w.store.SaveResponse(key, res); err != nil && !errors.Is(err, ErrStoragePolicy) {
+		return nil, err
+	}
+	if prev == nil || !res.Changed(prev) {
+		return nil, nil
+	}
+
+	diff := Diff(prev.Records, res.Records)
+	if diff.Empty() {
+		return nil, nil
+	}
+
+	e := &ChangeEvent{Domain: req.Domain, URL: key, Previous: prev.Fetched, Current: res.Fetched, Diff: diff}
+	return e, w.notify(ctx, e)
+}
+
+// Handler return Handler that watches each successful Ads.txt response (see Watch) and then passes the result to h (h
+// may be nil). Watch failures are logged with the watcher logger
+func (w *Watcher) Handler(h Handler) Handler {
+	return HandlerFunc(func(req *Request, res *Response, err error) {
+		if err == nil && res != nil {
+			if _, werr := w.Watch(context.Background(), req, res); werr != nil && w.Logger != nil {
+				w.Logger.Log(context.Background(), slog.LevelWarn, "Ads.txt watcher failed", "domain", req.Domain,
+					"url", req.URL, "error", werr)
+			}
+		}
+		if h != nil {
+			h.Handle(req, res, err)
+		}
+	})
+}
+
+// notify post change event JSON to all webhooks, return the first webhook error
+func (w *Watcher) notify(ctx context.Context, e *ChangeEvent) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	var first error
+	for _, url := range w.Webhooks {
+		if err := w.post(ctx, url, b); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// post change event JSON to single webhook
+func (w *Watcher) post(ctx context.Context, url string, b []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := w.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf(errWebhookStatus, url, res.Status)
+	}
+	return nil
+}
+
+// responseKey return the Ads.txt URL of the request before redirects, that identifies the Ads.txt file in Store
+func responseKey(req *Request, res *Response) string {
+	if len(res.Redirects) > 0 {
+		return res.Redirects[0].URL
+	}
+	return req.URL
+}
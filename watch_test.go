@@ -0,0 +1,72 @@
+package adstxt
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestWatcher test posting change events to webhook when crawled Ads.txt file changed since the previous crawl
+func TestWatcher(t *testing.T) {
+	events := []*ChangeEvent{}
+	status := http.StatusOK
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Expected change event to be posted as JSON")
+		}
+		e := &ChangeEvent{}
+		if err := json.NewDecoder(r.Body).Decode(e); err != nil {
+			t.Error(err)
+		}
+		events = append(events, e)
+		w.WriteHeader(status)
+	}))
+	defer ts.Close()
+
+	s, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := NewWatcher(s, ts.URL)
+
+	req, _ := NewRequest("example.com")
+	response := func(body string) *Response {
+		records, _ := ParseBody([]byte(body))
+		return &Response{Request: req, Records: records}
+	}
+
+	// first crawl is only saved
+	if e, err := w.Watch(context.Background(), req, response("greenadexchange.com,XF7342,DIRECT\ngoogle.com,pub-1,RESELLER")); e != nil || err != nil {
+		t.Errorf("Expected no change event for first crawl and not [%v] (%v)", e, err)
+	}
+	// unchanged Ads.txt file
+	if e, err := w.Watch(context.Background(), req, response("GreenAdExchange.com, XF7342, DIRECT\ngoogle.com,pub-1,RESELLER")); e != nil || err != nil {
+		t.Errorf("Expected no change event for unchanged Ads.txt file and not [%v] (%v)", e, err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("Expected no change events to be posted and not [%d]", len(events))
+	}
+
+	// reseller removed
+	e, err := w.Watch(context.Background(), req, response("greenadexchange.com,XF7342,DIRECT"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e == nil || len(e.Diff.RemovedRecords) != 1 || e.Diff.RemovedRecords[0].AdverterDomain != "google.com" {
+		t.Errorf("Expected change event with removed reseller")
+	}
+	if len(events) != 1 || events[0].URL != req.URL || len(events[0].Diff.RemovedRecords) != 1 {
+		t.Errorf("Expected change event to be posted to webhook")
+	}
+
+	// webhook failure is returned, and the new version is still saved
+	status = http.StatusInternalServerError
+	if _, err := w.Watch(context.Background(), req, response("greenadexchange.com,XF7342,RESELLER")); err == nil {
+		t.Errorf("Expected webhook error")
+	}
+	if prev, _ := s.LoadResponse(req.URL); prev == nil || prev.DataRecords[0].AccountType != accountTypeReseller {
+		t.Errorf("Expected new version to be saved")
+	}
+}
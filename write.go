@@ -0,0 +1,90 @@
+package adstxt
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"strings"
+)
+
+// String return Ads.txt file content regenerated from the records (see WriteTo)
+func (r *Records) String() string {
+	var b bytes.Buffer
+	r.WriteTo(&b)
+	return b.String()
+}
+
+// WriteTo write Ads.txt file regenerated from the records to w: data records and variables are written in the order
+// they were declared in the parsed Ads.txt file (records that were added manually follow them, data records first),
+// each with its full-line and inline comments. Invalid lines of the parsed Ads.txt file are not written
+func (r *Records) WriteTo(w io.Writer) (int64, error) {
+	type entry struct {
+		line     int
+		text     string
+		comment  string
+		comments []string
+	}
+
+	entries := make([]*entry, 0, len(r.DataRecords)+len(r.Variables))
+	for _, d := range r.DataRecords {
+		entries = append(entries, &entry{line: r.Line(d), text: d.adsTxtLine(), comment: d.Comment, comments: d.Comments})
+	}
+	for _, v := range r.Variables {
+		entries = append(entries, &entry{line: r.Line(v), text: v.adsTxtLine(), comment: v.Comment, comments: v.Comments})
+	}
+
+	// keep Ads.txt file order, records without line index go last
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].line == 0 || entries[j].line == 0 {
+			return entries[j].line == 0 && entries[i].line != 0
+		}
+		return entries[i].line < entries[j].line
+	})
+
+	lines := []string{}
+	for _, e := range entries {
+		for _, c := range e.comments {
+			lines = append(lines, commentLine(c))
+		}
+		if len(e.comment) > 0 {
+			e.text += " " + commentLine(e.comment)
+		}
+		lines = append(lines, e.text)
+	}
+	for _, c := range r.TrailingComments {
+		lines = append(lines, commentLine(c))
+	}
+
+	if len(lines) == 0 {
+		return 0, nil
+	}
+	n, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return int64(n), err
+}
+
+// adsTxtLine return DataRecord Ads.txt line: <FIELD #1>, <FIELD #2>, <FIELD #3>[, <FIELD #4>][;<EXTENSION>]
+func (r *DataRecord) adsTxtLine() string {
+	fields := []string{r.AdverterDomain, r.PublisherAccountID, string(r.AccountType)}
+	if len(r.CertAuthorityID) > 0 || len(r.Extensions) > 0 {
+		fields = append(fields, r.CertAuthorityID)
+	}
+
+	line := strings.Join(fields, ", ")
+	if len(r.Extensions) > 0 {
+		line += extensionDenote + strings.Join(r.Extensions, extensionDenote)
+	}
+	return line
+}
+
+// adsTxtLine return Variable Ads.txt line: <VARIABLE>=<VALUE>
+func (v *Variable) adsTxtLine() string {
+	return strings.ToUpper(v.Type) + "=" + v.Value
+}
+
+// commentLine return Ads.txt comment
+func commentLine(comment string) string {
+	if len(comment) == 0 {
+		return commentDenote
+	}
+	return commentDenote + " " + comment
+}
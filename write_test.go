@@ -0,0 +1,55 @@
+package adstxt
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestRecordsWriteTo test regenerating Ads.txt file from parsed records, preserving comments and records order
+func TestRecordsWriteTo(t *testing.T) {
+	body := `# Ads.txt file of example.com
+#
+contact=adops@example.com
+greenadexchange.com,XF7342,DIRECT # main account
+invalid line
+# resellers
+greenadexchange.com, 185, reseller, d75815a79;ext1
+subdomain=dev.example.com
+# end of file`
+
+	records, _ := ParseBody([]byte(body))
+
+	expected := `# Ads.txt file of example.com
+#
+CONTACT=adops@example.com
+greenadexchange.com, XF7342, DIRECT # main account
+# resellers
+greenadexchange.com, 185, RESELLER, d75815a79;ext1
+SUBDOMAIN=dev.example.com
+# end of file
+`
+	if s := records.String(); s != expected {
+		t.Errorf("Expected regenerated Ads.txt file to be [%s] and not [%s]", expected, s)
+	}
+
+	// regenerated Ads.txt file is parsed to the same records
+	parsed, _ := ParseBody([]byte(records.String()))
+	if parsed.Hash() != records.Hash() || len(parsed.Warnings) != 0 || parsed.String() != expected {
+		t.Errorf("Expected regenerated Ads.txt file to be parsed to the same records")
+	}
+	if c := parsed.DataRecords[1].Comments; len(c) != 1 || c[0] != "resellers" {
+		t.Errorf("Expected full-line comment to be attached to the next record and not %v", c)
+	}
+
+	// records added manually follow parsed records
+	records.DataRecords = append(records.DataRecords, &DataRecord{AdverterDomain: "google.com", PublisherAccountID: "pub-1", AccountType: "DIRECT"})
+	var b strings.Builder
+	n, err := records.WriteTo(&b)
+	if err != nil || n != int64(b.Len()) {
+		t.Fatalf("Expected WriteTo to return the number of written bytes [%d] [%v]", n, err)
+	}
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if lines[len(lines)-2] != "google.com, pub-1, DIRECT" {
+		t.Errorf("Expected manually added record to follow parsed records and not [%s]", lines[len(lines)-2])
+	}
+}